@@ -0,0 +1,206 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dashboardCookie holds the session token of a logged-in dashboard user.
+const dashboardCookie = "opcuababy_session"
+
+type dashboardSession struct {
+	user     string
+	lastSeen time.Time
+}
+
+// dashboardSessions are the logins of the web dashboard, kept in memory so restarting the API
+// server logs everybody out. Users and the idle timeout are read from cfg on every request.
+type dashboardSessions struct {
+	cfg      *opc.Config
+	mu       sync.Mutex
+	sessions map[string]*dashboardSession
+}
+
+func newDashboardSessions(cfg *opc.Config) *dashboardSessions {
+	return &dashboardSessions{cfg: cfg, sessions: make(map[string]*dashboardSession)}
+}
+
+func (s *dashboardSessions) create(user string) string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[token] = &dashboardSession{user: user, lastSeen: time.Now()}
+	return token
+}
+
+// lookup returns the user of an active session and refreshes its idle timer. Idle sessions and
+// sessions of users removed from the config are dropped.
+func (s *dashboardSessions) lookup(token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	idle := s.cfg.DashboardIdle()
+	for t, ss := range s.sessions {
+		if now.Sub(ss.lastSeen) > idle {
+			delete(s.sessions, t)
+		}
+	}
+	ss, ok := s.sessions[token]
+	if !ok {
+		return "", false
+	}
+	if !s.userExists(ss.user) {
+		delete(s.sessions, token)
+		return "", false
+	}
+	ss.lastSeen = now
+	return ss.user, true
+}
+
+func (s *dashboardSessions) userExists(name string) bool {
+	for _, u := range s.cfg.DashboardUsers {
+		if strings.EqualFold(u.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *dashboardSessions) remove(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+// current returns the logged-in user of the request.
+func (s *dashboardSessions) current(c *gin.Context) (string, bool) {
+	token, err := c.Cookie(dashboardCookie)
+	if err != nil || token == "" {
+		return "", false
+	}
+	return s.lookup(token)
+}
+
+// apiKey returns the API key sent with the request, as a bearer token or in X-API-Key.
+func apiKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// authenticated reports whether the request carries a dashboard session or the API key.
+func (s *dashboardSessions) authenticated(c *gin.Context) bool {
+	if s.cfg.CheckApiKey(apiKey(c)) {
+		return true
+	}
+	_, ok := s.current(c)
+	return ok
+}
+
+// requireAPI protects the API, WebSocket and metrics routes while ApiAuthRequired: requests need
+// a dashboard session cookie (the dashboard pages' own requests) or the API key, others answer
+// 401.
+func (s *dashboardSessions) requireAPI() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.cfg.ApiAuthRequired() || s.authenticated(c) {
+			c.Next()
+			return
+		}
+		c.Header("WWW-Authenticate", `Bearer realm="opcuababy"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key or dashboard login required"})
+		c.Abort()
+	}
+}
+
+// require protects the dashboard pages while dashboard users are configured: browsers are
+// redirected to the login page, other clients get 401. The data the pages load is protected by
+// requireAPI.
+func (s *dashboardSessions) require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.cfg.DashboardLoginRequired() {
+			c.Next()
+			return
+		}
+		if _, ok := s.current(c); ok {
+			c.Next()
+			return
+		}
+		if c.Request.Method == http.MethodGet && strings.Contains(c.GetHeader("Accept"), "text/html") {
+			c.Redirect(http.StatusSeeOther, "/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "dashboard login required"})
+		}
+		c.Abort()
+	}
+}
+
+func (s *dashboardSessions) setCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(dashboardCookie, token, maxAge, "/", "", c.Request.TLS != nil, true)
+}
+
+// safeNext returns a local redirect target, "/" for anything that could leave the dashboard.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// registerDashboardAuthRoutes adds the login page, login/logout and GET /auth/session, which tells
+// the dashboard pages whether a login is configured and who is logged in.
+func registerDashboardAuthRoutes(router *gin.Engine, s *dashboardSessions) {
+	router.GET("/login", func(c *gin.Context) {
+		if _, ok := s.current(c); ok || !s.cfg.DashboardLoginRequired() {
+			c.Redirect(http.StatusSeeOther, safeNext(c.Query("next")))
+			return
+		}
+		data, err := webTemplate.ReadFile("templates/login.html")
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Error reading login page")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	})
+
+	router.POST("/login", func(c *gin.Context) {
+		next := safeNext(c.PostForm("next"))
+		user, ok := s.cfg.CheckDashboardLogin(c.PostForm("username"), c.PostForm("password"))
+		if !ok {
+			// Slow down password guessing from the plant network
+			time.Sleep(time.Second)
+			c.Redirect(http.StatusSeeOther, "/login?error=1&next="+url.QueryEscape(next))
+			return
+		}
+		s.setCookie(c, s.create(user), 0)
+		c.Redirect(http.StatusSeeOther, next)
+	})
+
+	router.POST("/logout", func(c *gin.Context) {
+		if token, err := c.Cookie(dashboardCookie); err == nil {
+			s.remove(token)
+		}
+		s.setCookie(c, "", -1)
+		c.Redirect(http.StatusSeeOther, "/login")
+	})
+
+	router.GET("/auth/session", func(c *gin.Context) {
+		user, ok := s.current(c)
+		c.JSON(http.StatusOK, gin.H{"login_required": s.cfg.DashboardLoginRequired(), "logged_in": ok, "user": user})
+	})
+}
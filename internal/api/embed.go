@@ -2,5 +2,5 @@ package api
 
 import "embed"
 
-//go:embed templates/doc.html templates/index.html
+//go:embed templates/doc.html templates/index.html templates/login.html templates/public.html templates/snippets.html
 var webTemplate embed.FS
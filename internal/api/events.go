@@ -0,0 +1,291 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/update"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventLogSize is how many events /api/v1/events keeps for consumers catching up.
+	eventLogSize = 1000
+	// eventPollTimeout and eventPollTimeoutMax bound how long a long poll waits for events.
+	eventPollTimeout    = 25 * time.Second
+	eventPollTimeoutMax = 60 * time.Second
+	// eventHeartbeat keeps idle streams alive through proxies.
+	eventHeartbeat = 15 * time.Second
+)
+
+// streamEvent is an entry of the event log: a watch update ("watch", a WatchItem) or a change of
+// the OPC UA connection ("connection", a ConnectionEvent).
+type streamEvent struct {
+	ID    uint64      `json:"id"`
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// eventLog numbers the events of the hub and keeps the last eventLogSize of them, so that long
+// polls and reconnecting streams continue where they left off.
+type eventLog struct {
+	mu     sync.Mutex
+	last   uint64
+	events []streamEvent // oldest first
+	wake   chan struct{} // closed and replaced by append
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{wake: make(chan struct{})}
+}
+
+func (l *eventLog) append(event string, data interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.last++
+	if len(l.events) >= eventLogSize {
+		l.events = append(l.events[:0], l.events[len(l.events)-eventLogSize+1:]...)
+	}
+	l.events = append(l.events, streamEvent{ID: l.last, Event: event, Data: data})
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// cursor returns the id of the latest event.
+func (l *eventLog) cursor() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// since returns the events after id, how many of them were already dropped from the log, the
+// id of the latest event and a channel closed by the next append.
+func (l *eventLog) since(id uint64) (events []streamEvent, missed, last uint64, wake <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if id > l.last {
+		// The API server restarted since the consumer's last event
+		id = 0
+	}
+	if len(l.events) > 0 && l.events[0].ID > id+1 {
+		missed = l.events[0].ID - id - 1
+	}
+	for _, ev := range l.events {
+		if ev.ID > id {
+			events = append(events, ev)
+		}
+	}
+	return events, missed, l.last, l.wake
+}
+
+// snapshotEvents returns the current value of every watch item as "watch" events without IDs.
+func snapshotEvents(ctx context.Context, ctrl controller.NodeManager, meta bool) []streamEvent {
+	items := ctrl.WatchSnapshot()
+	events := make([]streamEvent, 0, len(items))
+	for _, it := range items {
+		events = append(events, streamEvent{Event: "watch", Data: withMeta(ctx, ctrl, it, meta)})
+	}
+	return events
+}
+
+// eventsWithMeta returns events with the node's metadata added to the "watch" events; the log
+// itself is shared by all consumers and stays as it is.
+func eventsWithMeta(ctx context.Context, ctrl controller.NodeManager, events []streamEvent, meta bool) []streamEvent {
+	if !meta {
+		return events
+	}
+	out := make([]streamEvent, len(events))
+	for i, ev := range events {
+		if it, ok := ev.Data.(*controller.WatchItem); ok {
+			ev.Data = withMeta(ctx, ctrl, it, true)
+		}
+		out[i] = ev
+	}
+	return out
+}
+
+// registerEventRoutes adds the capabilities endpoint and the event stream and long poll, made
+// for HTTP clients without WebSocket support such as Node-RED's http request and SSE nodes.
+func registerEventRoutes(api *gin.RouterGroup, hub *Hub) {
+	ctrl := hub.controller
+
+	api.GET("/capabilities", func(c *gin.Context) {
+		st := ctrl.ConnectionStatus()
+		c.JSON(http.StatusOK, gin.H{
+			"name":      "opcuaBaby",
+			"version":   update.Version,
+			"api":       "v1",
+			"connected": st.Connected,
+			"offline":   st.Offline,
+			"endpoint":  st.Endpoint,
+			"features": []string{
+				"read", "write", "watch", "history", "event_history", "alarms", "export",
+				"snippets", "metrics", "grafana", "websocket", "json_rpc", "sse", "long_poll",
+			},
+			"endpoints": gin.H{
+				"status":        "/api/v1/status",
+				"read":          "/api/v1/read",
+				"write":         "/api/v1/write",
+				"watch":         "/api/v1/watch",
+				"watch_values":  "/api/v1/watch/values",
+				"history":       "/api/v1/history/aggregate",
+				"event_history": "/api/v1/history/events",
+				"alarms":        "/api/v1/alarms",
+				"grafana":       "/api/v1/grafana",
+				"events_stream": "/api/v1/events/stream",
+				"events_poll":   "/api/v1/events/poll",
+				"ws_subscribe":  "/ws/subscribe",
+				"ws_rpc":        "/ws/rpc",
+				"metrics":       "/metrics",
+			},
+			"events": gin.H{
+				"types":              []string{"watch", "connection"},
+				"buffer":             eventLogSize,
+				"poll_timeout_s":     eventPollTimeout.Seconds(),
+				"poll_timeout_max_s": eventPollTimeoutMax.Seconds(),
+				"heartbeat_s":        eventHeartbeat.Seconds(),
+			},
+		})
+	})
+
+	// Long poll: without ?since= the current watch values are returned at once with the cursor
+	// to pass as since next time; with it the request waits up to ?timeout= seconds for newer
+	// events. missed counts events that fell out of the log in between.
+	api.GET("/events/poll", func(c *gin.Context) {
+		meta := includeMeta(c)
+		if c.Query("since") == "" {
+			c.JSON(http.StatusOK, gin.H{"cursor": hub.events.cursor(), "missed": 0, "events": snapshotEvents(c.Request.Context(), ctrl, meta)})
+			return
+		}
+		since, err := strconv.ParseUint(c.Query("since"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a cursor returned by a previous poll"})
+			return
+		}
+		timeout := eventPollTimeout
+		if v := c.Query("timeout"); v != "" {
+			d, err := parseDurationParam(v)
+			if err != nil || d < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timeout"})
+				return
+			}
+			timeout = min(d, eventPollTimeoutMax)
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		for {
+			events, missed, last, wake := hub.events.since(since)
+			if len(events) > 0 || missed > 0 {
+				c.JSON(http.StatusOK, gin.H{"cursor": last, "missed": missed, "events": eventsWithMeta(c.Request.Context(), ctrl, events, meta)})
+				return
+			}
+			select {
+			case <-wake:
+			case <-timer.C:
+				c.JSON(http.StatusOK, gin.H{"cursor": last, "missed": 0, "events": []streamEvent{}})
+				return
+			case <-c.Request.Context().Done():
+				return
+			case <-hub.stop:
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API server stopped"})
+				return
+			}
+		}
+	})
+
+	// Server-Sent Events of every watch update and connection change. Unless ?snapshot=false,
+	// the stream starts with the current watch values; a reconnect with Last-Event-ID resumes
+	// after that event instead. ?include=meta adds the node's metadata to watch events.
+	api.GET("/events/stream", func(c *gin.Context) {
+		w := c.Writer
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "streaming is not supported"})
+			return
+		}
+		resume := c.GetHeader("Last-Event-ID")
+		if resume == "" {
+			resume = c.Query("last_event_id")
+		}
+		var cursor uint64
+		if resume != "" {
+			id, err := strconv.ParseUint(resume, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+				return
+			}
+			cursor = id
+		} else {
+			cursor = hub.events.cursor()
+		}
+		meta := includeMeta(c)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: 3000\n\n")
+		if resume == "" && c.Query("snapshot") != "false" {
+			for _, ev := range snapshotEvents(c.Request.Context(), ctrl, meta) {
+				if writeStreamEvent(w, ev) != nil {
+					return
+				}
+			}
+		}
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			events, missed, last, wake := hub.events.since(cursor)
+			if missed > 0 {
+				if writeStreamEvent(w, streamEvent{Event: "missed", Data: gin.H{"missed": missed}}) != nil {
+					return
+				}
+			}
+			for _, ev := range eventsWithMeta(c.Request.Context(), ctrl, events, meta) {
+				if writeStreamEvent(w, ev) != nil {
+					return
+				}
+			}
+			cursor = last
+			flusher.Flush()
+			select {
+			case <-wake:
+			case <-heartbeat.C:
+				if _, err := fmt.Fprintf(w, ": ping\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-c.Request.Context().Done():
+				return
+			case <-hub.stop:
+				return
+			}
+		}
+	})
+}
+
+// writeStreamEvent writes ev in the text/event-stream format; events without an ID (snapshots)
+// leave the client's Last-Event-ID unchanged.
+func writeStreamEvent(w http.ResponseWriter, ev streamEvent) error {
+	data, err := json.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+	if ev.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", ev.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Event, data)
+	return err
+}
@@ -0,0 +1,623 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// exportJobTimeout bounds address space traversal for background export jobs,
+	// which are allowed to run much longer than synchronous requests.
+	exportJobTimeout = 30 * time.Minute
+	// exportJobRetention is how long finished jobs and their files are kept for polling, checked
+	// every exportJobPruneInterval.
+	exportJobRetention     = time.Hour
+	exportJobPruneInterval = 5 * time.Minute
+	// maxRunningExportJobs bounds concurrent traversals and maxExportJobs the jobs kept; beyond
+	// that the oldest finished jobs are dropped early.
+	maxRunningExportJobs = 4
+	maxExportJobs        = 50
+	maxExportPageSize    = 10000
+)
+
+// errTooManyExportJobs is returned by exportJobStore.start while maxRunningExportJobs are running.
+var errTooManyExportJobs = fmt.Errorf("%d export jobs are already running; try again when one has finished", maxRunningExportJobs)
+
+// tagFilter holds the name/data type filters shared by the export endpoints.
+type tagFilter struct {
+	name      string
+	dataTypes map[string]bool
+}
+
+// parseTagFilter reads ?name= (case-insensitive substring) and ?data_type= (comma separated).
+func parseTagFilter(c *gin.Context) tagFilter {
+	f := tagFilter{name: strings.ToLower(strings.TrimSpace(c.Query("name")))}
+	if dt := strings.TrimSpace(c.Query("data_type")); dt != "" {
+		f.dataTypes = make(map[string]bool)
+		for _, t := range strings.Split(dt, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				f.dataTypes[t] = true
+			}
+		}
+	}
+	return f
+}
+
+func (f tagFilter) apply(tags []*controller.ExportTag) []*controller.ExportTag {
+	if f.name == "" && len(f.dataTypes) == 0 {
+		return tags
+	}
+	out := make([]*controller.ExportTag, 0, len(tags))
+	for _, t := range tags {
+		if f.name != "" && !strings.Contains(strings.ToLower(t.Name), f.name) && !strings.Contains(strings.ToLower(t.NodeID), f.name) {
+			continue
+		}
+		if len(f.dataTypes) > 0 && !f.dataTypes[strings.ToLower(t.DataType)] {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// paginateTags applies ?limit= and ?offset= and sets X-Total-Count. Without a limit,
+// all remaining tags are returned so existing clients keep working unchanged.
+func paginateTags(c *gin.Context, tags []*controller.ExportTag) ([]*controller.ExportTag, error) {
+	total := len(tags)
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	offset := 0
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid offset: %s", v)
+		}
+		offset = n
+	}
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxExportPageSize {
+			return nil, fmt.Errorf("invalid limit: %s (1-%d)", v, maxExportPageSize)
+		}
+		limit = n
+	}
+	if offset >= total {
+		return []*controller.ExportTag{}, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+		c.Header("X-Next-Offset", strconv.Itoa(end))
+	}
+	return tags[offset:end], nil
+}
+
+// exportJob is a background address space export that clients poll for progress.
+type exportJob struct {
+	ID          string    `json:"id"`
+	NodeID      string    `json:"node_id,omitempty"`
+	Recursive   bool      `json:"recursive"`
+	Format      string    `json:"format,omitempty"` // json, csv or xlsx when a downloadable file is produced
+	Status      string    `json:"status"`           // running, done, failed
+	Template    string    `json:"template,omitempty"`
+	Visited     int       `json:"visited"`
+	Found       int       `json:"found"`
+	Error       string    `json:"error,omitempty"`
+	DownloadURL string    `json:"download_url,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+
+	tags []*controller.ExportTag
+	file string
+}
+
+// exportJobStore keeps export jobs in memory for the lifetime of the API server.
+type exportJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]*exportJob
+	// closed is set when the API server has ended and the files have been removed
+	closed bool
+}
+
+// newExportJobStore returns a store that prunes expired jobs until ctx (the API server) ends and
+// then removes the files of all jobs. Expired job files left by an earlier run that could not
+// clean up, e.g. after a crash, are removed at once.
+func newExportJobStore(ctx context.Context) *exportJobStore {
+	s := &exportJobStore{jobs: make(map[string]*exportJob)}
+	removeStaleJobFiles()
+	go s.janitor(ctx)
+	return s
+}
+
+// removeStaleJobFiles removes job files older than exportJobRetention. Younger ones may belong
+// to another API server instance, which prunes them itself.
+func removeStaleJobFiles() {
+	files, _ := filepath.Glob(filepath.Join(exportJobDir(), "tags_*"))
+	for _, f := range files {
+		if st, err := os.Stat(f); err == nil && time.Since(st.ModTime()) > exportJobRetention {
+			_ = os.Remove(f)
+		}
+	}
+}
+
+func (s *exportJobStore) janitor(ctx context.Context) {
+	ticker := time.NewTicker(exportJobPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.close()
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.prune()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// close removes the files of all jobs; jobs still running remove theirs when they finish.
+func (s *exportJobStore) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, j := range s.jobs {
+		if j.file != "" {
+			_ = os.Remove(j.file)
+			j.file, j.DownloadURL = "", ""
+		}
+	}
+}
+
+// start launches a traversal of parentID in the background and returns the job snapshot. ctx
+// bounds the job (it outlives the request that started it). When format is set, the result is
+// also written to a file that can be downloaded. It fails with errTooManyExportJobs while
+// maxRunningExportJobs jobs are running.
+func (s *exportJobStore) start(ctx context.Context, ctrl controller.NodeManager, parentID string, recursive bool, out exportOutput) (exportJob, error) {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	job := &exportJob{
+		ID:        hex.EncodeToString(b),
+		NodeID:    parentID,
+		Recursive: recursive,
+		Format:    out.Format,
+		Template:  templateName(out.Template),
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+	s.mu.Lock()
+	if s.running() >= maxRunningExportJobs {
+		s.mu.Unlock()
+		return exportJob{}, errTooManyExportJobs
+	}
+	s.jobs[job.ID] = job
+	s.prune()
+	snapshot := *job
+	s.mu.Unlock()
+
+	go func() {
+		tags, err := ctrl.CollectVariableNodesProgress(ctx, parentID, recursive, exportJobTimeout, func(visited, found int) {
+			s.mu.Lock()
+			job.Visited, job.Found = visited, found
+			s.mu.Unlock()
+		})
+		var file string
+		if err == nil && out.Format != "" {
+			file, err = writeTagsFile(job.ID, out, tags)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		job.FinishedAt = time.Now()
+		job.tags = tags
+		job.Found = len(tags)
+		if err != nil {
+			job.Status = "failed"
+			job.Error = err.Error()
+			return
+		}
+		job.Status = "done"
+		if file != "" && s.closed {
+			_ = os.Remove(file)
+		} else if file != "" {
+			job.file = file
+			job.DownloadURL = "/api/v1/export/jobs/" + job.ID + "/download"
+		}
+	}()
+	return snapshot, nil
+}
+
+// get returns a snapshot of the job and its collected tags.
+func (s *exportJobStore) get(id string) (exportJob, []*controller.ExportTag, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return exportJob{}, nil, false
+	}
+	return *job, job.tags, true
+}
+
+// artifact returns the path of a finished job's export file.
+func (s *exportJobStore) artifact(id string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok || job.file == "" {
+		return "", false
+	}
+	return job.file, true
+}
+
+func (s *exportJobStore) list() []exportJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]exportJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, *j)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
+
+// running counts the running jobs. Callers must hold s.mu.
+func (s *exportJobStore) running() int {
+	n := 0
+	for _, j := range s.jobs {
+		if j.Status == "running" {
+			n++
+		}
+	}
+	return n
+}
+
+// prune drops finished jobs older than exportJobRetention, and the oldest finished ones while
+// more than maxExportJobs are kept, removing their files. Callers must hold s.mu.
+func (s *exportJobStore) prune() {
+	var finished []*exportJob
+	for _, j := range s.jobs {
+		if j.Status == "running" {
+			continue
+		}
+		if time.Since(j.FinishedAt) > exportJobRetention {
+			s.drop(j)
+		} else {
+			finished = append(finished, j)
+		}
+	}
+	sort.Slice(finished, func(a, b int) bool { return finished[a].FinishedAt.Before(finished[b].FinishedAt) })
+	for _, j := range finished {
+		if len(s.jobs) <= maxExportJobs {
+			break
+		}
+		s.drop(j)
+	}
+}
+
+// drop removes job j and its file. Callers must hold s.mu.
+func (s *exportJobStore) drop(j *exportJob) {
+	if j.file != "" {
+		_ = os.Remove(j.file)
+	}
+	delete(s.jobs, j.ID)
+}
+
+// registerExportJobRoutes adds /export/jobs endpoints for starting, polling and downloading background
+// exports. Jobs run until they finish or ctx (the API server) ends.
+func registerExportJobRoutes(ctx context.Context, api *gin.RouterGroup, ctrl controller.NodeManager, cfg *opc.Config, jobs *exportJobStore) {
+	api.GET("/export/jobs", func(c *gin.Context) {
+		c.JSON(http.StatusOK, jobs.list())
+	})
+
+	api.POST("/export/jobs", func(c *gin.Context) {
+		controllerCtx := ctrl.GetClientContext()
+		if controllerCtx == nil || controllerCtx.Err() != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+			return
+		}
+		var req struct {
+			Format    string `json:"format"`
+			Scope     string `json:"scope"`
+			NodeID    string `json:"node_id"`
+			Recursive *bool  `json:"recursive"`
+			Template  string `json:"template"`
+			OPCServer string `json:"opc_server"`
+			TagGroup  string `json:"tag_group"`
+			ScanRate  int    `json:"scan_rate"`
+			Delimiter string `json:"delimiter"`
+			BOM       *bool  `json:"bom"`
+			Quote     string `json:"quote"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		format, err := parseExportFormat(req.Format)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		parentID, err := parseExportScope(req.Scope, req.NodeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		recursive := true
+		if req.Recursive != nil {
+			recursive = *req.Recursive
+		}
+		tmpl, err := cfg.ExportTemplate(req.Template)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		csvOpts, err := csvFormat(cfg, req.Delimiter, req.BOM, req.Quote)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		job, err := jobs.start(ctx, ctrl, parentID, recursive, exportOutput{
+			Format:   format,
+			Template: tmpl,
+			CSV:      csvOpts,
+			Preset:   controller.TagPresetOptions{OPCServer: req.OPCServer, TagGroup: req.TagGroup, ScanRateMs: req.ScanRate},
+		})
+		if err != nil {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"job": job, "status_url": "/api/v1/export/jobs/" + job.ID})
+	})
+
+	api.GET("/export/jobs/:id/download", func(c *gin.Context) {
+		file, ok := jobs.artifact(c.Param("id"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no export file for this job (unknown, still running or started without a format)"})
+			return
+		}
+		c.FileAttachment(file, filepath.Base(file))
+	})
+
+	// Job status; once done, the (filtered, paginated) tags are included
+	api.GET("/export/jobs/:id", func(c *gin.Context) {
+		job, tags, ok := jobs.get(c.Param("id"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "export job not found"})
+			return
+		}
+		if job.Status != "done" {
+			c.JSON(http.StatusOK, gin.H{"job": job})
+			return
+		}
+		page, err := paginateTags(c, parseTagFilter(c).apply(tags))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"job": job, "tags": page})
+	})
+}
+
+// tagColumns is the standard column layout of tag exports.
+var tagColumns = &opc.ExportTemplate{Columns: []opc.ExportColumn{
+	{Field: "node_id"}, {Field: "name"}, {Field: "browse_name"}, {Field: "namespace_uri"}, {Field: "data_type"},
+	{Field: "description"}, {Field: "path"},
+}}
+
+// exportTemplate resolves ?template= against the configured export templates; nil means the
+// standard layout.
+func exportTemplate(c *gin.Context, cfg *opc.Config) (*opc.ExportTemplate, error) {
+	return cfg.ExportTemplate(c.Query("template"))
+}
+
+// tagField returns the named export field of t; fields tags do not have are empty.
+func tagField(t *controller.ExportTag, field string) string {
+	switch field {
+	case "node_id":
+		return t.NodeID
+	case "name":
+		return t.Name
+	case "browse_name":
+		return t.BrowseName
+	case "namespace_uri":
+		return t.NamespaceURI
+	case "data_type":
+		return t.DataType
+	case "description":
+		return t.Description
+	case "path":
+		return t.Path
+	case "node_class":
+		return "Variable"
+	}
+	return ""
+}
+
+// tagRows renders tags as the header row followed by one row per tag in the columns of tmpl.
+func tagRows(tmpl *opc.ExportTemplate, tags []*controller.ExportTag) [][]string {
+	if tmpl == nil {
+		tmpl = tagColumns
+	}
+	rows := make([][]string, 0, len(tags)+1)
+	rows = append(rows, tmpl.Headers())
+	for _, t := range tags {
+		rows = append(rows, tmpl.Row(func(field string) string { return tagField(t, field) }))
+	}
+	return rows
+}
+
+// templatedTags renders tags as JSON objects keyed by the template's headers.
+func templatedTags(tmpl *opc.ExportTemplate, tags []*controller.ExportTag) []map[string]string {
+	rows := tagRows(tmpl, tags)
+	out := make([]map[string]string, 0, len(tags))
+	for _, row := range rows[1:] {
+		obj := make(map[string]string, len(row))
+		for i, h := range rows[0] {
+			obj[h] = row[i]
+		}
+		out = append(out, obj)
+	}
+	return out
+}
+
+// writeTagsCSV streams tags as a CSV attachment in the columns of tmpl.
+func writeTagsCSV(c *gin.Context, filename string, tmpl *opc.ExportTemplate, f opc.CSVFormat, tags []*controller.ExportTag) {
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	_ = opc.NewCSVWriter(c.Writer, f).WriteAll(tagRows(tmpl, tags))
+}
+
+// csvFormat applies the CSV options of a request (empty ones keep the configured defaults).
+func csvFormat(cfg *opc.Config, delimiter string, bom *bool, quote string) (opc.CSVFormat, error) {
+	f := cfg.CSVFormat()
+	var err error
+	if delimiter != "" {
+		if f.Delimiter, err = opc.ParseCSVDelimiter(delimiter); err != nil {
+			return f, err
+		}
+	}
+	if quote != "" {
+		if f.Quote, err = opc.ParseCSVQuote(quote); err != nil {
+			return f, err
+		}
+	}
+	if bom != nil {
+		f.BOM = *bom
+	}
+	return f, nil
+}
+
+// csvQueryFormat reads ?delimiter=comma|semicolon|tab, ?bom=true|false and ?quote=minimal|all.
+func csvQueryFormat(c *gin.Context, cfg *opc.Config) (opc.CSVFormat, error) {
+	var bom *bool
+	if v := c.Query("bom"); v != "" {
+		b := isTruthy(v)
+		bom = &b
+	}
+	return csvFormat(cfg, c.Query("delimiter"), bom, c.Query("quote"))
+}
+
+// exportOutput describes the file of an export: its format (json, csv, xlsx or a SCADA tag
+// preset), the column template, the CSV options and the connection settings written into
+// preset tags.
+type exportOutput struct {
+	Format   string
+	Template *opc.ExportTemplate
+	CSV      opc.CSVFormat
+	Preset   controller.TagPresetOptions
+}
+
+// parseExportFormat normalizes a requested format; "" is json.
+func parseExportFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return "json", nil
+	case "json", "csv", "xlsx":
+		return format, nil
+	case "excel":
+		return "xlsx", nil
+	}
+	if _, ok := controller.LookupTagPreset(format); ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("format must be json, csv, xlsx, ignition-json, ignition-csv or kepware-csv")
+}
+
+// tagPresetOptions reads ?opc_server=, ?tag_group= and ?scan_rate= (ms) for SCADA presets.
+func tagPresetOptions(c *gin.Context) controller.TagPresetOptions {
+	rate, _ := strconv.Atoi(c.Query("scan_rate"))
+	return controller.TagPresetOptions{
+		OPCServer:  strings.TrimSpace(c.Query("opc_server")),
+		TagGroup:   strings.TrimSpace(c.Query("tag_group")),
+		ScanRateMs: rate,
+	}
+}
+
+// writeTagPreset streams tags as a SCADA tag import attachment.
+func writeTagPreset(c *gin.Context, filename string, preset controller.TagPreset, opts controller.TagPresetOptions, tags []*controller.ExportTag) {
+	c.Header("Content-Disposition", "attachment; filename="+filename+preset.Extension)
+	c.Header("Content-Type", preset.ContentType)
+	if err := controller.WriteTagPreset(c.Writer, preset.ID, tags, opts); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// exportJobDir is the directory of the files of export jobs.
+func exportJobDir() string {
+	return filepath.Join(os.TempDir(), "opcuababy-exports")
+}
+
+// writeTagsFile writes tags to a temporary file as described by out and returns its path.
+func writeTagsFile(jobID string, out exportOutput, tags []*controller.ExportTag) (string, error) {
+	dir := exportJobDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	format, tmpl := out.Format, out.Template
+	if preset, ok := controller.LookupTagPreset(format); ok {
+		path := filepath.Join(dir, "tags_"+jobID+"_"+preset.ID+preset.Extension)
+		f, err := os.Create(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		return path, controller.WriteTagPreset(f, preset.ID, tags, out.Preset)
+	}
+	path := filepath.Join(dir, "tags_"+jobID+"."+format)
+	rows := tagRows(tmpl, tags)
+
+	switch format {
+	case "json":
+		var v interface{} = tags
+		if tmpl != nil {
+			v = templatedTags(tmpl, tags)
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return path, os.WriteFile(path, data, 0o644)
+	case "csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		return path, opc.NewCSVWriter(f, out.CSV).WriteAll(rows)
+	case "xlsx":
+		f := excelize.NewFile()
+		defer f.Close()
+		sheet := "Tags"
+		f.SetSheetName("Sheet1", sheet)
+		for i, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			_ = f.SetSheetRow(sheet, cell, &row)
+		}
+		return path, f.SaveAs(path)
+	}
+	return "", fmt.Errorf("unsupported export format: %s", format)
+}
+
+func templateName(t *opc.ExportTemplate) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
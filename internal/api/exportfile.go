@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportDir returns the directory POST /export/file writes to.
+func exportDir(cfg *opc.Config) string {
+	if cfg != nil && strings.TrimSpace(cfg.ExportDir) != "" {
+		return cfg.ExportDir
+	}
+	return filepath.Join(os.TempDir(), "opcuababy-exports")
+}
+
+// exportFilePath resolves a requested file name below dir. Absolute paths and paths leaving dir
+// are rejected; a missing extension is taken from ext.
+func exportFilePath(dir, name, ext string) (string, error) {
+	name = filepath.Clean(filepath.FromSlash(strings.TrimSpace(name)))
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be relative to the export directory")
+	}
+	if filepath.Ext(name) == "" {
+		name += ext
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// parseExportScope returns the parent NodeID of an export scope: "" for scope all, nodeID for
+// scope folder.
+func parseExportScope(scope, nodeID string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "", "all":
+		return "", nil
+	case "folder":
+		if nodeID = strings.TrimSpace(nodeID); nodeID == "" {
+			return "", fmt.Errorf("node_id is required for scope=folder")
+		}
+		return nodeID, nil
+	}
+	return "", fmt.Errorf("scope must be all or folder")
+}
+
+// registerExportFileRoutes adds POST /export/file, which runs an address space export and writes
+// the file below the export directory on this machine or returns it as a download.
+func registerExportFileRoutes(api *gin.RouterGroup, ctrl controller.NodeManager, cfg *opc.Config) {
+	api.POST("/export/file", func(c *gin.Context) {
+		controllerCtx := ctrl.GetClientContext()
+		if controllerCtx == nil || controllerCtx.Err() != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+			return
+		}
+		var req struct {
+			Format   string  `json:"format"`
+			Scope    string  `json:"scope"`
+			NodeID   string  `json:"node_id"`
+			Template string  `json:"template"`
+			Path     string  `json:"path"`
+			Download bool    `json:"download"`
+			Timeout  float64 `json:"timeout"` // seconds; zero uses the configured export timeout
+			// CSV options; empty ones use the configured defaults
+			Delimiter string `json:"delimiter"`
+			BOM       *bool  `json:"bom"`
+			Quote     string `json:"quote"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		format := strings.ToLower(strings.TrimSpace(req.Format))
+		switch format {
+		case "":
+			format = "json"
+		case "excel":
+			format = "xlsx"
+		case "json", "json-stream", "csv", "xlsx":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(controller.AddressSpaceFormats, ", ")})
+			return
+		}
+		rootID, err := parseExportScope(req.Scope, req.NodeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, err := cfg.ExportTemplate(req.Template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		csvOpts, err := csvFormat(cfg, req.Delimiter, req.BOM, req.Quote)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ext := "." + strings.TrimSuffix(format, "-stream")
+		name := req.Path
+		if strings.TrimSpace(name) == "" {
+			name = "address_space_" + time.Now().Format("20060102_150405")
+		}
+		var path, attachment string
+		if req.Download {
+			// Every download gets its own file; the requested name is only offered to the client
+			dir := filepath.Join(os.TempDir(), "opcuababy-exports")
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			f, err := os.CreateTemp(dir, "download_*"+ext)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			f.Close()
+			path = f.Name()
+			attachment = filepath.Base(filepath.FromSlash(strings.TrimSpace(name)))
+			if filepath.Ext(attachment) == "" {
+				attachment += ext
+			}
+		} else {
+			path, err = exportFilePath(exportDir(cfg), name, ext)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		ctx := c.Request.Context()
+		if req.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(req.Timeout*float64(time.Second)))
+			defer cancel()
+		}
+		start := time.Now()
+		err = ctrl.ExportAddressSpace(ctx, controller.AddressSpaceExport{RootID: rootID, Format: format, Template: req.Template, CSV: &csvOpts, FilePath: path})
+		if err != nil {
+			if req.Download {
+				_ = os.Remove(path)
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "path": path})
+			return
+		}
+		if req.Download {
+			defer os.Remove(path)
+			c.FileAttachment(path, attachment)
+			return
+		}
+		var size int64
+		if st, err := os.Stat(path); err == nil {
+			size = st.Size()
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"path":        path,
+			"format":      format,
+			"node_id":     rootID,
+			"bytes":       size,
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+	})
+}
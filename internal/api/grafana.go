@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// grafanaOptions select how a Grafana target is queried. simple-json sends them as data, the
+// JSON API datasource as payload.
+type grafanaOptions struct {
+	// Aggregate is a server-side aggregate such as Average, computed over the panel interval;
+	// empty reads raw values
+	Aggregate string `json:"aggregate"`
+	// Source is "history" (the server's historian only), "live" (the values last received for
+	// the watch item only) or empty for history followed by newer live values
+	Source string `json:"source"`
+}
+
+// grafanaTarget is one query of a Grafana panel; Target is a NodeID.
+type grafanaTarget struct {
+	Target  string         `json:"target"`
+	RefID   string         `json:"refId"`
+	Type    string         `json:"type"` // "timeserie" (default) or "table"
+	Hide    bool           `json:"hide"`
+	Data    grafanaOptions `json:"data"`
+	Payload grafanaOptions `json:"payload"`
+}
+
+func (t grafanaTarget) options() grafanaOptions {
+	if t.Payload != (grafanaOptions{}) {
+		return t.Payload
+	}
+	return t.Data
+}
+
+type grafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	IntervalMs int64           `json:"intervalMs"`
+	Targets    []grafanaTarget `json:"targets"`
+}
+
+// grafanaSeries is a time series result: datapoints are [value, unix milliseconds].
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	RefID      string       `json:"refId,omitempty"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type grafanaTable struct {
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Type    string          `json:"type"`
+	RefID   string          `json:"refId,omitempty"`
+}
+
+// registerGrafanaRoutes serves the query protocol of the Grafana simple-json and JSON API
+// datasources (and Infinity's JSON backend) under /api/v1/grafana, so panels can chart watched
+// nodes from the server's historian and live values without a database in between.
+func registerGrafanaRoutes(api *gin.RouterGroup, ctrl controller.NodeManager) {
+	// Connection test of the datasource settings page
+	api.GET("/grafana", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "connected": ctrl.ConnectionStatus().Connected})
+	})
+
+	// Metric names for the query editor: watch items whose name or NodeID contains target.
+	api.POST("/grafana/search", func(c *gin.Context) {
+		var req struct {
+			Target string `json:"target"`
+		}
+		// An empty body lists everything
+		_ = c.ShouldBindJSON(&req)
+		q := strings.ToLower(strings.TrimSpace(req.Target))
+		out := make([]gin.H, 0)
+		for _, it := range ctrl.WatchSnapshot() {
+			if q != "" && !strings.Contains(strings.ToLower(it.Name), q) && !strings.Contains(strings.ToLower(it.NodeID), q) {
+				continue
+			}
+			out = append(out, gin.H{"text": grafanaName(it), "value": it.NodeID})
+		}
+		c.JSON(http.StatusOK, out)
+	})
+
+	api.POST("/grafana/query", func(c *gin.Context) {
+		var req grafanaQueryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to := req.Range.To
+		if to.IsZero() {
+			to = time.Now()
+		}
+		from := req.Range.From
+		if from.IsZero() {
+			from = to.Add(-time.Hour)
+		}
+		interval := time.Duration(req.IntervalMs) * time.Millisecond
+		if interval < time.Second {
+			interval = time.Second
+		}
+
+		names := make(map[string]string)
+		for _, it := range ctrl.WatchSnapshot() {
+			names[it.NodeID] = grafanaName(it)
+		}
+		out := make([]interface{}, 0, len(req.Targets))
+		for _, t := range req.Targets {
+			t.Target = strings.TrimSpace(t.Target)
+			if t.Hide {
+				continue
+			}
+			if t.Type == "table" {
+				out = append(out, grafanaWatchTable(ctrl, t))
+				continue
+			}
+			if t.Target == "" {
+				continue
+			}
+			points, err := grafanaPoints(c.Request.Context(), ctrl, t.Target, t.options(), from, to, interval)
+			if err != nil {
+				c.JSON(grafanaErrorStatus(err), gin.H{"error": fmt.Sprintf("%s: %v", t.Target, err)})
+				return
+			}
+			s := grafanaSeries{Target: t.Target, RefID: t.RefID, Datapoints: make([][2]float64, 0, len(points))}
+			if name, ok := names[t.Target]; ok {
+				s.Target = name
+			}
+			for _, p := range points {
+				s.Datapoints = append(s.Datapoints, [2]float64{p.Value, float64(p.Time.UnixMilli())})
+			}
+			out = append(out, s)
+		}
+		c.JSON(http.StatusOK, out)
+	})
+}
+
+// grafanaPoints reads the numeric values of nodeID in [from, to]. Without a source, the values
+// last received for a watch item are appended after the history, and stand in for it when the
+// server has none.
+func grafanaPoints(ctx context.Context, ctrl controller.NodeManager, nodeID string, opts grafanaOptions, from, to time.Time, interval time.Duration) ([]controller.HistoryPoint, error) {
+	source := strings.ToLower(strings.TrimSpace(opts.Source))
+	if source != "" && source != "history" && source != "live" {
+		return nil, fmt.Errorf("source must be history or live, got %q", opts.Source)
+	}
+	var points []controller.HistoryPoint
+	var historyErr error
+	if source != "live" {
+		var values []*controller.HistoryValue
+		if opts.Aggregate != "" {
+			values, historyErr = ctrl.ReadHistoryAggregate(ctx, nodeID, opts.Aggregate, from, to, interval)
+		} else {
+			values, historyErr = ctrl.ReadHistoryRaw(ctx, nodeID, from, to, 0)
+		}
+		// A truncated read still charts the values read
+		if errors.Is(historyErr, opc.ErrHistoryTruncated) {
+			historyErr = nil
+		}
+		// Invalid queries fail whatever the source
+		if historyErr != nil && (source == "history" || grafanaErrorStatus(historyErr) == http.StatusBadRequest) {
+			return nil, historyErr
+		}
+		points = controller.NumericPoints(values)
+	}
+	if source == "history" {
+		return points, nil
+	}
+
+	live := ctrl.WatchPoints(nodeID)
+	if live == nil {
+		if historyErr != nil {
+			return nil, historyErr
+		}
+		if source == "live" {
+			return nil, errors.New("not on the watch list")
+		}
+		return points, nil
+	}
+	var last time.Time
+	if len(points) > 0 {
+		last = points[len(points)-1].Time
+	}
+	for _, p := range live {
+		if p.Time.After(last) && !p.Time.Before(from) && !p.Time.After(to) {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+// grafanaWatchTable lists the current values of the watch items, or only of the target NodeID.
+func grafanaWatchTable(ctrl controller.NodeManager, t grafanaTarget) grafanaTable {
+	table := grafanaTable{
+		Columns: []grafanaColumn{
+			{Text: "Node ID", Type: "string"},
+			{Text: "Name", Type: "string"},
+			{Text: "Value", Type: "string"},
+			{Text: "Status", Type: "string"},
+			{Text: "Source time", Type: "time"},
+		},
+		Rows:  make([][]interface{}, 0),
+		Type:  "table",
+		RefID: t.RefID,
+	}
+	for _, it := range ctrl.WatchSnapshot() {
+		if t.Target != "" && t.Target != "*" && t.Target != it.NodeID {
+			continue
+		}
+		var ts interface{}
+		if at, err := time.Parse(time.RFC3339Nano, it.SourceTimestamp); err == nil {
+			ts = at.UnixMilli()
+		}
+		status := it.SymbolicName
+		if status == "" {
+			status = it.Severity
+		}
+		table.Rows = append(table.Rows, []interface{}{it.NodeID, grafanaName(it), it.Value, status, ts})
+	}
+	return table
+}
+
+func grafanaName(it *controller.WatchItem) string {
+	if it.Name != "" {
+		return it.Name
+	}
+	return it.NodeID
+}
+
+func grafanaErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not connected"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "unsupported aggregate"), strings.Contains(msg, "must be"), strings.Contains(msg, "not on the watch list"):
+		return http.StatusBadRequest
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"opcuababy/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// includeMeta reports whether ?include= (comma-separated or repeated) asks for node metadata.
+func includeMeta(c *gin.Context) bool {
+	for _, v := range c.QueryArray("include") {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), "meta") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// watchItemMeta is a watch update with the metadata of its node.
+type watchItemMeta struct {
+	*controller.WatchItem
+	Meta *controller.NodeMeta `json:"meta"`
+}
+
+// withMeta returns it with the metadata of its node when meta is set. The controller caches the
+// metadata, so only the first update of a node costs a read.
+func withMeta(ctx context.Context, ctrl controller.NodeManager, it *controller.WatchItem, meta bool) interface{} {
+	if !meta || it == nil {
+		return it
+	}
+	return watchItemMeta{WatchItem: it, Meta: ctrl.NodeMeta(ctx, it.NodeID)}
+}
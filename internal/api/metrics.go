@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"opcuababy/internal/controller"
+)
+
+// writeMetrics renders the connection state and the channel statistics of the current OPC UA
+// connection in the Prometheus text exposition format. Counters restart with each connection.
+func writeMetrics(w io.Writer, ctrl controller.NodeManager) {
+	st := ctrl.ConnectionStatus()
+	gauge := func(name, help string, v float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatFloat(v))
+	}
+	gauge("opcua_connected", "1 while an OPC UA session is established.", boolFloat(st.Connected))
+	gauge("opcua_watch_items", "Number of nodes on the watch list.", float64(st.Watches))
+
+	cs := ctrl.ChannelStats()
+	if cs == nil {
+		return
+	}
+	counter := func(name, help string, v uint64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+	}
+	counter("opcua_bytes_sent_total", "Encoded request bytes sent, excluding secure channel overhead.", cs.BytesSent)
+	counter("opcua_bytes_received_total", "Encoded response and notification bytes received, excluding secure channel overhead.", cs.BytesReceived)
+	counter("opcua_notifications_total", "Data change notifications received.", cs.Notifications)
+	gauge("opcua_connection_start_time_seconds", "Unix time the counters started.", float64(cs.Since.UnixNano())/1e9)
+
+	perService := func(name, typ, help string, value func(i int) string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for i, s := range cs.Services {
+			fmt.Fprintf(w, "%s{service=\"%s\"} %s\n", name, escapeLabel(s.Service), value(i))
+		}
+	}
+	perService("opcua_requests_total", "counter", "OPC UA service requests sent.", func(i int) string {
+		return strconv.FormatUint(cs.Services[i].Requests, 10)
+	})
+	perService("opcua_request_errors_total", "counter", "OPC UA service requests that failed.", func(i int) string {
+		return strconv.FormatUint(cs.Services[i].Errors, 10)
+	})
+	perService("opcua_request_duration_seconds_sum", "counter", "Total time spent waiting for OPC UA service responses.", func(i int) string {
+		return formatFloat(cs.Services[i].TotalLatency.Seconds())
+	})
+	perService("opcua_request_duration_seconds_max", "gauge", "Slowest OPC UA service response.", func(i int) string {
+		return formatFloat(cs.Services[i].MaxLatencyMs / 1000)
+	})
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startPublicDashboard serves the read-only dashboard on cfg.PublicDashboardAddr until ctx ends.
+// It has its own router with nothing but the page, the connection status and the watch values,
+// so the port can be opened to plant TVs. The API port is a separate matter: it is only
+// protected once dashboard users or an API key are configured (see opc.Config.ApiAuthRequired).
+func startPublicDashboard(ctx context.Context, ctrl controller.NodeManager, cfg *opc.Config) *http.Server {
+	addr := cfg.PublicDashboardAddr()
+	if addr == "" {
+		return nil
+	}
+	if err := cfg.ValidatePublicDashboardPort(); err != nil {
+		ctrl.Log("[red]Public dashboard not started: " + err.Error() + "[-]")
+		return nil
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/", func(c *gin.Context) {
+		data, err := webTemplate.ReadFile("templates/public.html")
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Error reading dashboard page")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	})
+	router.GET("/api/v1/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, ctrl.ConnectionStatus())
+	})
+	router.GET("/api/v1/watch/values", func(c *gin.Context) {
+		c.JSON(http.StatusOK, watchValues(c.Request.Context(), ctrl, c.QueryArray("node_id"), false))
+	})
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+	go func() {
+		ctrl.Log("[green]Read-only public dashboard listening on " + addr + "[-]")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			ctrl.Log("[red]Public dashboard: " + err.Error() + "[-]")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	return srv
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDHeader carries the correlation ID of an API request in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID limits client supplied IDs to what is safe to echo into headers and logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// requestIDMiddleware assigns every request a correlation ID, taken from X-Request-ID when the
+// client sent a usable one. The ID is returned in the X-Request-ID response header and attached
+// to the request context so controller log lines of the resulting OPC UA operations carry it.
+// Requests that change state or fail are logged to the controller log with their ID.
+func requestIDMiddleware(ctrl controller.NodeManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			b := make([]byte, 8)
+			_, _ = rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		c.Set("request_id", id)
+		c.Header(requestIDHeader, id)
+		c.Request = c.Request.WithContext(controller.WithRequestID(c.Request.Context(), id))
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if c.Request.Method == http.MethodGet && status < http.StatusBadRequest {
+			return
+		}
+		color := "[cyan]"
+		if status >= http.StatusBadRequest {
+			color = "[red]"
+		}
+		ctrl.Log(fmt.Sprintf("%s[req %s] %s %s -> %d (%dms)[-]", color, id, c.Request.Method, c.Request.URL.Path, status, time.Since(start).Milliseconds()))
+	}
+}
+
+// requestID returns the correlation ID assigned by requestIDMiddleware.
+func requestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
@@ -0,0 +1,496 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// JSON-RPC 2.0 error codes; rpcNotConnected and rpcUnauthorized are application errors in the
+// server range.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+	rpcNotConnected   = -32001
+	rpcUnauthorized   = -32003
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications, which get no response
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// rpcNotification is sent to JSON-RPC clients for subscribed watch updates ("watch.update") and
+// connection events ("connection.lost", "connection.restored").
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// rpcConn carries the responses of a /ws/rpc client next to its watch notifications; done is
+// closed when the write pump has stopped. authorized is set when the upgrade request carried
+// the API key or a dashboard session.
+type rpcConn struct {
+	replies    chan *rpcResponse
+	done       chan struct{}
+	authorized bool
+}
+
+// rpcMethod handles one JSON-RPC method; returned *rpcError values keep their code, other
+// errors are reported as rpcServerError.
+type rpcMethod func(c *Client, params json.RawMessage) (interface{}, error)
+
+// rpcMethods is the remote control surface of /ws/rpc.
+var rpcMethods = map[string]rpcMethod{
+	"status": func(c *Client, _ json.RawMessage) (interface{}, error) {
+		return c.hub.controller.ConnectionStatus(), nil
+	},
+	"connect": func(c *Client, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			EndpointURL string `json:"endpoint_url"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if err := c.hub.controller.ConnectEndpoint(p.EndpointURL); err != nil {
+			return nil, err
+		}
+		return c.hub.controller.ConnectionStatus(), nil
+	},
+	"disconnect": func(c *Client, _ json.RawMessage) (interface{}, error) {
+		c.hub.controller.Disconnect()
+		return c.hub.controller.ConnectionStatus(), nil
+	},
+	"browse": rpcBrowse,
+	"search": func(c *Client, params json.RawMessage) (interface{}, error) {
+		var p controller.SearchRequest
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		return c.hub.controller.SearchNodes(c.ctx, p, nil)
+	},
+	"read": func(c *Client, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			NodeID string `json:"node_id"`
+		}
+		if err := decodeNodeParams(params, &p, &p.NodeID); err != nil {
+			return nil, err
+		}
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		return c.hub.controller.ReadNodeAttributes(c.ctx, p.NodeID)
+	},
+	"write": func(c *Client, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			NodeID   string `json:"node_id"`
+			DataType string `json:"data_type"`
+			Value    string `json:"value"`
+		}
+		if err := decodeNodeParams(params, &p, &p.NodeID); err != nil {
+			return nil, err
+		}
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		if c.hub.controller.IsOffline() {
+			return nil, errors.New("writes are disabled in offline mode")
+		}
+		if err := c.hub.controller.CheckWriteAllowed(p.NodeID); err != nil {
+			return nil, err
+		}
+		// The write completes after the reply; it ends with the server, not the connection
+		c.hub.controller.WriteValue(c.hub.ctx, p.NodeID, p.DataType, p.Value)
+		return gin.H{"status": "write request sent"}, nil
+	},
+	"alarm.list": func(c *Client, _ json.RawMessage) (interface{}, error) {
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		return c.hub.controller.Conditions(c.ctx)
+	},
+	"alarm.acknowledge": func(c *Client, params json.RawMessage) (interface{}, error) {
+		return rpcConditionAction(c, params, controller.ConditionAcknowledge)
+	},
+	"alarm.confirm": func(c *Client, params json.RawMessage) (interface{}, error) {
+		return rpcConditionAction(c, params, controller.ConditionConfirm)
+	},
+	"alarm.comment": func(c *Client, params json.RawMessage) (interface{}, error) {
+		return rpcConditionAction(c, params, controller.ConditionComment)
+	},
+	"watch.list": func(c *Client, _ json.RawMessage) (interface{}, error) {
+		return c.hub.controller.WatchSnapshot(), nil
+	},
+	"watch.add": func(c *Client, params json.RawMessage) (interface{}, error) {
+		ids, err := decodeNodeIDs(params)
+		if err != nil {
+			return nil, err
+		}
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			c.hub.controller.AddWatch(c.ctx, id)
+		}
+		return c.hub.controller.WatchSnapshot(), nil
+	},
+	"watch.remove": func(c *Client, params json.RawMessage) (interface{}, error) {
+		ids, err := decodeNodeIDs(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			c.hub.controller.RemoveWatch(id)
+		}
+		return c.hub.controller.WatchSnapshot(), nil
+	},
+	"watch.clear": func(c *Client, _ json.RawMessage) (interface{}, error) {
+		c.hub.controller.RemoveAllWatches()
+		return c.hub.controller.WatchSnapshot(), nil
+	},
+	"subscribe":   func(c *Client, params json.RawMessage) (interface{}, error) { return rpcSubscribe(c, params, true) },
+	"unsubscribe": func(c *Client, params json.RawMessage) (interface{}, error) { return rpcSubscribe(c, params, false) },
+	"export.tags": func(c *Client, params json.RawMessage) (interface{}, error) {
+		p := struct {
+			NodeID    string `json:"node_id"`
+			Recursive *bool  `json:"recursive"`
+		}{}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+		recursive := p.Recursive == nil || *p.Recursive
+		return c.hub.controller.CollectVariableNodes(c.ctx, strings.TrimSpace(p.NodeID), recursive)
+	},
+}
+
+// rpcControlMethods connect, disconnect or act on the plant. They are only accepted on
+// connections opened with the API key or a dashboard session, so they are unavailable until
+// one of them is configured.
+var rpcControlMethods = map[string]bool{
+	"connect":           true,
+	"disconnect":        true,
+	"write":             true,
+	"alarm.acknowledge": true,
+	"alarm.confirm":     true,
+	"alarm.comment":     true,
+}
+
+// rpcBrowse returns the children of node_id (default: Objects), browsing it first if needed or
+// again when refresh is set.
+func rpcBrowse(c *Client, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		NodeID  string `json:"node_id"`
+		Refresh bool   `json:"refresh"` // browse again instead of answering from the cache
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := requireSession(c.hub.controller); err != nil {
+		return nil, err
+	}
+	ctrl := c.hub.controller
+	nodeID := strings.TrimSpace(p.NodeID)
+	if nodeID == "" {
+		nodeID = "i=85"
+	}
+	if p.Refresh {
+		if err := ctrl.RefreshBranch(c.ctx, nodeID); err != nil {
+			return nil, err
+		}
+	} else if !ctrl.HasBrowseBeenPerformed(nodeID) {
+		ctrl.Browse(c.ctx, nodeID)
+		if !ctrl.HasBrowseBeenPerformed(nodeID) {
+			return nil, errors.New("browse of " + nodeID + " failed, see the application log")
+		}
+	}
+	type child struct {
+		NodeID      string `json:"node_id"`
+		Name        string `json:"name"`
+		NodeClass   string `json:"node_class"`
+		HasChildren bool   `json:"has_children"`
+	}
+	children := []child{}
+	for _, id := range ctrl.GetAddressSpaceChildren(nodeID) {
+		n := ctrl.GetNode(id)
+		if n == nil {
+			continue
+		}
+		children = append(children, child{NodeID: n.NodeID, Name: n.Name, NodeClass: n.NodeClass.String(), HasChildren: n.HasChildren})
+	}
+	return children, nil
+}
+
+// rpcConditionAction acknowledges, confirms or comments the condition condition_id.
+func rpcConditionAction(c *Client, params json.RawMessage, action string) (interface{}, error) {
+	var p controller.ConditionRequest
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(p.ConditionID) == "" {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "condition_id is required"}
+	}
+	if err := requireSession(c.hub.controller); err != nil {
+		return nil, err
+	}
+	if err := c.hub.controller.ConditionAction(c.ctx, action, p); err != nil {
+		return nil, err
+	}
+	return gin.H{"status": "Good"}, nil
+}
+
+// rpcSubscribe changes which watch updates the client receives as "watch.update" notifications.
+// Subscribing to a node also adds it to the watch list.
+func rpcSubscribe(c *Client, params json.RawMessage, on bool) (interface{}, error) {
+	var p struct {
+		NodeIDs []string `json:"node_ids"`
+		All     bool     `json:"all"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if on && len(p.NodeIDs) > 0 {
+		if err := requireSession(c.hub.controller); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.Lock()
+	if p.All {
+		c.subscribeAll = on
+	}
+	for _, id := range p.NodeIDs {
+		if on {
+			c.subscriptions[id] = true
+		} else {
+			delete(c.subscriptions, id)
+		}
+	}
+	subs := make([]string, 0, len(c.subscriptions))
+	for id := range c.subscriptions {
+		subs = append(subs, id)
+	}
+	all := c.subscribeAll
+	c.mu.Unlock()
+	if on {
+		for _, id := range p.NodeIDs {
+			c.hub.controller.AddWatch(c.ctx, id)
+		}
+	}
+	return gin.H{"all": all, "node_ids": subs}, nil
+}
+
+func requireSession(ctrl controller.NodeManager) error {
+	if st := ctrl.ConnectionStatus(); !st.Connected && !st.Offline {
+		return &rpcError{Code: rpcNotConnected, Message: "OPC UA connection is not active"}
+	}
+	return nil
+}
+
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// decodeNodeParams decodes params and requires a non-empty node_id.
+func decodeNodeParams(params json.RawMessage, v interface{}, nodeID *string) error {
+	if err := decodeParams(params, v); err != nil {
+		return err
+	}
+	if *nodeID = strings.TrimSpace(*nodeID); *nodeID == "" {
+		return &rpcError{Code: rpcInvalidParams, Message: "node_id is required"}
+	}
+	return nil
+}
+
+func decodeNodeIDs(params json.RawMessage) ([]string, error) {
+	var p struct {
+		NodeIDs []string `json:"node_ids"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.NodeIDs) == 0 {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "node_ids is required"}
+	}
+	return p.NodeIDs, nil
+}
+
+// rpcUpgrader accepts /ws/rpc connections from clients without an Origin (scripts), from pages
+// served by the API server itself and from cfg.ApiAllowedOrigins, so that a web page open in a
+// browser on the plant network cannot drive the application (cross-site WebSocket hijacking).
+func rpcUpgrader(cfg *opc.Config) *websocket.Upgrader {
+	u := upgrader
+	u.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if o, err := url.Parse(origin); err == nil && strings.EqualFold(o.Host, r.Host) {
+			return true
+		}
+		for _, allowed := range cfg.ApiAllowedOrigins {
+			if strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(allowed), "/"), origin) {
+				return true
+			}
+		}
+		return false
+	}
+	return &u
+}
+
+// serveRPC upgrades a /ws/rpc request. Unlike /ws/subscribe it does not need an OPC UA session,
+// so a remote frontend can connect and disconnect the application through it.
+func serveRPC(hub *Hub, c *gin.Context, cfg *opc.Config, dashboard *dashboardSessions) {
+	authorized := cfg.ApiAuthRequired() && dashboard.authenticated(c)
+	conn, err := rpcUpgrader(cfg).Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		if !hub.controller.IsLogDisabled() {
+			log.Printf("Failed to set websocket upgrade: %+v", err)
+		}
+		return
+	}
+	client := hub.newClient(conn)
+	client.rpc = &rpcConn{replies: make(chan *rpcResponse, 16), done: make(chan struct{}), authorized: authorized}
+	client.hub.register <- client
+
+	go client.rpcWritePump()
+	go client.rpcReadPump()
+}
+
+// rpcReadPump reads JSON-RPC requests; each call runs in its own goroutine so slow calls
+// (connect, export) do not hold up others.
+func (c *Client) rpcReadPump() {
+	defer func() {
+		c.cancel()
+		c.hub.unregister <- c
+		c.conn.Close()
+	}()
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("error: %v", err)
+			}
+			return
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			c.reply(&rpcResponse{ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			id := req.ID
+			if len(id) == 0 {
+				id = json.RawMessage("null")
+			}
+			c.reply(&rpcResponse{ID: id, Error: &rpcError{Code: rpcInvalidRequest, Message: "expected a JSON-RPC 2.0 request"}})
+			continue
+		}
+		go c.handleRPC(req)
+	}
+}
+
+func (c *Client) handleRPC(req rpcRequest) {
+	resp := &rpcResponse{ID: req.ID}
+	if method, ok := rpcMethods[req.Method]; !ok {
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	} else if rpcControlMethods[req.Method] && !c.rpc.authorized {
+		resp.Error = &rpcError{Code: rpcUnauthorized, Message: req.Method + " needs a connection opened with the API key or a dashboard login (Settings → Dashboard Login)"}
+	} else if result, err := method(c, req.Params); err != nil {
+		var re *rpcError
+		if !errors.As(err, &re) {
+			re = &rpcError{Code: rpcServerError, Message: err.Error()}
+		}
+		resp.Error = re
+	} else if resp.Result, err = json.Marshal(result); err != nil {
+		resp.Result, resp.Error = nil, &rpcError{Code: rpcServerError, Message: err.Error()}
+	}
+	if len(req.ID) == 0 {
+		return // notification
+	}
+	c.reply(resp)
+}
+
+func (c *Client) reply(resp *rpcResponse) {
+	resp.JSONRPC = "2.0"
+	select {
+	case c.rpc.replies <- resp:
+	case <-c.rpc.done:
+	}
+}
+
+// rpcWritePump writes responses and notifications to the connection.
+func (c *Client) rpcWritePump() {
+	defer func() {
+		close(c.rpc.done)
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case message, ok := <-c.send:
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			method := "watch.update"
+			if ev, ok := message.(controller.ConnectionEvent); ok {
+				method = strings.Replace(ev.Event, "_", ".", 1)
+			}
+			if err := c.conn.WriteJSON(rpcNotification{JSONRPC: "2.0", Method: method, Params: message}); err != nil {
+				log.Printf("error writing json: %v", err)
+				return
+			}
+		case <-c.updates.Ready():
+			for _, message := range c.updates.Drain() {
+				if err := c.conn.WriteJSON(rpcNotification{JSONRPC: "2.0", Method: "watch.update", Params: message}); err != nil {
+					log.Printf("error writing json: %v", err)
+					return
+				}
+			}
+		case resp := <-c.rpc.replies:
+			if err := c.conn.WriteJSON(resp); err != nil {
+				log.Printf("error writing json: %v", err)
+				return
+			}
+		}
+	}
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -24,35 +26,50 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-// Client is a middleman between the websocket connection and the hub.
+// Client is a middleman between a websocket connection or an SSE stream and the hub.
 type Client struct {
 	hub *Hub
-	// The websocket connection.
+	// The websocket connection; nil for /api/v1/stream clients.
 	conn *websocket.Conn
-	// Buffered channel of outbound messages.
-	send chan *controller.WatchItem
+	// Address of the peer, for the client list
+	remote string
+	// Buffered channel of outbound connection events (controller.ConnectionEvent).
+	send chan interface{}
+	// Watch updates for this client, newest value per node
+	updates *controller.WatchFeed
 	// A map of nodeIDs the client is subscribed to.
 	subscriptions map[string]bool
 	// If true, client receives all watch updates regardless of per-node subscriptions
 	subscribeAll bool
-	mu            sync.RWMutex
+	// If true, watch updates carry the node's metadata (?include=meta)
+	meta bool
+	mu   sync.RWMutex
+	// Set for /ws/rpc clients, which stay connected across OPC UA sessions
+	rpc *rpcConn
+	// ctx bounds the OPC UA calls made for this client; it ends when the connection closes
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // Hub maintains the set of active clients and broadcasts messages to the
 // clients.
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan *controller.WatchItem
+	feed       *controller.WatchFeed // all watch updates, fanned out to the clients
+	events     *eventLog             // watch updates and connection events for /api/v1/events
 	register   chan *Client
 	unregister chan *Client
 	controller controller.NodeManager
 	mu         sync.Mutex
 	stop       chan struct{}
+	ctx        context.Context // lifetime of the API server
 }
 
-func newHub(ctrl controller.NodeManager) *Hub {
+func newHub(ctx context.Context, ctrl controller.NodeManager) *Hub {
 	return &Hub{
-		broadcast:  ctrl.GetApiBroadcastChan(),
+		ctx:        ctx,
+		feed:       ctrl.SubscribeWatchFeed(),
+		events:     newEventLog(),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
@@ -61,13 +78,31 @@ func newHub(ctrl controller.NodeManager) *Hub {
 	}
 }
 
+// newClient returns a client of conn whose context ends with the connection or the server.
+func (h *Hub) newClient(conn *websocket.Conn) *Client {
+	client := h.newStreamClient(conn.RemoteAddr().String())
+	client.conn = conn
+	return client
+}
+
+// newStreamClient returns a client without a websocket connection, for the SSE stream of
+// remote; its context ends when the stream is closed (cancel) or with the server.
+func (h *Hub) newStreamClient(remote string) *Client {
+	ctx, cancel := context.WithCancel(h.ctx)
+	return &Client{
+		hub:           h,
+		remote:        remote,
+		send:          make(chan interface{}, 16),
+		updates:       controller.NewWatchFeed(),
+		subscriptions: make(map[string]bool),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
 func (h *Hub) run(context.Context) {
+	events := h.controller.ConnectionEvents()
 	for {
-		// Watch controller client context to close clients on OPC UA disconnect
-		var ctrlDone <-chan struct{}
-		if cctx := h.controller.GetClientContext(); cctx != nil {
-			ctrlDone = cctx.Done()
-		}
 		select {
 		case client := <-h.register:
 			h.mu.Lock()
@@ -80,58 +115,36 @@ func (h *Hub) run(context.Context) {
 				close(client.send)
 			}
 			h.mu.Unlock()
-		case <-ctrlDone:
-			// OPC UA client context cancelled; close all clients and reset broadcast
+		case ev := <-events:
+			// Clients stay connected across OPC UA reconnects; they are told about the outage and
+			// their subscriptions are re-established on the new session.
 			if !h.controller.IsLogDisabled() {
-				log.Println("Hub: OPC UA client context done, closing websocket clients.")
+				log.Printf("Hub: %s, notifying websocket clients.", ev.Event)
 			}
-			h.mu.Lock()
-			for client := range h.clients {
-				close(client.send)
-				delete(h.clients, client)
+			h.notify(ev)
+			h.events.append("connection", ev)
+			if ev.Event == controller.EventConnectionRestored {
+				go h.resubscribe()
 			}
-			h.mu.Unlock()
-			h.broadcast = h.controller.GetApiBroadcastChan()
-			continue
-		case message, ok := <-h.broadcast:
-			if !ok {
-				// The broadcast channel was closed by the controller, indicating a disconnect.
-				// Close all client connections.
-				if !h.controller.IsLogDisabled() {
-					log.Println("Hub: Controller disconnected, closing all websocket clients.")
-				}
-				h.mu.Lock()
-				for client := range h.clients {
-					close(client.send)
-					delete(h.clients, client)
-				}
-				h.mu.Unlock()
-				h.broadcast = h.controller.GetApiBroadcastChan()
-
-				// The Hub continues to run, ready for a new connection cycle.
-				// It will accept new clients once the controller reconnects and
-				// re-creates the broadcast channel.
-				continue // Continue the loop to accept new registrations etc.
+		case <-h.feed.Ready():
+			// Slow clients are never dropped: their feeds keep the newest value per node.
+			messages := h.feed.Drain()
+			for _, message := range messages {
+				h.events.append("watch", message)
 			}
-
 			h.mu.Lock()
 			for client := range h.clients {
 				client.mu.RLock()
-				forward := client.subscribeAll || client.subscriptions[message.NodeID]
-				client.mu.RUnlock()
-				if forward {
-					select {
-					case client.send <- message:
-					default:
-						close(client.send)
-						h.mu.Lock()
-						delete(h.clients, client)
-						h.mu.Unlock()
+				for _, message := range messages {
+					if client.subscribeAll || client.subscriptions[message.NodeID] {
+						client.updates.Push(message)
 					}
 				}
+				client.mu.RUnlock()
 			}
 			h.mu.Unlock()
 		case <-h.stop:
+			h.controller.UnsubscribeWatchFeed(h.feed)
 			h.mu.Lock()
 			for client := range h.clients {
 				close(client.send)
@@ -143,6 +156,40 @@ func (h *Hub) run(context.Context) {
 	}
 }
 
+// notify sends a connection event to every client; clients too slow to take it are dropped.
+func (h *Hub) notify(ev controller.ConnectionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.clients {
+		select {
+		case client.send <- ev:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+}
+
+// resubscribe adds the nodes subscribed by any client to the watch list of the new session.
+func (h *Hub) resubscribe() {
+	nodes := make(map[string]bool)
+	h.mu.Lock()
+	for client := range h.clients {
+		client.mu.RLock()
+		for id := range client.subscriptions {
+			nodes[id] = true
+		}
+		client.mu.RUnlock()
+	}
+	h.mu.Unlock()
+	for id := range nodes {
+		h.controller.AddWatch(h.ctx, id)
+	}
+	if len(nodes) > 0 {
+		h.controller.Log(fmt.Sprintf("[green]Re-established %d WebSocket subscriptions on the new session[-]", len(nodes)))
+	}
+}
+
 // WebSocketMessage defines the structure for messages between client and server.
 type WebSocketMessage struct {
 	Action  string   `json:"action"` // "subscribe", "unsubscribe", "subscribe_all", "unsubscribe_all"
@@ -152,6 +199,7 @@ type WebSocketMessage struct {
 // readPump pumps messages from the websocket connection to the hub.
 func (c *Client) readPump() {
 	defer func() {
+		c.cancel()
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
@@ -168,29 +216,7 @@ func (c *Client) readPump() {
 		c.mu.Lock()
 		switch msg.Action {
 		case "subscribe":
-			for _, nodeID := range msg.NodeIDs {
-				c.subscriptions[nodeID] = true
-				// Ensure a server-side watch exists
-				go c.hub.controller.AddWatch(nodeID)
-				// Send current snapshot to this client immediately (best effort)
-				go func(nid string) {
-					attrs, err := c.hub.controller.ReadNodeAttributes(nid)
-					if err == nil && attrs != nil {
-						now := time.Now().Format("15:04:05.000")
-						wi := &controller.WatchItem{
-							NodeID:    attrs.NodeID,
-							Name:      attrs.Name,
-							DataType:  attrs.DataType,
-							Value:     attrs.Value,
-							Timestamp: now,
-						}
-						select {
-						case c.send <- wi:
-						default:
-						}
-					}
-				}(nodeID)
-			}
+			c.subscribeLocked(msg.NodeIDs)
 		case "unsubscribe":
 			for _, nodeID := range msg.NodeIDs {
 				delete(c.subscriptions, nodeID)
@@ -204,56 +230,140 @@ func (c *Client) readPump() {
 	}
 }
 
+// subscribeLocked subscribes the client to nodeIDs, adds them to the watch list and queues their
+// current values. Callers must hold c.mu.
+func (c *Client) subscribeLocked(nodeIDs []string) {
+	for _, nodeID := range nodeIDs {
+		c.subscriptions[nodeID] = true
+		// Ensure a server-side watch exists
+		go c.hub.controller.AddWatch(c.ctx, nodeID)
+		// Send current snapshot to this client immediately (best effort)
+		go func(nid string) {
+			attrs, err := c.hub.controller.ReadNodeAttributes(c.ctx, nid)
+			if err == nil && attrs != nil {
+				wi := &controller.WatchItem{
+					NodeID:          attrs.NodeID,
+					Name:            attrs.Name,
+					DataType:        attrs.DataType,
+					Value:           attrs.Value,
+					ValueTyped:      attrs.ValueTyped,
+					UAType:          attrs.UAType,
+					Timestamp:       attrs.Timestamp,
+					SourceTimestamp: attrs.SourceTimestamp,
+					ServerTimestamp: attrs.ServerTimestamp,
+				}
+				c.updates.Push(wi)
+			}
+		}(nodeID)
+	}
+}
+
 // writePump pumps messages from the hub to the websocket connection.
 func (c *Client) writePump() {
 	defer func() {
 		c.conn.Close()
 	}()
-	for message := range c.send {
-		if err := c.conn.WriteJSON(message); err != nil {
-			log.Printf("error writing json: %v", err)
-			return
+	for {
+		select {
+		case message, ok := <-c.send:
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(message); err != nil {
+				log.Printf("error writing json: %v", err)
+				return
+			}
+		case <-c.updates.Ready():
+			for _, message := range c.updates.Drain() {
+				if err := c.conn.WriteJSON(withMeta(c.ctx, c.hub.controller, message, c.meta)); err != nil {
+					log.Printf("error writing json: %v", err)
+					return
+				}
+			}
 		}
 	}
-	c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 }
 
 // StartServer initializes and starts the API server. It returns the http.Server instance.
 func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *string, cfg *opc.Config) *http.Server {
-	hub := newHub(ctrl)
+	hub := newHub(ctx, ctrl)
 	go hub.run(ctx)
 	router := gin.Default()
+	router.Use(requestIDMiddleware(ctrl))
+	dashboard := newDashboardSessions(cfg)
+	registerDashboardAuthRoutes(router, dashboard)
+	exportJobs := newExportJobStore(ctx)
 
-	// REST API endpoints
-	api := router.Group("/api/v1")
+	// Session and API state; always 200 and open so monitoring can tell "API up, OPC down" apart.
+	router.GET("/api/v1/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, hub.controller.ConnectionStatus())
+	})
+
+	// REST API endpoints; with dashboard users or an API key configured they need a dashboard
+	// session or the key, like the WebSocket, stream and metrics routes below.
+	api := router.Group("/api/v1", dashboard.requireAPI())
 	{
-		// Export all Variable nodes in the address space
+
+		// Export all Variable nodes in the address space.
+		// Supports ?name=&data_type= filters, ?limit=&offset= pagination, ?template= column layouts and ?job=true to run in the background.
+		// CSV output takes ?delimiter=comma|semicolon|tab, ?bom= and ?quote=minimal|all.
+		// format=ignition-json|ignition-csv|kepware-csv writes a SCADA tag import (?opc_server=&tag_group=&scan_rate=).
 		api.GET("/export/tags", func(c *gin.Context) {
 			controllerCtx := hub.controller.GetClientContext()
 			if controllerCtx == nil || controllerCtx.Err() != nil {
 				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
 				return
 			}
+			tmpl, err := exportTemplate(c, cfg)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			csvOpts, err := csvQueryFormat(c, cfg)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if isTruthy(c.Query("job")) {
+				job, err := exportJobs.start(ctx, ctrl, "", true, exportOutput{})
+				if err != nil {
+					c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusAccepted, gin.H{"job": job, "status_url": "/api/v1/export/jobs/" + job.ID})
+				return
+			}
 			format := strings.ToLower(strings.TrimSpace(c.Query("format")))
-			if format == "" { format = "json" }
-			tags, err := ctrl.CollectVariableNodes("", true)
+			if format == "" {
+				format = "json"
+			}
+			tags, err := ctrl.CollectVariableNodes(c.Request.Context(), "", true)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
+			tags, err = paginateTags(c, parseTagFilter(c).apply(tags))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if preset, ok := controller.LookupTagPreset(format); ok {
+				writeTagPreset(c, "tags_all_"+preset.ID, preset, tagPresetOptions(c), tags)
+				return
+			}
 			if format == "csv" {
-				c.Header("Content-Disposition", "attachment; filename=tags_all.csv")
-				c.Header("Content-Type", "text/csv; charset=utf-8")
-				w := csv.NewWriter(c.Writer)
-				defer w.Flush()
-				_ = w.Write([]string{"NodeID","Name","DataType","Description","Path"})
-				for _, t := range tags { _ = w.Write([]string{t.NodeID, t.Name, t.DataType, t.Description, t.Path}) }
+				writeTagsCSV(c, "tags_all.csv", tmpl, csvOpts, tags)
+				return
+			}
+			if tmpl != nil {
+				c.JSON(http.StatusOK, templatedTags(tmpl, tags))
 				return
 			}
 			c.JSON(http.StatusOK, tags)
 		})
 
-		// Export Variable nodes under a specific folder
+		// Export Variable nodes under a specific folder (same filters, pagination and job mode as /export/tags)
 		api.GET("/export/tags/folder", func(c *gin.Context) {
 			controllerCtx := hub.controller.GetClientContext()
 			if controllerCtx == nil || controllerCtx.Err() != nil {
@@ -269,25 +379,301 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 			if rv := c.Query("recursive"); rv != "" {
 				recursive = rv != "false" && rv != "0"
 			}
+			tmpl, err := exportTemplate(c, cfg)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			csvOpts, err := csvQueryFormat(c, cfg)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if isTruthy(c.Query("job")) {
+				job, err := exportJobs.start(ctx, ctrl, nodeID, recursive, exportOutput{})
+				if err != nil {
+					c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusAccepted, gin.H{"job": job, "status_url": "/api/v1/export/jobs/" + job.ID})
+				return
+			}
 			format := strings.ToLower(strings.TrimSpace(c.Query("format")))
-			if format == "" { format = "json" }
-			tags, err := ctrl.CollectVariableNodes(nodeID, recursive)
+			if format == "" {
+				format = "json"
+			}
+			tags, err := ctrl.CollectVariableNodes(c.Request.Context(), nodeID, recursive)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
+			tags, err = paginateTags(c, parseTagFilter(c).apply(tags))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if preset, ok := controller.LookupTagPreset(format); ok {
+				writeTagPreset(c, "tags_folder_"+preset.ID, preset, tagPresetOptions(c), tags)
+				return
+			}
 			if format == "csv" {
-				c.Header("Content-Disposition", "attachment; filename=tags_folder.csv")
-				c.Header("Content-Type", "text/csv; charset=utf-8")
-				w := csv.NewWriter(c.Writer)
-				defer w.Flush()
-				_ = w.Write([]string{"NodeID","Name","DataType","Description","Path"})
-				for _, t := range tags { _ = w.Write([]string{t.NodeID, t.Name, t.DataType, t.Description, t.Path}) }
+				writeTagsCSV(c, "tags_folder.csv", tmpl, csvOpts, tags)
+				return
+			}
+			if tmpl != nil {
+				c.JSON(http.StatusOK, templatedTags(tmpl, tags))
 				return
 			}
 			c.JSON(http.StatusOK, tags)
 		})
 
+		registerExportJobRoutes(ctx, api, ctrl, cfg, exportJobs)
+		registerExportFileRoutes(api, ctrl, cfg)
+		registerGrafanaRoutes(api, ctrl)
+		registerEventRoutes(api, hub)
+
+		// Server-side aggregates (HistoryRead Processed) for a single node
+		api.GET("/history/aggregate", func(c *gin.Context) {
+			controllerCtx := hub.controller.GetClientContext()
+			if controllerCtx == nil || controllerCtx.Err() != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+				return
+			}
+			nodeID := strings.TrimSpace(c.Query("node_id"))
+			if nodeID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "node_id is required"})
+				return
+			}
+			aggregate := strings.TrimSpace(c.DefaultQuery("aggregate", "Average"))
+			end := time.Now()
+			if v := c.Query("end"); v != "" {
+				t, err := parseTimeParam(v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end: " + err.Error()})
+					return
+				}
+				end = t
+			}
+			start := end.Add(-1 * time.Hour)
+			if v := c.Query("start"); v != "" {
+				t, err := parseTimeParam(v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start: " + err.Error()})
+					return
+				}
+				start = t
+			}
+			interval := time.Minute
+			if v := c.Query("interval"); v != "" {
+				d, err := parseDurationParam(v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interval: " + err.Error()})
+					return
+				}
+				interval = d
+			}
+			values, err := ctrl.ReadHistoryAggregate(c.Request.Context(), nodeID, aggregate, start, end, interval)
+			truncated := errors.Is(err, opc.ErrHistoryTruncated)
+			if err != nil && !truncated {
+				status := http.StatusInternalServerError
+				if strings.Contains(err.Error(), "not connected") {
+					status = http.StatusServiceUnavailable
+				} else if strings.Contains(err.Error(), "unsupported aggregate") || strings.Contains(err.Error(), "must be") {
+					status = http.StatusBadRequest
+				}
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"node_id":   nodeID,
+				"aggregate": aggregate,
+				"start":     start.UTC().Format(time.RFC3339),
+				"end":       end.UTC().Format(time.RFC3339),
+				"interval":  interval.String(),
+				"values":    values,
+				"truncated": truncated,
+			})
+		})
+
+		// Historized events (HistoryRead Events) of a notifier, the Server object by default,
+		// optionally through a saved event filter
+		api.GET("/history/events", func(c *gin.Context) {
+			controllerCtx := hub.controller.GetClientContext()
+			if controllerCtx == nil || controllerCtx.Err() != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+				return
+			}
+			req := controller.EventHistoryRequest{
+				NodeID:    strings.TrimSpace(c.DefaultQuery("node_id", "i=2253")),
+				EventType: strings.TrimSpace(c.Query("event_type")),
+				End:       time.Now(),
+			}
+			if v := c.Query("end"); v != "" {
+				t, err := parseTimeParam(v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end: " + err.Error()})
+					return
+				}
+				req.End = t
+			}
+			req.Start = req.End.Add(-1 * time.Hour)
+			if v := c.Query("start"); v != "" {
+				t, err := parseTimeParam(v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start: " + err.Error()})
+					return
+				}
+				req.Start = t
+			}
+			if name := c.Query("filter"); name != "" {
+				preset, err := cfg.EventFilter(name)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				if req.EventType == "" {
+					req.EventType = preset.EventType
+				}
+				req.Fields, req.Where = preset.Fields, preset.Where
+			}
+			if v := c.Query("fields"); v != "" {
+				req.Fields = append(req.Fields, strings.Split(v, ",")...)
+			}
+			if v := c.Query("max"); v != "" {
+				n, err := strconv.ParseUint(v, 10, 32)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid max: " + err.Error()})
+					return
+				}
+				req.Max = uint32(n)
+			}
+			events, err := ctrl.ReadEventHistory(c.Request.Context(), req)
+			truncated := errors.Is(err, opc.ErrHistoryTruncated)
+			if err != nil && !truncated {
+				status := http.StatusInternalServerError
+				if strings.Contains(err.Error(), "not connected") {
+					status = http.StatusServiceUnavailable
+				} else if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must be") ||
+					strings.Contains(err.Error(), "unknown") || strings.Contains(err.Error(), "condition") {
+					status = http.StatusBadRequest
+				}
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"node_id":   req.NodeID,
+				"start":     req.Start.UTC().Format(time.RFC3339),
+				"end":       req.End.UTC().Format(time.RFC3339),
+				"events":    events,
+				"truncated": truncated,
+			})
+		})
+
+		// HistoryUpdate: insert/replace/update raw values. Destructive, so callers must set confirm=true.
+		api.POST("/history/update", func(c *gin.Context) {
+			controllerCtx := hub.controller.GetClientContext()
+			if controllerCtx == nil || controllerCtx.Err() != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+				return
+			}
+			if ctrl.IsOffline() {
+				c.JSON(http.StatusForbidden, gin.H{"error": "writes are disabled in offline mode"})
+				return
+			}
+
+			var req struct {
+				NodeID   string `json:"node_id" binding:"required"`
+				Mode     string `json:"mode" binding:"required"`
+				DataType string `json:"data_type"`
+				Confirm  bool   `json:"confirm"`
+				Values   []struct {
+					Timestamp string `json:"timestamp"`
+					Value     string `json:"value"`
+				} `json:"values" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if !req.Confirm {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "history update modifies archived data; set confirm=true to proceed"})
+				return
+			}
+			samples := make([]controller.HistorySample, 0, len(req.Values))
+			for i, v := range req.Values {
+				ts, err := parseTimeParam(v.Timestamp)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("values[%d].timestamp: %v", i, err)})
+					return
+				}
+				samples = append(samples, controller.HistorySample{Timestamp: ts, Value: v.Value})
+			}
+			if err := ctrl.UpdateHistory(c.Request.Context(), req.NodeID, req.Mode, req.DataType, samples); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, controller.ErrWriteDenied) {
+					status = http.StatusForbidden
+				} else if strings.Contains(err.Error(), "not connected") {
+					status = http.StatusServiceUnavailable
+				} else if strings.Contains(err.Error(), "unsupported") || strings.Contains(err.Error(), "invalid value") || strings.Contains(err.Error(), "requires") {
+					status = http.StatusBadRequest
+				}
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "Good", "updated": len(samples)})
+		})
+
+		// HistoryUpdate: delete raw values in a time range. Requires confirm=true.
+		api.POST("/history/delete", func(c *gin.Context) {
+			controllerCtx := hub.controller.GetClientContext()
+			if controllerCtx == nil || controllerCtx.Err() != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+				return
+			}
+			if ctrl.IsOffline() {
+				c.JSON(http.StatusForbidden, gin.H{"error": "writes are disabled in offline mode"})
+				return
+			}
+
+			var req struct {
+				NodeID  string `json:"node_id" binding:"required"`
+				Start   string `json:"start" binding:"required"`
+				End     string `json:"end" binding:"required"`
+				Confirm bool   `json:"confirm"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if !req.Confirm {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "history delete removes archived data; set confirm=true to proceed"})
+				return
+			}
+			start, err := parseTimeParam(req.Start)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start: " + err.Error()})
+				return
+			}
+			end, err := parseTimeParam(req.End)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end: " + err.Error()})
+				return
+			}
+			if err := ctrl.DeleteHistoryRaw(c.Request.Context(), req.NodeID, start, end); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, controller.ErrWriteDenied) {
+					status = http.StatusForbidden
+				} else if strings.Contains(err.Error(), "not connected") {
+					status = http.StatusServiceUnavailable
+				} else if strings.Contains(err.Error(), "must be") {
+					status = http.StatusBadRequest
+				}
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "Good"})
+		})
+
 		api.POST("/read", func(c *gin.Context) {
 			controllerCtx := hub.controller.GetClientContext()
 			if controllerCtx == nil || controllerCtx.Err() != nil {
@@ -302,7 +688,7 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
-			attrs, err := ctrl.ReadNodeAttributes(req.NodeID)
+			attrs, err := ctrl.ReadNodeAttributes(c.Request.Context(), req.NodeID)
 			if err != nil {
 				status := http.StatusInternalServerError
 				if strings.Contains(err.Error(), "not connected") {
@@ -311,6 +697,13 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 				c.JSON(status, gin.H{"error": err.Error()})
 				return
 			}
+			if includeMeta(c) {
+				c.JSON(http.StatusOK, struct {
+					*controller.NodeAttributes
+					Meta *controller.NodeMeta `json:"meta"`
+				}{attrs, ctrl.NodeMeta(c.Request.Context(), req.NodeID)})
+				return
+			}
 			c.JSON(http.StatusOK, attrs)
 		})
 
@@ -320,6 +713,10 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
 				return
 			}
+			if ctrl.IsOffline() {
+				c.JSON(http.StatusForbidden, gin.H{"error": "writes are disabled in offline mode"})
+				return
+			}
 
 			var req struct {
 				NodeID   string `json:"node_id" binding:"required"`
@@ -330,13 +727,132 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
-			ctrl.WriteValue(req.NodeID, req.DataType, req.Value)
-			c.JSON(http.StatusOK, gin.H{"status": "write request sent"})
+			if err := ctrl.CheckWriteAllowed(req.NodeID); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
+			// The write completes after the response, so it must not end with the request
+			ctrl.WriteValue(context.WithoutCancel(c.Request.Context()), req.NodeID, req.DataType, req.Value)
+			c.JSON(http.StatusOK, gin.H{"status": "write request sent", "request_id": requestID(c)})
+		})
+
+		// Retained alarms and conditions with the EventId to acknowledge them by.
+		api.GET("/alarms", func(c *gin.Context) {
+			conds, err := ctrl.Conditions(c.Request.Context())
+			if err != nil {
+				c.JSON(alarmErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"alarms": conds})
+		})
+
+		// Acknowledge, confirm or comment a condition: POST /alarms/acknowledge|confirm|comment.
+		api.POST("/alarms/:action", func(c *gin.Context) {
+			var req controller.ConditionRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if strings.TrimSpace(req.ConditionID) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "condition_id is required"})
+				return
+			}
+			if err := ctrl.ConditionAction(c.Request.Context(), c.Param("action"), req); err != nil {
+				c.JSON(alarmErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "Good", "request_id": requestID(c)})
+		})
+
+		// Server-side watch list (the list shown in the UI); listing works without a session.
+		api.GET("/watch", func(c *gin.Context) {
+			items := ctrl.WatchSnapshot()
+			if !includeMeta(c) {
+				c.JSON(http.StatusOK, items)
+				return
+			}
+			out := make([]interface{}, 0, len(items))
+			for _, it := range items {
+				out = append(out, withMeta(c.Request.Context(), ctrl, it, true))
+			}
+			c.JSON(http.StatusOK, out)
+		})
+
+		// Latest cached value of every watch item, for pollers that do not want a subscription.
+		// ?node_id= (repeatable) limits the result to those nodes; ?include=meta adds their metadata.
+		api.GET("/watch/values", func(c *gin.Context) {
+			c.JSON(http.StatusOK, watchValues(c.Request.Context(), ctrl, c.QueryArray("node_id"), includeMeta(c)))
+		})
+
+		api.POST("/watch", func(c *gin.Context) {
+			var req struct {
+				NodeID  string   `json:"node_id"`
+				NodeIDs []string `json:"node_ids"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ids := watchNodeIDs(req.NodeID, req.NodeIDs)
+			if len(ids) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "node_id or node_ids is required"})
+				return
+			}
+			if st := ctrl.ConnectionStatus(); !st.Connected && !st.Offline {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+				return
+			}
+			for _, id := range ids {
+				ctrl.AddWatch(c.Request.Context(), id)
+			}
+			c.JSON(http.StatusOK, ctrl.WatchSnapshot())
+		})
+
+		// Client code for a node: ?node_id=, optional ?data_type= and ?value= for the write
+		// example. Without data_type the node is read for its type and current value.
+		api.GET("/snippets", func(c *gin.Context) {
+			nodeID := strings.TrimSpace(c.Query("node_id"))
+			if nodeID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "node_id is required"})
+				return
+			}
+			dataType, value := c.Query("data_type"), c.Query("value")
+			if dataType == "" && ctrl.ConnectionStatus().Connected {
+				if attrs, err := ctrl.ReadNodeAttributes(c.Request.Context(), nodeID); err == nil && attrs != nil {
+					dataType = attrs.DataType
+					if value == "" {
+						value = attrs.Value
+					}
+				}
+			}
+			scheme := "http"
+			if c.Request.TLS != nil {
+				scheme = "https"
+			}
+			c.JSON(http.StatusOK, Snippets(scheme+"://"+c.Request.Host, nodeID, dataType, value))
+		})
+
+		// Remove watches by ?node_id= (repeatable); ?all=true clears the whole list.
+		api.DELETE("/watch", func(c *gin.Context) {
+			if isTruthy(c.Query("all")) {
+				ctrl.RemoveAllWatches()
+				c.JSON(http.StatusOK, ctrl.WatchSnapshot())
+				return
+			}
+			ids := watchNodeIDs("", c.QueryArray("node_id"))
+			if len(ids) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "node_id or all=true is required"})
+				return
+			}
+			for _, id := range ids {
+				ctrl.RemoveWatch(id)
+			}
+			c.JSON(http.StatusOK, ctrl.WatchSnapshot())
 		})
 	}
 
 	// WebSocket endpoint
-	router.GET("/ws/subscribe", func(c *gin.Context) {
+	router.GET("/ws/subscribe", dashboard.requireAPI(), func(c *gin.Context) {
 		controllerCtx := hub.controller.GetClientContext()
 		if controllerCtx == nil || controllerCtx.Err() != nil {
 			// controllerCtx is nil (never connected) or its .Done() channel is closed (disconnected).
@@ -350,20 +866,26 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 			}
 			return
 		}
-		client := &Client{
-			hub:           hub,
-			conn:          conn,
-			send:          make(chan *controller.WatchItem, 256),
-			subscriptions: make(map[string]bool),
-		}
+		client := hub.newClient(conn)
+		client.meta = includeMeta(c)
 		client.hub.register <- client
 
 		go client.writePump()
 		go client.readPump()
 	})
 
+	// Server-Sent Events alternative to /ws/subscribe for networks whose proxies block WebSocket
+	router.GET("/api/v1/stream", dashboard.requireAPI(), func(c *gin.Context) {
+		serveStream(hub, c)
+	})
+
+	// JSON-RPC 2.0 remote control, available with or without an OPC UA session
+	router.GET("/ws/rpc", dashboard.requireAPI(), func(c *gin.Context) {
+		serveRPC(hub, c, cfg, dashboard)
+	})
+
 	// Documentation and client info
-	router.GET("/", func(c *gin.Context) {
+	router.GET("/", dashboard.require(), func(c *gin.Context) {
 		data, err := webTemplate.ReadFile("templates/index.html")
 		if err != nil {
 			c.String(http.StatusInternalServerError, "Error reading index page")
@@ -372,7 +894,7 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
 	})
 
-	router.GET("/doc", func(c *gin.Context) {
+	router.GET("/doc", dashboard.require(), func(c *gin.Context) {
 		data, err := webTemplate.ReadFile("templates/doc.html")
 		if err != nil {
 			c.String(http.StatusInternalServerError, "Error reading documentation")
@@ -381,13 +903,31 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
 	})
 
-	router.GET("/api/v1/ws/clients", func(c *gin.Context) {
+	router.GET("/snippets", dashboard.require(), func(c *gin.Context) {
+		data, err := webTemplate.ReadFile("templates/snippets.html")
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Error reading snippets page")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	})
+
+	// Prometheus scrape endpoint: connection state and per-service request statistics
+	router.GET("/metrics", dashboard.requireAPI(), func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		writeMetrics(c.Writer, ctrl)
+	})
+
+	router.GET("/api/v1/ws/clients", dashboard.requireAPI(), func(c *gin.Context) {
 		hub.mu.Lock()
 		defer hub.mu.Unlock()
 
 		type clientInfo struct {
 			RemoteAddr    string   `json:"remote_addr"`
+			Transport     string   `json:"transport"` // "websocket" or "sse"
 			Subscriptions []string `json:"subscriptions"`
+			Coalesced     uint64   `json:"coalesced"` // updates replaced by a newer value before delivery
 		}
 		var clientsData []clientInfo
 
@@ -397,9 +937,15 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 			for sub := range client.subscriptions {
 				subs = append(subs, sub)
 			}
+			transport := "websocket"
+			if client.conn == nil {
+				transport = "sse"
+			}
 			clientsData = append(clientsData, clientInfo{
-				RemoteAddr:    client.conn.RemoteAddr().String(),
+				RemoteAddr:    client.remote,
+				Transport:     transport,
 				Subscriptions: subs,
+				Coalesced:     client.updates.Superseded(),
 			})
 			client.mu.RUnlock()
 		}
@@ -415,6 +961,8 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 		Handler: router,
 	}
 
+	startPublicDashboard(ctx, ctrl, cfg)
+
 	go func() {
 		*apiStatus = "Running on:" + port
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -440,3 +988,123 @@ func StartServer(ctx context.Context, ctrl controller.NodeManager, apiStatus *st
 
 	return srv
 }
+
+// parseTimeParam accepts RFC3339 timestamps or Unix seconds.
+func parseTimeParam(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return t, nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 or unix seconds, got %q", v)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// parseDurationParam accepts Go durations ("30s", "5m") or plain seconds.
+func parseDurationParam(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if d, err := time.ParseDuration(v); err == nil {
+		return d, nil
+	}
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected duration like 60s or seconds, got %q", v)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// isTruthy reports whether a query flag is set ("1", "true", "yes").
+func isTruthy(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// alarmErrorStatus maps errors of the alarm endpoints to HTTP status codes.
+func alarmErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, controller.ErrWriteDenied), strings.Contains(msg, "offline mode"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "not connected"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "unknown condition action"), strings.Contains(msg, "invalid condition_id"),
+		strings.Contains(msg, "is required"), strings.Contains(msg, "no event of"):
+		return http.StatusBadRequest
+	}
+	return http.StatusBadGateway
+}
+
+// watchValue is one entry of GET /api/v1/watch/values.
+type watchValue struct {
+	NodeID          string               `json:"node_id"`
+	Name            string               `json:"name"`
+	DataType        string               `json:"data_type"`
+	Value           string               `json:"value"`
+	ValueTyped      interface{}          `json:"value_typed"`
+	UAType          string               `json:"ua_type"`
+	Status          string               `json:"status"`      // Good, Uncertain or Bad
+	StatusCode      string               `json:"status_code"` // symbolic StatusCode name
+	SourceTimestamp string               `json:"source_timestamp"`
+	ServerTimestamp string               `json:"server_timestamp"`
+	Forced          bool                 `json:"forced"`         // value is forced locally, not the server's
+	OverflowCount   uint64               `json:"overflow_count"` // notifications whose queue overflowed
+	Polled          bool                 `json:"polled"`         // value is polled, not monitored
+	RateMs          float64              `json:"rate_ms"`        // requested sampling interval, 0 for the default
+	Meta            *controller.NodeMeta `json:"meta,omitempty"` // with ?include=meta
+}
+
+// watchValues is the GET /api/v1/watch/values response: the latest cached value of every watch
+// item, or of nodeIDs when given, with their metadata when meta is set.
+func watchValues(ctx context.Context, ctrl controller.NodeManager, nodeIDs []string, meta bool) gin.H {
+	filter := make(map[string]bool)
+	for _, id := range watchNodeIDs("", nodeIDs) {
+		filter[id] = true
+	}
+	items := ctrl.WatchSnapshot()
+	values := make([]watchValue, 0, len(items))
+	for _, it := range items {
+		if len(filter) > 0 && !filter[it.NodeID] {
+			continue
+		}
+		values = append(values, watchValue{
+			NodeID:          it.NodeID,
+			Name:            it.Name,
+			DataType:        it.DataType,
+			Value:           it.Value,
+			ValueTyped:      it.ValueTyped,
+			UAType:          it.UAType,
+			Status:          it.Severity,
+			StatusCode:      it.SymbolicName,
+			SourceTimestamp: it.SourceTimestamp,
+			ServerTimestamp: it.ServerTimestamp,
+			Forced:          it.Forced,
+			OverflowCount:   it.OverflowCount,
+			Polled:          it.Polled,
+			RateMs:          it.RateMs,
+		})
+		if meta {
+			values[len(values)-1].Meta = ctrl.NodeMeta(ctx, it.NodeID)
+		}
+	}
+	return gin.H{
+		"connected": ctrl.ConnectionStatus().Connected,
+		"time":      time.Now().UTC().Format(time.RFC3339Nano),
+		"values":    values,
+	}
+}
+
+// watchNodeIDs merges a single node_id and a node_ids list, dropping blanks.
+func watchNodeIDs(one string, many []string) []string {
+	var ids []string
+	for _, id := range append([]string{one}, many...) {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
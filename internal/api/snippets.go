@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Snippet is client code for one language and operation, generated for a node.
+type Snippet struct {
+	Language  string `json:"language"`  // "python", "nodejs" or "go"
+	Operation string `json:"operation"` // "read", "write" or "subscribe"
+	Code      string `json:"code"`
+}
+
+// SnippetLanguages and SnippetOperations list what Snippets generates, in order.
+var (
+	SnippetLanguages  = []string{"python", "nodejs", "go"}
+	SnippetOperations = []string{"read", "write", "subscribe"}
+)
+
+// Snippets returns code reading, writing and subscribing to nodeID through the REST and
+// WebSocket API at baseURL (e.g. "http://localhost:8080"). dataType and value prefill the write
+// request; value is the text form POST /api/v1/write takes.
+func Snippets(baseURL, nodeID, dataType, value string) []Snippet {
+	baseURL = strings.TrimRight(baseURL, "/")
+	wsURL := "ws" + strings.TrimPrefix(baseURL, "http")
+	if dataType == "" {
+		dataType = "String"
+	}
+	var out []Snippet
+	for _, lang := range SnippetLanguages {
+		quote := jsonQuote
+		if lang == "go" {
+			quote = strconv.Quote
+		}
+		r := strings.NewReplacer(
+			"{URL}", quote(baseURL),
+			"{WS_URL}", quote(wsURL+"/ws/subscribe"),
+			"{NODE}", quote(nodeID),
+			"{TYPE}", quote(dataType),
+			"{VALUE}", quote(value),
+		)
+		for _, op := range SnippetOperations {
+			out = append(out, Snippet{Language: lang, Operation: op, Code: r.Replace(snippetTemplates[lang+"/"+op])})
+		}
+	}
+	return out
+}
+
+// jsonQuote quotes s as a JSON string, which Python and JavaScript accept as a literal.
+func jsonQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+var snippetTemplates = map[string]string{
+	"python/read": `import requests
+
+BASE_URL = {URL}
+NODE_ID = {NODE}
+
+resp = requests.post(BASE_URL + "/api/v1/read", json={"node_id": NODE_ID}, timeout=10)
+resp.raise_for_status()
+attrs = resp.json()
+print(attrs["value_typed"], attrs["DataType"], attrs["source_timestamp"])
+`,
+	"python/write": `import requests
+
+BASE_URL = {URL}
+NODE_ID = {NODE}
+
+payload = {"node_id": NODE_ID, "data_type": {TYPE}, "value": {VALUE}}
+resp = requests.post(BASE_URL + "/api/v1/write", json=payload, timeout=10)
+resp.raise_for_status()
+print(resp.json())
+`,
+	"python/subscribe": `# pip install websocket-client
+import json
+import websocket
+
+NODE_ID = {NODE}
+
+ws = websocket.create_connection({WS_URL})
+ws.send(json.dumps({"action": "subscribe", "node_ids": [NODE_ID]}))
+try:
+    while True:
+        item = json.loads(ws.recv())
+        print(item["NodeID"], item["value_typed"], item["source_timestamp"])
+finally:
+    ws.close()
+`,
+	"nodejs/read": `// Node.js 18+ (built-in fetch)
+const BASE_URL = {URL};
+const NODE_ID = {NODE};
+
+const resp = await fetch(BASE_URL + "/api/v1/read", {
+  method: "POST",
+  headers: { "Content-Type": "application/json" },
+  body: JSON.stringify({ node_id: NODE_ID }),
+});
+if (!resp.ok) throw new Error(await resp.text());
+const attrs = await resp.json();
+console.log(attrs.value_typed, attrs.DataType, attrs.source_timestamp);
+`,
+	"nodejs/write": `// Node.js 18+ (built-in fetch)
+const BASE_URL = {URL};
+const NODE_ID = {NODE};
+
+const resp = await fetch(BASE_URL + "/api/v1/write", {
+  method: "POST",
+  headers: { "Content-Type": "application/json" },
+  body: JSON.stringify({ node_id: NODE_ID, data_type: {TYPE}, value: {VALUE} }),
+});
+if (!resp.ok) throw new Error(await resp.text());
+console.log(await resp.json());
+`,
+	"nodejs/subscribe": `// npm install ws
+import WebSocket from "ws";
+
+const NODE_ID = {NODE};
+
+const ws = new WebSocket({WS_URL});
+ws.on("open", () => {
+  ws.send(JSON.stringify({ action: "subscribe", node_ids: [NODE_ID] }));
+});
+ws.on("message", (data) => {
+  const item = JSON.parse(data);
+  console.log(item.NodeID, item.value_typed, item.source_timestamp);
+});
+`,
+	"go/read": `package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	body, _ := json.Marshal(map[string]string{"node_id": {NODE}})
+	resp, err := http.Post({URL}+"/api/v1/read", "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var attrs struct {
+		DataType        string
+		SourceTimestamp string ` + "`json:\"source_timestamp\"`" + `
+		Value           any    ` + "`json:\"value_typed\"`" + `
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&attrs); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(attrs.Value, attrs.DataType, attrs.SourceTimestamp)
+}
+`,
+	"go/write": `package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+func main() {
+	body, _ := json.Marshal(map[string]string{
+		"node_id":   {NODE},
+		"data_type": {TYPE},
+		"value":     {VALUE},
+	})
+	resp, err := http.Post({URL}+"/api/v1/write", "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(resp.Body)
+	fmt.Println(resp.Status, string(reply))
+}
+`,
+	"go/subscribe": `package main
+
+// go get github.com/gorilla/websocket
+import (
+	"fmt"
+	"log"
+
+	"github.com/gorilla/websocket"
+)
+
+func main() {
+	conn, _, err := websocket.DefaultDialer.Dial({WS_URL}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	sub := map[string]any{"action": "subscribe", "node_ids": []string{{NODE}}}
+	if err := conn.WriteJSON(sub); err != nil {
+		log.Fatal(err)
+	}
+	for {
+		var item struct {
+			NodeID          string
+			SourceTimestamp string ` + "`json:\"source_timestamp\"`" + `
+			Value           any    ` + "`json:\"value_typed\"`" + `
+		}
+		if err := conn.ReadJSON(&item); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(item.NodeID, item.Value, item.SourceTimestamp)
+	}
+}
+`,
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveStream serves GET /api/v1/stream: the data changes of ?node_ids= (comma-separated or
+// repeated, also as node_id) or, with ?all=true, of every watch item as Server-Sent Events. The
+// stream is a hub client like a /ws/subscribe connection, so nodes are added to the watch list,
+// start with their current value, are re-subscribed after reconnects and coalesce the same way.
+// ?include=meta adds the node's metadata to every event.
+func serveStream(hub *Hub, c *gin.Context) {
+	controllerCtx := hub.controller.GetClientContext()
+	if controllerCtx == nil || controllerCtx.Err() != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OPC UA connection is not active"})
+		return
+	}
+	var nodeIDs []string
+	for _, v := range append(c.QueryArray("node_ids"), c.QueryArray("node_id")...) {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				nodeIDs = append(nodeIDs, id)
+			}
+		}
+	}
+	all := isTruthy(c.Query("all"))
+	if len(nodeIDs) == 0 && !all {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "node_ids or all=true is required"})
+		return
+	}
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "streaming is not supported"})
+		return
+	}
+
+	client := hub.newStreamClient(c.Request.RemoteAddr)
+	client.mu.Lock()
+	client.subscribeAll = all
+	client.meta = includeMeta(c)
+	client.subscribeLocked(nodeIDs)
+	client.mu.Unlock()
+	select {
+	case hub.register <- client:
+	case <-hub.stop:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API server stopped"})
+		return
+	}
+	defer func() {
+		client.cancel()
+		select {
+		case hub.unregister <- client:
+		case <-hub.stop:
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case ev, ok := <-client.send:
+			if !ok {
+				// Dropped by the hub or the server is stopping
+				return
+			}
+			if writeStreamEvent(w, streamEvent{Event: "connection", Data: ev}) != nil {
+				return
+			}
+		case <-client.updates.Ready():
+			for _, item := range client.updates.Drain() {
+				if writeStreamEvent(w, streamEvent{Event: "watch", Data: withMeta(client.ctx, hub.controller, item, client.meta)}) != nil {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
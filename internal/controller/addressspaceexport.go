@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"opcuababy/internal/exporter"
+	"opcuababy/internal/opc"
+)
+
+// AddressSpaceFormats are the file formats of address space exports.
+var AddressSpaceFormats = []string{"json", "json-stream", "csv", "xlsx"}
+
+// AddressSpaceExport describes an address space export to a file.
+type AddressSpaceExport struct {
+	RootID   string         // node to export from; "" is the RootFolder (i=84)
+	Format   string         // one of AddressSpaceFormats
+	Template string         // export template for csv and xlsx; "" is the standard layout
+	CSV      *opc.CSVFormat // delimiter, BOM and quoting of csv files; nil uses the config
+	FilePath string
+}
+
+// ExportAddressSpace browses the address space below e.RootID and writes it to e.FilePath with
+// the configured locale, attribute selection and read batch size. ctx bounds the traversal;
+// without a deadline the configured export timeout applies.
+func (c *Controller) ExportAddressSpace(ctx context.Context, e AddressSpaceExport) error {
+	client := c.GetClientForExport()
+	if client == nil {
+		return errors.New("not connected to an OPC UA server")
+	}
+	c.mu.RLock()
+	cfg := c.currentConfig
+	c.mu.RUnlock()
+	tmpl, err := cfg.ExportTemplate(e.Template)
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeouts().Export)
+		defer cancel()
+	}
+	rootID := e.RootID
+	if rootID == "" {
+		rootID = "i=84"
+	}
+
+	log := c.logFor(ctx)
+	x := exporter.New(client)
+	x.SetLogger(log)
+	x.SetTimeouts(cfg.Timeouts())
+	x.SetLocale(cfg.DisplayLocale())
+	x.SetTemplate(tmpl)
+	if e.CSV != nil {
+		x.SetCSVFormat(*e.CSV)
+	} else {
+		x.SetCSVFormat(cfg.CSVFormat())
+	}
+	if cfg != nil {
+		x.SkipAttributes(cfg.ExportSkipAttributes)
+		x.SetReadBatch(cfg.ExportReadBatch)
+	}
+	switch e.Format {
+	case "json":
+		err = x.ExportToJSON(ctx, rootID, e.FilePath)
+	case "json-stream":
+		err = x.ExportToJSONStream(ctx, rootID, e.FilePath)
+	case "csv":
+		err = x.ExportToCSV(ctx, rootID, e.FilePath)
+	case "xlsx":
+		err = x.ExportToExcel(ctx, rootID, e.FilePath)
+	default:
+		return fmt.Errorf("unknown address space export format %q", e.Format)
+	}
+	if err != nil {
+		log(fmt.Sprintf("[red]Export failed: %v[-]", err))
+		return err
+	}
+	c.usageExported()
+	log(fmt.Sprintf("[green]Successfully exported from %s to %s[-]", rootID, e.FilePath))
+	return nil
+}
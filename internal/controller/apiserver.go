@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// ConnectionStatus is the OPC UA session state reported by GET /api/v1/status.
+type ConnectionStatus struct {
+	Connected  bool                `json:"connected"`
+	Connecting bool                `json:"connecting"`
+	Offline    bool                `json:"offline"`
+	Endpoint   string              `json:"endpoint,omitempty"`
+	Watches    int                 `json:"watches"`
+	Server     *opc.ServerIdentity `json:"server,omitempty"`
+	Clock      *opc.ClockOffset    `json:"clock,omitempty"`
+	ApiPort    string              `json:"api_port"`
+	KeepApi    bool                `json:"keep_api_running"`
+	Time       string              `json:"time"`
+}
+
+// ConnectionStatus returns the current session state; it never touches the server.
+func (c *Controller) ConnectionStatus() ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	st := ConnectionStatus{
+		Connected:  c.isConnected,
+		Connecting: c.isConnecting,
+		Offline:    c.offline != nil,
+		Watches:    len(c.watchItems),
+		Server:     c.serverIdentity,
+		Clock:      c.clockOffset,
+		Time:       c.formatISOTimestamp(c.currentConfig, time.Now()),
+	}
+	if cfg := c.currentConfig; cfg != nil {
+		st.ApiPort = apiPortOf(cfg)
+		st.KeepApi = cfg.KeepApiRunning
+		if st.Connected && !st.Offline {
+			st.Endpoint = cfg.EndpointURL
+		}
+	}
+	return st
+}
+
+func apiPortOf(cfg *opc.Config) string {
+	if cfg.ApiPort == "" {
+		return "8080"
+	}
+	return cfg.ApiPort
+}
+
+// syncApiServer starts or stops the API server to match the config and connection state.
+// With KeepApiRunning the server runs whenever the API is enabled; otherwise only while a
+// session (live or offline) is open. A running server is only restarted when its port or the
+// public dashboard setting changed.
+func (c *Controller) syncApiServer() {
+	c.mu.RLock()
+	cfg := c.currentConfig
+	connected := c.isConnected
+	c.mu.RUnlock()
+
+	c.apiMu.Lock()
+	defer c.apiMu.Unlock()
+	if c.apiStarter == nil || c.apiStatus == nil {
+		return
+	}
+	if cfg == nil || !cfg.ApiEnabled {
+		c.stopApiServerLocked()
+		*c.apiStatus = "API Disabled"
+		return
+	}
+	if !cfg.KeepApiRunning && !connected {
+		if c.apiServer != nil {
+			c.stopApiServerLocked()
+			c.Log("[yellow]API server stopped: OPC UA session closed[-]")
+		}
+		*c.apiStatus = "API Server Stopped"
+		return
+	}
+	port := apiPortOf(cfg)
+	if public := cfg.PublicDashboardAddr(); public != "" {
+		port += " public" + public
+	}
+	if c.apiServer != nil && c.apiPort == port {
+		return
+	}
+	c.stopApiServerLocked()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.apiServerCtx = ctx
+	c.apiServerCancel = cancel
+	c.apiPort = port
+	c.apiServer = c.apiStarter(ctx, c, c.apiStatus, cfg)
+}
+
+// stopApiServerLocked cancels the running API server. Callers must hold c.apiMu.
+func (c *Controller) stopApiServerLocked() {
+	if c.apiServerCancel != nil {
+		c.apiServerCancel()
+	}
+	c.apiServer = nil
+	c.apiServerCtx = nil
+	c.apiServerCancel = nil
+	c.apiPort = ""
+}
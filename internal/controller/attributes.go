@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// editableAttribute describes a non-Value attribute that can be edited from the
+// advanced attributes editor, together with the built-in type it is encoded as and
+// the WriteMask bit that permits writing it.
+type editableAttribute struct {
+	Name     string
+	ID       ua.AttributeID
+	DataType string
+	Mask     ua.AttributeWriteMask
+}
+
+// editableAttributes lists the attributes offered by the attributes editor in display order.
+// Whether a write succeeds is decided by the server through the node's (User)WriteMask.
+var editableAttributes = []editableAttribute{
+	{"DisplayName", ua.AttributeIDDisplayName, "LocalizedText", ua.AttributeWriteMaskDisplayName},
+	{"Description", ua.AttributeIDDescription, "LocalizedText", ua.AttributeWriteMaskDescription},
+	{"BrowseName", ua.AttributeIDBrowseName, "QualifiedName", ua.AttributeWriteMaskBrowseName},
+	{"WriteMask", ua.AttributeIDWriteMask, "UInt32", ua.AttributeWriteMaskWriteMask},
+	{"AccessLevel", ua.AttributeIDAccessLevel, "Byte", ua.AttributeWriteMaskAccessLevel},
+	{"UserAccessLevel", ua.AttributeIDUserAccessLevel, "Byte", ua.AttributeWriteMaskUserAccessLevel},
+	{"MinimumSamplingInterval", ua.AttributeIDMinimumSamplingInterval, "Double", ua.AttributeWriteMaskMinimumSamplingInterval},
+	{"Historizing", ua.AttributeIDHistorizing, "Boolean", ua.AttributeWriteMaskHistorizing},
+	{"EventNotifier", ua.AttributeIDEventNotifier, "Byte", ua.AttributeWriteMaskEventNotifier},
+	{"IsAbstract", ua.AttributeIDIsAbstract, "Boolean", ua.AttributeWriteMaskIsAbstract},
+	{"Symmetric", ua.AttributeIDSymmetric, "Boolean", ua.AttributeWriteMaskSymmetric},
+	{"InverseName", ua.AttributeIDInverseName, "LocalizedText", ua.AttributeWriteMaskInverseName},
+	{"ContainsNoLoops", ua.AttributeIDContainsNoLoops, "Boolean", ua.AttributeWriteMaskContainsNoLoops},
+	{"Executable", ua.AttributeIDExecutable, "Boolean", ua.AttributeWriteMaskExecutable},
+	{"UserExecutable", ua.AttributeIDUserExecutable, "Boolean", ua.AttributeWriteMaskUserExecutable},
+}
+
+// WriteMasks holds the WriteMask and UserWriteMask attributes of a node, which tell which of
+// its attributes other than Value may be written, in general and by the current user.
+type WriteMasks struct {
+	WriteMask     uint32 `json:"write_mask"`
+	UserWriteMask uint32 `json:"user_write_mask"`
+	Known         bool   `json:"known"`      // WriteMask was read
+	UserKnown     bool   `json:"user_known"` // UserWriteMask was read
+}
+
+// CanWrite reports whether the masks permit writing attribute, going by UserWriteMask when
+// the server returned it. Masks the server did not return permit everything, leaving the
+// decision to the server.
+func (m WriteMasks) CanWrite(attribute string) bool {
+	attr, err := lookupEditableAttribute(attribute)
+	if err != nil {
+		return false
+	}
+	switch {
+	case m.UserKnown:
+		return m.UserWriteMask&uint32(attr.Mask) != 0
+	case m.Known:
+		return m.WriteMask&uint32(attr.Mask) != 0
+	}
+	return true
+}
+
+// Writable returns the editable attributes the masks permit writing, in display order.
+func (m WriteMasks) Writable() []string {
+	var names []string
+	for _, a := range editableAttributes {
+		if m.CanWrite(a.Name) {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// setWriteMask stores the WriteMask or UserWriteMask read in v.
+func (m *WriteMasks) setWriteMask(attrID ua.AttributeID, v *ua.Variant) {
+	var mask uint32
+	switch x := v.Value().(type) {
+	case uint32:
+		mask = x
+	case int32:
+		mask = uint32(x)
+	case ua.AttributeWriteMask:
+		mask = uint32(x)
+	default:
+		return
+	}
+	if attrID == ua.AttributeIDUserWriteMask {
+		m.UserWriteMask, m.UserKnown = mask, true
+	} else {
+		m.WriteMask, m.Known = mask, true
+	}
+}
+
+// ReadWriteMasks reads the WriteMask and UserWriteMask of a node. Both are optional; masks the
+// server does not return are left unknown rather than failing the read.
+func (c *Controller) ReadWriteMasks(nodeID string) (WriteMasks, error) {
+	var m WriteMasks
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return m, errors.New("not connected")
+	}
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	defer cancel()
+	ids := []ua.AttributeID{ua.AttributeIDWriteMask, ua.AttributeIDUserWriteMask}
+	results, err := client.ReadAttributes(ctx, nodeID, ids...)
+	if err != nil {
+		return m, err
+	}
+	for i, res := range results {
+		if i < len(ids) && res != nil && res.Status == ua.StatusOK && res.Value != nil {
+			m.setWriteMask(ids[i], res.Value)
+		}
+	}
+	return m, nil
+}
+
+// EditableAttributeNames returns the attribute names accepted by ReadAttribute/WriteAttribute.
+func EditableAttributeNames() []string {
+	names := make([]string, 0, len(editableAttributes))
+	for _, a := range editableAttributes {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func lookupEditableAttribute(name string) (editableAttribute, error) {
+	for _, a := range editableAttributes {
+		if strings.EqualFold(a.Name, strings.TrimSpace(name)) {
+			return a, nil
+		}
+	}
+	return editableAttribute{}, fmt.Errorf("unsupported attribute '%s'", name)
+}
+
+// ReadAttribute reads a single editable attribute and returns it formatted for display.
+func (c *Controller) ReadAttribute(nodeID, attribute string) (string, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return "", errors.New("not connected")
+	}
+	attr, err := lookupEditableAttribute(attribute)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	defer cancel()
+	results, err := client.ReadAttributes(ctx, nodeID, attr.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 || results[0] == nil {
+		return "", errors.New("empty read response")
+	}
+	if results[0].Status != ua.StatusOK {
+		return "", fmt.Errorf("read %s failed with status: %s", attr.Name, results[0].Status)
+	}
+	if results[0].Value == nil {
+		return "", nil
+	}
+	switch v := results[0].Value.Value().(type) {
+	case *ua.LocalizedText:
+		if v == nil {
+			return "", nil
+		}
+		return formatLocalizedText(v.Locale, v.Text), nil
+	case *ua.QualifiedName:
+		if v == nil {
+			return "", nil
+		}
+		return fmt.Sprintf("%d:%s", v.NamespaceIndex, v.Name), nil
+	}
+	return formatValue(results[0].Value, attr.DataType), nil
+}
+
+// WriteAttribute writes a non-Value attribute of a node. LocalizedText values accept
+// "text" or "locale|text" (see splitLocalizedText); BrowseName accepts "name" or "nsIndex:name".
+func (c *Controller) WriteAttribute(nodeID, attribute, valueStr string) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+	if err := c.CheckWriteAllowed(nodeID); err != nil {
+		return err
+	}
+	attr, err := lookupEditableAttribute(attribute)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	switch attr.DataType {
+	case "LocalizedText":
+		locale, text := splitLocalizedText(valueStr)
+		value = ua.NewLocalizedTextWithLocale(text, locale)
+	case "QualifiedName":
+		value, err = parseQualifiedName(valueStr)
+	default:
+		value, err = convertStringToType(strings.TrimSpace(valueStr), attr.DataType)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value '%s' for %s: %w", valueStr, attr.Name, err)
+	}
+
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Write)
+	defer cancel()
+	if err := client.WriteAttribute(ctx, nodeID, attr.ID, value); err != nil {
+		c.usageWrites(0, 1)
+		c.Log(fmt.Sprintf("[red]Write %s failed for %s: %v[-]", attr.Name, nodeID, err))
+		return err
+	}
+	c.usageWrites(1, 0)
+	c.Log(fmt.Sprintf("[green]Wrote %s of %s: %s[-]", attr.Name, nodeID, valueStr))
+	return nil
+}
+
+func parseQualifiedName(s string) (*ua.QualifiedName, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("name is required")
+	}
+	if i := strings.Index(s, ":"); i > 0 {
+		if ns, err := strconv.ParseUint(s[:i], 10, 16); err == nil {
+			return &ua.QualifiedName{NamespaceIndex: uint16(ns), Name: s[i+1:]}, nil
+		}
+	}
+	return &ua.QualifiedName{Name: s}, nil
+}
+
+// localePattern matches the locale ids of LocalizedText values, e.g. "en", "zh-CN" or "de-DE-1996".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// splitLocalizedText parses "locale|text" or "text". The part before the first '|' is only taken
+// as the locale when it looks like one, so "A|B" stays the text "A|B"; a leading '|' ("|en|x")
+// marks a text without locale that would otherwise be read as one.
+func splitLocalizedText(s string) (locale, text string) {
+	prefix, rest, ok := strings.Cut(s, "|")
+	switch {
+	case !ok:
+		return "", s
+	case prefix == "":
+		return "", rest
+	case localePattern.MatchString(strings.TrimSpace(prefix)):
+		return strings.TrimSpace(prefix), strings.TrimSpace(rest)
+	}
+	return "", s
+}
+
+// formatLocalizedText is the inverse of splitLocalizedText.
+func formatLocalizedText(locale, text string) string {
+	if locale != "" {
+		return locale + "|" + text
+	}
+	if l, _ := splitLocalizedText(text); l != "" || strings.HasPrefix(text, "|") {
+		return "|" + text
+	}
+	return text
+}
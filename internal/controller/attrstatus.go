@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// attributeDisplayNames maps the optional attributes read by ReadNodeAttributes to the keys
+// used in NodeAttributes.AttributeStatus. AccessLevel is handled separately because it is
+// merged from AccessLevel and UserAccessLevel.
+var attributeDisplayNames = map[ua.AttributeID]string{
+	ua.AttributeIDNodeClass:       "NodeClass",
+	ua.AttributeIDDisplayName:     "DisplayName",
+	ua.AttributeIDBrowseName:      "BrowseName",
+	ua.AttributeIDDescription:     "Description",
+	ua.AttributeIDDataType:        "DataType",
+	ua.AttributeIDValue:           "Value",
+	ua.AttributeIDValueRank:       "ValueRank",
+	ua.AttributeIDArrayDimensions: "ArrayDimensions",
+}
+
+// variableOnlyAttributes are only defined for Variable and VariableType nodes; servers
+// rightly reject them on other node classes, so such failures are not reported.
+var variableOnlyAttributes = []string{"Value", "DataType", "ValueRank", "ArrayDimensions", "AccessLevel"}
+
+// statusName returns the symbolic name of sc without the "Status" prefix, e.g. "BadAttributeIDInvalid".
+func statusName(sc ua.StatusCode) string {
+	if d, ok := ua.StatusCodes[sc]; ok && d.Name != "" {
+		return strings.TrimPrefix(d.Name, "Status")
+	}
+	return fmt.Sprintf("0x%08X", uint32(sc))
+}
+
+// UnavailableText renders an attribute that could not be read, e.g. "n/a (BadAttributeIDInvalid)".
+func UnavailableText(status string) string {
+	return fmt.Sprintf("n/a (%s)", status)
+}
+
+func (a *NodeAttributes) setAttributeStatus(name string, sc ua.StatusCode) {
+	if a.AttributeStatus == nil {
+		a.AttributeStatus = make(map[string]string)
+	}
+	a.AttributeStatus[name] = statusName(sc)
+}
+
+// resolveAccessLevelStatus sets AccessLevelKnown from the read status of AccessLevel and
+// UserAccessLevel. A level that was read successfully but grants nothing is reported as "None"
+// rather than left blank, so that it can be told apart from a level the server did not return.
+func (a *NodeAttributes) resolveAccessLevelStatus(failed map[ua.AttributeID]ua.StatusCode) {
+	_, levelFailed := failed[ua.AttributeIDAccessLevel]
+	_, userFailed := failed[ua.AttributeIDUserAccessLevel]
+	a.AccessLevelKnown = !levelFailed || !userFailed
+	if !a.AccessLevelKnown {
+		a.setAttributeStatus("AccessLevel", failed[ua.AttributeIDAccessLevel])
+		return
+	}
+	if a.AccessLevel == "" {
+		a.AccessLevel = formatAccessLevel(0)
+	}
+}
+
+// pruneAttributeStatus drops statuses of attributes that do not apply to the node's class.
+func (a *NodeAttributes) pruneAttributeStatus() {
+	if a.NodeClass == "NodeClassVariable" || a.NodeClass == "NodeClassVariableType" || a.NodeClass == "" {
+		return
+	}
+	for _, name := range variableOnlyAttributes {
+		delete(a.AttributeStatus, name)
+	}
+	if len(a.AttributeStatus) == 0 {
+		a.AttributeStatus = nil
+	}
+}
+
+// strictAccessLevel reports whether writes to nodes with an unknown AccessLevel are refused.
+func (c *Controller) strictAccessLevel() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentConfig != nil && c.currentConfig.StrictAccessLevel
+}
@@ -0,0 +1,493 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// Bulk write row states
+const (
+	BulkWriteValid   = "valid"
+	BulkWriteInvalid = "invalid"
+	BulkWriteOK      = "ok"
+	BulkWriteFailed  = "failed"
+	// Transactional bulk writes only (see ExecuteBulkWriteTransaction)
+	BulkWriteSkipped    = "skipped"     // not written because the batch stopped earlier
+	BulkWriteRolledBack = "rolled back" // written, then restored to Previous
+)
+
+// DefaultBulkWriteChunkSize is the number of values sent per Write request.
+const DefaultBulkWriteChunkSize = 50
+
+// BulkWriteRow is one planned write read from a CSV file, together with its validation or
+// execution result.
+type BulkWriteRow struct {
+	Line     int    `json:"line"`
+	NodeID   string `json:"node_id"`
+	DataType string `json:"data_type"`
+	Value    string `json:"value"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Previous string `json:"previous,omitempty"` // value before a transactional write
+
+	value interface{}
+	prev  interface{} // Previous as read, written back on rollback
+}
+
+// ParseBulkWriteCSV reads NodeID,DataType,Value rows. A header row is detected and skipped,
+// DataType may be empty (the server-reported type is used) and lines starting with # are ignored.
+func ParseBulkWriteCSV(r io.Reader) ([]*BulkWriteRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+
+	var rows []*BulkWriteRow
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(rows) == 0 && len(rec) > 0 && strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(rec[0], "\ufeff")), "nodeid") {
+			continue
+		}
+		row := &BulkWriteRow{Line: line}
+		switch len(rec) {
+		case 0:
+			continue
+		case 1:
+			row.NodeID = rec[0]
+		case 2:
+			// NodeID,Value
+			row.NodeID, row.Value = rec[0], rec[1]
+		default:
+			row.NodeID, row.DataType, row.Value = rec[0], rec[1], strings.Join(rec[2:], ",")
+		}
+		row.NodeID = strings.TrimSpace(strings.TrimPrefix(row.NodeID, "\ufeff"))
+		row.DataType = strings.TrimSpace(row.DataType)
+		if row.NodeID == "" && row.Value == "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no rows found (expected NodeID,DataType,Value)")
+	}
+	return rows, nil
+}
+
+// ValidateBulkWrite checks every row against the server: the node must exist and be writable,
+// and the value must convert to the node's DataType. Arrays are given as comma separated lists.
+// Rows are marked valid or invalid; nothing is written.
+func (c *Controller) ValidateBulkWrite(rows []*BulkWriteRow) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+
+	timeouts := c.timeouts()
+	strict := c.strictAccessLevel()
+	guard, err := c.writeGuard()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]int)
+	for _, row := range rows {
+		row.value = nil
+		if _, err := ua.ParseNodeID(row.NodeID); err != nil {
+			row.invalid("invalid NodeID: %v", err)
+			continue
+		}
+		if prev, ok := seen[row.NodeID]; ok {
+			row.invalid("duplicate of line %d", prev)
+			continue
+		}
+		seen[row.NodeID] = row.Line
+		if err := guard.check(row.NodeID); err != nil {
+			row.invalid("%v", err)
+			continue
+		}
+
+		ctx, cancel := c.opContext(context.Background(), timeouts.Read)
+		results, err := client.ReadAttributes(ctx, row.NodeID,
+			ua.AttributeIDDataType, ua.AttributeIDValueRank, ua.AttributeIDUserAccessLevel)
+		cancel()
+		if err != nil {
+			row.invalid("read failed: %v", err)
+			continue
+		}
+		if len(results) != 3 || results[0] == nil || results[0].Status != ua.StatusOK {
+			row.invalid("node not found or not a variable")
+			continue
+		}
+		serverDT := ""
+		if dt, ok := results[0].Value.Value().(*ua.NodeID); ok {
+			serverDT = builtinTypeName(dt)
+		}
+		valueRank := -1
+		if results[1] != nil && results[1].Status == ua.StatusOK {
+			if v, ok := results[1].Value.Value().(int32); ok {
+				valueRank = int(v)
+			}
+		}
+		if r := results[2]; r != nil && r.Status == ua.StatusOK {
+			if lvl, ok := r.Value.Value().(uint8); ok && ua.AccessLevelType(lvl)&ua.AccessLevelTypeCurrentWrite == 0 {
+				row.invalid("not writable (AccessLevel=%s)", formatAccessLevel(ua.AccessLevelType(lvl)))
+				continue
+			}
+		} else if strict {
+			status := ua.StatusBadNoData
+			if r != nil {
+				status = r.Status
+			}
+			row.invalid("AccessLevel unknown (%s), strict mode", statusName(status))
+			continue
+		}
+
+		dataType := row.DataType
+		var note string
+		if serverDT != "" && !strings.EqualFold(serverDT, dataType) {
+			if dataType != "" {
+				note = fmt.Sprintf("using server DataType %s instead of %s", serverDT, dataType)
+			}
+			dataType = serverDT
+		}
+		if dataType == "" {
+			row.invalid("unknown DataType")
+			continue
+		}
+		row.DataType = dataType
+
+		// Enumerations are written as Int32; symbolic names are replaced by their values
+		input := row.Value
+		if enum := c.EnumType(context.Background(), dataType); enum != nil {
+			if input, err = enum.resolveInput(row.Value, valueRank >= 0); err != nil {
+				row.invalid("%v", err)
+				continue
+			}
+			dataType = "Int32"
+		}
+		var val interface{}
+		if valueRank >= 0 {
+			val, err = convertStringToArray(input, dataType)
+		} else {
+			val, err = convertStringToType(input, dataType)
+		}
+		if err != nil {
+			row.invalid("cannot convert value to %s: %v", dataType, err)
+			continue
+		}
+		if _, err := ua.NewVariant(val); err != nil {
+			row.invalid("unsupported value: %v", err)
+			continue
+		}
+		row.value = val
+		row.Status = BulkWriteValid
+		row.Message = note
+	}
+	return nil
+}
+
+// ExecuteBulkWrite writes all valid rows in chunks of chunkSize values per request and records
+// the per-row result. progress, if set, is called after each chunk.
+func (c *Controller) ExecuteBulkWrite(rows []*BulkWriteRow, chunkSize int, progress func(done, total int)) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultBulkWriteChunkSize
+	}
+
+	// The lists may have changed since validation
+	guard, err := c.writeGuard()
+	if err != nil {
+		return err
+	}
+	pending := make([]*BulkWriteRow, 0, len(rows))
+	for _, row := range rows {
+		if row.Status == BulkWriteValid && row.value != nil {
+			if err := guard.check(row.NodeID); err != nil {
+				row.invalid("%v", err)
+				continue
+			}
+			pending = append(pending, row)
+		}
+	}
+	if len(pending) == 0 {
+		return errors.New("no valid rows to write")
+	}
+
+	var okCount, failCount int
+	for start := 0; start < len(pending); start += chunkSize {
+		end := min(start+chunkSize, len(pending))
+		ok, failed := c.writeBulkChunk(context.Background(), client, pending[start:end])
+		okCount += ok
+		failCount += failed
+		if progress != nil {
+			progress(end, len(pending))
+		}
+	}
+
+	c.usageWrites(okCount, failCount)
+	if failCount > 0 {
+		c.Log(fmt.Sprintf("[yellow]Bulk write finished: %d ok, %d failed[-]", okCount, failCount))
+	} else {
+		c.Log(fmt.Sprintf("[green]Bulk write finished: %d values written[-]", okCount))
+	}
+	return nil
+}
+
+// writeBulkChunk writes the values of chunk in one request and records the per-row result.
+func (c *Controller) writeBulkChunk(ctx context.Context, client *opc.Client, chunk []*BulkWriteRow) (okCount, failCount int) {
+	ids := make([]*ua.NodeID, len(chunk))
+	values := make([]interface{}, len(chunk))
+	for i, row := range chunk {
+		ids[i], _ = ua.ParseNodeID(row.NodeID)
+		values[i] = row.value
+	}
+
+	ctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	results, err := client.WriteValues(ctx, ids, values)
+	cancel()
+	for i, row := range chunk {
+		switch {
+		case err != nil:
+			row.Status, row.Message = BulkWriteFailed, err.Error()
+		case results[i] != ua.StatusOK:
+			row.Status, row.Message = BulkWriteFailed, results[i].Error()
+		default:
+			row.Status, row.Message = BulkWriteOK, ""
+		}
+		if row.Status == BulkWriteOK {
+			okCount++
+		} else {
+			failCount++
+		}
+	}
+	return okCount, failCount
+}
+
+// ExecuteBulkWriteTransaction writes the valid rows like ExecuteBulkWrite, but first reads the
+// current value of every row into Previous; if that fails for any row nothing is written. The
+// batch stops at the first chunk with a failed write or when ctx is cancelled (the user
+// aborts); rows not reached are marked skipped. It reports whether written rows can be restored
+// with RollbackBulkWrite, which the caller offers when the batch did not complete.
+func (c *Controller) ExecuteBulkWriteTransaction(ctx context.Context, rows []*BulkWriteRow, chunkSize int, progress func(done, total int)) (complete bool, err error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return false, errors.New("not connected")
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultBulkWriteChunkSize
+	}
+	guard, err := c.writeGuard()
+	if err != nil {
+		return false, err
+	}
+	pending := make([]*BulkWriteRow, 0, len(rows))
+	for _, row := range rows {
+		if row.Status == BulkWriteValid && row.value != nil {
+			if err := guard.check(row.NodeID); err != nil {
+				row.invalid("%v", err)
+				continue
+			}
+			pending = append(pending, row)
+		}
+	}
+	if len(pending) == 0 {
+		return false, errors.New("no valid rows to write")
+	}
+
+	// Capture the previous values; a parameter set that cannot be restored is not written
+	for start := 0; start < len(pending); start += chunkSize {
+		chunk := pending[start:min(start+chunkSize, len(pending))]
+		ids := make([]*ua.NodeID, len(chunk))
+		for i, row := range chunk {
+			ids[i], _ = ua.ParseNodeID(row.NodeID)
+		}
+		readCtx, cancel := c.opContext(ctx, c.timeouts().Read)
+		dvs, err := client.ReadValues(readCtx, ids)
+		cancel()
+		if err != nil {
+			return false, fmt.Errorf("reading previous values failed, nothing written: %w", err)
+		}
+		for i, row := range chunk {
+			dv := dvs[i]
+			if dv == nil || dv.Status != ua.StatusOK || dv.Value == nil || dv.Value.Value() == nil {
+				status := ua.StatusBadNoData
+				if dv != nil && dv.Status != ua.StatusOK {
+					status = dv.Status
+				}
+				return false, fmt.Errorf("cannot read previous value of %s (%s), nothing written", row.NodeID, statusName(status))
+			}
+			row.prev = dv.Value.Value()
+			row.Previous = formatValue(dv.Value, row.DataType)
+		}
+	}
+
+	var okCount, failCount int
+	for start := 0; start < len(pending); start += chunkSize {
+		if ctx.Err() != nil || failCount > 0 {
+			reason := "aborted"
+			if failCount > 0 {
+				reason = "stopped after a failed write"
+			}
+			for _, row := range pending[start:] {
+				row.Status, row.Message = BulkWriteSkipped, reason
+			}
+			break
+		}
+		end := min(start+chunkSize, len(pending))
+		ok, failed := c.writeBulkChunk(ctx, client, pending[start:end])
+		okCount += ok
+		failCount += failed
+		if progress != nil {
+			progress(end, len(pending))
+		}
+	}
+
+	c.usageWrites(okCount, failCount)
+	complete = okCount == len(pending)
+	if complete {
+		c.Log(fmt.Sprintf("[green]Transactional bulk write finished: %d values written[-]", okCount))
+	} else {
+		c.Log(fmt.Sprintf("[yellow]Transactional bulk write incomplete: %d ok, %d failed, %d skipped; rollback available[-]", okCount, failCount, len(pending)-okCount-failCount))
+	}
+	return complete, nil
+}
+
+// CanRollbackBulkWrite reports whether rows has written values with a captured previous value.
+func CanRollbackBulkWrite(rows []*BulkWriteRow) bool {
+	for _, row := range rows {
+		if row.Status == BulkWriteOK && row.prev != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RollbackBulkWrite writes the previous values of the rows written by
+// ExecuteBulkWriteTransaction back, in reverse order, and marks them rolled back; rows whose
+// restore fails stay ok with the error in Message.
+func (c *Controller) RollbackBulkWrite(rows []*BulkWriteRow, chunkSize int) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultBulkWriteChunkSize
+	}
+	var written []*BulkWriteRow
+	for i := len(rows) - 1; i >= 0; i-- {
+		if rows[i].Status == BulkWriteOK && rows[i].prev != nil {
+			written = append(written, rows[i])
+		}
+	}
+	if len(written) == 0 {
+		return errors.New("nothing to roll back")
+	}
+
+	var restored, failed int
+	for start := 0; start < len(written); start += chunkSize {
+		chunk := written[start:min(start+chunkSize, len(written))]
+		ids := make([]*ua.NodeID, len(chunk))
+		values := make([]interface{}, len(chunk))
+		for i, row := range chunk {
+			ids[i], _ = ua.ParseNodeID(row.NodeID)
+			values[i] = row.prev
+		}
+		ctx, cancel := c.opContext(context.Background(), c.timeouts().Write)
+		results, err := client.WriteValues(ctx, ids, values)
+		cancel()
+		for i, row := range chunk {
+			switch {
+			case err != nil:
+				row.Message = "rollback failed: " + err.Error()
+				failed++
+			case results[i] != ua.StatusOK:
+				row.Message = "rollback failed: " + results[i].Error()
+				failed++
+			default:
+				row.Status, row.Message = BulkWriteRolledBack, ""
+				restored++
+			}
+		}
+	}
+	if failed > 0 {
+		c.Log(fmt.Sprintf("[red]Bulk write rollback: %d restored, %d failed[-]", restored, failed))
+		return fmt.Errorf("%d of %d values could not be restored", failed, len(written))
+	}
+	c.Log(fmt.Sprintf("[green]Bulk write rolled back: %d values restored[-]", restored))
+	return nil
+}
+
+// WriteBulkWriteReport saves the per-row status of a bulk write as CSV.
+func WriteBulkWriteReport(filePath string, rows []*BulkWriteRow) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"Line", "NodeID", "DataType", "Value", "Previous", "Status", "Message"})
+	for _, row := range rows {
+		_ = w.Write([]string{strconv.Itoa(row.Line), row.NodeID, row.DataType, row.Value, row.Previous, row.Status, row.Message})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (r *BulkWriteRow) invalid(format string, args ...interface{}) {
+	r.Status = BulkWriteInvalid
+	r.Message = fmt.Sprintf(format, args...)
+}
+
+// convertStringToArray parses "[1,2,3]" or "1,2,3" into a typed slice of dataType.
+func convertStringToArray(valueStr, dataType string) (interface{}, error) {
+	s := strings.TrimSpace(valueStr)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	var slice reflect.Value
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := convertStringToType(part, dataType)
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.ValueOf(v)
+		if !slice.IsValid() {
+			slice = reflect.MakeSlice(reflect.SliceOf(rv.Type()), 0, 0)
+		}
+		slice = reflect.Append(slice, rv)
+	}
+	if !slice.IsValid() {
+		return nil, errors.New("empty array")
+	}
+	return slice.Interface(), nil
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseHexBytes parses the bytes of a ByteString given as hex digit pairs. Pairs may be
+// separated by spaces, line breaks, commas, semicolons or colons and carry a 0x prefix, e.g.
+// "4142", "41 42", "0x41,0x42" or a pasted hex dump without offsets. The error names the byte
+// that is not valid hex.
+func ParseHexBytes(s string) ([]byte, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == ',' || r == ';' || r == ':'
+	})
+	b := make([]byte, 0, len(s)/2)
+	for _, f := range fields {
+		if len(f) > 2 && (f[:2] == "0x" || f[:2] == "0X") {
+			f = f[2:]
+		}
+		if len(f)%2 != 0 {
+			return nil, fmt.Errorf("byte %d: odd number of hex digits in %q", len(b)+len(f)/2, f)
+		}
+		for i := 0; i < len(f); i += 2 {
+			hi, ok1 := hexDigit(f[i])
+			lo, ok2 := hexDigit(f[i+1])
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("byte %d: %q is not hex", len(b), f[i:i+2])
+			}
+			b = append(b, hi<<4|lo)
+		}
+	}
+	return b, nil
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// FormatHexGrid writes b as uppercase hex pairs, perLine bytes per line.
+func FormatHexGrid(b []byte, perLine int) string {
+	var sb strings.Builder
+	for i, c := range b {
+		if i > 0 {
+			if perLine > 0 && i%perLine == 0 {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		fmt.Fprintf(&sb, "%02X", c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Trigger conditions of a capture
+const (
+	TriggerRising  = "rising"  // value crosses Threshold upwards (false→true for booleans)
+	TriggerFalling = "falling" // value crosses Threshold downwards (true→false for booleans)
+	TriggerChange  = "change"  // any change of the value
+)
+
+// maxCaptureSamples bounds the pre-trigger ring buffer regardless of the window length.
+const maxCaptureSamples = 100000
+
+// CaptureTrigger defines when a capture fires and which window around it is written.
+type CaptureTrigger struct {
+	NodeID    string        `json:"node_id"`
+	Condition string        `json:"condition"`
+	Threshold float64       `json:"threshold"`
+	Pre       time.Duration `json:"pre"`  // window kept before the trigger
+	Post      time.Duration `json:"post"` // window recorded after the trigger
+	Dir       string        `json:"dir"`  // directory the capture files are written to
+	OneShot   bool          `json:"one_shot"`
+}
+
+// CaptureStatus is passed to OnCaptureUpdate.
+type CaptureStatus struct {
+	Armed     bool
+	Capturing bool // triggered, recording the post-trigger window
+	Trigger   CaptureTrigger
+	Count     int    // captures written since arming
+	LastFile  string // last capture file written
+}
+
+// captureSample is one watch value in the ring buffer.
+type captureSample struct {
+	at   time.Time
+	item WatchItem
+}
+
+// captureState is the armed trigger with its ring buffer, guarded by its own mutex since it is fed
+// from the data change path.
+type captureState struct {
+	mu        sync.Mutex
+	trigger   CaptureTrigger
+	ring      []captureSample
+	last      string // last value of the trigger node
+	hasLast   bool
+	firedAt   time.Time
+	post      []captureSample // samples since the trigger; nil while waiting for the trigger
+	count     int
+	lastFile  string
+	disarmed  bool
+	capturing bool
+}
+
+// DefaultCaptureDir is where capture files go when no directory is given.
+func DefaultCaptureDir() string {
+	return filepath.Join(os.TempDir(), "opcuababy-captures")
+}
+
+// ArmCapture arms a trigger on a watched node. Until it fires all watch values of the last t.Pre
+// are kept in memory; when it fires the values of the following t.Post are added and the window
+// is written as JSON Lines of watch items, the format offline mode replays. Arming replaces a
+// previously armed trigger.
+func (c *Controller) ArmCapture(t CaptureTrigger) error {
+	switch t.Condition {
+	case TriggerRising, TriggerFalling, TriggerChange:
+	default:
+		return fmt.Errorf("unknown trigger condition %q", t.Condition)
+	}
+	if t.Pre < 0 || t.Post < 0 {
+		return errors.New("capture windows must not be negative")
+	}
+	c.mu.RLock()
+	_, watched := c.watchItems[t.NodeID]
+	c.mu.RUnlock()
+	if !watched {
+		return fmt.Errorf("%s is not in the watch list", t.NodeID)
+	}
+	if strings.TrimSpace(t.Dir) == "" {
+		t.Dir = DefaultCaptureDir()
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+
+	c.DisarmCapture()
+	c.capMu.Lock()
+	c.capture = &captureState{trigger: t}
+	c.capMu.Unlock()
+	c.Log(fmt.Sprintf("[cyan]Capture armed on %s (%s %g), window -%s/+%s[-]", t.NodeID, t.Condition, t.Threshold, t.Pre, t.Post))
+	c.notifyCapture()
+	return nil
+}
+
+// DisarmCapture removes the armed trigger. A capture that already fired is still written.
+func (c *Controller) DisarmCapture() {
+	c.capMu.Lock()
+	st := c.capture
+	c.capture = nil
+	c.capMu.Unlock()
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	st.disarmed = true
+	st.ring = nil
+	capturing := st.capturing
+	st.mu.Unlock()
+	if !capturing {
+		c.Log("[yellow]Capture disarmed[-]")
+	}
+	c.notifyCapture()
+}
+
+// CaptureStatus reports the armed trigger, if any.
+func (c *Controller) CaptureStatus() CaptureStatus {
+	c.capMu.Lock()
+	st := c.capture
+	c.capMu.Unlock()
+	if st == nil {
+		return CaptureStatus{}
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return CaptureStatus{Armed: true, Capturing: st.capturing, Trigger: st.trigger, Count: st.count, LastFile: st.lastFile}
+}
+
+func (c *Controller) notifyCapture() {
+	c.mu.RLock()
+	cb := c.OnCaptureUpdate
+	c.mu.RUnlock()
+	if cb != nil {
+		cb(c.CaptureStatus())
+	}
+}
+
+// captureSample feeds a watch value into the armed capture, if any.
+func (c *Controller) captureSample(item *WatchItem) {
+	c.capMu.Lock()
+	st := c.capture
+	c.capMu.Unlock()
+	if st == nil {
+		return
+	}
+	now := time.Now()
+	s := captureSample{at: now, item: *item}
+	s.item.subHandle = nil
+	s.item.lastDataValue = nil
+	s.item.recent = nil
+
+	st.mu.Lock()
+	if st.capturing {
+		st.post = append(st.post, s)
+		st.mu.Unlock()
+		return
+	}
+	st.ring = append(st.ring, s)
+	cut := 0
+	for cut < len(st.ring) && (now.Sub(st.ring[cut].at) > st.trigger.Pre || len(st.ring)-cut > maxCaptureSamples) {
+		cut++
+	}
+	st.ring = st.ring[cut:]
+	fired := false
+	if item.NodeID == st.trigger.NodeID {
+		fired = st.hasLast && triggerFires(st.trigger, st.last, item.Value)
+		st.last, st.hasLast = item.Value, true
+	}
+	if fired && !st.disarmed {
+		st.capturing = true
+		st.firedAt = now
+		st.post = nil
+		time.AfterFunc(st.trigger.Post, func() { c.finishCapture(st) })
+	}
+	st.mu.Unlock()
+
+	if fired {
+		c.Log(fmt.Sprintf("[cyan]Capture triggered by %s = %s[-]", item.NodeID, item.Value))
+		c.notifyCapture()
+	}
+}
+
+// finishCapture writes the pre/post window of a fired trigger and re-arms it unless it is one-shot.
+func (c *Controller) finishCapture(st *captureState) {
+	st.mu.Lock()
+	samples := append(st.ring, st.post...)
+	t, firedAt := st.trigger, st.firedAt
+	st.ring, st.post = nil, nil
+	st.capturing = false
+	st.mu.Unlock()
+
+	path := filepath.Join(t.Dir, "capture_"+firedAt.Format("20060102_150405.000")+".jsonl")
+	err := writeCapture(path, samples)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Writing capture failed: %v[-]", err))
+	} else {
+		c.Log(fmt.Sprintf("[green]Capture of %d values written to %s[-]", len(samples), path))
+	}
+
+	st.mu.Lock()
+	if err == nil {
+		st.count++
+		st.lastFile = path
+	}
+	st.mu.Unlock()
+	if t.OneShot {
+		c.capMu.Lock()
+		if c.capture == st {
+			c.capture = nil
+		}
+		c.capMu.Unlock()
+	}
+	c.notifyCapture()
+}
+
+// writeCapture writes samples as JSON Lines of watch items with their capture time as timestamps.
+func writeCapture(path string, samples []captureSample) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, s := range samples {
+		item := s.item
+		if item.SourceTimestamp == "" {
+			item.SourceTimestamp = s.at.Format(time.RFC3339Nano)
+		}
+		if err := enc.Encode(&item); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// triggerFires evaluates the trigger condition between the previous and current value.
+func triggerFires(t CaptureTrigger, prev, cur string) bool {
+	if t.Condition == TriggerChange {
+		return prev != cur
+	}
+	p, okP := triggerNumber(prev)
+	v, okV := triggerNumber(cur)
+	if !okP || !okV {
+		return false
+	}
+	if t.Condition == TriggerRising {
+		return p <= t.Threshold && v > t.Threshold
+	}
+	return p >= t.Threshold && v < t.Threshold
+}
+
+// triggerNumber reads a formatted watch value as a number; booleans are 0 and 1.
+func triggerNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if b, err := strconv.ParseBool(s); err == nil {
+		if b {
+			return 1, true
+		}
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
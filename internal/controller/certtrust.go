@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// DefaultTrustPollInterval is how often WaitForCertificateTrust asks the server again.
+const DefaultTrustPollInterval = 5 * time.Second
+
+// WaitForCertificateTrust presents the client certificate of cfg to the server (see
+// opc.ProbeTrust) every interval until the server accepts it or ctx ends, and reports each
+// result to onAttempt (a nil error once trusted). A signal on resend starts the next attempt
+// right away. It works independently of the current connection.
+func (c *Controller) WaitForCertificateTrust(ctx context.Context, cfg *opc.Config, interval time.Duration, resend <-chan struct{}, onAttempt func(attempt int, err error)) error {
+	if interval <= 0 {
+		interval = DefaultTrustPollInterval
+	}
+	timeout := 10 * time.Second
+	if cfg.ConnectTimeout > 0 {
+		timeout = time.Duration(cfg.ConnectTimeout * float64(time.Second))
+	}
+	c.Log(fmt.Sprintf("[cyan]Waiting for %s to trust the client certificate %s[-]", cfg.EndpointURL, cfg.CertFile))
+	for attempt := 1; ; attempt++ {
+		probeCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := opc.ProbeTrust(probeCtx, cfg)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if onAttempt != nil {
+			onAttempt(attempt, err)
+		}
+		if err == nil {
+			c.Log(fmt.Sprintf("[green]%s now trusts the client certificate[-]", cfg.EndpointURL))
+			return nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-resend:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// clockCheckInterval is how often the server clock is compared with the local one.
+const clockCheckInterval = 10 * time.Minute
+
+// ClockOffset returns the last measured offset of the server clock, or nil if it is not
+// connected or the server does not publish its CurrentTime.
+func (c *Controller) ClockOffset() *opc.ClockOffset {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clockOffset
+}
+
+// clockLoop compares the clock of the session cli with the local one after connect and every
+// clockCheckInterval until the session ends.
+func (c *Controller) clockLoop(cli *opc.Client) {
+	ctx := c.GetClientContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for first := true; ; first = false {
+		if !c.checkClock(ctx, cli, first) {
+			return
+		}
+		timer := time.NewTimer(clockCheckInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// checkClock measures the clock offset of cli and logs a warning when it crosses the configured
+// threshold, and a note when it is back within. Read failures are logged on the first check and
+// when the offset becomes unavailable. It reports false once cli is no longer the session.
+func (c *Controller) checkClock(ctx context.Context, cli *opc.Client, first bool) bool {
+	readCtx, cancel := c.opContext(ctx, c.timeouts().Read)
+	off, err := cli.ReadClockOffset(readCtx)
+	cancel()
+
+	c.mu.Lock()
+	if c.client != cli {
+		c.mu.Unlock()
+		return false
+	}
+	prev := c.clockOffset
+	if err != nil {
+		c.clockOffset = nil
+		c.mu.Unlock()
+		if ctx.Err() != nil {
+			return false
+		}
+		if first || prev != nil {
+			c.Log(fmt.Sprintf("[yellow]Could not read the server clock: %v[-]", err))
+		}
+		return true
+	}
+	limit := c.currentConfig.ClockSkewWarn()
+	off.Skewed = off.Offset().Abs() > limit
+	c.clockOffset = off
+	c.mu.Unlock()
+
+	offset := FormatClockOffset(off.Offset())
+	switch {
+	case off.Skewed && (prev == nil || !prev.Skewed):
+		c.Log(fmt.Sprintf("[yellow]The server clock is off by %s (more than %v); timestamps of exported data are skewed, synchronize the clocks (NTP)[-]", offset, limit))
+	case !off.Skewed && prev != nil && prev.Skewed:
+		c.Log(fmt.Sprintf("[green]The server clock is back within %v of the local clock (%s)[-]", limit, offset))
+	case first:
+		c.Log(fmt.Sprintf("[cyan]Server clock offset %s (round trip %.0f ms)[-]", offset, off.RoundTripMs))
+	}
+	return true
+}
+
+// FormatClockOffset renders d rounded to milliseconds with its sign, e.g. "+1.25s" when the server
+// clock is ahead.
+func FormatClockOffset(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+	if d < 0 {
+		return d.String()
+	}
+	return "+" + d.String()
+}
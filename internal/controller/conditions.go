@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// Actions of ConditionAction, the methods of AcknowledgeableConditionType and ConditionType.
+const (
+	ConditionAcknowledge = "acknowledge"
+	ConditionConfirm     = "confirm"
+	ConditionComment     = "comment"
+)
+
+// Condition is the latest reported state of an alarm or condition of the server.
+type Condition struct {
+	ConditionID string    `json:"condition_id"`
+	Name        string    `json:"name"`
+	SourceNode  string    `json:"source_node,omitempty"`
+	SourceName  string    `json:"source_name,omitempty"`
+	EventType   string    `json:"event_type"`
+	EventID     []byte    `json:"event_id"` // base64 in JSON; identifies the state to act on
+	Time        time.Time `json:"time"`
+	Message     string    `json:"message"`
+	Severity    uint16    `json:"severity"`
+	Comment     string    `json:"comment,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	Active      bool      `json:"active"`
+	Acked       bool      `json:"acked"`
+	Confirmed   bool      `json:"confirmed"`
+}
+
+// ConditionRequest selects the condition state an action applies to.
+type ConditionRequest struct {
+	ConditionID string `json:"condition_id"`
+	// EventID (base64 in JSON) of the state to act on; empty uses the latest tracked event
+	EventID []byte `json:"event_id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+}
+
+// conditionTracker keeps the retained conditions of one session up to date from its events.
+type conditionTracker struct {
+	cli       *opc.Client
+	conds     map[string]*Condition
+	refreshed chan struct{} // closed once the initial ConditionRefresh completed
+	done      bool
+}
+
+// Conditions returns the retained alarms and conditions of the server, most recent first.
+// Condition events are subscribed on the first call of a session and the current states
+// requested with ConditionRefresh; later calls answer from the states tracked since.
+func (c *Controller) Conditions(ctx context.Context) ([]Condition, error) {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return nil, errors.New("alarms are not available in offline mode")
+	}
+	if cli == nil {
+		return nil, errors.New("not connected")
+	}
+
+	c.condMu.Lock()
+	t := c.conditions
+	start := t == nil || t.cli != cli
+	if start {
+		t = &conditionTracker{cli: cli, conds: make(map[string]*Condition), refreshed: make(chan struct{})}
+		c.conditions = t
+	}
+	c.condMu.Unlock()
+
+	if start {
+		if err := c.startConditionTracking(ctx, t); err != nil {
+			c.condMu.Lock()
+			if c.conditions == t {
+				c.conditions = nil
+			}
+			c.condMu.Unlock()
+			return nil, err
+		}
+	}
+	wait, cancel := context.WithTimeout(ctx, c.timeouts().Read)
+	defer cancel()
+	select {
+	case <-t.refreshed:
+	case <-wait.Done():
+	}
+
+	c.condMu.Lock()
+	out := make([]Condition, 0, len(t.conds))
+	for _, cond := range t.conds {
+		out = append(out, *cond)
+	}
+	c.condMu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Time.Equal(out[j].Time) {
+			return out[i].Time.After(out[j].Time)
+		}
+		return out[i].ConditionID < out[j].ConditionID
+	})
+	return out, nil
+}
+
+// startConditionTracking subscribes to the condition events of t's session and asks for the
+// current states. Servers without ConditionRefresh are tracked from the next events on.
+func (c *Controller) startConditionTracking(ctx context.Context, t *conditionTracker) error {
+	es, err := t.cli.MonitorConditions(func(ev opc.ConditionEvent) {
+		c.condMu.Lock()
+		defer c.condMu.Unlock()
+		c.applyConditionEvent(t, ev)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot subscribe to condition events: %w", err)
+	}
+	// es is closed with the session
+	rctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+	if err := t.cli.RefreshConditions(rctx, es); err != nil {
+		c.Log(fmt.Sprintf("[yellow]ConditionRefresh failed (%v); alarms are tracked from their next state change[-]", err))
+		c.condMu.Lock()
+		t.markRefreshed()
+		c.condMu.Unlock()
+	}
+	return nil
+}
+
+// applyConditionEvent records ev in t. Callers must hold c.condMu.
+func (c *Controller) applyConditionEvent(t *conditionTracker, ev opc.ConditionEvent) {
+	if ev.RefreshEnd {
+		t.markRefreshed()
+		return
+	}
+	if ev.ConditionID == "" {
+		return
+	}
+	if !ev.Retain {
+		// The condition no longer needs attention
+		delete(t.conds, ev.ConditionID)
+		return
+	}
+	name := ev.ConditionName
+	if name == "" {
+		name = ev.SourceName
+	}
+	t.conds[ev.ConditionID] = &Condition{
+		ConditionID: ev.ConditionID,
+		Name:        name,
+		SourceNode:  ev.SourceNode,
+		SourceName:  ev.SourceName,
+		EventType:   ev.EventType,
+		EventID:     ev.EventID,
+		Time:        ev.Time,
+		Message:     ev.Message,
+		Severity:    ev.Severity,
+		Comment:     ev.Comment,
+		Enabled:     ev.Enabled,
+		Active:      ev.Active,
+		Acked:       ev.Acked,
+		Confirmed:   ev.Confirmed,
+	}
+}
+
+func (t *conditionTracker) markRefreshed() {
+	if !t.done {
+		t.done = true
+		close(t.refreshed)
+	}
+}
+
+// ConditionAction acknowledges or confirms a condition, or adds a comment to it, by calling the
+// condition's method on the server. Without an EventID the latest tracked state is used. The
+// write allow/deny lists apply to the ConditionId.
+func (c *Controller) ConditionAction(ctx context.Context, action string, req ConditionRequest) error {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return errors.New("alarms are not available in offline mode")
+	}
+	if cli == nil {
+		return errors.New("not connected")
+	}
+	req.ConditionID = strings.TrimSpace(req.ConditionID)
+	condID, err := ua.ParseNodeID(req.ConditionID)
+	if err != nil {
+		return fmt.Errorf("invalid condition_id %q: %w", req.ConditionID, err)
+	}
+	var call func(context.Context, *ua.NodeID, []byte, *ua.LocalizedText) error
+	var done string
+	switch strings.ToLower(action) {
+	case ConditionAcknowledge:
+		call, done = cli.AcknowledgeCondition, "Acknowledged"
+	case ConditionConfirm:
+		call, done = cli.ConfirmCondition, "Confirmed"
+	case ConditionComment:
+		if strings.TrimSpace(req.Comment) == "" {
+			return errors.New("comment is required")
+		}
+		call, done = cli.CommentCondition, "Commented on"
+	default:
+		return fmt.Errorf("unknown condition action %q", action)
+	}
+	if err := c.CheckWriteAllowed(req.ConditionID); err != nil {
+		return err
+	}
+	eventID := req.EventID
+	if len(eventID) == 0 {
+		c.condMu.Lock()
+		if t := c.conditions; t != nil && t.cli == cli {
+			if cond, ok := t.conds[req.ConditionID]; ok {
+				eventID = cond.EventID
+			}
+		}
+		c.condMu.Unlock()
+		if len(eventID) == 0 {
+			return fmt.Errorf("no event of %s is known; pass event_id or list the alarms first", req.ConditionID)
+		}
+	}
+
+	cctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
+	comment := ua.NewLocalizedTextWithLocale(req.Comment, req.Locale)
+	if err := call(cctx, condID, eventID, comment); err != nil {
+		c.Log(fmt.Sprintf("[red]Condition %s of %s failed: %v[-]", strings.ToLower(action), req.ConditionID, err))
+		return err
+	}
+	if req.Comment != "" {
+		c.Log(fmt.Sprintf("[green]%s condition %s: %q[-]", done, req.ConditionID, req.Comment))
+	} else {
+		c.Log(fmt.Sprintf("[green]%s condition %s[-]", done, req.ConditionID))
+	}
+	return nil
+}
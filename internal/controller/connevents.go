@@ -0,0 +1,37 @@
+package controller
+
+import "time"
+
+// Connection event names sent to API clients.
+const (
+	EventConnectionLost     = "connection_lost"
+	EventConnectionRestored = "connection_restored"
+)
+
+// ConnectionEvent tells API clients that the OPC UA session was lost or is back, so WebSocket
+// clients can stay connected across reconnects.
+type ConnectionEvent struct {
+	Event    string `json:"event"` // EventConnectionLost or EventConnectionRestored
+	Endpoint string `json:"endpoint,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Time     string `json:"time"`
+}
+
+// ConnectionEvents delivers connection losses and restorations, both automatic reconnects of the
+// stack and Disconnect/Connect cycles. It has a single consumer (the API hub); events are
+// dropped while nobody reads them.
+func (c *Controller) ConnectionEvents() <-chan ConnectionEvent { return c.connEvents }
+
+func (c *Controller) emitConnectionEvent(event, endpoint, reason string) {
+	c.mu.RLock()
+	cfg := c.currentConfig
+	c.mu.RUnlock()
+	if endpoint == "" && cfg != nil {
+		endpoint = cfg.EndpointURL
+	}
+	ev := ConnectionEvent{Event: event, Endpoint: endpoint, Reason: reason, Time: c.formatISOTimestamp(cfg, time.Now())}
+	select {
+	case c.connEvents <- ev:
+	default:
+	}
+}
@@ -23,33 +23,66 @@ import (
 	"github.com/gopcua/opcua/ua"
 
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// startWatchUpdatePump periodically emits the entire watch list to the UI callback,
-// reducing UI refresh frequency under heavy data change load.
-// (removed) startWatchUpdatePump was unused after simplifying connection logic
 // NodeManager defines the interface for API server interactions, breaking import cycles.
 type NodeManager interface {
-	ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
-	WriteValue(nodeID, dataType, valueStr string)
-	AddWatch(nodeID string)
-	GetApiBroadcastChan() chan *WatchItem
+	ReadNodeAttributes(ctx context.Context, nodeID string) (*NodeAttributes, error)
+	WriteValue(ctx context.Context, nodeID, dataType, valueStr string)
+	CheckWriteAllowed(nodeID string) error
+	AddWatch(ctx context.Context, nodeID string)
+	SubscribeWatchFeed() *WatchFeed
+	UnsubscribeWatchFeed(f *WatchFeed)
+	ConnectionEvents() <-chan ConnectionEvent
 	GetClientContext() context.Context
 	IsLogDisabled() bool
-	CollectVariableNodes(parentID string, recursive bool) ([]*ExportTag, error)
+	Log(msg string)
+	IsOffline() bool
+	ConnectionStatus() ConnectionStatus
+	CollectVariableNodes(ctx context.Context, parentID string, recursive bool) ([]*ExportTag, error)
+	CollectVariableNodesProgress(ctx context.Context, parentID string, recursive bool, timeout time.Duration, progress func(visited, found int)) ([]*ExportTag, error)
+	ExportAddressSpace(ctx context.Context, e AddressSpaceExport) error
+	ReadHistoryRaw(ctx context.Context, nodeID string, start, end time.Time, maxValues uint32) ([]*HistoryValue, error)
+	ReadHistoryAggregate(ctx context.Context, nodeID, aggregate string, start, end time.Time, interval time.Duration) ([]*HistoryValue, error)
+	ReadEventHistory(ctx context.Context, req EventHistoryRequest) ([]HistoryEvent, error)
+	UpdateHistory(ctx context.Context, nodeID, mode, dataType string, samples []HistorySample) error
+	DeleteHistoryRaw(ctx context.Context, nodeID string, start, end time.Time) error
+	// Remote control (JSON-RPC)
+	ConnectEndpoint(endpoint string) error
+	Disconnect()
+	Browse(ctx context.Context, parentID string)
+	HasBrowseBeenPerformed(nodeID string) bool
+	RefreshBranch(ctx context.Context, nodeID string) error
+	SearchNodes(ctx context.Context, req SearchRequest, progress func(visited, found int)) (*SearchResults, error)
+	Conditions(ctx context.Context) ([]Condition, error)
+	ConditionAction(ctx context.Context, action string, req ConditionRequest) error
+	GetAddressSpaceChildren(parentID string) []string
+	GetNode(id string) *AddressSpaceNode
+	RemoveWatch(nodeID string)
+	RemoveAllWatches()
+	WatchSnapshot() []*WatchItem
+	WatchPoints(nodeID string) []HistoryPoint
+	NodeMeta(ctx context.Context, nodeID string) *NodeMeta
+	ChannelStats() *opc.ChannelStats
 }
 
 // ApiServerStarter defines the function signature for starting the API server.
 type ApiServerStarter func(ctx context.Context, nodeMgr NodeManager, apiStatus *string, cfg *opc.Config) *http.Server
 
 // WatchItem 监视的变量节点封装
+// Fields added after the first release carry snake_case JSON keys, as in the rest of the API.
 type WatchItem struct {
 	NodeID           string
 	Name             string
 	DataType         string
 	Value            string
-	Timestamp        string
+	ValueTyped       interface{} `json:"value_typed"` // Value in its native JSON type
+	UAType           string      `json:"ua_type"`     // built-in type of the Value variant, e.g. "Double" or "Int32[]"
+	Timestamp        string      // display time, taken from the configured timestamp source
+	SourceTimestamp  string      `json:"source_timestamp"` // ISO-8601 SourceTimestamp of the last DataValue
+	ServerTimestamp  string      `json:"server_timestamp"` // ISO-8601 ServerTimestamp of the last DataValue
 	Severity         string
 	SymbolicName     string
 	SubCode          uint16
@@ -57,8 +90,31 @@ type WatchItem struct {
 	SemanticsChanged bool
 	InfoBits         uint16
 	RawCode          string
+	Golden           *opc.GoldenValue `json:"golden,omitempty"`       // expected value, nil when none is set
+	Deviation        bool             `json:"deviation"`              // Value does not match Golden
+	Forced           bool             `json:"forced"`                 // Value is forced locally (ForceValue), not the server's
+	ServerValue      string           `json:"server_value,omitempty"` // server's latest value while Forced
+	Overflow         bool             `json:"overflow"`               // last notification had the Overflow bit set
+	OverflowCount    uint64           `json:"overflow_count"`         // notifications with the Overflow bit since the watch was added
+	Polled           bool             `json:"polled"`                 // Value is read every poll interval instead of monitored
+	RateMs           float64          `json:"rate_ms,omitempty"`      // requested sampling interval, 0 for the default subscription
+	Dedup            bool             `json:"dedup"`                  // samples repeating the last value and status are not broadcast
+	Suppressed       uint64           `json:"suppressed"`             // samples not broadcast because of Dedup
+	EnumName         string           `json:"enum_name,omitempty"`    // symbolic name of Value when DataType is an Enumeration
+	Flags            string           `json:"flags,omitempty"`        // set bits of Value when DataType is an OptionSet, e.g. "Bit0|Bit3: Running|Enabled"
+
+	subHandle     *opc.Subscription
+	serverTyped   interface{}     // ValueTyped of ServerValue
+	lastDataValue *ua.DataValue   // raw value kept for lossless (OPC UA JSON) export
+	recent        []receivedValue // last rawHistoryLen DataValues, oldest first
+}
 
-	subHandle *opc.Subscription
+// WatchListUpdate is passed to OnWatchListUpdate. Items is set (sorted by NodeID) when rows were
+// added or removed; otherwise it is nil and Changed lists the NodeIDs whose values changed, so
+// the UI only needs to redraw those rows.
+type WatchListUpdate struct {
+	Items   []*WatchItem
+	Changed []string
 }
 
 // AddressSpaceNode 地址空间节点结构
@@ -70,6 +126,7 @@ type AddressSpaceNode struct {
 }
 
 // NodeAttributes 节点详细属性
+// Like WatchItem, fields added after the first release carry snake_case JSON keys.
 type NodeAttributes struct {
 	NodeID      string
 	Name        string
@@ -79,15 +136,40 @@ type NodeAttributes struct {
 	AccessLevel string
 	Value       string
 	ValueRank   int // -1: scalar; 0 or >0: array (0 = any dims, >0 = number of dimensions)
+	// SourceTimestamp/ServerTimestamp of the Value attribute (ISO-8601, empty if not reported)
+	SourceTimestamp string `json:"source_timestamp"`
+	ServerTimestamp string `json:"server_timestamp"`
+	// Timestamp is Value's time in the watch list form, chosen like WatchItem.Timestamp
+	Timestamp  string      `json:"-"`
+	ValueTyped interface{} `json:"value_typed"`
+	UAType     string      `json:"ua_type"`
+	// AttributeStatus maps attribute names to the status of reads that did not return Good,
+	// e.g. "Description": "BadAttributeIdInvalid". Such fields are left empty above.
+	AttributeStatus map[string]string `json:"attribute_status,omitempty"`
+	// AccessLevelKnown is false when neither AccessLevel nor UserAccessLevel could be read.
+	AccessLevelKnown bool `json:"access_level_known"`
+	// WriteMasks tell which attributes other than Value the server lets the user write
+	WriteMasks WriteMasks `json:"write_masks"`
+	// BrowseName as "nsIndex:name" and the URI of the NodeID's namespace
+	BrowseName   string `json:"browse_name,omitempty"`
+	NamespaceURI string `json:"namespace_uri,omitempty"`
+	// Enum holds the values of an Enumeration DataType and EnumName the symbolic name of Value;
+	// OptionSet and Flags are their counterparts for OptionSet DataTypes
+	Enum      *EnumType  `json:"enum,omitempty"`
+	EnumName  string     `json:"enum_name,omitempty"`
+	OptionSet *OptionSet `json:"option_set,omitempty"`
+	Flags     string     `json:"flags,omitempty"`
 }
 
 // ExportTag represents a tag for export
 type ExportTag struct {
-	NodeID      string `json:"node_id"`
-	Name        string `json:"name"`
-	DataType    string `json:"data_type,omitempty"`
-	Description string `json:"description,omitempty"`
-	Path        string `json:"path,omitempty"`
+	NodeID       string `json:"node_id"`
+	Name         string `json:"name"`
+	BrowseName   string `json:"browse_name,omitempty"` // "nsIndex:name"
+	NamespaceURI string `json:"namespace_uri,omitempty"`
+	DataType     string `json:"data_type,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Path         string `json:"path,omitempty"`
 }
 
 type Controller struct {
@@ -100,7 +182,11 @@ type Controller struct {
 	isConnecting bool
 	isConnected  bool
 
-	watchItems map[string]*WatchItem
+	watchItems   map[string]*WatchItem
+	pumpMu       sync.Mutex
+	watchChanged map[string]struct{} // NodeIDs changed since the last redraw, guarded by pumpMu
+	pumpBackoff  atomic.Bool         // redraw at backgroundPumpInterval
+	pumpOnce     sync.Once
 
 	addressSpaceMutex    sync.RWMutex
 	addressSpaceNodes    map[string]*AddressSpaceNode
@@ -109,27 +195,114 @@ type Controller struct {
 	browsingNodes    map[string]bool // 浏览防护，防止重复浏览
 	noChildrenCached map[string]bool // 日志限流用
 
+	// Search results of the session by query (see SearchNodes)
+	searchMu       sync.Mutex
+	searchCache    map[string]*SearchResults
+	querySupported bool // a Query search succeeded in the session
+
+	// Metadata of nodes read for API payloads in the session (see NodeMeta)
+	metaMu    sync.Mutex
+	metaCache map[string]*NodeMeta
+
+	// Value names of the DataTypes read in the session (see EnumType and OptionSetType)
+	enumMu      sync.Mutex
+	enumCache   map[string]*dataTypeValues
+	enumLoading map[string]bool
+
 	logMu sync.Mutex
 
-	// API Server fields
+	// API Server fields, guarded by apiMu
+	apiMu           sync.Mutex
 	apiServer       *http.Server
+	apiPort         string // port of the running server and the public dashboard address, if any
 	apiServerCtx    context.Context
 	apiServerCancel context.CancelFunc
 	apiStatus       *string
 	currentConfig   *opc.Config
 	apiStarter      ApiServerStarter
 
+	serverIdentity *opc.ServerIdentity
+	clockOffset    *opc.ClockOffset // last server clock check (see clockLoop)
+	// NamespaceArray the watched NodeIDs refer to, and saved watches the server could not resolve
+	namespaces        []string
+	unresolvedWatches []string
+	offline           *offlineState // non-nil while serving a loaded address space (see StartOffline)
+
+	// Node shown in the details panel and its temporary monitored item (see SetDetailNode)
+	detailMu       sync.Mutex
+	detailNodeID   string
+	detailDataType string
+	detailSub      *opc.Subscription
+
+	// Scheduled and ramped writes (see ScheduleWrite)
+	schedMu     sync.Mutex
+	schedWrites map[int]*ScheduledWrite
+	schedNextID int
+
+	// Alarms and conditions tracked for the API (see Conditions)
+	condMu     sync.Mutex
+	conditions *conditionTracker
+
+	// Running value simulations by NodeID (see StartSimulation)
+	simMu       sync.Mutex
+	simulations map[string]*Simulation
+
+	// Armed trigger capture (see ArmCapture)
+	capMu   sync.Mutex
+	capture *captureState
+
+	// Locally forced watch values by NodeID (see ForceValue), guarded by mu
+	forces map[string]*forcedValue
+
+	// Polled watch items (see startPolling), guarded by mu: the session whose poll loop runs,
+	// and whether the server refused subscriptions in this session
+	pollClient *opc.Client
+	pollOnly   bool
+
+	// linkDown is set, under mu, while the stack reconnects a lost connection
+	linkDown bool
+
+	// Writes issued while the session was down (see queueWrite)
+	wqMu        sync.Mutex
+	writeQueue  []QueuedWrite
+	wqNextID    int
+	wqReplaying bool
+
+	// Location of the timestamp timezone setting, loaded when the setting changes
+	tzMu   sync.Mutex
+	tzName string
+	tzLoc  *time.Location
+
 	OnConnectionStateChange func(connected bool, endpoint string, err error)
 
 	// UI callbacks
-	OnAddressSpaceReset    func()
-	OnWatchListUpdate      func(items []*WatchItem)
-	OnNodeAttributesUpdate func(attrs *NodeAttributes)
+	OnAddressSpaceReset     func()
+	OnWatchListUpdate       func(u WatchListUpdate)
+	OnNodeAttributesUpdate  func(attrs *NodeAttributes)
+	OnServerIdentityUpdate  func(si *opc.ServerIdentity)
+	OnDetailValueUpdate     func(nodeID, value string)
+	OnScheduledWritesUpdate func()
+	OnSimulationsUpdate     func()
+	OnWriteQueueUpdate      func()
+	OnLinkStateChange       func(st LinkState)
+	OnGoldenDeviation       func(item WatchItem)
+	OnCaptureUpdate         func(s CaptureStatus)
+	OnValueChange           func(item WatchItem)      // every watched value change, e.g. for gateway sinks
+	OnWriteDone             func(rec opc.WriteRecord) // outcome of every WriteValue call
 
 	// Channels
 	AddressSpaceUpdateChan chan string
-	ApiBroadcastChan       chan *WatchItem
 	LogChan                chan string
+	connEvents             chan ConnectionEvent // see ConnectionEvents
+
+	// API consumers of watch updates (see SubscribeWatchFeed)
+	feedsMu sync.Mutex
+	feeds   map[*WatchFeed]struct{}
+
+	// Local usage statistics (see UsageStats)
+	usageMu      sync.Mutex
+	usage        opc.UsageStats
+	sessionStart time.Time
 }
 
 func New() *Controller {
@@ -140,8 +313,8 @@ func New() *Controller {
 		browsingNodes:          make(map[string]bool),
 		noChildrenCached:       make(map[string]bool),
 		AddressSpaceUpdateChan: make(chan string, 64),
-		ApiBroadcastChan:       make(chan *WatchItem, 64),
 		LogChan:                make(chan string, 256),
+		connEvents:             make(chan ConnectionEvent, 16),
 	}
 }
 
@@ -175,7 +348,21 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 	}
 	c.isConnecting = true
 	c.mu.Unlock()
+	if err := opc.CheckEndpointScheme(cfg.EndpointURL); err != nil {
+		c.mu.Lock()
+		c.isConnecting = false
+		c.mu.Unlock()
+		c.Log(fmt.Sprintf("[red]%v[-]", err))
+		if c.OnConnectionStateChange != nil {
+			c.OnConnectionStateChange(false, cfg.EndpointURL, err)
+		}
+		return err
+	}
 	c.Log(fmt.Sprintf("[cyan]Connecting to %s...[-]", cfg.EndpointURL))
+	if len(cfg.LocaleIDs) > 0 {
+		c.Log(fmt.Sprintf("[cyan]Requesting server texts in locales %s[-]", strings.Join(cfg.LocaleIDs, ", ")))
+	}
+	_ = c.ApplyProtocolTrace(cfg)
 
 	// Create lifecycle context
 	c.clientLifecycleMutex.Lock()
@@ -187,10 +374,50 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 	c.clientCancel = cancel
 	c.clientLifecycleMutex.Unlock()
 
+	// Retry failed attempts with backoff; Disconnect cancels ctx and ends the wait
+	policy := cfg.ConnectRetryPolicy()
+	err := policy.Do(ctx, retryableConnectError, func() error { return c.connectAttempt(ctx, cfg) }, func(attempt int, wait time.Duration, err error) {
+		c.Log(fmt.Sprintf("[yellow]Connect attempt %d/%d failed: %v; retrying in %s[-]", attempt, policy.Attempts, err, wait.Round(100*time.Millisecond)))
+	})
+	if err != nil {
+		c.mu.Lock()
+		c.isConnecting = false
+		c.mu.Unlock()
+		if c.OnConnectionStateChange != nil {
+			c.OnConnectionStateChange(false, cfg.EndpointURL, err)
+		}
+	}
+	return err
+}
+
+// retryableConnectError reports whether another connect attempt can succeed; rejected
+// credentials and cancellation are final.
+func retryableConnectError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var sc ua.StatusCode
+	if errors.As(err, &sc) {
+		switch sc {
+		case ua.StatusBadUserAccessDenied, ua.StatusBadIdentityTokenInvalid, ua.StatusBadIdentityTokenRejected:
+			return false
+		}
+	}
+	return true
+}
+
+// connectAttempt makes one pass over the server's endpoints and, on success, installs the client.
+func (c *Controller) connectAttempt(ctx context.Context, cfg *opc.Config) error {
 	// Build endpoint candidates and honor requested AuthMode. Try Anonymous across endpoints when selected.
 	var opts []opcua.Option
-	connectURL := cfg.EndpointURL
-	if eps, err := opcua.GetEndpoints(ctx, cfg.EndpointURL); err == nil {
+	connectURL := cfg.DialURL()
+	if connectURL != cfg.EndpointURL {
+		c.Log(fmt.Sprintf("[cyan]Dialing %s for endpoint %s (host override)[-]", connectURL, cfg.EndpointURL))
+	}
+	if err := c.precheck(ctx, cfg, connectURL); err != nil {
+		return err
+	}
+	if eps, err := opcua.GetEndpoints(ctx, connectURL); err == nil {
 		// Helper to inspect user token support and policyID
 		getPolicySupport := func(ep *ua.EndpointDescription) (userPID string, supportsUser, supportsAnon bool) {
 			if ep == nil {
@@ -217,7 +444,8 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 		type endpointRef struct{ ep *ua.EndpointDescription }
 		all := make([]endpointRef, 0, len(eps))
 		for _, ep := range eps {
-			if ep == nil {
+			// Skip the HTTPS/WebSocket variants of the policies; we connect over opc.tcp
+			if !opc.IsSupportedEndpoint(ep) {
 				continue
 			}
 			all = append(all, endpointRef{ep: ep})
@@ -372,16 +600,18 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 				attempted++
 				c.Log(fmt.Sprintf("[yellow]Trying Anonymous endpoint: %s / %s @ %s[-]", r.ep.SecurityPolicyURI, r.ep.SecurityMode.String(), connectURL))
 				if km != nil && strings.TrimSpace(km.appURI) != "" {
-                    c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", km.appURI, km.certURIs))
-                } else {
-                    c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", strings.TrimSpace(cfg.ApplicationURI), nil))
-                }
-                tmpCli, cerr := opc.NewClient(connectURL, optsAnon...)
+					c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", km.appURI, km.certURIs))
+				} else {
+					c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", strings.TrimSpace(cfg.ApplicationURI), nil))
+				}
+				tmpCli, cerr := opc.NewClient(connectURL, append(optsAnon, cfg.SessionOptions()...)...)
 				if cerr != nil {
 					lastErr = cerr
 					c.Log(fmt.Sprintf("[red]Create client failed (Anonymous %s/%s): %v[-]", r.ep.SecurityPolicyURI, r.ep.SecurityMode.String(), cerr))
 					continue
 				}
+				tmpCli.SetRequestRetry(cfg.RequestRetryPolicy())
+				tmpCli.ShareSession(opc.SessionKey(connectURL, r.ep.SecurityPolicyURI, r.ep.SecurityMode, "Anonymous", cfg))
 				if err := tmpCli.Connect(ctx); err != nil {
 					lastErr = err
 					c.Log(fmt.Sprintf("[red]Connect failed (Anonymous %s/%s): %v[-]", r.ep.SecurityPolicyURI, r.ep.SecurityMode.String(), err))
@@ -396,22 +626,25 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 				c.mu.Unlock()
 				tmpCli.Handler = c
 				c.Log(fmt.Sprintf("[green]Connected to %s (Anonymous, %s/%s)[-]", cfg.EndpointURL, r.ep.SecurityPolicyURI, r.ep.SecurityMode.String()))
+				c.syncApiServer()
 				if c.OnConnectionStateChange != nil {
 					c.OnConnectionStateChange(true, cfg.EndpointURL, nil)
 				}
+				c.emitConnectionEvent(EventConnectionRestored, cfg.EndpointURL, "")
+				c.usageSessionStarted()
+				go c.replayWriteQueue()
+				c.logSharedSession(tmpCli)
+				go c.loadServerIdentity(tmpCli)
+				go c.clockLoop(tmpCli)
+				go c.watchModelChanges(tmpCli)
+				go c.loadNamespaces(tmpCli)
 				return nil
 			}
 			if attempted > 0 && !tryUsername() {
 				// Do not auto-fallback to Username if user requested Anonymous explicitly
-				c.mu.Lock()
-				c.isConnecting = false
-				c.mu.Unlock()
 				if lastErr == nil {
 					lastErr = fmt.Errorf("all Anonymous candidates failed")
 				}
-				if c.OnConnectionStateChange != nil {
-					c.OnConnectionStateChange(false, cfg.EndpointURL, lastErr)
-				}
 				return lastErr
 			}
 		}
@@ -469,16 +702,18 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 				attempted++
 				c.Log(fmt.Sprintf("[yellow]Trying Username endpoint: %s / %s @ %s[-]", cand.ep.SecurityPolicyURI, cand.ep.SecurityMode.String(), connectURL))
 				if km != nil && strings.TrimSpace(km.appURI) != "" {
-                    c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", km.appURI, km.certURIs))
-                } else {
-                    c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", strings.TrimSpace(cfg.ApplicationURI), nil))
-                }
-                tmpCli, cerr := opc.NewClient(connectURL, tryOpts...)
+					c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", km.appURI, km.certURIs))
+				} else {
+					c.Log(fmt.Sprintf("[blue]Using ApplicationURI for session: %s; cert URIs: %v[-]", strings.TrimSpace(cfg.ApplicationURI), nil))
+				}
+				tmpCli, cerr := opc.NewClient(connectURL, append(tryOpts, cfg.SessionOptions()...)...)
 				if cerr != nil {
 					lastErr = cerr
 					c.Log(fmt.Sprintf("[red]Create client failed for %s / %s: %v[-]", cand.ep.SecurityPolicyURI, cand.ep.SecurityMode.String(), cerr))
 					continue
 				}
+				tmpCli.SetRequestRetry(cfg.RequestRetryPolicy())
+				tmpCli.ShareSession(opc.SessionKey(connectURL, cand.ep.SecurityPolicyURI, cand.ep.SecurityMode, "Username", cfg))
 				if err := tmpCli.Connect(ctx); err != nil {
 					lastErr = err
 					c.Log(fmt.Sprintf("[red]Connect failed for %s / %s: %v[-]", cand.ep.SecurityPolicyURI, cand.ep.SecurityMode.String(), err))
@@ -492,21 +727,24 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 				c.mu.Unlock()
 				tmpCli.Handler = c
 				c.Log(fmt.Sprintf("[green]Connected to %s (Username, %s/%s)[-]", cfg.EndpointURL, cand.ep.SecurityPolicyURI, cand.ep.SecurityMode.String()))
+				c.syncApiServer()
 				if c.OnConnectionStateChange != nil {
 					c.OnConnectionStateChange(true, cfg.EndpointURL, nil)
 				}
+				c.emitConnectionEvent(EventConnectionRestored, cfg.EndpointURL, "")
+				c.usageSessionStarted()
+				go c.replayWriteQueue()
+				c.logSharedSession(tmpCli)
+				go c.loadServerIdentity(tmpCli)
+				go c.clockLoop(tmpCli)
+				go c.watchModelChanges(tmpCli)
+				go c.loadNamespaces(tmpCli)
 				return nil
 			}
 			if attempted > 0 {
-				c.mu.Lock()
-				c.isConnecting = false
-				c.mu.Unlock()
 				if lastErr == nil {
 					lastErr = fmt.Errorf("all Username candidates failed")
 				}
-				if c.OnConnectionStateChange != nil {
-					c.OnConnectionStateChange(false, cfg.EndpointURL, lastErr)
-				}
 				return lastErr
 			}
 		}
@@ -521,29 +759,19 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 	}
 
 	// Create client (Anonymous path or fallback)
-	cli, err := opc.NewClient(connectURL, opts...)
+	cli, err := opc.NewClient(connectURL, append(opts, cfg.SessionOptions()...)...)
 	if err != nil {
-		c.mu.Lock()
-		c.isConnecting = false
-		c.mu.Unlock()
 		c.Log(fmt.Sprintf("[red]Create client failed: %v[-]", err))
-		if c.OnConnectionStateChange != nil {
-			c.OnConnectionStateChange(false, cfg.EndpointURL, err)
-		}
 		return err
 	}
 
 	// Set data change handler and connect
 	cli.Handler = c
+	cli.SetRequestRetry(cfg.RequestRetryPolicy())
+	cli.ShareSession(opc.SessionKey(connectURL, ua.SecurityPolicyURINone, ua.MessageSecurityModeNone, "Anonymous", cfg))
 	if err := cli.Connect(ctx); err != nil {
 		_ = cli.Disconnect(context.Background())
-		c.mu.Lock()
-		c.isConnecting = false
-		c.mu.Unlock()
 		c.Log(fmt.Sprintf("[red]Connect failed: %v[-]", err))
-		if c.OnConnectionStateChange != nil {
-			c.OnConnectionStateChange(false, cfg.EndpointURL, err)
-		}
 		return err
 	}
 
@@ -554,42 +782,95 @@ func (c *Controller) Connect(cfg *opc.Config) error {
 	c.isConnecting = false
 	c.mu.Unlock()
 	c.Log(fmt.Sprintf("[green]Connected to %s[-]", cfg.EndpointURL))
+	c.syncApiServer()
 	if c.OnConnectionStateChange != nil {
 		c.OnConnectionStateChange(true, cfg.EndpointURL, nil)
 	}
-	// Minimal: no extra background flows here
+	c.emitConnectionEvent(EventConnectionRestored, cfg.EndpointURL, "")
+	c.usageSessionStarted()
+	go c.replayWriteQueue()
+	c.logSharedSession(cli)
+	go c.loadServerIdentity(cli)
+	go c.clockLoop(cli)
+	go c.watchModelChanges(cli)
+	go c.loadNamespaces(cli)
 	return nil
 }
 
+// precheck resolves the host of connectURL and tries its TCP port, so DNS and reachability
+// failures are logged as such instead of surfacing from the OPC UA handshake.
+func (c *Controller) precheck(ctx context.Context, cfg *opc.Config, connectURL string) error {
+	timeout := 10 * time.Second
+	if cfg.ConnectTimeout > 0 {
+		timeout = time.Duration(cfg.ConnectTimeout * float64(time.Second))
+	}
+	pctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	p, err := opc.PrecheckEndpoint(pctx, connectURL)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]%v[-]", err))
+		return err
+	}
+	c.Log(fmt.Sprintf("[cyan]%s resolves to %s; TCP %s open (%s)[-]", p.Host, strings.Join(p.Addrs, ", "), p.Dialed,
+		(p.ResolveTime + p.ConnectTime).Round(time.Millisecond)))
+	return nil
+}
+
+// logSharedSession notes when Connect reused the session of another connection in this process.
+func (c *Controller) logSharedSession(cli *opc.Client) {
+	if shared, users := cli.SharedSession(); shared {
+		c.Log(fmt.Sprintf("[cyan]Reusing the existing session to %s (%d connections share it)[-]", cli.Endpoint(), users))
+	}
+}
+
 func (c *Controller) Disconnect() {
+	c.disconnect(false)
+}
+
+// Reconnect closes the session and opens a new one with cfg, keeping the watch list: its items
+// are monitored again once the new session is up. It applies settings only sent when a session
+// is created and recovers connections the stack could not restore.
+func (c *Controller) Reconnect(cfg *opc.Config) error {
+	c.disconnect(true)
+	return c.Connect(cfg)
+}
+
+// disconnect closes the session and clears its state; keepWatches leaves the watch list for the
+// next session to restore (see restoreWatchList).
+func (c *Controller) disconnect(keepWatches bool) {
+	c.cancelScheduledWrites("disconnected")
+	c.StopAllSimulations("disconnected")
 	c.clientLifecycleMutex.Lock()
 	if c.clientCancel != nil {
 		c.clientCancel()
 		c.clientCancel = nil
 	}
+	endpoint := ""
 	if c.client != nil {
+		endpoint = c.client.Endpoint()
 		_ = c.client.Disconnect(context.Background())
 		c.client = nil
 	}
 	c.clientCtx = nil
 	c.clientLifecycleMutex.Unlock()
 
+	c.usageSessionEnded()
 	c.mu.Lock()
+	wasConnected := c.isConnected
 	c.isConnected = false
 	c.isConnecting = false
+	c.serverIdentity = nil
+	c.clockOffset = nil
+	c.offline = nil
+	c.detailNodeID, c.detailDataType, c.detailSub = "", "", nil
+	c.pollClient, c.pollOnly = nil, false
+	c.linkDown = false
 	c.mu.Unlock()
 
-	// Close and recreate API broadcast channel to notify Hub and future sessions get a fresh channel
-	c.mu.Lock()
-	oldBroadcast := c.ApiBroadcastChan
-	c.ApiBroadcastChan = make(chan *WatchItem, 64)
-	c.mu.Unlock()
-	if oldBroadcast != nil {
-		close(oldBroadcast)
-	}
-
 	// Clear all watches (also closes any active subscriptions) and notify UI
-	c.RemoveAllWatches()
+	if !keepWatches {
+		c.RemoveAllWatches()
+	}
 
 	// Clear address space caches and browsing flags
 	c.addressSpaceMutex.Lock()
@@ -600,8 +881,15 @@ func (c *Controller) Disconnect() {
 	c.browsingNodes = make(map[string]bool)
 	c.noChildrenCached = make(map[string]bool)
 	c.mu.Unlock()
+	c.clearSearchCache(true)
+	c.clearNodeMeta()
+	c.clearEnumTypes()
 
 	c.Log("[yellow]Disconnected[-]")
+	if wasConnected {
+		c.emitConnectionEvent(EventConnectionLost, endpoint, "disconnected")
+	}
+	c.syncApiServer()
 	if c.OnConnectionStateChange != nil {
 		c.OnConnectionStateChange(false, "", nil)
 	}
@@ -612,24 +900,25 @@ func (c *Controller) Disconnect() {
 
 // Shutdown stops the API server (if running) and disconnects the OPC UA client, ensuring all state is cleared.
 func (c *Controller) Shutdown() {
-	// Stop API server
-	if c.apiServerCancel != nil {
-		c.apiServerCancel()
-	}
-	c.apiServer = nil
-	c.apiServerCtx = nil
-	c.apiServerCancel = nil
-
 	// Disconnect OPC UA client and clear state
 	c.Disconnect()
-}
 
-func (c *Controller) GetApiBroadcastChan() chan *WatchItem { return c.ApiBroadcastChan }
+	// Stop API server
+	c.apiMu.Lock()
+	c.stopApiServerLocked()
+	c.apiMu.Unlock()
+
+	// Flush and close the protocol trace file
+	if t := opc.SetTracer(nil); t != nil {
+		_ = t.Close()
+	}
+}
 
 func (c *Controller) GetClientContext() context.Context { return c.clientCtx }
 
 // ... (rest of the code remains the same)
-func (c *Controller) Browse(parentID string) {
+// Browse reads the children of parentID into the address space cache; ctx bounds the request.
+func (c *Controller) Browse(ctx context.Context, parentID string) {
 	// Prevent duplicate browse for the same node
 	c.mu.Lock()
 	if c.browsingNodes[parentID] {
@@ -637,12 +926,12 @@ func (c *Controller) Browse(parentID string) {
 		return
 	}
 	c.browsingNodes[parentID] = true
-	ctx := c.clientCtx
+	session := c.clientCtx
 	client := c.client
 	c.mu.Unlock()
 
 	// Validate state
-	if client == nil || ctx == nil {
+	if client == nil || session == nil {
 		c.Log(fmt.Sprintf("[red]Browse aborted for %s: client not connected[-]", parentID))
 		c.mu.Lock()
 		c.browsingNodes[parentID] = false
@@ -661,7 +950,7 @@ func (c *Controller) Browse(parentID string) {
 	}
 
 	// Perform browse with timeout
-	browseCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	browseCtx, cancel := c.opContext(ctx, c.timeouts().Browse)
 	defer cancel()
 	refs, err := client.Browse(browseCtx, nID)
 	if err != nil {
@@ -672,6 +961,8 @@ func (c *Controller) Browse(parentID string) {
 		return
 	}
 
+	c.usageCount(func(u *opc.UsageStats) { u.NodesBrowsed += int64(len(refs)) })
+
 	// Build children list and node entries
 	children := make([]string, 0, len(refs))
 	nodes := make(map[string]*AddressSpaceNode, len(refs))
@@ -763,35 +1054,46 @@ func (c *Controller) GetNode(id string) *AddressSpaceNode {
 // CollectVariableNodes collects Variable-class nodes under the given parent. If parentID is empty,
 // it attempts to walk the entire known address space starting from RootFolder (i=84).
 // It performs best-effort browsing on demand. It respects connection state and client context.
-func (c *Controller) CollectVariableNodes(parentID string, recursive bool) ([]*ExportTag, error) {
+func (c *Controller) CollectVariableNodes(ctx context.Context, parentID string, recursive bool) ([]*ExportTag, error) {
+	return c.CollectVariableNodesProgress(ctx, parentID, recursive, c.timeouts().Export, nil)
+}
+
+// CollectVariableNodesProgress is CollectVariableNodes with a caller-chosen traversal timeout
+// and an optional progress callback reporting visited nodes and variables found so far.
+func (c *Controller) CollectVariableNodesProgress(ctx context.Context, parentID string, recursive bool, timeout time.Duration, progress func(visited, found int)) ([]*ExportTag, error) {
 	// Connection gating
 	c.mu.RLock()
-	ctx := c.clientCtx
+	session := c.clientCtx
 	cli := c.client
+	offline := c.offline != nil
 	c.mu.RUnlock()
-	if cli == nil || ctx == nil {
+	if (cli == nil && !offline) || session == nil {
 		return nil, fmt.Errorf("not connected")
 	}
+	ctx, cancel := c.opContext(ctx, timeout)
+	defer cancel()
 
 	// Iterative BFS to avoid deep recursion and leaks
 	queue := make([]string, 0, 64)
 	visited := make(map[string]bool)
+	paths := make(map[string]string) // browse path like "/Objects/Folder/Var"
 	if parentID == "" {
 		queue = append(queue, "i=84") // RootFolder
 	} else {
 		queue = append(queue, parentID)
+		paths[parentID] = strings.TrimSuffix(c.nodePath(parentID), "/")
 	}
 
 	tags := make([]*ExportTag, 0, 256)
-	deadline := time.After(30 * time.Second) // safeguard
 
 	for len(queue) > 0 {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// Time-guard to prevent excessive blocking
+				return tags, fmt.Errorf("export traversal timeout")
+			}
 			return tags, ctx.Err()
-		case <-deadline:
-			// Time-guard to prevent excessive blocking
-			return tags, fmt.Errorf("export traversal timeout")
 		default:
 		}
 
@@ -804,9 +1106,9 @@ func (c *Controller) CollectVariableNodes(parentID string, recursive bool) ([]*E
 
 		// Ensure we have children cached; if unknown and recursive, try to browse
 		ch := c.GetAddressSpaceChildren(id)
-		if recursive && len(ch) == 0 {
+		if recursive && len(ch) == 0 && cli != nil {
 			// best effort browse to populate
-			c.Browse(id)
+			c.Browse(ctx, id)
 			// small wait to allow browse to complete
 			time.Sleep(20 * time.Millisecond)
 			ch = c.GetAddressSpaceChildren(id)
@@ -816,17 +1118,14 @@ func (c *Controller) CollectVariableNodes(parentID string, recursive bool) ([]*E
 		if n := c.GetNode(id); n != nil {
 			if n.NodeClass == ua.NodeClassVariable {
 				// Best-effort attributes
-				var dt, desc string
-				if attrs, err := c.ReadNodeAttributes(id); err == nil && attrs != nil {
-					dt = attrs.DataType
-					desc = attrs.Description
+				tag := &ExportTag{NodeID: id, Name: n.Name, Path: paths[id]}
+				if attrs, err := c.ReadNodeAttributes(ctx, id); err == nil && attrs != nil {
+					tag.DataType = attrs.DataType
+					tag.Description = attrs.Description
+					tag.BrowseName = attrs.BrowseName
+					tag.NamespaceURI = attrs.NamespaceURI
 				}
-				tags = append(tags, &ExportTag{
-					NodeID:      id,
-					Name:        n.Name,
-					DataType:    dt,
-					Description: desc,
-				})
+				tags = append(tags, tag)
 			}
 		}
 
@@ -835,20 +1134,31 @@ func (c *Controller) CollectVariableNodes(parentID string, recursive bool) ([]*E
 			for _, child := range ch {
 				if !visited[child] {
 					queue = append(queue, child)
+					if _, ok := paths[child]; !ok {
+						if n := c.GetNode(child); n != nil {
+							paths[child] = paths[id] + "/" + n.Name
+						}
+					}
 				}
 			}
 		}
+		if progress != nil {
+			progress(len(visited), len(tags))
+		}
 	}
 
 	return tags, nil
 }
 
-func (c *Controller) AddWatch(nodeID string) {
+// AddWatch adds nodeID to the watch list and starts monitoring it; ctx bounds the reads and
+// subscription changes involved.
+func (c *Controller) AddWatch(ctx context.Context, nodeID string) {
 	// Validate connection first
 	c.mu.RLock()
 	cli := c.client
+	offline := c.offline != nil
 	c.mu.RUnlock()
-	if cli == nil {
+	if cli == nil && !offline {
 		c.Log(fmt.Sprintf("[red]AddWatch failed: not connected (node %s)[-]", nodeID))
 		return
 	}
@@ -859,26 +1169,77 @@ func (c *Controller) AddWatch(nodeID string) {
 		c.mu.Unlock()
 		return
 	}
-	wi := &WatchItem{NodeID: nodeID}
+	// A node shown in the details panel is already monitored in the default subscription; the
+	// watch takes over its item unless the node has a rate group of its own
+	wi := &WatchItem{NodeID: nodeID, RateMs: c.rateForLocked(nodeID), Dedup: c.dedupConfiguredLocked(nodeID)}
+	if wi.RateMs == 0 {
+		wi.subHandle = c.takeDetailSubLocked(nodeID)
+	}
 	c.watchItems[nodeID] = wi
+	adopted := wi.subHandle != nil
+	watched := len(c.watchItems)
 	c.mu.Unlock()
+	c.usageCount(func(u *opc.UsageStats) { u.MaxWatched = max(u.MaxWatched, watched) })
 
 	// Populate fields from attributes (best-effort)
-	if attrs, err := c.ReadNodeAttributes(nodeID); err == nil && attrs != nil {
+	if attrs, err := c.ReadNodeAttributes(ctx, nodeID); err == nil && attrs != nil {
 		c.mu.Lock()
 		if it, ok := c.watchItems[nodeID]; ok {
 			it.Name = attrs.Name
 			it.DataType = attrs.DataType
 			it.Value = attrs.Value
-			it.Timestamp = time.Now().Format("15:04:05.000")
+			it.ValueTyped = attrs.ValueTyped
+			it.UAType = attrs.UAType
+			it.EnumName, it.Flags = attrs.EnumName, attrs.Flags
+			it.SourceTimestamp = attrs.SourceTimestamp
+			it.ServerTimestamp = attrs.ServerTimestamp
+			it.Timestamp = attrs.Timestamp
 		}
 		c.mu.Unlock()
 	}
+	c.mu.Lock()
+	var alert *WatchItem
+	if it, ok := c.watchItems[nodeID]; ok {
+		c.applyForceLocked(it)
+		if it.Golden = c.goldenForLocked(nodeID); c.checkGoldenLocked(it) {
+			msg := *it
+			msg.subHandle = nil
+			alert = &msg
+		}
+	}
+	c.mu.Unlock()
+	if alert != nil {
+		c.raiseGoldenAlert(alert)
+	}
 
-	// Start monitoring value changes
-	sub, err := cli.MonitorItem(nodeID)
-	if err != nil {
-		c.Log(fmt.Sprintf("[red]Failed to monitor %s: %v[-]", nodeID, err))
+	// Start monitoring value changes; offline values come from the recording replay
+	if !offline {
+		subCtx, cancel := c.opContext(ctx, c.timeouts().Write)
+		if err := cli.SetPublishInterval(subCtx, c.intervals().Publish); err != nil {
+			c.Log(fmt.Sprintf("[yellow]Failed to set publishing interval: %v[-]", err))
+		}
+		if err := cli.SetSubscriptionTuning(subCtx, c.subscriptionTuning()); err != nil {
+			c.Log(fmt.Sprintf("[yellow]Failed to set subscription parameters: %v[-]", err))
+		}
+		cancel()
+	}
+	c.mu.RLock()
+	poll := !offline && !adopted && c.pollConfiguredLocked(nodeID)
+	c.mu.RUnlock()
+	if offline {
+		c.Log(fmt.Sprintf("[green]Watching %s (offline)[-]", nodeID))
+	} else if adopted {
+		c.Log(fmt.Sprintf("[green]Monitoring %s started[-]", nodeID))
+	} else if poll {
+		c.startPolling(nodeID, cli)
+		c.Log(fmt.Sprintf("[green]Polling %s every %v[-]", nodeID, c.intervals().Poll))
+	} else if sub, err := cli.MonitorItemAt(nodeID, rateInterval(wi.RateMs)); err != nil {
+		if c.pollFallback(err) {
+			c.startPolling(nodeID, cli)
+			c.Log(fmt.Sprintf("[yellow]Cannot monitor %s (%v); polling every %v instead[-]", nodeID, err, c.intervals().Poll))
+		} else {
+			c.Log(fmt.Sprintf("[red]Failed to monitor %s: %v[-]", nodeID, err))
+		}
 	} else {
 		c.mu.Lock()
 		if it, ok := c.watchItems[nodeID]; ok {
@@ -898,21 +1259,17 @@ func (c *Controller) AddWatch(nodeID string) {
 	sort.Slice(items, func(i, j int) bool { return items[i].NodeID < items[j].NodeID })
 	cb := c.OnWatchListUpdate
 	// Prepare API broadcast of the newly added item (shallow copy)
+	var added *WatchItem
 	if it, ok := c.watchItems[nodeID]; ok {
 		msg := *it
-		// do not include subHandle in broadcast
-		msg.subHandle = nil
-		broadcast := c.ApiBroadcastChan
-		go func(m *WatchItem, ch chan *WatchItem) {
-			select {
-			case ch <- m:
-			default:
-			}
-		}(&msg, broadcast)
+		added = &msg
 	}
 	c.mu.RUnlock()
+	if added != nil {
+		c.broadcastWatch(added)
+	}
 	if cb != nil {
-		cb(items)
+		cb(WatchListUpdate{Items: items})
 	}
 }
 
@@ -931,57 +1288,47 @@ func (c *Controller) SetApiStarter(starter ApiServerStarter) {
 // SetApiStatus allows the UI to bind to a status string owned by the controller.
 func (c *Controller) SetApiStatus(ptr *string) { c.apiStatus = ptr }
 
-// UpdateApiServerState starts/stops the API server based on cfg.ApiEnabled and port changes.
+// UpdateApiServerState starts/stops the API server based on cfg.ApiEnabled, cfg.KeepApiRunning
+// and the connection state, and restarts it when the port changed.
 func (c *Controller) UpdateApiServerState(cfg *opc.Config) {
 	// Save the latest config
+	c.mu.Lock()
 	c.currentConfig = cfg
+	c.mu.Unlock()
 
-	if c.apiStarter == nil || c.apiStatus == nil {
-		return
-	}
-	// If disabled, ensure server is stopped
-	if cfg == nil || !cfg.ApiEnabled {
-		if c.apiServerCancel != nil {
-			c.apiServerCancel()
-		}
-		c.apiServer = nil
-		c.apiServerCtx = nil
-		c.apiServerCancel = nil
-		if c.apiStatus != nil {
-			*c.apiStatus = "API Disabled"
-		}
-		return
-	}
+	c.syncApiServer()
+}
 
-	// If enabled and server not running, start it
-	needStart := c.apiServer == nil
-	// Or restart if port changed
-	if !needStart && c.currentConfig != nil {
-		// Compare server address via cfg.ApiPort; we don't have a direct getter from http.Server
-		// so conservatively restart on any Update when enabled.
-		needStart = true
-	}
-	if needStart {
-		if c.apiServerCancel != nil {
-			c.apiServerCancel()
-		}
-		ctx, cancel := context.WithCancel(context.Background())
-		c.apiServerCtx = ctx
-		c.apiServerCancel = cancel
-		c.apiServer = c.apiStarter(ctx, c, c.apiStatus, cfg)
-	}
+// timeouts returns the effective per-operation timeouts. Callers must not hold c.mu.
+func (c *Controller) timeouts() opc.Timeouts {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentConfig.Timeouts()
 }
 
 func (c *Controller) HandleDataChange(nodeID string, dv *ua.DataValue) {
 	c.mu.Lock()
 	item, ok := c.watchItems[nodeID]
+	detail := c.OnDetailValueUpdate
+	if nodeID != c.detailNodeID {
+		detail = nil
+	}
 	if !ok {
+		dataType := c.detailDataType
 		c.mu.Unlock()
+		if detail != nil {
+			detail(nodeID, detailValue(dv, dataType, c.cachedDataTypeValues(dataType)))
+		}
 		return
 	}
+	// Servers republishing a sample with new timestamps only are filtered out for the sinks
+	repeat := item.Dedup && dv != nil && item.lastDataValue != nil && sameDataValue(item.lastDataValue, dv)
+	if repeat {
+		item.Suppressed++
+	}
 	if dv == nil {
 		item.Value = "<error: no data>"
-		item.Timestamp = time.Now().Format("15:04:05.000")
+		item.Timestamp = c.formatDisplayTimestamp(c.currentConfig, time.Now())
 		item.Severity = "Bad"
 		// do not access dv fields when dv is nil
 	} else {
@@ -990,7 +1337,11 @@ func (c *Controller) HandleDataChange(nodeID string, dv *ua.DataValue) {
 		} else {
 			item.Value = "<nil>"
 		}
-		item.Timestamp = time.Now().Format("15:04:05.000")
+		item.ValueTyped = typedValue(dv.Value)
+		item.UAType = uaTypeName(dv.Value)
+		item.lastDataValue = dv
+		appendReceived(item, dv, time.Now())
+		c.applyTimestamps(c.currentConfig, item, dv)
 		sev, symName, subCode, structChanged, semChanged, infoBits, rawCode := decodeStatusCode(dv.Status)
 		item.Severity = sev
 		item.SymbolicName = symName
@@ -999,30 +1350,44 @@ func (c *Controller) HandleDataChange(nodeID string, dv *ua.DataValue) {
 		item.SemanticsChanged = semChanged
 		item.InfoBits = infoBits
 		item.RawCode = rawCode
+		if item.Overflow = isOverflow(dv.Status); item.Overflow {
+			item.OverflowCount++
+		}
 	}
-	// Snapshot for UI
-	items := make([]*WatchItem, 0, len(c.watchItems))
-	for _, wi := range c.watchItems {
-		items = append(items, wi)
+	c.applyForceLocked(item)
+	names := item.ValueTyped
+	if !item.Forced && item.lastDataValue != nil && item.lastDataValue.Value != nil {
+		// OptionSet structures are decoded from the variant
+		names = item.lastDataValue.Value.Value()
 	}
-	sort.Slice(items, func(i, j int) bool { return items[i].NodeID < items[j].NodeID })
-	update := c.OnWatchListUpdate
+	item.EnumName, item.Flags = c.valueNames(item.DataType, names)
+	alert := c.checkGoldenLocked(item)
 	// Prepare API broadcast message (shallow copy)
 	msg := *item
 	msg.subHandle = nil
-	broadcast := c.ApiBroadcastChan
 	c.mu.Unlock()
 
-	// UI update
-	if update != nil {
-		update(items)
+	if alert {
+		c.raiseGoldenAlert(&msg)
 	}
-	// Non-blocking API broadcast
-	select {
-	case broadcast <- &msg:
-	default:
-		// drop if channel is full to avoid blocking
+	if msg.Overflow && msg.OverflowCount == 1 {
+		c.Log(fmt.Sprintf("[yellow]Queue overflow on %s: the server discarded samples; use a longer sampling interval or a larger queue[-]", nodeID))
+	}
+	c.captureSample(&msg)
+	if c.OnValueChange != nil && !repeat {
+		c.OnValueChange(msg)
+	}
+
+	// UI update is coalesced by the watch pump
+	c.markWatchDirty(nodeID)
+	if detail != nil {
+		detail(nodeID, FormatEnumValue(msg.Value, msg.EnumName+msg.Flags))
 	}
+	if repeat {
+		return
+	}
+	// Non-blocking API broadcast; each feed keeps the newest value per node
+	c.broadcastWatch(&msg)
 }
 
 func (c *Controller) RemoveWatch(nodeID string) {
@@ -1035,7 +1400,12 @@ func (c *Controller) RemoveWatch(nodeID string) {
 		return
 	}
 	subToClose = item.subHandle
+	if nodeID == c.detailNodeID && c.detailSub == nil {
+		// Still shown in the details panel: keep the monitored item for the live value
+		c.detailSub, subToClose = subToClose, nil
+	}
 	delete(c.watchItems, nodeID)
+	delete(c.forces, nodeID)
 	// Prepare snapshot for UI update after unlock
 	itemsToUpdate := make([]*WatchItem, 0, len(c.watchItems))
 	for _, wi := range c.watchItems {
@@ -1055,7 +1425,7 @@ func (c *Controller) RemoveWatch(nodeID string) {
 
 	// Notify UI of updated watch list
 	if updateFunc != nil {
-		updateFunc(itemsToUpdate)
+		updateFunc(WatchListUpdate{Items: itemsToUpdate})
 	}
 }
 
@@ -1063,10 +1433,16 @@ func (c *Controller) RemoveAllWatches() {
 	c.mu.Lock()
 	// collect subs to close and clear map
 	subs := make([]*opc.Subscription, 0, len(c.watchItems))
-	for _, item := range c.watchItems {
+	for id, item := range c.watchItems {
+		if id == c.detailNodeID && c.detailSub == nil {
+			c.detailSub = item.subHandle
+			continue
+		}
 		subs = append(subs, item.subHandle)
 	}
 	c.watchItems = make(map[string]*WatchItem)
+	c.unresolvedWatches = nil
+	c.forces = nil
 	updateFunc := c.OnWatchListUpdate
 	c.mu.Unlock()
 
@@ -1080,7 +1456,7 @@ func (c *Controller) RemoveAllWatches() {
 
 	// notify UI
 	if updateFunc != nil {
-		updateFunc([]*WatchItem{})
+		updateFunc(WatchListUpdate{Items: []*WatchItem{}})
 	}
 
 }
@@ -1116,26 +1492,13 @@ func convertStringToType(valueStr, dataType string) (interface{}, error) {
 		if strings.HasPrefix(strings.ToLower(s), "ascii:") || strings.HasPrefix(strings.ToLower(s), "text:") {
 			return []byte(strings.TrimSpace(s[strings.Index(s, ":")+1:])), nil
 		}
-		// Explicit hex: prefix allowed; strip it then continue normalization
+		// Explicit hex: prefix (sent by the UI's byte editor) must be valid hex
 		if strings.HasPrefix(strings.ToLower(s), "hex:") {
-			s = strings.TrimSpace(s[4:])
-		}
-		// Remove separators and 0x/0X prefixes
-		// First split on separators, then strip 0x and rejoin
-		parts := strings.FieldsFunc(s, func(r rune) bool {
-			return r == ' ' || r == ',' || r == ';' || r == ':'
-		})
-		if len(parts) > 0 {
-			for i := range parts {
-				parts[i] = strings.TrimPrefix(strings.TrimPrefix(parts[i], "0x"), "0X")
-			}
-			s = strings.Join(parts, "")
+			return ParseHexBytes(s[4:])
 		}
-		// If even length and valid hex, decode as bytes
-		if len(s)%2 == 0 {
-			if b, err := hex.DecodeString(s); err == nil {
-				return b, nil
-			}
+		// Without a prefix, valid hex is decoded as bytes
+		if b, err := ParseHexBytes(s); err == nil {
+			return b, nil
 		}
 		// Fallback: treat as raw ASCII/UTF-8 bytes
 		return []byte(valueStr), nil
@@ -1211,11 +1574,8 @@ func convertStringToType(valueStr, dataType string) (interface{}, error) {
 		return t, nil
 	case "localizedtext":
 		// Accept "text" or "locale|text"
-		parts := strings.SplitN(valueStr, "|", 2)
-		if len(parts) == 2 {
-			return ua.LocalizedText{Locale: strings.TrimSpace(parts[0]), Text: strings.TrimSpace(parts[1])}, nil
-		}
-		return ua.LocalizedText{Text: valueStr}, nil
+		locale, text := splitLocalizedText(valueStr)
+		return ua.LocalizedText{Locale: locale, Text: text}, nil
 	case "string":
 		return valueStr, nil
 	default:
@@ -1223,458 +1583,535 @@ func convertStringToType(valueStr, dataType string) (interface{}, error) {
 	}
 }
 
-func (c *Controller) WriteValue(nodeID, dataType, valueStr string) {
+// WriteValue writes valueStr to nodeID in the background, converted to the node's DataType and
+// verified by reading it back. ctx bounds the OPC UA calls, so callers that return before the
+// write completes pass a context that outlives them. Log lines of the write carry the request
+// ID of ctx (see WithRequestID).
+func (c *Controller) WriteValue(ctx context.Context, nodeID, dataType, valueStr string) {
+	log := c.logFor(ctx)
+	if c.IsOffline() {
+		log(fmt.Sprintf("[red]OFFLINE mode: write to %s refused[-]", nodeID))
+		c.recordWrite(nodeID, dataType, valueStr, errors.New("writes are disabled in offline mode"))
+		return
+	}
+	if err := c.CheckWriteAllowed(nodeID); err != nil {
+		log(fmt.Sprintf("[red]Write refused: %v[-]", err))
+		c.recordWrite(nodeID, dataType, valueStr, err)
+		return
+	}
+	if c.queueWrite(log, nodeID, dataType, valueStr) {
+		return
+	}
 	c.mu.RLock()
 	if c.client == nil {
-		c.Log("[red]Not connected. Cannot write value[-]")
+		log("[red]Not connected. Cannot write value[-]")
 		c.mu.RUnlock()
+		c.recordWrite(nodeID, dataType, valueStr, errors.New("not connected"))
 		return
 	}
 	client := c.client
 	c.mu.RUnlock()
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				c.Log(fmt.Sprintf("[red]WriteValue panic recovered for %s: %v[-]", nodeID, r))
-			}
-		}()
+	go c.writeValueNow(ctx, client, log, nodeID, dataType, valueStr)
+}
 
-		// Basic validation of NodeID format for clearer error logging
-		if _, err := ua.ParseNodeID(nodeID); err != nil {
-			c.Log(fmt.Sprintf("[red]Invalid NodeID '%s': %v[-]", nodeID, err))
-			return
+// writeValueNow performs a write started by WriteValue and returns when it is done.
+func (c *Controller) writeValueNow(ctx context.Context, client *opc.Client, log func(string), nodeID, dataType, valueStr string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log(fmt.Sprintf("[red]WriteValue panic recovered for %s: %v[-]", nodeID, r))
 		}
+	}()
+	result := errWriteNotDone
+	defer func() { c.recordWrite(nodeID, dataType, valueStr, result) }()
 
-		// Read the authoritative DataType/ValueRank from server to avoid type mismatch
-		serverDT := ""
-		serverVR := -1
-		if a, err := c.ReadNodeAttributes(nodeID); err == nil && a != nil {
-			// Gate on write access
-			if !strings.Contains(strings.ToLower(a.AccessLevel), "write") {
-				c.Log(fmt.Sprintf("[red]Node %s is not writable (AccessLevel=%s). Abort write.[-]", nodeID, a.AccessLevel))
+	// Basic validation of NodeID format for clearer error logging
+	if _, err := ua.ParseNodeID(nodeID); err != nil {
+		log(fmt.Sprintf("[red]Invalid NodeID '%s': %v[-]", nodeID, err))
+		return
+	}
+
+	// Read the authoritative DataType/ValueRank from server to avoid type mismatch
+	serverDT := ""
+	serverVR := -1
+	if a, err := c.ReadNodeAttributes(ctx, nodeID); err == nil && a != nil {
+		// Gate on write access
+		if !a.AccessLevelKnown {
+			if c.strictAccessLevel() {
+				log(fmt.Sprintf("[red]AccessLevel of %s is unknown (%s) and strict mode is on. Abort write.[-]", nodeID, a.AttributeStatus["AccessLevel"]))
 				return
 			}
-			if a.DataType != "" {
-				serverDT = a.DataType
-			}
-			serverVR = a.ValueRank
+			log(fmt.Sprintf("[yellow]AccessLevel of %s is unknown (%s); letting the server decide.[-]", nodeID, a.AttributeStatus["AccessLevel"]))
+		} else if !strings.Contains(strings.ToLower(a.AccessLevel), "write") {
+			log(fmt.Sprintf("[red]Node %s is not writable (AccessLevel=%s). Abort write.[-]", nodeID, a.AccessLevel))
+			return
 		}
-		if serverDT != "" {
-			if !strings.EqualFold(dataType, serverDT) {
-				c.Log(fmt.Sprintf("[yellow]Overriding provided DataType '%s' with server-reported '%s'[-]", dataType, serverDT))
-			}
-			dataType = serverDT
-		}
-		if serverVR >= 0 {
-			c.Log(fmt.Sprintf("[yellow]Server reports ValueRank=%d (array). Input will be parsed as an array.[-]", serverVR))
-		}
-		c.Log(fmt.Sprintf("[cyan]Resolved DataType=%s, ValueRank=%d[-]", dataType, serverVR))
-
-		// Probe actual variant type by reading current value (helps when attribute DataType is misleading)
-		var preferScalarGoType reflect.Kind
-		if serverVR < 0 { // only meaningful for scalar
-			func() {
-				ctx0, cancel0 := context.WithTimeout(context.Background(), 2*time.Second)
-				defer cancel0()
-				// read only Value attribute
-				vals, rerr := client.ReadAttributes(ctx0, nodeID, ua.AttributeIDValue)
-				if rerr == nil && len(vals) == 1 && vals[0] != nil && vals[0].Value != nil {
-					cur := vals[0].Value.Value()
-					if cur != nil {
-						preferScalarGoType = reflect.TypeOf(cur).Kind()
-						c.Log(fmt.Sprintf("[cyan]Actual current Value GoType=%T, Kind=%s, Val=%v[-]", cur, preferScalarGoType, cur))
-					}
-				}
-			}()
-		}
-
-		// If array is expected, parse CSV or bracketed input into a typed slice
-		var writeValue interface{}
-		var err error
-		if serverVR >= 0 { // array or matrix
-			// normalize input like "[1,2,3]" or "1,2,3"
-			s := strings.TrimSpace(valueStr)
-			s = strings.TrimPrefix(s, "[")
-			s = strings.TrimSuffix(s, "]")
-			parts := strings.Split(s, ",")
-			// helper to trim each
-			trim := func(ss []string) []string {
-				out := make([]string, 0, len(ss))
-				for _, p := range ss {
-					t := strings.TrimSpace(p)
-					if t != "" {
-						out = append(out, t)
-					}
+		if a.DataType != "" {
+			serverDT = a.DataType
+		}
+		serverVR = a.ValueRank
+	}
+	if serverDT != "" {
+		if !strings.EqualFold(dataType, serverDT) {
+			log(fmt.Sprintf("[yellow]Overriding provided DataType '%s' with server-reported '%s'[-]", dataType, serverDT))
+		}
+		dataType = serverDT
+	}
+	if serverVR >= 0 {
+		log(fmt.Sprintf("[yellow]Server reports ValueRank=%d (array). Input will be parsed as an array.[-]", serverVR))
+	}
+	log(fmt.Sprintf("[cyan]Resolved DataType=%s, ValueRank=%d[-]", dataType, serverVR))
+	// Enumerations are written as Int32; symbolic names are replaced by their values
+	if enum := c.EnumType(ctx, dataType); enum != nil {
+		resolved, err := enum.resolveInput(valueStr, serverVR >= 0)
+		if err != nil {
+			log(fmt.Sprintf("[red]%v[-]", err))
+			result = err
+			return
+		}
+		if resolved != valueStr {
+			log(fmt.Sprintf("[cyan]Enumeration value %s is written as %s[-]", valueStr, resolved))
+		}
+		valueStr, dataType = resolved, "Int32"
+	}
+
+	// Probe actual variant type by reading current value (helps when attribute DataType is misleading)
+	var preferScalarGoType reflect.Kind
+	var current interface{}
+	if serverVR < 0 { // only meaningful for scalar
+		func() {
+			ctx0, cancel0 := c.opContext(ctx, c.timeouts().Read)
+			defer cancel0()
+			// read only Value attribute
+			vals, rerr := client.ReadAttributes(ctx0, nodeID, ua.AttributeIDValue)
+			if rerr == nil && len(vals) == 1 && vals[0] != nil && vals[0].Value != nil {
+				cur := vals[0].Value.Value()
+				current = cur
+				if cur != nil {
+					preferScalarGoType = reflect.TypeOf(cur).Kind()
+					log(fmt.Sprintf("[cyan]Actual current Value GoType=%T, Kind=%s, Val=%v[-]", cur, preferScalarGoType, cur))
 				}
-				return out
 			}
-			items := trim(parts)
-			if len(items) == 0 {
-				c.Log("[red]Empty array input for array-typed node[-]")
-				return
+		}()
+	}
+
+	// OptionSets take a mask, bit names or flags and are written as the unsigned integer type of
+	// the current value, or as an OptionSet structure
+	var structured interface{}
+	if opts := c.OptionSetType(ctx, dataType); opts != nil && serverVR < 0 {
+		mask, err := opts.Parse(valueStr)
+		if err != nil {
+			log(fmt.Sprintf("[red]%v[-]", err))
+			result = err
+			return
+		}
+		log(fmt.Sprintf("[cyan]Option set value %s is written as %d[-]", valueStr, mask))
+		valueStr = strconv.FormatUint(mask, 10)
+		switch current.(type) {
+		case uint8:
+			dataType = "Byte"
+		case uint16:
+			dataType = "UInt16"
+		case uint64:
+			dataType = "UInt64"
+		case *ua.ExtensionObject:
+			structured = ua.NewExtensionObject(opts.structValue(mask))
+		default:
+			dataType = "UInt32"
+		}
+	}
+
+	// If array is expected, parse CSV or bracketed input into a typed slice
+	var writeValue interface{}
+	var err error
+	if serverVR >= 0 { // array or matrix
+		// normalize input like "[1,2,3]" or "1,2,3"
+		s := strings.TrimSpace(valueStr)
+		s = strings.TrimPrefix(s, "[")
+		s = strings.TrimSuffix(s, "]")
+		parts := strings.Split(s, ",")
+		// helper to trim each
+		trim := func(ss []string) []string {
+			out := make([]string, 0, len(ss))
+			for _, p := range ss {
+				t := strings.TrimSpace(p)
+				if t != "" {
+					out = append(out, t)
+				}
 			}
-			dt := strings.ToLower(strings.TrimSpace(dataType))
-			switch dt {
-			case "float", "float32":
-				arr := make([]float32, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseFloat(it, 32)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as float32: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, float32(v))
+			return out
+		}
+		items := trim(parts)
+		if len(items) == 0 {
+			log("[red]Empty array input for array-typed node[-]")
+			return
+		}
+		dt := strings.ToLower(strings.TrimSpace(dataType))
+		switch dt {
+		case "float", "float32":
+			arr := make([]float32, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseFloat(it, 32)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as float32: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "double", "float64":
-				arr := make([]float64, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseFloat(it, 64)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as float64: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, v)
+				arr = append(arr, float32(v))
+			}
+			writeValue = arr
+		case "double", "float64":
+			arr := make([]float64, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseFloat(it, 64)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as float64: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "int16":
-				arr := make([]int16, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseInt(it, 10, 16)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as int16: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, int16(v))
+				arr = append(arr, v)
+			}
+			writeValue = arr
+		case "int16":
+			arr := make([]int16, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseInt(it, 10, 16)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as int16: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "uint16":
-				arr := make([]uint16, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseUint(it, 10, 16)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as uint16: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, uint16(v))
+				arr = append(arr, int16(v))
+			}
+			writeValue = arr
+		case "uint16":
+			arr := make([]uint16, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseUint(it, 10, 16)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as uint16: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "int32":
-				arr := make([]int32, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseInt(it, 10, 32)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as int32: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, int32(v))
+				arr = append(arr, uint16(v))
+			}
+			writeValue = arr
+		case "int32":
+			arr := make([]int32, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseInt(it, 10, 32)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as int32: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "uint32":
-				arr := make([]uint32, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseUint(it, 10, 32)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as uint32: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, uint32(v))
+				arr = append(arr, int32(v))
+			}
+			writeValue = arr
+		case "uint32":
+			arr := make([]uint32, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseUint(it, 10, 32)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as uint32: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "int64":
-				arr := make([]int64, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseInt(it, 10, 64)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as int64: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, v)
+				arr = append(arr, uint32(v))
+			}
+			writeValue = arr
+		case "int64":
+			arr := make([]int64, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseInt(it, 10, 64)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as int64: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "uint64":
-				arr := make([]uint64, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseUint(it, 10, 64)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as uint64: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, v)
+				arr = append(arr, v)
+			}
+			writeValue = arr
+		case "uint64":
+			arr := make([]uint64, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseUint(it, 10, 64)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as uint64: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "boolean", "bool":
-				arr := make([]bool, 0, len(items))
-				for _, it := range items {
-					v, perr := strconv.ParseBool(it)
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as bool: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, v)
+				arr = append(arr, v)
+			}
+			writeValue = arr
+		case "boolean", "bool":
+			arr := make([]bool, 0, len(items))
+			for _, it := range items {
+				v, perr := strconv.ParseBool(it)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as bool: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			case "string":
-				// items already strings
-				writeValue = items
-			case "localizedtext":
-				arr := make([]ua.LocalizedText, 0, len(items))
-				for _, it := range items {
-					parts := strings.SplitN(it, "|", 2)
-					if len(parts) == 2 {
-						arr = append(arr, ua.LocalizedText{Locale: strings.TrimSpace(parts[0]), Text: strings.TrimSpace(parts[1])})
+				arr = append(arr, v)
+			}
+			writeValue = arr
+		case "string":
+			// items already strings
+			writeValue = items
+		case "localizedtext":
+			arr := make([]ua.LocalizedText, 0, len(items))
+			for _, it := range items {
+				locale, text := splitLocalizedText(it)
+				arr = append(arr, ua.LocalizedText{Locale: locale, Text: text})
+			}
+			writeValue = arr
+		case "datetime":
+			arr := make([]time.Time, 0, len(items))
+			for _, it := range items {
+				// Accept RFC3339 or common format
+				var t time.Time
+				var perr error
+				layouts := []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04:05.000"}
+				for _, layout := range layouts {
+					if tt, err := time.Parse(layout, it); err == nil {
+						t = tt
+						perr = nil
+						break
 					} else {
-						arr = append(arr, ua.LocalizedText{Text: it})
+						perr = err
 					}
 				}
-				writeValue = arr
-			case "datetime":
-				arr := make([]time.Time, 0, len(items))
-				for _, it := range items {
-					// Accept RFC3339 or common format
-					var t time.Time
-					var perr error
-					layouts := []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04:05.000"}
-					for _, layout := range layouts {
-						if tt, err := time.Parse(layout, it); err == nil {
-							t = tt
-							perr = nil
-							break
-						} else {
-							perr = err
-						}
-					}
-					if perr != nil {
-						c.Log(fmt.Sprintf("[red]Failed to parse '%s' as DateTime: %v[-]", it, perr))
-						return
-					}
-					arr = append(arr, t)
+				if perr != nil {
+					log(fmt.Sprintf("[red]Failed to parse '%s' as DateTime: %v[-]", it, perr))
+					return
 				}
-				writeValue = arr
-			default:
-				c.Log(fmt.Sprintf("[red]Array writes for type '%s' are not implemented yet[-]", dataType))
-				return
+				arr = append(arr, t)
 			}
-		} else {
-			// If we detected actual scalar Go kind and it is numeric, coerce to that exact width first
-			switch preferScalarGoType {
-			case reflect.Float32:
-				writeValue, err = convertStringToType(valueStr, "float32")
-			case reflect.Float64:
-				writeValue, err = convertStringToType(valueStr, "float64")
-			case reflect.Int16:
-				writeValue, err = convertStringToType(valueStr, "int16")
-			case reflect.Int32:
-				writeValue, err = convertStringToType(valueStr, "int32")
-			case reflect.Int64:
-				writeValue, err = convertStringToType(valueStr, "int64")
-			case reflect.Uint16:
-				writeValue, err = convertStringToType(valueStr, "uint16")
-			case reflect.Uint32:
-				writeValue, err = convertStringToType(valueStr, "uint32")
-			case reflect.Uint64:
-				writeValue, err = convertStringToType(valueStr, "uint64")
-			case reflect.Bool:
-				writeValue, err = convertStringToType(valueStr, "bool")
-			default:
-				writeValue, err = convertStringToType(valueStr, dataType)
-			}
-		}
-		if err != nil {
-			c.Log(fmt.Sprintf("[red]Failed to parse value '%s' for type %s: %v[-]", valueStr, dataType, err))
+			writeValue = arr
+		default:
+			log(fmt.Sprintf("[red]Array writes for type '%s' are not implemented yet[-]", dataType))
 			return
 		}
+	} else {
+		// If we detected actual scalar Go kind and it is numeric, coerce to that exact width first
+		switch preferScalarGoType {
+		case reflect.Float32:
+			writeValue, err = convertStringToType(valueStr, "float32")
+		case reflect.Float64:
+			writeValue, err = convertStringToType(valueStr, "float64")
+		case reflect.Int16:
+			writeValue, err = convertStringToType(valueStr, "int16")
+		case reflect.Int32:
+			writeValue, err = convertStringToType(valueStr, "int32")
+		case reflect.Int64:
+			writeValue, err = convertStringToType(valueStr, "int64")
+		case reflect.Uint16:
+			writeValue, err = convertStringToType(valueStr, "uint16")
+		case reflect.Uint32:
+			writeValue, err = convertStringToType(valueStr, "uint32")
+		case reflect.Uint64:
+			writeValue, err = convertStringToType(valueStr, "uint64")
+		case reflect.Bool:
+			writeValue, err = convertStringToType(valueStr, "bool")
+		default:
+			writeValue, err = convertStringToType(valueStr, dataType)
+		}
+	}
+	if structured != nil {
+		writeValue, err = structured, nil
+	}
+	if err != nil {
+		log(fmt.Sprintf("[red]Failed to parse value '%s' for type %s: %v[-]", valueStr, dataType, err))
+		return
+	}
 
-		c.Log(fmt.Sprintf("Attempting to write to NodeID %s. Value: %v (GoType: %T, Kind: %s)", nodeID, writeValue, writeValue, reflect.TypeOf(writeValue).Kind()))
+	log(fmt.Sprintf("Attempting to write to NodeID %s. Value: %v (GoType: %T, Kind: %s)", nodeID, writeValue, writeValue, reflect.TypeOf(writeValue).Kind()))
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	writeCtx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
 
-		// helper: perform write and verify by reading back Value
-		tryWrite := func(val interface{}) (bool, error) {
-			if werr := client.WriteValue(ctx, nodeID, val); werr != nil {
-				return false, werr
-			}
-			// verify
-			vctx, vcancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer vcancel()
-			vals, rerr := client.ReadAttributes(vctx, nodeID, ua.AttributeIDValue, ua.AttributeIDDataType)
-			if rerr == nil && len(vals) >= 1 && vals[0] != nil {
-				// DataType might be at index 1 if returned
-				var dtName string
-				if len(vals) >= 2 && vals[1] != nil {
-					if nid, ok := vals[1].Value.Value().(*ua.NodeID); ok {
-						dtName = builtinTypeName(nid)
-					}
+	// helper: perform write and verify by reading back Value
+	tryWrite := func(val interface{}) (bool, error) {
+		if werr := client.WriteValue(writeCtx, nodeID, val); werr != nil {
+			result = werr
+			return false, werr
+		}
+		result = nil
+		// verify
+		vctx, vcancel := c.opContext(ctx, c.timeouts().Read)
+		defer vcancel()
+		vals, rerr := client.ReadAttributes(vctx, nodeID, ua.AttributeIDValue, ua.AttributeIDDataType)
+		if rerr == nil && len(vals) >= 1 && vals[0] != nil {
+			// DataType might be at index 1 if returned
+			var dtName string
+			if len(vals) >= 2 && vals[1] != nil {
+				if nid, ok := vals[1].Value.Value().(*ua.NodeID); ok {
+					dtName = builtinTypeName(nid)
 				}
-				c.Log(fmt.Sprintf("[green]Write success. Server Value=%v DataType=%s[-]", vals[0].Value.Value(), dtName))
 			}
-			return true, nil
-		}
-
-		// Perform write
-		if ok, err := tryWrite(writeValue); !ok {
-			c.Log(fmt.Sprintf("[red]Failed to write to %s: %v[-]", nodeID, err))
-			lower := strings.ToLower(err.Error())
-			// Retry on type mismatch
-			if strings.Contains(lower, "typemismatch") || strings.Contains(lower, "bad_type") {
-				// A) If we attempted scalar, try as single-element array
-				if reflect.ValueOf(writeValue).Kind() != reflect.Slice {
-					// A0) If server provided DataType differs from what we sent, try reconverting to server DataType
-					if dataType != "" {
-						c.Log(fmt.Sprintf("[yellow]TypeMismatch: retry using server DataType '%s' as scalar...[-]", dataType))
-						if coerced, ferr := convertStringToType(valueStr, dataType); ferr == nil {
-							if ok, _ := tryWrite(coerced); ok {
-								c.Log(fmt.Sprintf("[yellow]Retried using server DataType '%s' and succeeded for %s[-]", dataType, nodeID))
-								return
-							} else {
-								c.Log(fmt.Sprintf("[red]Retry using server DataType '%s' failed[-]", dataType))
-							}
+			log(fmt.Sprintf("[green]Write success. Server Value=%v DataType=%s[-]", vals[0].Value.Value(), dtName))
+		}
+		return true, nil
+	}
+
+	// Perform write
+	if ok, err := tryWrite(writeValue); !ok {
+		log(fmt.Sprintf("[red]Failed to write to %s: %v[-]", nodeID, err))
+		lower := strings.ToLower(err.Error())
+		// Retry on type mismatch
+		if strings.Contains(lower, "typemismatch") || strings.Contains(lower, "bad_type") {
+			// A) If we attempted scalar, try as single-element array
+			if reflect.ValueOf(writeValue).Kind() != reflect.Slice {
+				// A0) If server provided DataType differs from what we sent, try reconverting to server DataType
+				if dataType != "" {
+					log(fmt.Sprintf("[yellow]TypeMismatch: retry using server DataType '%s' as scalar...[-]", dataType))
+					if coerced, ferr := convertStringToType(valueStr, dataType); ferr == nil {
+						if ok, _ := tryWrite(coerced); ok {
+							log(fmt.Sprintf("[yellow]Retried using server DataType '%s' and succeeded for %s[-]", dataType, nodeID))
+							return
 						} else {
-							c.Log(fmt.Sprintf("[red]Cannot coerce input to server DataType '%s': %v[-]", dataType, ferr))
+							log(fmt.Sprintf("[red]Retry using server DataType '%s' failed[-]", dataType))
 						}
+					} else {
+						log(fmt.Sprintf("[red]Cannot coerce input to server DataType '%s': %v[-]", dataType, ferr))
 					}
-					s := strings.TrimSpace(valueStr)
-					s = strings.TrimPrefix(s, "[")
-					s = strings.TrimSuffix(s, "]")
-					if s != "" {
-						items := []string{s}
-						dt := strings.ToLower(strings.TrimSpace(dataType))
-						var arr interface{}
-						var buildErr error
-						switch dt {
-						case "float", "float32":
-							v, perr := strconv.ParseFloat(items[0], 32)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []float32{float32(v)}
-							}
-						case "double", "float64":
-							v, perr := strconv.ParseFloat(items[0], 64)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []float64{v}
-							}
-						case "int16":
-							v, perr := strconv.ParseInt(items[0], 10, 16)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []int16{int16(v)}
-							}
-						case "uint16":
-							v, perr := strconv.ParseUint(items[0], 10, 16)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []uint16{uint16(v)}
-							}
-						case "int32":
-							v, perr := strconv.ParseInt(items[0], 10, 32)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []int32{int32(v)}
-							}
-						case "uint32":
-							v, perr := strconv.ParseUint(items[0], 10, 32)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []uint32{uint32(v)}
-							}
-						case "int64":
-							v, perr := strconv.ParseInt(items[0], 10, 64)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []int64{v}
-							}
-						case "uint64":
-							v, perr := strconv.ParseUint(items[0], 10, 64)
-							buildErr = perr
-							if buildErr == nil {
-								arr = []uint64{v}
-							}
-						case "boolean", "bool":
-							v, perr := strconv.ParseBool(items[0])
-							buildErr = perr
-							if buildErr == nil {
-								arr = []bool{v}
-							}
-						case "string":
-							arr = []string{items[0]}
-						case "localizedtext":
-							parts := strings.SplitN(items[0], "|", 2)
-							if len(parts) == 2 {
-								arr = []ua.LocalizedText{{Locale: strings.TrimSpace(parts[0]), Text: strings.TrimSpace(parts[1])}}
-							} else {
-								arr = []ua.LocalizedText{{Text: items[0]}}
-							}
-						case "datetime":
-							t, perr := time.Parse("2006-01-02 15:04:05.999999999", items[0])
-							buildErr = perr
-							if buildErr == nil {
-								arr = []time.Time{t}
-							}
+				}
+				s := strings.TrimSpace(valueStr)
+				s = strings.TrimPrefix(s, "[")
+				s = strings.TrimSuffix(s, "]")
+				if s != "" {
+					items := []string{s}
+					dt := strings.ToLower(strings.TrimSpace(dataType))
+					var arr interface{}
+					var buildErr error
+					switch dt {
+					case "float", "float32":
+						v, perr := strconv.ParseFloat(items[0], 32)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []float32{float32(v)}
 						}
-						if buildErr == nil && arr != nil {
-							c.Log("[yellow]TypeMismatch: retry as single-element array...[-]")
-							if ok, _ := tryWrite(arr); ok {
-								c.Log(fmt.Sprintf("[yellow]Retried as array and succeeded for %s[-]", nodeID))
-								return
-							} else {
-								c.Log("[red]Array retry failed[-]")
-							}
+					case "double", "float64":
+						v, perr := strconv.ParseFloat(items[0], 64)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []float64{v}
+						}
+					case "int16":
+						v, perr := strconv.ParseInt(items[0], 10, 16)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []int16{int16(v)}
+						}
+					case "uint16":
+						v, perr := strconv.ParseUint(items[0], 10, 16)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []uint16{uint16(v)}
+						}
+					case "int32":
+						v, perr := strconv.ParseInt(items[0], 10, 32)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []int32{int32(v)}
+						}
+					case "uint32":
+						v, perr := strconv.ParseUint(items[0], 10, 32)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []uint32{uint32(v)}
+						}
+					case "int64":
+						v, perr := strconv.ParseInt(items[0], 10, 64)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []int64{v}
+						}
+					case "uint64":
+						v, perr := strconv.ParseUint(items[0], 10, 64)
+						buildErr = perr
+						if buildErr == nil {
+							arr = []uint64{v}
+						}
+					case "boolean", "bool":
+						v, perr := strconv.ParseBool(items[0])
+						buildErr = perr
+						if buildErr == nil {
+							arr = []bool{v}
+						}
+					case "string":
+						arr = []string{items[0]}
+					case "localizedtext":
+						locale, text := splitLocalizedText(items[0])
+						arr = []ua.LocalizedText{{Locale: locale, Text: text}}
+					case "datetime":
+						t, perr := time.Parse("2006-01-02 15:04:05.999999999", items[0])
+						buildErr = perr
+						if buildErr == nil {
+							arr = []time.Time{t}
 						}
 					}
-				}
-				// B) scalar float64 -> float32 retry
-				if _, ok := writeValue.(float64); ok {
-					c.Log("[yellow]TypeMismatch: retry scalar float64 as float32...[-]")
-					if fv, ferr := convertStringToType(valueStr, "float32"); ferr == nil {
-						if ok, _ := tryWrite(fv); ok {
-							c.Log(fmt.Sprintf("[yellow]Retried as Float32 and succeeded for %s[-]", nodeID))
+					if buildErr == nil && arr != nil {
+						log("[yellow]TypeMismatch: retry as single-element array...[-]")
+						if ok, _ := tryWrite(arr); ok {
+							log(fmt.Sprintf("[yellow]Retried as array and succeeded for %s[-]", nodeID))
 							return
 						} else {
-							c.Log("[red]Float32 retry failed[-]")
+							log("[red]Array retry failed[-]")
 						}
+					}
+				}
+			}
+			// B) scalar float64 -> float32 retry
+			if _, ok := writeValue.(float64); ok {
+				log("[yellow]TypeMismatch: retry scalar float64 as float32...[-]")
+				if fv, ferr := convertStringToType(valueStr, "float32"); ferr == nil {
+					if ok, _ := tryWrite(fv); ok {
+						log(fmt.Sprintf("[yellow]Retried as Float32 and succeeded for %s[-]", nodeID))
+						return
 					} else {
-						c.Log(fmt.Sprintf("[red]Cannot convert to float32 for retry: %v[-]", ferr))
+						log("[red]Float32 retry failed[-]")
 					}
+				} else {
+					log(fmt.Sprintf("[red]Cannot convert to float32 for retry: %v[-]", ferr))
 				}
-				// Final exhaustive fallback matrix if still failing
-				candidates := []string{"bytestring", "float64", "float32", "int64", "int32", "int16", "uint64", "uint32", "uint16", "bool", "string"}
-				for _, tname := range candidates {
-					// scalar attempt
-					if v, perr := convertStringToType(valueStr, tname); perr == nil {
-						c.Log(fmt.Sprintf("[yellow]Fallback: try scalar as %s...[-]", tname))
-						if ok, _ := tryWrite(v); ok {
-							c.Log(fmt.Sprintf("[green]Fallback success as scalar %s for %s[-]", tname, nodeID))
-							return
-						} else {
-							c.Log(fmt.Sprintf("[red]Fallback scalar %s failed[-]", tname))
-						}
+			}
+			// Final exhaustive fallback matrix if still failing
+			candidates := []string{"bytestring", "float64", "float32", "int64", "int32", "int16", "uint64", "uint32", "uint16", "bool", "string"}
+			for _, tname := range candidates {
+				// scalar attempt
+				if v, perr := convertStringToType(valueStr, tname); perr == nil {
+					log(fmt.Sprintf("[yellow]Fallback: try scalar as %s...[-]", tname))
+					if ok, _ := tryWrite(v); ok {
+						log(fmt.Sprintf("[green]Fallback success as scalar %s for %s[-]", tname, nodeID))
+						return
+					} else {
+						log(fmt.Sprintf("[red]Fallback scalar %s failed[-]", tname))
 					}
-					// array attempt [single element]
-					if v, perr := convertStringToType(valueStr, tname); perr == nil {
-						arr := []interface{}{v}
-						c.Log(fmt.Sprintf("[yellow]Fallback: try single-element array as %s...[-]", tname))
-						if ok, _ := tryWrite(arr); ok {
-							c.Log(fmt.Sprintf("[green]Fallback success as array %s for %s[-]", tname, nodeID))
-							return
-						} else {
-							c.Log(fmt.Sprintf("[red]Fallback array %s failed[-]", tname))
-						}
+				}
+				// array attempt [single element]
+				if v, perr := convertStringToType(valueStr, tname); perr == nil {
+					arr := []interface{}{v}
+					log(fmt.Sprintf("[yellow]Fallback: try single-element array as %s...[-]", tname))
+					if ok, _ := tryWrite(arr); ok {
+						log(fmt.Sprintf("[green]Fallback success as array %s for %s[-]", tname, nodeID))
+						return
+					} else {
+						log(fmt.Sprintf("[red]Fallback array %s failed[-]", tname))
 					}
 				}
-				c.Log("[red]All fallback attempts exhausted. Write failed.[-]")
 			}
-			return
+			log("[red]All fallback attempts exhausted. Write failed.[-]")
 		}
-		c.Log(fmt.Sprintf("[green]Write to %s succeeded[-]", nodeID))
-	}()
+		return
+	}
+	log(fmt.Sprintf("[green]Write to %s succeeded[-]", nodeID))
 }
 
-func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error) {
+// ReadNodeAttributes reads the attributes shown in the details panel; ctx bounds the Read.
+func (c *Controller) ReadNodeAttributes(ctx context.Context, nodeID string) (*NodeAttributes, error) {
+	if attrs, offline, err := c.offlineAttributes(nodeID); offline {
+		if err == nil && c.OnNodeAttributesUpdate != nil {
+			c.OnNodeAttributesUpdate(attrs)
+		}
+		return attrs, err
+	}
+
 	c.mu.RLock()
 	client := c.client
+	cfg := c.currentConfig
 	c.mu.RUnlock()
 
 	if client == nil {
@@ -1685,13 +2122,14 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := c.opContext(ctx, cfg.Timeouts().Read)
 	defer cancel()
 
 	attrsToRead := []ua.AttributeID{
 		ua.AttributeIDNodeID,
 		ua.AttributeIDNodeClass,
 		ua.AttributeIDDisplayName,
+		ua.AttributeIDBrowseName,
 		ua.AttributeIDDescription,
 		ua.AttributeIDAccessLevel,
 		ua.AttributeIDUserAccessLevel,
@@ -1699,6 +2137,8 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 		ua.AttributeIDValue,
 		ua.AttributeIDValueRank,
 		ua.AttributeIDArrayDimensions,
+		ua.AttributeIDWriteMask,
+		ua.AttributeIDUserWriteMask,
 	}
 
 	results, err := client.ReadAttributes(ctx, nodeID, attrsToRead...)
@@ -1706,16 +2146,26 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 		return nil, err
 	}
 
-	attrs := &NodeAttributes{ValueRank: -1}
+	attrs := &NodeAttributes{ValueRank: -1, Timestamp: c.formatDisplayTimestamp(cfg, time.Now())}
 	var rawValue *ua.Variant
 	var levelValue uint32
 	var userLevelValue uint32
+	levelStatus := make(map[ua.AttributeID]ua.StatusCode, 2)
 
 	for i, res := range results {
+		attrID := attrsToRead[i]
 		if res == nil || res.Status != ua.StatusOK {
+			status := ua.StatusBadNoData
+			if res != nil {
+				status = res.Status
+			}
+			if attrID == ua.AttributeIDAccessLevel || attrID == ua.AttributeIDUserAccessLevel {
+				levelStatus[attrID] = status
+			} else if name, ok := attributeDisplayNames[attrID]; ok {
+				attrs.setAttributeStatus(name, status)
+			}
 			continue
 		}
-		attrID := attrsToRead[i]
 		switch attrID {
 		case ua.AttributeIDNodeID:
 			if id, ok := res.Value.Value().(*ua.NodeID); ok {
@@ -1740,6 +2190,10 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 			} else if lt, ok := res.Value.Value().(*ua.LocalizedText); ok && lt != nil {
 				attrs.Name = lt.Text
 			}
+		case ua.AttributeIDBrowseName:
+			if qn, ok := res.Value.Value().(*ua.QualifiedName); ok {
+				attrs.BrowseName = opc.FormatBrowseName(qn)
+			}
 		case ua.AttributeIDDescription:
 			if lt, ok := res.Value.Value().(ua.LocalizedText); ok {
 				attrs.Description = lt.Text
@@ -1774,6 +2228,11 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 			}
 		case ua.AttributeIDValue:
 			rawValue = res.Value
+			attrs.SourceTimestamp = c.formatISOTimestamp(cfg, res.SourceTimestamp)
+			attrs.ServerTimestamp = c.formatISOTimestamp(cfg, res.ServerTimestamp)
+			attrs.Timestamp = c.displayTimestamp(cfg, res.SourceTimestamp, res.ServerTimestamp)
+		case ua.AttributeIDWriteMask, ua.AttributeIDUserWriteMask:
+			attrs.WriteMasks.setWriteMask(attrID, res.Value)
 		case ua.AttributeIDValueRank:
 			switch v := res.Value.Value().(type) {
 			case int32:
@@ -1795,14 +2254,28 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 	if attrs.NodeID == "" {
 		attrs.NodeID = nodeID
 	}
+	c.mu.RLock()
+	attrs.NamespaceURI = opc.NamespaceURI(attrs.NodeID, c.namespaces)
+	c.mu.RUnlock()
 	// Prefer UserAccessLevel if provided; fallback to AccessLevel
 	if userLevelValue > 0 {
 		attrs.AccessLevel = formatAccessLevel(ua.AccessLevelType(userLevelValue))
 	} else if levelValue > 0 {
 		attrs.AccessLevel = formatAccessLevel(ua.AccessLevelType(levelValue))
 	}
+	attrs.resolveAccessLevelStatus(levelStatus)
+	attrs.pruneAttributeStatus()
 	if rawValue != nil {
 		attrs.Value = formatValue(rawValue, attrs.DataType)
+		attrs.ValueTyped = typedValue(rawValue)
+		attrs.UAType = uaTypeName(rawValue)
+	}
+	if names := c.dataTypeValues(ctx, attrs.DataType); names != nil {
+		attrs.Enum, attrs.OptionSet = names.enum, names.options
+		if rawValue != nil {
+			attrs.EnumName, _ = attrs.Enum.Name(rawValue.Value())
+			attrs.Flags, _ = attrs.OptionSet.Decode(rawValue.Value())
+		}
 	}
 	if c.OnNodeAttributesUpdate != nil {
 		c.OnNodeAttributesUpdate(attrs)
@@ -1812,6 +2285,12 @@ func (c *Controller) ReadNodeAttributes(nodeID string) (*NodeAttributes, error)
 
 // ReadNodeClass reads only the NodeClass for a given node. Some UI code depends on this helper.
 func (c *Controller) ReadNodeClass(nodeID string) (ua.NodeClass, error) {
+	if c.IsOffline() {
+		if n := c.GetNode(nodeID); n != nil {
+			return n.NodeClass, nil
+		}
+		return 0, fmt.Errorf("node %s not found in offline address space", nodeID)
+	}
 	c.mu.RLock()
 	client := c.client
 	c.mu.RUnlock()
@@ -1823,7 +2302,7 @@ func (c *Controller) ReadNodeClass(nodeID string) (ua.NodeClass, error) {
 	if err != nil {
 		return 0, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeouts().Read)
 	defer cancel()
 	return client.ReadNodeClass(ctx, nID)
 }
@@ -1958,6 +2437,13 @@ func builtinTypeName(id *ua.NodeID) string {
 }
 
 // 解析状态码详细信息
+// isOverflow reports the Overflow bit of a data change StatusCode: the monitored item's queue
+// was full and the server discarded at least one sample.
+func isOverflow(status ua.StatusCode) bool {
+	const infoTypeMask, infoTypeDataValue, overflowBit = 0x0C00, 0x0400, 0x0080
+	return uint32(status)&infoTypeMask == infoTypeDataValue && uint32(status)&overflowBit != 0
+}
+
 func decodeStatusCode(
 	status ua.StatusCode,
 ) (
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"opcuababy/internal/opc"
+)
+
+// dedupConfiguredLocked reports whether repeated samples of nodeID are suppressed, i.e. whether
+// it is listed in Config.DedupNodes. Callers must hold c.mu.
+func (c *Controller) dedupConfiguredLocked(nodeID string) bool {
+	if c.currentConfig == nil || len(c.currentConfig.DedupNodes) == 0 {
+		return false
+	}
+	ref, err := opc.ExpandNodeID(nodeID, c.namespaces)
+	return slices.ContainsFunc(c.currentConfig.DedupNodes, func(p string) bool {
+		return p == nodeID || (err == nil && p == ref)
+	})
+}
+
+// SetWatchDedup switches the suppression of repeated samples of the watch item nodeID. While on,
+// a sample with the value and status of the previous one (a server republishing with new
+// timestamps only) still updates the watch list but is not broadcast to the API clients and the
+// gateway sinks. The caller stores the choice in Config.DedupNodes under NodeRef first.
+func (c *Controller) SetWatchDedup(nodeID string, on bool) {
+	c.mu.Lock()
+	it, ok := c.watchItems[nodeID]
+	if !ok || it.Dedup == on {
+		c.mu.Unlock()
+		return
+	}
+	it.Dedup = on
+	c.mu.Unlock()
+	c.markWatchDirty(nodeID)
+	if on {
+		c.Log(fmt.Sprintf("[green]Repeated samples of %s are no longer broadcast[-]", nodeID))
+	} else {
+		c.Log(fmt.Sprintf("[green]Every sample of %s is broadcast[-]", nodeID))
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/gopcua/opcua/ua"
+
+	"opcuababy/internal/opc"
+)
+
+// SetDetailNode makes nodeID the node shown in the details panel and keeps its Value live with a
+// temporary monitored item until another node (or "") is set. Watched nodes reuse their watch
+// subscription. dataType is used to format the values passed to OnDetailValueUpdate.
+func (c *Controller) SetDetailNode(nodeID, dataType string) {
+	c.detailMu.Lock()
+	defer c.detailMu.Unlock()
+
+	c.mu.Lock()
+	if c.detailNodeID == nodeID {
+		c.detailDataType = dataType
+		c.mu.Unlock()
+		return
+	}
+	prevID, prevSub := c.detailNodeID, c.detailSub
+	c.detailNodeID, c.detailDataType, c.detailSub = nodeID, dataType, nil
+	cli := c.client
+	_, watched := c.watchItems[nodeID]
+	c.mu.Unlock()
+
+	if prevSub != nil {
+		if err := prevSub.Close(); err != nil {
+			c.Log(fmt.Sprintf("[yellow]Failed to stop detail monitoring of %s: %v[-]", prevID, err))
+		}
+	}
+	if nodeID == "" || cli == nil || watched {
+		return
+	}
+
+	sub, err := cli.MonitorItem(nodeID)
+	if err != nil {
+		c.Log(fmt.Sprintf("[yellow]Live value for %s unavailable: %v[-]", nodeID, err))
+		return
+	}
+	c.mu.Lock()
+	if c.detailNodeID == nodeID && c.client == cli {
+		c.detailSub = sub
+		sub = nil
+	}
+	c.mu.Unlock()
+	if sub != nil {
+		_ = sub.Close()
+	}
+}
+
+// takeDetailSubLocked hands the detail subscription of nodeID over to the caller, e.g. when the
+// node is added to the watch list. Callers must hold c.mu.
+func (c *Controller) takeDetailSubLocked(nodeID string) *opc.Subscription {
+	if c.detailNodeID != nodeID || c.detailSub == nil {
+		return nil
+	}
+	sub := c.detailSub
+	c.detailSub = nil
+	return sub
+}
+
+// detailValue formats dv like the watch list does; values of an Enumeration carry their name and
+// those of an OptionSet their flags.
+func detailValue(dv *ua.DataValue, dataType string, names *dataTypeValues) string {
+	switch {
+	case dv == nil:
+		return "<error: no data>"
+	case dv.Value == nil:
+		return "<nil>"
+	case names == nil:
+		return formatValue(dv.Value, dataType)
+	default:
+		name, _ := names.enum.Name(dv.Value.Value())
+		flags, _ := names.options.Decode(dv.Value.Value())
+		return FormatEnumValue(formatValue(dv.Value, dataType), name+flags)
+	}
+}
@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// EnumValue is one value of an Enumeration DataType.
+type EnumValue struct {
+	Value int64  `json:"value"`
+	Name  string `json:"name"`
+}
+
+// EnumType holds the values of an Enumeration DataType, taken from its EnumStrings or EnumValues
+// property.
+type EnumType struct {
+	DataType string      `json:"data_type"`
+	Values   []EnumValue `json:"values"`
+}
+
+// Name returns the symbolic name of v, which may be any integer Go type.
+func (t *EnumType) Name(v interface{}) (string, bool) {
+	n, ok := enumInt(v)
+	if t == nil || !ok {
+		return "", false
+	}
+	for _, ev := range t.Values {
+		if ev.Value == n {
+			return ev.Name, true
+		}
+	}
+	return "", false
+}
+
+// Parse returns the value of a symbolic name (case-insensitive), an integer, or an integer with
+// its name as shown in the watch list, e.g. "Running", "0" or "0 (Running)".
+func (t *EnumType) Parse(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " ("); i > 0 && strings.HasSuffix(s, ")") {
+		s = s[:i]
+	}
+	if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+		return n, nil
+	}
+	names := make([]string, 0, len(t.Values))
+	for _, ev := range t.Values {
+		if strings.EqualFold(ev.Name, s) {
+			return ev.Value, nil
+		}
+		names = append(names, ev.Name)
+	}
+	return 0, fmt.Errorf("%q is not a value of enumeration %s (%s)", s, t.DataType, strings.Join(names, ", "))
+}
+
+// resolveInput replaces the symbolic names of a write input by their values; array inputs
+// ("[A, B]" or "A,B") are resolved item by item.
+func (t *EnumType) resolveInput(s string, array bool) (string, error) {
+	if !array {
+		n, err := t.Parse(s)
+		return strconv.FormatInt(n, 10), err
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "["), "]")
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		n, err := t.Parse(item)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, strconv.FormatInt(n, 10))
+	}
+	return strings.Join(out, ","), nil
+}
+
+// FormatEnumValue shows a value with its symbolic name, e.g. "0 (Running)"; without a name it
+// returns value.
+func FormatEnumValue(value, name string) string {
+	if name == "" {
+		return value
+	}
+	return value + " (" + name + ")"
+}
+
+func enumInt(v interface{}) (int64, bool) {
+	switch x := v.(type) {
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case int:
+		return int64(x), true
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case float64:
+		// Forced values parsed from JSON
+		return int64(x), x == float64(int64(x))
+	}
+	return 0, false
+}
+
+// dataTypeValues are the value names of a DataType: the values of an Enumeration or the bits of
+// an OptionSet. Both are nil for other DataTypes.
+type dataTypeValues struct {
+	enum    *EnumType
+	options *OptionSet
+}
+
+// EnumType returns the values of the DataType dataType (a NodeID as in NodeAttributes.DataType),
+// or nil when it is not an Enumeration. Each DataType is read once per session.
+func (c *Controller) EnumType(ctx context.Context, dataType string) *EnumType {
+	if v := c.dataTypeValues(ctx, dataType); v != nil {
+		return v.enum
+	}
+	return nil
+}
+
+// OptionSetType returns the bits of the DataType dataType, or nil when it is not an OptionSet.
+// Each DataType is read once per session.
+func (c *Controller) OptionSetType(ctx context.Context, dataType string) *OptionSet {
+	if v := c.dataTypeValues(ctx, dataType); v != nil {
+		return v.options
+	}
+	return nil
+}
+
+func (c *Controller) dataTypeValues(ctx context.Context, dataType string) *dataTypeValues {
+	c.enumMu.Lock()
+	v, ok := c.enumCache[dataType]
+	c.enumMu.Unlock()
+	if ok {
+		return v
+	}
+	v, err := c.readDataTypeValues(ctx, dataType)
+	if err != nil {
+		// Not cached, so that the next call tries again
+		return nil
+	}
+	c.enumMu.Lock()
+	if c.enumCache == nil {
+		c.enumCache = make(map[string]*dataTypeValues)
+	}
+	c.enumCache[dataType] = v
+	c.enumMu.Unlock()
+	return v
+}
+
+// cachedDataTypeValues is dataTypeValues without reading: on a cache miss the DataType is read
+// in the background and nil is returned until it is known. It is called for every data change.
+func (c *Controller) cachedDataTypeValues(dataType string) *dataTypeValues {
+	if _, err := ua.ParseNodeID(dataType); dataType == "" || err != nil {
+		return nil
+	}
+	c.enumMu.Lock()
+	v, ok := c.enumCache[dataType]
+	loading := c.enumLoading[dataType]
+	if !ok && !loading {
+		if c.enumLoading == nil {
+			c.enumLoading = make(map[string]bool)
+		}
+		c.enumLoading[dataType] = true
+	}
+	c.enumMu.Unlock()
+	if !ok && !loading {
+		go func() {
+			c.dataTypeValues(context.Background(), dataType)
+			c.enumMu.Lock()
+			delete(c.enumLoading, dataType)
+			c.enumMu.Unlock()
+		}()
+	}
+	return v
+}
+
+// valueNames returns the symbolic name (Enumerations) or the set flags (OptionSets) of v, v being
+// a value of dataType, as far as the DataType is cached.
+func (c *Controller) valueNames(dataType string, v interface{}) (enumName, flags string) {
+	names := c.cachedDataTypeValues(dataType)
+	if names == nil {
+		return "", ""
+	}
+	enumName, _ = names.enum.Name(v)
+	flags, _ = names.options.Decode(v)
+	return enumName, flags
+}
+
+// readDataTypeValues reads the EnumStrings, EnumValues or OptionSetValues property of a DataType
+// node. A DataType without them (a built-in or structured type) yields nil fields and no error.
+func (c *Controller) readDataTypeValues(ctx context.Context, dataType string) (*dataTypeValues, error) {
+	v := &dataTypeValues{}
+	id, err := ua.ParseNodeID(dataType)
+	if dataType == "" || err != nil {
+		// Built-in types are named, e.g. "Int32"
+		return v, nil
+	}
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	rctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+	refs, err := cli.Browse(rctx, id)
+	if err != nil {
+		if v.options = standardOptionSet(id); v.options != nil {
+			return v, nil
+		}
+		return nil, err
+	}
+	var prop *ua.NodeID
+	var name string
+	for _, ref := range refs {
+		if ref.BrowseName == nil || ref.NodeID == nil || ref.BrowseName.NamespaceIndex != 0 {
+			continue
+		}
+		switch ref.BrowseName.Name {
+		case "EnumStrings", "EnumValues", "OptionSetValues":
+			prop, name = ref.NodeID.NodeID, ref.BrowseName.Name
+		}
+	}
+	if prop == nil {
+		v.options = standardOptionSet(id)
+		return v, nil
+	}
+	dvs, err := cli.ReadValues(rctx, []*ua.NodeID{prop})
+	if err != nil {
+		return nil, err
+	}
+	if len(dvs) == 0 || dvs[0] == nil || dvs[0].Status != ua.StatusOK || dvs[0].Value == nil {
+		v.options = standardOptionSet(id)
+		return v, nil
+	}
+	if name == "OptionSetValues" {
+		v.options = &OptionSet{DataType: dataType, Bits: localizedTexts(dvs[0].Value.Value())}
+		if len(v.options.Bits) == 0 {
+			c.Log(fmt.Sprintf("[yellow]%s of DataType %s could not be decoded[-]", name, dataType))
+			v.options = standardOptionSet(id)
+		}
+		return v, nil
+	}
+	t := &EnumType{DataType: dataType}
+	switch vals := dvs[0].Value.Value().(type) {
+	case []*ua.ExtensionObject:
+		for _, eo := range vals {
+			if eo == nil {
+				continue
+			}
+			var ev *ua.EnumValueType
+			switch x := eo.Value.(type) {
+			case *ua.EnumValueType:
+				ev = x
+			case ua.EnumValueType:
+				ev = &x
+			}
+			if ev != nil && ev.DisplayName != nil {
+				t.Values = append(t.Values, EnumValue{Value: ev.Value, Name: ev.DisplayName.Text})
+			}
+		}
+	default:
+		// EnumStrings: the value is the index
+		for i, text := range localizedTexts(vals) {
+			if text != "" {
+				t.Values = append(t.Values, EnumValue{Value: int64(i), Name: text})
+			}
+		}
+	}
+	if len(t.Values) == 0 {
+		c.Log(fmt.Sprintf("[yellow]%s of DataType %s could not be decoded[-]", name, dataType))
+		return v, nil
+	}
+	v.enum = t
+	return v, nil
+}
+
+// localizedTexts returns the texts of a LocalizedText array; missing entries are "".
+func localizedTexts(v interface{}) []string {
+	var texts []string
+	switch vals := v.(type) {
+	case []*ua.LocalizedText:
+		for _, lt := range vals {
+			text := ""
+			if lt != nil {
+				text = lt.Text
+			}
+			texts = append(texts, text)
+		}
+	case []ua.LocalizedText:
+		for _, lt := range vals {
+			texts = append(texts, lt.Text)
+		}
+	}
+	return texts
+}
+
+// clearEnumTypes drops the cached Enumerations and OptionSets, e.g. when the session ends.
+func (c *Controller) clearEnumTypes() {
+	c.enumMu.Lock()
+	c.enumCache = nil
+	c.enumMu.Unlock()
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// EventHistoryRequest selects the historized events ReadEventHistory returns.
+type EventHistoryRequest struct {
+	// NodeID is the notifier whose event history is read; empty reads the Server object (i=2253)
+	NodeID string    `json:"node_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	// EventType limits the result to events of this type and its subtypes, e.g. ConditionType
+	// or i=2782 for alarms and conditions; empty returns all events
+	EventType string `json:"event_type,omitempty"`
+	// Fields are additional BaseEventType browse paths to select, such as "ActiveState/Id"
+	Fields []string `json:"fields,omitempty"`
+	// Where are conditions the events must meet, all of them
+	Where []opc.EventClause `json:"where,omitempty"`
+	// Max limits the number of events; 0 leaves the limit to the server
+	Max uint32 `json:"max,omitempty"`
+}
+
+// HistoryEvent is one event as shown in the event history dialog and API, historized or live.
+type HistoryEvent struct {
+	Time       time.Time         `json:"time"`
+	EventType  string            `json:"event_type"`
+	SourceNode string            `json:"source_node,omitempty"`
+	SourceName string            `json:"source_name,omitempty"`
+	Message    string            `json:"message"`
+	Severity   uint16            `json:"severity"`
+	Fields     map[string]string `json:"fields,omitempty"` // the requested extra fields by browse path
+}
+
+// eventHistoryFields are the select clauses every event history read starts with; the
+// requested extra fields follow them.
+var eventHistoryFields = []*ua.SimpleAttributeOperand{
+	opc.SelectField(id.BaseEventType, "EventType"),
+	opc.SelectField(id.BaseEventType, "SourceNode"),
+	opc.SelectField(id.BaseEventType, "SourceName"),
+	opc.SelectField(id.BaseEventType, "Time"),
+	opc.SelectField(id.BaseEventType, "Message"),
+	opc.SelectField(id.BaseEventType, "Severity"),
+}
+
+// ReadEventHistory reads the events the server historized for req.NodeID in [req.Start,
+// req.End], oldest first unless the server orders them otherwise. Servers that do not
+// historize events answer with BadHistoryOperationUnsupported. Truncated reads return the events
+// read with opc.ErrHistoryTruncated.
+func (c *Controller) ReadEventHistory(ctx context.Context, req EventHistoryRequest) ([]HistoryEvent, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return nil, errors.New("not connected")
+	}
+	if !req.End.After(req.Start) {
+		return nil, errors.New("end time must be after start time")
+	}
+	nodeID := strings.TrimSpace(req.NodeID)
+	if nodeID == "" {
+		nodeID = ua.NewNumericNodeID(0, id.Server).String()
+	}
+
+	preset := opc.EventFilterPreset{EventType: req.EventType, Fields: req.Fields, Where: req.Where}
+	filter, err := preset.Build(eventHistoryFields)
+	if err != nil {
+		return nil, err
+	}
+	fields := preset.SelectedFields()
+
+	ctx, cancel := c.opContext(ctx, 30*time.Second)
+	defer cancel()
+	rows, err := client.HistoryReadEvents(ctx, nodeID, req.Start, req.End, filter, req.Max)
+	truncated := errors.Is(err, opc.ErrHistoryTruncated)
+	if err != nil && !truncated {
+		c.Log(fmt.Sprintf("[red]Event history read failed for %s: %v[-]", nodeID, err))
+		return nil, err
+	}
+	events := make([]HistoryEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, decodeHistoryEvent(row, fields))
+	}
+	if truncated {
+		c.Log(fmt.Sprintf("[yellow]Event history of %s truncated at %d events: %v[-]", nodeID, len(events), err))
+		return events, err
+	}
+	c.Log(fmt.Sprintf("[green]Read %d historical events of %s[-]", len(events), nodeID))
+	return events, nil
+}
+
+// SubscribeEvents calls handle for each event nodeID notifies that passes preset, with the
+// preset's fields in HistoryEvent.Fields. Close the subscription when done; it ends with the
+// session.
+func (c *Controller) SubscribeEvents(nodeID string, preset opc.EventFilterPreset, handle func(HistoryEvent)) (*opc.EventSubscription, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return nil, errors.New("not connected")
+	}
+	nodeID = strings.TrimSpace(nodeID)
+	if nodeID == "" {
+		nodeID = ua.NewNumericNodeID(0, id.Server).String()
+	}
+	notifier, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node_id %q: %w", nodeID, err)
+	}
+	filter, err := preset.Build(eventHistoryFields)
+	if err != nil {
+		return nil, err
+	}
+	fields := preset.SelectedFields()
+	es, err := client.MonitorEvents(notifier, filter, func(row []*ua.Variant) {
+		handle(decodeHistoryEvent(row, fields))
+	})
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Event subscription on %s failed: %v[-]", nodeID, err))
+		return nil, err
+	}
+	c.Log(fmt.Sprintf("[green]Subscribed to the events of %s[-]", nodeID))
+	return es, nil
+}
+
+// decodeHistoryEvent maps the fields of one event, in the order of eventHistoryFields followed
+// by the extra fields, to a HistoryEvent.
+func decodeHistoryEvent(row []*ua.Variant, extra []string) HistoryEvent {
+	field := func(i int) *ua.Variant {
+		if i < len(row) {
+			return row[i]
+		}
+		return nil
+	}
+	var ev HistoryEvent
+	if v := field(0); v != nil {
+		if n, ok := v.Value().(*ua.NodeID); ok && n != nil {
+			ev.EventType = eventTypeName(n)
+		}
+	}
+	if v := field(1); v != nil {
+		if n, ok := v.Value().(*ua.NodeID); ok && n != nil {
+			ev.SourceNode = n.String()
+		}
+	}
+	ev.SourceName = formatValue(field(2), "")
+	if v := field(3); v != nil {
+		ev.Time, _ = v.Value().(time.Time)
+	}
+	ev.Message = formatValue(field(4), "")
+	if v := field(5); v != nil {
+		ev.Severity, _ = v.Value().(uint16)
+	}
+	for i, f := range extra {
+		if ev.Fields == nil {
+			ev.Fields = make(map[string]string, len(extra))
+		}
+		ev.Fields[f] = formatValue(field(len(eventHistoryFields)+i), "")
+	}
+	return ev
+}
+
+// eventTypeName returns the browse name of standard event types and the NodeID of others.
+func eventTypeName(n *ua.NodeID) string {
+	if n.Namespace() == 0 && n.Type() == ua.NodeIDTypeNumeric {
+		if name := id.Name(n.IntID()); name != strconv.FormatUint(uint64(n.IntID()), 10) {
+			return name
+		}
+	}
+	return n.String()
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// forcedValue is a locally forced watch value (see ForceValue).
+type forcedValue struct {
+	value string
+	typed interface{}
+}
+
+// ForceValue makes the watched node nodeID show value instead of the server's value, like a PLC
+// force table, without writing anything to the server. The item is flagged Forced and keeps the
+// server's latest value in ServerValue until ReleaseForce. Forces survive reconnects.
+func (c *Controller) ForceValue(nodeID, value string) error {
+	c.mu.Lock()
+	item, ok := c.watchItems[nodeID]
+	if !ok {
+		c.mu.Unlock()
+		return errors.New("only watched nodes can be forced")
+	}
+	var typed interface{} = value
+	if item.DataType != "" {
+		v, err := convertStringToType(value, item.DataType)
+		if err != nil {
+			c.mu.Unlock()
+			return fmt.Errorf("invalid value for %s: %w", item.DataType, err)
+		}
+		typed = v
+	}
+	if c.forces == nil {
+		c.forces = make(map[string]*forcedValue)
+	}
+	if item.Forced {
+		// Show the server value again before applying the new force
+		item.Value, item.ValueTyped = item.ServerValue, item.serverTyped
+	}
+	c.forces[nodeID] = &forcedValue{value: value, typed: typed}
+	c.applyForceLocked(item)
+	msg := *item
+	msg.subHandle = nil
+	c.mu.Unlock()
+
+	c.Log(fmt.Sprintf("[yellow]FORCED %s = %s (local only, not written to the server)[-]", nodeID, value))
+	c.publishForceChange(&msg)
+	return nil
+}
+
+// ReleaseForce removes the force of nodeID, showing the server's value again.
+func (c *Controller) ReleaseForce(nodeID string) {
+	c.mu.Lock()
+	_, forced := c.forces[nodeID]
+	delete(c.forces, nodeID)
+	item, ok := c.watchItems[nodeID]
+	if !forced || !ok || !item.Forced {
+		c.mu.Unlock()
+		return
+	}
+	item.Value, item.ValueTyped = item.ServerValue, item.serverTyped
+	item.ServerValue, item.serverTyped = "", nil
+	item.Forced = false
+	msg := *item
+	msg.subHandle = nil
+	c.mu.Unlock()
+
+	c.Log(fmt.Sprintf("[cyan]Released force of %s[-]", nodeID))
+	c.publishForceChange(&msg)
+}
+
+// ReleaseAllForces releases every forced node.
+func (c *Controller) ReleaseAllForces() {
+	for _, id := range c.ForcedNodes() {
+		c.ReleaseForce(id)
+	}
+}
+
+// ForcedNodes returns the NodeIDs with an active force, sorted.
+func (c *Controller) ForcedNodes() []string {
+	c.mu.RLock()
+	ids := make([]string, 0, len(c.forces))
+	for id := range c.forces {
+		ids = append(ids, id)
+	}
+	c.mu.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// applyForceLocked replaces a freshly updated value with its force, if any. Callers must hold c.mu.
+func (c *Controller) applyForceLocked(item *WatchItem) {
+	f, ok := c.forces[item.NodeID]
+	if !ok {
+		return
+	}
+	item.ServerValue, item.serverTyped = item.Value, item.ValueTyped
+	item.Value, item.ValueTyped = f.value, f.typed
+	item.Forced = true
+}
+
+// publishForceChange redraws the row and sends the item to API clients.
+func (c *Controller) publishForceChange(msg *WatchItem) {
+	c.markWatchDirty(msg.NodeID)
+	c.broadcastWatch(msg)
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// goldenWebhookTimeout bounds a single deviation webhook POST.
+const goldenWebhookTimeout = 5 * time.Second
+
+// NodeRef returns the reference nodeID is saved under in the config ("nsu=<uri>;<id>" when the
+// namespace is known), as used by Config.WatchList and Config.GoldenValues.
+func (c *Controller) NodeRef(nodeID string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if ref, err := opc.ExpandNodeID(nodeID, c.namespaces); err == nil {
+		return ref
+	}
+	return nodeID
+}
+
+// SetGoldenValue attaches the expected value g to the watched node nodeID, or removes it when g
+// is nil, and re-evaluates the row. The caller stores g in Config.GoldenValues under NodeRef.
+func (c *Controller) SetGoldenValue(nodeID string, g *opc.GoldenValue) {
+	c.mu.Lock()
+	item, ok := c.watchItems[nodeID]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	item.Golden = g
+	item.Deviation = false
+	alert := c.checkGoldenLocked(item)
+	msg := *item
+	msg.subHandle = nil
+	c.mu.Unlock()
+
+	c.markWatchDirty(nodeID)
+	if alert {
+		c.raiseGoldenAlert(&msg)
+	}
+}
+
+// goldenForLocked looks up the saved expected value of nodeID. Callers must hold c.mu.
+func (c *Controller) goldenForLocked(nodeID string) *opc.GoldenValue {
+	if c.currentConfig == nil || len(c.currentConfig.GoldenValues) == 0 {
+		return nil
+	}
+	keys := []string{nodeID}
+	if ref, err := opc.ExpandNodeID(nodeID, c.namespaces); err == nil && ref != nodeID {
+		keys = append([]string{ref}, keys...)
+	}
+	for _, k := range keys {
+		if g, ok := c.currentConfig.GoldenValues[k]; ok {
+			return &g
+		}
+	}
+	return nil
+}
+
+// checkGoldenLocked updates item.Deviation and reports whether the item has just started to
+// deviate. Callers must hold c.mu.
+func (c *Controller) checkGoldenLocked(item *WatchItem) bool {
+	if item.Golden == nil {
+		item.Deviation = false
+		return false
+	}
+	was := item.Deviation
+	item.Deviation = item.Severity == "Bad" || !goldenMatches(item.Value, *item.Golden)
+	if was && !item.Deviation {
+		go c.Log(fmt.Sprintf("[green]%s is back at its expected value %s[-]", item.NodeID, item.Golden.Expected))
+	}
+	return item.Deviation && !was
+}
+
+// goldenMatches compares a formatted value against g: numerically within the tolerance when
+// both parse as numbers, otherwise as case-insensitive text.
+func goldenMatches(value string, g opc.GoldenValue) bool {
+	value, expected := strings.TrimSpace(value), strings.TrimSpace(g.Expected)
+	v, errV := strconv.ParseFloat(value, 64)
+	e, errE := strconv.ParseFloat(expected, 64)
+	if errV == nil && errE == nil {
+		return math.Abs(v-e) <= math.Abs(g.Tolerance)
+	}
+	return strings.EqualFold(value, expected)
+}
+
+// goldenAlert is the body of the deviation webhook.
+type goldenAlert struct {
+	Event     string  `json:"event"`
+	NodeID    string  `json:"node_id"`
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Expected  string  `json:"expected"`
+	Tolerance float64 `json:"tolerance,omitempty"`
+	Severity  string  `json:"severity"`
+	Endpoint  string  `json:"endpoint,omitempty"`
+	Time      string  `json:"time"`
+}
+
+// raiseGoldenAlert logs a deviation, passes it to OnGoldenDeviation and posts it to the
+// configured webhook.
+func (c *Controller) raiseGoldenAlert(item *WatchItem) {
+	c.Log(fmt.Sprintf("[yellow]%s deviates: %s, expected %s[-]", item.NodeID, item.Value, FormatGolden(item.Golden)))
+
+	c.mu.RLock()
+	cb := c.OnGoldenDeviation
+	var url, endpoint string
+	if c.currentConfig != nil {
+		url = strings.TrimSpace(c.currentConfig.GoldenWebhookURL)
+		endpoint = c.currentConfig.EndpointURL
+	}
+	c.mu.RUnlock()
+	if cb != nil {
+		cb(*item)
+	}
+	if url == "" {
+		return
+	}
+
+	body, _ := json.Marshal(goldenAlert{
+		Event:     "golden_deviation",
+		NodeID:    item.NodeID,
+		Name:      item.Name,
+		Value:     item.Value,
+		Expected:  item.Golden.Expected,
+		Tolerance: item.Golden.Tolerance,
+		Severity:  item.Severity,
+		Endpoint:  endpoint,
+		Time:      time.Now().Format(time.RFC3339),
+	})
+	go func() {
+		client := &http.Client{Timeout: goldenWebhookTimeout}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			c.Log(fmt.Sprintf("[red]Deviation webhook failed: %v[-]", err))
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			c.Log(fmt.Sprintf("[red]Deviation webhook returned %s[-]", resp.Status))
+		}
+	}()
+}
+
+// FormatGolden renders g as "expected ± tolerance", empty when g is nil.
+func FormatGolden(g *opc.GoldenValue) string {
+	if g == nil {
+		return ""
+	}
+	if g.Tolerance != 0 {
+		return fmt.Sprintf("%s ± %g", g.Expected, g.Tolerance)
+	}
+	return g.Expected
+}
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// HistoryValue is a single historical sample as shown in the history dialog and API.
+type HistoryValue struct {
+	Timestamp       string `json:"timestamp"`
+	ServerTimestamp string `json:"server_timestamp,omitempty"`
+	Value           string `json:"value"`
+	Status          string `json:"status"`
+
+	raw *ua.DataValue
+}
+
+// aggregateFunctions maps user-facing aggregate names to standard AggregateFunction NodeIDs (ns=0).
+var aggregateFunctions = map[string]uint32{
+	"average":       id.AggregateFunction_Average,
+	"minimum":       id.AggregateFunction_Minimum,
+	"maximum":       id.AggregateFunction_Maximum,
+	"interpolative": id.AggregateFunction_Interpolative,
+	"count":         id.AggregateFunction_Count,
+	"total":         id.AggregateFunction_Total,
+	"start":         id.AggregateFunction_Start,
+	"end":           id.AggregateFunction_End,
+}
+
+// AggregateNames returns the supported aggregate names in display order.
+func AggregateNames() []string {
+	return []string{"Average", "Minimum", "Maximum", "Interpolative", "Count", "Total", "Start", "End"}
+}
+
+// resolveAggregate accepts a known aggregate name (case-insensitive) or an explicit NodeID.
+func resolveAggregate(name string) (*ua.NodeID, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if n == "" {
+		return nil, errors.New("aggregate is required")
+	}
+	if v, ok := aggregateFunctions[n]; ok {
+		return ua.NewNumericNodeID(0, v), nil
+	}
+	if nid, err := ua.ParseNodeID(name); err == nil {
+		return nid, nil
+	}
+	names := make([]string, 0, len(aggregateFunctions))
+	for k := range aggregateFunctions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unsupported aggregate '%s' (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// ReadHistoryRaw reads raw historical values for nodeID in [start, end]; ctx bounds the request.
+// When the server has more values than the read follows, the values read are returned with
+// opc.ErrHistoryTruncated.
+func (c *Controller) ReadHistoryRaw(ctx context.Context, nodeID string, start, end time.Time, maxValues uint32) ([]*HistoryValue, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return nil, errors.New("not connected")
+	}
+	if !end.After(start) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	ctx, cancel := c.opContext(ctx, 30*time.Second)
+	defer cancel()
+	dvs, err := client.HistoryReadRaw(ctx, nodeID, start, end, maxValues)
+	if errors.Is(err, opc.ErrHistoryTruncated) {
+		c.Log(fmt.Sprintf("[yellow]History of %s truncated at %d values: %v[-]", nodeID, len(dvs), err))
+		return toHistoryValues(dvs), err
+	}
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]History read failed for %s: %v[-]", nodeID, err))
+		return nil, err
+	}
+	return toHistoryValues(dvs), nil
+}
+
+// ReadHistoryAggregate reads server-computed aggregates for nodeID in [start, end] using
+// the given processing interval. Servers without Aggregate support typically answer
+// with BadAggregateNotSupported or BadHistoryOperationUnsupported. Truncated reads are reported
+// like ReadHistoryRaw does.
+func (c *Controller) ReadHistoryAggregate(ctx context.Context, nodeID, aggregate string, start, end time.Time, interval time.Duration) ([]*HistoryValue, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return nil, errors.New("not connected")
+	}
+	if !end.After(start) {
+		return nil, errors.New("end time must be after start time")
+	}
+	if interval <= 0 {
+		return nil, errors.New("processing interval must be positive")
+	}
+	aggID, err := resolveAggregate(aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.opContext(ctx, 30*time.Second)
+	defer cancel()
+	dvs, err := client.HistoryReadProcessed(ctx, nodeID, start, end, interval, aggID)
+	if errors.Is(err, opc.ErrHistoryTruncated) {
+		c.Log(fmt.Sprintf("[yellow]%s aggregate history of %s truncated at %d values: %v[-]", aggregate, nodeID, len(dvs), err))
+		return toHistoryValues(dvs), err
+	}
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Aggregate history read (%s) failed for %s: %v[-]", aggregate, nodeID, err))
+		return nil, err
+	}
+	c.Log(fmt.Sprintf("[green]Read %d %s aggregate values for %s[-]", len(dvs), aggregate, nodeID))
+	return toHistoryValues(dvs), nil
+}
+
+func toHistoryValues(dvs []*ua.DataValue) []*HistoryValue {
+	out := make([]*HistoryValue, 0, len(dvs))
+	for _, dv := range dvs {
+		if dv == nil {
+			continue
+		}
+		hv := &HistoryValue{Status: dv.Status.Error(), raw: dv}
+		if dv.Status == ua.StatusOK {
+			hv.Status = "Good"
+		}
+		if !dv.SourceTimestamp.IsZero() {
+			hv.Timestamp = dv.SourceTimestamp.Format("2006-01-02 15:04:05.000")
+		}
+		if !dv.ServerTimestamp.IsZero() {
+			hv.ServerTimestamp = dv.ServerTimestamp.Format("2006-01-02 15:04:05.000")
+			if hv.Timestamp == "" {
+				hv.Timestamp = hv.ServerTimestamp
+			}
+		}
+		if dv.Value != nil {
+			hv.Value = formatValue(dv.Value, "")
+		}
+		out = append(out, hv)
+	}
+	return out
+}
+
+// HistoryPoint is a numeric sample for charting clients such as Grafana.
+type HistoryPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// NumericPoints returns the samples of values that have a timestamp and a finite numeric value,
+// in the order given; booleans count as 0 and 1.
+func NumericPoints(values []*HistoryValue) []HistoryPoint {
+	out := make([]HistoryPoint, 0, len(values))
+	for _, hv := range values {
+		if hv == nil || hv.raw == nil {
+			continue
+		}
+		if p, ok := dataValuePoint(hv.raw, time.Time{}); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// dataValuePoint returns dv as a HistoryPoint at its SourceTimestamp, ServerTimestamp or else
+// fallback.
+func dataValuePoint(dv *ua.DataValue, fallback time.Time) (HistoryPoint, bool) {
+	v, ok := numericValue(dv.Value)
+	if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+		return HistoryPoint{}, false
+	}
+	at := dv.SourceTimestamp
+	if at.IsZero() {
+		at = dv.ServerTimestamp
+	}
+	if at.IsZero() {
+		at = fallback
+	}
+	if at.IsZero() {
+		return HistoryPoint{}, false
+	}
+	return HistoryPoint{Time: at, Value: v}, true
+}
+
+// HistorySample is a single value to insert or replace in a node's history.
+type HistorySample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     string    `json:"value"`
+}
+
+// historyUpdateModes maps user-facing update modes to PerformUpdateType.
+var historyUpdateModes = map[string]ua.PerformUpdateType{
+	"insert":  ua.PerformUpdateTypeInsert,
+	"replace": ua.PerformUpdateTypeReplace,
+	"update":  ua.PerformUpdateTypeUpdate,
+}
+
+// HistoryUpdateModes returns the supported update modes in display order.
+func HistoryUpdateModes() []string {
+	return []string{"Insert", "Replace", "Update"}
+}
+
+// UpdateHistory inserts, replaces or upserts ("update") historical values of a scalar node.
+// dataType may be empty, in which case the server-reported DataType is used.
+func (c *Controller) UpdateHistory(ctx context.Context, nodeID, mode, dataType string, samples []HistorySample) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+	if err := c.CheckWriteAllowed(nodeID); err != nil {
+		c.Log(fmt.Sprintf("[red]History update refused: %v[-]", err))
+		return err
+	}
+	perform, ok := historyUpdateModes[strings.ToLower(strings.TrimSpace(mode))]
+	if !ok {
+		return fmt.Errorf("unsupported history update mode '%s' (supported: insert, replace, update)", mode)
+	}
+	if len(samples) == 0 {
+		return errors.New("no values to update")
+	}
+	if a, err := c.ReadNodeAttributes(ctx, nodeID); err == nil && a != nil && a.DataType != "" {
+		if dataType != "" && !strings.EqualFold(dataType, a.DataType) {
+			c.Log(fmt.Sprintf("[yellow]Overriding provided DataType '%s' with server-reported '%s'[-]", dataType, a.DataType))
+		}
+		dataType = a.DataType
+	}
+	if dataType == "" {
+		return errors.New("data type could not be determined")
+	}
+
+	values := make([]*ua.DataValue, 0, len(samples))
+	for _, s := range samples {
+		if s.Timestamp.IsZero() {
+			return errors.New("every value requires a timestamp")
+		}
+		v, err := convertStringToType(s.Value, dataType)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' for %s: %w", s.Value, dataType, err)
+		}
+		variant, err := ua.NewVariant(v)
+		if err != nil {
+			return fmt.Errorf("failed to create variant: %w", err)
+		}
+		values = append(values, &ua.DataValue{
+			EncodingMask:    ua.DataValueValue | ua.DataValueStatusCode | ua.DataValueSourceTimestamp,
+			Value:           variant,
+			Status:          ua.StatusOK,
+			SourceTimestamp: s.Timestamp,
+		})
+	}
+
+	ctx, cancel := c.opContext(ctx, 30*time.Second)
+	defer cancel()
+	if _, err := client.HistoryUpdateData(ctx, nodeID, perform, values); err != nil {
+		c.Log(fmt.Sprintf("[red]History %s failed for %s: %v[-]", strings.ToLower(mode), nodeID, err))
+		return err
+	}
+	c.Log(fmt.Sprintf("[green]History %s of %d value(s) succeeded for %s[-]", strings.ToLower(mode), len(values), nodeID))
+	return nil
+}
+
+// DeleteHistoryRaw removes raw historical values of nodeID in [start, end].
+func (c *Controller) DeleteHistoryRaw(ctx context.Context, nodeID string, start, end time.Time) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	if client == nil {
+		return errors.New("not connected")
+	}
+	if err := c.CheckWriteAllowed(nodeID); err != nil {
+		c.Log(fmt.Sprintf("[red]History delete refused: %v[-]", err))
+		return err
+	}
+	if !end.After(start) {
+		return errors.New("end time must be after start time")
+	}
+
+	ctx, cancel := c.opContext(ctx, 30*time.Second)
+	defer cancel()
+	if err := client.HistoryDeleteRaw(ctx, nodeID, start, end); err != nil {
+		c.Log(fmt.Sprintf("[red]History delete failed for %s: %v[-]", nodeID, err))
+		return err
+	}
+	c.Log(fmt.Sprintf("[green]Deleted history of %s between %s and %s[-]", nodeID, start.Format("2006-01-02 15:04:05"), end.Format("2006-01-02 15:04:05")))
+	return nil
+}
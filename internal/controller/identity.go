@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"opcuababy/internal/opc"
+)
+
+// ServerIdentity returns the identity read from the connected server, or nil if it is not
+// connected or the identity has not been read yet.
+func (c *Controller) ServerIdentity() *opc.ServerIdentity {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serverIdentity
+}
+
+// SubscriptionStats returns the parameters and publish statistics of the watch subscriptions, one
+// per rate group, or nil when not connected or nothing is monitored.
+func (c *Controller) SubscriptionStats() []*opc.SubscriptionStats {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil
+	}
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	defer cancel()
+	return cli.SubscriptionStats(ctx)
+}
+
+// ChannelStats returns the request, latency and byte counters of the current connection, or nil
+// when not connected.
+func (c *Controller) ChannelStats() *opc.ChannelStats {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil
+	}
+	return cli.ChannelStats()
+}
+
+// loadServerIdentity reads ApplicationDescription and BuildInfo from cli after a successful
+// connect and publishes them via OnServerIdentityUpdate.
+func (c *Controller) loadServerIdentity(cli *opc.Client) {
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	defer cancel()
+	si, err := cli.ReadServerIdentity(ctx)
+	if err != nil {
+		c.Log(fmt.Sprintf("[yellow]Could not read server identity: %v[-]", err))
+		return
+	}
+
+	c.mu.Lock()
+	if c.client != cli {
+		// Disconnected or reconnected meanwhile
+		c.mu.Unlock()
+		return
+	}
+	c.serverIdentity = si
+	c.mu.Unlock()
+
+	c.Log(fmt.Sprintf("[green]Server: %s (%s %s, %s)[-]", si.ApplicationName, si.ProductName, si.SoftwareVersion, si.ApplicationURI))
+	if c.OnServerIdentityUpdate != nil {
+		c.OnServerIdentityUpdate(si)
+	}
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// ModelPollInterval is how often the branches in Config.ModelWatchBranches are browsed again.
+const ModelPollInterval = 30 * time.Second
+
+// watchModelChanges keeps the cached address space of the session cli current: model change
+// events of the server update the branches they affect, and the marked branches are browsed
+// again every ModelPollInterval for servers that do not send such events.
+func (c *Controller) watchModelChanges(cli *opc.Client) {
+	ctx := c.GetClientContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	es, err := cli.MonitorModelChanges(func(changes []opc.ModelChange) {
+		c.applyModelChanges(ctx, cli, changes)
+	})
+	if err != nil {
+		c.Log(fmt.Sprintf("[yellow]The server does not report model changes (%v); branches marked to watch for changes are browsed again every %v[-]", err, ModelPollInterval))
+	} else {
+		defer es.Close()
+	}
+
+	ticker := time.NewTicker(ModelPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.mu.RLock()
+		current := c.client == cli
+		c.mu.RUnlock()
+		if !current {
+			return
+		}
+		c.pollModelBranches(ctx)
+	}
+}
+
+// IsModelWatched reports whether nodeID is marked to be browsed again periodically.
+func (c *Controller) IsModelWatched(nodeID string) bool {
+	ref := c.NodeRef(nodeID)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.currentConfig == nil {
+		return false
+	}
+	return slices.Contains(c.currentConfig.ModelWatchBranches, ref) || slices.Contains(c.currentConfig.ModelWatchBranches, nodeID)
+}
+
+// pollModelBranches browses the marked branches again that are in the cache; the others are
+// browsed fresh when the tree opens them.
+func (c *Controller) pollModelBranches(ctx context.Context) {
+	c.mu.RLock()
+	var refs []string
+	if c.currentConfig != nil {
+		refs = slices.Clone(c.currentConfig.ModelWatchBranches)
+	}
+	c.mu.RUnlock()
+	for _, ref := range refs {
+		nodeID, err := c.ResolveNodeRef(ref)
+		if err != nil || !c.HasBrowseBeenPerformed(nodeID) {
+			continue
+		}
+		c.rebrowseBranch(ctx, nodeID)
+	}
+}
+
+// applyModelChanges logs the changes of a model change event and browses the cached branches
+// they affect again. An event without details checks the marked branches.
+func (c *Controller) applyModelChanges(ctx context.Context, cli *opc.Client, changes []opc.ModelChange) {
+	if len(changes) == 0 {
+		c.Log("[cyan]The server reported a model change without details; checking the marked branches[-]")
+		c.pollModelBranches(ctx)
+		return
+	}
+	parents := make(map[string]bool)
+	for _, m := range changes {
+		c.Log(fmt.Sprintf("[cyan]Model change: %s %s[-]", m.VerbString(), m.Affected))
+		if c.HasBrowseBeenPerformed(m.Affected) {
+			parents[m.Affected] = true
+		}
+		if m.Verb&opc.ModelNodeDeleted != 0 {
+			for _, p := range c.cachedParents(m.Affected) {
+				parents[p] = true
+			}
+		}
+		if m.Verb&opc.ModelNodeAdded != 0 {
+			for _, p := range c.browseParents(ctx, cli, m.Affected) {
+				parents[p] = true
+			}
+		}
+	}
+	for p := range parents {
+		c.rebrowseBranch(ctx, p)
+	}
+}
+
+// cachedParents returns the cached branches listing nodeID as a child.
+func (c *Controller) cachedParents(nodeID string) []string {
+	c.addressSpaceMutex.RLock()
+	defer c.addressSpaceMutex.RUnlock()
+	var out []string
+	for p, children := range c.addressSpaceChildren {
+		if slices.Contains(children, nodeID) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// browseParents returns the cached sources of the inverse references of nodeID.
+func (c *Controller) browseParents(ctx context.Context, cli *opc.Client, nodeID string) []string {
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return nil
+	}
+	browseCtx, cancel := c.opContext(ctx, c.timeouts().Browse)
+	defer cancel()
+	refs, err := cli.BrowseReferences(browseCtx, id)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, r := range refs {
+		if r == nil || r.IsForward || r.NodeID == nil || r.NodeID.NodeID == nil {
+			continue
+		}
+		if p := r.NodeID.NodeID.String(); c.HasBrowseBeenPerformed(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// rebrowseBranch browses the cached branch parentID again, which also redraws it in the tree,
+// and logs the children that were added or removed.
+func (c *Controller) rebrowseBranch(ctx context.Context, parentID string) {
+	before := c.GetAddressSpaceChildren(parentID)
+	c.Browse(ctx, parentID)
+	after := c.GetAddressSpaceChildren(parentID)
+	if !slices.Equal(before, after) {
+		c.clearSearchCache(false)
+	}
+	for _, id := range after {
+		if !slices.Contains(before, id) {
+			name := id
+			if n := c.GetNode(id); n != nil {
+				name = n.Name + " (" + id + ")"
+			}
+			c.Log(fmt.Sprintf("[green]Node added under %s: %s[-]", parentID, name))
+		}
+	}
+	for _, id := range before {
+		if !slices.Contains(after, id) {
+			c.Log(fmt.Sprintf("[yellow]Node removed from %s: %s[-]", parentID, id))
+		}
+	}
+}
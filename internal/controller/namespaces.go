@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// WatchListRefs returns the watched nodes as "nsu=<uri>;<id>" references, so a saved watch list
+// survives servers reordering their namespaces. Nodes whose namespace is unknown keep their
+// index form; saved references the current server could not resolve are kept as they are.
+func (c *Controller) WatchListRefs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	refs := make([]string, 0, len(c.watchItems)+len(c.unresolvedWatches))
+	for id := range c.watchItems {
+		ref, err := opc.ExpandNodeID(id, c.namespaces)
+		if err != nil {
+			ref = id
+		}
+		refs = append(refs, ref)
+	}
+	refs = append(refs, c.unresolvedWatches...)
+	sort.Strings(refs)
+	return refs
+}
+
+// ApplyWatchListRefs makes the watch list of the current session match refs, saved references
+// as in Config.WatchList, e.g. after a synced watch list arrived. Without a session there is
+// nothing to do; the saved list is restored on connect.
+func (c *Controller) ApplyWatchListRefs(ctx context.Context, refs []string) {
+	c.mu.RLock()
+	live := c.client != nil || c.offline != nil
+	ns := c.namespaces
+	watched := make(map[string]bool, len(c.watchItems))
+	for id := range c.watchItems {
+		watched[id] = true
+	}
+	c.mu.RUnlock()
+	if !live {
+		return
+	}
+	want := make(map[string]bool, len(refs))
+	var add []string
+	for _, ref := range refs {
+		id, err := opc.ResolveNodeID(ref, ns)
+		if err != nil {
+			c.Log(fmt.Sprintf("[yellow]Watch %s not added: %v[-]", ref, err))
+			continue
+		}
+		want[id] = true
+		if !watched[id] {
+			add = append(add, id)
+		}
+	}
+	for id := range watched {
+		if !want[id] {
+			c.RemoveWatch(id)
+		}
+	}
+	for _, id := range add {
+		c.AddWatch(ctx, id)
+	}
+}
+
+// ResolveNodeRef converts a saved "nsu=<uri>;<id>" reference into a NodeID of the current
+// session; see NodeRef for the reverse.
+func (c *Controller) ResolveNodeRef(ref string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return opc.ResolveNodeID(ref, c.namespaces)
+}
+
+// loadNamespaces reads the NamespaceArray after a successful connect and restores the watch list
+// against it.
+func (c *Controller) loadNamespaces(cli *opc.Client) {
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	ns, err := cli.NamespaceArray(ctx)
+	cancel()
+	if err != nil {
+		c.Log(fmt.Sprintf("[yellow]Could not read NamespaceArray, namespace URIs are not resolved: %v[-]", err))
+	}
+
+	c.mu.RLock()
+	current := c.client == cli
+	c.mu.RUnlock()
+	if current {
+		c.restoreWatchList(ns)
+	}
+}
+
+// restoreWatchList switches to the namespace table ns and re-adds the watch list of the previous
+// session plus the saved one (Config.WatchList), translating namespace indexes by URI. Monitored
+// items of the previous session are dropped; they belonged to its client.
+func (c *Controller) restoreWatchList(ns []string) {
+	c.mu.Lock()
+	old := c.namespaces
+	c.namespaces = ns
+	refs := make([]string, 0, len(c.watchItems))
+	for id := range c.watchItems {
+		ref := id
+		if ns != nil {
+			// Without a namespace table (JSON offline exports) NodeIDs are kept as they are
+			if r, err := opc.ExpandNodeID(id, old); err == nil {
+				ref = r
+			}
+		}
+		refs = append(refs, ref)
+	}
+	refs = append(refs, c.unresolvedWatches...)
+	if c.currentConfig != nil {
+		refs = append(refs, c.currentConfig.WatchList...)
+	}
+	hadItems := len(c.watchItems) > 0
+	c.watchItems = make(map[string]*WatchItem)
+	c.unresolvedWatches = nil
+	cb := c.OnWatchListUpdate
+	c.mu.Unlock()
+
+	seenRef := make(map[string]bool, len(refs))
+	seenID := make(map[string]bool, len(refs))
+	var nodeIDs, unresolved []string
+	moved := 0
+	for _, ref := range refs {
+		if seenRef[ref] {
+			continue
+		}
+		seenRef[ref] = true
+		nodeID, err := opc.ResolveNodeID(ref, ns)
+		if err != nil {
+			c.Log(fmt.Sprintf("[yellow]Watch %s not restored: %v[-]", ref, err))
+			unresolved = append(unresolved, ref)
+			continue
+		}
+		if seenID[nodeID] {
+			continue
+		}
+		seenID[nodeID] = true
+		if strings.HasPrefix(ref, "nsu=") && old != nil {
+			if prev, err := opc.ResolveNodeID(ref, old); err == nil && prev != nodeID {
+				moved++
+			}
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	c.mu.Lock()
+	c.unresolvedWatches = unresolved
+	c.mu.Unlock()
+	if moved > 0 {
+		c.Log(fmt.Sprintf("[cyan]%d watched nodes moved to new namespace indexes[-]", moved))
+	}
+	for _, nodeID := range nodeIDs {
+		c.AddWatch(context.Background(), nodeID)
+	}
+	if len(nodeIDs) == 0 && hadItems && cb != nil {
+		cb(WatchListUpdate{Items: []*WatchItem{}})
+	}
+}
+
+// ParseNodeIDList splits a pasted list of NodeIDs (see opc.SplitNodeIDList) and validates each
+// entry against the current session, resolving "nsu=" references. Duplicates are dropped.
+func (c *Controller) ParseNodeIDList(text string) (ids []string, invalid []error) {
+	seen := make(map[string]bool)
+	for _, ref := range opc.SplitNodeIDList(text) {
+		if !opc.LooksLikeNodeID(ref) {
+			invalid = append(invalid, fmt.Errorf("%s: not a NodeID", ref))
+			continue
+		}
+		nodeID, err := c.ResolveNodeRef(ref)
+		if err == nil {
+			var n *ua.NodeID
+			if n, err = ua.ParseNodeID(nodeID); err == nil {
+				nodeID = n.String()
+			}
+		}
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("%s: %v", ref, err))
+			continue
+		}
+		if !seen[nodeID] {
+			seen[nodeID] = true
+			ids = append(ids, nodeID)
+		}
+	}
+	return ids, invalid
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// NodeMeta labels a node's values for API consumers (?include=meta), so they need no second
+// read per node.
+type NodeMeta struct {
+	DisplayName      string   `json:"display_name,omitempty"`
+	DataType         string   `json:"data_type,omitempty"`
+	EngineeringUnits string   `json:"engineering_units,omitempty"` // DisplayName of the EUInformation, e.g. "°C"
+	EURange          *EURange `json:"eu_range,omitempty"`
+	Alias            string   `json:"alias,omitempty"` // Config.NodeAliases
+	Path             string   `json:"path,omitempty"`  // browse path, when the node was browsed to
+}
+
+// EURange is the EURange property of an analog item.
+type EURange struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// NodeMeta returns the metadata of nodeID. What the server reports (DisplayName, DataType and
+// the EngineeringUnits and EURange properties) is read once per session and cached; the alias
+// and the path are looked up on every call. Without a session only those two are set.
+func (c *Controller) NodeMeta(ctx context.Context, nodeID string) *NodeMeta {
+	c.metaMu.Lock()
+	cached, ok := c.metaCache[nodeID]
+	c.metaMu.Unlock()
+	if !ok {
+		cached = c.readNodeMeta(ctx, nodeID)
+	}
+	meta := &NodeMeta{}
+	if cached != nil {
+		*meta = *cached
+	}
+	meta.Alias = c.NodeAlias(nodeID)
+	meta.Path = c.nodePath(nodeID)
+	return meta
+}
+
+// readNodeMeta reads the server side of NodeMeta and caches it; it returns nil when the node
+// could not be read.
+func (c *Controller) readNodeMeta(ctx context.Context, nodeID string) *NodeMeta {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil
+	}
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return nil
+	}
+	rctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+	results, err := cli.ReadAttributes(rctx, nodeID, ua.AttributeIDDisplayName, ua.AttributeIDDataType)
+	if err != nil || len(results) < 2 {
+		return nil
+	}
+	meta := &NodeMeta{}
+	if res := results[0]; res != nil && res.Status == ua.StatusOK && res.Value != nil {
+		switch lt := res.Value.Value().(type) {
+		case ua.LocalizedText:
+			meta.DisplayName = lt.Text
+		case *ua.LocalizedText:
+			if lt != nil {
+				meta.DisplayName = lt.Text
+			}
+		}
+	}
+	if res := results[1]; res != nil && res.Status == ua.StatusOK && res.Value != nil {
+		if dt, ok := res.Value.Value().(*ua.NodeID); ok {
+			meta.DataType = builtinTypeName(dt)
+		}
+	}
+
+	// EngineeringUnits and EURange are properties of AnalogItemType variables
+	if refs, err := cli.Browse(rctx, id); err == nil {
+		var props []*ua.NodeID
+		var names []string
+		for _, ref := range refs {
+			if ref.BrowseName == nil || ref.NodeID == nil || ref.BrowseName.NamespaceIndex != 0 {
+				continue
+			}
+			if ref.BrowseName.Name == "EngineeringUnits" || ref.BrowseName.Name == "EURange" {
+				props = append(props, ref.NodeID.NodeID)
+				names = append(names, ref.BrowseName.Name)
+			}
+		}
+		if len(props) > 0 {
+			if dvs, err := cli.ReadValues(rctx, props); err == nil {
+				for i, dv := range dvs {
+					if i < len(names) && dv != nil && dv.Status == ua.StatusOK {
+						meta.setProperty(names[i], dv.Value)
+					}
+				}
+			}
+		}
+	}
+
+	c.metaMu.Lock()
+	if c.metaCache == nil {
+		c.metaCache = make(map[string]*NodeMeta)
+	}
+	c.metaCache[nodeID] = meta
+	c.metaMu.Unlock()
+	return meta
+}
+
+func (m *NodeMeta) setProperty(name string, v *ua.Variant) {
+	if v == nil {
+		return
+	}
+	val := v.Value()
+	if eo, ok := val.(*ua.ExtensionObject); ok && eo != nil {
+		val = eo.Value
+	}
+	switch name {
+	case "EngineeringUnits":
+		var eu *ua.EUInformation
+		switch x := val.(type) {
+		case *ua.EUInformation:
+			eu = x
+		case ua.EUInformation:
+			eu = &x
+		}
+		if eu != nil && eu.DisplayName != nil {
+			m.EngineeringUnits = eu.DisplayName.Text
+		}
+	case "EURange":
+		switch x := val.(type) {
+		case *ua.Range:
+			if x != nil {
+				m.EURange = &EURange{Low: x.Low, High: x.High}
+			}
+		case ua.Range:
+			m.EURange = &EURange{Low: x.Low, High: x.High}
+		}
+	}
+}
+
+// clearNodeMeta drops the cached metadata, e.g. when the session ends.
+func (c *Controller) clearNodeMeta() {
+	c.metaMu.Lock()
+	c.metaCache = nil
+	c.metaMu.Unlock()
+}
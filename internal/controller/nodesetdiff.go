@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+
+	"opcuababy/internal/nodeset"
+)
+
+// nodeSetReadBatch is the number of baseline nodes checked per Read request.
+const nodeSetReadBatch = 250
+
+// NodeSetDiffEntry is one difference between a NodeSet2 baseline and the live server.
+type NodeSetDiffEntry struct {
+	Kind       string `json:"kind"`    // "missing", "extra", "node_class" or "data_type"
+	NodeID     string `json:"node_id"` // NodeID with the server's namespace indexes when resolvable
+	BrowseName string `json:"browse_name,omitempty"`
+	NodeClass  string `json:"node_class,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+}
+
+// NodeSetDiff is the result of comparing the live server against a NodeSet2 baseline.
+type NodeSetDiff struct {
+	Baseline    string             `json:"baseline"`
+	Endpoint    string             `json:"endpoint,omitempty"`
+	GeneratedAt string             `json:"generated_at"`
+	Checked     int                `json:"checked"`
+	Missing     int                `json:"missing"`
+	Extra       int                `json:"extra"`
+	Mismatched  int                `json:"mismatched"`
+	Entries     []NodeSetDiffEntry `json:"entries"`
+}
+
+// DiffNodeSet compares the connected server against the NodeSet2 file at path. Nodes of the
+// OPC UA namespace are skipped. Every baseline node is looked up (NodeClass, and DataType for
+// variables); nodes found on the server are browsed for children in the model's namespaces that
+// the baseline does not declare. progress, if set, is called with the checked and total counts.
+func (c *Controller) DiffNodeSet(path string, progress func(done, total int)) (*NodeSetDiff, error) {
+	c.mu.RLock()
+	cli := c.client
+	cfg := c.currentConfig
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil, errors.New("not connected to a server")
+	}
+
+	set, err := nodeset.ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse NodeSet2: %w", err)
+	}
+
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Export)
+	defer cancel()
+	serverNS, err := cli.NamespaceArray(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read NamespaceArray: %w", err)
+	}
+
+	diff := &NodeSetDiff{
+		Baseline:    filepath.Base(path),
+		GeneratedAt: c.formatISOTimestamp(cfg, time.Now()),
+	}
+	if cfg != nil {
+		diff.Endpoint = cfg.EndpointURL
+	}
+	add := func(e NodeSetDiffEntry) {
+		diff.Entries = append(diff.Entries, e)
+		switch e.Kind {
+		case "missing":
+			diff.Missing++
+		case "extra":
+			diff.Extra++
+		default:
+			diff.Mismatched++
+		}
+	}
+
+	// Resolve the baseline to server NodeIDs; nodes of unknown namespaces are missing outright
+	type checkNode struct {
+		node     *nodeset.Node
+		id       *ua.NodeID
+		dataType string
+	}
+	var checks []checkNode
+	known := make(map[string]bool)
+	modelNS := make(map[uint16]bool)
+	for _, n := range set.Nodes {
+		if strings.HasPrefix(n.NodeID, "i=") || strings.HasPrefix(n.NodeID, "ns=0;") {
+			continue
+		}
+		entry := NodeSetDiffEntry{BrowseName: n.BrowseName, NodeClass: nodeClassName(n.NodeClass)}
+		sid, err := set.Remap(n.NodeID, serverNS)
+		if err != nil {
+			entry.Kind, entry.NodeID, entry.Actual = "missing", n.NodeID, err.Error()
+			add(entry)
+			continue
+		}
+		id, err := ua.ParseNodeID(sid)
+		if err != nil {
+			continue
+		}
+		cn := checkNode{node: n, id: id}
+		if n.DataType != "" {
+			if cn.dataType, err = set.Remap(n.DataType, serverNS); err != nil {
+				cn.dataType = n.DataType
+			}
+		}
+		known[sid] = true
+		modelNS[id.Namespace()] = true
+		checks = append(checks, cn)
+	}
+
+	var present []checkNode
+	for start := 0; start < len(checks); start += nodeSetReadBatch {
+		end := min(start+nodeSetReadBatch, len(checks))
+		ids := make([]*ua.NodeID, 0, end-start)
+		for _, cn := range checks[start:end] {
+			ids = append(ids, cn.id)
+		}
+		results, err := cli.ReadMany(ctx, ids, ua.AttributeIDNodeClass, ua.AttributeIDDataType)
+		if err != nil {
+			return nil, fmt.Errorf("read baseline nodes: %w", err)
+		}
+		for i, cn := range checks[start:end] {
+			entry := NodeSetDiffEntry{NodeID: cn.id.String(), BrowseName: cn.node.BrowseName, NodeClass: nodeClassName(cn.node.NodeClass)}
+			classDV, typeDV := results[2*i], results[2*i+1]
+			if classDV == nil || classDV.Status != ua.StatusOK || classDV.Value == nil {
+				entry.Kind = "missing"
+				if classDV != nil && classDV.Status != ua.StatusOK {
+					entry.Actual = statusName(classDV.Status)
+				}
+				add(entry)
+				continue
+			}
+			present = append(present, cn)
+			actualClass := ua.NodeClass(toUint32(classDV.Value.Value()))
+			if actualClass != cn.node.NodeClass {
+				entry.Kind, entry.Expected, entry.Actual = "node_class", nodeClassName(cn.node.NodeClass), nodeClassName(actualClass)
+				add(entry)
+				continue
+			}
+			if cn.dataType == "" {
+				continue
+			}
+			actualType := ""
+			if typeDV != nil && typeDV.Status == ua.StatusOK && typeDV.Value != nil {
+				if dt, ok := typeDV.Value.Value().(*ua.NodeID); ok && dt != nil {
+					actualType = dt.String()
+				}
+			}
+			if actualType != cn.dataType {
+				entry.Kind = "data_type"
+				entry.Expected = dataTypeLabel(cn.dataType)
+				entry.Actual = dataTypeLabel(actualType)
+				add(entry)
+			}
+		}
+		if progress != nil {
+			progress(end, len(checks))
+		}
+	}
+
+	// Children in the model's namespaces that the baseline does not declare
+	seenExtra := make(map[string]bool)
+	for _, cn := range present {
+		if ctx.Err() != nil {
+			c.Log("[yellow]NodeSet diff: export timeout reached, extra-node search incomplete[-]")
+			break
+		}
+		refs, err := cli.Browse(ctx, cn.id)
+		if err != nil {
+			continue
+		}
+		for _, ref := range refs {
+			if ref == nil || ref.NodeID == nil || ref.NodeID.NodeID == nil {
+				continue
+			}
+			child := ref.NodeID.NodeID
+			cid := child.String()
+			if !modelNS[child.Namespace()] || known[cid] || seenExtra[cid] {
+				continue
+			}
+			seenExtra[cid] = true
+			e := NodeSetDiffEntry{Kind: "extra", NodeID: cid, NodeClass: nodeClassName(ref.NodeClass)}
+			if ref.BrowseName != nil {
+				e.BrowseName = fmt.Sprintf("%d:%s", ref.BrowseName.NamespaceIndex, ref.BrowseName.Name)
+			}
+			add(e)
+		}
+	}
+
+	diff.Checked = len(checks)
+	order := map[string]int{"missing": 0, "node_class": 1, "data_type": 2, "extra": 3}
+	sort.SliceStable(diff.Entries, func(i, j int) bool {
+		return order[diff.Entries[i].Kind] < order[diff.Entries[j].Kind]
+	})
+	c.Log(fmt.Sprintf("[cyan]NodeSet diff against %s: %d checked, %d missing, %d extra, %d mismatched[-]",
+		diff.Baseline, diff.Checked, diff.Missing, diff.Extra, diff.Mismatched))
+	return diff, nil
+}
+
+func nodeClassName(nc ua.NodeClass) string {
+	return strings.TrimPrefix(nc.String(), "NodeClass")
+}
+
+// dataTypeLabel shows standard DataTypes by name, e.g. "Double (i=11)".
+func dataTypeLabel(s string) string {
+	if s == "" {
+		return ""
+	}
+	if n, err := ua.ParseNodeID(s); err == nil {
+		if name := referenceTypeName(n); name != s {
+			return fmt.Sprintf("%s (%s)", name, s)
+		}
+	}
+	return s
+}
+
+func toUint32(v interface{}) uint32 {
+	switch x := v.(type) {
+	case int32:
+		return uint32(x)
+	case uint32:
+		return x
+	case int64:
+		return uint32(x)
+	}
+	return 0
+}
+
+// WriteNodeSetDiff writes d to path as CSV for .csv files and as JSON otherwise.
+func WriteNodeSetDiff(path string, d *NodeSetDiff) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := csv.NewWriter(f)
+		_ = w.Write([]string{"Kind", "NodeID", "BrowseName", "NodeClass", "Expected", "Actual"})
+		for _, e := range d.Entries {
+			_ = w.Write([]string{e.Kind, e.NodeID, e.BrowseName, e.NodeClass, e.Expected, e.Actual})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"sort"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+
+	"opcuababy/internal/nodeset"
+)
+
+// Standard folders of namespace 0 that NodeSet models attach to, with their own parents.
+var standardFolders = []struct {
+	nodeID, name, parent string
+}{
+	{"i=85", "Objects", "i=84"},
+	{"i=86", "Types", "i=84"},
+	{"i=87", "Views", "i=84"},
+	{"i=88", "ObjectTypes", "i=86"},
+	{"i=89", "VariableTypes", "i=86"},
+	{"i=90", "DataTypes", "i=86"},
+	{"i=91", "ReferenceTypes", "i=86"},
+}
+
+// addNodeSet loads the nodes of a NodeSet2 model into the offline address space. NodeIDs keep
+// the namespace indexes of the file. Nodes hang below their parent in the model; parents from
+// namespace 0 become folders, with the standard folders at their usual place and other base types
+// (e.g. BaseObjectType) in the type folder matching the child's NodeClass.
+func (st *offlineState) addNodeSet(set *nodeset.NodeSet, nodes map[string]*AddressSpaceNode, children map[string][]string) {
+	addFolder := func(nodeID, name, parent string) {
+		if _, ok := nodes[nodeID]; ok {
+			return
+		}
+		nodes[nodeID] = &AddressSpaceNode{NodeID: nodeID, Name: name, NodeClass: ua.NodeClassObject, HasChildren: true}
+		st.attrs[nodeID] = &NodeAttributes{NodeID: nodeID, NodeClass: "NodeClassObject", Name: name, ValueRank: -1}
+		children[parent] = append(children[parent], nodeID)
+	}
+	folder := make(map[string]struct{ name, parent string })
+	for _, f := range standardFolders {
+		folder[f.nodeID] = struct{ name, parent string }{f.name, f.parent}
+	}
+	var ensureStandard func(nodeID string)
+	ensureStandard = func(nodeID string) {
+		f := folder[nodeID]
+		if f.parent != "i=84" {
+			ensureStandard(f.parent)
+		}
+		addFolder(nodeID, f.name, f.parent)
+	}
+
+	for _, n := range set.Nodes {
+		if set.Node(n.NodeID) != n {
+			continue // duplicate declaration
+		}
+		parent := set.Parent(n)
+		switch {
+		case parent == "i=84" || parent != "" && set.Node(parent) != nil:
+		case parent != "" && folder[parent].name != "":
+			ensureStandard(parent)
+		case parent != "":
+			// Base type or other namespace 0 node: show it as a folder in the matching type folder
+			typeFolder := typeFolderFor(n.NodeClass)
+			ensureStandard(typeFolder)
+			name := parent
+			if pid, err := ua.ParseNodeID(parent); err == nil && pid.Namespace() == 0 {
+				if s := id.Name(pid.IntID()); s != "" {
+					name = s
+				}
+			}
+			addFolder(parent, name, typeFolder)
+		default:
+			parent = typeFolderFor(n.NodeClass)
+			ensureStandard(parent)
+		}
+
+		nodes[n.NodeID] = &AddressSpaceNode{NodeID: n.NodeID, Name: n.DisplayName, NodeClass: n.NodeClass}
+		a := &NodeAttributes{
+			NodeID:      n.NodeID,
+			NodeClass:   n.NodeClass.String(),
+			Name:        n.DisplayName,
+			Description: n.Description,
+			ValueRank:   int(n.ValueRank),
+		}
+		if n.NodeClass == ua.NodeClassVariable {
+			if dt, err := ua.ParseNodeID(n.DataType); err == nil {
+				a.DataType = builtinTypeName(dt)
+			}
+			a.AccessLevel = formatAccessLevel(ua.AccessLevelType(n.AccessLevel))
+			a.AccessLevelKnown = true
+		}
+		st.attrs[n.NodeID] = a
+		children[parent] = append(children[parent], n.NodeID)
+	}
+
+	for parentID, ids := range children {
+		if p := nodes[parentID]; p != nil {
+			p.HasChildren = true
+		}
+		sort.SliceStable(ids, func(i, j int) bool { return nodes[ids[i]].Name < nodes[ids[j]].Name })
+	}
+}
+
+// typeFolderFor returns the standard folder holding nodes of class nc without a known parent.
+func typeFolderFor(nc ua.NodeClass) string {
+	switch nc {
+	case ua.NodeClassObjectType:
+		return "i=88"
+	case ua.NodeClassVariableType:
+		return "i=89"
+	case ua.NodeClassDataType:
+		return "i=90"
+	case ua.NodeClassReferenceType:
+		return "i=91"
+	}
+	return "i=85"
+}
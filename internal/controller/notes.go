@@ -0,0 +1,35 @@
+package controller
+
+import "opcuababy/internal/opc"
+
+// NodeNote returns the saved note of nodeID, or "". The caller stores notes in
+// Config.NodeNotes under NodeRef; notes saved under the plain NodeID are found too.
+func (c *Controller) NodeNote(nodeID string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.currentConfig == nil || len(c.currentConfig.NodeNotes) == 0 {
+		return ""
+	}
+	if ref, err := opc.ExpandNodeID(nodeID, c.namespaces); err == nil {
+		if note, ok := c.currentConfig.NodeNotes[ref]; ok {
+			return note
+		}
+	}
+	return c.currentConfig.NodeNotes[nodeID]
+}
+
+// NodeAlias returns the saved alias of nodeID, or "". Aliases are stored in Config.NodeAliases
+// like notes.
+func (c *Controller) NodeAlias(nodeID string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.currentConfig == nil || len(c.currentConfig.NodeAliases) == 0 {
+		return ""
+	}
+	if ref, err := opc.ExpandNodeID(nodeID, c.namespaces); err == nil {
+		if alias, ok := c.currentConfig.NodeAliases[ref]; ok {
+			return alias
+		}
+	}
+	return c.currentConfig.NodeAliases[nodeID]
+}
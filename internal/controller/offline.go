@@ -0,0 +1,348 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+
+	"opcuababy/internal/exporter"
+	"opcuababy/internal/nodeset"
+	"opcuababy/internal/opc"
+)
+
+// Replay pacing for recordings: gaps between samples are reproduced up to maxReplayGap;
+// samples without a usable timestamp are spaced by defaultReplayGap.
+const (
+	defaultReplayGap = time.Second
+	maxReplayGap     = 5 * time.Second
+)
+
+// offlineState holds the address space and recording loaded for offline mode.
+type offlineState struct {
+	addressSpacePath string
+	recordingPath    string
+	attrs            map[string]*NodeAttributes
+	namespaces       []string // NamespaceArray of a NodeSet2 model; nil for JSON exports
+}
+
+// IsOffline reports whether the controller is serving a loaded address space instead of a live server.
+func (c *Controller) IsOffline() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offline != nil
+}
+
+// StartOffline loads an address space exported as JSON (see exporter.ExportToJSON) or a NodeSet2
+// XML model (.xml) and an optional recorded value stream, and serves them to the UI and API as if
+// connected. The recording is a JSON Lines file of watch items as sent by /ws/subscribe; it is
+// replayed in a loop with its original pacing. Writes are refused while offline. Disconnect
+// leaves offline mode.
+func (c *Controller) StartOffline(addressSpacePath, recordingPath string) error {
+	c.mu.RLock()
+	busy := c.client != nil || c.isConnecting || c.offline != nil
+	c.mu.RUnlock()
+	if busy {
+		return errors.New("disconnect before starting offline mode")
+	}
+
+	st := &offlineState{addressSpacePath: addressSpacePath, recordingPath: recordingPath, attrs: make(map[string]*NodeAttributes)}
+	nodes := make(map[string]*AddressSpaceNode)
+	children := make(map[string][]string)
+	if strings.EqualFold(filepath.Ext(addressSpacePath), ".xml") {
+		set, err := nodeset.ParseFile(addressSpacePath)
+		if err != nil {
+			return fmt.Errorf("load NodeSet2: %w", err)
+		}
+		st.addNodeSet(set, nodes, children)
+		st.namespaces = append([]string{opc.NamespaceZeroURI}, set.NamespaceURIs...)
+	} else {
+		root, err := loadExportTree(addressSpacePath)
+		if err != nil {
+			return fmt.Errorf("load address space: %w", err)
+		}
+		// The tree hangs below RootFolder (i=84) like a browsed address space; exports of a
+		// sub-folder appear as its only child.
+		if root.NodeID == "i=84" {
+			st.addNodes(root.Children, "i=84", nodes, children)
+		} else {
+			st.addNodes([]*exporter.ExportNode{root}, "i=84", nodes, children)
+		}
+	}
+	var records []*WatchItem
+	if recordingPath != "" {
+		var err error
+		if records, err = loadRecording(recordingPath); err != nil {
+			return fmt.Errorf("load recording: %w", err)
+		}
+	}
+	// Seed current values from the recording so watches start with its first sample
+	for _, r := range records {
+		if a, ok := st.attrs[r.NodeID]; ok && a.Value == "" {
+			a.Value = r.Value
+			a.ValueTyped = r.ValueTyped
+			a.UAType = r.UAType
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.clientLifecycleMutex.Lock()
+	c.clientCtx = ctx
+	c.clientCancel = cancel
+	c.clientLifecycleMutex.Unlock()
+
+	c.addressSpaceMutex.Lock()
+	c.addressSpaceNodes = nodes
+	c.addressSpaceChildren = children
+	c.addressSpaceMutex.Unlock()
+
+	c.mu.Lock()
+	c.offline = st
+	c.isConnected = true
+	c.mu.Unlock()
+
+	// Blocking sends so the UI learns the names of every loaded level
+	go func() {
+		for parentID := range children {
+			select {
+			case c.AddressSpaceUpdateChan <- parentID:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	if len(records) > 0 {
+		go c.replayRecording(ctx, records)
+	}
+
+	c.Log(fmt.Sprintf("[yellow]OFFLINE mode: %d nodes from %s, %d recorded samples. Writes are disabled.[-]", len(st.attrs), addressSpacePath, len(records)))
+	c.syncApiServer()
+	if c.OnConnectionStateChange != nil {
+		c.OnConnectionStateChange(true, "offline", nil)
+	}
+	c.restoreWatchList(st.namespaces)
+	return nil
+}
+
+func (st *offlineState) addNodes(list []*exporter.ExportNode, parentID string, nodes map[string]*AddressSpaceNode, children map[string][]string) {
+	ids := make([]string, 0, len(list))
+	for _, n := range list {
+		if n == nil || n.NodeID == "" {
+			continue
+		}
+		if _, dup := st.attrs[n.NodeID]; dup {
+			continue
+		}
+		class := parseNodeClassName(n.NodeClass)
+		name := n.Name
+		if name == "" {
+			name = n.NodeID
+		}
+		nodes[n.NodeID] = &AddressSpaceNode{
+			NodeID:      n.NodeID,
+			Name:        name,
+			NodeClass:   class,
+			HasChildren: len(n.Children) > 0,
+		}
+		st.attrs[n.NodeID] = &NodeAttributes{
+			NodeID:           n.NodeID,
+			NodeClass:        class.String(),
+			Name:             name,
+			Description:      n.Description,
+			BrowseName:       n.BrowseName,
+			NamespaceURI:     n.NamespaceURI,
+			DataType:         n.DataType,
+			AccessLevel:      n.AccessLevel,
+			AccessLevelKnown: n.AccessLevel != "",
+			Value:            n.Value,
+			ValueRank:        -1,
+		}
+		ids = append(ids, n.NodeID)
+		st.addNodes(n.Children, n.NodeID, nodes, children)
+	}
+	sort.Slice(ids, func(i, j int) bool { return nodes[ids[i]].Name < nodes[ids[j]].Name })
+	children[parentID] = ids
+}
+
+// parseNodeClassName accepts both "NodeClassVariable" (exporter) and "Variable".
+func parseNodeClassName(s string) ua.NodeClass {
+	s = strings.TrimPrefix(s, "NodeClass")
+	for _, nc := range []ua.NodeClass{
+		ua.NodeClassObject, ua.NodeClassVariable, ua.NodeClassMethod, ua.NodeClassObjectType,
+		ua.NodeClassVariableType, ua.NodeClassReferenceType, ua.NodeClassDataType, ua.NodeClassView,
+	} {
+		if strings.EqualFold(strings.TrimPrefix(nc.String(), "NodeClass"), s) {
+			return nc
+		}
+	}
+	return ua.NodeClassObject
+}
+
+func loadExportTree(path string) (*exporter.ExportNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return exporter.ParseJSONExport(data)
+}
+
+// loadRecording reads a JSON Lines stream of watch items. Blank and unparsable lines are skipped.
+func loadRecording(path string) ([]*WatchItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []*WatchItem
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var wi WatchItem
+		if err := json.Unmarshal([]byte(line), &wi); err != nil || wi.NodeID == "" {
+			continue
+		}
+		out = append(out, &wi)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no samples found")
+	}
+	return out, nil
+}
+
+func recordedTime(wi *WatchItem) (time.Time, bool) {
+	for _, s := range []string{wi.SourceTimestamp, wi.ServerTimestamp} {
+		if s == "" {
+			continue
+		}
+		if t, err := time.Parse(isoTimestampLayout, s); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// replayRecording feeds the recorded samples to the watch list until ctx is cancelled, starting
+// over when the end is reached.
+func (c *Controller) replayRecording(ctx context.Context, records []*WatchItem) {
+	for {
+		var prev time.Time
+		for i, r := range records {
+			gap := defaultReplayGap
+			t, ok := recordedTime(r)
+			if i == 0 {
+				gap = 0
+			} else if ok && !prev.IsZero() {
+				gap = t.Sub(prev)
+				if gap < 0 {
+					gap = 0
+				} else if gap > maxReplayGap {
+					gap = maxReplayGap
+				}
+			}
+			if ok {
+				prev = t
+			}
+			if gap > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(gap):
+				}
+			} else if ctx.Err() != nil {
+				return
+			}
+			c.applyOfflineSample(r)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(defaultReplayGap):
+		}
+	}
+}
+
+// applyOfflineSample updates the offline node value and, if watched, the watch item, stamping
+// the sample with the current time so the replay looks live.
+func (c *Controller) applyOfflineSample(r *WatchItem) {
+	now := time.Now()
+	c.mu.Lock()
+	if c.offline == nil {
+		c.mu.Unlock()
+		return
+	}
+	iso := c.formatISOTimestamp(c.currentConfig, now)
+	if a, ok := c.offline.attrs[r.NodeID]; ok {
+		a.Value = r.Value
+		a.ValueTyped = r.ValueTyped
+		a.UAType = r.UAType
+		a.SourceTimestamp = iso
+		a.ServerTimestamp = iso
+	}
+	item, ok := c.watchItems[r.NodeID]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	item.Value = r.Value
+	item.ValueTyped = r.ValueTyped
+	item.UAType = r.UAType
+	item.SourceTimestamp = iso
+	item.ServerTimestamp = iso
+	item.Timestamp = c.formatDisplayTimestamp(c.currentConfig, now)
+	item.Severity = r.Severity
+	if item.Severity == "" {
+		item.Severity = "Good"
+	}
+	item.SymbolicName = r.SymbolicName
+	item.RawCode = r.RawCode
+	c.applyForceLocked(item)
+	alert := c.checkGoldenLocked(item)
+	msg := *item
+	c.mu.Unlock()
+
+	if alert {
+		c.raiseGoldenAlert(&msg)
+	}
+	c.captureSample(&msg)
+	if c.OnValueChange != nil {
+		c.OnValueChange(msg)
+	}
+
+	c.markWatchDirty(r.NodeID)
+	c.broadcastWatch(&msg)
+}
+
+// offlineAttributes returns a copy of the loaded attributes of nodeID.
+func (c *Controller) offlineAttributes(nodeID string) (*NodeAttributes, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.offline == nil {
+		return nil, false, nil
+	}
+	a, ok := c.offline.attrs[nodeID]
+	if !ok {
+		return nil, true, fmt.Errorf("node %s not found in offline address space", nodeID)
+	}
+	cp := *a
+	cp.Timestamp = c.formatDisplayTimestamp(c.currentConfig, time.Now())
+	return &cp, true, nil
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// opContext derives the context of an OPC UA call from the caller's ctx. It ends when ctx is
+// cancelled (e.g. the HTTP request went away), after timeout, or when the session is closed by
+// Disconnect or Shutdown, so no call outlives its caller or the session. A nil ctx counts as
+// context.Background().
+func (c *Controller) opContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	session := c.GetClientContext()
+	if session == nil {
+		return opCtx, cancel
+	}
+	stop := context.AfterFunc(session, cancel)
+	return opCtx, func() {
+		stop()
+		cancel()
+	}
+}
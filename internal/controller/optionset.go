@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// OptionSet holds the bit names of an OptionSet DataType, taken from its OptionSetValues
+// property. Its values are unsigned integers, or OptionSet structures, whose bit i is Bits[i].
+type OptionSet struct {
+	DataType string   `json:"data_type"`
+	Bits     []string `json:"bits"` // "" for reserved bits
+}
+
+// standardOptionSets name the bits of the bitmask DataTypes of namespace 0 for servers that do
+// not expose their OptionSetValues.
+var standardOptionSets = map[uint32][]string{
+	15031: {"CurrentRead", "CurrentWrite", "HistoryRead", "HistoryWrite", "SemanticChange", "StatusWrite", "TimestampWrite"}, // AccessLevelType
+	15406: {"CurrentRead", "CurrentWrite", "HistoryRead", "HistoryWrite", "SemanticChange", "StatusWrite", "TimestampWrite",
+		"", "NonatomicRead", "NonatomicWrite", "WriteFullArrayOnly", "NoSubDataTypes"}, // AccessLevelExType
+	15033: {"SubscribeToEvents", "", "HistoryRead", "HistoryWrite"},                                  // EventNotifierType
+	95:    {"SigningRequired", "EncryptionRequired", "SessionRequired", "ApplyRestrictionsToBrowse"}, // AccessRestrictionType
+}
+
+func standardOptionSet(id *ua.NodeID) *OptionSet {
+	if id.Namespace() != 0 || id.Type() != ua.NodeIDTypeNumeric && id.Type() != ua.NodeIDTypeTwoByte && id.Type() != ua.NodeIDTypeFourByte {
+		return nil
+	}
+	if bits, ok := standardOptionSets[id.IntID()]; ok {
+		return &OptionSet{DataType: id.String(), Bits: bits}
+	}
+	return nil
+}
+
+// BitName is the name of bit i, "Bit<i>" when it has none.
+func (o *OptionSet) BitName(i int) string {
+	if i < len(o.Bits) && o.Bits[i] != "" {
+		return o.Bits[i]
+	}
+	return "Bit" + strconv.Itoa(i)
+}
+
+// Decode lists the set bits of v and the names of the named ones, e.g. "Bit0|Bit3: Running|
+// Enabled"; it is "" when no bit is set.
+func (o *OptionSet) Decode(v interface{}) (string, bool) {
+	mask, ok := optionBits(v)
+	if o == nil || !ok {
+		return "", false
+	}
+	var bits, names []string
+	for i := 0; i < 64; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		bits = append(bits, "Bit"+strconv.Itoa(i))
+		if i < len(o.Bits) && o.Bits[i] != "" {
+			names = append(names, o.Bits[i])
+		}
+	}
+	if len(bits) == 0 {
+		return "", true
+	}
+	if len(names) == 0 {
+		return strings.Join(bits, "|"), true
+	}
+	return strings.Join(bits, "|") + ": " + strings.Join(names, "|"), true
+}
+
+// Parse returns the mask of a number (decimal or 0x hex), of bit names and "Bit<i>" joined by
+// "|" or ",", or of a value with its flags as shown in the watch list, e.g. "9",
+// "Running|Enabled", "Bit0|Bit3" or "9 (Bit0|Bit3: Running|Enabled)".
+func (o *OptionSet) Parse(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " ("); i > 0 && strings.HasSuffix(s, ")") {
+		s = s[:i]
+	}
+	if n, err := strconv.ParseUint(s, 0, 64); err == nil {
+		return n, nil
+	}
+	var mask uint64
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		f = strings.TrimSpace(f)
+		bit := -1
+		for i := range o.Bits {
+			if o.Bits[i] != "" && strings.EqualFold(o.Bits[i], f) {
+				bit = i
+				break
+			}
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(f), "bit")); bit < 0 && err == nil && strings.HasPrefix(strings.ToLower(f), "bit") {
+			bit = n
+		}
+		if bit < 0 || bit > 63 {
+			return 0, fmt.Errorf("%q is not a bit of option set %s (%s)", f, o.DataType, strings.Join(o.names(), ", "))
+		}
+		mask |= 1 << bit
+	}
+	return mask, nil
+}
+
+func (o *OptionSet) names() []string {
+	var names []string
+	for _, n := range o.Bits {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// structValue is mask as an OptionSet structure covering the named bits.
+func (o *OptionSet) structValue(mask uint64) *ua.OptionSet {
+	n := max((len(o.Bits)+7)/8, 1)
+	os := &ua.OptionSet{Value: make([]byte, n), ValidBits: make([]byte, n)}
+	for i := 0; i < n*8 && i < 64; i++ {
+		if mask&(1<<i) != 0 {
+			os.Value[i/8] |= 1 << (i % 8)
+		}
+		if i < len(o.Bits) && o.Bits[i] != "" {
+			os.ValidBits[i/8] |= 1 << (i % 8)
+		}
+	}
+	return os
+}
+
+// optionBits returns the bits of an unsigned integer value or an OptionSet structure.
+func optionBits(v interface{}) (uint64, bool) {
+	switch x := v.(type) {
+	case uint8:
+		return uint64(x), true
+	case uint16:
+		return uint64(x), true
+	case uint32:
+		return uint64(x), true
+	case uint64:
+		return x, true
+	case *ua.ExtensionObject:
+		if x != nil {
+			return optionBits(x.Value)
+		}
+	case *ua.OptionSet:
+		if x != nil {
+			var mask uint64
+			for i, b := range x.Value {
+				if i < 8 {
+					mask |= uint64(b) << (8 * i)
+				}
+			}
+			return mask, true
+		}
+	case float64:
+		// Forced values parsed from JSON
+		return uint64(x), x >= 0 && x == float64(uint64(x))
+	}
+	return 0, false
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// pollConfiguredLocked reports whether nodeID is to be polled rather than monitored: in
+// PollAlways mode, once the server refused subscriptions in this session, or when it is listed
+// in Config.PolledNodes. Callers must hold c.mu.
+func (c *Controller) pollConfiguredLocked(nodeID string) bool {
+	if c.pollOnly {
+		return true
+	}
+	cfg := c.currentConfig
+	if cfg == nil {
+		return false
+	}
+	if cfg.PollMode == opc.PollAlways {
+		return true
+	}
+	ref, err := opc.ExpandNodeID(nodeID, c.namespaces)
+	for _, p := range cfg.PolledNodes {
+		if p == nodeID || (err == nil && p == ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// pollFallback decides whether a watch item the server would not monitor (err from
+// MonitorItem) is polled instead. Not when polling is off or the node itself is the problem; a
+// server refusing subscriptions altogether gets all further items polled right away.
+func (c *Controller) pollFallback(err error) bool {
+	var sc ua.StatusCode
+	if errors.As(err, &sc) {
+		switch sc {
+		case ua.StatusBadNodeIDUnknown, ua.StatusBadNodeIDInvalid, ua.StatusBadAttributeIDInvalid,
+			ua.StatusBadNotReadable, ua.StatusBadUserAccessDenied:
+			return false
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.currentConfig != nil && c.currentConfig.PollMode == opc.PollNever {
+		return false
+	}
+	if errors.As(err, &sc) {
+		switch sc {
+		case ua.StatusBadServiceUnsupported, ua.StatusBadNotSupported, ua.StatusBadNotImplemented,
+			ua.StatusBadTooManySubscriptions:
+			if !c.pollOnly {
+				c.pollOnly = true
+				go c.Log(fmt.Sprintf("[yellow]The server refuses subscriptions (%s); watch items are polled[-]", sc))
+			}
+		}
+	}
+	return true
+}
+
+// startPolling marks the watch item nodeID as polled and starts the poll loop of the session
+// cli if it is not running.
+func (c *Controller) startPolling(nodeID string, cli *opc.Client) {
+	c.mu.Lock()
+	it, ok := c.watchItems[nodeID]
+	if ok {
+		it.Polled = true
+	}
+	start := ok && c.pollClient != cli
+	if start {
+		c.pollClient = cli
+	}
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	c.markWatchDirty(nodeID)
+	if start {
+		ctx := c.GetClientContext()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		go c.pollLoop(ctx, cli)
+	}
+}
+
+// SetWatchPolled switches the watch item nodeID between polling and monitoring. The caller
+// stores the choice in Config.PolledNodes under NodeRef first. A node the server refuses to
+// monitor stays polled.
+func (c *Controller) SetWatchPolled(nodeID string, polled bool) {
+	c.mu.Lock()
+	it, ok := c.watchItems[nodeID]
+	cli := c.client
+	if !ok || cli == nil || it.Polled == polled {
+		c.mu.Unlock()
+		return
+	}
+	sub := it.subHandle
+	it.subHandle = nil
+	rate := it.RateMs
+	c.mu.Unlock()
+
+	if polled {
+		if sub != nil {
+			if err := sub.Close(); err != nil {
+				c.Log(fmt.Sprintf("[yellow]Failed to unmonitor %s: %v[-]", nodeID, err))
+			}
+		}
+		c.startPolling(nodeID, cli)
+		c.Log(fmt.Sprintf("[green]Polling %s every %v[-]", nodeID, c.intervals().Poll))
+		return
+	}
+	sub, err := cli.MonitorItemAt(nodeID, rateInterval(rate))
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Failed to monitor %s, polling continues: %v[-]", nodeID, err))
+		return
+	}
+	c.mu.Lock()
+	if it, ok := c.watchItems[nodeID]; ok {
+		it.Polled = false
+		it.subHandle = sub
+		sub = nil
+	}
+	c.mu.Unlock()
+	if sub != nil {
+		_ = sub.Close() // removed meanwhile
+	}
+	c.markWatchDirty(nodeID)
+	c.Log(fmt.Sprintf("[green]Monitoring %s started[-]", nodeID))
+}
+
+// pollLoop reads the polled watch items of the session cli every poll interval and feeds the
+// values through HandleDataChange, like subscription notifications. It ends with the session
+// or when no item is polled any more.
+func (c *Controller) pollLoop(ctx context.Context, cli *opc.Client) {
+	failing := false
+	for {
+		timer := time.NewTimer(c.intervals().Poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+
+		// Deciding to stop under the lock lets startPolling start a new loop without a gap
+		c.mu.Lock()
+		var ids []string
+		if ctx.Err() == nil && c.client == cli {
+			for id, it := range c.watchItems {
+				if it.Polled {
+					ids = append(ids, id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			if c.pollClient == cli {
+				c.pollClient = nil
+			}
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		sort.Strings(ids)
+		err := c.pollOnce(ctx, cli, ids)
+		switch {
+		case err != nil && !failing:
+			c.Log(fmt.Sprintf("[red]Polling %d watch items failed: %v[-]", len(ids), err))
+		case err == nil && failing:
+			c.Log("[green]Polling watch items works again[-]")
+		}
+		failing = err != nil
+	}
+}
+
+// pollOnce reads ids in one request and passes on the values that changed, in value or status,
+// since the last notification: a subscription reports nothing else either.
+func (c *Controller) pollOnce(ctx context.Context, cli *opc.Client, ids []string) error {
+	nodes := make([]*ua.NodeID, 0, len(ids))
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if n, err := ua.ParseNodeID(id); err == nil {
+			nodes = append(nodes, n)
+			valid = append(valid, id)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	readCtx, cancel := c.opContext(ctx, c.timeouts().Read)
+	dvs, err := cli.ReadValues(readCtx, nodes)
+	cancel()
+	if err != nil {
+		return err
+	}
+	for i, dv := range dvs {
+		c.mu.RLock()
+		var last *ua.DataValue
+		if it, ok := c.watchItems[valid[i]]; ok {
+			last = it.lastDataValue
+		}
+		c.mu.RUnlock()
+		if last != nil && sameDataValue(last, dv) {
+			continue
+		}
+		c.HandleDataChange(valid[i], dv)
+	}
+	return nil
+}
+
+// sameDataValue reports whether a and b have the same status and value.
+func sameDataValue(a, b *ua.DataValue) bool {
+	if a == nil || b == nil || a.Status != b.Status || (a.Value == nil) != (b.Value == nil) {
+		return false
+	}
+	return a.Value == nil || reflect.DeepEqual(a.Value.Value(), b.Value.Value())
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// TestEndpoint checks that endpoint is reachable and measures secure channel latency without
+// creating a session. It works independently of the current connection.
+func (c *Controller) TestEndpoint(endpoint string) (*opc.EndpointProbe, error) {
+	if err := opc.CheckEndpointScheme(endpoint); err != nil {
+		c.Log(fmt.Sprintf("[red]Endpoint test failed: %v[-]", err))
+		return nil, err
+	}
+	timeout := 10 * time.Second
+	c.mu.RLock()
+	if c.currentConfig != nil && c.currentConfig.ConnectTimeout > 0 {
+		timeout = time.Duration(c.currentConfig.ConnectTimeout * float64(time.Second))
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := c.opContext(context.Background(), timeout)
+	defer cancel()
+	p, err := opc.ProbeEndpoint(ctx, endpoint)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Endpoint test failed for %s: %v[-]", endpoint, err))
+		return nil, err
+	}
+	c.Log(fmt.Sprintf("[green]Endpoint %s reachable: channel open %s, round trip %s[-]",
+		endpoint, p.ChannelOpenTime.Round(time.Millisecond), p.RoundTrip.Round(time.Millisecond)))
+	return p, nil
+}
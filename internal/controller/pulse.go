@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// ToggleValue writes the negation of the current value of the Boolean node nodeID and returns
+// the value written.
+func (c *Controller) ToggleValue(ctx context.Context, nodeID string) (bool, error) {
+	if err := c.checkBooleanWrite(ctx, nodeID); err != nil {
+		return false, err
+	}
+	cur, err := c.readBoolValue(ctx, nodeID)
+	if err != nil {
+		return false, err
+	}
+	if err := c.writeScalar(ctx, nodeID, "Boolean", fmt.Sprint(!cur)); err != nil {
+		c.Log(fmt.Sprintf("[red]Toggle of %s failed: %v[-]", nodeID, err))
+		return cur, err
+	}
+	c.Log(fmt.Sprintf("[green]Toggled %s: %v -> %v[-]", nodeID, cur, !cur))
+	return !cur, nil
+}
+
+// PulseValue writes true to the Boolean node nodeID, waits width and writes false, e.g. to
+// trigger a command bit. It returns when the pulse is over. When ctx ends during the wait the
+// falling edge is written at once and ctx's error is returned; when that write fails the node is
+// left true and the error says so.
+func (c *Controller) PulseValue(ctx context.Context, nodeID string, width time.Duration) error {
+	if err := c.checkBooleanWrite(ctx, nodeID); err != nil {
+		return err
+	}
+	if err := c.writeScalar(ctx, nodeID, "Boolean", "true"); err != nil {
+		c.Log(fmt.Sprintf("[red]Pulse of %s failed: %v[-]", nodeID, err))
+		return err
+	}
+	timer := time.NewTimer(width)
+	defer timer.Stop()
+	var cancelled error
+	select {
+	case <-ctx.Done():
+		cancelled = ctx.Err()
+	case <-timer.C:
+	}
+	// The falling edge must not be skipped because ctx ended; the write timeout still bounds it
+	if err := c.writeScalar(context.WithoutCancel(ctx), nodeID, "Boolean", "false"); err != nil {
+		err = fmt.Errorf("%s was left true: %w", nodeID, err)
+		c.Log(fmt.Sprintf("[red]Pulse: %v[-]", err))
+		return err
+	}
+	if cancelled != nil {
+		c.Log(fmt.Sprintf("[yellow]Pulse of %s cut short: %v[-]", nodeID, cancelled))
+		return cancelled
+	}
+	c.Log(fmt.Sprintf("[green]Pulsed %s for %s[-]", nodeID, width))
+	return nil
+}
+
+// checkBooleanWrite checks that nodeID is a writable scalar Boolean.
+func (c *Controller) checkBooleanWrite(ctx context.Context, nodeID string) error {
+	if c.IsOffline() {
+		return errors.New("writes are disabled in offline mode")
+	}
+	a, err := c.ReadNodeAttributes(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	if err := c.checkWritable(a); err != nil {
+		return err
+	}
+	// Variables of an abstract DataType qualify while they hold a Boolean
+	if a.ValueRank >= 0 || a.DataType != "Boolean" && a.UAType != "Boolean" {
+		return fmt.Errorf("%s is not a scalar Boolean", nodeID)
+	}
+	return nil
+}
+
+func (c *Controller) readBoolValue(ctx context.Context, nodeID string) (bool, error) {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return false, errors.New("not connected")
+	}
+	ctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+	vals, err := cli.ReadAttributes(ctx, nodeID, ua.AttributeIDValue)
+	if err != nil {
+		return false, err
+	}
+	if len(vals) != 1 || vals[0] == nil || vals[0].Status != ua.StatusOK || vals[0].Value == nil {
+		return false, errors.New("current value is not readable")
+	}
+	b, ok := vals[0].Value.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("current value of %s is not a Boolean", nodeID)
+	}
+	return b, nil
+}
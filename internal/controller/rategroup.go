@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// rateInterval converts a sampling interval in milliseconds to the rate group it is monitored in.
+func rateInterval(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// rateForLocked looks up the saved sampling interval of nodeID, 0 when it uses the default
+// subscription. Callers must hold c.mu.
+func (c *Controller) rateForLocked(nodeID string) float64 {
+	if c.currentConfig == nil || len(c.currentConfig.WatchRates) == 0 {
+		return 0
+	}
+	keys := []string{nodeID}
+	if ref, err := opc.ExpandNodeID(nodeID, c.namespaces); err == nil && ref != nodeID {
+		keys = append([]string{ref}, keys...)
+	}
+	for _, k := range keys {
+		if ms, ok := c.currentConfig.WatchRates[k]; ok {
+			return ms
+		}
+	}
+	return 0
+}
+
+// SetWatchRate moves the watch item nodeID to the subscription of the sampling interval ms (0
+// for the default subscription). The caller stores ms in Config.WatchRates under NodeRef. Polled
+// items keep their poll interval and take the rate once they are monitored again.
+func (c *Controller) SetWatchRate(nodeID string, ms float64) {
+	c.mu.Lock()
+	it, ok := c.watchItems[nodeID]
+	cli := c.client
+	if !ok || it.RateMs == ms {
+		c.mu.Unlock()
+		return
+	}
+	it.RateMs = ms
+	if cli == nil || it.Polled {
+		c.mu.Unlock()
+		c.markWatchDirty(nodeID)
+		return
+	}
+	sub := it.subHandle
+	it.subHandle = nil
+	c.mu.Unlock()
+
+	if sub != nil {
+		if err := sub.Close(); err != nil {
+			c.Log(fmt.Sprintf("[yellow]Failed to unmonitor %s: %v[-]", nodeID, err))
+		}
+	}
+	sub, err := cli.MonitorItemAt(nodeID, rateInterval(ms))
+	if err != nil {
+		if c.pollFallback(err) {
+			c.startPolling(nodeID, cli)
+			c.Log(fmt.Sprintf("[yellow]Cannot monitor %s (%v); polling every %v instead[-]", nodeID, err, c.intervals().Poll))
+		} else {
+			c.Log(fmt.Sprintf("[red]Failed to monitor %s: %v[-]", nodeID, err))
+		}
+		return
+	}
+	c.mu.Lock()
+	if it, ok := c.watchItems[nodeID]; ok && it.subHandle == nil && !it.Polled {
+		it.subHandle = sub
+		sub = nil
+	}
+	c.mu.Unlock()
+	if sub != nil {
+		_ = sub.Close() // removed or switched meanwhile
+	}
+	c.markWatchDirty(nodeID)
+	if ms > 0 {
+		c.Log(fmt.Sprintf("[green]Monitoring %s at %v[-]", nodeID, rateInterval(ms)))
+	} else {
+		c.Log(fmt.Sprintf("[green]Monitoring %s in the default subscription[-]", nodeID))
+	}
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// rawHistoryLen is how many DataValues each watch item keeps for WatchDataValues.
+const rawHistoryLen = 20
+
+// receivedValue is a DataValue as it arrived, with the time it was handled.
+type receivedValue struct {
+	dv *ua.DataValue
+	at time.Time
+}
+
+// RawDataValue is a DataValue as received from the server, broken down for debugging type and
+// status problems.
+type RawDataValue struct {
+	Received     time.Time
+	EncodingMask byte     // DataValue encoding mask
+	Fields       []string // fields present according to EncodingMask
+
+	VariantType     string  // built-in type of the variant, e.g. "Double" or "Int32[]"; empty without a value
+	VariantMask     byte    // variant encoding mask: type ID plus array flags
+	ArrayDimensions []int32 // set for multi-dimensional arrays
+	Value           string
+
+	SourceTimestamp   time.Time
+	SourcePicoseconds uint16
+	ServerTimestamp   time.Time
+	ServerPicoseconds uint16
+
+	Status           ua.StatusCode
+	RawCode          string // e.g. "0x80340000"
+	Severity         string // Good, Uncertain or Bad
+	SymbolicName     string
+	SubCode          uint16
+	StructureChanged bool
+	SemanticsChanged bool
+	InfoBits         uint16
+	InfoType         string // "DataValue" when the info bits describe the value, otherwise "NotUsed"
+	Limit            string // None, Low, High or Constant (InfoType DataValue only)
+	Overflow         bool
+	HistorianBits    []string // Calculated, Interpolated, Partial, ExtraData, MultiValue
+}
+
+var dataValueFields = []struct {
+	mask byte
+	name string
+}{
+	{ua.DataValueValue, "Value"},
+	{ua.DataValueStatusCode, "StatusCode"},
+	{ua.DataValueSourceTimestamp, "SourceTimestamp"},
+	{ua.DataValueServerTimestamp, "ServerTimestamp"},
+	{ua.DataValueSourcePicoseconds, "SourcePicoseconds"},
+	{ua.DataValueServerPicoseconds, "ServerPicoseconds"},
+}
+
+// newRawDataValue breaks dv down; dataType is the node's DataType used to format the value.
+func newRawDataValue(dv *ua.DataValue, at time.Time, dataType string) RawDataValue {
+	r := RawDataValue{
+		Received:          at,
+		EncodingMask:      dv.EncodingMask,
+		SourceTimestamp:   dv.SourceTimestamp,
+		SourcePicoseconds: dv.SourcePicoseconds,
+		ServerTimestamp:   dv.ServerTimestamp,
+		ServerPicoseconds: dv.ServerPicoseconds,
+		Status:            dv.Status,
+		Value:             "<nil>",
+	}
+	for _, f := range dataValueFields {
+		if dv.EncodingMask&f.mask != 0 {
+			r.Fields = append(r.Fields, f.name)
+		}
+	}
+	if v := dv.Value; v != nil {
+		r.VariantType = uaTypeName(v)
+		r.VariantMask = v.EncodingMask()
+		if len(v.ArrayDimensions()) > 1 {
+			r.ArrayDimensions = v.ArrayDimensions()
+		}
+		r.Value = formatValue(v, dataType)
+	}
+	r.Severity, r.SymbolicName, r.SubCode, r.StructureChanged, r.SemanticsChanged, r.InfoBits, r.RawCode = decodeStatusCode(dv.Status)
+
+	// Info bits, OPC UA Part 4 7.39.1
+	bits := uint32(dv.Status)
+	r.InfoType = "NotUsed"
+	if bits&0x0C00 == 0x0400 {
+		r.InfoType = "DataValue"
+		r.Limit = []string{"None", "Low", "High", "Constant"}[(bits>>8)&0x3]
+		r.Overflow = bits&0x0080 != 0
+		switch bits & 0x3 {
+		case 1:
+			r.HistorianBits = append(r.HistorianBits, "Calculated")
+		case 2:
+			r.HistorianBits = append(r.HistorianBits, "Interpolated")
+		}
+		for _, h := range []struct {
+			bit  uint32
+			name string
+		}{{0x4, "Partial"}, {0x8, "ExtraData"}, {0x10, "MultiValue"}} {
+			if bits&h.bit != 0 {
+				r.HistorianBits = append(r.HistorianBits, h.name)
+			}
+		}
+	}
+	return r
+}
+
+// appendReceived adds dv to the recent values of item, dropping the oldest beyond rawHistoryLen.
+func appendReceived(item *WatchItem, dv *ua.DataValue, at time.Time) {
+	if len(item.recent) >= rawHistoryLen {
+		item.recent = append(item.recent[:0], item.recent[len(item.recent)-rawHistoryLen+1:]...)
+	}
+	item.recent = append(item.recent, receivedValue{dv: dv, at: at})
+}
+
+// WatchDataValues returns the last DataValues received for the watch item nodeID, newest first,
+// or nil when it is not watched or nothing was received yet.
+func (c *Controller) WatchDataValues(nodeID string) []RawDataValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.watchItems[nodeID]
+	if !ok {
+		return nil
+	}
+	out := make([]RawDataValue, 0, len(item.recent))
+	for i := len(item.recent) - 1; i >= 0; i-- {
+		out = append(out, newRawDataValue(item.recent[i].dv, item.recent[i].at, item.DataType))
+	}
+	return out
+}
+
+// WatchPoints returns the numeric values last received for the watch item nodeID, oldest first,
+// or nil when it is not watched.
+func (c *Controller) WatchPoints(nodeID string) []HistoryPoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.watchItems[nodeID]
+	if !ok {
+		return nil
+	}
+	out := make([]HistoryPoint, 0, len(item.recent))
+	for _, r := range item.recent {
+		if p, ok := dataValuePoint(r.dv, r.at); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// FormatPicoTime renders t with its picoseconds past the 100 ns resolution of DateTime, e.g.
+// "2024-05-01T10:00:00.1234567Z +250ps"; empty for a zero time.
+func FormatPicoTime(t time.Time, pico uint16) string {
+	if t.IsZero() {
+		return ""
+	}
+	s := t.UTC().Format("2006-01-02T15:04:05.0000000Z")
+	if pico > 0 {
+		s += fmt.Sprintf(" +%dps", pico)
+	}
+	return s
+}
+
+// StatusBits describes the flag bits of r in one line, e.g. "StructureChanged, Limit=High".
+func (r RawDataValue) StatusBits() string {
+	var parts []string
+	if r.StructureChanged {
+		parts = append(parts, "StructureChanged")
+	}
+	if r.SemanticsChanged {
+		parts = append(parts, "SemanticsChanged")
+	}
+	if r.InfoType == "DataValue" {
+		parts = append(parts, "Limit="+r.Limit)
+		if r.Overflow {
+			parts = append(parts, "Overflow")
+		}
+		parts = append(parts, r.HistorianBits...)
+	}
+	return strings.Join(parts, ", ")
+}
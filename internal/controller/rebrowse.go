@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RefreshBranch discards the cached children of nodeID and of every branch below it and browses
+// nodeID again, so nodes created on the server since the last browse show up without
+// reconnecting. Branches below are browsed again when the tree asks for them. An empty nodeID
+// discards the whole cache and browses the RootFolder.
+func (c *Controller) RefreshBranch(ctx context.Context, nodeID string) error {
+	c.mu.RLock()
+	session := c.clientCtx
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return errors.New("an offline address space cannot be browsed again")
+	}
+	if cli == nil || session == nil {
+		return errors.New("not connected")
+	}
+
+	root := nodeID
+	if root == "" {
+		root = "i=84"
+	}
+	if c.IsBrowsing(root) {
+		return fmt.Errorf("%s is being browsed", root)
+	}
+	n := c.invalidateBranch(nodeID)
+	c.Browse(ctx, root)
+	if !c.HasBrowseBeenPerformed(root) {
+		return fmt.Errorf("browse of %s failed, see the log", root)
+	}
+	if nodeID == "" {
+		c.Log("[green]Address space cache cleared, browsing again[-]")
+	} else {
+		c.Log(fmt.Sprintf("[green]Refreshed %s (%d cached branches discarded)[-]", nodeID, n))
+	}
+	return nil
+}
+
+// invalidateBranch drops the cached children of nodeID and its descendants, or the whole cache
+// if nodeID is empty, and returns the number of branches dropped. Node entries are kept; the
+// next browse of their parent overwrites them.
+func (c *Controller) invalidateBranch(nodeID string) int {
+	c.clearSearchCache(false)
+	if nodeID == "" {
+		c.addressSpaceMutex.Lock()
+		n := len(c.addressSpaceChildren)
+		c.addressSpaceNodes = make(map[string]*AddressSpaceNode)
+		c.addressSpaceChildren = make(map[string][]string)
+		c.addressSpaceMutex.Unlock()
+		c.mu.Lock()
+		c.noChildrenCached = make(map[string]bool)
+		c.mu.Unlock()
+		return n
+	}
+	c.addressSpaceMutex.Lock()
+	defer c.addressSpaceMutex.Unlock()
+	n := 0
+	seen := map[string]bool{nodeID: true}
+	queue := []string{nodeID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		children, ok := c.addressSpaceChildren[id]
+		if !ok {
+			continue
+		}
+		delete(c.addressSpaceChildren, id)
+		n++
+		for _, ch := range children {
+			if !seen[ch] {
+				seen[ch] = true
+				queue = append(queue, ch)
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// linkErrorWindow is how long before a connection loss an error is taken as its cause.
+const linkErrorWindow = 30 * time.Second
+
+// LinkState describes the connection after it dropped unexpectedly, for the reconnect banner.
+type LinkState struct {
+	Down    bool      // false once the stack restored the connection
+	Reason  string    // the last error before the loss, if one was seen
+	Since   time.Time // when the connection was lost
+	Aborted bool      // the stack gave up reconnecting, e.g. because the server refused it
+}
+
+func (c *Controller) notifyLinkState(st LinkState) {
+	if c.OnLinkStateChange != nil {
+		c.OnLinkStateChange(st)
+	}
+}
+
+// lostReason returns the error that most likely caused a connection loss of cli, or "".
+func lostReason(cli *opc.Client) string {
+	if cli == nil {
+		return ""
+	}
+	if err := cli.LastError(linkErrorWindow); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// HandleConnectionLost is called by the client when the connection drops and the stack starts
+// reconnecting. The watch list is kept; the subscription normally survives on the server.
+func (c *Controller) HandleConnectionLost() {
+	c.mu.Lock()
+	c.linkDown = true
+	cli := c.client
+	c.mu.Unlock()
+	reason := lostReason(cli)
+	if reason != "" {
+		c.Log(fmt.Sprintf("[yellow]Connection lost (%s), reconnecting; the subscription is kept on the server meanwhile[-]", reason))
+	} else {
+		c.Log("[yellow]Connection lost, reconnecting; the subscription is kept on the server meanwhile[-]")
+	}
+	c.emitConnectionEvent(EventConnectionLost, "", "connection lost, reconnecting")
+	c.notifyLinkState(LinkState{Down: true, Reason: reason, Since: time.Now()})
+}
+
+// HandleReconnected is called by the client once the session is back.
+func (c *Controller) HandleReconnected(sub opc.SubscriptionRecovery) {
+	switch sub {
+	case opc.SubscriptionTransferred:
+		c.Log("[green]Reconnected: subscription transferred to the session, monitored items kept[-]")
+	case opc.SubscriptionRecreated:
+		c.mu.RLock()
+		n := len(c.watchItems)
+		c.mu.RUnlock()
+		c.Log(fmt.Sprintf("[yellow]Reconnected: the server had dropped the subscription, %d monitored items were recreated[-]", n))
+	default:
+		c.Log("[green]Reconnected[-]")
+	}
+	c.emitConnectionEvent(EventConnectionRestored, "", "reconnected")
+	c.mu.Lock()
+	c.linkDown = false
+	c.mu.Unlock()
+	c.notifyLinkState(LinkState{})
+	go c.replayWriteQueue()
+}
+
+// HandleReconnectAborted is called by the client when the stack gave up reconnecting. The
+// session stays down until it is connected again (see the reconnect banner of the UI).
+func (c *Controller) HandleReconnectAborted() {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	reason := lostReason(cli)
+	if reason != "" {
+		c.Log(fmt.Sprintf("[red]Reconnecting stopped: %s[-]", reason))
+	} else {
+		c.Log("[red]Reconnecting stopped: the server refused the connection[-]")
+	}
+	c.notifyLinkState(LinkState{Down: true, Reason: reason, Since: time.Now(), Aborted: true})
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ConnectEndpoint connects with the current settings, optionally to another endpoint, for remote
+// control through the API. The settings themselves are left unchanged.
+func (c *Controller) ConnectEndpoint(endpoint string) error {
+	c.mu.RLock()
+	cfg := c.currentConfig
+	c.mu.RUnlock()
+	if cfg == nil {
+		return errors.New("no connection settings")
+	}
+	cp := *cfg
+	if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+		cp.EndpointURL = endpoint
+	}
+	return c.Connect(&cp)
+}
+
+// WatchSnapshot returns copies of the watched items, sorted by NodeID.
+func (c *Controller) WatchSnapshot() []*WatchItem {
+	c.mu.RLock()
+	items := make([]*WatchItem, 0, len(c.watchItems))
+	for _, it := range c.watchItems {
+		cp := *it
+		cp.subHandle = nil
+		cp.lastDataValue = nil
+		cp.recent = nil
+		items = append(items, &cp)
+	}
+	c.mu.RUnlock()
+	sort.Slice(items, func(i, j int) bool { return items[i].NodeID < items[j].NodeID })
+	return items
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// NodeReference is one reference of a node in a NodeReport.
+type NodeReference struct {
+	ReferenceType string `json:"reference_type"`
+	Direction     string `json:"direction"` // "Forward" or "Inverse"
+	TargetNodeID  string `json:"target_node_id"`
+	BrowseName    string `json:"browse_name"`
+	DisplayName   string `json:"display_name"`
+	NodeClass     string `json:"node_class"`
+}
+
+// NodeReport collects everything shown for a node, for commissioning reports.
+type NodeReport struct {
+	GeneratedAt string          `json:"generated_at"`
+	Endpoint    string          `json:"endpoint,omitempty"`
+	Path        string          `json:"path,omitempty"`
+	Attributes  *NodeAttributes `json:"attributes"`
+	Note        string          `json:"note,omitempty"`
+	References  []NodeReference `json:"references,omitempty"`
+}
+
+// BuildNodeReport reads the attributes and all references of nodeID. The path is taken from the
+// browsed address space and is empty if the node has not been reached through the tree.
+func (c *Controller) BuildNodeReport(nodeID string) (*NodeReport, error) {
+	attrs, err := c.ReadNodeAttributes(context.Background(), nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	cli := c.client
+	cfg := c.currentConfig
+	c.mu.RUnlock()
+
+	r := &NodeReport{
+		GeneratedAt: c.formatISOTimestamp(cfg, time.Now()),
+		Path:        c.nodePath(nodeID),
+		Attributes:  attrs,
+		Note:        c.NodeNote(nodeID),
+	}
+	if cfg != nil {
+		r.Endpoint = cfg.EndpointURL
+	}
+	if cli == nil {
+		return r, nil
+	}
+
+	nID, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Browse)
+	defer cancel()
+	refs, err := cli.BrowseReferences(ctx, nID)
+	if err != nil {
+		c.Log(fmt.Sprintf("[yellow]Could not browse references of %s: %v[-]", nodeID, err))
+		return r, nil
+	}
+	for _, ref := range refs {
+		if ref == nil || ref.NodeID == nil {
+			continue
+		}
+		nr := NodeReference{
+			ReferenceType: referenceTypeName(ref.ReferenceTypeID),
+			Direction:     "Forward",
+			TargetNodeID:  ref.NodeID.String(),
+			DisplayName:   ref.DisplayName.Text,
+			NodeClass:     strings.TrimPrefix(ref.NodeClass.String(), "NodeClass"),
+		}
+		if ref.NodeID.NodeID != nil {
+			nr.TargetNodeID = ref.NodeID.NodeID.String()
+		}
+		if !ref.IsForward {
+			nr.Direction = "Inverse"
+		}
+		if ref.BrowseName != nil {
+			nr.BrowseName = ref.BrowseName.Name
+			if ref.BrowseName.NamespaceIndex != 0 {
+				nr.BrowseName = fmt.Sprintf("%d:%s", ref.BrowseName.NamespaceIndex, ref.BrowseName.Name)
+			}
+		}
+		r.References = append(r.References, nr)
+	}
+	return r, nil
+}
+
+func referenceTypeName(n *ua.NodeID) string {
+	if n == nil {
+		return ""
+	}
+	if n.Namespace() == 0 {
+		if name := id.Name(n.IntID()); name != "" {
+			return name
+		}
+	}
+	return n.String()
+}
+
+// nodePath returns the display path of nodeID from RootFolder in the browsed address space.
+func (c *Controller) nodePath(nodeID string) string {
+	c.addressSpaceMutex.RLock()
+	defer c.addressSpaceMutex.RUnlock()
+
+	parent := make(map[string]string, len(c.addressSpaceNodes))
+	for p, children := range c.addressSpaceChildren {
+		for _, ch := range children {
+			if _, seen := parent[ch]; !seen {
+				parent[ch] = p
+			}
+		}
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for cur := nodeID; cur != "" && cur != "i=84" && !seen[cur]; cur = parent[cur] {
+		seen[cur] = true
+		n := c.addressSpaceNodes[cur]
+		if n == nil {
+			return ""
+		}
+		names = append(names, n.Name)
+		if _, ok := parent[cur]; !ok {
+			return ""
+		}
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return "/" + strings.Join(names, "/")
+}
+
+// reportRows returns the attribute rows in display order, with unavailable attributes marked,
+// followed by the note if there is one.
+func (r *NodeReport) reportRows() [][2]string {
+	a := r.Attributes
+	rows := [][2]string{
+		{"NodeID", a.NodeID},
+		{"NodeClass", a.NodeClass},
+		{"DisplayName", a.Name},
+		{"Description", a.Description},
+		{"DataType", a.DataType},
+		{"ValueRank", fmt.Sprint(a.ValueRank)},
+		{"AccessLevel", a.AccessLevel},
+		{"Value", a.Value},
+		{"SourceTimestamp", a.SourceTimestamp},
+		{"ServerTimestamp", a.ServerTimestamp},
+	}
+	for i, row := range rows {
+		if st, ok := a.AttributeStatus[row[0]]; ok {
+			rows[i][1] = UnavailableText(st)
+		}
+	}
+	if r.Note != "" {
+		rows = append(rows, [2]string{"Note", r.Note})
+	}
+	return rows
+}
+
+// JSON returns the report as indented JSON.
+func (r *NodeReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown returns the report as Markdown tables.
+func (r *NodeReport) Markdown() string {
+	esc := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.ReplaceAll(s, "\n", "<br>")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", esc(r.Attributes.Name))
+	if r.Path != "" {
+		fmt.Fprintf(&b, "Path: `%s`  \n", r.Path)
+	}
+	if r.Endpoint != "" {
+		fmt.Fprintf(&b, "Endpoint: `%s`  \n", r.Endpoint)
+	}
+	fmt.Fprintf(&b, "Generated: %s\n\n", r.GeneratedAt)
+	b.WriteString("| Attribute | Value |\n|---|---|\n")
+	for _, row := range r.reportRows() {
+		fmt.Fprintf(&b, "| %s | %s |\n", row[0], esc(row[1]))
+	}
+	if len(r.References) > 0 {
+		b.WriteString("\n| Reference | Direction | Target | BrowseName | NodeClass |\n|---|---|---|---|---|\n")
+		for _, ref := range r.References {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", esc(ref.ReferenceType), ref.Direction,
+				esc(ref.TargetNodeID), esc(ref.BrowseName), ref.NodeClass)
+		}
+	}
+	return b.String()
+}
+
+var nodeReportHTML = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Report.Attributes.Name}}</title>
+<style>body{font-family:sans-serif}table{border-collapse:collapse;margin-bottom:1em}td,th{border:1px solid #ccc;padding:4px 8px;text-align:left;vertical-align:top}th{background:#f2f2f2}</style>
+</head><body>
+<h2>{{.Report.Attributes.Name}}</h2>
+<p>{{if .Report.Path}}Path: <code>{{.Report.Path}}</code><br>{{end}}{{if .Report.Endpoint}}Endpoint: <code>{{.Report.Endpoint}}</code><br>{{end}}Generated: {{.Report.GeneratedAt}}</p>
+<table><tr><th>Attribute</th><th>Value</th></tr>{{range .Rows}}
+<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>{{end}}
+</table>
+{{if .Report.References}}<table><tr><th>Reference</th><th>Direction</th><th>Target</th><th>BrowseName</th><th>NodeClass</th></tr>{{range .Report.References}}
+<tr><td>{{.ReferenceType}}</td><td>{{.Direction}}</td><td>{{.TargetNodeID}}</td><td>{{.BrowseName}}</td><td>{{.NodeClass}}</td></tr>{{end}}
+</table>{{end}}
+</body></html>
+`))
+
+// WriteNodeReport writes r to path as HTML for .html/.htm files and as JSON otherwise.
+func WriteNodeReport(path string, r *NodeReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return nodeReportHTML.Execute(f, struct {
+			Report *NodeReport
+			Rows   [][2]string
+		}{r, r.reportRows()})
+	default:
+		data, err := r.JSON()
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+}
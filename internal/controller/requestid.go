@@ -0,0 +1,33 @@
+package controller
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the correlation ID of an API request. Controller
+// operations started with that context prefix their log lines with "[req <id>]".
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the correlation ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// logFor returns c.Log, prefixing messages with the request ID of ctx when it has one.
+func (c *Controller) logFor(ctx context.Context) func(string) {
+	id := RequestID(ctx)
+	if id == "" {
+		return c.Log
+	}
+	prefix := "[req " + id + "] "
+	return func(msg string) { c.Log(prefix + msg) }
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// Scheduled write states
+const (
+	ScheduledPending   = "pending"
+	ScheduledRunning   = "running"
+	ScheduledDone      = "done"
+	ScheduledFailed    = "failed"
+	ScheduledCancelled = "cancelled"
+)
+
+// ScheduledWrite is a write planned for a later time, optionally ramping a numeric value from
+// its current value to Target in Steps equal steps over Ramp.
+type ScheduledWrite struct {
+	ID       int           `json:"id"`
+	NodeID   string        `json:"node_id"`
+	DataType string        `json:"data_type"`
+	Target   string        `json:"target"`
+	At       time.Time     `json:"at"`
+	Ramp     time.Duration `json:"ramp"`
+	Steps    int           `json:"steps"`
+	Step     int           `json:"step"` // steps written so far
+	Status   string        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// ScheduleWrite plans a write of target to nodeID at the given time (now if zero). With ramp > 0
+// the value moves from the node's current value to target in steps writes spread evenly over
+// ramp; ramps need a numeric scalar DataType. Only scalar values are supported.
+func (c *Controller) ScheduleWrite(nodeID, target string, at time.Time, ramp time.Duration, steps int) (*ScheduledWrite, error) {
+	if c.IsOffline() {
+		return nil, errors.New("writes are disabled in offline mode")
+	}
+	a, err := c.ReadNodeAttributes(context.Background(), nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkWritable(a); err != nil {
+		return nil, err
+	}
+	if a.ValueRank >= 0 {
+		return nil, errors.New("scheduled writes support scalar values only")
+	}
+	dataType := a.DataType
+	if a.Enum != nil {
+		// Enumerations are written as Int32; a symbolic name is replaced by its value
+		n, err := a.Enum.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		target, dataType = strconv.FormatInt(n, 10), "Int32"
+	} else if a.OptionSet != nil && (strings.HasPrefix(a.UAType, "UInt") || a.UAType == "Byte") {
+		// Integer OptionSets take bit names as well; the value keeps its type
+		mask, err := a.OptionSet.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		target, dataType = strconv.FormatUint(mask, 10), a.UAType
+	}
+	if _, err := convertStringToType(target, dataType); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", a.DataType, err)
+	}
+	if ramp > 0 {
+		if a.Enum != nil || a.OptionSet != nil || !isNumericType(dataType) {
+			return nil, fmt.Errorf("cannot ramp a %s value", a.DataType)
+		}
+		if steps <= 0 {
+			return nil, errors.New("ramp needs at least one step")
+		}
+	} else {
+		steps = 1
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.schedMu.Lock()
+	c.schedNextID++
+	sw := &ScheduledWrite{
+		ID:       c.schedNextID,
+		NodeID:   nodeID,
+		DataType: dataType,
+		Target:   target,
+		At:       at,
+		Ramp:     ramp,
+		Steps:    steps,
+		Status:   ScheduledPending,
+		cancel:   cancel,
+	}
+	if c.schedWrites == nil {
+		c.schedWrites = make(map[int]*ScheduledWrite)
+	}
+	c.schedWrites[sw.ID] = sw
+	c.schedMu.Unlock()
+
+	if ramp > 0 {
+		c.Log(fmt.Sprintf("[cyan]Scheduled ramp of %s to %s over %s in %d steps at %s[-]", nodeID, target, ramp, steps, at.Format("15:04:05")))
+	} else {
+		c.Log(fmt.Sprintf("[cyan]Scheduled write of %s = %s at %s[-]", nodeID, target, at.Format("15:04:05")))
+	}
+	c.notifyScheduledWrites()
+	go c.runScheduledWrite(ctx, sw)
+	return sw, nil
+}
+
+// checkWritable applies the same write protection and AccessLevel gates as WriteValue.
+func (c *Controller) checkWritable(a *NodeAttributes) error {
+	if err := c.CheckWriteAllowed(a.NodeID); err != nil {
+		return err
+	}
+	if !a.AccessLevelKnown {
+		if c.strictAccessLevel() {
+			return fmt.Errorf("AccessLevel of %s is unknown and strict mode is on", a.NodeID)
+		}
+		return nil
+	}
+	if !strings.Contains(strings.ToLower(a.AccessLevel), "write") {
+		return fmt.Errorf("node %s is not writable (AccessLevel=%s)", a.NodeID, a.AccessLevel)
+	}
+	return nil
+}
+
+func isNumericType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "sbyte", "byte", "int16", "uint16", "int32", "uint32", "int64", "uint64", "float", "double":
+		return true
+	}
+	return false
+}
+
+// CancelScheduledWrite stops a pending or running scheduled write. Steps already written stay.
+func (c *Controller) CancelScheduledWrite(id int) bool {
+	c.schedMu.Lock()
+	sw, ok := c.schedWrites[id]
+	active := ok && (sw.Status == ScheduledPending || sw.Status == ScheduledRunning)
+	if active {
+		sw.Status = ScheduledCancelled
+		sw.cancel()
+	}
+	c.schedMu.Unlock()
+	if active {
+		c.Log(fmt.Sprintf("[yellow]Scheduled write #%d of %s cancelled[-]", id, sw.NodeID))
+		c.notifyScheduledWrites()
+	}
+	return active
+}
+
+// ScheduledWrites returns copies of all scheduled writes, oldest first.
+func (c *Controller) ScheduledWrites() []ScheduledWrite {
+	c.schedMu.Lock()
+	defer c.schedMu.Unlock()
+	out := make([]ScheduledWrite, 0, len(c.schedWrites))
+	for _, sw := range c.schedWrites {
+		cp := *sw
+		cp.cancel = nil
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// ClearFinishedScheduledWrites drops done, failed and cancelled entries.
+func (c *Controller) ClearFinishedScheduledWrites() {
+	c.schedMu.Lock()
+	for id, sw := range c.schedWrites {
+		if sw.Status != ScheduledPending && sw.Status != ScheduledRunning {
+			delete(c.schedWrites, id)
+		}
+	}
+	c.schedMu.Unlock()
+	c.notifyScheduledWrites()
+}
+
+// cancelScheduledWrites cancels everything still pending or running, e.g. on disconnect.
+func (c *Controller) cancelScheduledWrites(reason string) {
+	c.schedMu.Lock()
+	n := 0
+	for _, sw := range c.schedWrites {
+		if sw.Status == ScheduledPending || sw.Status == ScheduledRunning {
+			sw.Status, sw.Message = ScheduledCancelled, reason
+			sw.cancel()
+			n++
+		}
+	}
+	c.schedMu.Unlock()
+	if n > 0 {
+		c.Log(fmt.Sprintf("[yellow]%d scheduled writes cancelled: %s[-]", n, reason))
+		c.notifyScheduledWrites()
+	}
+}
+
+func (c *Controller) notifyScheduledWrites() {
+	if c.OnScheduledWritesUpdate != nil {
+		c.OnScheduledWritesUpdate()
+	}
+}
+
+func (c *Controller) setScheduledState(sw *ScheduledWrite, update func()) {
+	c.schedMu.Lock()
+	if sw.Status == ScheduledCancelled {
+		c.schedMu.Unlock()
+		return
+	}
+	update()
+	c.schedMu.Unlock()
+	c.notifyScheduledWrites()
+}
+
+func (c *Controller) runScheduledWrite(ctx context.Context, sw *ScheduledWrite) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(time.Until(sw.At)):
+	}
+	c.setScheduledState(sw, func() { sw.Status = ScheduledRunning })
+
+	fail := func(err error) {
+		c.setScheduledState(sw, func() { sw.Status, sw.Message = ScheduledFailed, err.Error() })
+		c.Log(fmt.Sprintf("[red]Scheduled write #%d of %s failed: %v[-]", sw.ID, sw.NodeID, err))
+	}
+
+	values := []string{sw.Target}
+	if sw.Ramp > 0 {
+		from, err := c.readNumericValue(sw.NodeID)
+		if err != nil {
+			fail(err)
+			return
+		}
+		to, _ := strconv.ParseFloat(sw.Target, 64)
+		values = rampValues(from, to, sw.Steps, sw.DataType)
+	}
+
+	// Ramp steps are spread over the ramp duration, the first one after one interval
+	interval := sw.Ramp / time.Duration(len(values))
+	for i, v := range values {
+		if interval > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err := c.writeScalar(ctx, sw.NodeID, sw.DataType, v); err != nil {
+			fail(err)
+			return
+		}
+		c.setScheduledState(sw, func() { sw.Step = i + 1 })
+	}
+	c.setScheduledState(sw, func() { sw.Status = ScheduledDone })
+	c.Log(fmt.Sprintf("[green]Scheduled write #%d: %s = %s[-]", sw.ID, sw.NodeID, sw.Target))
+}
+
+// rampValues returns the values of steps equal steps from "from" to "to", formatted for
+// dataType (integers rounded). The last value is exactly "to".
+func rampValues(from, to float64, steps int, dataType string) []string {
+	isFloat := strings.EqualFold(dataType, "float") || strings.EqualFold(dataType, "double")
+	out := make([]string, steps)
+	for i := 1; i <= steps; i++ {
+		v := from + (to-from)*float64(i)/float64(steps)
+		if isFloat {
+			out[i-1] = strconv.FormatFloat(v, 'g', -1, 64)
+		} else {
+			out[i-1] = strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+		}
+	}
+	return out
+}
+
+func (c *Controller) readNumericValue(nodeID string) (float64, error) {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return 0, errors.New("not connected")
+	}
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Read)
+	defer cancel()
+	vals, err := cli.ReadAttributes(ctx, nodeID, ua.AttributeIDValue)
+	if err != nil {
+		return 0, err
+	}
+	if len(vals) != 1 || vals[0] == nil || vals[0].Status != ua.StatusOK || vals[0].Value == nil {
+		return 0, errors.New("current value is not readable")
+	}
+	return strconv.ParseFloat(fmt.Sprint(vals[0].Value.Value()), 64)
+}
+
+// writeScalar converts valueStr to dataType and writes it, returning the server's status.
+func (c *Controller) writeScalar(ctx context.Context, nodeID, dataType, valueStr string) error {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return errors.New("not connected")
+	}
+	if err := c.CheckWriteAllowed(nodeID); err != nil {
+		return err
+	}
+	v, err := convertStringToType(valueStr, dataType)
+	if err != nil {
+		return err
+	}
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
+	results, err := cli.WriteValues(ctx, []*ua.NodeID{id}, []interface{}{v})
+	if err == nil && len(results) == 1 && results[0] != ua.StatusOK {
+		err = results[0]
+	}
+	if err != nil {
+		c.usageWrites(0, 1)
+		return err
+	}
+	c.usageWrites(1, 0)
+	return nil
+}
@@ -0,0 +1,358 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// Search modes of SearchNodes.
+const (
+	SearchAuto   = "auto"   // path for queries starting with "/", else Query if it worked before, else browse
+	SearchPath   = "path"   // TranslateBrowsePathsToNodeIDs, e.g. "/Objects/2:Plant/Temperature"
+	SearchQuery  = "query"  // the server's Query service, matching BrowseNames
+	SearchBrowse = "browse" // client-side traversal matching DisplayNames
+)
+
+// DefaultSearchLimit is the number of results SearchNodes returns when no limit is given.
+const DefaultSearchLimit = 500
+
+// searchBrowseWorkers is how many branches the traversal browses at a time.
+const searchBrowseWorkers = 8
+
+// SearchRequest describes a node search. Text matches names case-insensitively as a substring,
+// or as a glob pattern when it contains * or ?. In path mode it is a browse path whose elements
+// may carry a namespace index ("2:Pump"); elements without one match in any namespace.
+type SearchRequest struct {
+	Text    string `json:"text"`
+	Mode    string `json:"mode,omitempty"`     // default SearchAuto
+	StartID string `json:"start_id,omitempty"` // browse and relative paths start here; default Objects (i=85)
+	Limit   int    `json:"limit,omitempty"`    // default DefaultSearchLimit
+}
+
+// SearchResult is a node found by SearchNodes.
+type SearchResult struct {
+	NodeID    string `json:"node_id"`
+	Name      string `json:"name"`
+	Path      string `json:"path,omitempty"`
+	NodeClass string `json:"node_class,omitempty"`
+}
+
+// SearchResults are the results of a search and how they were found.
+type SearchResults struct {
+	Results   []SearchResult `json:"results"`
+	Mode      string         `json:"mode"`      // the mode that produced the results
+	Visited   int            `json:"visited"`   // nodes traversed (browse mode)
+	Truncated bool           `json:"truncated"` // more nodes matched than the limit
+	Cached    bool           `json:"cached"`    // served from the search cache
+	// Incomplete reports branches that could not be browsed; such results are not cached
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// SearchNodes finds nodes by name or browse path. Server-side modes answer in one or a few
+// requests; the browse mode walks the address space below StartID, reusing the tree's cache and
+// adding to it, and reports its progress. Automatic mode only uses the Query service once a
+// Query search succeeded in the session, since some servers drop the connection on services
+// they do not implement. Results are cached for the session until the address space cache
+// changes. Cancelling ctx stops the search with the results so far.
+func (c *Controller) SearchNodes(ctx context.Context, req SearchRequest, progress func(visited, found int)) (*SearchResults, error) {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if cli == nil && !offline {
+		return nil, errors.New("not connected")
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return nil, errors.New("empty search text")
+	}
+	if req.StartID == "" {
+		req.StartID = "i=85"
+	}
+	if req.Limit <= 0 {
+		req.Limit = DefaultSearchLimit
+	}
+	mode := req.Mode
+	if mode == "" || mode == SearchAuto {
+		c.searchMu.Lock()
+		switch {
+		case strings.HasPrefix(req.Text, "/"):
+			mode = SearchPath
+		case c.querySupported:
+			mode = SearchQuery
+		default:
+			mode = SearchBrowse
+		}
+		c.searchMu.Unlock()
+	}
+	if offline && mode != SearchBrowse {
+		mode = SearchBrowse // an offline address space can only be traversed
+	}
+
+	key := strings.Join([]string{mode, req.StartID, strconv.Itoa(req.Limit), req.Text}, "\x00")
+	c.searchMu.Lock()
+	if r, ok := c.searchCache[key]; ok {
+		c.searchMu.Unlock()
+		cached := *r
+		cached.Cached = true
+		return &cached, nil
+	}
+	c.searchMu.Unlock()
+
+	var res *SearchResults
+	var err error
+	switch mode {
+	case SearchPath:
+		res, err = c.searchPath(ctx, cli, req)
+	case SearchQuery:
+		res, err = c.searchQuery(ctx, cli, req)
+		c.searchMu.Lock()
+		c.querySupported = err == nil
+		c.searchMu.Unlock()
+	case SearchBrowse:
+		res, err = c.searchBrowse(ctx, req, progress)
+	default:
+		return nil, fmt.Errorf("unknown search mode %q", req.Mode)
+	}
+	if err != nil || res.Incomplete {
+		return res, err
+	}
+	c.searchMu.Lock()
+	if c.searchCache == nil {
+		c.searchCache = make(map[string]*SearchResults)
+	}
+	c.searchCache[key] = res
+	c.searchMu.Unlock()
+	return res, nil
+}
+
+// clearSearchCache drops cached search results, e.g. after the address space changed; session
+// also forgets whether the server supports the Query service.
+func (c *Controller) clearSearchCache(session bool) {
+	c.searchMu.Lock()
+	c.searchCache = nil
+	if session {
+		c.querySupported = false
+	}
+	c.searchMu.Unlock()
+}
+
+// searchMatcher returns the name test of text: a case-insensitive substring, or a glob when text
+// contains * or ?.
+func searchMatcher(text string) func(name string) bool {
+	text = strings.ToLower(text)
+	if strings.ContainsAny(text, "*?") {
+		return func(name string) bool {
+			ok, _ := path.Match(text, strings.ToLower(name))
+			return ok
+		}
+	}
+	return func(name string) bool { return strings.Contains(strings.ToLower(name), text) }
+}
+
+// likePattern converts the search text to the pattern of the Like filter operator.
+func likePattern(text string) string {
+	if strings.ContainsAny(text, "*?") {
+		return strings.NewReplacer("*", "%", "?", "_").Replace(text)
+	}
+	return "%" + text + "%"
+}
+
+func (c *Controller) searchResult(nodeID, name string) SearchResult {
+	r := SearchResult{NodeID: nodeID, Name: name, Path: c.nodePath(nodeID)}
+	if n := c.GetNode(nodeID); n != nil {
+		r.NodeClass = n.NodeClass.String()
+		if r.Name == "" {
+			r.Name = n.Name
+		}
+	}
+	if r.Name == "" {
+		r.Name = nodeID
+	}
+	return r
+}
+
+func (c *Controller) searchQuery(ctx context.Context, cli *opc.Client, req SearchRequest) (*SearchResults, error) {
+	qctx, cancel := c.opContext(ctx, c.timeouts().Browse)
+	defer cancel()
+	nodes, more, err := cli.QueryBrowseName(qctx, likePattern(req.Text), uint32(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	res := &SearchResults{Mode: SearchQuery, Results: []SearchResult{}, Truncated: more}
+	for _, n := range nodes {
+		if len(res.Results) == req.Limit {
+			res.Truncated = true
+			break
+		}
+		res.Results = append(res.Results, c.searchResult(n.NodeID, n.BrowseName))
+	}
+	return res, nil
+}
+
+// searchPath resolves the browse path req.Text one element at a time, so elements without a
+// namespace index can be tried in every namespace in a single request per element. Absolute
+// paths start at the RootFolder, relative ones at req.StartID.
+func (c *Controller) searchPath(ctx context.Context, cli *opc.Client, req SearchRequest) (*SearchResults, error) {
+	start := req.StartID
+	if strings.HasPrefix(req.Text, "/") {
+		start = "i=84"
+	}
+	startID, err := ua.ParseNodeID(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start node %q: %w", start, err)
+	}
+	c.mu.RLock()
+	nsCount := max(len(c.namespaces), 1)
+	c.mu.RUnlock()
+
+	current := []*ua.NodeID{startID}
+	var last string
+	for _, elem := range strings.Split(req.Text, "/") {
+		if elem == "" {
+			continue
+		}
+		names := []*ua.QualifiedName{}
+		if i := strings.IndexByte(elem, ':'); i > 0 {
+			if ns, err := strconv.ParseUint(elem[:i], 10, 16); err == nil {
+				names = append(names, &ua.QualifiedName{NamespaceIndex: uint16(ns), Name: elem[i+1:]})
+			}
+		}
+		if len(names) == 0 {
+			for ns := 0; ns < nsCount; ns++ {
+				names = append(names, &ua.QualifiedName{NamespaceIndex: uint16(ns), Name: elem})
+			}
+		}
+		last = names[0].Name
+		var paths []*ua.BrowsePath
+		for _, from := range current {
+			for _, qn := range names {
+				paths = append(paths, &ua.BrowsePath{
+					StartingNode: from,
+					RelativePath: &ua.RelativePath{Elements: []*ua.RelativePathElement{{
+						ReferenceTypeID: ua.NewNumericNodeID(0, id.HierarchicalReferences),
+						IncludeSubtypes: true,
+						TargetName:      qn,
+					}}},
+				})
+			}
+		}
+		tctx, cancel := c.opContext(ctx, c.timeouts().Browse)
+		results, err := cli.TranslateBrowsePaths(tctx, paths)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		current = nil
+		for _, r := range results {
+			if r == nil || r.StatusCode != ua.StatusOK {
+				continue
+			}
+			for _, t := range r.Targets {
+				if t == nil || t.TargetID == nil || t.TargetID.NodeID == nil || seen[t.TargetID.NodeID.String()] {
+					continue
+				}
+				seen[t.TargetID.NodeID.String()] = true
+				current = append(current, t.TargetID.NodeID)
+			}
+		}
+		if len(current) == 0 {
+			break
+		}
+	}
+	res := &SearchResults{Mode: SearchPath, Results: []SearchResult{}}
+	for _, n := range current {
+		if len(res.Results) == req.Limit {
+			res.Truncated = true
+			break
+		}
+		res.Results = append(res.Results, c.searchResult(n.String(), last))
+	}
+	return res, nil
+}
+
+// searchBrowse walks the address space below req.StartID level by level, browsing branches that
+// are not cached yet searchBrowseWorkers at a time.
+func (c *Controller) searchBrowse(ctx context.Context, req SearchRequest, progress func(visited, found int)) (*SearchResults, error) {
+	match := searchMatcher(req.Text)
+	browse := !c.IsOffline() // an offline address space is complete
+	res := &SearchResults{Mode: SearchBrowse, Results: []SearchResult{}}
+	visited := map[string]bool{req.StartID: true}
+	paths := map[string]string{req.StartID: strings.TrimSuffix(c.nodePath(req.StartID), "/")}
+	level := []string{req.StartID}
+	for len(level) > 0 {
+		var pending []string
+		for _, id := range level {
+			if !browse {
+				break
+			}
+			if n := c.GetNode(id); (n == nil || n.HasChildren) && !c.HasBrowseBeenPerformed(id) {
+				pending = append(pending, id)
+			}
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, searchBrowseWorkers)
+		for _, id := range pending {
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(id string) {
+				defer wg.Done()
+				c.Browse(ctx, id)
+				<-sem
+			}(id)
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		for _, id := range pending {
+			if !c.HasBrowseBeenPerformed(id) {
+				res.Incomplete = true
+			}
+		}
+
+		var next []string
+		for _, parent := range level {
+			for _, child := range c.GetAddressSpaceChildren(parent) {
+				if visited[child] {
+					continue
+				}
+				visited[child] = true
+				n := c.GetNode(child)
+				if n == nil {
+					continue
+				}
+				paths[child] = paths[parent] + "/" + n.Name
+				if match(n.Name) {
+					if len(res.Results) == req.Limit {
+						res.Truncated = true
+						res.Visited = len(visited)
+						return res, nil
+					}
+					res.Results = append(res.Results, SearchResult{NodeID: child, Name: n.Name, Path: paths[child], NodeClass: n.NodeClass.String()})
+				}
+				next = append(next, child)
+			}
+		}
+		res.Visited = len(visited)
+		if progress != nil {
+			progress(res.Visited, len(res.Results))
+		}
+		level = next
+	}
+	return res, nil
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ServerStates are the states RequestServerStateChange accepts, the first being the default.
+var ServerStates = []string{"Shutdown", "Suspended", "Running", "Test"}
+
+// serverActionObjects are the objects whose methods ServerActions offers: the Server object, its
+// VendorServerInfo, where vendors place reset and maintenance methods, and ServerConfiguration.
+var serverActionObjects = []uint32{id.Server, id.Server_VendorServerInfo, id.ServerConfiguration}
+
+// serverActionSkip are methods of the Server object that serve client sessions rather than act
+// on the server.
+var serverActionSkip = map[uint32]bool{
+	id.Server_GetMonitoredItems:      true,
+	id.Server_ResendData:             true,
+	id.Server_SetSubscriptionDurable: true,
+}
+
+// ServerAction is a method of the server that the Server actions menu can call.
+type ServerAction struct {
+	ObjectID    string   `json:"object_id"`
+	MethodID    string   `json:"method_id"`
+	Object      string   `json:"object"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Inputs      []string `json:"inputs,omitempty"` // "Name (DataType)" of each input argument
+	// StateChange marks RequestServerStateChange, which has a form of its own; other methods
+	// can only be called when they take no arguments
+	StateChange bool `json:"state_change,omitempty"`
+}
+
+// Callable reports whether the action can be called from the menu.
+func (a ServerAction) Callable() bool {
+	return a.StateChange || len(a.Inputs) == 0
+}
+
+// ServerStateChangeRequest are the arguments of RequestServerStateChange.
+type ServerStateChangeRequest struct {
+	State               string `json:"state"`
+	SecondsTillShutdown uint32 `json:"seconds_till_shutdown"`
+	// EstimatedReturn is how long the server expects to be unavailable; zero means unknown
+	EstimatedReturn time.Duration `json:"estimated_return"`
+	Reason          string        `json:"reason"`
+	Restart         bool          `json:"restart"`
+}
+
+// ServerActions lists the methods of the Server object, its VendorServerInfo and
+// ServerConfiguration, with their input arguments. Objects the server does not expose are
+// skipped.
+func (c *Controller) ServerActions(ctx context.Context) ([]ServerAction, error) {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil, errors.New("not connected")
+	}
+	rctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+
+	stateChange := ua.NewNumericNodeID(0, id.Server_RequestServerStateChange).String()
+	var actions []ServerAction
+	seen := make(map[string]bool)
+	for _, obj := range serverActionObjects {
+		objID := ua.NewNumericNodeID(0, obj)
+		refs, err := cli.Browse(rctx, objID)
+		if err != nil {
+			if rctx.Err() != nil {
+				return actions, err
+			}
+			continue
+		}
+		for _, ref := range refs {
+			if ref.NodeClass != ua.NodeClassMethod || ref.NodeID == nil {
+				continue
+			}
+			mID := ref.NodeID.NodeID
+			if mID.Namespace() == 0 && serverActionSkip[mID.IntID()] {
+				continue
+			}
+			// A method can be reached through more than one reference
+			key := objID.String() + "|" + mID.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			a := ServerAction{
+				ObjectID: objID.String(),
+				MethodID: mID.String(),
+				Object:   id.Name(obj),
+			}
+			if ref.DisplayName != nil {
+				a.Name = ref.DisplayName.Text
+			}
+			if a.Name == "" && ref.BrowseName != nil {
+				a.Name = ref.BrowseName.Name
+			}
+			a.StateChange = a.MethodID == stateChange
+			args, err := cli.MethodInputArguments(rctx, mID)
+			if err != nil {
+				a.Description = fmt.Sprintf("input arguments unknown: %v", err)
+				a.Inputs = []string{"?"}
+			}
+			for _, arg := range args {
+				a.Inputs = append(a.Inputs, fmt.Sprintf("%s (%s)", arg.Name, builtinTypeName(arg.DataType)))
+			}
+			actions = append(actions, a)
+		}
+	}
+	return actions, nil
+}
+
+// CallServerAction calls a server method that takes no arguments and returns its output
+// arguments formatted. The write allow/deny lists apply to the method's NodeID.
+func (c *Controller) CallServerAction(ctx context.Context, objectID, methodID string) ([]string, error) {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return nil, errors.New("server actions are not available in offline mode")
+	}
+	if cli == nil {
+		return nil, errors.New("not connected")
+	}
+	objID, err := ua.ParseNodeID(strings.TrimSpace(objectID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid object_id %q: %w", objectID, err)
+	}
+	mID, err := ua.ParseNodeID(strings.TrimSpace(methodID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid method_id %q: %w", methodID, err)
+	}
+	if err := c.CheckWriteAllowed(mID.String()); err != nil {
+		return nil, err
+	}
+
+	cctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
+	c.Log(fmt.Sprintf("[yellow]Calling server action %s on %s[-]", mID, objID))
+	out, err := cli.CallMethod(cctx, objID, mID)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Server action %s failed: %v[-]", mID, err))
+		return nil, err
+	}
+	results := make([]string, len(out))
+	for i, v := range out {
+		results[i] = formatValue(v, "")
+	}
+	c.Log(fmt.Sprintf("[green]Server action %s done[-]", mID))
+	return results, nil
+}
+
+// RequestServerStateChange asks the server to change its state, e.g. to Shutdown with Restart
+// to reboot it. The write allow/deny lists apply to the method's NodeID.
+func (c *Controller) RequestServerStateChange(ctx context.Context, req ServerStateChangeRequest) error {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return errors.New("server actions are not available in offline mode")
+	}
+	if cli == nil {
+		return errors.New("not connected")
+	}
+	state := -1
+	for _, s := range ServerStates {
+		if strings.EqualFold(s, req.State) {
+			state, req.State = int(ua.ServerStateFromString(s)), s
+		}
+	}
+	if state < 0 {
+		return fmt.Errorf("unsupported server state %q (supported: %s)", req.State, strings.Join(ServerStates, ", "))
+	}
+	if err := c.CheckWriteAllowed(ua.NewNumericNodeID(0, id.Server_RequestServerStateChange).String()); err != nil {
+		return err
+	}
+	var estimated time.Time
+	if req.EstimatedReturn > 0 {
+		estimated = time.Now().Add(req.EstimatedReturn)
+	}
+
+	cctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
+	c.Log(fmt.Sprintf("[yellow]Requesting server state %s (restart %v, in %d s): %s[-]", req.State, req.Restart, req.SecondsTillShutdown, req.Reason))
+	if err := cli.RequestServerStateChange(cctx, ua.ServerState(state), estimated, req.SecondsTillShutdown, ua.NewLocalizedText(req.Reason), req.Restart); err != nil {
+		c.Log(fmt.Sprintf("[red]RequestServerStateChange failed: %v[-]", err))
+		return err
+	}
+	c.Log("[green]The server accepted the state change request[-]")
+	return nil
+}
@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Simulation waveforms
+const (
+	SimSine   = "sine"
+	SimRamp   = "ramp"   // sawtooth from Min to Max, then back to Min
+	SimSquare = "square" // Min for the first half of each period, Max for the second
+	SimRandom = "random" // uniformly distributed between Min and Max
+)
+
+// SimWaveforms lists the waveforms accepted by StartSimulation.
+var SimWaveforms = []string{SimSine, SimRamp, SimSquare, SimRandom}
+
+// MinSimInterval is the shortest interval between simulated writes.
+const MinSimInterval = 100 * time.Millisecond
+
+// simMaxFailures is how many writes in a row may fail before a simulation stops.
+const simMaxFailures = 3
+
+// Simulation periodically writes generated values to a node, for exercising HMI and alarm logic
+// downstream of the server. Boolean nodes take the lower half of the range as false.
+type Simulation struct {
+	NodeID    string        `json:"node_id"`
+	DataType  string        `json:"data_type"`
+	Waveform  string        `json:"waveform"`
+	Min       float64       `json:"min"`
+	Max       float64       `json:"max"`
+	Period    time.Duration `json:"period"`   // of sine, ramp and square waves
+	Interval  time.Duration `json:"interval"` // between writes
+	Started   time.Time     `json:"started"`
+	Writes    int           `json:"writes"`
+	Failures  int           `json:"failures"`
+	LastValue string        `json:"last_value,omitempty"`
+	LastError string        `json:"last_error,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// StartSimulation starts writing generated values to nodeID every interval, replacing a
+// simulation already running on it. The node must be writable and hold a numeric or Boolean
+// scalar. It stops on StopSimulation, on disconnect or after simMaxFailures failed writes in a row.
+func (c *Controller) StartSimulation(nodeID, waveform string, min, max float64, period, interval time.Duration) (*Simulation, error) {
+	if c.IsOffline() {
+		return nil, errors.New("writes are disabled in offline mode")
+	}
+	waveform = strings.ToLower(strings.TrimSpace(waveform))
+	if !simWaveformKnown(waveform) {
+		return nil, fmt.Errorf("unknown waveform %q", waveform)
+	}
+	if min > max {
+		min, max = max, min
+	}
+	if interval < MinSimInterval {
+		return nil, fmt.Errorf("interval must be at least %v", MinSimInterval)
+	}
+	if waveform != SimRandom && period < interval {
+		return nil, errors.New("period must not be shorter than the interval")
+	}
+	a, err := c.ReadNodeAttributes(context.Background(), nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkWritable(a); err != nil {
+		return nil, err
+	}
+	if a.ValueRank >= 0 || strings.HasSuffix(a.UAType, "[]") {
+		return nil, errors.New("simulation supports scalar values only")
+	}
+	dataType := a.DataType
+	if !isNumericType(dataType) && !strings.EqualFold(dataType, "Boolean") {
+		// Abstract or missing DataTypes (Number, BaseDataType) take the type of the current value
+		dataType = a.UAType
+	}
+	if !isNumericType(dataType) && !strings.EqualFold(dataType, "Boolean") {
+		return nil, fmt.Errorf("cannot simulate a %s value", dataType)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sim := &Simulation{
+		NodeID:   nodeID,
+		DataType: dataType,
+		Waveform: waveform,
+		Min:      min,
+		Max:      max,
+		Period:   period,
+		Interval: interval,
+		Started:  time.Now(),
+		cancel:   cancel,
+	}
+	c.simMu.Lock()
+	if old, ok := c.simulations[nodeID]; ok {
+		old.cancel()
+	}
+	if c.simulations == nil {
+		c.simulations = make(map[string]*Simulation)
+	}
+	c.simulations[nodeID] = sim
+	c.simMu.Unlock()
+
+	c.Log(fmt.Sprintf("[cyan]Simulating %s: %s %g..%g, period %v, every %v[-]", nodeID, waveform, min, max, period, interval))
+	c.notifySimulations()
+	go c.runSimulation(ctx, sim)
+	return sim, nil
+}
+
+func simWaveformKnown(waveform string) bool {
+	for _, w := range SimWaveforms {
+		if w == waveform {
+			return true
+		}
+	}
+	return false
+}
+
+// StopSimulation stops the simulation of nodeID. The node keeps the last value written.
+func (c *Controller) StopSimulation(nodeID string) bool {
+	c.simMu.Lock()
+	sim, ok := c.simulations[nodeID]
+	if ok {
+		sim.cancel()
+		delete(c.simulations, nodeID)
+	}
+	c.simMu.Unlock()
+	if ok {
+		c.Log(fmt.Sprintf("[yellow]Simulation of %s stopped after %d writes[-]", nodeID, sim.Writes))
+		c.notifySimulations()
+	}
+	return ok
+}
+
+// StopAllSimulations stops every running simulation, e.g. on disconnect.
+func (c *Controller) StopAllSimulations(reason string) {
+	c.simMu.Lock()
+	n := len(c.simulations)
+	for _, sim := range c.simulations {
+		sim.cancel()
+	}
+	c.simulations = nil
+	c.simMu.Unlock()
+	if n > 0 {
+		c.Log(fmt.Sprintf("[yellow]%d simulations stopped: %s[-]", n, reason))
+		c.notifySimulations()
+	}
+}
+
+// Simulations returns copies of the running simulations, sorted by NodeID.
+func (c *Controller) Simulations() []Simulation {
+	c.simMu.Lock()
+	defer c.simMu.Unlock()
+	out := make([]Simulation, 0, len(c.simulations))
+	for _, sim := range c.simulations {
+		cp := *sim
+		cp.cancel = nil
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out
+}
+
+// IsSimulated reports whether a simulation is writing to nodeID.
+func (c *Controller) IsSimulated(nodeID string) bool {
+	c.simMu.Lock()
+	defer c.simMu.Unlock()
+	_, ok := c.simulations[nodeID]
+	return ok
+}
+
+func (c *Controller) notifySimulations() {
+	if c.OnSimulationsUpdate != nil {
+		c.OnSimulationsUpdate()
+	}
+}
+
+func (c *Controller) runSimulation(ctx context.Context, sim *Simulation) {
+	ticker := time.NewTicker(sim.Interval)
+	defer ticker.Stop()
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	failures := 0
+	for {
+		v := simValue(sim.Waveform, sim.Min, sim.Max, sim.Period, time.Since(sim.Started), rnd)
+		s := formatSimValue(v, sim.Min, sim.Max, sim.DataType)
+		err := c.writeScalar(ctx, sim.NodeID, sim.DataType, s)
+		if ctx.Err() != nil {
+			return
+		}
+		c.simMu.Lock()
+		if err != nil {
+			failures++
+			sim.Failures++
+			sim.LastError = err.Error()
+		} else {
+			failures = 0
+			sim.Writes++
+			sim.LastValue, sim.LastError = s, ""
+		}
+		c.simMu.Unlock()
+		c.notifySimulations()
+		if failures >= simMaxFailures {
+			c.Log(fmt.Sprintf("[red]Simulation of %s stopped after %d failed writes: %v[-]", sim.NodeID, failures, err))
+			c.simMu.Lock()
+			if c.simulations[sim.NodeID] == sim {
+				delete(c.simulations, sim.NodeID)
+			}
+			c.simMu.Unlock()
+			c.notifySimulations()
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// simValue returns the value of the waveform at elapsed time t.
+func simValue(waveform string, min, max float64, period, t time.Duration, rnd *rand.Rand) float64 {
+	phase := 0.0
+	if period > 0 {
+		phase = math.Mod(float64(t), float64(period)) / float64(period)
+	}
+	switch waveform {
+	case SimSine:
+		return min + (max-min)*(1+math.Sin(2*math.Pi*phase))/2
+	case SimRamp:
+		return min + (max-min)*phase
+	case SimSquare:
+		if phase < 0.5 {
+			return min
+		}
+		return max
+	}
+	return min + (max-min)*rnd.Float64()
+}
+
+// formatSimValue formats v for dataType: integers are rounded, Booleans are true in the upper
+// half of the range.
+func formatSimValue(v, min, max float64, dataType string) string {
+	switch strings.ToLower(dataType) {
+	case "boolean":
+		return strconv.FormatBool(v > (min+max)/2 || (min == max && v != 0))
+	case "float", "double":
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TagPreset is an export format that SCADA packages can import as tags reading the exported
+// nodes through their OPC UA client connection.
+type TagPreset struct {
+	ID          string // as used in ?format= and the export dialog
+	Label       string
+	Extension   string
+	ContentType string
+}
+
+// TagPresets are the supported SCADA tag import formats.
+var TagPresets = []TagPreset{
+	{ID: "ignition-json", Label: "Ignition tags (JSON)", Extension: ".json", ContentType: "application/json"},
+	{ID: "ignition-csv", Label: "Ignition tags (CSV)", Extension: ".csv", ContentType: "text/csv; charset=utf-8"},
+	{ID: "kepware-csv", Label: "KEPServerEX tags (CSV)", Extension: ".csv", ContentType: "text/csv; charset=utf-8"},
+}
+
+// LookupTagPreset returns the preset with the given ID (case-insensitive).
+func LookupTagPreset(id string) (TagPreset, bool) {
+	for _, p := range TagPresets {
+		if strings.EqualFold(p.ID, id) {
+			return p, true
+		}
+	}
+	return TagPreset{}, false
+}
+
+// TagPresetOptions are the connection settings written into preset tags.
+type TagPresetOptions struct {
+	// OPCServer is the name of the OPC UA connection in the SCADA (Ignition's OPC Server);
+	// empty uses "OPC UA Server".
+	OPCServer string
+	// TagGroup is Ignition's tag group (scan class); empty uses "Default".
+	TagGroup string
+	// ScanRateMs is KEPServerEX's scan rate; zero uses 1000 ms.
+	ScanRateMs int
+}
+
+// WriteTagPreset writes tags in the preset format to w. Folders are taken from the tags' browse
+// paths without the leading Objects folder; names are reduced to the characters the target
+// accepts and made unique per folder.
+func WriteTagPreset(w io.Writer, presetID string, tags []*ExportTag, opts TagPresetOptions) error {
+	if opts.OPCServer == "" {
+		opts.OPCServer = "OPC UA Server"
+	}
+	if opts.TagGroup == "" {
+		opts.TagGroup = "Default"
+	}
+	if opts.ScanRateMs <= 0 {
+		opts.ScanRateMs = 1000
+	}
+	switch strings.ToLower(presetID) {
+	case "ignition-json":
+		return writeIgnitionJSON(w, tags, opts)
+	case "ignition-csv":
+		return writeIgnitionCSV(w, tags, opts)
+	case "kepware-csv":
+		return writeKepwareCSV(w, tags, opts)
+	}
+	return fmt.Errorf("unknown tag preset %q", presetID)
+}
+
+// presetTag is an exported tag placed in its folder with a name valid for the target.
+type presetTag struct {
+	*ExportTag
+	folders []string
+	name    string
+}
+
+// placeTags sanitizes folder and tag names with clean and makes tag names unique per folder.
+func placeTags(tags []*ExportTag, clean func(string) string) []presetTag {
+	used := make(map[string]bool)
+	out := make([]presetTag, 0, len(tags))
+	for _, t := range tags {
+		segments := strings.Split(strings.Trim(t.Path, "/"), "/")
+		if len(segments) > 0 && segments[0] == "Objects" {
+			segments = segments[1:]
+		}
+		if len(segments) > 0 {
+			segments = segments[:len(segments)-1] // the tag itself
+		}
+		folders := make([]string, 0, len(segments))
+		for _, s := range segments {
+			if s = clean(s); s != "" {
+				folders = append(folders, s)
+			}
+		}
+		base := clean(t.Name)
+		if base == "" {
+			base = clean(t.NodeID)
+		}
+		name := base
+		prefix := strings.Join(folders, "/") + "/"
+		for i := 2; used[prefix+strings.ToLower(name)]; i++ {
+			name = base + "_" + strconv.Itoa(i)
+		}
+		used[prefix+strings.ToLower(name)] = true
+		out = append(out, presetTag{ExportTag: t, folders: folders, name: name})
+	}
+	return out
+}
+
+// ignitionName keeps letters, digits, spaces, '_' and '-'.
+func ignitionName(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, s))
+}
+
+// kepwareName keeps ASCII letters, digits and '_' and must not start with '_'.
+func kepwareName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, s)
+	return strings.TrimLeft(s, "_")
+}
+
+// ignitionDataType maps an OPC UA built-in type to Ignition's tag data type.
+func ignitionDataType(uaType string) string {
+	switch uaType {
+	case "Boolean":
+		return "Boolean"
+	case "SByte":
+		return "Int1"
+	case "Byte", "Int16":
+		return "Int2"
+	case "UInt16", "Int32":
+		return "Int4"
+	case "UInt32", "Int64", "UInt64":
+		return "Int8"
+	case "Float":
+		return "Float4"
+	case "Double":
+		return "Float8"
+	case "DateTime":
+		return "DateTime"
+	case "ByteString":
+		return "ByteArray"
+	}
+	return "String"
+}
+
+// kepwareDataType maps an OPC UA built-in type to KEPServerEX's tag data type.
+func kepwareDataType(uaType string) string {
+	switch uaType {
+	case "Boolean":
+		return "Boolean"
+	case "SByte":
+		return "Char"
+	case "Byte":
+		return "Byte"
+	case "Int16":
+		return "Short"
+	case "UInt16":
+		return "Word"
+	case "Int32":
+		return "Long"
+	case "UInt32":
+		return "DWord"
+	case "Int64":
+		return "LLong"
+	case "UInt64":
+		return "QWord"
+	case "Float":
+		return "Float"
+	case "Double":
+		return "Double"
+	case "String", "LocalizedText":
+		return "String"
+	case "DateTime":
+		return "Date"
+	}
+	return "Default"
+}
+
+// ignitionTag is a node of Ignition's tag JSON (folders and OPC tags).
+type ignitionTag struct {
+	Name        string         `json:"name"`
+	TagType     string         `json:"tagType"`
+	ValueSource string         `json:"valueSource,omitempty"`
+	DataType    string         `json:"dataType,omitempty"`
+	OPCServer   string         `json:"opcServer,omitempty"`
+	OPCItemPath string         `json:"opcItemPath,omitempty"`
+	TagGroup    string         `json:"tagGroup,omitempty"`
+	Tooltip     string         `json:"tooltip,omitempty"`
+	Tags        []*ignitionTag `json:"tags,omitempty"`
+}
+
+func writeIgnitionJSON(w io.Writer, tags []*ExportTag, opts TagPresetOptions) error {
+	root := &ignitionTag{Name: "", TagType: "Provider"}
+	folders := map[string]*ignitionTag{"": root}
+	folder := func(path []string) *ignitionTag {
+		parent, key := root, ""
+		for _, name := range path {
+			key += "/" + name
+			f, ok := folders[key]
+			if !ok {
+				f = &ignitionTag{Name: name, TagType: "Folder"}
+				folders[key] = f
+				parent.Tags = append(parent.Tags, f)
+			}
+			parent = f
+		}
+		return parent
+	}
+	for _, t := range placeTags(tags, ignitionName) {
+		f := folder(t.folders)
+		f.Tags = append(f.Tags, &ignitionTag{
+			Name:        t.name,
+			TagType:     "AtomicTag",
+			ValueSource: "opc",
+			DataType:    ignitionDataType(t.DataType),
+			OPCServer:   opts.OPCServer,
+			OPCItemPath: t.NodeID,
+			TagGroup:    opts.TagGroup,
+			Tooltip:     t.Description,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+func writeIgnitionCSV(w io.Writer, tags []*ExportTag, opts TagPresetOptions) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Path", "Name", "Tag Type", "Value Source", "Data Type", "OPC Server", "OPC Item Path", "Tag Group", "Tooltip"})
+	for _, t := range placeTags(tags, ignitionName) {
+		_ = cw.Write([]string{strings.Join(t.folders, "/"), t.name, "AtomicTag", "opc", ignitionDataType(t.DataType),
+			opts.OPCServer, t.NodeID, opts.TagGroup, t.Description})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeKepwareCSV writes a KEPServerEX tag CSV for a device of the OPC UA Client driver, whose
+// tag addresses are NodeIDs. Folders become tag groups through dotted tag names.
+func writeKepwareCSV(w io.Writer, tags []*ExportTag, opts TagPresetOptions) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Tag Name", "Address", "Data Type", "Respect Data Type", "Client Access", "Scan Rate",
+		"Scaling", "Raw Low", "Raw High", "Scaled Low", "Scaled High", "Scaled Data Type",
+		"Clamp Low", "Clamp High", "Eng Units", "Description", "Negate Value"})
+	rate := strconv.Itoa(opts.ScanRateMs)
+	for _, t := range placeTags(tags, kepwareName) {
+		name := strings.Join(append(append([]string{}, t.folders...), t.name), ".")
+		_ = cw.Write([]string{name, t.NodeID, kepwareDataType(t.DataType), "1", "RO", rate,
+			"", "", "", "", "", "", "", "", "", t.Description, ""})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+const (
+	isoTimestampLayout     = "2006-01-02T15:04:05.000Z07:00"
+	displayTimestampLayout = "15:04:05.000"
+)
+
+// timestampLocation resolves cfg.TimestampTimezone ("local", "utc" or an IANA name). An IANA
+// zone is loaded only when the setting differs from the last call, Local if it cannot be loaded.
+func (c *Controller) timestampLocation(cfg *opc.Config) *time.Location {
+	if cfg == nil {
+		return time.Local
+	}
+	tz := strings.TrimSpace(cfg.TimestampTimezone)
+	switch strings.ToLower(tz) {
+	case "", "local":
+		return time.Local
+	case "utc":
+		return time.UTC
+	}
+	c.tzMu.Lock()
+	defer c.tzMu.Unlock()
+	if c.tzLoc == nil || c.tzName != tz {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+		c.tzName, c.tzLoc = tz, loc
+	}
+	return c.tzLoc
+}
+
+// timestampSource returns cfg.TimestampSource normalized to "source", "server" or "client".
+func timestampSource(cfg *opc.Config) string {
+	if cfg != nil {
+		if s := strings.ToLower(strings.TrimSpace(cfg.TimestampSource)); s == "server" || s == "client" {
+			return s
+		}
+	}
+	return "source"
+}
+
+// formatISOTimestamp renders t as ISO-8601 with milliseconds in the configured timezone.
+// Zero times yield an empty string.
+func (c *Controller) formatISOTimestamp(cfg *opc.Config, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(c.timestampLocation(cfg)).Format(isoTimestampLayout)
+}
+
+// formatDisplayTimestamp renders t in the short form used by the watch list.
+func (c *Controller) formatDisplayTimestamp(cfg *opc.Config, t time.Time) string {
+	return t.In(c.timestampLocation(cfg)).Format(displayTimestampLayout)
+}
+
+// applyTimestamps fills the timestamp fields of item from dv according to cfg.
+func (c *Controller) applyTimestamps(cfg *opc.Config, item *WatchItem, dv *ua.DataValue) {
+	item.SourceTimestamp = c.formatISOTimestamp(cfg, dv.SourceTimestamp)
+	item.ServerTimestamp = c.formatISOTimestamp(cfg, dv.ServerTimestamp)
+	item.Timestamp = c.displayTimestamp(cfg, dv.SourceTimestamp, dv.ServerTimestamp)
+}
+
+// displayTimestamp renders the timestamp chosen by cfg.TimestampSource in the watch list form.
+// It falls back to the server and then the client clock when the chosen one is missing.
+func (c *Controller) displayTimestamp(cfg *opc.Config, source, server time.Time) string {
+	display := time.Now()
+	switch timestampSource(cfg) {
+	case "source":
+		if !source.IsZero() {
+			display = source
+		} else if !server.IsZero() {
+			display = server
+		}
+	case "server":
+		if !server.IsZero() {
+			display = server
+		}
+	}
+	return c.formatDisplayTimestamp(cfg, display)
+}
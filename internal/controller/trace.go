@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opcuababy/internal/opc"
+)
+
+// ProtocolTracePath returns the trace file configured in cfg, or the default in the temp directory.
+func ProtocolTracePath(cfg *opc.Config) string {
+	if cfg != nil {
+		if p := strings.TrimSpace(cfg.ProtocolTraceFile); p != "" {
+			return p
+		}
+	}
+	return filepath.Join(os.TempDir(), "opcuababy-trace.log")
+}
+
+// ApplyProtocolTrace starts, switches or stops the protocol trace according to cfg.ProtocolTrace
+// and cfg.ProtocolTraceFile. It is safe to call repeatedly.
+func (c *Controller) ApplyProtocolTrace(cfg *opc.Config) error {
+	current := opc.ActiveTracer()
+	if cfg == nil || !cfg.ProtocolTrace {
+		if current != nil {
+			opc.SetTracer(nil)
+			_ = current.Close()
+			c.Log(fmt.Sprintf("[yellow]Protocol trace stopped (%s)[-]", current.Path()))
+		}
+		return nil
+	}
+
+	path := ProtocolTracePath(cfg)
+	if current != nil && current.Path() == path {
+		return nil
+	}
+	t, err := opc.OpenTracer(path)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Failed to open protocol trace file %s: %v[-]", path, err))
+		return err
+	}
+	if old := opc.SetTracer(t); old != nil {
+		_ = old.Close()
+	}
+	c.Log(fmt.Sprintf("[green]Protocol trace enabled: %s[-]", path))
+	return nil
+}
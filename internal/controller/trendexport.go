@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"opcuababy/internal/exporter"
+)
+
+// ExportRecordingExcel converts a recording (JSON Lines of watch items, as written by captures
+// or streamed from /ws/subscribe) to an Excel workbook with one sheet per node and, with chart,
+// a chart sheet. Samples without a timestamp are skipped.
+func (c *Controller) ExportRecordingExcel(recordingPath, filePath string, chart bool) error {
+	records, err := loadRecording(recordingPath)
+	if err != nil {
+		return fmt.Errorf("load recording: %w", err)
+	}
+	var series []*exporter.TrendSeries
+	byNode := make(map[string]*exporter.TrendSeries)
+	for _, r := range records {
+		at, ok := recordedTime(r)
+		if !ok {
+			continue
+		}
+		s := byNode[r.NodeID]
+		if s == nil {
+			s = &exporter.TrendSeries{NodeID: r.NodeID, Name: r.Name, DataType: r.DataType}
+			byNode[r.NodeID] = s
+			series = append(series, s)
+		}
+		status := r.SymbolicName
+		if status == "" {
+			status = r.Severity
+		}
+		s.Samples = append(s.Samples, exporter.TrendSample{Time: at, Value: r.Value, Status: status})
+	}
+	for _, s := range series {
+		sort.SliceStable(s.Samples, func(i, j int) bool { return s.Samples[i].Time.Before(s.Samples[j].Time) })
+	}
+	return c.exportTrends(series, filePath, chart)
+}
+
+// ExportHistoryExcel writes history values as read in the history dialog to an Excel workbook.
+func (c *Controller) ExportHistoryExcel(nodeID, name string, values []*HistoryValue, filePath string, chart bool) error {
+	s := &exporter.TrendSeries{NodeID: nodeID, Name: name}
+	for _, hv := range values {
+		if hv == nil || hv.raw == nil {
+			continue
+		}
+		at := hv.raw.SourceTimestamp
+		if at.IsZero() {
+			at = hv.raw.ServerTimestamp
+		}
+		if at.IsZero() {
+			continue
+		}
+		s.Samples = append(s.Samples, exporter.TrendSample{Time: at, Value: hv.Value, Status: hv.Status})
+	}
+	return c.exportTrends([]*exporter.TrendSeries{s}, filePath, chart)
+}
+
+func (c *Controller) exportTrends(series []*exporter.TrendSeries, filePath string, chart bool) error {
+	if err := exporter.ExportTrendsToExcel(series, filePath, chart); err != nil {
+		c.Log(fmt.Sprintf("[red]Excel export of recorded values failed: %v[-]", err))
+		return err
+	}
+	c.usageExported()
+	samples := 0
+	for _, s := range series {
+		samples += len(s.Samples)
+	}
+	c.Log(fmt.Sprintf("[green]Exported %d values of %d nodes to %s[-]", samples, len(series), filePath))
+	return nil
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// TrustListEntry is one certificate or CRL of the server's trust list.
+type TrustListEntry struct {
+	List       string    `json:"list"` // "trusted" or "issuer"
+	Kind       string    `json:"kind"` // "certificate" or "crl"
+	Subject    string    `json:"subject,omitempty"`
+	Issuer     string    `json:"issuer,omitempty"`
+	NotAfter   time.Time `json:"not_after,omitempty"` // of certificates; NextUpdate of CRLs
+	Thumbprint string    `json:"thumbprint"`
+}
+
+// ServerTrustList is the content of the server's default application trust list.
+type ServerTrustList struct {
+	Entries []TrustListEntry `json:"entries"`
+	// ClientTrusted reports whether the client certificate is among the trusted certificates
+	ClientTrusted bool `json:"client_trusted"`
+}
+
+// ReadServerTrustList reads the TrustList the server exposes under ServerConfiguration. certFile,
+// when set, is the client certificate looked up in it. Reading usually needs an encrypted
+// session and a user with the SecurityAdmin role.
+func (c *Controller) ReadServerTrustList(ctx context.Context, certFile string) (*ServerTrustList, error) {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return nil, errors.New("not connected")
+	}
+	rctx, cancel := c.opContext(ctx, c.timeouts().Read)
+	defer cancel()
+	tl, err := cli.ReadTrustList(rctx)
+	if err != nil {
+		c.Log(fmt.Sprintf("[red]Reading the server trust list failed: %v[-]", err))
+		return nil, err
+	}
+	out := &ServerTrustList{Entries: []TrustListEntry{}}
+	for _, der := range tl.TrustedCertificates {
+		out.Entries = append(out.Entries, certificateEntry("trusted", der))
+	}
+	for _, der := range tl.TrustedCrls {
+		out.Entries = append(out.Entries, crlEntry("trusted", der))
+	}
+	for _, der := range tl.IssuerCertificates {
+		out.Entries = append(out.Entries, certificateEntry("issuer", der))
+	}
+	for _, der := range tl.IssuerCrls {
+		out.Entries = append(out.Entries, crlEntry("issuer", der))
+	}
+	if certFile != "" {
+		if thumbprint, err := opc.CertificateThumbprint(certFile); err == nil {
+			for _, e := range out.Entries {
+				if e.List == "trusted" && e.Kind == "certificate" && e.Thumbprint == thumbprint {
+					out.ClientTrusted = true
+				}
+			}
+		}
+	}
+	c.Log(fmt.Sprintf("[green]Read the server trust list: %d entries[-]", len(out.Entries)))
+	return out, nil
+}
+
+func certificateEntry(list string, der []byte) TrustListEntry {
+	e := TrustListEntry{List: list, Kind: "certificate", Thumbprint: opc.Thumbprint(der)}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		e.Subject = cert.Subject.String()
+		e.Issuer = cert.Issuer.String()
+		e.NotAfter = cert.NotAfter
+	}
+	return e
+}
+
+func crlEntry(list string, der []byte) TrustListEntry {
+	e := TrustListEntry{List: list, Kind: "crl", Thumbprint: opc.Thumbprint(der)}
+	if crl, err := x509.ParseRevocationList(der); err == nil {
+		e.Issuer = crl.Issuer.String()
+		e.NotAfter = crl.NextUpdate
+	}
+	return e
+}
+
+// PushClientCertificate adds the client certificate in certFile to the server's trusted
+// certificates with AddCertificate and applies the change with ApplyChanges, so later sessions
+// are accepted without an administrator trusting the certificate on the server. It needs a
+// user with the SecurityAdmin role, usually on an encrypted session.
+func (c *Controller) PushClientCertificate(ctx context.Context, certFile string) error {
+	c.mu.RLock()
+	cli := c.client
+	offline := c.offline != nil
+	c.mu.RUnlock()
+	if offline {
+		return errors.New("trust list changes are not available in offline mode")
+	}
+	if cli == nil {
+		return errors.New("not connected")
+	}
+	if certFile == "" {
+		return errors.New("no client certificate configured")
+	}
+	der, err := opc.ReadCertificateDER(certFile)
+	if err != nil {
+		return err
+	}
+	if _, err := x509.ParseCertificate(der); err != nil {
+		return fmt.Errorf("invalid client certificate %s: %w", certFile, err)
+	}
+
+	wctx, cancel := c.opContext(ctx, c.timeouts().Write)
+	defer cancel()
+	if err := cli.AddTrustedCertificate(wctx, der, true); err != nil {
+		c.Log(fmt.Sprintf("[red]Adding the client certificate to the server trust list failed: %v[-]", err))
+		return err
+	}
+	if err := cli.ApplyServerConfigChanges(wctx); err != nil {
+		// Servers that take trust list changes immediately need no ApplyChanges
+		c.Log(fmt.Sprintf("[yellow]ApplyChanges was not accepted (%v); the server may have applied the trust list change already[-]", err))
+	}
+	c.Log(fmt.Sprintf("[green]Added the client certificate %s to the server trust list[-]", opc.Thumbprint(der)))
+	return nil
+}
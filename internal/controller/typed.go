@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// uaTypeName returns the built-in type name of a variant (e.g. "Double", "Int32[]").
+func uaTypeName(v *ua.Variant) string {
+	if v == nil {
+		return ""
+	}
+	name := strings.TrimPrefix(v.Type().String(), "TypeID")
+	if v.Has(ua.VariantArrayValues) {
+		name += "[]"
+	}
+	return name
+}
+
+// typedValue converts a variant into a value that marshals to its natural JSON type:
+// numbers, booleans, strings, arrays, and objects for structured types. Non-finite floats
+// are returned as strings ("NaN", "+Inf", "-Inf") because JSON cannot represent them.
+func typedValue(v *ua.Variant) interface{} {
+	if v == nil {
+		return nil
+	}
+	return toJSONValue(v.Value())
+}
+
+func toJSONValue(val interface{}) interface{} {
+	switch x := val.(type) {
+	case nil:
+		return nil
+	case bool, string, int8, int16, int32, int64, uint8, uint16, uint32, uint64, int, uint:
+		return x
+	case float32:
+		return jsonFloat(float64(x))
+	case float64:
+		return jsonFloat(x)
+	case []byte:
+		// encoding/json emits []byte as base64, matching the OPC UA JSON encoding of ByteString
+		return x
+	case time.Time:
+		if x.IsZero() {
+			return nil
+		}
+		return x.UTC().Format(time.RFC3339Nano)
+	case ua.StatusCode:
+		return uint32(x)
+	case *ua.NodeID:
+		if x == nil {
+			return nil
+		}
+		return x.String()
+	case *ua.ExpandedNodeID:
+		if x == nil || x.NodeID == nil {
+			return nil
+		}
+		return x.NodeID.String()
+	case *ua.GUID:
+		if x == nil {
+			return nil
+		}
+		return x.String()
+	case ua.LocalizedText:
+		return map[string]interface{}{"locale": x.Locale, "text": x.Text}
+	case *ua.LocalizedText:
+		if x == nil {
+			return nil
+		}
+		return map[string]interface{}{"locale": x.Locale, "text": x.Text}
+	case *ua.QualifiedName:
+		if x == nil {
+			return nil
+		}
+		return map[string]interface{}{"namespace_index": x.NamespaceIndex, "name": x.Name}
+	case *ua.ExtensionObject:
+		if x == nil {
+			return nil
+		}
+		return toJSONValue(x.Value)
+	case *ua.Variant:
+		return typedValue(x)
+	case *ua.DataValue:
+		if x == nil {
+			return nil
+		}
+		return typedValue(x.Value)
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = toJSONValue(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return toJSONValue(rv.Elem().Interface())
+	case reflect.Struct:
+		// Decoded structures: expose exported fields as an object
+		out := make(map[string]interface{}, rv.NumField())
+		t := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				out[f.Name] = toJSONValue(rv.Field(i).Interface())
+			}
+		}
+		return out
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// numericValue returns a scalar numeric or Boolean variant as a number; booleans are 0 and 1.
+func numericValue(v *ua.Variant) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	switch x := v.Value().(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case int8:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+func jsonFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return f
+}
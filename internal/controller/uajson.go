@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// The functions in this file implement the reversible form of the OPC UA JSON encoding
+// (Part 6, 5.4). Unlike typedValue, which is meant for human/JSON consumers, the output keeps
+// built-in type ids, 64-bit integers, NodeIds and structures so it can be decoded losslessly
+// by other UA-aware tools.
+
+const uaJSONTimeLayout = "2006-01-02T15:04:05.0000000Z"
+
+// uaJSONExport is the document written by ExportWatchUAJSON and ExportHistoryUAJSON.
+type uaJSONExport struct {
+	Encoding   string             `json:"Encoding"`
+	ExportedAt string             `json:"ExportedAt"`
+	Endpoint   string             `json:"Endpoint,omitempty"`
+	Items      []uaJSONExportItem `json:"Items"`
+}
+
+type uaJSONExportItem struct {
+	NodeId    interface{}   `json:"NodeId"`
+	Name      string        `json:"Name,omitempty"`
+	DataValue interface{}   `json:"DataValue,omitempty"`
+	History   []interface{} `json:"History,omitempty"`
+}
+
+// ExportWatchUAJSON writes the last received DataValue of every watched node to filePath
+// using the OPC UA JSON encoding.
+func (c *Controller) ExportWatchUAJSON(filePath string) error {
+	c.mu.RLock()
+	doc := c.newUAJSONExport()
+	for _, item := range c.watchItems {
+		if item.lastDataValue == nil {
+			continue
+		}
+		nid, err := ua.ParseNodeID(item.NodeID)
+		if err != nil {
+			continue
+		}
+		doc.Items = append(doc.Items, uaJSONExportItem{
+			NodeId:    encodeNodeIDJSON(nid),
+			Name:      item.Name,
+			DataValue: encodeDataValueJSON(item.lastDataValue),
+		})
+	}
+	c.mu.RUnlock()
+	if len(doc.Items) == 0 {
+		return errors.New("no watched values to export")
+	}
+	sort.Slice(doc.Items, func(i, j int) bool { return doc.Items[i].Name < doc.Items[j].Name })
+
+	if err := writeUAJSON(filePath, doc); err != nil {
+		c.Log(fmt.Sprintf("[red]UA JSON export failed: %v[-]", err))
+		return err
+	}
+	c.usageExported()
+	c.Log(fmt.Sprintf("[green]Exported %d watched values as OPC UA JSON to %s[-]", len(doc.Items), filePath))
+	return nil
+}
+
+// ExportHistoryUAJSON writes history values previously returned by ReadHistoryRaw or
+// ReadHistoryAggregate for nodeID to filePath using the OPC UA JSON encoding.
+func (c *Controller) ExportHistoryUAJSON(nodeID string, values []*HistoryValue, filePath string) error {
+	nid, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return fmt.Errorf("invalid node ID: %w", err)
+	}
+	item := uaJSONExportItem{NodeId: encodeNodeIDJSON(nid), History: make([]interface{}, 0, len(values))}
+	for _, hv := range values {
+		if hv != nil && hv.raw != nil {
+			item.History = append(item.History, encodeDataValueJSON(hv.raw))
+		}
+	}
+	c.mu.RLock()
+	doc := c.newUAJSONExport()
+	c.mu.RUnlock()
+	doc.Items = []uaJSONExportItem{item}
+
+	if err := writeUAJSON(filePath, doc); err != nil {
+		c.Log(fmt.Sprintf("[red]UA JSON export failed: %v[-]", err))
+		return err
+	}
+	c.usageExported()
+	c.Log(fmt.Sprintf("[green]Exported %d history values of %s as OPC UA JSON to %s[-]", len(item.History), nodeID, filePath))
+	return nil
+}
+
+// newUAJSONExport must be called with c.mu held.
+func (c *Controller) newUAJSONExport() *uaJSONExport {
+	doc := &uaJSONExport{
+		Encoding:   "OPC UA JSON (reversible)",
+		ExportedAt: time.Now().UTC().Format(uaJSONTimeLayout),
+	}
+	if c.client != nil {
+		doc.Endpoint = c.client.Endpoint()
+	}
+	return doc
+}
+
+func writeUAJSON(filePath string, doc *uaJSONExport) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0o644)
+}
+
+// encodeDataValueJSON encodes a DataValue; Good status codes and zero timestamps are omitted.
+func encodeDataValueJSON(dv *ua.DataValue) map[string]interface{} {
+	out := make(map[string]interface{})
+	if dv == nil {
+		return out
+	}
+	if dv.Value != nil {
+		out["Value"] = encodeVariantJSON(dv.Value)
+	}
+	if dv.Status != ua.StatusOK {
+		out["StatusCode"] = uint32(dv.Status)
+	}
+	if !dv.SourceTimestamp.IsZero() {
+		out["SourceTimestamp"] = dv.SourceTimestamp.UTC().Format(uaJSONTimeLayout)
+	}
+	if dv.SourcePicoseconds != 0 {
+		out["SourcePicoseconds"] = dv.SourcePicoseconds
+	}
+	if !dv.ServerTimestamp.IsZero() {
+		out["ServerTimestamp"] = dv.ServerTimestamp.UTC().Format(uaJSONTimeLayout)
+	}
+	if dv.ServerPicoseconds != 0 {
+		out["ServerPicoseconds"] = dv.ServerPicoseconds
+	}
+	return out
+}
+
+// encodeVariantJSON encodes a Variant as {"Type": <built-in type id>, "Body": ..., "Dimensions": [...]}.
+func encodeVariantJSON(v *ua.Variant) interface{} {
+	if v == nil || v.Type() == ua.TypeIDNull {
+		return nil
+	}
+	out := map[string]interface{}{"Type": uint8(v.Type())}
+	val := v.Value()
+	rv := reflect.ValueOf(val)
+	if v.Has(ua.VariantArrayValues) && rv.Kind() == reflect.Slice {
+		body := make([]interface{}, rv.Len())
+		for i := range body {
+			body[i] = encodeBuiltinJSON(rv.Index(i).Interface())
+		}
+		out["Body"] = body
+		if dims := v.ArrayDimensions(); len(dims) > 1 {
+			out["Dimensions"] = dims
+		}
+		return out
+	}
+	out["Body"] = encodeBuiltinJSON(val)
+	return out
+}
+
+// encodeBuiltinJSON encodes the body of a single built-in type value.
+func encodeBuiltinJSON(val interface{}) interface{} {
+	switch x := val.(type) {
+	case nil:
+		return nil
+	case bool, string, int8, int16, int32, uint8, uint16, uint32:
+		return x
+	case int64:
+		// 64-bit integers are strings so that JavaScript consumers do not lose precision
+		return strconv.FormatInt(x, 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case float32:
+		return uaJSONFloat(float64(x))
+	case float64:
+		return uaJSONFloat(x)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	case time.Time:
+		return x.UTC().Format(uaJSONTimeLayout)
+	case ua.StatusCode:
+		return uint32(x)
+	case *ua.GUID:
+		if x == nil {
+			return nil
+		}
+		return x.String()
+	case *ua.NodeID:
+		return encodeNodeIDJSON(x)
+	case *ua.ExpandedNodeID:
+		return encodeExpandedNodeIDJSON(x)
+	case *ua.QualifiedName:
+		if x == nil {
+			return nil
+		}
+		out := map[string]interface{}{"Name": x.Name}
+		if x.NamespaceIndex != 0 {
+			out["Uri"] = x.NamespaceIndex
+		}
+		return out
+	case *ua.LocalizedText:
+		if x == nil {
+			return nil
+		}
+		out := map[string]interface{}{}
+		if x.Locale != "" {
+			out["Locale"] = x.Locale
+		}
+		if x.Text != "" {
+			out["Text"] = x.Text
+		}
+		return out
+	case *ua.ExtensionObject:
+		return encodeExtensionObjectJSON(x)
+	case *ua.DataValue:
+		return encodeDataValueJSON(x)
+	case *ua.Variant:
+		return encodeVariantJSON(x)
+	}
+	return toJSONValue(val)
+}
+
+// encodeNodeIDJSON encodes a NodeId as {"IdType", "Id", "Namespace"}; numeric IdType and
+// namespace 0 are omitted.
+func encodeNodeIDJSON(n *ua.NodeID) interface{} {
+	if n == nil {
+		return nil
+	}
+	out := make(map[string]interface{})
+	switch n.Type() {
+	case ua.NodeIDTypeTwoByte, ua.NodeIDTypeFourByte, ua.NodeIDTypeNumeric:
+		out["Id"] = n.IntID()
+	case ua.NodeIDTypeString:
+		out["IdType"] = 1
+		out["Id"] = n.StringID()
+	case ua.NodeIDTypeGUID:
+		out["IdType"] = 2
+		out["Id"] = n.StringID()
+	case ua.NodeIDTypeByteString:
+		out["IdType"] = 3
+		out["Id"] = n.StringID()
+	}
+	if ns := n.Namespace(); ns != 0 {
+		out["Namespace"] = ns
+	}
+	return out
+}
+
+func encodeExpandedNodeIDJSON(n *ua.ExpandedNodeID) interface{} {
+	if n == nil || n.NodeID == nil {
+		return nil
+	}
+	out := encodeNodeIDJSON(n.NodeID).(map[string]interface{})
+	if n.NamespaceURI != "" {
+		out["Namespace"] = n.NamespaceURI
+	}
+	if n.ServerIndex != 0 {
+		out["ServerUri"] = n.ServerIndex
+	}
+	return out
+}
+
+// encodeExtensionObjectJSON keeps the structure in its binary encoding (Encoding 1) so that
+// any structure, including ones unknown to this client, round-trips without loss.
+func encodeExtensionObjectJSON(eo *ua.ExtensionObject) interface{} {
+	if eo == nil || eo.TypeID == nil {
+		return nil
+	}
+	out := map[string]interface{}{"TypeId": encodeNodeIDJSON(eo.TypeID.NodeID)}
+	if eo.Value == nil {
+		return out
+	}
+	if body, err := ua.Encode(eo.Value); err == nil {
+		out["Encoding"] = 1
+		out["Body"] = base64.StdEncoding.EncodeToString(body)
+	} else {
+		// Fall back to a readable body if the value cannot be binary encoded
+		out["Body"] = toJSONValue(eo.Value)
+	}
+	return out
+}
+
+func uaJSONFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
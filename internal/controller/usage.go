@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// SetUsageStats restores the usage statistics saved in the config.
+func (c *Controller) SetUsageStats(s opc.UsageStats) {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	c.usage = s
+	if c.usage.Since.IsZero() {
+		c.usage.Since = time.Now()
+	}
+}
+
+// UsageStats returns the usage statistics including the running session.
+func (c *Controller) UsageStats() opc.UsageStats {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	s := c.usage
+	if s.Since.IsZero() {
+		s.Since = time.Now()
+	}
+	if !c.sessionStart.IsZero() {
+		d := time.Since(c.sessionStart).Seconds()
+		s.SessionSeconds += d
+		s.LongestSessionSeconds = max(s.LongestSessionSeconds, d)
+		s.LargestAddressSpace = max(s.LargestAddressSpace, c.addressSpaceSize())
+	}
+	return s
+}
+
+// ResetUsageStats starts the statistics afresh; a running session counts from now.
+func (c *Controller) ResetUsageStats() {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	c.usage = opc.UsageStats{Since: time.Now()}
+	if !c.sessionStart.IsZero() {
+		c.sessionStart = time.Now()
+		c.usage.Sessions = 1
+	}
+}
+
+func (c *Controller) usageCount(fn func(u *opc.UsageStats)) {
+	c.usageMu.Lock()
+	fn(&c.usage)
+	c.usageMu.Unlock()
+}
+
+func (c *Controller) usageSessionStarted() {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	c.usage.Sessions++
+	c.sessionStart = time.Now()
+}
+
+// usageSessionEnded must run before the address space cache is cleared.
+func (c *Controller) usageSessionEnded() {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	if c.sessionStart.IsZero() {
+		return
+	}
+	d := time.Since(c.sessionStart).Seconds()
+	c.usage.SessionSeconds += d
+	c.usage.LongestSessionSeconds = max(c.usage.LongestSessionSeconds, d)
+	c.usage.LargestAddressSpace = max(c.usage.LargestAddressSpace, c.addressSpaceSize())
+	c.sessionStart = time.Time{}
+}
+
+func (c *Controller) addressSpaceSize() int {
+	c.addressSpaceMutex.RLock()
+	defer c.addressSpaceMutex.RUnlock()
+	return len(c.addressSpaceNodes)
+}
+
+// usageWrites counts write outcomes.
+func (c *Controller) usageWrites(ok, failed int) {
+	c.usageCount(func(u *opc.UsageStats) {
+		u.Writes += int64(ok + failed)
+		u.FailedWrites += int64(failed)
+	})
+}
+
+func (c *Controller) usageExported() {
+	c.usageCount(func(u *opc.UsageStats) { u.Exports++ })
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ValueCopy is a node value with the metadata a script needs to read or write it again through
+// the REST API. It is what "Copy value" puts on the clipboard.
+type ValueCopy struct {
+	NodeID          string      `json:"node_id"`
+	Name            string      `json:"name,omitempty"`
+	DataType        string      `json:"data_type"`
+	UAType          string      `json:"ua_type,omitempty"`
+	Value           interface{} `json:"value"`
+	Status          string      `json:"status,omitempty"`
+	SourceTimestamp string      `json:"source_timestamp,omitempty"`
+
+	text string // Value as accepted by POST /api/v1/write
+}
+
+// ValueCopyFromWatch takes the latest value of a watch item. A forced item yields the server's
+// value, not the local override.
+func ValueCopyFromWatch(it *WatchItem) *ValueCopy {
+	v := &ValueCopy{
+		NodeID:          it.NodeID,
+		Name:            it.Name,
+		DataType:        it.DataType,
+		UAType:          it.UAType,
+		Value:           it.ValueTyped,
+		Status:          it.SymbolicName,
+		SourceTimestamp: it.SourceTimestamp,
+		text:            it.Value,
+	}
+	if it.Forced {
+		v.Value, v.text = it.serverTyped, it.ServerValue
+	}
+	return v
+}
+
+// ValueCopyFromAttributes takes the Value attribute of a node read with ReadNodeAttributes.
+func ValueCopyFromAttributes(a *NodeAttributes) *ValueCopy {
+	return &ValueCopy{
+		NodeID:          a.NodeID,
+		Name:            a.Name,
+		DataType:        a.DataType,
+		UAType:          a.UAType,
+		Value:           a.ValueTyped,
+		SourceTimestamp: a.SourceTimestamp,
+		Status:          a.AttributeStatus["Value"], // empty when Good
+		text:            a.Value,
+	}
+}
+
+// JSON renders v as indented JSON.
+func (v *ValueCopy) JSON() (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data), err
+}
+
+// CurlRead returns a curl command reading the node through the REST API at baseURL
+// (e.g. "http://localhost:8080"). windows quotes for cmd.exe instead of a POSIX shell.
+func (v *ValueCopy) CurlRead(baseURL string, windows bool) string {
+	return curlPost(baseURL+"/api/v1/read", map[string]string{"node_id": v.NodeID}, windows)
+}
+
+// CurlWrite returns a curl command writing the copied value back to the node, as a template
+// to edit.
+func (v *ValueCopy) CurlWrite(baseURL string, windows bool) string {
+	body := map[string]string{"node_id": v.NodeID, "data_type": v.DataType, "value": v.text}
+	return curlPost(baseURL+"/api/v1/write", body, windows)
+}
+
+func curlPost(url string, body map[string]string, windows bool) string {
+	data, _ := json.Marshal(body) // map[string]string cannot fail
+	return "curl -X POST " + shellQuote(url, windows) +
+		" -H " + shellQuote("Content-Type: application/json", windows) +
+		" -d " + shellQuote(string(data), windows)
+}
+
+// shellQuote quotes s as one argument of a POSIX shell or, for windows, of cmd.exe.
+func shellQuote(s string, windows bool) string {
+	if windows {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
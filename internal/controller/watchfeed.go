@@ -0,0 +1,94 @@
+package controller
+
+import "sync"
+
+// WatchFeed queues watch updates for one API consumer, keyed by NodeID. Push never blocks and
+// never loses the newest value: while a node has an undelivered update, a newer one replaces it
+// in place and keeps its position in the queue. A slow consumer therefore skips intermediate
+// values under bursts but always ends up with the latest value of every node.
+type WatchFeed struct {
+	mu         sync.Mutex
+	pending    map[string]*WatchItem
+	order      []string // NodeIDs of pending in arrival order
+	ready      chan struct{}
+	superseded uint64
+}
+
+// NewWatchFeed returns an empty feed.
+func NewWatchFeed() *WatchFeed {
+	return &WatchFeed{pending: make(map[string]*WatchItem), ready: make(chan struct{}, 1)}
+}
+
+// Push queues it, replacing an undelivered update of the same node.
+func (f *WatchFeed) Push(it *WatchItem) {
+	if it == nil {
+		return
+	}
+	f.mu.Lock()
+	if _, ok := f.pending[it.NodeID]; ok {
+		f.superseded++
+	} else {
+		f.order = append(f.order, it.NodeID)
+	}
+	f.pending[it.NodeID] = it
+	f.mu.Unlock()
+	select {
+	case f.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ready is signalled after Push; call Drain to take the queued updates.
+func (f *WatchFeed) Ready() <-chan struct{} { return f.ready }
+
+// Drain returns and removes the queued updates, oldest node first.
+func (f *WatchFeed) Drain() []*WatchItem {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.order) == 0 {
+		return nil
+	}
+	items := make([]*WatchItem, 0, len(f.order))
+	for _, id := range f.order {
+		items = append(items, f.pending[id])
+		delete(f.pending, id)
+	}
+	f.order = f.order[:0]
+	return items
+}
+
+// Superseded returns how many updates were replaced by a newer value before delivery.
+func (f *WatchFeed) Superseded() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.superseded
+}
+
+// SubscribeWatchFeed returns a feed receiving every watch update until UnsubscribeWatchFeed.
+func (c *Controller) SubscribeWatchFeed() *WatchFeed {
+	f := NewWatchFeed()
+	c.feedsMu.Lock()
+	if c.feeds == nil {
+		c.feeds = make(map[*WatchFeed]struct{})
+	}
+	c.feeds[f] = struct{}{}
+	c.feedsMu.Unlock()
+	return f
+}
+
+// UnsubscribeWatchFeed stops delivering watch updates to f.
+func (c *Controller) UnsubscribeWatchFeed(f *WatchFeed) {
+	c.feedsMu.Lock()
+	delete(c.feeds, f)
+	c.feedsMu.Unlock()
+}
+
+// broadcastWatch hands a watch update (a copy owned by the receivers) to every feed.
+func (c *Controller) broadcastWatch(msg *WatchItem) {
+	msg.subHandle = nil
+	c.feedsMu.Lock()
+	defer c.feedsMu.Unlock()
+	for f := range c.feeds {
+		f.Push(msg)
+	}
+}
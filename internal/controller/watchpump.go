@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// backgroundPumpInterval is the slowest watch list redraw rate, used while the window is in the background.
+const backgroundPumpInterval = time.Second
+
+// intervals returns the effective update rates. Callers must not hold c.mu.
+func (c *Controller) intervals() opc.Intervals {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentConfig.Intervals()
+}
+
+func (c *Controller) subscriptionTuning() opc.SubscriptionTuning {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentConfig.SubscriptionTuning()
+}
+
+// SetWatchPumpBackoff slows the watch list redraw down to backgroundPumpInterval while on is true,
+// e.g. when the window is minimized or loses focus.
+func (c *Controller) SetWatchPumpBackoff(on bool) {
+	c.pumpBackoff.Store(on)
+}
+
+// markWatchDirty schedules a redraw of the nodeID row on the next pump tick. Value changes are
+// coalesced so a busy subscription does not redraw the UI on every notification.
+func (c *Controller) markWatchDirty(nodeID string) {
+	c.pumpMu.Lock()
+	if c.watchChanged == nil {
+		c.watchChanged = make(map[string]struct{})
+	}
+	c.watchChanged[nodeID] = struct{}{}
+	c.pumpMu.Unlock()
+	c.pumpOnce.Do(func() { go c.startWatchUpdatePump() })
+}
+
+// startWatchUpdatePump periodically sends the rows changed since the last tick to the UI callback.
+func (c *Controller) startWatchUpdatePump() {
+	for {
+		interval := c.intervals().WatchPump
+		if c.pumpBackoff.Load() && interval < backgroundPumpInterval {
+			interval = backgroundPumpInterval
+		}
+		time.Sleep(interval)
+
+		c.pumpMu.Lock()
+		changed := make([]string, 0, len(c.watchChanged))
+		for id := range c.watchChanged {
+			changed = append(changed, id)
+		}
+		c.watchChanged = nil
+		c.pumpMu.Unlock()
+		if len(changed) == 0 {
+			continue
+		}
+		sort.Strings(changed)
+
+		c.mu.RLock()
+		update := c.OnWatchListUpdate
+		c.mu.RUnlock()
+		if update != nil {
+			update(WatchListUpdate{Changed: changed})
+		}
+	}
+}
+
+// ApplyIntervals applies changed update rates and subscription parameters to the running session.
+// The pump picks up its new rate on the next tick; an existing subscription is modified.
+func (c *Controller) ApplyIntervals() {
+	c.mu.RLock()
+	cli := c.client
+	c.mu.RUnlock()
+	if cli == nil {
+		return
+	}
+	iv := c.intervals()
+	ctx, cancel := c.opContext(context.Background(), c.timeouts().Write)
+	defer cancel()
+	if err := cli.SetPublishInterval(ctx, iv.Publish); err != nil {
+		c.Log(fmt.Sprintf("[red]Failed to change publishing interval to %v: %v[-]", iv.Publish, err))
+		return
+	}
+	if err := cli.SetSubscriptionTuning(ctx, c.subscriptionTuning()); err != nil {
+		c.Log(fmt.Sprintf("[red]Failed to change subscription parameters: %v[-]", err))
+		return
+	}
+	c.Log(fmt.Sprintf("[green]Publishing interval %v, watch refresh every %v[-]", iv.Publish, iv.WatchPump))
+}
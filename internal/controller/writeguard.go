@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// ErrWriteDenied is returned (wrapped) for writes blocked by the write allow/deny lists.
+var ErrWriteDenied = errors.New("write denied by write protection")
+
+// writePattern is one allow/deny entry: "re:<regexp>", "<prefix>*" or an exact NodeID.
+// Entries match the canonical NodeID as well as its "nsu=<uri>;<id>" form (see writeTargets);
+// exact and prefix entries are canonicalized the same way, and regexps must match the whole
+// NodeID.
+type writePattern struct {
+	text   string
+	exact  string
+	prefix string
+	re     *regexp.Regexp
+}
+
+func parseWritePattern(s string) (writePattern, error) {
+	p := writePattern{text: s}
+	switch {
+	case strings.HasPrefix(s, "re:"):
+		re, err := regexp.Compile("^(?:" + s[3:] + ")$")
+		if err != nil {
+			return p, fmt.Errorf("invalid write pattern %q: %w", s, err)
+		}
+		p.re = re
+	case strings.HasSuffix(s, "*"):
+		p.prefix = canonicalPrefix(strings.TrimSuffix(s, "*"))
+	default:
+		p.exact = canonicalNodeID(s)
+	}
+	return p, nil
+}
+
+// canonicalNodeID returns the NodeID s as ua.NodeID.String formats it, e.g. "ns=2;i=5" for
+// "ns=02;i=05", keeping an "nsu=<uri>;" prefix; s is returned as it is when it does not parse.
+func canonicalNodeID(s string) string {
+	if rest, ok := strings.CutPrefix(s, "nsu="); ok {
+		uri, ident, found := strings.Cut(rest, ";")
+		if n, err := ua.ParseNodeID(ident); found && err == nil {
+			return "nsu=" + uri + ";" + n.String()
+		}
+		return s
+	}
+	if n, err := ua.ParseNodeID(s); err == nil {
+		return n.String()
+	}
+	return s
+}
+
+// canonicalPrefix strips the leading zeros of the namespace index of a prefix entry, e.g.
+// "ns=02;s=Pump" is "ns=2;s=Pump", and drops "ns=0;" in front of an identifier, which
+// canonical NodeIDs of namespace 0 do not have.
+func canonicalPrefix(p string) string {
+	rest, ok := strings.CutPrefix(p, "ns=")
+	if !ok {
+		return p
+	}
+	idx, ident, found := strings.Cut(rest, ";")
+	n, err := strconv.ParseUint(idx, 10, 16)
+	if !found || err != nil {
+		return p
+	}
+	if n == 0 {
+		if ident == "" {
+			return p
+		}
+		return ident
+	}
+	return "ns=" + strconv.FormatUint(n, 10) + ";" + ident
+}
+
+// writeTargets returns the forms of nodeID that allow/deny entries are matched against: as
+// given, canonical, and in the "ns=<index>" and "nsu=<uri>" forms as far as namespaces (the
+// session's NamespaceArray) resolves them. "ns=02;s=Pump" thus matches the entries
+// "ns=2;s=Pump" and "nsu=<uri of 2>;s=Pump".
+func writeTargets(nodeID string, namespaces []string) []string {
+	targets := []string{nodeID}
+	add := func(s string) {
+		if s != "" && !slices.Contains(targets, s) {
+			targets = append(targets, s)
+		}
+	}
+	id := nodeID
+	if strings.HasPrefix(nodeID, "nsu=") {
+		add(canonicalNodeID(nodeID))
+		if r, err := opc.ResolveNodeID(nodeID, namespaces); err == nil {
+			id = r
+		}
+	}
+	if n, err := ua.ParseNodeID(id); err == nil {
+		add(n.String())
+		if ref, err := opc.ExpandNodeID(n.String(), namespaces); err == nil {
+			add(ref)
+		}
+	}
+	return targets
+}
+
+func (p writePattern) match(id string) bool {
+	switch {
+	case p.re != nil:
+		return p.re.MatchString(id)
+	case p.exact != "":
+		return id == p.exact
+	default:
+		return strings.HasPrefix(id, p.prefix)
+	}
+}
+
+// ValidateWritePatterns checks allow/deny entries, e.g. before saving settings.
+func ValidateWritePatterns(patterns []string) error {
+	for _, s := range patterns {
+		if _, err := parseWritePattern(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeGuard is the compiled allow/deny configuration. A node matching a deny entry is never
+// written; with a non-empty allow list only matching nodes are.
+type writeGuard struct {
+	allow, deny []writePattern
+	namespaces  []string // NamespaceArray of the session, for the "nsu=" forms
+}
+
+// writeGuard compiles the current lists. An invalid entry blocks all writes rather than
+// silently dropping protection.
+func (c *Controller) writeGuard() (*writeGuard, error) {
+	c.mu.RLock()
+	var allow, deny []string
+	if c.currentConfig != nil {
+		allow, deny = c.currentConfig.WriteAllow, c.currentConfig.WriteDeny
+	}
+	namespaces := c.namespaces
+	c.mu.RUnlock()
+	return compileWriteGuard(allow, deny, namespaces)
+}
+
+func compileWriteGuard(allow, deny, namespaces []string) (*writeGuard, error) {
+	g := &writeGuard{namespaces: namespaces}
+	for _, l := range []struct {
+		src []string
+		dst *[]writePattern
+	}{{allow, &g.allow}, {deny, &g.deny}} {
+		for _, s := range l.src {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			p, err := parseWritePattern(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrWriteDenied, err)
+			}
+			*l.dst = append(*l.dst, p)
+		}
+	}
+	return g, nil
+}
+
+func (g *writeGuard) check(nodeID string) error {
+	targets := writeTargets(nodeID, g.namespaces)
+	matches := func(p writePattern) bool { return slices.ContainsFunc(targets, p.match) }
+	for _, p := range g.deny {
+		if matches(p) {
+			return fmt.Errorf("%w: %s matches deny entry %q", ErrWriteDenied, nodeID, p.text)
+		}
+	}
+	if len(g.allow) == 0 {
+		return nil
+	}
+	for _, p := range g.allow {
+		if matches(p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in the write allow list", ErrWriteDenied, nodeID)
+}
+
+// CheckWriteAllowed reports whether the write allow/deny lists permit writing nodeID.
+func (c *Controller) CheckWriteAllowed(nodeID string) error {
+	g, err := c.writeGuard()
+	if err != nil {
+		return err
+	}
+	return g.check(nodeID)
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWriteGuardCheck(t *testing.T) {
+	namespaces := []string{"http://opcfoundation.org/UA/", "urn:server", "urn:plant"}
+	tests := []struct {
+		name        string
+		allow, deny []string
+		namespaces  []string
+		nodeID      string
+		denied      bool
+	}{
+		{name: "exact deny", deny: []string{"ns=2;s=Pump"}, nodeID: "ns=2;s=Pump", denied: true},
+		{name: "exact deny, other node", deny: []string{"ns=2;s=Pump"}, nodeID: "ns=2;s=Pump2"},
+		{name: "padded namespace index", deny: []string{"ns=2;s=Pump"}, nodeID: "ns=02;s=Pump", denied: true},
+		{name: "padded namespace index, connected", deny: []string{"ns=2;s=Pump"}, namespaces: namespaces, nodeID: "ns=02;s=Pump", denied: true},
+		{name: "padded numeric identifier", deny: []string{"ns=2;i=5"}, nodeID: "ns=2;i=0005", denied: true},
+		{name: "padded deny entry", deny: []string{"ns=002;i=05"}, nodeID: "ns=2;i=5", denied: true},
+		{name: "namespace 0 written out", deny: []string{"i=2255"}, nodeID: "ns=0;i=2255", denied: true},
+		{name: "nsu entry, index input", deny: []string{"nsu=urn:plant;s=Pump"}, namespaces: namespaces, nodeID: "ns=02;s=Pump", denied: true},
+		{name: "nsu entry, other namespace", deny: []string{"nsu=urn:plant;s=Pump"}, namespaces: namespaces, nodeID: "ns=1;s=Pump"},
+		{name: "index entry, nsu input", deny: []string{"ns=2;s=Pump"}, namespaces: namespaces, nodeID: "nsu=urn:plant;s=Pump", denied: true},
+		{name: "prefix", deny: []string{"ns=2;s=Pump*"}, nodeID: "ns=2;s=PumpSpeed", denied: true},
+		{name: "padded prefix entry", deny: []string{"ns=02;s=Pump*"}, nodeID: "ns=2;s=PumpSpeed", denied: true},
+		{name: "prefix, padded input", deny: []string{"ns=2;s=Pump*"}, nodeID: "ns=0002;s=PumpSpeed", denied: true},
+		{name: "namespace prefix", deny: []string{"ns=2;*"}, nodeID: "ns=02;i=7", denied: true},
+		{name: "namespace prefix, other namespace", deny: []string{"ns=2;*"}, nodeID: "ns=3;i=7"},
+		{name: "regexp is anchored", deny: []string{"re:Pump"}, nodeID: "ns=2;s=PumpSpeedSetpoint"},
+		{name: "regexp matching the whole NodeID", deny: []string{"re:ns=2;s=Pump.*"}, nodeID: "ns=2;s=PumpSpeedSetpoint", denied: true},
+		{name: "regexp against canonical form", deny: []string{"re:ns=2;s=Pump"}, nodeID: "ns=02;s=Pump", denied: true},
+		{name: "regexp alternation is anchored", deny: []string{"re:ns=2;s=A|ns=2;s=B"}, nodeID: "ns=2;s=AB"},
+		{name: "allow list", allow: []string{"ns=2;s=Pump"}, nodeID: "ns=002;s=Pump"},
+		{name: "allow list, other node", allow: []string{"ns=2;s=Pump"}, nodeID: "ns=2;s=Valve", denied: true},
+		{name: "deny wins over allow", allow: []string{"ns=2;*"}, deny: []string{"ns=2;s=Pump"}, nodeID: "ns=02;s=Pump", denied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := compileWriteGuard(tt.allow, tt.deny, tt.namespaces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = g.check(tt.nodeID)
+			if denied := errors.Is(err, ErrWriteDenied); denied != tt.denied {
+				t.Errorf("check(%q) = %v, want denied %v", tt.nodeID, err, tt.denied)
+			}
+		})
+	}
+}
+
+func TestCompileWriteGuardInvalidPattern(t *testing.T) {
+	if _, err := compileWriteGuard(nil, []string{"re:("}, nil); !errors.Is(err, ErrWriteDenied) {
+		t.Errorf("compileWriteGuard with an invalid regexp = %v, want ErrWriteDenied", err)
+	}
+}
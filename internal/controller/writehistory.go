@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// errWriteNotDone is recorded for writes that failed before reaching the server (parse errors,
+// AccessLevel gates); the log has the details.
+var errWriteNotDone = errors.New("not written, see log")
+
+// recordWrite reports the outcome of a WriteValue call to OnWriteDone.
+func (c *Controller) recordWrite(nodeID, dataType, value string, err error) {
+	if err != nil {
+		c.usageWrites(0, 1)
+	} else {
+		c.usageWrites(1, 0)
+	}
+	cb := c.OnWriteDone
+	if cb == nil {
+		return
+	}
+	rec := opc.WriteRecord{Ref: c.NodeRef(nodeID), DataType: dataType, Value: value, Time: time.Now(), Result: "Good"}
+	if err != nil {
+		rec.Result = err.Error()
+	}
+	cb(rec)
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QueuedWrite is a write issued while the session was down, replayed once it is back (see
+// Config.WriteQueue).
+type QueuedWrite struct {
+	ID       int       `json:"id"`
+	NodeID   string    `json:"node_id"`
+	DataType string    `json:"data_type"`
+	Value    string    `json:"value"`
+	Endpoint string    `json:"endpoint"` // server the write is meant for; only replayed there
+	Queued   time.Time `json:"queued"`
+}
+
+// queueWrite buffers a write when the write queue is enabled and the session is down (not
+// connected, or connection lost and reconnecting), and reports whether it did.
+func (c *Controller) queueWrite(log func(string), nodeID, dataType, valueStr string) bool {
+	c.mu.RLock()
+	cfg := c.currentConfig
+	down := c.client == nil || c.linkDown
+	c.mu.RUnlock()
+	if cfg == nil || !cfg.WriteQueue || !down || cfg.EndpointURL == "" {
+		return false
+	}
+
+	c.wqMu.Lock()
+	c.wqNextID++
+	qw := QueuedWrite{ID: c.wqNextID, NodeID: nodeID, DataType: dataType, Value: valueStr, Endpoint: cfg.EndpointURL, Queued: time.Now()}
+	c.writeQueue = append(c.writeQueue, qw)
+	n := len(c.writeQueue)
+	c.wqMu.Unlock()
+
+	log(fmt.Sprintf("[yellow]Not connected: write #%d of %s = %s queued (%d pending)[-]", qw.ID, nodeID, valueStr, n))
+	c.notifyWriteQueue()
+	return true
+}
+
+// QueuedWrites returns the writes waiting for the session, oldest first.
+func (c *Controller) QueuedWrites() []QueuedWrite {
+	c.wqMu.Lock()
+	defer c.wqMu.Unlock()
+	return append([]QueuedWrite(nil), c.writeQueue...)
+}
+
+// CancelQueuedWrite drops the queued write id; it reports false when it was already replayed
+// or cancelled.
+func (c *Controller) CancelQueuedWrite(id int) bool {
+	c.wqMu.Lock()
+	var found *QueuedWrite
+	for i, qw := range c.writeQueue {
+		if qw.ID == id {
+			found = &qw
+			c.writeQueue = append(c.writeQueue[:i], c.writeQueue[i+1:]...)
+			break
+		}
+	}
+	c.wqMu.Unlock()
+	if found == nil {
+		return false
+	}
+	c.Log(fmt.Sprintf("[yellow]Queued write #%d of %s cancelled[-]", id, found.NodeID))
+	c.notifyWriteQueue()
+	return true
+}
+
+func (c *Controller) notifyWriteQueue() {
+	if c.OnWriteQueueUpdate != nil {
+		c.OnWriteQueueUpdate()
+	}
+}
+
+// replayWriteQueue writes the queued writes for the connected endpoint one after another, in
+// the order they were issued. Writes for other servers stay queued. It stops when the session
+// goes down again; the write being replayed then fails like any other.
+func (c *Controller) replayWriteQueue() {
+	c.wqMu.Lock()
+	if c.wqReplaying {
+		c.wqMu.Unlock()
+		return
+	}
+	c.wqReplaying = true
+	c.wqMu.Unlock()
+	defer func() {
+		c.wqMu.Lock()
+		c.wqReplaying = false
+		c.wqMu.Unlock()
+	}()
+
+	replayed, kept := 0, 0
+	for {
+		c.mu.RLock()
+		cli := c.client
+		down := c.linkDown
+		endpoint := ""
+		if c.currentConfig != nil {
+			endpoint = c.currentConfig.EndpointURL
+		}
+		c.mu.RUnlock()
+		if cli == nil || down {
+			return
+		}
+
+		c.wqMu.Lock()
+		var next *QueuedWrite
+		kept = 0
+		at := -1
+		for i, qw := range c.writeQueue {
+			if qw.Endpoint != endpoint {
+				kept++
+			} else if at < 0 {
+				at = i
+			}
+		}
+		if at >= 0 {
+			qw := c.writeQueue[at]
+			next = &qw
+			c.writeQueue = append(c.writeQueue[:at], c.writeQueue[at+1:]...)
+		}
+		c.wqMu.Unlock()
+		if next == nil {
+			break
+		}
+		c.notifyWriteQueue()
+
+		c.Log(fmt.Sprintf("[cyan]Replaying queued write #%d: %s = %s (queued %s)[-]", next.ID, next.NodeID, next.Value, next.Queued.Format("15:04:05")))
+		if err := c.CheckWriteAllowed(next.NodeID); err != nil {
+			c.Log(fmt.Sprintf("[red]Write refused: %v[-]", err))
+			c.recordWrite(next.NodeID, next.DataType, next.Value, err)
+			continue
+		}
+		ctx := c.GetClientContext()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		c.writeValueNow(ctx, cli, c.Log, next.NodeID, next.DataType, next.Value)
+		replayed++
+	}
+	if replayed > 0 {
+		c.Log(fmt.Sprintf("[green]Replayed %d queued writes[-]", replayed))
+	}
+	if kept > 0 {
+		c.Log(fmt.Sprintf("[yellow]%d queued writes for other servers stay pending[-]", kept))
+	}
+}
@@ -0,0 +1,269 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"opcuababy/internal/opc"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	excelSummarySheet = "Summary"
+	excelTreeSheet    = "Address Space"
+	// Excel supports outline levels 1..7; deeper nodes share the deepest group.
+	excelMaxOutlineLevel = 7
+	excelMaxColumnWidth  = 80
+)
+
+// ExportToExcel exports the address space starting from rootNodeID to an Excel workbook with:
+//   - a Summary sheet (server, export time, node counts per NodeClass),
+//   - the full tree with indentation and collapsible outline groups,
+//   - one flat sheet per NodeClass whose NodeID cells link back to the tree row.
+//
+// The columns follow the template set with SetTemplate. All data sheets have a bold, frozen header row, auto filters and fitted column widths.
+func (e *Exporter) ExportToExcel(ctx context.Context, rootNodeID, filePath string) error {
+	visited := make(map[string]struct{})
+	rootNode, err := e.buildTree(ctx, rootNodeID, visited)
+	if err != nil {
+		return fmt.Errorf("failed to build address space tree: %w", err)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName("Sheet1", excelSummarySheet); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(excelTreeSheet); err != nil {
+		return err
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	linkStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}})
+	if err != nil {
+		return err
+	}
+	indentStyles := make(map[int]int)
+	indentStyle := func(level int) int {
+		if id, ok := indentStyles[level]; ok {
+			return id
+		}
+		id, _ := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Indent: level}})
+		indentStyles[level] = id
+		return id
+	}
+
+	// Tree sheet: flatten depth-first, remembering each node's row for hyperlinks.
+	type flatRow struct {
+		node  *ExportNode
+		level int
+		row   int
+	}
+	var rows []flatRow
+	stack := []flatRow{{node: rootNode}}
+	for len(stack) > 0 {
+		fr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fr.row = len(rows) + 2
+		rows = append(rows, fr)
+		for i := len(fr.node.Children) - 1; i >= 0; i-- {
+			stack = append(stack, flatRow{node: fr.node.Children[i], level: fr.level + 1})
+		}
+	}
+
+	tmpl := e.layout()
+	headers := tmpl.Headers()
+	nameCol, idCol := tmpl.Index("name"), tmpl.Index("node_id")
+	widths := newColumnWidths(headers, nameCol)
+	if err := writeExcelHeader(f, excelTreeSheet, headers, headerStyle); err != nil {
+		return err
+	}
+	byClass := make(map[string][]flatRow)
+	for _, r := range rows {
+		values := e.excelRowValues(tmpl, r.node, r.level)
+		cell, _ := excelize.CoordinatesToCellName(1, r.row)
+		if err := f.SetSheetRow(excelTreeSheet, cell, &values); err != nil {
+			return err
+		}
+		widths.observe(values, r.level)
+		if r.level > 0 {
+			if nameCol >= 0 {
+				nameCell, _ := excelize.CoordinatesToCellName(nameCol+1, r.row)
+				_ = f.SetCellStyle(excelTreeSheet, nameCell, nameCell, indentStyle(r.level))
+			}
+			lvl := r.level
+			if lvl > excelMaxOutlineLevel {
+				lvl = excelMaxOutlineLevel
+			}
+			_ = f.SetRowOutlineLevel(excelTreeSheet, r.row, uint8(lvl))
+		}
+		class := r.node.NodeClass
+		if class == "" {
+			class = "Unknown"
+		}
+		byClass[class] = append(byClass[class], r)
+	}
+	if err := finishDataSheet(f, excelTreeSheet, len(rows), widths); err != nil {
+		return err
+	}
+
+	// Per-NodeClass sheets
+	classes := make([]string, 0, len(byClass))
+	for class := range byClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		sheet := strings.TrimPrefix(class, "NodeClass")
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+		if err := writeExcelHeader(f, sheet, headers, headerStyle); err != nil {
+			return err
+		}
+		w := newColumnWidths(headers, nameCol)
+		for i, r := range byClass[class] {
+			values := e.excelRowValues(tmpl, r.node, r.level)
+			cell, _ := excelize.CoordinatesToCellName(1, i+2)
+			if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+				return err
+			}
+			w.observe(values, 0)
+			if idCol < 0 {
+				continue
+			}
+			idCell, _ := excelize.CoordinatesToCellName(idCol+1, i+2)
+			target, _ := excelize.CoordinatesToCellName(idCol+1, r.row)
+			if err := f.SetCellHyperLink(sheet, idCell, fmt.Sprintf("'%s'!%s", excelTreeSheet, target), "Location"); err == nil {
+				_ = f.SetCellStyle(sheet, idCell, idCell, linkStyle)
+			}
+		}
+		if err := finishDataSheet(f, sheet, len(byClass[class]), w); err != nil {
+			return err
+		}
+	}
+
+	// Summary sheet
+	summary := [][]interface{}{
+		{"Server", e.client.Endpoint()},
+		{"Root NodeID", rootNodeID},
+		{"Exported At", time.Now().Format(time.RFC3339)},
+		{"Total Nodes", len(rows)},
+		{},
+		{"NodeClass", "Count"},
+	}
+	for _, class := range classes {
+		summary = append(summary, []interface{}{class, len(byClass[class])})
+	}
+	for i, values := range summary {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(excelSummarySheet, cell, &values); err != nil {
+			return err
+		}
+	}
+	_ = f.SetCellStyle(excelSummarySheet, "A1", "A4", headerStyle)
+	_ = f.SetCellStyle(excelSummarySheet, "A6", "B6", headerStyle)
+	for i, class := range classes {
+		cell, _ := excelize.CoordinatesToCellName(1, i+7)
+		if err := f.SetCellHyperLink(excelSummarySheet, cell, fmt.Sprintf("'%s'!A1", strings.TrimPrefix(class, "NodeClass")), "Location"); err == nil {
+			_ = f.SetCellStyle(excelSummarySheet, cell, cell, linkStyle)
+		}
+	}
+	_ = f.SetColWidth(excelSummarySheet, "A", "A", 18)
+	_ = f.SetColWidth(excelSummarySheet, "B", "B", 60)
+	f.SetActiveSheet(0)
+
+	return f.SaveAs(filePath)
+}
+
+func (e *Exporter) excelRowValues(tmpl *opc.ExportTemplate, node *ExportNode, level int) []interface{} {
+	values := make([]interface{}, len(tmpl.Columns))
+	for i, col := range tmpl.Columns {
+		if col.Field == "level" {
+			values[i] = level
+			continue
+		}
+		values[i] = e.nodeField(node, level, col.Field)
+	}
+	return values
+}
+
+func writeExcelHeader(f *excelize.File, sheet string, headers []string, style int) error {
+	cells := make([]interface{}, len(headers))
+	for i, h := range headers {
+		cells[i] = h
+	}
+	if err := f.SetSheetRow(sheet, "A1", &cells); err != nil {
+		return err
+	}
+	last, _ := excelize.CoordinatesToCellName(len(headers), 1)
+	return f.SetCellStyle(sheet, "A1", last, style)
+}
+
+// finishDataSheet freezes the header row, adds an auto filter and applies column widths.
+func finishDataSheet(f *excelize.File, sheet string, dataRows int, widths *columnWidths) error {
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	last, _ := excelize.CoordinatesToCellName(len(widths.w), dataRows+1)
+	if err := f.AutoFilter(sheet, "A1:"+last, nil); err != nil {
+		return err
+	}
+	for i, w := range widths.w {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(sheet, col, col, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnWidths tracks the widest value per column to approximate Excel's auto-fit.
+type columnWidths struct {
+	w       []float64
+	nameCol int // column indented by tree depth, or -1
+}
+
+func newColumnWidths(headers []string, nameCol int) *columnWidths {
+	cw := &columnWidths{w: make([]float64, len(headers)), nameCol: nameCol}
+	for i, h := range headers {
+		cw.w[i] = float64(utf8.RuneCountInString(h)) + 4
+	}
+	return cw
+}
+
+// observe widens columns for values; indent adds room for the Name column's indentation.
+func (cw *columnWidths) observe(values []interface{}, indent int) {
+	for i, v := range values {
+		if i >= len(cw.w) {
+			break
+		}
+		n := float64(utf8.RuneCountInString(fmt.Sprint(v))) + 2
+		if i == cw.nameCol {
+			n += float64(indent) * 2
+		}
+		if n > excelMaxColumnWidth {
+			n = excelMaxColumnWidth
+		}
+		if n > cw.w[i] {
+			cw.w[i] = n
+		}
+	}
+}
@@ -2,28 +2,28 @@ package exporter
 
 import (
     "context"
-    "encoding/csv"
     "encoding/json"
     "fmt"
     "opcuababy/internal/opc"
     "os"
+    "strconv"
     "strings"
-	"time"
 
 	"github.com/gopcua/opcua/ua"
-	"github.com/xuri/excelize/v2"
 )
 
 // ExportNode represents a node in the address space for export purposes.
 type ExportNode struct {
-	Name        string        `json:"name"`
-	NodeID      string        `json:"nodeId"`
-	NodeClass   string        `json:"nodeClass"`
-	DataType    string        `json:"dataType,omitempty"`
-	AccessLevel string        `json:"accessLevel,omitempty"`
-	Description string        `json:"description,omitempty"`
-	Value       string        `json:"value,omitempty"`
-	Children    []*ExportNode `json:"children,omitempty"`
+	Name         string        `json:"name"`
+	NodeID       string        `json:"nodeId"`
+	NodeClass    string        `json:"nodeClass"`
+	BrowseName   string        `json:"browseName,omitempty"`   // "nsIndex:name"
+	NamespaceURI string        `json:"namespaceUri,omitempty"` // namespace of NodeID
+	DataType     string        `json:"dataType,omitempty"`
+	AccessLevel  string        `json:"accessLevel,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Value        string        `json:"value,omitempty"`
+	Children     []*ExportNode `json:"children,omitempty"`
 }
 
 // ExportToCSV exports the full address space (starting from rootNodeID) to a CSV file.
@@ -40,10 +40,11 @@ func (e *Exporter) ExportToCSV(ctx context.Context, rootNodeID, filePath string)
     }
     defer f.Close()
 
-    w := csv.NewWriter(f)
+    w := opc.NewCSVWriter(f, e.csv)
     defer w.Flush()
 
-    _ = w.Write([]string{"Level", "Name", "NodeID", "NodeClass", "DataType", "AccessLevel", "Description", "Value"})
+    tmpl := e.layout()
+    _ = w.Write(tmpl.Headers())
 
     // Iterative stack to avoid deep recursion
     type frame struct { node *ExportNode; level int }
@@ -51,10 +52,7 @@ func (e *Exporter) ExportToCSV(ctx context.Context, rootNodeID, filePath string)
     for len(stack) > 0 {
         fr := stack[len(stack)-1]
         stack = stack[:len(stack)-1]
-        _ = w.Write([]string{
-            fmt.Sprintf("%d", fr.level), fr.node.Name, fr.node.NodeID, fr.node.NodeClass,
-            fr.node.DataType, fr.node.AccessLevel, fr.node.Description, fr.node.Value,
-        })
+        _ = w.Write(tmpl.Row(func(field string) string { return e.nodeField(fr.node, fr.level, field) }))
         // push children in reverse to keep natural order
         for i := len(fr.node.Children) - 1; i >= 0; i-- {
             stack = append(stack, frame{node: fr.node.Children[i], level: fr.level + 1})
@@ -65,12 +63,107 @@ func (e *Exporter) ExportToCSV(ctx context.Context, rootNodeID, filePath string)
 
 // Exporter handles the logic for exporting the address space.
 type Exporter struct {
-	client *opc.Client
+	client   *opc.Client
+	timeouts opc.Timeouts
+	locale   opc.DisplayLocale
+	template *opc.ExportTemplate
+	// skip are the optional attributes not to read, by export field; readBatch is the number of
+	// nodes per Read request (zero uses defaultReadBatch).
+	skip      map[string]bool
+	readBatch int
+	// namespaces is the server's NamespaceArray, read at the start of a walk
+	namespaces []string
+	csv        opc.CSVFormat
+	// log receives nodes that could not be browsed or read; nil drops them
+	log func(string)
 }
 
-// New creates a new Exporter.
+// New creates a new Exporter using the default request timeouts.
 func New(client *opc.Client) *Exporter {
-	return &Exporter{client: client}
+	return &Exporter{client: client, timeouts: (*opc.Config)(nil).Timeouts()}
+}
+
+// SetTimeouts overrides the per-call browse and read timeouts.
+func (e *Exporter) SetTimeouts(t opc.Timeouts) {
+	e.timeouts = t
+}
+
+// SetLocale sets how numbers and dates are written to CSV and Excel files. JSON exports keep
+// the raw values so they can be loaded back (see controller.StartOffline).
+func (e *Exporter) SetLocale(l opc.DisplayLocale) {
+	e.locale = l
+}
+
+// SetTemplate sets the columns of CSV and Excel files; nil uses the standard layout.
+func (e *Exporter) SetTemplate(t *opc.ExportTemplate) {
+	e.template = t
+}
+
+// SetCSVFormat sets the delimiter, BOM and quoting of CSV files.
+func (e *Exporter) SetCSVFormat(f opc.CSVFormat) {
+	e.csv = f
+}
+
+// SkipAttributes sets optional attributes not to read for each node, by export field (see
+// opc.ExportAttributeFields). Skipping Value and Description speeds up exports of large
+// address spaces; the skipped fields stay empty.
+func (e *Exporter) SkipAttributes(fields []string) {
+	e.skip = make(map[string]bool, len(fields))
+	for _, f := range fields {
+		e.skip[f] = true
+	}
+}
+
+// SetReadBatch sets how many nodes' attributes are read per Read request; zero or less uses
+// the default.
+func (e *Exporter) SetReadBatch(n int) {
+	e.readBatch = n
+}
+
+// SetLogger sets where nodes that could not be browsed or read are reported; the export skips
+// them and goes on.
+func (e *Exporter) SetLogger(log func(string)) {
+	e.log = log
+}
+
+func (e *Exporter) logf(format string, args ...any) {
+	if e.log != nil {
+		e.log(fmt.Sprintf(format, args...))
+	}
+}
+
+func (e *Exporter) layout() *opc.ExportTemplate {
+	if e.template != nil {
+		return e.template
+	}
+	return opc.DefaultExportTemplate()
+}
+
+// nodeField returns the named export field of n at tree depth level.
+func (e *Exporter) nodeField(n *ExportNode, level int, field string) string {
+	switch field {
+	case "level":
+		return strconv.Itoa(level)
+	case "name":
+		return n.Name
+	case "node_id":
+		return n.NodeID
+	case "node_class":
+		return n.NodeClass
+	case "browse_name":
+		return n.BrowseName
+	case "namespace_uri":
+		return n.NamespaceURI
+	case "data_type":
+		return n.DataType
+	case "access_level":
+		return n.AccessLevel
+	case "description":
+		return n.Description
+	case "value":
+		return e.locale.ValueOfType(n.DataType, n.Value)
+	}
+	return ""
 }
 
 // ExportToJSON exports the full address space starting from rootNodeID to a JSON file.
@@ -89,176 +182,275 @@ func (e *Exporter) ExportToJSON(ctx context.Context, rootNodeID, filePath string
 	return os.WriteFile(filePath, data, 0644)
 }
 
-// ExportToExcel exports the full address space starting from rootNodeID to an Excel file.
-func (e *Exporter) ExportToExcel(ctx context.Context, rootNodeID, filePath string) error {
-    visited := make(map[string]struct{})
-    rootNode, err := e.buildTree(ctx, rootNodeID, visited)
+// defaultReadBatch is the number of nodes whose attributes are read per Read request when no
+// batch size is set.
+const defaultReadBatch = 200
+
+// treeNode is a node being exported with its parsed NodeID, parent and depth.
+type treeNode struct {
+	*ExportNode
+	id       *ua.NodeID
+	parentID string
+	level    int
+}
+
+// buildTree browses the address space from the given nodeID and builds a tree. visited ensures
+// we don't loop forever if the server exposes cyclic references.
+func (e *Exporter) buildTree(ctx context.Context, nodeID string, visited map[string]struct{}) (*ExportNode, error) {
+	var root *ExportNode
+	byID := make(map[string]*ExportNode)
+	err := e.walk(ctx, nodeID, visited, func(n *ExportNode, parentID string, level int) error {
+		if parent := byID[parentID]; parent != nil {
+			parent.Children = append(parent.Children, n)
+		} else if root == nil {
+			root = n
+		}
+		// Variables have no browsed children, so only folders need to be found again.
+		if n.NodeClass != ua.NodeClassVariable.String() {
+			byID[n.NodeID] = n
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to build address space tree: %w", err)
+		return nil, err
 	}
+	return root, nil
+}
 
-	f := excelize.NewFile()
-	sheetName := "OPC UA Address Space"
-	_, err = f.NewSheet(sheetName)
+// walk browses the address space from the given nodeID breadth-first and calls visit for each
+// node with its parent's NodeID ("" for the root) and depth, one level at a time in browse
+// order. NodeClass and DisplayName come from the browse results; the other attributes of each
+// level are read in batched Read requests. Only the current and the next level are kept in
+// memory. visited holds the NodeIDs seen so far.
+func (e *Exporter) walk(ctx context.Context, nodeID string, visited map[string]struct{}, visit func(n *ExportNode, parentID string, level int) error) error {
+	id, err := ua.ParseNodeID(nodeID)
 	if err != nil {
 		return err
 	}
-	f.DeleteSheet("Sheet1")
-
-	headers := []string{"Level", "Name", "NodeID", "NodeClass", "DataType", "AccessLevel", "Description", "Value"}
-	for i, h := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheetName, cell, h)
+	// Without the NamespaceArray the namespace URIs of non-zero namespaces stay empty
+	nsCtx, cancel := context.WithTimeout(ctx, e.timeouts.Read)
+	e.namespaces, _ = e.client.NamespaceArray(nsCtx)
+	cancel()
+	root := treeNode{ExportNode: &ExportNode{NodeID: nodeID, NamespaceURI: opc.NamespaceURI(nodeID, e.namespaces), Children: []*ExportNode{}}, id: id}
+	// The root has no browse result, so read its NodeClass, DisplayName and BrowseName as well.
+	attrs := append([]ua.AttributeID{ua.AttributeIDNodeClass, ua.AttributeIDDisplayName, ua.AttributeIDBrowseName}, e.attributeIDs(true)...)
+	if err := e.readChunk(ctx, []treeNode{root}, attrs); err != nil {
+		return err
+	}
+	if root.Name == "" {
+		root.Name = nodeID
+	}
+	visited[nodeID] = struct{}{}
+	if err := visit(root.ExportNode, "", 0); err != nil {
+		return err
 	}
 
-	row := 2
-	e.writeExcelRow(f, sheetName, rootNode, 0, &row)
-
-	return f.SaveAs(filePath)
+	level := []treeNode{root}
+	for len(level) > 0 {
+		var next []treeNode
+		for _, parent := range level {
+			// Only browse children if the node is not a variable (i.e., it's an object or view)
+			if parent.NodeClass == ua.NodeClassVariable.String() {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			browseCtx, cancel := context.WithTimeout(ctx, e.timeouts.Browse) // Timeout for each browse call
+			refs, err := e.client.Browse(browseCtx, parent.id)
+			cancel()
+			if err != nil {
+				// Log the error but continue, as some nodes might not be browsable
+				e.logf("[yellow]Export: could not browse node %s: %v[-]", parent.NodeID, err)
+				continue
+			}
+			for _, ref := range refs {
+				if ref.NodeID == nil || ref.NodeID.NodeID == nil {
+					continue
+				}
+				// Skip if we've seen this NodeID to avoid cycles
+				cid := ref.NodeID.String()
+				if _, ok := visited[cid]; ok {
+					continue
+				}
+				visited[cid] = struct{}{}
+				child := treeNode{
+					ExportNode: &ExportNode{
+						NodeID:       cid,
+						NodeClass:    ref.NodeClass.String(),
+						BrowseName:   opc.FormatBrowseName(ref.BrowseName),
+						NamespaceURI: opc.NamespaceURI(cid, e.namespaces),
+						Children:     []*ExportNode{},
+					},
+					id:       ref.NodeID.NodeID,
+					parentID: parent.NodeID,
+					level:    parent.level + 1,
+				}
+				if ref.DisplayName != nil {
+					child.Name = ref.DisplayName.Text
+				}
+				if child.Name == "" {
+					child.Name = cid
+				}
+				next = append(next, child)
+			}
+		}
+		if err := e.readLevel(ctx, next); err != nil {
+			return err
+		}
+		for _, n := range next {
+			if err := visit(n.ExportNode, n.parentID, n.level); err != nil {
+				return err
+			}
+		}
+		level = next
+	}
+	return nil
 }
 
-// buildTree recursively browses the address space from the given nodeID and builds a tree.
-// visited ensures we don't loop forever if the server exposes cyclic references.
-func (e *Exporter) buildTree(ctx context.Context, nodeID string, visited map[string]struct{}) (*ExportNode, error) {
-    // Cycle protection
-    if _, ok := visited[nodeID]; ok {
-        // already visited: don't expand; try to keep a human-readable name
-        attrs, _ := e.readAttributes(ctx, nodeID)
-        name := nodeID
-        if attrs != nil && attrs.Name != "" {
-            name = attrs.Name
-        }
-        return &ExportNode{ Name: name, NodeID: nodeID }, nil
-    }
-
-    attrs, err := e.readAttributes(ctx, nodeID)
-    if err != nil {
-        return nil, err
-    }
+// attributeIDs returns the selected attributes to read for a node; DataType, AccessLevel and
+// Value are only read for variables and variable types.
+func (e *Exporter) attributeIDs(variable bool) []ua.AttributeID {
+	var ids []ua.AttributeID
+	if e.reads("description") {
+		ids = append(ids, ua.AttributeIDDescription)
+	}
+	if !variable {
+		return ids
+	}
+	if e.reads("access_level") {
+		ids = append(ids, ua.AttributeIDAccessLevel)
+	}
+	if e.reads("data_type") {
+		ids = append(ids, ua.AttributeIDDataType)
+	}
+	if e.reads("value") {
+		ids = append(ids, ua.AttributeIDValue)
+	}
+	return ids
+}
 
-    exportNode := &ExportNode{
-        Name:        attrs.Name,
-        NodeID:      attrs.NodeID,
-        NodeClass:   attrs.NodeClass,
-        DataType:    attrs.DataType,
-        AccessLevel: attrs.AccessLevel,
-        Description: attrs.Description,
-        Value:       attrs.Value,
-        Children:    []*ExportNode{},
-    }
-    // mark visited after we know the real NodeID
-    visited[exportNode.NodeID] = struct{}{}
-
-    // Only browse children if the node is not a variable (i.e., it's an object or view)
-    if exportNode.NodeClass != ua.NodeClassVariable.String() {
-        browseCtx, cancel := context.WithTimeout(ctx, 30*time.Second) // Timeout for each browse call
-        defer cancel()
-        refs, err := e.client.Browse(browseCtx, ua.MustParseNodeID(nodeID))
-        if err != nil {
-            // Log the error but continue, as some nodes might not be browsable
-            fmt.Printf("could not browse node %s: %v\n", nodeID, err)
-        } else {
-            for _, ref := range refs {
-                // Check for context cancellation before recursing
-                if ctx.Err() != nil {
-                    return nil, ctx.Err()
-                }
-                // Skip if we've seen this NodeID to avoid cycles
-                cid := ref.NodeID.String()
-                if _, ok := visited[cid]; ok {
-                    continue
-                }
-                childNode, err := e.buildTree(ctx, cid, visited)
-                if err != nil {
-                    fmt.Printf("Skipping child node %s due to error: %v\n", ref.NodeID.String(), err)
-                    continue
-                }
-                exportNode.Children = append(exportNode.Children, childNode)
-            }
-        }
-    }
+func (e *Exporter) reads(field string) bool {
+	return !e.skip[field]
+}
 
-    return exportNode, nil
+// readLevel reads the selected attributes of nodes, variables and other nodes separately since
+// they need different attributes.
+func (e *Exporter) readLevel(ctx context.Context, nodes []treeNode) error {
+	var variables, others []treeNode
+	for _, n := range nodes {
+		if n.NodeClass == ua.NodeClassVariable.String() || n.NodeClass == ua.NodeClassVariableType.String() {
+			variables = append(variables, n)
+		} else {
+			others = append(others, n)
+		}
+	}
+	if err := e.readNodes(ctx, variables, e.attributeIDs(true)); err != nil {
+		return err
+	}
+	return e.readNodes(ctx, others, e.attributeIDs(false))
 }
 
-// readAttributes reads all relevant attributes for a given node.
-func (e *Exporter) readAttributes(ctx context.Context, nodeID string) (*ExportNode, error) {
-    attrsToRead := []ua.AttributeID{
-        ua.AttributeIDNodeID,
-        ua.AttributeIDNodeClass,
-        ua.AttributeIDDisplayName,
-        ua.AttributeIDDescription,
-        ua.AttributeIDAccessLevel,
-        ua.AttributeIDDataType,
-        ua.AttributeIDValue,
-    }
+// readNodes reads attrs of nodes in Read requests of up to readBatch nodes. A failed request is
+// retried node by node, since servers may limit the operations per request or reject a single
+// bad node; nodes that still fail keep their browsed name and class. Only a cancelled ctx is an
+// error.
+func (e *Exporter) readNodes(ctx context.Context, nodes []treeNode, attrs []ua.AttributeID) error {
+	if len(attrs) == 0 {
+		return nil
+	}
+	batch := e.readBatch
+	if batch <= 0 {
+		batch = defaultReadBatch
+	}
+	for start := 0; start < len(nodes); start += batch {
+		end := min(start+batch, len(nodes))
+		if err := e.readChunk(ctx, nodes[start:end], attrs); err == nil {
+			continue
+		}
+		for i := start; i < end && ctx.Err() == nil; i++ {
+			if err := e.readChunk(ctx, nodes[i:i+1], attrs); err != nil && ctx.Err() == nil {
+				e.logf("[yellow]Export: could not read node %s: %v[-]", nodes[i].NodeID, err)
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
 
-	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// readChunk reads attrs of nodes in one Read request and stores the results in the nodes.
+func (e *Exporter) readChunk(ctx context.Context, nodes []treeNode, attrs []ua.AttributeID) error {
+	ids := make([]*ua.NodeID, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.id
+	}
+	readCtx, cancel := context.WithTimeout(ctx, e.timeouts.Read)
 	defer cancel()
-	results, err := e.client.ReadAttributes(readCtx, nodeID, attrsToRead...)
+	results, err := e.client.ReadMany(readCtx, ids, attrs...)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	for i, n := range nodes {
+		for j, attr := range attrs {
+			setAttribute(n.ExportNode, attr, results[i*len(attrs)+j])
+		}
 	}
+	return nil
+}
 
-	attrs := &ExportNode{NodeID: nodeID}
-	for i, res := range results {
-		if res.Status != ua.StatusOK || res.Value == nil {
-			continue
+// setAttribute stores a read attribute value in n; bad or empty results are ignored.
+func setAttribute(n *ExportNode, attr ua.AttributeID, res *ua.DataValue) {
+	if res == nil || res.Status != ua.StatusOK || res.Value == nil {
+		return
+	}
+	switch attr {
+	case ua.AttributeIDNodeClass:
+		if val, ok := res.Value.Value().(int32); ok {
+			n.NodeClass = ua.NodeClass(val).String()
 		}
-		switch attrsToRead[i] {
-		case ua.AttributeIDNodeClass:
-			if val, ok := res.Value.Value().(int32); ok {
-				attrs.NodeClass = ua.NodeClass(val).String()
-			}
-		case ua.AttributeIDDisplayName:
-			if val, ok := res.Value.Value().(ua.LocalizedText); ok {
-				attrs.Name = val.Text
-			}
-		case ua.AttributeIDDescription:
-			if val, ok := res.Value.Value().(ua.LocalizedText); ok {
-				attrs.Description = val.Text
-			}
-		case ua.AttributeIDAccessLevel:
-			var levelValue uint32
-			switch v := res.Value.Value().(type) {
-			case uint8:
-				levelValue = uint32(v)
-			case uint32:
-				levelValue = v
-			}
-			if levelValue > 0 {
-				attrs.AccessLevel = formatAccessLevel(ua.AccessLevelType(levelValue))
-			}
-		case ua.AttributeIDDataType:
-			if dt, ok := res.Value.Value().(*ua.NodeID); ok {
-				attrs.DataType = builtinTypeName(dt)
-			}
-		case ua.AttributeIDValue:
-			attrs.Value = fmt.Sprintf("%v", res.Value.Value())
+	case ua.AttributeIDDisplayName:
+		if text := localizedText(res.Value.Value()); text != "" {
+			n.Name = text
 		}
+	case ua.AttributeIDBrowseName:
+		if qn, ok := res.Value.Value().(*ua.QualifiedName); ok {
+			n.BrowseName = opc.FormatBrowseName(qn)
+		}
+	case ua.AttributeIDDescription:
+		n.Description = localizedText(res.Value.Value())
+	case ua.AttributeIDAccessLevel:
+		var levelValue uint32
+		switch v := res.Value.Value().(type) {
+		case uint8:
+			levelValue = uint32(v)
+		case uint32:
+			levelValue = v
+		}
+		if levelValue > 0 {
+			n.AccessLevel = formatAccessLevel(ua.AccessLevelType(levelValue))
+		}
+	case ua.AttributeIDDataType:
+		if dt, ok := res.Value.Value().(*ua.NodeID); ok {
+			n.DataType = builtinTypeName(dt)
+		}
+	case ua.AttributeIDValue:
+		n.Value = fmt.Sprintf("%v", res.Value.Value())
 	}
-	if attrs.Name == "" {
-		attrs.Name = nodeID
-	}
-	return attrs, nil
 }
 
-func (e *Exporter) writeExcelRow(f *excelize.File, sheetName string, node *ExportNode, level int, row *int) {
-	// Write current node
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", *row), level)
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", *row), node.Name)
-	f.SetCellValue(sheetName, fmt.Sprintf("C%d", *row), node.NodeID)
-	f.SetCellValue(sheetName, fmt.Sprintf("D%d", *row), node.NodeClass)
-	f.SetCellValue(sheetName, fmt.Sprintf("E%d", *row), node.DataType)
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", *row), node.AccessLevel)
-	f.SetCellValue(sheetName, fmt.Sprintf("G%d", *row), node.Description)
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", *row), node.Value)
-	(*row)++
-
-	// Write children
-	for _, child := range node.Children {
-		e.writeExcelRow(f, sheetName, child, level+1, row)
+// localizedText returns the text of a LocalizedText value, which decodes as a pointer.
+func localizedText(v any) string {
+	switch t := v.(type) {
+	case *ua.LocalizedText:
+		if t != nil {
+			return t.Text
+		}
+	case ua.LocalizedText:
+		return t.Text
 	}
+	return ""
 }
 
 func formatAccessLevel(level ua.AccessLevelType) string {
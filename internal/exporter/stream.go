@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ExportRecord is one node of a streamed JSON export: the node without its children, its
+// parent's NodeID (empty for the export root) and its depth below the root.
+type ExportRecord struct {
+	ParentID string `json:"parentId,omitempty"`
+	Level    int    `json:"level"`
+	ExportNode
+}
+
+// ExportToJSONStream exports the address space starting from rootNodeID to a JSON file written
+// while browsing: an array of ExportRecord, one per line, in breadth-first order. Unlike
+// ExportToJSON it never holds the whole tree in memory. If ctx ends or the server fails midway,
+// the array is closed so the file holds the nodes exported so far, and an error reporting the
+// partial export is returned.
+func (e *Exporter) ExportToJSONStream(ctx context.Context, rootNodeID, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	count := 0
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+	walkErr := e.walk(ctx, rootNodeID, make(map[string]struct{}), func(n *ExportNode, parentID string, level int) error {
+		data, err := json.Marshal(ExportRecord{ParentID: parentID, Level: level, ExportNode: *n})
+		if err != nil {
+			return err
+		}
+		sep := ",\n"
+		if count == 0 {
+			sep = "\n"
+		}
+		if _, err := w.WriteString(sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if _, err := w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if walkErr != nil {
+		return fmt.Errorf("export incomplete, %d nodes written: %w", count, walkErr)
+	}
+	return f.Close()
+}
+
+// ParseJSONExport parses an address space export written by ExportToJSON (a tree) or
+// ExportToJSONStream (records with parent references) and returns its root node.
+func ParseJSONExport(data []byte) (*ExportNode, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		var root ExportNode
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, err
+		}
+		if root.NodeID == "" {
+			return nil, errors.New("not an address space export (missing nodeId)")
+		}
+		return &root, nil
+	}
+
+	var records []*ExportRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || records[0].NodeID == "" {
+		return nil, errors.New("not an address space export (no root node)")
+	}
+	root := &records[0].ExportNode
+	byID := map[string]*ExportNode{root.NodeID: root}
+	for _, rec := range records[1:] {
+		n := &rec.ExportNode
+		byID[n.NodeID] = n
+		if parent := byID[rec.ParentID]; parent != nil {
+			parent.Children = append(parent.Children, n)
+		}
+	}
+	return root, nil
+}
@@ -0,0 +1,207 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	excelChartSheet = "Chart"
+	// excelMaxSheetName is Excel's limit on sheet name length.
+	excelMaxSheetName = 31
+	// excelTimeFormat shows trend timestamps with milliseconds.
+	excelTimeFormat = "yyyy-mm-dd hh:mm:ss.000"
+)
+
+// TrendSample is one recorded value of a node.
+type TrendSample struct {
+	Time   time.Time
+	Value  string
+	Status string
+}
+
+// TrendSeries is the recorded values of one node in time order.
+type TrendSeries struct {
+	NodeID   string
+	Name     string
+	DataType string
+	Samples  []TrendSample
+}
+
+// ExportTrendsToExcel writes recorded values to an Excel workbook for reports:
+//   - a Summary sheet listing each node with its sample count and time range,
+//   - one sheet per node with local timestamps, values and status,
+//   - with chart, a Chart sheet plotting every series with numeric values over time.
+//
+// Numeric values are written as numbers and booleans as TRUE/FALSE so they can be charted and
+// filtered; other values are written as text.
+func ExportTrendsToExcel(series []*TrendSeries, filePath string, chart bool) error {
+	if len(series) == 0 {
+		return fmt.Errorf("no recorded values to export")
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName("Sheet1", excelSummarySheet); err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	timeFormat := excelTimeFormat
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat})
+	if err != nil {
+		return err
+	}
+	linkStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}})
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"Timestamp", "Value", "Status"}
+	used := map[string]bool{strings.ToLower(excelSummarySheet): true, strings.ToLower(excelChartSheet): true}
+	sheets := make([]string, len(series))
+	var plotted []excelize.ChartSeries
+	for i, s := range series {
+		sheet := uniqueSheetName(s.Name, s.NodeID, used)
+		sheets[i] = sheet
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+		if err := writeExcelHeader(f, sheet, headers, headerStyle); err != nil {
+			return err
+		}
+		numeric := false
+		for j, sample := range s.Samples {
+			value := trendCellValue(sample.Value)
+			if _, ok := value.(float64); ok {
+				numeric = true
+			}
+			row := []interface{}{excelWallTime(sample.Time), value, sample.Status}
+			cell, _ := excelize.CoordinatesToCellName(1, j+2)
+			if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+				return err
+			}
+		}
+		last := len(s.Samples) + 1
+		if len(s.Samples) > 0 {
+			_ = f.SetCellStyle(sheet, "A2", fmt.Sprintf("A%d", last), timeStyle)
+		}
+		widths := &columnWidths{w: []float64{26, 24, 22}, nameCol: -1}
+		if err := finishDataSheet(f, sheet, len(s.Samples), widths); err != nil {
+			return err
+		}
+		if numeric && len(s.Samples) > 0 {
+			plotted = append(plotted, excelize.ChartSeries{
+				Name:       sheet,
+				Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", sheet, last),
+				Values:     fmt.Sprintf("'%s'!$B$2:$B$%d", sheet, last),
+				Marker:     excelize.ChartMarker{Symbol: "none"},
+			})
+		}
+	}
+
+	// Summary sheet
+	summary := [][]interface{}{
+		{"Exported At", time.Now().Format(time.RFC3339)},
+		{"Nodes", len(series)},
+		{},
+		{"Sheet", "NodeID", "Name", "DataType", "Samples", "First", "Last"},
+	}
+	for i, s := range series {
+		row := []interface{}{sheets[i], s.NodeID, s.Name, s.DataType, len(s.Samples), "", ""}
+		if n := len(s.Samples); n > 0 {
+			row[5], row[6] = excelWallTime(s.Samples[0].Time), excelWallTime(s.Samples[n-1].Time)
+		}
+		summary = append(summary, row)
+	}
+	for i, values := range summary {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(excelSummarySheet, cell, &values); err != nil {
+			return err
+		}
+	}
+	_ = f.SetCellStyle(excelSummarySheet, "A1", "A2", headerStyle)
+	_ = f.SetCellStyle(excelSummarySheet, "A4", "G4", headerStyle)
+	_ = f.SetCellStyle(excelSummarySheet, "F5", fmt.Sprintf("G%d", len(series)+4), timeStyle)
+	for i, sheet := range sheets {
+		cell, _ := excelize.CoordinatesToCellName(1, i+5)
+		if err := f.SetCellHyperLink(excelSummarySheet, cell, fmt.Sprintf("'%s'!A1", sheet), "Location"); err == nil {
+			_ = f.SetCellStyle(excelSummarySheet, cell, cell, linkStyle)
+		}
+	}
+	for col, w := range map[string]float64{"A": 24, "B": 36, "C": 24, "D": 12, "E": 10, "F": 26, "G": 26} {
+		_ = f.SetColWidth(excelSummarySheet, col, col, w)
+	}
+
+	if chart && len(plotted) > 0 {
+		if err := f.AddChartSheet(excelChartSheet, &excelize.Chart{
+			Type:   excelize.Scatter,
+			Series: plotted,
+			Title:  []excelize.RichTextRun{{Text: "Recorded values"}},
+			Legend: excelize.ChartLegend{Position: "bottom"},
+			XAxis:  excelize.ChartAxis{NumFmt: excelize.ChartNumFmt{CustomNumFmt: "hh:mm:ss"}},
+		}); err != nil {
+			return err
+		}
+	}
+	f.SetActiveSheet(0)
+	return f.SaveAs(filePath)
+}
+
+// trendCellValue converts a formatted value to a number or boolean cell where possible.
+func trendCellValue(s string) interface{} {
+	if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return v
+	}
+	if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+		return b
+	}
+	return s
+}
+
+// excelWallTime returns t's local wall clock time in UTC, since Excel dates carry no zone and
+// excelize converts the instant as UTC.
+func excelWallTime(t time.Time) time.Time {
+	l := t.Local()
+	return time.Date(l.Year(), l.Month(), l.Day(), l.Hour(), l.Minute(), l.Second(), l.Nanosecond(), time.UTC)
+}
+
+// uniqueSheetName derives a valid sheet name from the node name (or NodeID), unique among used.
+func uniqueSheetName(name, nodeID string, used map[string]bool) string {
+	if strings.TrimSpace(name) == "" {
+		name = nodeID
+	}
+	base := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.Trim(strings.TrimSpace(name), "'"))
+	if base == "" {
+		base = "Node"
+	}
+	sheet := truncateRunes(base, excelMaxSheetName)
+	for i := 2; used[strings.ToLower(sheet)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		sheet = truncateRunes(base, excelMaxSheetName-len(suffix)) + suffix
+	}
+	used[strings.ToLower(sheet)] = true
+	return sheet
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
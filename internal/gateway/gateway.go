@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"opcuababy/internal/api"
+	"opcuababy/internal/controller"
+)
+
+// Retry pacing of an instance whose endpoint is not reachable at startup.
+const (
+	reconnectMin = 5 * time.Second
+	reconnectMax = 2 * time.Minute
+)
+
+// sink is a value consumer started per instance.
+type sink interface {
+	Publish(item controller.WatchItem)
+	Run(ctx context.Context)
+}
+
+var colorTag = regexp.MustCompile(`\[(?:-|[a-z]+)\]`)
+
+// Run starts every instance of the manifest and keeps it connected until ctx ends. Log lines
+// are passed to logf with the instance name and without UI color tags.
+func Run(ctx context.Context, m *Manifest, logf func(string)) {
+	var wg sync.WaitGroup
+	for i := range m.Instances {
+		in := m.Instances[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runInstance(ctx, &in, logf)
+		}()
+	}
+	wg.Wait()
+}
+
+func runInstance(ctx context.Context, in *Instance, logf func(string)) {
+	log := func(msg string) { logf(in.Name + ": " + colorTag.ReplaceAllString(msg, "")) }
+
+	c := controller.New()
+	c.SetApiStarter(api.StartServer)
+	apiStatus := ""
+	c.SetApiStatus(&apiStatus)
+	go func() {
+		for msg := range c.LogChan {
+			log(msg)
+		}
+	}()
+
+	var sinks []sink
+	for _, s := range in.Sinks {
+		switch s.Type {
+		case SinkMQTT:
+			sinks = append(sinks, newMQTTSink(in.Name, s, log))
+		case SinkInflux:
+			sinks = append(sinks, newInfluxSink(in.Name, s, log))
+		case SinkPostgres:
+			sinks = append(sinks, newPostgresSink(in.Name, s, log))
+		}
+	}
+	var sinkWG sync.WaitGroup
+	sinkCtx, stopSinks := context.WithCancel(context.Background())
+	for _, s := range sinks {
+		sinkWG.Add(1)
+		go func(s sink) {
+			defer sinkWG.Done()
+			s.Run(sinkCtx)
+		}(s)
+	}
+	c.OnValueChange = func(item controller.WatchItem) {
+		for _, s := range sinks {
+			s.Publish(item)
+		}
+	}
+
+	// The client re-establishes a lost session by itself; only the first connect is retried here
+	cfg := in.config()
+	c.UpdateApiServerState(cfg)
+	backoff := reconnectMin
+	for ctx.Err() == nil {
+		if err := c.Connect(cfg); err == nil {
+			break
+		}
+		log(fmt.Sprintf("connect failed, retrying in %s", backoff))
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > reconnectMax {
+			backoff = reconnectMax
+		}
+	}
+	<-ctx.Done()
+
+	c.Shutdown()
+	stopSinks()
+	sinkWG.Wait()
+}
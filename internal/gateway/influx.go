@@ -0,0 +1,205 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"opcuababy/internal/controller"
+)
+
+// influxFlushInterval is how often buffered points are written.
+const influxFlushInterval = time.Second
+
+// maxInfluxBuffer drops the oldest points when InfluxDB is unreachable for long.
+const maxInfluxBuffer = 50000
+
+// influxSink batches value changes as line protocol and writes them to InfluxDB (v2 API with a
+// bucket, v1 /write with a database).
+type influxSink struct {
+	instance string
+	sink     Sink
+	logf     func(string)
+	client   *http.Client
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func newInfluxSink(instance string, s Sink, logf func(string)) *influxSink {
+	if s.Measurement == "" {
+		s.Measurement = "opcua"
+	}
+	return &influxSink{instance: instance, sink: s, logf: logf, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *influxSink) Publish(item controller.WatchItem) {
+	line := influxLine(s.sink.Measurement, s.instance, item, time.Now())
+	if line == "" {
+		return
+	}
+	s.mu.Lock()
+	s.lines = append(s.lines, line)
+	if len(s.lines) > maxInfluxBuffer {
+		s.lines = s.lines[len(s.lines)-maxInfluxBuffer:]
+	}
+	s.mu.Unlock()
+}
+
+func (s *influxSink) Run(ctx context.Context) {
+	t := time.NewTicker(influxFlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush()
+			return
+		case <-t.C:
+			s.flush()
+		}
+	}
+}
+
+func (s *influxSink) flush() {
+	s.mu.Lock()
+	lines := s.lines
+	s.lines = nil
+	s.mu.Unlock()
+	if len(lines) == 0 {
+		return
+	}
+	if err := s.write(strings.Join(lines, "\n")); err != nil {
+		var rejected *influxRejectedError
+		if errors.As(err, &rejected) {
+			// Retrying a batch the server refused would block every later point
+			s.logf(fmt.Sprintf("[red]InfluxDB rejected %d points, dropped: %v[-]", len(lines), err))
+			return
+		}
+		s.logf(fmt.Sprintf("[red]InfluxDB write failed (%d points kept): %v[-]", len(lines), err))
+		s.mu.Lock()
+		s.lines = append(lines, s.lines...)
+		s.mu.Unlock()
+	}
+}
+
+func (s *influxSink) write(body string) error {
+	base := strings.TrimRight(s.sink.URL, "/")
+	q := url.Values{"precision": {"ns"}}
+	var endpoint string
+	if s.sink.Bucket != "" {
+		q.Set("org", s.sink.Org)
+		q.Set("bucket", s.sink.Bucket)
+		endpoint = base + "/api/v2/write?" + q.Encode()
+	} else {
+		q.Set("db", s.sink.Database)
+		endpoint = base + "/write?" + q.Encode()
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.sink.Token != "" {
+		req.Header.Set("Authorization", "Token "+s.sink.Token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return &influxRejectedError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// influxRejectedError is a 4xx answer (other than 429): the batch will not be accepted on retry.
+type influxRejectedError struct{ err error }
+
+func (e *influxRejectedError) Error() string { return e.err.Error() }
+func (e *influxRejectedError) Unwrap() error { return e.err }
+
+// influxLine renders a value as line protocol: numbers and booleans (as 0/1) as the float field
+// "value", so nodes of different types do not conflict in one measurement, everything else as the
+// string field "value_str". Tags are the instance, NodeID and display name.
+func influxLine(measurement, instance string, item controller.WatchItem, now time.Time) string {
+	var field string
+	if f, ok := influxFloat(item.ValueTyped); ok {
+		field = "value=" + strconv.FormatFloat(f, 'g', -1, 64)
+	} else {
+		if item.Value == "" {
+			return ""
+		}
+		field = "value_str=" + influxQuote(item.Value)
+	}
+	ts := now
+	if t, err := time.Parse(time.RFC3339Nano, item.SourceTimestamp); err == nil {
+		ts = t
+	}
+	tags := ",instance=" + influxEscape(instance) + ",node_id=" + influxEscape(item.NodeID)
+	if item.Name != "" {
+		tags += ",name=" + influxEscape(item.Name)
+	}
+	return fmt.Sprintf("%s%s %s,severity=%s %d", influxEscape(measurement), tags, field, influxQuote(item.Severity), ts.UnixNano())
+}
+
+// influxFloat converts numbers and booleans to a float; NaN and ±Inf, which line protocol cannot
+// carry, are not converted.
+func influxFloat(value any) (float64, bool) {
+	var f float64
+	switch v := value.(type) {
+	case bool:
+		if v {
+			f = 1
+		}
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case int:
+		f = float64(v)
+	case int8:
+		f = float64(v)
+	case int16:
+		f = float64(v)
+	case int32:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case uint:
+		f = float64(v)
+	case uint8:
+		f = float64(v)
+	case uint16:
+		f = float64(v)
+	case uint32:
+		f = float64(v)
+	case uint64:
+		f = float64(v)
+	default:
+		return 0, false
+	}
+	return f, !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+var influxEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+func influxEscape(s string) string { return influxEscaper.Replace(s) }
+
+var influxQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func influxQuote(s string) string { return `"` + influxQuoter.Replace(s) + `"` }
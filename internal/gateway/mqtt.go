@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Settings of the publisher. With QoS 1 or 2 the broker session is kept across reconnects, so
+// messages in flight when the connection drops are delivered once it is back.
+const (
+	mqttKeepAlive   = 60 * time.Second
+	mqttDialTimeout = 10 * time.Second
+	mqttMaxBackoff  = time.Minute
+	mqttQueueSize   = 4096
+	mqttQuiesce     = 250 // ms given to in-flight messages on shutdown
+)
+
+type mqttMessage struct {
+	topic   string
+	payload []byte
+}
+
+// mqttSink publishes value changes as watch item JSON to <topic>/<instance>/<NodeID>. Messages
+// are queued while the broker is unreachable and dropped when the queue is full.
+type mqttSink struct {
+	instance string
+	sink     Sink
+	logf     func(string)
+	queue    chan mqttMessage
+}
+
+func newMQTTSink(instance string, s Sink, logf func(string)) *mqttSink {
+	if s.Topic == "" {
+		s.Topic = "opcuababy"
+	}
+	if s.ClientID == "" {
+		s.ClientID = "opcuababy-" + instance
+	}
+	return &mqttSink{instance: instance, sink: s, logf: logf, queue: make(chan mqttMessage, mqttQueueSize)}
+}
+
+func (s *mqttSink) Publish(item controller.WatchItem) {
+	payload, err := json.Marshal(item)
+	if err != nil {
+		return
+	}
+	topic := strings.TrimRight(s.sink.Topic, "/") + "/" + s.instance + "/" + mqttTopicLevel(item.NodeID)
+	select {
+	case s.queue <- mqttMessage{topic: topic, payload: payload}:
+	default:
+	}
+}
+
+// mqttTopicLevel makes a NodeID usable as one topic level ("ns=2;s=A/B" → "ns=2;s=A_B").
+func mqttTopicLevel(nodeID string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(nodeID)
+}
+
+func (s *mqttSink) Run(ctx context.Context) {
+	broker, err := mqttBroker(s.sink.URL)
+	if err != nil {
+		s.logf(fmt.Sprintf("[red]MQTT %s: %v[-]", s.sink.URL, err))
+		return
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(s.sink.ClientID).
+		SetUsername(s.sink.Username).
+		SetPassword(s.sink.Password).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(mqttDialTimeout).
+		SetWriteTimeout(mqttDialTimeout).
+		SetCleanSession(s.sink.QoS == 0).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(mqttMaxBackoff).
+		SetOnConnectHandler(func(mqtt.Client) {
+			s.logf(fmt.Sprintf("[green]MQTT connected to %s[-]", s.sink.URL))
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			s.logf(fmt.Sprintf("[red]MQTT %s: %v, reconnecting[-]", s.sink.URL, err))
+		})
+	client := mqtt.NewClient(opts)
+	if !s.connect(ctx, client) {
+		return
+	}
+	defer client.Disconnect(mqttQuiesce)
+
+	qos := byte(s.sink.QoS)
+	for {
+		// Messages stay in the bounded queue while paho reconnects instead of piling up in its store
+		if !client.IsConnectionOpen() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-s.queue:
+			t := client.Publish(m.topic, qos, s.sink.Retain, m.payload)
+			// Acknowledgements of QoS 1/2 arrive later; only failures known at once are reported
+			select {
+			case <-t.Done():
+				if err := t.Error(); err != nil {
+					s.logf(fmt.Sprintf("[red]MQTT publish to %s failed: %v[-]", m.topic, err))
+				}
+			default:
+			}
+		}
+	}
+}
+
+// connect retries the first connection with backoff until it succeeds or ctx ends; later
+// connection losses are handled by paho's automatic reconnect.
+func (s *mqttSink) connect(ctx context.Context, client mqtt.Client) bool {
+	backoff := time.Second
+	for {
+		t := client.Connect()
+		select {
+		case <-ctx.Done():
+			client.Disconnect(0)
+			return false
+		case <-t.Done():
+		}
+		err := t.Error()
+		if err == nil {
+			return true
+		}
+		s.logf(fmt.Sprintf("[red]MQTT %s: %v, retrying in %s[-]", s.sink.URL, err, backoff))
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > mqttMaxBackoff {
+			backoff = mqttMaxBackoff
+		}
+	}
+}
+
+// mqttBroker adds the default port to a broker URL without one, which paho requires
+// ("tcp://host" → "tcp://host:1883", "ssl://host" → "ssl://host:8883").
+func mqttBroker(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if u.Port() != "" {
+		return raw, nil
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		u.Host += ":1883"
+	case "ssl", "tls", "mqtts", "mqtt+ssl", "tcps":
+		u.Host += ":8883"
+	}
+	return u.String(), nil
+}
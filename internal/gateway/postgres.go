@@ -0,0 +1,230 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// postgresFlushInterval is how often buffered rows are inserted when a batch is not yet full.
+const postgresFlushInterval = time.Second
+
+// maxPostgresBuffer drops the oldest rows when the database is unreachable for long.
+const maxPostgresBuffer = 50000
+
+// postgresTimeout bounds connecting, creating the table and every batch insert.
+const postgresTimeout = 30 * time.Second
+
+var postgresColumns = []string{"time", "instance", "node_id", "name", "value", "value_text", "severity", "status_code"}
+
+// postgresSink inserts value changes in batches into a PostgreSQL (optionally TimescaleDB)
+// table that is created on first connect. Rows are inserted with COPY:
+//
+//	time timestamptz, instance text, node_id text, name text, value double precision,
+//	value_text text, severity text, status_code text
+//
+// Numbers and booleans (as 0/1) are stored in value, every value is also kept in value_text.
+type postgresSink struct {
+	instance string
+	sink     Sink
+	logf     func(string)
+	wake     chan struct{}
+
+	mu   sync.Mutex
+	rows [][]any // postgresColumns values
+}
+
+func newPostgresSink(instance string, s Sink, logf func(string)) *postgresSink {
+	if s.Schema == "" {
+		s.Schema = "public"
+	}
+	if s.Table == "" {
+		s.Table = "opcua_values"
+	}
+	if s.BatchSize <= 0 {
+		s.BatchSize = 500
+	}
+	return &postgresSink{instance: instance, sink: s, logf: logf, wake: make(chan struct{}, 1)}
+}
+
+func (s *postgresSink) Publish(item controller.WatchItem) {
+	row := postgresRow(s.instance, item, time.Now())
+	s.mu.Lock()
+	s.rows = append(s.rows, row)
+	if len(s.rows) > maxPostgresBuffer {
+		s.rows = s.rows[len(s.rows)-maxPostgresBuffer:]
+	}
+	full := len(s.rows) >= s.sink.BatchSize
+	s.mu.Unlock()
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *postgresSink) Run(ctx context.Context) {
+	// Buffered rows are still flushed once ctx ends, so database calls only stop on their timeout
+	dbCtx := context.WithoutCancel(ctx)
+	var conn *pgx.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close(dbCtx)
+		}
+	}()
+	t := time.NewTicker(postgresFlushInterval)
+	defer t.Stop()
+	backoff := time.Second
+	var retryAt time.Time
+	for {
+		stopping := false
+		select {
+		case <-ctx.Done():
+			stopping = true
+		case <-t.C:
+		case <-s.wake:
+		}
+		if conn == nil && time.Now().After(retryAt) {
+			c, err := s.open(dbCtx)
+			if err != nil {
+				s.logf(fmt.Sprintf("[red]PostgreSQL %s: %v, retrying in %s[-]", s.table(), err, backoff))
+				retryAt = time.Now().Add(backoff)
+				if backoff *= 2; backoff > time.Minute {
+					backoff = time.Minute
+				}
+			} else {
+				conn, backoff = c, time.Second
+				s.logf(fmt.Sprintf("[green]PostgreSQL connected, writing to %s[-]", s.table()))
+			}
+		}
+		if conn != nil {
+			if err := s.flush(dbCtx, conn); err != nil {
+				s.logf(fmt.Sprintf("[red]PostgreSQL insert failed: %v[-]", err))
+				conn.Close(dbCtx)
+				conn = nil
+			}
+		}
+		if stopping {
+			return
+		}
+	}
+}
+
+// open connects and creates the schema, table and index (and hypertable) if needed. The URL
+// takes the libpq parameters, including sslmode (default prefer).
+func (s *postgresSink) open(ctx context.Context) (*pgx.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+	conn, err := pgx.Connect(ctx, s.sink.URL)
+	if err != nil {
+		return nil, err
+	}
+	ddl := fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s;
+CREATE TABLE IF NOT EXISTS %s (
+	time timestamptz NOT NULL,
+	instance text NOT NULL,
+	node_id text NOT NULL,
+	name text,
+	value double precision,
+	value_text text,
+	severity text,
+	status_code text
+);
+CREATE INDEX IF NOT EXISTS %s ON %s (node_id, time DESC);`,
+		pgx.Identifier{s.sink.Schema}.Sanitize(), s.table(), pgx.Identifier{s.sink.Table + "_node_time"}.Sanitize(), s.table())
+	if _, err := conn.Exec(ctx, ddl); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	if s.sink.Timescale {
+		if _, err := conn.Exec(ctx, "SELECT create_hypertable($1::text::regclass, 'time', if_not_exists => TRUE)", s.table()); err != nil {
+			conn.Close(ctx)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// flush copies everything buffered in batches of at most BatchSize rows. Rows of a failed batch
+// are put back in front of the buffer.
+func (s *postgresSink) flush(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		s.mu.Lock()
+		n := min(len(s.rows), s.sink.BatchSize)
+		batch := s.rows[:n:n]
+		s.rows = s.rows[n:]
+		s.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		if err := s.copy(ctx, conn, batch); err != nil {
+			s.mu.Lock()
+			s.rows = append(batch, s.rows...)
+			s.mu.Unlock()
+			return err
+		}
+	}
+}
+
+func (s *postgresSink) copy(ctx context.Context, conn *pgx.Conn, batch [][]any) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+	_, err := conn.CopyFrom(ctx, pgx.Identifier{s.sink.Schema, s.sink.Table}, postgresColumns, pgx.CopyFromRows(batch))
+	return err
+}
+
+func (s *postgresSink) table() string {
+	return pgx.Identifier{s.sink.Schema, s.sink.Table}.Sanitize()
+}
+
+// postgresRow converts a value change to the values of postgresColumns.
+func postgresRow(instance string, item controller.WatchItem, now time.Time) []any {
+	var value any
+	switch v := item.ValueTyped.(type) {
+	case bool:
+		value = 0.0
+		if v {
+			value = 1.0
+		}
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case int8:
+		value = float64(v)
+	case int16:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case uint:
+		value = float64(v)
+	case uint8:
+		value = float64(v)
+	case uint16:
+		value = float64(v)
+	case uint32:
+		value = float64(v)
+	case uint64:
+		value = float64(v)
+	}
+	ts := now
+	if t, err := time.Parse(time.RFC3339Nano, item.SourceTimestamp); err == nil {
+		ts = t
+	}
+	return []any{ts, pgText(instance), pgText(item.NodeID), pgText(item.Name), value,
+		pgText(item.Value), pgText(item.Severity), pgText(item.RawCode)}
+}
+
+// pgText drops NUL characters, which text columns cannot hold.
+func pgText(s string) string { return strings.ReplaceAll(s, "\x00", "") }
@@ -0,0 +1,286 @@
+// Package nodeset reads OPC UA NodeSet2 XML information models.
+package nodeset
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// Reference is one reference of a node as declared in the NodeSet.
+type Reference struct {
+	ReferenceType string // NodeID of the reference type, aliases resolved
+	IsForward     bool
+	Target        string
+}
+
+// Node is a node declared in the NodeSet. NodeIDs use the namespace indexes of the file; see
+// NodeSet.Remap to translate them for a server.
+type Node struct {
+	NodeID       string
+	NodeClass    ua.NodeClass
+	BrowseName   string // "ns:Name" as in the file
+	DisplayName  string
+	Description  string
+	DataType     string // NodeID, aliases resolved; Variables and VariableTypes only
+	ValueRank    int32
+	AccessLevel  uint8
+	ParentNodeID string
+	References   []Reference
+}
+
+// NodeSet is a parsed NodeSet2 file.
+type NodeSet struct {
+	// NamespaceURIs are the URIs of the file's namespace indexes 1..n (index 0 is always the
+	// OPC UA namespace and is not listed).
+	NamespaceURIs []string
+	Nodes         []*Node
+	byID          map[string]*Node
+	parent        map[string]string
+}
+
+type xmlLocalized struct {
+	Locale string `xml:"Locale,attr"`
+	Text   string `xml:",chardata"`
+}
+
+type xmlReference struct {
+	ReferenceType string `xml:"ReferenceType,attr"`
+	IsForward     string `xml:"IsForward,attr"`
+	Target        string `xml:",chardata"`
+}
+
+type xmlNode struct {
+	NodeID       string         `xml:"NodeId,attr"`
+	BrowseName   string         `xml:"BrowseName,attr"`
+	ParentNodeID string         `xml:"ParentNodeId,attr"`
+	DataType     string         `xml:"DataType,attr"`
+	ValueRank    string         `xml:"ValueRank,attr"`
+	AccessLevel  string         `xml:"AccessLevel,attr"`
+	DisplayName  []xmlLocalized `xml:"DisplayName"`
+	Description  []xmlLocalized `xml:"Description"`
+	References   []xmlReference `xml:"References>Reference"`
+}
+
+var nodeElements = map[string]ua.NodeClass{
+	"UAObject":        ua.NodeClassObject,
+	"UAVariable":      ua.NodeClassVariable,
+	"UAMethod":        ua.NodeClassMethod,
+	"UAObjectType":    ua.NodeClassObjectType,
+	"UAVariableType":  ua.NodeClassVariableType,
+	"UAReferenceType": ua.NodeClassReferenceType,
+	"UADataType":      ua.NodeClassDataType,
+	"UAView":          ua.NodeClassView,
+}
+
+// hierarchicalReferences are the standard subtypes of HierarchicalReferences used to build the
+// browse tree from a NodeSet.
+var hierarchicalReferences = map[string]bool{
+	"i=33": true, "i=34": true, "i=35": true, "i=36": true, "i=44": true, "i=45": true,
+	"i=46": true, "i=47": true, "i=48": true, "i=49": true,
+}
+
+// ParseFile reads the NodeSet2 file at path.
+func ParseFile(path string) (*NodeSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a NodeSet2 document. Values, extensions and models are ignored.
+func Parse(r io.Reader) (*NodeSet, error) {
+	ns := &NodeSet{byID: make(map[string]*Node), parent: make(map[string]string)}
+	aliases := make(map[string]string)
+	var raw []struct {
+		class ua.NodeClass
+		node  xmlNode
+	}
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch name := se.Name.Local; name {
+		case "NamespaceUris":
+			var v struct {
+				URIs []string `xml:"Uri"`
+			}
+			if err := dec.DecodeElement(&v, &se); err != nil {
+				return nil, err
+			}
+			for _, u := range v.URIs {
+				ns.NamespaceURIs = append(ns.NamespaceURIs, strings.TrimSpace(u))
+			}
+		case "Aliases":
+			var v struct {
+				Aliases []struct {
+					Alias  string `xml:"Alias,attr"`
+					NodeID string `xml:",chardata"`
+				} `xml:"Alias"`
+			}
+			if err := dec.DecodeElement(&v, &se); err != nil {
+				return nil, err
+			}
+			for _, a := range v.Aliases {
+				aliases[a.Alias] = strings.TrimSpace(a.NodeID)
+			}
+		default:
+			class, ok := nodeElements[name]
+			if !ok {
+				continue
+			}
+			var n xmlNode
+			if err := dec.DecodeElement(&n, &se); err != nil {
+				return nil, err
+			}
+			raw = append(raw, struct {
+				class ua.NodeClass
+				node  xmlNode
+			}{class, n})
+		}
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("no nodes found (not a NodeSet2 file?)")
+	}
+
+	resolve := func(s string) string {
+		s = strings.TrimSpace(s)
+		if id, ok := aliases[s]; ok {
+			return id
+		}
+		return s
+	}
+	for _, r := range raw {
+		n := &Node{
+			NodeID:       strings.TrimSpace(r.node.NodeID),
+			NodeClass:    r.class,
+			BrowseName:   r.node.BrowseName,
+			DisplayName:  localizedText(r.node.DisplayName),
+			Description:  localizedText(r.node.Description),
+			ParentNodeID: strings.TrimSpace(r.node.ParentNodeID),
+			ValueRank:    -1,
+		}
+		if n.NodeID == "" {
+			continue
+		}
+		if n.DisplayName == "" {
+			n.DisplayName = n.BrowseName
+			if i := strings.IndexByte(n.DisplayName, ':'); i >= 0 {
+				n.DisplayName = n.DisplayName[i+1:]
+			}
+		}
+		if r.class == ua.NodeClassVariable || r.class == ua.NodeClassVariableType {
+			n.DataType = "i=24" // BaseDataType is the NodeSet default
+			if r.node.DataType != "" {
+				n.DataType = resolve(r.node.DataType)
+			}
+		}
+		if v, err := strconv.ParseInt(r.node.ValueRank, 10, 32); err == nil {
+			n.ValueRank = int32(v)
+		}
+		if v, err := strconv.ParseUint(r.node.AccessLevel, 10, 8); err == nil {
+			n.AccessLevel = uint8(v)
+		} else if r.class == ua.NodeClassVariable {
+			n.AccessLevel = uint8(ua.AccessLevelTypeCurrentRead)
+		}
+		for _, ref := range r.node.References {
+			n.References = append(n.References, Reference{
+				ReferenceType: resolve(ref.ReferenceType),
+				IsForward:     !strings.EqualFold(strings.TrimSpace(ref.IsForward), "false"),
+				Target:        resolve(ref.Target),
+			})
+		}
+		ns.Nodes = append(ns.Nodes, n)
+		ns.byID[n.NodeID] = n
+	}
+	ns.buildParents()
+	return ns, nil
+}
+
+// buildParents records each node's parent in the browse tree: its ParentNodeId, else the source
+// of an inverse hierarchical reference, else a node declaring a forward one to it.
+func (ns *NodeSet) buildParents() {
+	for _, n := range ns.Nodes {
+		if n.ParentNodeID != "" {
+			ns.parent[n.NodeID] = n.ParentNodeID
+			continue
+		}
+		for _, ref := range n.References {
+			if !ref.IsForward && hierarchicalReferences[ref.ReferenceType] {
+				ns.parent[n.NodeID] = ref.Target
+				break
+			}
+		}
+	}
+	for _, p := range ns.Nodes {
+		for _, ref := range p.References {
+			if !ref.IsForward || !hierarchicalReferences[ref.ReferenceType] {
+				continue
+			}
+			if _, ok := ns.parent[ref.Target]; !ok {
+				ns.parent[ref.Target] = p.NodeID
+			}
+		}
+	}
+}
+
+func localizedText(list []xmlLocalized) string {
+	for _, l := range list {
+		if t := strings.TrimSpace(l.Text); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// Node returns the node with the given file NodeID, or nil.
+func (ns *NodeSet) Node(id string) *Node {
+	return ns.byID[id]
+}
+
+// Parent returns the file NodeID of n's parent in the browse tree, or "" for top-level nodes.
+func (ns *NodeSet) Parent(n *Node) string {
+	return ns.parent[n.NodeID]
+}
+
+// Remap translates a NodeID using the file's namespace indexes into one using the indexes of
+// serverNamespaces (the server's NamespaceArray). It fails if the namespace is not present.
+func (ns *NodeSet) Remap(id string, serverNamespaces []string) (string, error) {
+	n, err := ua.ParseNodeID(id)
+	if err != nil {
+		return "", err
+	}
+	idx := n.Namespace()
+	if idx == 0 {
+		return n.String(), nil
+	}
+	if int(idx) > len(ns.NamespaceURIs) {
+		return "", fmt.Errorf("%s: namespace index %d not declared in NodeSet", id, idx)
+	}
+	uri := ns.NamespaceURIs[idx-1]
+	for i, s := range serverNamespaces {
+		if s == uri {
+			n.SetNamespace(uint16(i))
+			return n.String(), nil
+		}
+	}
+	return "", fmt.Errorf("%s: namespace %s not found on server", id, uri)
+}
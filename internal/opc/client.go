@@ -36,12 +36,21 @@ type Client struct {
 	mu               sync.RWMutex
 	Client           *opcua.Client
 	endpoint         string
-	sub              *opcua.Subscription
-	dataChangeChan   chan *opcua.PublishNotificationData
+	groups           map[time.Duration]*rateGroup // subscriptions by rate group, see MonitorItemAt
+	itemGroups       map[string]time.Duration     // rate group of each monitored NodeID
+	events           map[*EventSubscription]struct{}
 	clientHandles    map[uint32]string
 	monitoredItems   map[string]uint32
 	clientHandleSeed uint32
+	publishInterval  time.Duration
+	tuning           SubscriptionTuning
+	reqStats         requestStats
 	Handler          DataChangeHandler
+	shareKey         string // see ShareSession
+	shared           bool   // Client is a registered user of a shared session
+	reconnecting     bool   // connection lost, the stack is reconnecting
+	retryMu          sync.Mutex
+	retry            RetryPolicy // see SetRequestRetry
 }
 
 type Subscription struct {
@@ -54,20 +63,51 @@ func (s *Subscription) Close() error {
 }
 
 func NewClient(endpoint string, opts ...opcua.Option) (*Client, error) {
-	cli, err := opcua.NewClient(endpoint, opts...)
+	states := make(chan opcua.ConnState, 8)
+	cli, err := opcua.NewClient(endpoint, append(opts, opcua.StateChangedCh(states))...)
 	if err != nil {
 		return nil, err
 	}
+	watchState(cli, states)
 	return &Client{
 		Client:         cli,
 		endpoint:       endpoint,
+		groups:         make(map[time.Duration]*rateGroup),
+		itemGroups:     make(map[string]time.Duration),
 		clientHandles:  make(map[uint32]string),
 		monitoredItems: make(map[string]uint32),
 	}, nil
 }
 
+// Endpoint returns the endpoint URL the client was created for.
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
 func (c *Client) Connect(ctx context.Context) error {
-	return c.Client.Connect(ctx)
+	if c.shareKey != "" {
+		if cli := acquireSession(c.shareKey); cli != nil {
+			c.mu.Lock()
+			closeStateFeed(c.Client) // never connected, replaced by the shared session
+			c.Client = cli
+			c.shared = true
+			c.mu.Unlock()
+			followState(cli, c, true)
+			return nil
+		}
+	}
+	start := time.Now()
+	err := c.Client.Connect(ctx)
+	c.traceCall("Connect", start, nil, 0, err)
+	if err == nil {
+		followState(c.Client, c, true)
+		if c.shareKey != "" {
+			c.mu.Lock()
+			c.shared = registerSession(c.shareKey, c.Client)
+			c.mu.Unlock()
+		}
+	}
+	return err
 }
 
 func (c *Client) Disconnect(ctx context.Context) error {
@@ -78,16 +118,28 @@ func (c *Client) Disconnect(ctx context.Context) error {
 		return nil
 	}
 
-	if c.sub != nil {
-		// Cancel the subscription; do not close dataChangeChan here.
-		_ = c.sub.Cancel(context.Background())
+	for _, g := range c.groups {
+		// Cancel the subscription; do not close its notification channel here.
+		_ = g.sub.Cancel(context.Background())
+	}
+	for es := range c.events {
+		_ = c.closeEventsLocked(es)
 	}
 
-	err := c.Client.Close(ctx)
+	var err error
+	followState(c.Client, c, false)
+	if !c.shared || releaseSession(c.shareKey, c.Client) {
+		start := time.Now()
+		err = c.Client.Close(ctx)
+		c.traceCall("Close", start, nil, 0, err)
+		closeStateFeed(c.Client)
+	}
+	c.reconnecting = false
 
 	c.Client = nil
-	c.sub = nil
-	c.dataChangeChan = nil
+	c.shared = false
+	c.groups = make(map[time.Duration]*rateGroup)
+	c.itemGroups = make(map[string]time.Duration)
 	c.clientHandles = make(map[uint32]string)
 	c.monitoredItems = make(map[string]uint32)
 	c.clientHandleSeed = 0
@@ -96,6 +148,14 @@ func (c *Client) Disconnect(ctx context.Context) error {
 }
 
 func (c *Client) MonitorItem(nodeID string) (*Subscription, error) {
+	return c.MonitorItemAt(nodeID, 0)
+}
+
+// MonitorItemAt monitors nodeID in the subscription of the rate group interval, creating that
+// subscription on first use, so that fast and slow items do not share one publishing interval.
+// Items of a rate group are sampled at its interval as well. Interval 0 is the default group,
+// published at the SetPublishInterval rate with the server's default sampling.
+func (c *Client) MonitorItemAt(nodeID string, interval time.Duration) (*Subscription, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -106,35 +166,36 @@ func (c *Client) MonitorItem(nodeID string) (*Subscription, error) {
 		return nil, fmt.Errorf("nodeID %s is already monitored", nodeID)
 	}
 
-	if c.sub == nil {
-		c.dataChangeChan = make(chan *opcua.PublishNotificationData, 100)
-		sub, err := c.Client.Subscribe(context.Background(), &opcua.SubscriptionParameters{
-			Interval: 1000 * time.Millisecond,
-		}, c.dataChangeChan)
-		if err != nil {
-			return nil, err
-		}
-		c.sub = sub
-		go c.handleDataChanges()
-	}
-
 	id, err := ua.ParseNodeID(nodeID)
 	if err != nil {
 		return nil, err
 	}
+	g, err := c.rateGroupLocked(interval)
+	if err != nil {
+		return nil, err
+	}
 
 	handle := atomic.AddUint32(&c.clientHandleSeed, 1)
 	req := opcua.NewMonitoredItemCreateRequestWithDefaults(id, ua.AttributeIDValue, handle)
-	res, err := c.sub.Monitor(context.Background(), ua.TimestampsToReturnBoth, req)
+	if interval > 0 {
+		req.RequestedParameters.SamplingInterval = float64(interval) / float64(time.Millisecond)
+	}
+	start := time.Now()
+	res, err := g.sub.Monitor(context.Background(), ua.TimestampsToReturnBoth, req)
+	c.traceCall("CreateMonitoredItems", start, responseHeader(res), 1, err)
+	c.reqStats.bytes("CreateMonitoredItems", req, res)
+	if err == nil && res.Results[0].StatusCode != ua.StatusOK {
+		err = fmt.Errorf("failed to monitor item: %w", res.Results[0].StatusCode)
+	}
 	if err != nil {
+		c.dropRateGroupLocked(g)
 		return nil, err
 	}
-	if res.Results[0].StatusCode != ua.StatusOK {
-		return nil, fmt.Errorf("failed to monitor item: %s", res.Results[0].StatusCode)
-	}
 
+	g.items++
 	c.clientHandles[handle] = nodeID
 	c.monitoredItems[nodeID] = handle
+	c.itemGroups[nodeID] = interval
 
 	return &Subscription{nodeID: nodeID, parentClient: c}, nil
 }
@@ -168,6 +229,28 @@ func (c *Client) WriteValue(ctx context.Context, nodeID string, value interface{
 		}
 	}
 
+	return c.writeAttribute(ctx, cli, id, ua.AttributeIDValue, value)
+}
+
+// WriteAttribute writes an arbitrary attribute (DisplayName, Description, ...) of a node.
+// The server decides whether the attribute is writable based on its WriteMask.
+func (c *Client) WriteAttribute(ctx context.Context, nodeID string, attrID ua.AttributeID, value interface{}) error {
+	c.mu.RLock()
+	if c.Client == nil {
+		c.mu.RUnlock()
+		return errors.New("opc ua client is not connected")
+	}
+	cli := c.Client
+	c.mu.RUnlock()
+
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return fmt.Errorf("invalid node id: %w", err)
+	}
+	return c.writeAttribute(ctx, cli, id, attrID, value)
+}
+
+func (c *Client) writeAttribute(ctx context.Context, cli *opcua.Client, id *ua.NodeID, attrID ua.AttributeID, value interface{}) error {
 	v, err := ua.NewVariant(value)
 	if err != nil {
 		return fmt.Errorf("failed to create variant: %w", err)
@@ -177,7 +260,7 @@ func (c *Client) WriteValue(ctx context.Context, nodeID string, value interface{
 		NodesToWrite: []*ua.WriteValue{
 			{
 				NodeID:      id,
-				AttributeID: ua.AttributeIDValue,
+				AttributeID: attrID,
 				Value: &ua.DataValue{
 					EncodingMask: ua.DataValueValue,
 					Value:        v,
@@ -186,7 +269,14 @@ func (c *Client) WriteValue(ctx context.Context, nodeID string, value interface{
 		},
 	}
 
-	resp, err := cli.Write(ctx, req)
+	var resp *ua.WriteResponse
+	err = c.withRetry(ctx, false, func() error {
+		start := time.Now()
+		resp, err = cli.Write(ctx, req)
+		c.traceCall("Write", start, responseHeader(resp), 1, err)
+		c.reqStats.bytes("Write", req, resp)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -198,6 +288,51 @@ func (c *Client) WriteValue(ctx context.Context, nodeID string, value interface{
 	return nil
 }
 
+// WriteValues writes the Value attribute of several nodes in a single Write request and
+// returns the per-node status codes in input order.
+func (c *Client) WriteValues(ctx context.Context, nodeIDs []*ua.NodeID, values []interface{}) ([]ua.StatusCode, error) {
+	if len(nodeIDs) != len(values) {
+		return nil, errors.New("node ids and values must have the same length")
+	}
+	c.mu.RLock()
+	if c.Client == nil {
+		c.mu.RUnlock()
+		return nil, errors.New("opc ua client is not connected")
+	}
+	cli := c.Client
+	c.mu.RUnlock()
+
+	nodesToWrite := make([]*ua.WriteValue, len(nodeIDs))
+	for i, id := range nodeIDs {
+		v, err := ua.NewVariant(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create variant for %s: %w", id, err)
+		}
+		nodesToWrite[i] = &ua.WriteValue{
+			NodeID:      id,
+			AttributeID: ua.AttributeIDValue,
+			Value:       &ua.DataValue{EncodingMask: ua.DataValueValue, Value: v},
+		}
+	}
+	req := &ua.WriteRequest{NodesToWrite: nodesToWrite}
+	var resp *ua.WriteResponse
+	err := c.withRetry(ctx, false, func() error {
+		start := time.Now()
+		var err error
+		resp, err = cli.Write(ctx, req)
+		c.traceCall("Write", start, responseHeader(resp), len(nodesToWrite), err)
+		c.reqStats.bytes("Write", req, resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(nodesToWrite) {
+		return nil, fmt.Errorf("server returned %d results for %d writes", len(resp.Results), len(nodesToWrite))
+	}
+	return resp.Results, nil
+}
+
 ////
 func (c *Client) ReadAttributes(ctx context.Context, nodeID string, attributeIDs ...ua.AttributeID) ([]*ua.DataValue, error) {
 	c.mu.RLock()
@@ -217,15 +352,114 @@ func (c *Client) ReadAttributes(ctx context.Context, nodeID string, attributeIDs
 		nodesToRead[i] = &ua.ReadValueID{NodeID: id, AttributeID: attrID}
 	}
 
-	req := &ua.ReadRequest{NodesToRead: nodesToRead}
-	resp, err := c.Client.Read(ctx, req)
+	req := &ua.ReadRequest{NodesToRead: nodesToRead, TimestampsToReturn: ua.TimestampsToReturnBoth}
+	var resp *ua.ReadResponse
+	err = c.withRetry(ctx, true, func() error {
+		start := time.Now()
+		resp, err = c.Client.Read(ctx, req)
+		c.traceCall("Read", start, responseHeader(resp), len(nodesToRead), err)
+		c.reqStats.bytes("Read", req, resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// ReadMany reads the given attributes of many nodes in one Read request. Results are ordered by
+// node, then attribute: result i*len(attributeIDs)+j belongs to nodeIDs[i] and attributeIDs[j].
+func (c *Client) ReadMany(ctx context.Context, nodeIDs []*ua.NodeID, attributeIDs ...ua.AttributeID) ([]*ua.DataValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+
+	nodesToRead := make([]*ua.ReadValueID, 0, len(nodeIDs)*len(attributeIDs))
+	for _, id := range nodeIDs {
+		for _, attrID := range attributeIDs {
+			nodesToRead = append(nodesToRead, &ua.ReadValueID{NodeID: id, AttributeID: attrID})
+		}
+	}
+
+	req := &ua.ReadRequest{NodesToRead: nodesToRead, TimestampsToReturn: ua.TimestampsToReturnNeither}
+	var resp *ua.ReadResponse
+	err := c.withRetry(ctx, true, func() error {
+		start := time.Now()
+		var err error
+		resp, err = c.Client.Read(ctx, req)
+		c.traceCall("Read", start, responseHeader(resp), len(nodesToRead), err)
+		c.reqStats.bytes("Read", req, resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(nodesToRead) {
+		return nil, fmt.Errorf("read returned %d results for %d items", len(resp.Results), len(nodesToRead))
+	}
+	return resp.Results, nil
+}
+
+// ReadValues reads the Value attribute of many nodes in one Read request, with source and server
+// timestamps, for polling. Result i belongs to nodeIDs[i].
+func (c *Client) ReadValues(ctx context.Context, nodeIDs []*ua.NodeID) ([]*ua.DataValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+
+	nodesToRead := make([]*ua.ReadValueID, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		nodesToRead = append(nodesToRead, &ua.ReadValueID{NodeID: id, AttributeID: ua.AttributeIDValue})
+	}
+	req := &ua.ReadRequest{NodesToRead: nodesToRead, TimestampsToReturn: ua.TimestampsToReturnBoth}
+	var resp *ua.ReadResponse
+	err := c.withRetry(ctx, true, func() error {
+		start := time.Now()
+		var err error
+		resp, err = c.Client.Read(ctx, req)
+		c.traceCall("Read", start, responseHeader(resp), len(nodesToRead), err)
+		c.reqStats.bytes("Read", req, resp)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	if len(resp.Results) != len(nodesToRead) {
+		return nil, fmt.Errorf("read returned %d results for %d items", len(resp.Results), len(nodesToRead))
+	}
 	return resp.Results, nil
 }
 
+// NamespaceArray reads the server's NamespaceArray; index i is the URI of namespace i.
+func (c *Client) NamespaceArray(ctx context.Context) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+	start := time.Now()
+	ns, err := c.Client.NamespaceArray(ctx)
+	c.traceCall("Read", start, nil, 1, err)
+	return ns, err
+}
+
 func (c *Client) Browse(ctx context.Context, nodeID *ua.NodeID) ([]*ua.ReferenceDescription, error) {
+	return c.browse(ctx, nodeID, ua.BrowseDirectionForward, ua.NewNumericNodeID(0, 33)) // HierarchicalReferences
+}
+
+// BrowseReferences returns all forward and inverse references of nodeID.
+func (c *Client) BrowseReferences(ctx context.Context, nodeID *ua.NodeID) ([]*ua.ReferenceDescription, error) {
+	return c.browse(ctx, nodeID, ua.BrowseDirectionBoth, ua.NewNumericNodeID(0, 31)) // References
+}
+
+func (c *Client) browse(ctx context.Context, nodeID *ua.NodeID, dir ua.BrowseDirection, refType *ua.NodeID) ([]*ua.ReferenceDescription, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -237,8 +471,8 @@ func (c *Client) Browse(ctx context.Context, nodeID *ua.NodeID) ([]*ua.Reference
 		NodesToBrowse: []*ua.BrowseDescription{
 			{
 				NodeID:          nodeID,
-				BrowseDirection: ua.BrowseDirectionForward,
-				ReferenceTypeID: ua.NewNumericNodeID(0, 33), // HierarchicalReferences
+				BrowseDirection: dir,
+				ReferenceTypeID: refType,
 				IncludeSubtypes: true,
 				NodeClassMask:   uint32(ua.NodeClassAll),
 				ResultMask:      uint32(ua.BrowseResultMaskAll),
@@ -247,7 +481,15 @@ func (c *Client) Browse(ctx context.Context, nodeID *ua.NodeID) ([]*ua.Reference
 		RequestedMaxReferencesPerNode: 1000,
 	}
 
-	resp, err := c.Client.Browse(ctx, req)
+	var resp *ua.BrowseResponse
+	err := c.withRetry(ctx, true, func() error {
+		start := time.Now()
+		var err error
+		resp, err = c.Client.Browse(ctx, req)
+		c.traceCall("Browse", start, responseHeader(resp), 1, err)
+		c.reqStats.bytes("Browse", req, resp)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -258,12 +500,14 @@ func (c *Client) Browse(ctx context.Context, nodeID *ua.NodeID) ([]*ua.Reference
 	return nil, nil
 }
 
-func (c *Client) handleDataChanges() {
-	for ntf := range c.dataChangeChan {
+func (c *Client) handleDataChanges(g *rateGroup) {
+	for ntf := range g.ch {
 		if ntf == nil {
 			continue
 		}
 		if ntf.Error != nil {
+			g.stats.errors.Add(1)
+			c.reqStats.failed(ntf.Error)
 			fmt.Printf("Subscription error: %v\n", ntf.Error)
 			continue
 		}
@@ -271,6 +515,8 @@ func (c *Client) handleDataChanges() {
 		if !ok || dcn == nil {
 			continue
 		}
+		g.stats.received(len(dcn.MonitoredItems))
+		c.reqStats.notification(dcn)
 		for _, item := range dcn.MonitoredItems {
 			if item == nil || item.Value == nil {
 				continue
@@ -311,18 +557,17 @@ func (c *Client) UnmonitorItem(nodeID string) error {
         return fmt.Errorf("nodeID %s is not monitored", nodeID)
     }
 
-    if c.sub != nil {
-        _, _ = c.sub.Unmonitor(context.Background(), handle)
+    if g := c.groups[c.itemGroups[nodeID]]; g != nil {
+        start := time.Now()
+        res, err := g.sub.Unmonitor(context.Background(), handle)
+        c.traceCall("DeleteMonitoredItems", start, responseHeader(res), 1, err)
+        g.items--
+        c.dropRateGroupLocked(g)
     }
 
     delete(c.monitoredItems, nodeID)
     delete(c.clientHandles, handle)
-
-    if len(c.monitoredItems) == 0 && c.sub != nil {
-        _ = c.sub.Cancel(context.Background())
-        c.sub = nil
-        c.dataChangeChan = nil
-    }
+    delete(c.itemGroups, nodeID)
 
     return nil
-}
\ No newline at end of file
+}
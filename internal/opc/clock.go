@@ -0,0 +1,82 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// DefaultClockSkewWarn is the clock offset above which a warning is logged when
+// Config.ClockSkewWarnSeconds is zero.
+const DefaultClockSkewWarn = 2 * time.Second
+
+// ClockOffset compares the server's Server/ServerStatus/CurrentTime with the local clock. The
+// offset is positive when the server clock is ahead; half the round trip of the read is taken
+// as the time the value was sampled, so the offset is exact to within RoundTripMs/2.
+type ClockOffset struct {
+	OffsetMs    float64   `json:"offset_ms"`
+	RoundTripMs float64   `json:"round_trip_ms"`
+	ServerTime  time.Time `json:"server_time"`
+	CheckedAt   time.Time `json:"checked_at"`
+	Skewed      bool      `json:"skewed"` // |OffsetMs| exceeds the warning threshold
+}
+
+// Offset returns OffsetMs as a Duration.
+func (o *ClockOffset) Offset() time.Duration {
+	return time.Duration(o.OffsetMs * float64(time.Millisecond))
+}
+
+// ClockSkewWarn returns the clock offset above which a warning is logged. It is safe to call on
+// a nil Config.
+func (c *Config) ClockSkewWarn() time.Duration {
+	if c == nil || c.ClockSkewWarnSeconds <= 0 {
+		return DefaultClockSkewWarn
+	}
+	return time.Duration(c.ClockSkewWarnSeconds * float64(time.Second))
+}
+
+// ReadClockOffset reads the server's CurrentTime and returns its offset from the local clock.
+func (c *Client) ReadClockOffset(ctx context.Context) (*ClockOffset, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+	req := &ua.ReadRequest{
+		NodesToRead:        []*ua.ReadValueID{{NodeID: ua.NewNumericNodeID(0, id.Server_ServerStatus_CurrentTime), AttributeID: ua.AttributeIDValue}},
+		TimestampsToReturn: ua.TimestampsToReturnNeither,
+	}
+	start := time.Now()
+	resp, err := c.Client.Read(ctx, req)
+	end := time.Now()
+	c.traceCall("Read", start, responseHeader(resp), 1, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 || resp.Results[0] == nil {
+		return nil, errors.New("empty read response")
+	}
+	if res := resp.Results[0]; res.Status != ua.StatusOK {
+		return nil, fmt.Errorf("read CurrentTime: %w", res.Status)
+	}
+	var server time.Time
+	if v := resp.Results[0].Value; v != nil {
+		server, _ = v.Value().(time.Time)
+	}
+	if server.IsZero() {
+		return nil, errors.New("server returned no CurrentTime")
+	}
+	rtt := end.Sub(start)
+	sampled := start.Add(rtt / 2)
+	return &ClockOffset{
+		OffsetMs:    float64(server.Sub(sampled)) / float64(time.Millisecond),
+		RoundTripMs: float64(rtt) / float64(time.Millisecond),
+		ServerTime:  server,
+		CheckedAt:   end,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// CallMethod calls methodID on objectID with args and returns the output arguments. Method
+// calls change server state, so they are not retried.
+func (c *Client) CallMethod(ctx context.Context, objectID, methodID *ua.NodeID, args ...interface{}) ([]*ua.Variant, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+	in := make([]*ua.Variant, len(args))
+	for i, a := range args {
+		v, err := ua.NewVariant(a)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		in[i] = v
+	}
+	req := &ua.CallRequest{MethodsToCall: []*ua.CallMethodRequest{{
+		ObjectID:       objectID,
+		MethodID:       methodID,
+		InputArguments: in,
+	}}}
+	var resp *ua.CallResponse
+	start := time.Now()
+	err := c.Client.Send(ctx, req, func(v ua.Response) error {
+		r, ok := v.(*ua.CallResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response type %T", v)
+		}
+		resp = r
+		return nil
+	})
+	c.traceCall("Call", start, responseHeader(resp), 1, err)
+	c.reqStats.bytes("Call", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 || resp.Results[0] == nil {
+		return nil, errors.New("empty call response")
+	}
+	res := resp.Results[0]
+	if res.StatusCode != ua.StatusOK {
+		for i, sc := range res.InputArgumentResults {
+			if sc != ua.StatusOK {
+				return nil, fmt.Errorf("argument %d: %w", i, sc)
+			}
+		}
+		return nil, res.StatusCode
+	}
+	return res.OutputArguments, nil
+}
+
+// ConditionEvent is a state notification of an alarm or condition (Part 9).
+type ConditionEvent struct {
+	EventID       []byte
+	EventType     string
+	ConditionID   string
+	ConditionName string
+	SourceNode    string
+	SourceName    string
+	Time          time.Time
+	Message       string
+	Severity      uint16
+	Comment       string
+	Retain        bool
+	Enabled       bool
+	Active        bool
+	Acked         bool
+	Confirmed     bool
+	// RefreshEnd marks the end of the events sent for a ConditionRefresh; it carries no state
+	RefreshEnd bool
+}
+
+// conditionFields are the select clauses of MonitorConditions, decoded by decodeCondition.
+var conditionFields = []*ua.SimpleAttributeOperand{
+	SelectField(id.BaseEventType, "EventId"),
+	SelectField(id.BaseEventType, "EventType"),
+	SelectField(id.BaseEventType, "SourceNode"),
+	SelectField(id.BaseEventType, "SourceName"),
+	SelectField(id.BaseEventType, "Time"),
+	SelectField(id.BaseEventType, "Message"),
+	SelectField(id.BaseEventType, "Severity"),
+	// The ConditionId is the NodeId of the condition object itself
+	{TypeDefinitionID: ua.NewNumericNodeID(0, id.ConditionType), AttributeID: ua.AttributeIDNodeID},
+	SelectField(id.ConditionType, "ConditionName"),
+	SelectField(id.ConditionType, "Retain"),
+	SelectField(id.ConditionType, "Comment"),
+	SelectField(id.ConditionType, "EnabledState", "Id"),
+	SelectField(id.AlarmConditionType, "ActiveState", "Id"),
+	SelectField(id.AcknowledgeableConditionType, "AckedState", "Id"),
+	SelectField(id.AcknowledgeableConditionType, "ConfirmedState", "Id"),
+}
+
+// MonitorConditions subscribes to the condition events of the server, including the end marker
+// of a ConditionRefresh, and calls handle for each of them.
+func (c *Client) MonitorConditions(handle func(ev ConditionEvent)) (*EventSubscription, error) {
+	ofType := func(typeID uint32) *ua.ContentFilterElement {
+		return &ua.ContentFilterElement{
+			FilterOperator: ua.FilterOperatorOfType,
+			FilterOperands: []*ua.ExtensionObject{ua.NewExtensionObject(&ua.LiteralOperand{
+				Value: ua.MustVariant(ua.NewNumericNodeID(0, typeID)),
+			})},
+		}
+	}
+	filter := &ua.EventFilter{
+		SelectClauses: conditionFields,
+		WhereClause: &ua.ContentFilter{Elements: []*ua.ContentFilterElement{
+			{
+				FilterOperator: ua.FilterOperatorOr,
+				FilterOperands: []*ua.ExtensionObject{
+					ua.NewExtensionObject(&ua.ElementOperand{Index: 1}),
+					ua.NewExtensionObject(&ua.ElementOperand{Index: 2}),
+				},
+			},
+			ofType(id.ConditionType),
+			ofType(id.RefreshEndEventType),
+		}},
+	}
+	return c.MonitorEvents(ua.NewNumericNodeID(0, id.Server), filter, func(fields []*ua.Variant) {
+		handle(decodeCondition(fields))
+	})
+}
+
+func decodeCondition(fields []*ua.Variant) ConditionEvent {
+	var ev ConditionEvent
+	field := func(i int) interface{} {
+		if i < len(fields) && fields[i] != nil {
+			return fields[i].Value()
+		}
+		return nil
+	}
+	nodeID := func(i int) string {
+		if n, ok := field(i).(*ua.NodeID); ok && n != nil {
+			return n.String()
+		}
+		return ""
+	}
+	text := func(i int) string {
+		switch v := field(i).(type) {
+		case *ua.LocalizedText:
+			if v != nil {
+				return v.Text
+			}
+		case string:
+			return v
+		}
+		return ""
+	}
+	flag := func(i int) bool {
+		b, _ := field(i).(bool)
+		return b
+	}
+	ev.EventID, _ = field(0).([]byte)
+	ev.EventType = nodeID(1)
+	ev.RefreshEnd = ev.EventType == ua.NewNumericNodeID(0, id.RefreshEndEventType).String()
+	ev.SourceNode = nodeID(2)
+	ev.SourceName = text(3)
+	ev.Time, _ = field(4).(time.Time)
+	ev.Message = text(5)
+	ev.Severity, _ = field(6).(uint16)
+	ev.ConditionID = nodeID(7)
+	ev.ConditionName = text(8)
+	ev.Retain = flag(9)
+	ev.Comment = text(10)
+	ev.Enabled = flag(11)
+	ev.Active = flag(12)
+	ev.Acked = flag(13)
+	ev.Confirmed = flag(14)
+	return ev
+}
+
+// RefreshConditions asks the server to send the current state of all retained conditions to
+// the subscription of es, followed by a RefreshEndEvent.
+func (c *Client) RefreshConditions(ctx context.Context, es *EventSubscription) error {
+	_, err := c.CallMethod(ctx,
+		ua.NewNumericNodeID(0, id.ConditionType),
+		ua.NewNumericNodeID(0, id.ConditionType_ConditionRefresh),
+		es.sub.SubscriptionID)
+	return err
+}
+
+// AcknowledgeCondition acknowledges the state of conditionID that eventID reported.
+func (c *Client) AcknowledgeCondition(ctx context.Context, conditionID *ua.NodeID, eventID []byte, comment *ua.LocalizedText) error {
+	_, err := c.CallMethod(ctx, conditionID, ua.NewNumericNodeID(0, id.AcknowledgeableConditionType_Acknowledge), eventID, comment)
+	return err
+}
+
+// ConfirmCondition confirms the state of conditionID that eventID reported.
+func (c *Client) ConfirmCondition(ctx context.Context, conditionID *ua.NodeID, eventID []byte, comment *ua.LocalizedText) error {
+	_, err := c.CallMethod(ctx, conditionID, ua.NewNumericNodeID(0, id.AcknowledgeableConditionType_Confirm), eventID, comment)
+	return err
+}
+
+// CommentCondition adds a comment to the state of conditionID that eventID reported.
+func (c *Client) CommentCondition(ctx context.Context, conditionID *ua.NodeID, eventID []byte, comment *ua.LocalizedText) error {
+	_, err := c.CallMethod(ctx, conditionID, ua.NewNumericNodeID(0, id.ConditionType_AddComment), eventID, comment)
+	return err
+}
@@ -21,6 +21,10 @@ type Config struct {
 	AuthMode         string // "Anonymous", "Username", "Certificate"
 	Username         string
 	Password         string
+	// HostOverride (IP or host[:port]) is dialed instead of the host of EndpointURL, for servers
+	// whose endpoint URLs name hosts that are not reachable from here. Discovery and the security
+	// settings are unaffected.
+	HostOverride string `json:"host_override,omitempty"`
 	// UserTokenPolicyID allows explicitly specifying the server's UserIdentityToken PolicyID
 	// (e.g., "anonymous", "username"). Some servers require the exact PolicyID; if not
 	// provided and no endpoint probing is performed, authentication may fail with
@@ -36,16 +40,218 @@ type Config struct {
 	SessionTimeout   uint32 `json:"session_timeout,omitempty"` // in seconds
 	ApiPort          string
 	ApiEnabled       bool    // Enable/disable the API/web server
+	KeepApiRunning   bool    `json:"keep_api_running"` // Keep the API/web server up while the OPC UA session is disconnected (default on)
 	DisableLog       bool    // When true, suppress UI/API logs
 	AutoConnect      bool    // Automatically connect on startup
 	ConnectTimeout   float64 `json:"connect_timeout,omitempty"`    // Connection timeout in seconds
 	// RetryAttempts controls how many times to try establishing a connection.
 	// 0 or 1 means single attempt (no retries). If omitted/zero, controller will default to 3.
 	RetryAttempts    int     `json:"retry_attempts,omitempty"`
-	// RetryDelaySeconds is the delay between attempts. If omitted/zero, controller will default to 1s.
+	// RetryDelaySeconds is the delay before the first retry, doubled for each further one (with
+	// jitter, see RetryPolicy). If omitted/zero, controller will default to 1s.
 	RetryDelaySeconds float64 `json:"retry_delay_seconds,omitempty"`
+	// RetryMaxDelaySeconds caps the exponential backoff between retries; zero uses 30 s.
+	RetryMaxDelaySeconds float64 `json:"retry_max_delay_seconds,omitempty"`
+	// RetryMaxElapsedSeconds stops retrying once an operation has been failing for this long;
+	// zero limits retries by the attempt counts only.
+	RetryMaxElapsedSeconds float64 `json:"retry_max_elapsed_seconds,omitempty"`
+	// ReconnectDelaySeconds is the countdown shown after the connection dropped unexpectedly,
+	// before the session is opened again; zero uses DefaultReconnectDelay.
+	ReconnectDelaySeconds float64 `json:"reconnect_delay_seconds,omitempty"`
+	// RequestRetryAttempts, when above 1, retries Read, Browse and Write requests the server
+	// rejected as overloaded (BadTooManyOperations, ...) and Reads and Browses that timed out.
+	RequestRetryAttempts int `json:"request_retry_attempts,omitempty"`
+	// RequestRetryDelaySeconds is the wait before the first request retry; zero uses 0.2 s.
+	RequestRetryDelaySeconds float64 `json:"request_retry_delay_seconds,omitempty"`
 	Language         string  `json:"language,omitempty"`           // UI language code: "en", "zh"
 	AutoGenerateCert bool    `json:"auto_generate_cert,omitempty"` // Automatically generate certificates if missing
+	// TimestampSource selects which timestamp is shown in the watch list: "source" (default), "server" or "client".
+	TimestampSource string `json:"timestamp_source,omitempty"`
+	// TimestampTimezone controls how timestamps are rendered: "local" (default), "utc" or an IANA zone name.
+	TimestampTimezone string `json:"timestamp_timezone,omitempty"`
+	// ClockSkewWarnSeconds is the offset between server and local clock above which a warning is
+	// logged; zero uses DefaultClockSkewWarn. The offset is checked on connect and every
+	// ten minutes.
+	ClockSkewWarnSeconds float64 `json:"clock_skew_warn_seconds,omitempty"`
+	// FormatLocale (e.g. "de-DE") sets the decimal separator and date order of values in the watch
+	// list, history table and CSV/Excel exports; empty keeps the machine format. API payloads and
+	// JSON exports always carry the raw values.
+	FormatLocale string `json:"format_locale,omitempty"`
+	// LocaleIDs (e.g. "de-DE", "en") are sent in ActivateSession, in order of preference, so that
+	// servers localizing their address space return DisplayNames and Descriptions in that
+	// language. They belong to the connection, independent of Language; empty uses "en-us".
+	LocaleIDs []string `json:"locale_ids,omitempty"`
+	// ProtocolTrace enables writing one line per OPC UA service call to ProtocolTraceFile.
+	ProtocolTrace bool `json:"protocol_trace,omitempty"`
+	// ProtocolTraceFile is the trace file path; empty uses opcuababy-trace.log in the temp directory.
+	ProtocolTraceFile string `json:"protocol_trace_file,omitempty"`
+	// Per-operation request timeouts in seconds; zero uses the defaults (see Timeouts).
+	ReadTimeout    float64 `json:"read_timeout,omitempty"`
+	BrowseTimeout  float64 `json:"browse_timeout,omitempty"`
+	WriteTimeout   float64 `json:"write_timeout,omitempty"`
+	PublishTimeout float64 `json:"publish_timeout,omitempty"`
+	ExportTimeout  float64 `json:"export_timeout,omitempty"`
+	// StrictAccessLevel refuses writes to nodes whose AccessLevel could not be read,
+	// instead of letting the server decide.
+	StrictAccessLevel bool `json:"strict_access_level,omitempty"`
+	// WriteAllow and WriteDeny restrict which nodes may be written at all (UI, API, scheduled and
+	// bulk writes). Entries are exact NodeIDs, prefixes ending in "*" or "re:<regexp>"; deny wins,
+	// and a non-empty allow list permits only matching nodes.
+	WriteAllow []string `json:"write_allow,omitempty"`
+	WriteDeny  []string `json:"write_deny,omitempty"`
+	// WriteQueue buffers writes issued while disconnected or reconnecting and replays them in
+	// order once a session to the same endpoint is up.
+	WriteQueue bool `json:"write_queue,omitempty"`
+	// PulseMs is how long a Pulse of a Boolean node holds it true before writing false; zero
+	// uses 500 ms.
+	PulseMs float64 `json:"pulse_ms,omitempty"`
+	// PublishIntervalMs is the requested publishing interval of the watch subscription; zero uses 1000 ms.
+	PublishIntervalMs float64 `json:"publish_interval_ms,omitempty"`
+	// WatchPumpIntervalMs is how often the watch list is redrawn; zero uses 33 ms.
+	WatchPumpIntervalMs float64 `json:"watch_pump_interval_ms,omitempty"`
+	// WatchValueMaxLen is the number of characters of a value shown in the watch list before it is
+	// cut off with an ellipsis; zero uses DefaultWatchValueMaxLen. The full value viewer shows all
+	// of it.
+	WatchValueMaxLen int `json:"watch_value_max_len,omitempty"`
+	// PollMode decides which watch items are polled instead of monitored (PollAuto, PollNever or
+	// PollAlways); PolledNodes are items always polled, referenced like WatchList entries.
+	PollMode    string   `json:"poll_mode,omitempty"`
+	PolledNodes []string `json:"polled_nodes,omitempty"`
+	// PollIntervalMs is how often polled watch items are read; zero uses 1000 ms.
+	PollIntervalMs float64 `json:"poll_interval_ms,omitempty"`
+	// WatchRates are the sampling intervals (ms) requested for watch items, keyed like WatchList
+	// entries. Items of one rate share a subscription publishing at that rate; items without one
+	// share the PublishIntervalMs subscription.
+	WatchRates map[string]float64 `json:"watch_rates,omitempty"`
+	// DedupNodes are watch items, referenced like WatchList entries, whose samples are not passed
+	// on to the API and the gateway sinks when only their timestamps changed.
+	DedupNodes []string `json:"dedup_nodes,omitempty"`
+	// Requested lifetime and max keep-alive counts (in publishing intervals) and priority of the
+	// watch subscription; zero uses the defaults 10000, 3000 and 0.
+	SubscriptionLifetimeCount     uint32 `json:"subscription_lifetime_count,omitempty"`
+	SubscriptionMaxKeepAliveCount uint32 `json:"subscription_max_keepalive_count,omitempty"`
+	SubscriptionPriority          uint8  `json:"subscription_priority,omitempty"`
+	// UIScale multiplies the text size of the UI (e.g. 1.5 for wall monitors); zero means 1.
+	UIScale float64 `json:"ui_scale,omitempty"`
+	// UIDensity is "comfortable" (default) or "compact", which halves paddings and line spacing.
+	UIDensity string `json:"ui_density,omitempty"`
+	// TrayEnabled shows a system tray icon; closing the window then hides it instead of quitting.
+	TrayEnabled bool `json:"tray_enabled,omitempty"`
+	// StartMinimized starts hidden in the tray (only when TrayEnabled).
+	StartMinimized bool `json:"start_minimized,omitempty"`
+	// WatchList is the saved watch list as "nsu=<uri>;<id>" references (see ExpandNodeID), resolved
+	// against the server's NamespaceArray on connect.
+	WatchList []string `json:"watch_list,omitempty"`
+	// Favorites are the starred nodes of the Favorites panel, referenced like WatchList entries.
+	Favorites []Favorite `json:"favorites,omitempty"`
+	// RecentNodes (newest first) and WriteHistory (newest first) back the History menu.
+	RecentNodes  []Favorite    `json:"recent_nodes,omitempty"`
+	WriteHistory []WriteRecord `json:"write_history,omitempty"`
+	// ExportTemplates are named column layouts selectable for exports (UI and REST).
+	ExportTemplates []ExportTemplate `json:"export_templates,omitempty"`
+	// EventFilters are named event filters for event subscriptions and event history (UI and REST).
+	EventFilters []EventFilterPreset `json:"event_filters,omitempty"`
+	// ExportSkipAttributes are optional attributes not read during address space exports, by
+	// export field (see ExportAttributeFields), e.g. "value" and "description".
+	ExportSkipAttributes []string `json:"export_skip_attributes,omitempty"`
+	// ExportReadBatch is the number of nodes read per Read request during exports; zero uses 200.
+	ExportReadBatch int `json:"export_read_batch,omitempty"`
+	// CSVDelimiter (comma, semicolon or tab), CSVBOM and CSVQuote (minimal or all) are the
+	// defaults of CSV exports, see CSVFormat.
+	CSVDelimiter string `json:"csv_delimiter,omitempty"`
+	CSVBOM       bool   `json:"csv_bom,omitempty"`
+	CSVQuote     string `json:"csv_quote,omitempty"`
+	// ExportDir is where POST /api/v1/export/file writes files; requests name paths relative to
+	// it. Empty uses opcuababy-exports in the temp directory.
+	ExportDir string `json:"export_dir,omitempty"`
+	// DashboardUsers, when set, require a login for the web dashboard ("/", "/doc"); passwords are
+	// stored as bcrypt hashes (see HashDashboardPassword). They also protect the API: see
+	// ApiAuthRequired.
+	DashboardUsers []DashboardUser `json:"dashboard_users,omitempty"`
+	// ApiKeyHash is the SHA-256 hash (hex) of the API key of scripts and other API clients (see
+	// GenerateApiKey), which send it as "Authorization: Bearer <key>" or in X-API-Key.
+	ApiKeyHash string `json:"api_key_hash,omitempty"`
+	// ApiAllowedOrigins are the origins ("https://scada.example:8443") of web pages besides the
+	// API server's own that may open /ws/rpc from a browser.
+	ApiAllowedOrigins []string `json:"api_allowed_origins,omitempty"`
+	// DashboardIdleMinutes ends dashboard sessions idle for longer; zero uses 30 minutes.
+	DashboardIdleMinutes float64 `json:"dashboard_idle_minutes,omitempty"`
+	// PublicDashboard serves a read-only dashboard of the watch list without login on
+	// PublicDashboardPort (default 8081, never the API port) while the API server runs, e.g. for
+	// plant TVs. It offers no writes, method calls or other API endpoints.
+	PublicDashboard     bool   `json:"public_dashboard,omitempty"`
+	PublicDashboardPort string `json:"public_dashboard_port,omitempty"`
+	// GoldenValues are the expected values of watched nodes, keyed like WatchList entries.
+	GoldenValues map[string]GoldenValue `json:"golden_values,omitempty"`
+	// NodeNotes are free-text notes on nodes (commissioning observations, vendor tickets), keyed
+	// like WatchList entries. They are shown in the details and exported with connection profiles
+	// on request.
+	NodeNotes map[string]string `json:"node_notes,omitempty"`
+	// NodeAliases are short names of nodes (e.g. a tag such as "TT-101"), keyed like WatchList
+	// entries, that API payloads include with ?include=meta.
+	NodeAliases map[string]string `json:"node_aliases,omitempty"`
+	// ModelWatchBranches are branches browsed again periodically to detect added and removed
+	// nodes on servers that do not send model change events, keyed like WatchList entries.
+	ModelWatchBranches []string `json:"model_watch_branches,omitempty"`
+	// GoldenNotify shows a desktop notification when a watched value leaves its expected value.
+	GoldenNotify bool `json:"golden_notify,omitempty"`
+	// GoldenWebhookURL, if set, receives a JSON POST for each such deviation.
+	GoldenWebhookURL string `json:"golden_webhook_url,omitempty"`
+	// Profiles are saved connection profiles (without certificates), selectable in the settings
+	// and shared between devices by sync.
+	Profiles []ConnectionProfile `json:"profiles,omitempty"`
+	// SyncURL is where profiles, the watch list and favorites are synced: a file path (file
+	// share), an http(s) WebDAV file URL or s3://bucket/key. Empty disables sync.
+	SyncURL string `json:"sync_url,omitempty"`
+	// SyncUsername and SyncPassword log in to WebDAV, or are the access key ID and secret key
+	// for S3.
+	SyncUsername string `json:"sync_username,omitempty"`
+	SyncPassword string `json:"sync_password,omitempty"`
+	// SyncS3Endpoint overrides the S3 endpoint (MinIO, ...); SyncS3Region defaults to us-east-1.
+	SyncS3Endpoint string `json:"sync_s3_endpoint,omitempty"`
+	SyncS3Region   string `json:"sync_s3_region,omitempty"`
+	// SyncOnStart syncs once when the application starts.
+	SyncOnStart bool `json:"sync_on_start,omitempty"`
+	// SyncETag and SyncHash record the remote version and the local content at the last sync,
+	// to tell which side changed since.
+	SyncETag string    `json:"sync_etag,omitempty"`
+	SyncHash string    `json:"sync_hash,omitempty"`
+	SyncTime time.Time `json:"sync_time,omitempty"`
+	// UpdateURL is where new releases are looked up: a GitHub release API URL, or the URL or path
+	// of a release description on a plant server. Empty uses the project's GitHub releases.
+	UpdateURL string `json:"update_url,omitempty"`
+	// UpdateCheckOnStart looks for a new release at startup, at most once a day.
+	UpdateCheckOnStart bool `json:"update_check_on_start,omitempty"`
+	// UpdateSkipVersion is a release the user chose not to be reminded of; UpdateLastCheck is
+	// the last automatic check.
+	UpdateSkipVersion string    `json:"update_skip_version,omitempty"`
+	UpdateLastCheck   time.Time `json:"update_last_check,omitempty"`
+	// Usage holds the local usage statistics (see Controller.UsageStats).
+	Usage UsageStats `json:"usage"`
+}
+
+// Favorite is a starred node. Name is the display name when it was starred, shown while the
+// node cannot be resolved (e.g. before connecting).
+type Favorite struct {
+	Ref  string `json:"ref"`
+	Name string `json:"name,omitempty"`
+}
+
+// WriteRecord is one entry of the write history. Ref is stored like WatchList entries; Result is
+// "Good" or the reason the write failed.
+type WriteRecord struct {
+	Ref      string    `json:"ref"`
+	DataType string    `json:"data_type"`
+	Value    string    `json:"value"`
+	Time     time.Time `json:"time"`
+	Result   string    `json:"result"`
+}
+
+// GoldenValue is the value a watched node is expected to have. Numeric values match within
+// Tolerance; other values must be equal (case-insensitive).
+type GoldenValue struct {
+	Expected  string  `json:"expected"`
+	Tolerance float64 `json:"tolerance,omitempty"`
 }
 
 // ToOpcuaOptions converts the Config struct into a slice of opcua.Option
@@ -73,10 +279,8 @@ func (c *Config) ToOpcuaOptions() ([]opcua.Option, error) {
 		opts = append(opts, opcua.SessionTimeout(time.Duration(c.SessionTimeout)*time.Second))
 	}
 
-	// Set connection timeout
-	if c.ConnectTimeout > 0 {
-		opts = append(opts, opcua.DialTimeout(time.Duration(c.ConnectTimeout*float64(time.Second))))
-	}
+	// Set connection and request timeouts
+	opts = append(opts, c.TimeoutOptions()...)
 
 	// Security Policy/Mode: config-driven
 	modeLower := strings.ToLower(strings.TrimSpace(c.SecurityMode))
@@ -236,6 +440,8 @@ func (c *Config) ToOpcuaOptions() ([]opcua.Option, error) {
 	// Finally, set the Application URI (deterministic from config/hostname)
 	opts = append(opts, opcua.ApplicationURI(appURI))
 
+	opts = append(opts, c.LocaleOptions()...)
+
 	// Set SessionName: default to ApplicationURI when not provided
 	if sn := strings.TrimSpace(c.SessionName); sn != "" {
 		opts = append(opts, opcua.SessionName(sn))
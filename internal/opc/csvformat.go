@@ -0,0 +1,151 @@
+package opc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSV delimiters and quoting rules offered for CSV exports.
+const (
+	CSVComma     = "comma"
+	CSVSemicolon = "semicolon"
+	CSVTab       = "tab"
+
+	CSVQuoteMinimal = "minimal" // only fields containing the delimiter, quotes or line breaks
+	CSVQuoteAll     = "all"     // every field, so Excel keeps e.g. leading zeros as text
+)
+
+// CSVDelimiters and CSVQuotes are the values offered in the Export dialog.
+var (
+	CSVDelimiters = []string{CSVComma, CSVSemicolon, CSVTab}
+	CSVQuotes     = []string{CSVQuoteMinimal, CSVQuoteAll}
+)
+
+// CSVFormat describes how CSV files are written. The zero value is plain RFC 4180 CSV: commas,
+// minimal quoting and no BOM. Excel with European regional settings expects semicolons, and
+// needs the BOM to read UTF-8.
+type CSVFormat struct {
+	Delimiter string `json:"delimiter,omitempty"` // CSVComma (default), CSVSemicolon or CSVTab
+	BOM       bool   `json:"bom,omitempty"`       // start with a UTF-8 byte order mark
+	Quote     string `json:"quote,omitempty"`     // CSVQuoteMinimal (default) or CSVQuoteAll
+}
+
+// CSVFormat returns the CSV options configured for exports.
+func (c *Config) CSVFormat() CSVFormat {
+	if c == nil {
+		return CSVFormat{}
+	}
+	return CSVFormat{Delimiter: c.CSVDelimiter, BOM: c.CSVBOM, Quote: c.CSVQuote}
+}
+
+// ParseCSVDelimiter accepts a delimiter by name or as the character itself (",", ";" or a
+// tab); "" is CSVComma.
+func ParseCSVDelimiter(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", ",", CSVComma:
+		return CSVComma, nil
+	case ";", CSVSemicolon:
+		return CSVSemicolon, nil
+	case "\\t", CSVTab:
+		return CSVTab, nil
+	}
+	if s == "\t" {
+		return CSVTab, nil
+	}
+	return "", fmt.Errorf("delimiter must be comma, semicolon or tab, got %q", s)
+}
+
+// ParseCSVQuote accepts a quoting rule; "" is CSVQuoteMinimal.
+func ParseCSVQuote(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", CSVQuoteMinimal:
+		return CSVQuoteMinimal, nil
+	case CSVQuoteAll:
+		return CSVQuoteAll, nil
+	}
+	return "", fmt.Errorf("quote must be minimal or all, got %q", s)
+}
+
+// Comma returns the delimiter character.
+func (f CSVFormat) Comma() rune {
+	switch f.Delimiter {
+	case CSVSemicolon:
+		return ';'
+	case CSVTab:
+		return '\t'
+	}
+	return ','
+}
+
+// CSVWriter writes records like encoding/csv's Writer, with the delimiter, BOM and quoting of
+// a CSVFormat.
+type CSVWriter struct {
+	w        *bufio.Writer
+	comma    rune
+	quoteAll bool
+	bom      bool // still to be written
+}
+
+// NewCSVWriter returns a writer of f-formatted records to w.
+func NewCSVWriter(w io.Writer, f CSVFormat) *CSVWriter {
+	return &CSVWriter{w: bufio.NewWriter(w), comma: f.Comma(), quoteAll: f.Quote == CSVQuoteAll, bom: f.BOM}
+}
+
+// Write writes one record. Errors are sticky and reported by Flush and Error.
+func (cw *CSVWriter) Write(record []string) error {
+	if cw.bom {
+		cw.bom = false
+		cw.w.WriteString("\ufeff")
+	}
+	for i, field := range record {
+		if i > 0 {
+			cw.w.WriteRune(cw.comma)
+		}
+		if !cw.quoteAll && !cw.needsQuotes(field) {
+			cw.w.WriteString(field)
+			continue
+		}
+		cw.w.WriteByte('"')
+		cw.w.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		cw.w.WriteByte('"')
+	}
+	_, err := cw.w.WriteString("\n")
+	return err
+}
+
+// WriteAll writes records and flushes.
+func (cw *CSVWriter) WriteAll(records [][]string) error {
+	for _, r := range records {
+		if err := cw.Write(r); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// Flush writes buffered data to the underlying writer.
+func (cw *CSVWriter) Flush() error {
+	return cw.w.Flush()
+}
+
+// Error reports an error of a previous Write or Flush.
+func (cw *CSVWriter) Error() error {
+	_, err := cw.w.Write(nil)
+	return err
+}
+
+// needsQuotes follows encoding/csv: fields with the delimiter, quotes, line breaks or a leading
+// space are quoted.
+func (cw *CSVWriter) needsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsRune(field, cw.comma) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return r == ' ' || r == '\t'
+}
@@ -0,0 +1,136 @@
+package opc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultDashboardIdle ends web dashboard sessions when DashboardIdleMinutes is zero.
+const DefaultDashboardIdle = 30 * time.Minute
+
+// DashboardUser is a login of the embedded web dashboard.
+type DashboardUser struct {
+	Name         string `json:"name"`
+	PasswordHash string `json:"password_hash"` // bcrypt
+}
+
+// HashDashboardPassword returns the bcrypt hash stored in DashboardUser.PasswordHash.
+func HashDashboardPassword(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(h), nil
+}
+
+// DashboardLoginRequired reports whether the web dashboard requires a login. It is safe to call on
+// a nil Config.
+func (c *Config) DashboardLoginRequired() bool {
+	return c != nil && len(c.DashboardUsers) > 0
+}
+
+// DashboardIdle returns the idle timeout of web dashboard sessions.
+func (c *Config) DashboardIdle() time.Duration {
+	if c == nil || c.DashboardIdleMinutes <= 0 {
+		return DefaultDashboardIdle
+	}
+	return time.Duration(c.DashboardIdleMinutes * float64(time.Minute))
+}
+
+// CheckDashboardLogin returns the configured name of the dashboard user matching name and
+// password. Names are case-insensitive.
+func (c *Config) CheckDashboardLogin(name, password string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	name = strings.TrimSpace(name)
+	for _, u := range c.DashboardUsers {
+		if strings.EqualFold(u.Name, name) {
+			return u.Name, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+		}
+	}
+	return "", false
+}
+
+// ApiAuthRequired reports whether the API server wants a dashboard session or the API key for
+// its API, WebSocket and metrics routes: once dashboard users or an API key are configured. It is
+// safe to call on a nil Config.
+func (c *Config) ApiAuthRequired() bool {
+	return c.DashboardLoginRequired() || (c != nil && c.ApiKeyHash != "")
+}
+
+// GenerateApiKey returns a new random API key and the hash stored in Config.ApiKeyHash; the key
+// itself is not stored.
+func GenerateApiKey() (key, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key = "opcb_" + hex.EncodeToString(b)
+	return key, hashApiKey(key), nil
+}
+
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckApiKey reports whether key is the configured API key. It is safe to call on a nil Config.
+func (c *Config) CheckApiKey(key string) bool {
+	if c == nil || c.ApiKeyHash == "" || key == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashApiKey(key)), []byte(strings.ToLower(c.ApiKeyHash))) == 1
+}
+
+// DefaultPublicDashboardPort is used when PublicDashboardPort is empty.
+const DefaultPublicDashboardPort = "8081"
+
+// ValidatePublicDashboardPort checks that the public dashboard, when enabled, has a valid port
+// other than the API port (8080 when ApiPort is empty). It is safe to call on a nil Config.
+func (c *Config) ValidatePublicDashboardPort() error {
+	if c == nil || !c.PublicDashboard {
+		return nil
+	}
+	port := strings.TrimSpace(c.PublicDashboardPort)
+	if port == "" {
+		port = DefaultPublicDashboardPort
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("public dashboard port %q is not a valid port", port)
+	}
+	apiPort := strings.TrimSpace(c.ApiPort)
+	if apiPort == "" {
+		apiPort = "8080"
+	}
+	if a, err := strconv.Atoi(apiPort); err == nil && a == n {
+		return fmt.Errorf("public dashboard port %d is the API port; choose another one", n)
+	}
+	return nil
+}
+
+// PublicDashboardAddr returns the listen address of the read-only public dashboard, or "" when it
+// is disabled.
+func (c *Config) PublicDashboardAddr() string {
+	if c == nil || !c.PublicDashboard {
+		return ""
+	}
+	port := strings.TrimSpace(c.PublicDashboardPort)
+	if port == "" {
+		port = DefaultPublicDashboardPort
+	}
+	return ":" + port
+}
@@ -0,0 +1,87 @@
+package opc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// DialFailure is the likely cause of a failed connection attempt, used to pick the hints of the
+// connection diagnostics.
+type DialFailure string
+
+const (
+	DialFailureScheme         DialFailure = "scheme"          // endpoint URL uses a transport the stack does not implement
+	DialFailureDNS            DialFailure = "dns"             // host name does not resolve
+	DialFailureRefused        DialFailure = "refused"         // nothing listens on the port
+	DialFailureUnreachable    DialFailure = "unreachable"     // no route to the host or connection reset
+	DialFailureTimeout        DialFailure = "timeout"         // no answer in time
+	DialFailureCertificate    DialFailure = "certificate"     // a certificate was rejected
+	DialFailureSecurityChecks DialFailure = "security_checks" // BadSecurityChecksFailed, usually an untrusted client certificate
+	DialFailureSecurityPolicy DialFailure = "security_policy" // security policy or mode not accepted
+	DialFailureIdentity       DialFailure = "identity"        // user identity token or credentials rejected
+	DialFailureUnknown        DialFailure = "unknown"
+)
+
+// ClassifyDialError returns the likely cause of a Connect error.
+func ClassifyDialError(err error) DialFailure {
+	if err == nil {
+		return DialFailureUnknown
+	}
+	var schemeErr *UnsupportedSchemeError
+	if errors.As(err, &schemeErr) {
+		return DialFailureScheme
+	}
+	var sc ua.StatusCode
+	if errors.As(err, &sc) {
+		switch sc {
+		case ua.StatusBadSecurityChecksFailed:
+			return DialFailureSecurityChecks
+		case ua.StatusBadCertificateInvalid, ua.StatusBadCertificatePolicyCheckFailed, ua.StatusBadCertificateTimeInvalid,
+			ua.StatusBadCertificateIssuerTimeInvalid, ua.StatusBadCertificateHostNameInvalid, ua.StatusBadCertificateURIInvalid,
+			ua.StatusBadCertificateUseNotAllowed, ua.StatusBadCertificateIssuerUseNotAllowed, ua.StatusBadCertificateUntrusted,
+			ua.StatusBadCertificateRevocationUnknown, ua.StatusBadCertificateIssuerRevocationUnknown, ua.StatusBadCertificateRevoked,
+			ua.StatusBadCertificateIssuerRevoked, ua.StatusBadCertificateChainIncomplete, ua.StatusBadNoValidCertificates:
+			return DialFailureCertificate
+		case ua.StatusBadSecurityPolicyRejected, ua.StatusBadSecurityModeRejected, ua.StatusBadSecurityModeInsufficient:
+			return DialFailureSecurityPolicy
+		case ua.StatusBadIdentityTokenRejected, ua.StatusBadIdentityTokenInvalid, ua.StatusBadUserAccessDenied:
+			return DialFailureIdentity
+		case ua.StatusBadTimeout, ua.StatusBadRequestTimeout:
+			return DialFailureTimeout
+		case ua.StatusBadTCPEndpointURLInvalid:
+			return DialFailureDNS
+		}
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && !dnsErr.IsTimeout {
+		return DialFailureDNS
+	}
+	// Windows reports WSA error numbers that do not match the syscall constants, hence the text checks
+	msg := strings.ToLower(err.Error())
+	if errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(msg, "refused") {
+		return DialFailureRefused
+	}
+	if errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.ECONNRESET) ||
+		strings.Contains(msg, "unreachable") || strings.Contains(msg, "forcibly closed") {
+		return DialFailureUnreachable
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return DialFailureTimeout
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var recordHeader tls.RecordHeaderError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCert) || errors.As(err, &hostname) || errors.As(err, &recordHeader) {
+		return DialFailureCertificate
+	}
+	return DialFailureUnknown
+}
@@ -0,0 +1,271 @@
+package opc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// EventFilterOperators are the comparisons an EventClause can use.
+var EventFilterOperators = []string{"==", "!=", ">", ">=", "<", "<=", "like"}
+
+var eventFilterOperators = map[string]ua.FilterOperator{
+	"==":   ua.FilterOperatorEquals,
+	"!=":   ua.FilterOperatorEquals, // negated with Not
+	">":    ua.FilterOperatorGreaterThan,
+	">=":   ua.FilterOperatorGreaterThanOrEqual,
+	"<":    ua.FilterOperatorLessThan,
+	"<=":   ua.FilterOperatorLessThanOrEqual,
+	"like": ua.FilterOperatorLike,
+}
+
+// EventTypes are the standard event types offered when building a filter, by browse name.
+var EventTypes = []string{
+	"BaseEventType",
+	"ConditionType",
+	"AlarmConditionType",
+	"LimitAlarmType",
+	"DiscreteAlarmType",
+	"AuditEventType",
+	"SystemEventType",
+	"BaseModelChangeEventType",
+}
+
+var eventTypeIDs = map[string]uint32{
+	"baseeventtype":            id.BaseEventType,
+	"conditiontype":            id.ConditionType,
+	"alarmconditiontype":       id.AlarmConditionType,
+	"limitalarmtype":           id.LimitAlarmType,
+	"discretealarmtype":        id.DiscreteAlarmType,
+	"auditeventtype":           id.AuditEventType,
+	"systemeventtype":          id.SystemEventType,
+	"basemodelchangeeventtype": id.BaseModelChangeEventType,
+}
+
+// EventFields are common event fields offered when building a filter, as BaseEventType browse
+// paths.
+var EventFields = []string{
+	"Severity", "SourceName", "SourceNode", "Message", "EventType", "ReceiveTime",
+	"ConditionName", "Retain", "Comment", "Quality",
+	"EnabledState/Id", "ActiveState/Id", "AckedState/Id", "ConfirmedState/Id",
+}
+
+// EventClause is one condition of an event filter's where clause: Field (a BaseEventType
+// browse path such as "Severity" or "ActiveState/Id") compared with Value.
+type EventClause struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// EventFilterPreset is a named event filter: the extra fields to select and the conditions
+// events must meet, all of them. It serves live event subscriptions and event history reads.
+type EventFilterPreset struct {
+	Name string `json:"name"`
+	// EventType limits events to a type and its subtypes, as a standard type's browse name
+	// (see EventTypes) or a NodeID; empty passes all types
+	EventType string        `json:"event_type,omitempty"`
+	Fields    []string      `json:"fields,omitempty"`
+	Where     []EventClause `json:"where,omitempty"`
+}
+
+// Validate checks that the preset has a name and a filter the server can be sent.
+func (p *EventFilterPreset) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("event filter needs a name")
+	}
+	if _, err := p.Build(nil); err != nil {
+		return fmt.Errorf("event filter %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// SelectedFields returns the cleaned-up Fields, in the order Build selects them.
+func (p *EventFilterPreset) SelectedFields() []string {
+	var out []string
+	for _, f := range p.Fields {
+		if f = cleanEventField(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Build returns the EventFilter of the preset. Its select clauses are base followed by
+// SelectedFields; the where clause ANDs the event type and every clause of Where.
+func (p *EventFilterPreset) Build(base []*ua.SimpleAttributeOperand) (*ua.EventFilter, error) {
+	filter := &ua.EventFilter{SelectClauses: append([]*ua.SimpleAttributeOperand(nil), base...)}
+	for _, f := range p.SelectedFields() {
+		filter.SelectClauses = append(filter.SelectClauses, eventFieldOperand(f))
+	}
+
+	var conds []filterCondition
+	if t := strings.TrimSpace(p.EventType); t != "" {
+		typeID, err := ParseEventType(t)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, filterCondition{elem: &ua.ContentFilterElement{
+			FilterOperator: ua.FilterOperatorOfType,
+			FilterOperands: []*ua.ExtensionObject{ua.NewExtensionObject(&ua.LiteralOperand{Value: ua.MustVariant(typeID)})},
+		}})
+	}
+	for i, w := range p.Where {
+		field := cleanEventField(w.Field)
+		if field == "" {
+			return nil, fmt.Errorf("condition %d has no field", i+1)
+		}
+		operator := strings.ToLower(strings.TrimSpace(w.Operator))
+		op, ok := eventFilterOperators[operator]
+		if !ok {
+			return nil, fmt.Errorf("condition %d: unknown operator %q", i+1, w.Operator)
+		}
+		lit, err := eventLiteral(field, operator, w.Value)
+		if err != nil {
+			return nil, fmt.Errorf("condition %d (%s): %w", i+1, field, err)
+		}
+		conds = append(conds, filterCondition{
+			elem: &ua.ContentFilterElement{
+				FilterOperator: op,
+				FilterOperands: []*ua.ExtensionObject{
+					ua.NewExtensionObject(eventFieldOperand(field)),
+					ua.NewExtensionObject(&ua.LiteralOperand{Value: lit}),
+				},
+			},
+			not: operator == "!=",
+		})
+	}
+	if len(conds) > 0 {
+		filter.WhereClause = buildContentFilter(conds)
+	}
+	return filter, nil
+}
+
+// filterCondition is one operand of the AND of a where clause, optionally negated.
+type filterCondition struct {
+	elem *ua.ContentFilterElement
+	not  bool
+}
+
+// buildContentFilter ANDs conds into a content filter whose first element is the root.
+func buildContentFilter(conds []filterCondition) *ua.ContentFilter {
+	cf := &ua.ContentFilter{}
+	add := func(e *ua.ContentFilterElement) uint32 {
+		cf.Elements = append(cf.Elements, e)
+		return uint32(len(cf.Elements) - 1)
+	}
+	operands := func(idx ...uint32) []*ua.ExtensionObject {
+		out := make([]*ua.ExtensionObject, len(idx))
+		for i, j := range idx {
+			out[i] = ua.NewExtensionObject(&ua.ElementOperand{Index: j})
+		}
+		return out
+	}
+	var place func(conds []filterCondition) uint32
+	place = func(conds []filterCondition) uint32 {
+		if len(conds) == 1 {
+			if !conds[0].not {
+				return add(conds[0].elem)
+			}
+			i := add(&ua.ContentFilterElement{FilterOperator: ua.FilterOperatorNot})
+			cf.Elements[i].FilterOperands = operands(add(conds[0].elem))
+			return i
+		}
+		// Elements are added before their operands, so the first one placed is the root
+		i := add(&ua.ContentFilterElement{FilterOperator: ua.FilterOperatorAnd})
+		left := place(conds[:1])
+		cf.Elements[i].FilterOperands = operands(left, place(conds[1:]))
+		return i
+	}
+	place(conds)
+	return cf
+}
+
+// ParseEventType resolves a standard event type's browse name (case-insensitive) or a NodeID.
+func ParseEventType(s string) (*ua.NodeID, error) {
+	s = strings.TrimSpace(s)
+	if v, ok := eventTypeIDs[strings.ToLower(s)]; ok {
+		return ua.NewNumericNodeID(0, v), nil
+	}
+	if strings.Contains(s, "=") {
+		if n, err := ua.ParseNodeID(s); err == nil {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown event type %q", s)
+}
+
+func cleanEventField(f string) string {
+	return strings.Trim(strings.TrimSpace(f), "/")
+}
+
+// eventFieldOperand selects field, a BaseEventType browse path whose segments may carry a
+// namespace index ("2:Temperature").
+func eventFieldOperand(field string) *ua.SimpleAttributeOperand {
+	op := SelectField(id.BaseEventType, strings.Split(field, "/")...)
+	for _, qn := range op.BrowsePath {
+		if ns, name, ok := strings.Cut(qn.Name, ":"); ok {
+			if n, err := strconv.ParseUint(ns, 10, 16); err == nil {
+				qn.NamespaceIndex, qn.Name = uint16(n), name
+			}
+		}
+	}
+	return op
+}
+
+// eventLiteral types value for a comparison with field: Severity is a UInt16, SourceNode and
+// EventType are NodeIDs and Like patterns are strings; other values are taken as Boolean,
+// integer, number or text, whichever parses first.
+func eventLiteral(field, operator, value string) (*ua.Variant, error) {
+	value = strings.TrimSpace(value)
+	if operator == "like" {
+		return ua.MustVariant(value), nil
+	}
+	switch strings.ToLower(field) {
+	case "severity":
+		n, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("severity must be 0..65535")
+		}
+		return ua.MustVariant(uint16(n)), nil
+	case "sourcenode":
+		n, err := ua.ParseNodeID(value)
+		if err != nil {
+			return nil, err
+		}
+		return ua.MustVariant(n), nil
+	case "eventtype":
+		n, err := ParseEventType(value)
+		if err != nil {
+			return nil, err
+		}
+		return ua.MustVariant(n), nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return ua.MustVariant(b), nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return ua.MustVariant(int32(n)), nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return ua.MustVariant(f), nil
+	}
+	return ua.MustVariant(value), nil
+}
+
+// EventFilter returns the event filter preset called name.
+func (c *Config) EventFilter(name string) (*EventFilterPreset, error) {
+	name = strings.TrimSpace(name)
+	if c != nil {
+		for i := range c.EventFilters {
+			if strings.EqualFold(c.EventFilters[i].Name, name) {
+				p := c.EventFilters[i]
+				return &p, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown event filter %q", name)
+}
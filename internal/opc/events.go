@@ -0,0 +1,154 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// EventHandler receives the fields of one event, in the order of the filter's select clauses.
+type EventHandler func(fields []*ua.Variant)
+
+// EventSubscription is an event monitored item with a subscription of its own, so events are not
+// delayed by the publishing interval of the watch list. Close it when done; Disconnect closes
+// all of them.
+type EventSubscription struct {
+	sub    *opcua.Subscription
+	ch     chan *opcua.PublishNotificationData
+	done   chan struct{}
+	parent *Client
+}
+
+// SelectField returns a select clause for the field at path of the event type typeID (a
+// namespace 0 type such as id.BaseEventType).
+func SelectField(typeID uint32, path ...string) *ua.SimpleAttributeOperand {
+	qn := make([]*ua.QualifiedName, len(path))
+	for i, p := range path {
+		qn[i] = &ua.QualifiedName{Name: p}
+	}
+	return &ua.SimpleAttributeOperand{
+		TypeDefinitionID: ua.NewNumericNodeID(0, typeID),
+		BrowsePath:       qn,
+		AttributeID:      ua.AttributeIDValue,
+	}
+}
+
+// OfTypeFilter returns a where clause passing events of typeID and its subtypes.
+func OfTypeFilter(typeID uint32) *ua.ContentFilter {
+	return &ua.ContentFilter{Elements: []*ua.ContentFilterElement{{
+		FilterOperator: ua.FilterOperatorOfType,
+		FilterOperands: []*ua.ExtensionObject{ua.NewExtensionObject(&ua.LiteralOperand{
+			Value: ua.MustVariant(ua.NewNumericNodeID(0, typeID)),
+		})},
+	}}}
+}
+
+// MonitorEvents subscribes to the events nodeID (usually the Server object, i=2253) notifies
+// that pass filter and calls handle for each of them on a goroutine of the subscription.
+func (c *Client) MonitorEvents(nodeID *ua.NodeID, filter *ua.EventFilter, handle EventHandler) (*EventSubscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+	es := &EventSubscription{
+		ch:     make(chan *opcua.PublishNotificationData, 100),
+		done:   make(chan struct{}),
+		parent: c,
+	}
+	start := time.Now()
+	params := c.subscriptionParams(0)
+	sub, err := c.Client.Subscribe(context.Background(), &params, es.ch)
+	c.traceCall("CreateSubscription", start, nil, 1, err)
+	if err != nil {
+		return nil, err
+	}
+	es.sub = sub
+
+	req := &ua.MonitoredItemCreateRequest{
+		ItemToMonitor: &ua.ReadValueID{
+			NodeID:       nodeID,
+			AttributeID:  ua.AttributeIDEventNotifier,
+			DataEncoding: &ua.QualifiedName{},
+		},
+		MonitoringMode: ua.MonitoringModeReporting,
+		RequestedParameters: &ua.MonitoringParameters{
+			ClientHandle:  1,
+			DiscardOldest: true,
+			Filter: &ua.ExtensionObject{
+				EncodingMask: ua.ExtensionObjectBinary,
+				TypeID:       &ua.ExpandedNodeID{NodeID: ua.NewNumericNodeID(0, id.EventFilter_Encoding_DefaultBinary)},
+				Value:        *filter,
+			},
+			QueueSize: 100,
+		},
+	}
+	start = time.Now()
+	res, err := sub.Monitor(context.Background(), ua.TimestampsToReturnNeither, req)
+	c.traceCall("CreateMonitoredItems", start, responseHeader(res), 1, err)
+	if err == nil && res.Results[0].StatusCode != ua.StatusOK {
+		err = fmt.Errorf("failed to monitor events of %s: %w", nodeID, res.Results[0].StatusCode)
+	}
+	if err != nil {
+		_ = sub.Cancel(context.Background())
+		return nil, err
+	}
+
+	if c.events == nil {
+		c.events = make(map[*EventSubscription]struct{})
+	}
+	c.events[es] = struct{}{}
+	go es.run(handle)
+	return es, nil
+}
+
+func (es *EventSubscription) run(handle EventHandler) {
+	for {
+		var ntf *opcua.PublishNotificationData
+		select {
+		case <-es.done:
+			return
+		case ntf = <-es.ch:
+		}
+		if ntf == nil {
+			continue
+		}
+		if ntf.Error != nil {
+			es.parent.reqStats.failed(ntf.Error)
+			continue
+		}
+		list, ok := ntf.Value.(*ua.EventNotificationList)
+		if !ok || list == nil {
+			continue
+		}
+		for _, ev := range list.Events {
+			if ev != nil {
+				handle(ev.EventFields)
+			}
+		}
+	}
+}
+
+// Close cancels the subscription. It is safe to call more than once.
+func (es *EventSubscription) Close() error {
+	c := es.parent
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeEventsLocked(es)
+}
+
+// closeEventsLocked cancels es. Callers must hold c.mu.
+func (c *Client) closeEventsLocked(es *EventSubscription) error {
+	if _, ok := c.events[es]; !ok {
+		return nil
+	}
+	delete(c.events, es)
+	close(es.done)
+	return es.sub.Cancel(context.Background())
+}
@@ -0,0 +1,161 @@
+package opc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExportFields are the fields an export column can show, with their default headers. Exports
+// fill the fields they know: address space exports all but "path", tag exports (REST) the
+// NodeID, name, BrowseName, namespace URI, data type, description and path.
+var ExportFields = []ExportColumn{
+	{Field: "level", Header: "Level"},
+	{Field: "name", Header: "Name"},
+	{Field: "node_id", Header: "NodeID"},
+	{Field: "browse_name", Header: "BrowseName"},
+	{Field: "namespace_uri", Header: "NamespaceURI"},
+	{Field: "node_class", Header: "NodeClass"},
+	{Field: "data_type", Header: "DataType"},
+	{Field: "access_level", Header: "AccessLevel"},
+	{Field: "description", Header: "Description"},
+	{Field: "value", Header: "Value"},
+	{Field: "path", Header: "Path"},
+}
+
+// ExportAttributeFields are the export fields backed by optional node attributes, which
+// address space exports can skip reading. NodeID, NodeClass and DisplayName are always read.
+var ExportAttributeFields = []string{"data_type", "access_level", "description", "value"}
+
+// ExportColumn is one column of an export: the field it shows and its header; an empty Header
+// uses the field's default.
+type ExportColumn struct {
+	Field  string `json:"field"`
+	Header string `json:"header,omitempty"`
+}
+
+// ExportTemplate is a named column layout for CSV, Excel and templated JSON exports.
+type ExportTemplate struct {
+	Name    string         `json:"name"`
+	Columns []ExportColumn `json:"columns"`
+}
+
+// Headers returns the header of each column.
+func (t *ExportTemplate) Headers() []string {
+	headers := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+		if headers[i] == "" {
+			headers[i] = defaultExportHeader(col.Field)
+		}
+	}
+	return headers
+}
+
+// Row returns the cells of one exported item, taking each column's value from field.
+func (t *ExportTemplate) Row(field func(name string) string) []string {
+	row := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		row[i] = field(col.Field)
+	}
+	return row
+}
+
+// Index returns the position of the first column showing field, or -1.
+func (t *ExportTemplate) Index(field string) int {
+	for i, col := range t.Columns {
+		if col.Field == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate checks that the template has a name and at least one column, all of known fields.
+func (t *ExportTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("export template needs a name")
+	}
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("export template %q has no columns", t.Name)
+	}
+	for _, col := range t.Columns {
+		if defaultExportHeader(col.Field) == "" {
+			return fmt.Errorf("export template %q: unknown field %q", t.Name, col.Field)
+		}
+	}
+	return nil
+}
+
+// String renders the columns one per line as "field" or "field: Header", the form accepted by
+// ParseExportColumns.
+func (t *ExportTemplate) String() string {
+	lines := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		lines[i] = col.Field
+		if col.Header != "" {
+			lines[i] += ": " + col.Header
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseExportColumns parses columns written one per line (or comma-separated) as "field" or
+// "field: Header".
+func ParseExportColumns(text string) ([]ExportColumn, error) {
+	var cols []ExportColumn
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		field, header, _ := strings.Cut(line, ":")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if defaultExportHeader(field) == "" {
+			return nil, fmt.Errorf("unknown export field %q", field)
+		}
+		cols = append(cols, ExportColumn{Field: field, Header: strings.TrimSpace(header)})
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no export columns")
+	}
+	return cols, nil
+}
+
+// DefaultExportTemplate returns the standard layout of address space exports: every field
+// but "path", with the default headers.
+func DefaultExportTemplate() *ExportTemplate {
+	t := &ExportTemplate{}
+	for _, f := range ExportFields {
+		if f.Field != "path" {
+			t.Columns = append(t.Columns, ExportColumn{Field: f.Field})
+		}
+	}
+	return t
+}
+
+// ExportTemplate returns the template called name. An empty name returns nil, meaning the
+// export's standard layout.
+func (c *Config) ExportTemplate(name string) (*ExportTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	if c != nil {
+		for i := range c.ExportTemplates {
+			if strings.EqualFold(c.ExportTemplates[i].Name, name) {
+				t := c.ExportTemplates[i]
+				return &t, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown export template %q", name)
+}
+
+func defaultExportHeader(field string) string {
+	for _, f := range ExportFields {
+		if f.Field == field {
+			return f.Header
+		}
+	}
+	return ""
+}
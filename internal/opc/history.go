@@ -0,0 +1,225 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// maxHistoryPages bounds how many continuation points are followed for a single
+// history read so a misbehaving server cannot keep us looping forever.
+const maxHistoryPages = 50
+
+// ErrHistoryTruncated is returned, together with the values read so far, when a history read
+// still had more data after maxHistoryPages pages. The server's continuation point is released.
+var ErrHistoryTruncated = fmt.Errorf("history read stopped after %d pages, the server has more data; narrow the time range", maxHistoryPages)
+
+// HistoryReadRaw reads raw historical values of a node between start and end.
+// maxValues limits the number of values per page (0 lets the server decide).
+// Continuation points are followed until the server reports no more data, or up to
+// maxHistoryPages pages (see ErrHistoryTruncated).
+func (c *Client) HistoryReadRaw(ctx context.Context, nodeID string, start, end time.Time, maxValues uint32) ([]*ua.DataValue, error) {
+	details := &ua.ReadRawModifiedDetails{
+		StartTime:        start,
+		EndTime:          end,
+		NumValuesPerNode: maxValues,
+	}
+	return c.historyRead(ctx, nodeID, details)
+}
+
+// HistoryReadProcessed reads aggregated values (Average, Minimum, Maximum, ...)
+// computed by the server over the given processing interval.
+func (c *Client) HistoryReadProcessed(ctx context.Context, nodeID string, start, end time.Time, interval time.Duration, aggregate *ua.NodeID) ([]*ua.DataValue, error) {
+	if aggregate == nil {
+		return nil, errors.New("aggregate type is required")
+	}
+	details := &ua.ReadProcessedDetails{
+		StartTime:          start,
+		EndTime:            end,
+		ProcessingInterval: float64(interval / time.Millisecond),
+		AggregateType:      []*ua.NodeID{aggregate},
+		AggregateConfiguration: &ua.AggregateConfiguration{
+			UseServerCapabilitiesDefaults: true,
+		},
+	}
+	return c.historyRead(ctx, nodeID, details)
+}
+
+// historyRead runs a single-node HistoryRead of details and follows continuation points.
+func (c *Client) historyRead(ctx context.Context, nodeID string, details interface{}) ([]*ua.DataValue, error) {
+	var values []*ua.DataValue
+	err := c.historyPages(ctx, nodeID, details, func(data *ua.ExtensionObject) {
+		if hd, ok := data.Value.(*ua.HistoryData); ok && hd != nil {
+			values = append(values, hd.DataValues...)
+		}
+	})
+	return values, err
+}
+
+// HistoryReadEvents reads the events nodeID (usually the Server object or a notifier below
+// it) historized between start and end, each with the fields of filter's select clauses.
+// maxEvents limits the number of events per page (0 lets the server decide).
+func (c *Client) HistoryReadEvents(ctx context.Context, nodeID string, start, end time.Time, filter *ua.EventFilter, maxEvents uint32) ([][]*ua.Variant, error) {
+	details := &ua.ReadEventDetails{
+		NumValuesPerNode: maxEvents,
+		StartTime:        start,
+		EndTime:          end,
+		Filter:           filter,
+	}
+	var events [][]*ua.Variant
+	err := c.historyPages(ctx, nodeID, details, func(data *ua.ExtensionObject) {
+		if he, ok := data.Value.(*ua.HistoryEvent); ok && he != nil {
+			for _, ev := range he.Events {
+				if ev != nil {
+					events = append(events, ev.EventFields)
+				}
+			}
+		}
+	})
+	return events, err
+}
+
+// historyPages runs a single-node HistoryRead of details, passing the history data of each page
+// to page, and follows continuation points. After maxHistoryPages pages it releases the
+// continuation point and returns ErrHistoryTruncated.
+func (c *Client) historyPages(ctx context.Context, nodeID string, details interface{}, page func(data *ua.ExtensionObject)) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return errors.New("client not connected")
+	}
+
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return err
+	}
+
+	var cp []byte
+	for n := 0; ; n++ {
+		if n == maxHistoryPages {
+			c.sendHistoryRead(ctx, id, details, cp, true)
+			return ErrHistoryTruncated
+		}
+		resp, err := c.sendHistoryRead(ctx, id, details, cp, false)
+		if err != nil {
+			return err
+		}
+		if resp == nil || len(resp.Results) == 0 || resp.Results[0] == nil {
+			return errors.New("empty history read response")
+		}
+		res := resp.Results[0]
+		if res.StatusCode != ua.StatusOK && res.StatusCode != ua.StatusGoodNoData && res.StatusCode != ua.StatusGoodMoreData {
+			return fmt.Errorf("history read failed with status: %s", res.StatusCode)
+		}
+		if res.HistoryData != nil {
+			page(res.HistoryData)
+		}
+		if len(res.ContinuationPoint) == 0 {
+			return nil
+		}
+		cp = res.ContinuationPoint
+	}
+}
+
+// sendHistoryRead sends a HistoryRead of details for id, continuing at cp if set. With release
+// set the server frees cp instead of returning data. Callers must hold c.mu.
+func (c *Client) sendHistoryRead(ctx context.Context, id *ua.NodeID, details interface{}, cp []byte, release bool) (*ua.HistoryReadResponse, error) {
+	req := &ua.HistoryReadRequest{
+		HistoryReadDetails:        ua.NewExtensionObject(details),
+		TimestampsToReturn:        ua.TimestampsToReturnBoth,
+		ReleaseContinuationPoints: release,
+		NodesToRead:               []*ua.HistoryReadValueID{{NodeID: id, ContinuationPoint: cp}},
+	}
+	var resp *ua.HistoryReadResponse
+	start := time.Now()
+	err := c.Client.Send(ctx, req, func(v ua.Response) error {
+		r, ok := v.(*ua.HistoryReadResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response type %T", v)
+		}
+		resp = r
+		return nil
+	})
+	c.traceCall("HistoryRead", start, responseHeader(resp), 1, err)
+	c.reqStats.bytes("HistoryRead", req, resp)
+	return resp, err
+}
+
+// HistoryUpdateData inserts, replaces or upserts historical values of a node
+// (Part 11, UpdateDataDetails). Every value must carry a SourceTimestamp.
+// The per-value operation results are returned alongside any overall error.
+func (c *Client) HistoryUpdateData(ctx context.Context, nodeID string, mode ua.PerformUpdateType, values []*ua.DataValue) ([]ua.StatusCode, error) {
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values to update")
+	}
+	details := &ua.UpdateDataDetails{
+		NodeID:               id,
+		PerformInsertReplace: mode,
+		UpdateValues:         values,
+	}
+	return c.historyUpdate(ctx, ua.NewExtensionObject(details))
+}
+
+// HistoryDeleteRaw deletes raw historical values of a node between start and end
+// (Part 11, DeleteRawModifiedDetails).
+func (c *Client) HistoryDeleteRaw(ctx context.Context, nodeID string, start, end time.Time) error {
+	id, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return err
+	}
+	details := &ua.DeleteRawModifiedDetails{
+		NodeID:    id,
+		StartTime: start,
+		EndTime:   end,
+	}
+	_, err = c.historyUpdate(ctx, ua.NewExtensionObject(details))
+	return err
+}
+
+// historyUpdate sends a single-detail HistoryUpdate request and checks its result.
+func (c *Client) historyUpdate(ctx context.Context, details *ua.ExtensionObject) ([]ua.StatusCode, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+
+	req := &ua.HistoryUpdateRequest{HistoryUpdateDetails: []*ua.ExtensionObject{details}}
+	var resp *ua.HistoryUpdateResponse
+	start := time.Now()
+	err := c.Client.Send(ctx, req, func(v ua.Response) error {
+		r, ok := v.(*ua.HistoryUpdateResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response type %T", v)
+		}
+		resp = r
+		return nil
+	})
+	c.traceCall("HistoryUpdate", start, responseHeader(resp), 1, err)
+	c.reqStats.bytes("HistoryUpdate", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || len(resp.Results) == 0 || resp.Results[0] == nil {
+		return nil, errors.New("empty history update response")
+	}
+	res := resp.Results[0]
+	if res.StatusCode != ua.StatusOK {
+		return res.OperationResults, fmt.Errorf("history update failed with status: %s", res.StatusCode)
+	}
+	for _, sc := range res.OperationResults {
+		if sc != ua.StatusOK && sc != ua.StatusGoodEntryInserted && sc != ua.StatusGoodEntryReplaced {
+			return res.OperationResults, fmt.Errorf("history update rejected value: %s", sc)
+		}
+	}
+	return res.OperationResults, nil
+}
@@ -0,0 +1,103 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ServerIdentity describes the connected server: its ApplicationDescription and the
+// BuildInfo published under Server/ServerStatus.
+type ServerIdentity struct {
+	ApplicationName  string    `json:"application_name"`
+	ApplicationURI   string    `json:"application_uri"`
+	ProductURI       string    `json:"product_uri"`
+	ProductName      string    `json:"product_name,omitempty"`
+	ManufacturerName string    `json:"manufacturer_name,omitempty"`
+	SoftwareVersion  string    `json:"software_version,omitempty"`
+	BuildNumber      string    `json:"build_number,omitempty"`
+	BuildDate        time.Time `json:"build_date,omitempty"`
+	StartTime        time.Time `json:"start_time,omitempty"`
+}
+
+// buildInfoNodes are read in this order by ReadServerIdentity.
+var buildInfoNodes = []uint32{
+	id.Server_ServerStatus_BuildInfo_ProductName,
+	id.Server_ServerStatus_BuildInfo_ProductURI,
+	id.Server_ServerStatus_BuildInfo_ManufacturerName,
+	id.Server_ServerStatus_BuildInfo_SoftwareVersion,
+	id.Server_ServerStatus_BuildInfo_BuildNumber,
+	id.Server_ServerStatus_BuildInfo_BuildDate,
+	id.Server_ServerStatus_StartTime,
+}
+
+// ReadServerIdentity reads the server's ApplicationDescription (via FindServers) and BuildInfo.
+// Either part may be missing on servers that do not implement it; an error is returned only
+// when neither could be read.
+func (c *Client) ReadServerIdentity(ctx context.Context) (*ServerIdentity, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+
+	si := &ServerIdentity{}
+
+	start := time.Now()
+	fs, fsErr := c.Client.FindServers(ctx)
+	c.traceCall("FindServers", start, responseHeader(fs), 0, fsErr)
+	if fsErr == nil {
+		for _, app := range fs.Servers {
+			if app == nil || app.ApplicationType == ua.ApplicationTypeClient {
+				continue
+			}
+			si.ApplicationURI = app.ApplicationURI
+			si.ProductURI = app.ProductURI
+			if app.ApplicationName != nil {
+				si.ApplicationName = app.ApplicationName.Text
+			}
+			break
+		}
+	}
+
+	nodesToRead := make([]*ua.ReadValueID, len(buildInfoNodes))
+	for i, n := range buildInfoNodes {
+		nodesToRead[i] = &ua.ReadValueID{NodeID: ua.NewNumericNodeID(0, n), AttributeID: ua.AttributeIDValue}
+	}
+	start = time.Now()
+	resp, readErr := c.Client.Read(ctx, &ua.ReadRequest{NodesToRead: nodesToRead, TimestampsToReturn: ua.TimestampsToReturnNeither})
+	c.traceCall("Read", start, responseHeader(resp), len(nodesToRead), readErr)
+	if fsErr != nil && readErr != nil {
+		return nil, readErr
+	}
+	if readErr == nil {
+		str := func(i int) string {
+			if i >= len(resp.Results) || resp.Results[i] == nil || resp.Results[i].Status != ua.StatusOK || resp.Results[i].Value == nil {
+				return ""
+			}
+			s, _ := resp.Results[i].Value.Value().(string)
+			return s
+		}
+		tm := func(i int) time.Time {
+			if i >= len(resp.Results) || resp.Results[i] == nil || resp.Results[i].Status != ua.StatusOK || resp.Results[i].Value == nil {
+				return time.Time{}
+			}
+			t, _ := resp.Results[i].Value.Value().(time.Time)
+			return t
+		}
+		si.ProductName = str(0)
+		if si.ProductURI == "" {
+			si.ProductURI = str(1)
+		}
+		si.ManufacturerName = str(2)
+		si.SoftwareVersion = str(3)
+		si.BuildNumber = str(4)
+		si.BuildDate = tm(5)
+		si.StartTime = tm(6)
+	}
+	return si, nil
+}
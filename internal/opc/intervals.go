@@ -0,0 +1,141 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gopcua/opcua"
+)
+
+// Default update rates used when the corresponding Config field is zero.
+const (
+	DefaultPublishInterval   = time.Second
+	DefaultWatchPumpInterval = 33 * time.Millisecond
+	DefaultPollInterval      = time.Second
+	DefaultPulseDuration     = 500 * time.Millisecond
+)
+
+// Watch item polling modes (Config.PollMode). Polled items are read periodically instead of
+// being monitored through the subscription.
+const (
+	PollAuto   = ""       // poll the items the server refuses to monitor
+	PollNever  = "never"  // leave such items without updates
+	PollAlways = "always" // poll every item, for servers without working subscriptions
+)
+
+// Intervals holds the effective subscription publishing interval, watch list redraw rate and
+// polling interval.
+type Intervals struct {
+	Publish   time.Duration
+	WatchPump time.Duration
+	Poll      time.Duration
+}
+
+// Intervals returns the configured update rates with defaults applied. It is safe to call on a nil Config.
+func (c *Config) Intervals() Intervals {
+	if c == nil {
+		return Intervals{DefaultPublishInterval, DefaultWatchPumpInterval, DefaultPollInterval}
+	}
+	return Intervals{
+		Publish:   millisOr(c.PublishIntervalMs, DefaultPublishInterval),
+		WatchPump: millisOr(c.WatchPumpIntervalMs, DefaultWatchPumpInterval),
+		Poll:      millisOr(c.PollIntervalMs, DefaultPollInterval),
+	}
+}
+
+// PulseDuration returns how long a Pulse holds a Boolean node true. It is safe to call on a nil
+// Config.
+func (c *Config) PulseDuration() time.Duration {
+	if c == nil {
+		return DefaultPulseDuration
+	}
+	return millisOr(c.PulseMs, DefaultPulseDuration)
+}
+
+func millisOr(ms float64, def time.Duration) time.Duration {
+	if ms <= 0 {
+		return def
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// SubscriptionTuning holds the requested subscription parameters besides the publishing
+// interval. Zero values use the stack defaults.
+type SubscriptionTuning struct {
+	LifetimeCount     uint32
+	MaxKeepAliveCount uint32
+	Priority          uint8
+}
+
+// SubscriptionTuning returns the configured subscription parameters. It is safe to call on a nil Config.
+func (c *Config) SubscriptionTuning() SubscriptionTuning {
+	if c == nil {
+		return SubscriptionTuning{}
+	}
+	return SubscriptionTuning{
+		LifetimeCount:     c.SubscriptionLifetimeCount,
+		MaxKeepAliveCount: c.SubscriptionMaxKeepAliveCount,
+		Priority:          c.SubscriptionPriority,
+	}
+}
+
+// subscriptionParams returns the parameters for creating or modifying the subscription of the
+// rate group interval; the default group 0 publishes at the SetPublishInterval rate. Callers
+// must hold c.mu.
+func (c *Client) subscriptionParams(group time.Duration) opcua.SubscriptionParameters {
+	interval := group
+	if interval <= 0 {
+		interval = c.publishInterval
+	}
+	if interval <= 0 {
+		interval = DefaultPublishInterval
+	}
+	return opcua.SubscriptionParameters{
+		Interval:          interval,
+		LifetimeCount:     c.tuning.LifetimeCount,
+		MaxKeepAliveCount: c.tuning.MaxKeepAliveCount,
+		Priority:          c.tuning.Priority,
+	}
+}
+
+// SetSubscriptionTuning sets the lifetime and keep-alive counts and the priority requested for
+// the watch subscriptions, modifying existing subscriptions in place.
+func (c *Client) SetSubscriptionTuning(ctx context.Context, t SubscriptionTuning) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t == c.tuning {
+		return nil
+	}
+	c.tuning = t
+	var errs []error
+	for d, g := range c.groups {
+		start := time.Now()
+		res, err := g.sub.ModifySubscription(ctx, c.subscriptionParams(d))
+		c.traceCall("ModifySubscription", start, responseHeader(res), 1, err)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// SetPublishInterval sets the publishing interval requested for the default watch subscription.
+// If the subscription already exists it is modified in place; otherwise the value is used on
+// creation. Rate groups keep publishing at their own interval.
+func (c *Client) SetPublishInterval(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d <= 0 || d == c.publishInterval {
+		return nil
+	}
+	c.publishInterval = d
+	g := c.groups[0]
+	if g == nil {
+		return nil
+	}
+	start := time.Now()
+	res, err := g.sub.ModifySubscription(ctx, c.subscriptionParams(0))
+	c.traceCall("ModifySubscription", start, responseHeader(res), 1, err)
+	return err
+}
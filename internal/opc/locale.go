@@ -0,0 +1,133 @@
+package opc
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gopcua/opcua"
+)
+
+// machineDateTimeLayout is how values and history timestamps are rendered before localization.
+const machineDateTimeLayout = "2006-01-02 15:04:05.000"
+
+// DisplayLocale formats numbers and dates for people: the decimal separator and the order of
+// day, month and year. The zero value keeps the machine format (decimal point, ISO dates).
+type DisplayLocale struct {
+	Tag        string
+	Decimal    string
+	DateLayout string
+}
+
+// DisplayLocales are the locales offered in Settings, keyed by tag.
+var DisplayLocales = []DisplayLocale{
+	{Tag: "en-US", Decimal: ".", DateLayout: "01/02/2006"},
+	{Tag: "en-GB", Decimal: ".", DateLayout: "02/01/2006"},
+	{Tag: "de-DE", Decimal: ",", DateLayout: "02.01.2006"},
+	{Tag: "fr-FR", Decimal: ",", DateLayout: "02/01/2006"},
+	{Tag: "zh-CN", Decimal: ".", DateLayout: "2006/01/02"},
+	{Tag: "ja-JP", Decimal: ".", DateLayout: "2006/01/02"},
+}
+
+// LookupDisplayLocale returns the locale for tag, or the machine format for "" and unknown tags.
+func LookupDisplayLocale(tag string) DisplayLocale {
+	for _, l := range DisplayLocales {
+		if strings.EqualFold(l.Tag, tag) {
+			return l
+		}
+	}
+	return DisplayLocale{}
+}
+
+// DisplayLocale returns the locale configured for the watch table and exports.
+func (c *Config) DisplayLocale() DisplayLocale {
+	if c == nil {
+		return DisplayLocale{}
+	}
+	return LookupDisplayLocale(c.FormatLocale)
+}
+
+// Number localizes the decimal separator of a number rendered with strconv or fmt; other
+// strings are returned unchanged.
+func (l DisplayLocale) Number(s string) string {
+	if l.Decimal == "" || l.Decimal == "." || !strings.Contains(s, ".") {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return s
+	}
+	return strings.Replace(s, ".", l.Decimal, 1)
+}
+
+// Time renders t with the locale's date order, or in the machine layout.
+func (l DisplayLocale) Time(t time.Time) string {
+	if l.DateLayout == "" {
+		return t.Format(machineDateTimeLayout)
+	}
+	return t.Format(l.DateLayout + " 15:04:05" + l.Decimal + "000")
+}
+
+// Value localizes a formatted OPC UA value: a number, a "[a b c]" array of numbers or a
+// DateTime in the machine layout. Anything else is returned unchanged.
+func (l DisplayLocale) Value(s string) string {
+	if l.Tag == "" || s == "" {
+		return s
+	}
+	if t, err := time.Parse(machineDateTimeLayout, s); err == nil {
+		return l.Time(t)
+	}
+	if len(s) > 2 && s[0] == '[' && s[len(s)-1] == ']' {
+		fields := strings.Fields(s[1 : len(s)-1])
+		for i, f := range fields {
+			fields[i] = l.Number(f)
+		}
+		return "[" + strings.Join(fields, " ") + "]"
+	}
+	return l.Number(s)
+}
+
+// localizedTypes are the built-in types whose values DisplayLocale.ValueOfType localizes.
+var localizedTypes = map[string]bool{
+	"Float": true, "Double": true, "Decimal": true, "Duration": true, "DateTime": true, "UtcTime": true,
+}
+
+// ValueOfType is Value for a value of the named data type (e.g. "Double" or "Double[]"), leaving
+// strings and other non-numeric types alone even when they look like numbers. An empty type
+// name localizes like Value.
+func (l DisplayLocale) ValueOfType(dataType, s string) string {
+	if dataType != "" && !localizedTypes[strings.TrimSuffix(dataType, "[]")] {
+		return s
+	}
+	return l.Value(s)
+}
+
+// LocaleOptions returns the client option requesting LocaleIDs, or none to keep the stack
+// default.
+func (c *Config) LocaleOptions() []opcua.Option {
+	if c == nil || len(c.LocaleIDs) == 0 {
+		return nil
+	}
+	return []opcua.Option{opcua.Locales(c.LocaleIDs...)}
+}
+
+// SessionOptions returns the client options every connection attempt takes from the config
+// besides security and identity: the timeouts and the preferred locales.
+func (c *Config) SessionOptions() []opcua.Option {
+	return append(c.TimeoutOptions(), c.LocaleOptions()...)
+}
+
+// ParseLocaleIDs splits a list of locale IDs separated by commas, semicolons or spaces, e.g.
+// "de-DE, en", dropping empty entries and duplicates.
+func ParseLocaleIDs(s string) []string {
+	var ids []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	}) {
+		if !slices.ContainsFunc(ids, func(id string) bool { return strings.EqualFold(id, f) }) {
+			ids = append(ids, f)
+		}
+	}
+	return ids
+}
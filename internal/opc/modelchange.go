@@ -0,0 +1,92 @@
+package opc
+
+import (
+	"strings"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// Verbs of a ModelChange, a bit mask (Part 3, ModelChangeStructureDataType).
+const (
+	ModelNodeAdded        = 1
+	ModelNodeDeleted      = 2
+	ModelReferenceAdded   = 4
+	ModelReferenceDeleted = 8
+	ModelDataTypeChanged  = 16
+)
+
+// ModelChange is one entry of a GeneralModelChangeEvent: Affected was added or deleted, or
+// references from it were.
+type ModelChange struct {
+	Affected     string
+	AffectedType string
+	Verb         uint8
+}
+
+// VerbString names the bits of Verb, e.g. "NodeAdded|ReferenceAdded".
+func (m ModelChange) VerbString() string {
+	var parts []string
+	for _, v := range []struct {
+		bit  uint8
+		name string
+	}{
+		{ModelNodeAdded, "NodeAdded"},
+		{ModelNodeDeleted, "NodeDeleted"},
+		{ModelReferenceAdded, "ReferenceAdded"},
+		{ModelReferenceDeleted, "ReferenceDeleted"},
+		{ModelDataTypeChanged, "DataTypeChanged"},
+	} {
+		if m.Verb&v.bit != 0 {
+			parts = append(parts, v.name)
+		}
+	}
+	if len(parts) == 0 {
+		return "Changed"
+	}
+	return strings.Join(parts, "|")
+}
+
+// MonitorModelChanges subscribes to the model change events of the server. handle receives the
+// changes of each event; a BaseModelChangeEvent, which does not say what changed, yields none.
+func (c *Client) MonitorModelChanges(handle func(changes []ModelChange)) (*EventSubscription, error) {
+	filter := &ua.EventFilter{
+		SelectClauses: []*ua.SimpleAttributeOperand{
+			SelectField(id.GeneralModelChangeEventType, "Changes"),
+		},
+		WhereClause: OfTypeFilter(id.BaseModelChangeEventType),
+	}
+	return c.MonitorEvents(ua.NewNumericNodeID(0, id.Server), filter, func(fields []*ua.Variant) {
+		var changes []ModelChange
+		if len(fields) > 0 && fields[0] != nil {
+			changes = decodeModelChanges(fields[0].Value())
+		}
+		handle(changes)
+	})
+}
+
+func decodeModelChanges(v interface{}) []ModelChange {
+	var objs []*ua.ExtensionObject
+	switch x := v.(type) {
+	case []*ua.ExtensionObject:
+		objs = x
+	case *ua.ExtensionObject:
+		objs = []*ua.ExtensionObject{x}
+	}
+	out := make([]ModelChange, 0, len(objs))
+	for _, eo := range objs {
+		if eo == nil {
+			continue
+		}
+		mc, ok := eo.Value.(*ua.ModelChangeStructureDataType)
+		if !ok || mc == nil || mc.Affected == nil {
+			continue
+		}
+		m := ModelChange{Affected: mc.Affected.String(), Verb: mc.Verb}
+		if mc.AffectedType != nil {
+			m.AffectedType = mc.AffectedType.String()
+		}
+		out = append(out, m)
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package opc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// NamespaceZeroURI is the URI of namespace 0, the OPC UA namespace.
+const NamespaceZeroURI = "http://opcfoundation.org/UA/"
+
+// ExpandNodeID converts a NodeID using a namespace index into the "nsu=<uri>;<id>" form, looking
+// the index up in namespaces (a NamespaceArray). Namespace 0 NodeIDs are returned unchanged, as
+// are all NodeIDs when namespaces is empty.
+func ExpandNodeID(nodeID string, namespaces []string) (string, error) {
+	if strings.HasPrefix(nodeID, "nsu=") || len(namespaces) == 0 {
+		return nodeID, nil
+	}
+	n, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return "", err
+	}
+	idx := int(n.Namespace())
+	if idx == 0 {
+		return n.String(), nil
+	}
+	if idx >= len(namespaces) {
+		return "", fmt.Errorf("%s: namespace index %d not in NamespaceArray", nodeID, idx)
+	}
+	_, ident, _ := strings.Cut(n.String(), ";")
+	return "nsu=" + namespaces[idx] + ";" + ident, nil
+}
+
+// NamespaceURI returns the URI of the namespace of nodeID, looked up in namespaces (a
+// NamespaceArray) for NodeIDs using an index, or "" when it is not known.
+func NamespaceURI(nodeID string, namespaces []string) string {
+	if rest, ok := strings.CutPrefix(nodeID, "nsu="); ok {
+		uri, _, _ := strings.Cut(rest, ";")
+		return uri
+	}
+	n, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return ""
+	}
+	idx := int(n.Namespace())
+	switch {
+	case idx == 0:
+		return NamespaceZeroURI
+	case idx < len(namespaces):
+		return namespaces[idx]
+	}
+	return ""
+}
+
+// FormatBrowseName writes a BrowseName as "nsIndex:name", e.g. "2:Temperature"; the namespace
+// index is always included since mapping tools need it to address the node by browse path.
+func FormatBrowseName(qn *ua.QualifiedName) string {
+	if qn == nil || qn.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d:%s", qn.NamespaceIndex, qn.Name)
+}
+
+// ResolveNodeID converts a "nsu=<uri>;<id>" reference into a NodeID with the index of uri in
+// namespaces. NodeIDs already using an index are returned unchanged.
+func ResolveNodeID(ref string, namespaces []string) (string, error) {
+	if !strings.HasPrefix(ref, "nsu=") {
+		return ref, nil
+	}
+	en, err := ua.ParseExpandedNodeID(ref, namespaces)
+	if err != nil {
+		return "", err
+	}
+	return en.NodeID.String(), nil
+}
+
+// SplitNodeIDList splits pasted text into NodeIDs separated by newlines, commas or tabs. A
+// comma inside a string identifier ("ns=2;s=a,b") stays part of the NodeID: pieces that do not
+// start like a NodeID are joined to the previous one. Surrounding quotes and blanks are trimmed.
+func SplitNodeIDList(text string) []string {
+	var ids []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' || r == '\t' }) {
+		for i, part := range strings.Split(line, ",") {
+			part = strings.Trim(strings.TrimSpace(part), `"'`)
+			if part == "" {
+				continue
+			}
+			if i > 0 && len(ids) > 0 && !LooksLikeNodeID(part) {
+				ids[len(ids)-1] += "," + part
+				continue
+			}
+			ids = append(ids, part)
+		}
+	}
+	return ids
+}
+
+// LooksLikeNodeID reports whether s starts with a NodeID prefix (ns=, nsu=, i=, s=, g= or b=).
+func LooksLikeNodeID(s string) bool {
+	for _, p := range []string{"ns=", "nsu=", "i=", "s=", "g=", "b="} {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package opc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PrecheckError is a failed DNS or TCP pre-check of an endpoint. It wraps the network error, so
+// ClassifyDialError sees the cause.
+type PrecheckError struct {
+	Stage string // "dns" or "tcp"
+	Host  string
+	Addr  string // host:port dialed in the tcp stage
+	Err   error
+}
+
+func (e *PrecheckError) Error() string {
+	if e.Stage == "dns" {
+		return fmt.Sprintf("DNS lookup of %s failed: %v", e.Host, e.Err)
+	}
+	return fmt.Sprintf("TCP port %s not reachable: %v", e.Addr, e.Err)
+}
+
+func (e *PrecheckError) Unwrap() error { return e.Err }
+
+// EndpointPrecheck is the result of PrecheckEndpoint.
+type EndpointPrecheck struct {
+	Host        string
+	Addrs       []string // resolved addresses; the host itself when it is an IP
+	Dialed      string   // address that accepted the TCP connection
+	ResolveTime time.Duration
+	ConnectTime time.Duration
+}
+
+// PrecheckEndpoint resolves the host of endpoint and opens (and closes) a TCP connection to its
+// port, so that name resolution and reachability problems are reported as such before the
+// OPC UA handshake starts. The resolved addresses are tried in order.
+func PrecheckEndpoint(ctx context.Context, endpoint string) (*EndpointPrecheck, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL %q: %w", endpoint, err)
+	}
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = defaultPorts[strings.ToLower(u.Scheme)]
+	}
+	p := &EndpointPrecheck{Host: host}
+
+	start := time.Now()
+	// A link-local IPv6 address may carry a zone ("fe80::1%eth0"), which ParseIP rejects
+	ipPart, _, _ := strings.Cut(host, "%")
+	if ip := net.ParseIP(ipPart); ip != nil {
+		p.Addrs = []string{host}
+	} else {
+		var resolver net.Resolver
+		addrs, err := resolver.LookupIPAddr(ctx, host)
+		if err == nil && len(addrs) == 0 {
+			err = &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		if err != nil {
+			return nil, &PrecheckError{Stage: "dns", Host: host, Err: err}
+		}
+		for _, a := range addrs {
+			p.Addrs = append(p.Addrs, a.String())
+		}
+	}
+	p.ResolveTime = time.Since(start)
+
+	start = time.Now()
+	var dialer net.Dialer
+	var lastErr error
+	for _, a := range p.Addrs {
+		addr := net.JoinHostPort(a, port)
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			lastErr = &PrecheckError{Stage: "tcp", Host: host, Addr: addr, Err: err}
+			continue
+		}
+		_ = conn.Close()
+		p.Dialed = addr
+		p.ConnectTime = time.Since(start)
+		return p, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,95 @@
+package opc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua"
+)
+
+// EndpointProbe is the result of a lightweight reachability test of an endpoint.
+type EndpointProbe struct {
+	Endpoint        string
+	ChannelOpenTime time.Duration // TCP connect, Hello/Acknowledge and OpenSecureChannel (SecurityPolicy None)
+	RoundTrip       time.Duration // GetEndpoints request over the open secure channel
+	CloseTime       time.Duration // CloseSecureChannel
+	ApplicationName string
+	ApplicationURI  string
+	ProductURI      string
+	Policies        []string // "Policy/Mode" pairs offered by the server
+	UserTokens      []string // supported user identity token types
+}
+
+// ProbeEndpoint opens and closes an unsecured secure channel to endpoint and issues a single
+// GetEndpoints request on it. No session is created, so the probe works without credentials
+// or a trusted client certificate.
+func ProbeEndpoint(ctx context.Context, endpoint string) (*EndpointProbe, error) {
+	c, err := opcua.NewClient(endpoint, opcua.AutoReconnect(false))
+	if err != nil {
+		return nil, err
+	}
+	p := &EndpointProbe{Endpoint: endpoint}
+	tc := &Client{endpoint: endpoint}
+
+	start := time.Now()
+	if err := c.Dial(ctx); err != nil {
+		tc.traceCall("OpenSecureChannel", start, nil, 0, err)
+		return nil, fmt.Errorf("open secure channel: %w", err)
+	}
+	p.ChannelOpenTime = time.Since(start)
+	tc.traceCall("OpenSecureChannel", start, nil, 0, nil)
+
+	start = time.Now()
+	res, err := c.GetEndpoints(ctx)
+	p.RoundTrip = time.Since(start)
+	tc.traceCall("GetEndpoints", start, responseHeader(res), 0, err)
+
+	start = time.Now()
+	cerr := c.Close(ctx)
+	p.CloseTime = time.Since(start)
+	tc.traceCall("CloseSecureChannel", start, nil, 0, cerr)
+	if err != nil {
+		return nil, fmt.Errorf("GetEndpoints: %w", err)
+	}
+
+	policies := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for _, ep := range res.Endpoints {
+		if ep == nil {
+			continue
+		}
+		if p.ApplicationURI == "" && ep.Server != nil {
+			p.ApplicationURI = ep.Server.ApplicationURI
+			p.ProductURI = ep.Server.ProductURI
+			if ep.Server.ApplicationName != nil {
+				p.ApplicationName = ep.Server.ApplicationName.Text
+			}
+		}
+		policy := ep.SecurityPolicyURI
+		if i := strings.LastIndex(policy, "#"); i >= 0 {
+			policy = policy[i+1:]
+		}
+		mode := strings.TrimPrefix(ep.SecurityMode.String(), "MessageSecurityMode")
+		policies[policy+"/"+mode] = true
+		for _, tok := range ep.UserIdentityTokens {
+			if tok != nil {
+				tokens[strings.TrimPrefix(tok.TokenType.String(), "UserTokenType")] = true
+			}
+		}
+	}
+	p.Policies = sortedKeys(policies)
+	p.UserTokens = sortedKeys(tokens)
+	return p, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,223 @@
+package opc
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ProfileFormat identifies exported connection profiles; ProfileVersion is bumped on incompatible
+// changes.
+const (
+	ProfileFormat  = "opcuababy-profile"
+	ProfileVersion = 1
+)
+
+// ProfileTextPrefix starts the compact text form of a profile used for QR codes and the clipboard.
+const ProfileTextPrefix = ProfileFormat + ":"
+
+// ConnectionProfile is the part of a Config needed to reach one server, in a form that can be
+// moved to another device. Passwords are never part of a profile. The client certificate and its
+// private key travel only when explicitly included; without them the importing device keeps its
+// own certificate and ApplicationURI, which must match it.
+type ConnectionProfile struct {
+	Format            string   `json:"format"`
+	Version           int      `json:"version"`
+	Name              string   `json:"name,omitempty"`
+	EndpointURL       string   `json:"endpoint_url"`
+	HostOverride      string   `json:"host_override,omitempty"`
+	SecurityPolicy    string   `json:"security_policy,omitempty"`
+	SecurityMode      string   `json:"security_mode,omitempty"`
+	AuthMode          string   `json:"auth_mode,omitempty"`
+	Username          string   `json:"username,omitempty"`
+	UserTokenPolicyID string   `json:"user_token_policy_id,omitempty"`
+	SessionName       string   `json:"session_name,omitempty"`
+	SessionTimeout    uint32   `json:"session_timeout,omitempty"`
+	ConnectTimeout    float64  `json:"connect_timeout,omitempty"`
+	RetryAttempts     int      `json:"retry_attempts,omitempty"`
+	RetryDelaySeconds float64  `json:"retry_delay_seconds,omitempty"`
+	LocaleIDs         []string `json:"locale_ids,omitempty"`
+	// Certificate bundle, present only when exported with certificates. The files are kept as
+	// they were (DER or PEM); the extensions are used when writing them back.
+	ApplicationURI string `json:"application_uri,omitempty"`
+	ProductURI     string `json:"product_uri,omitempty"`
+	Certificate    []byte `json:"certificate,omitempty"`
+	CertificateExt string `json:"certificate_ext,omitempty"`
+	PrivateKey     []byte `json:"private_key,omitempty"`
+	PrivateKeyExt  string `json:"private_key_ext,omitempty"`
+	// Notes are the node notes of the exporting device (see Config.NodeNotes), present only when
+	// exported with notes.
+	Notes map[string]string `json:"notes,omitempty"`
+}
+
+// HasCertificate reports whether the profile carries a client certificate and key.
+func (p *ConnectionProfile) HasCertificate() bool {
+	return len(p.Certificate) > 0 && len(p.PrivateKey) > 0
+}
+
+// ExportProfile returns the connection settings of c as a profile named name. With includeCerts
+// the configured client certificate and private key files are embedded.
+func (c *Config) ExportProfile(name string, includeCerts bool) (*ConnectionProfile, error) {
+	if strings.TrimSpace(c.EndpointURL) == "" {
+		return nil, errors.New("no endpoint URL configured")
+	}
+	p := &ConnectionProfile{
+		Format:            ProfileFormat,
+		Version:           ProfileVersion,
+		Name:              strings.TrimSpace(name),
+		EndpointURL:       c.EndpointURL,
+		HostOverride:      c.HostOverride,
+		SecurityPolicy:    c.SecurityPolicy,
+		SecurityMode:      c.SecurityMode,
+		AuthMode:          c.AuthMode,
+		Username:          c.Username,
+		UserTokenPolicyID: c.UserTokenPolicyID,
+		SessionName:       c.SessionName,
+		SessionTimeout:    c.SessionTimeout,
+		ConnectTimeout:    c.ConnectTimeout,
+		RetryAttempts:     c.RetryAttempts,
+		RetryDelaySeconds: c.RetryDelaySeconds,
+		LocaleIDs:         slices.Clone(c.LocaleIDs),
+	}
+	if !includeCerts {
+		return p, nil
+	}
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("no client certificate configured")
+	}
+	var err error
+	if p.Certificate, err = os.ReadFile(c.CertFile); err != nil {
+		return nil, fmt.Errorf("read certificate: %w", err)
+	}
+	if p.PrivateKey, err = os.ReadFile(c.KeyFile); err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	p.CertificateExt = filepath.Ext(c.CertFile)
+	p.PrivateKeyExt = filepath.Ext(c.KeyFile)
+	p.ApplicationURI = c.ApplicationURI
+	p.ProductURI = c.ProductURI
+	return p, nil
+}
+
+// MarshalText returns the compact form ProfileTextPrefix + base64url(deflate(JSON)), small
+// enough for a QR code when the profile carries no certificate.
+func (p *ConnectionProfile) MarshalText() (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return ProfileTextPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ParseProfile reads a profile from its JSON file form or its compact text form.
+func ParseProfile(data []byte) (*ConnectionProfile, error) {
+	s := strings.TrimSpace(string(data))
+	if rest, ok := strings.CutPrefix(s, ProfileTextPrefix); ok {
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(rest, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid profile text: %w", err)
+		}
+		if data, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(raw)), 1<<20)); err != nil {
+			return nil, fmt.Errorf("invalid profile text: %w", err)
+		}
+	}
+	var p ConnectionProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	if p.Format != ProfileFormat {
+		return nil, errors.New("not an OPC UA Baby connection profile")
+	}
+	if p.Version > ProfileVersion {
+		return nil, fmt.Errorf("profile version %d is newer than supported version %d", p.Version, ProfileVersion)
+	}
+	if strings.TrimSpace(p.EndpointURL) == "" {
+		return nil, errors.New("profile has no endpoint URL")
+	}
+	if err := CheckEndpointScheme(p.EndpointURL); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+var profileFileName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ApplyProfile copies the settings of p into c. A certificate carried by the profile is written
+// to certDir and replaces the configured one; notes it carries replace those of the same nodes.
+// The stored password is kept only when the username is unchanged.
+func (c *Config) ApplyProfile(p *ConnectionProfile, certDir string) error {
+	if p.HasCertificate() {
+		base := profileFileName.ReplaceAllString(p.Name, "_")
+		if base == "" || base == "_" {
+			base = "profile"
+		}
+		certExt, keyExt := p.CertificateExt, p.PrivateKeyExt
+		if certExt == "" {
+			certExt = ".der"
+		}
+		if keyExt == "" {
+			keyExt = ".key"
+		}
+		if err := os.MkdirAll(certDir, 0o700); err != nil {
+			return err
+		}
+		certPath := filepath.Join(certDir, base+"_client"+filepath.Base(certExt))
+		keyPath := filepath.Join(certDir, base+"_client"+filepath.Base(keyExt))
+		if certPath == keyPath {
+			keyPath += ".key"
+		}
+		if err := os.WriteFile(certPath, p.Certificate, 0o644); err != nil {
+			return fmt.Errorf("write certificate: %w", err)
+		}
+		if err := os.WriteFile(keyPath, p.PrivateKey, 0o600); err != nil {
+			return fmt.Errorf("write private key: %w", err)
+		}
+		c.CertFile, c.KeyFile = certPath, keyPath
+		c.ApplicationURI = p.ApplicationURI
+		c.ProductURI = p.ProductURI
+	}
+	if len(p.Notes) > 0 {
+		if c.NodeNotes == nil {
+			c.NodeNotes = make(map[string]string, len(p.Notes))
+		}
+		maps.Copy(c.NodeNotes, p.Notes)
+	}
+	if p.Username != c.Username {
+		c.Password = ""
+	}
+	c.EndpointURL = p.EndpointURL
+	c.HostOverride = p.HostOverride
+	c.SecurityPolicy = p.SecurityPolicy
+	c.SecurityMode = p.SecurityMode
+	c.AuthMode = p.AuthMode
+	c.Username = p.Username
+	c.UserTokenPolicyID = p.UserTokenPolicyID
+	c.SessionName = p.SessionName
+	c.SessionTimeout = p.SessionTimeout
+	c.ConnectTimeout = p.ConnectTimeout
+	c.RetryAttempts = p.RetryAttempts
+	c.RetryDelaySeconds = p.RetryDelaySeconds
+	c.LocaleIDs = slices.Clone(p.LocaleIDs)
+	return nil
+}
@@ -0,0 +1,66 @@
+package opc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gopcua/opcua"
+)
+
+// rateGroup is the subscription of the watch items requesting one sampling rate. Each group
+// publishes at its own interval, so slow items are not reported at the rate of fast ones.
+type rateGroup struct {
+	interval time.Duration // 0 for the default group
+	sub      *opcua.Subscription
+	ch       chan *opcua.PublishNotificationData
+	items    int
+	stats    subscriptionCounters
+	lostID   uint32 // SubscriptionID when the connection was lost
+}
+
+// rateGroupLocked returns the rate group of interval, creating its subscription on first use.
+// Callers must hold c.mu.
+func (c *Client) rateGroupLocked(interval time.Duration) (*rateGroup, error) {
+	if interval < 0 {
+		interval = 0
+	}
+	if g, ok := c.groups[interval]; ok {
+		return g, nil
+	}
+	g := &rateGroup{interval: interval, ch: make(chan *opcua.PublishNotificationData, 100)}
+	start := time.Now()
+	params := c.subscriptionParams(interval)
+	sub, err := c.Client.Subscribe(context.Background(), &params, g.ch)
+	c.traceCall("CreateSubscription", start, nil, 1, err)
+	if err != nil {
+		return nil, err
+	}
+	g.sub = sub
+	c.groups[interval] = g
+	go c.handleDataChanges(g)
+	return g, nil
+}
+
+// dropRateGroupLocked cancels the subscription of g once it has no items left. Callers must
+// hold c.mu.
+func (c *Client) dropRateGroupLocked(g *rateGroup) {
+	if g.items > 0 || c.groups[g.interval] != g {
+		return
+	}
+	delete(c.groups, g.interval)
+	_ = g.sub.Cancel(context.Background())
+}
+
+// RateGroups returns the sampling intervals that currently have a subscription, in ascending
+// order; 0 stands for the default group.
+func (c *Client) RateGroups() []time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]time.Duration, 0, len(c.groups))
+	for d := range c.groups {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
@@ -0,0 +1,159 @@
+package opc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua"
+)
+
+// The OPC UA stack reconnects on its own: it recreates the secure channel, reactivates or
+// recreates the session and moves the subscriptions to the new session with
+// TransferSubscriptions, republishing missed notifications. Only subscriptions the server has
+// dropped (e.g. their lifetime expired) are created again, monitored item by monitored item.
+
+// DefaultReconnectDelay is the countdown before the application reconnects on its own when the
+// stack has not restored the connection, used when Config.ReconnectDelaySeconds is zero.
+const DefaultReconnectDelay = 15 * time.Second
+
+// ReconnectDelay returns the countdown before an automatic reconnect. It is safe to call on a
+// nil Config.
+func (c *Config) ReconnectDelay() time.Duration {
+	if c == nil {
+		return DefaultReconnectDelay
+	}
+	return secondsOr(c.ReconnectDelaySeconds, DefaultReconnectDelay)
+}
+
+// SubscriptionRecovery tells how a Client's subscription came through a reconnect.
+type SubscriptionRecovery int
+
+const (
+	// NoSubscription: the client had no subscription when the connection was lost.
+	NoSubscription SubscriptionRecovery = iota
+	// SubscriptionTransferred: the server kept the subscription and it was adopted by the
+	// restored or new session; its monitored items were not recreated.
+	SubscriptionTransferred
+	// SubscriptionRecreated: the server had dropped the subscription; it and its monitored
+	// items were created again.
+	SubscriptionRecreated
+)
+
+// ReconnectHandler is implemented by Handlers that want to know about automatic reconnects.
+// HandleReconnectAborted is called when the stack gives up reconnecting, e.g. because the
+// server refused the connection; the Client is unusable then.
+type ReconnectHandler interface {
+	HandleConnectionLost()
+	HandleReconnected(sub SubscriptionRecovery)
+	HandleReconnectAborted()
+}
+
+// stateFeed forwards the connection states of one session to the Clients using it.
+type stateFeed struct {
+	done    chan struct{}
+	clients map[*Client]struct{}
+}
+
+var stateFeeds = struct {
+	sync.Mutex
+	m map[*opcua.Client]*stateFeed
+}{m: make(map[*opcua.Client]*stateFeed)}
+
+// watchState consumes the state channel of cli until closeStateFeed. It must keep running
+// while cli is closed, as the stack blocks until the Closed state is read.
+func watchState(cli *opcua.Client, ch <-chan opcua.ConnState) {
+	feed := &stateFeed{done: make(chan struct{}), clients: make(map[*Client]struct{})}
+	stateFeeds.Lock()
+	stateFeeds.m[cli] = feed
+	stateFeeds.Unlock()
+	go func() {
+		for {
+			select {
+			case <-feed.done:
+				return
+			case st := <-ch:
+				stateFeeds.Lock()
+				clients := make([]*Client, 0, len(feed.clients))
+				for c := range feed.clients {
+					clients = append(clients, c)
+				}
+				stateFeeds.Unlock()
+				for _, c := range clients {
+					c.handleState(st)
+				}
+			}
+		}
+	}()
+}
+
+// followState adds (or with on false removes) c to the receivers of cli's states.
+func followState(cli *opcua.Client, c *Client, on bool) {
+	stateFeeds.Lock()
+	defer stateFeeds.Unlock()
+	if feed, ok := stateFeeds.m[cli]; ok {
+		if on {
+			feed.clients[c] = struct{}{}
+		} else {
+			delete(feed.clients, c)
+		}
+	}
+}
+
+// closeStateFeed stops forwarding the states of cli once it is closed.
+func closeStateFeed(cli *opcua.Client) {
+	stateFeeds.Lock()
+	defer stateFeeds.Unlock()
+	if feed, ok := stateFeeds.m[cli]; ok {
+		close(feed.done)
+		delete(stateFeeds.m, cli)
+	}
+}
+
+func (c *Client) handleState(st opcua.ConnState) {
+	c.mu.Lock()
+	var lost, restored, aborted bool
+	recovery := NoSubscription
+	switch st {
+	case opcua.Disconnected, opcua.Reconnecting:
+		if !c.reconnecting {
+			c.reconnecting, lost = true, true
+			for _, g := range c.groups {
+				g.lostID = g.sub.SubscriptionID
+			}
+		}
+	case opcua.Connected:
+		if c.reconnecting {
+			c.reconnecting, restored = false, true
+			// One subscription the server did not keep means the watch values were missed
+			for _, g := range c.groups {
+				switch {
+				case g.lostID == 0:
+				case g.sub.SubscriptionID != g.lostID:
+					recovery = SubscriptionRecreated
+				case recovery == NoSubscription:
+					recovery = SubscriptionTransferred
+				}
+				g.lostID = 0
+			}
+		}
+	case opcua.Closed:
+		// Disconnect stops following the states before closing, so this is the stack
+		if c.reconnecting {
+			c.reconnecting, aborted = false, true
+		}
+	}
+	h, _ := c.Handler.(ReconnectHandler)
+	c.mu.Unlock()
+	if h == nil {
+		return
+	}
+	if lost {
+		h.HandleConnectionLost()
+	}
+	if restored {
+		h.HandleReconnected(recovery)
+	}
+	if aborted {
+		h.HandleReconnectAborted()
+	}
+}
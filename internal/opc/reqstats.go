@@ -0,0 +1,168 @@
+package opc
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// ServiceStats are the calls of one OPC UA service made by a Client.
+type ServiceStats struct {
+	Service       string        `json:"service"`
+	Requests      uint64        `json:"requests"`
+	Errors        uint64        `json:"errors"`
+	TotalLatency  time.Duration `json:"-"`
+	AvgLatencyMs  float64       `json:"avg_latency_ms"`
+	MaxLatencyMs  float64       `json:"max_latency_ms"`
+	BytesSent     uint64        `json:"bytes_sent"`
+	BytesReceived uint64        `json:"bytes_received"`
+}
+
+// ChannelStats is the load a Client placed on its server since it was created. Byte counts are
+// encoded request and response bodies (including received notifications) and leave out the
+// secure channel's framing, signing and encryption overhead, so they are a lower bound.
+type ChannelStats struct {
+	Since         time.Time      `json:"since"`
+	Requests      uint64         `json:"requests"`
+	Errors        uint64         `json:"errors"`
+	AvgLatencyMs  float64        `json:"avg_latency_ms"`
+	BytesSent     uint64         `json:"bytes_sent"`
+	BytesReceived uint64         `json:"bytes_received"`
+	Notifications uint64         `json:"notifications"`
+	Services      []ServiceStats `json:"services"` // sorted by service name
+}
+
+// requestStats collects ChannelStats.
+type requestStats struct {
+	mu            sync.Mutex
+	since         time.Time
+	services      map[string]*ServiceStats
+	notifications uint64
+	notifBytes    uint64
+	lastErr       error // most recent failed request or Publish, see Client.LastError
+	lastErrAt     time.Time
+}
+
+func (s *requestStats) service(name string) *ServiceStats {
+	if s.services == nil {
+		s.services = make(map[string]*ServiceStats)
+	}
+	if s.since.IsZero() {
+		s.since = time.Now()
+	}
+	st, ok := s.services[name]
+	if !ok {
+		st = &ServiceStats{Service: name}
+		s.services[name] = st
+	}
+	return st
+}
+
+func (s *requestStats) call(service string, d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.service(service)
+	st.Requests++
+	if err != nil {
+		st.Errors++
+		s.lastErr, s.lastErrAt = err, time.Now()
+	}
+	st.TotalLatency += d
+	if ms := float64(d) / float64(time.Millisecond); ms > st.MaxLatencyMs {
+		st.MaxLatencyMs = ms
+	}
+}
+
+// bytes adds the encoded sizes of a request and its response (either may be nil).
+func (s *requestStats) bytes(service string, req, resp interface{}) {
+	sent, received := encodedSize(req), encodedSize(resp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.service(service)
+	st.BytesSent += sent
+	st.BytesReceived += received
+}
+
+// failed records an error not seen as an individual call, such as a failed Publish.
+func (s *requestStats) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr, s.lastErrAt = err, time.Now()
+}
+
+// recentError returns the last error if it occurred within d, or nil.
+func (s *requestStats) recentError(d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastErr == nil || time.Since(s.lastErrAt) > d {
+		return nil
+	}
+	return s.lastErr
+}
+
+// notification adds a data change notification delivered by the stack's Publish requests,
+// which are not seen as individual calls.
+func (s *requestStats) notification(n interface{}) {
+	size := encodedSize(n)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.since.IsZero() {
+		s.since = time.Now()
+	}
+	s.notifications++
+	s.notifBytes += size
+}
+
+func (s *requestStats) snapshot() *ChannelStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cs := &ChannelStats{Since: s.since, Notifications: s.notifications, BytesReceived: s.notifBytes}
+	var total time.Duration
+	for _, st := range s.services {
+		out := *st
+		if out.Requests > 0 {
+			out.AvgLatencyMs = float64(out.TotalLatency) / float64(out.Requests) / float64(time.Millisecond)
+		}
+		cs.Requests += out.Requests
+		cs.Errors += out.Errors
+		cs.BytesSent += out.BytesSent
+		cs.BytesReceived += out.BytesReceived
+		total += out.TotalLatency
+		if out.Requests > 0 {
+			cs.Services = append(cs.Services, out)
+		}
+	}
+	if cs.Requests > 0 {
+		cs.AvgLatencyMs = float64(total) / float64(cs.Requests) / float64(time.Millisecond)
+	}
+	sort.Slice(cs.Services, func(i, j int) bool { return cs.Services[i].Service < cs.Services[j].Service })
+	return cs
+}
+
+func encodedSize(v interface{}) uint64 {
+	if v == nil {
+		return 0
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return 0
+	}
+	b, err := ua.Encode(v)
+	if err != nil {
+		return 0
+	}
+	return uint64(len(b))
+}
+
+// ChannelStats returns the requests, latencies and bytes of this client so far.
+func (c *Client) ChannelStats() *ChannelStats {
+	return c.reqStats.snapshot()
+}
+
+// LastError returns the most recent error of a request or of a subscription's Publish if it
+// occurred within d, e.g. the cause of a lost connection; nil otherwise.
+func (c *Client) LastError(d time.Duration) error {
+	return c.reqStats.recentError(d)
+}
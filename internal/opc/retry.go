@@ -0,0 +1,135 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// Defaults used when the retry fields of Config are zero.
+const (
+	DefaultRetryAttempts     = 3
+	DefaultRetryDelay        = time.Second
+	DefaultRetryMaxDelay     = 30 * time.Second
+	DefaultRequestRetryDelay = 200 * time.Millisecond
+)
+
+// RetryPolicy retries a failed operation with exponential backoff: the wait before the n-th
+// retry is Delay·2^(n-1), capped at MaxDelay, with ±20 % jitter so that many clients losing the
+// same server do not come back in lockstep.
+type RetryPolicy struct {
+	Attempts   int           // tries including the first one; 1 or less disables retries
+	Delay      time.Duration // wait before the first retry
+	MaxDelay   time.Duration // cap of the wait between tries; zero means no cap
+	MaxElapsed time.Duration // no retry is started after this long; zero means no limit
+}
+
+// ConnectRetryPolicy returns the policy of Connect from RetryAttempts, RetryDelaySeconds,
+// RetryMaxDelaySeconds and RetryMaxElapsedSeconds. It is safe to call on a nil Config.
+func (c *Config) ConnectRetryPolicy() RetryPolicy {
+	p := RetryPolicy{Attempts: DefaultRetryAttempts, Delay: DefaultRetryDelay, MaxDelay: DefaultRetryMaxDelay}
+	if c == nil {
+		return p
+	}
+	if c.RetryAttempts > 0 {
+		p.Attempts = c.RetryAttempts
+	}
+	p.Delay = secondsOr(c.RetryDelaySeconds, DefaultRetryDelay)
+	p.MaxDelay = secondsOr(c.RetryMaxDelaySeconds, DefaultRetryMaxDelay)
+	p.MaxElapsed = secondsOr(c.RetryMaxElapsedSeconds, 0)
+	return p
+}
+
+// RequestRetryPolicy returns the policy applied to transient failures of Read, Browse and Write
+// requests. Request retries are off unless RequestRetryAttempts is above 1. It is safe to call on
+// a nil Config.
+func (c *Config) RequestRetryPolicy() RetryPolicy {
+	if c == nil || c.RequestRetryAttempts <= 1 {
+		return RetryPolicy{Attempts: 1}
+	}
+	return RetryPolicy{
+		Attempts:   c.RequestRetryAttempts,
+		Delay:      secondsOr(c.RequestRetryDelaySeconds, DefaultRequestRetryDelay),
+		MaxDelay:   secondsOr(c.RetryMaxDelaySeconds, DefaultRetryMaxDelay),
+		MaxElapsed: secondsOr(c.RetryMaxElapsedSeconds, 0),
+	}
+}
+
+// Backoff returns the jittered wait before retry number n (1 for the first retry).
+func (p RetryPolicy) Backoff(n int) time.Duration {
+	d := p.Delay
+	for i := 1; i < n && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(float64(d) * (0.8 + 0.4*rand.Float64()))
+}
+
+// Do calls call until it succeeds, returns an error retryable rejects, the attempts or the
+// elapsed time are used up, or ctx ends. onRetry, if set, is told about each failed try before
+// the wait. The error of the last try is returned.
+func (p RetryPolicy) Do(ctx context.Context, retryable func(error) bool, call func() error, onRetry func(attempt int, wait time.Duration, err error)) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= p.Attempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		wait := p.Backoff(attempt)
+		if p.MaxElapsed > 0 && time.Since(start)+wait > p.MaxElapsed {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt, wait, err)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// IsTransientStatus reports whether err is a service fault a server returns when it is
+// temporarily overloaded or slow. When idempotent is false only faults the server raises before
+// executing the request count, so a retried Write can never be applied twice.
+func IsTransientStatus(err error, idempotent bool) bool {
+	var sc ua.StatusCode
+	if !errors.As(err, &sc) {
+		return false
+	}
+	switch sc {
+	case ua.StatusBadTooManyOperations, ua.StatusBadTCPServerTooBusy, ua.StatusBadResourceUnavailable, ua.StatusBadTooManySessions:
+		return true
+	case ua.StatusBadTimeout, ua.StatusBadRequestTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// SetRequestRetry sets the retry policy of Read, Browse and Write requests (see
+// Config.RequestRetryPolicy).
+func (c *Client) SetRequestRetry(p RetryPolicy) {
+	c.retryMu.Lock()
+	c.retry = p
+	c.retryMu.Unlock()
+}
+
+// withRetry runs one service call under the request retry policy. Retries are counted in the
+// request stats like any other call.
+func (c *Client) withRetry(ctx context.Context, idempotent bool, call func() error) error {
+	c.retryMu.Lock()
+	p := c.retry
+	c.retryMu.Unlock()
+	if p.Attempts <= 1 {
+		return call()
+	}
+	return p.Do(ctx, func(err error) bool { return IsTransientStatus(err, idempotent) }, call, nil)
+}
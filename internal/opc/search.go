@@ -0,0 +1,123 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// TranslateBrowsePaths resolves browse paths to NodeIDs on the server.
+func (c *Client) TranslateBrowsePaths(ctx context.Context, paths []*ua.BrowsePath) ([]*ua.BrowsePathResult, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, errors.New("client not connected")
+	}
+
+	req := &ua.TranslateBrowsePathsToNodeIDsRequest{BrowsePaths: paths}
+	var resp *ua.TranslateBrowsePathsToNodeIDsResponse
+	err := c.withRetry(ctx, true, func() error {
+		start := time.Now()
+		err := c.Client.Send(ctx, req, func(v ua.Response) error {
+			r, ok := v.(*ua.TranslateBrowsePathsToNodeIDsResponse)
+			if !ok {
+				return fmt.Errorf("unexpected response type %T", v)
+			}
+			resp = r
+			return nil
+		})
+		c.traceCall("TranslateBrowsePathsToNodeIDs", start, responseHeader(resp), len(paths), err)
+		c.reqStats.bytes("TranslateBrowsePathsToNodeIDs", req, resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// QueryNode is a node returned by QueryBrowseName.
+type QueryNode struct {
+	NodeID     string
+	BrowseName string
+}
+
+// QueryBrowseName asks the server's Query service for Objects and Variables whose BrowseName
+// matches the Like pattern (e.g. "%Temp%"), returning at most max nodes. more reports that the
+// server had further matches. Most servers do not implement the service and fail with
+// BadServiceUnsupported.
+func (c *Client) QueryBrowseName(ctx context.Context, pattern string, max uint32) (nodes []QueryNode, more bool, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Client == nil {
+		return nil, false, errors.New("client not connected")
+	}
+
+	browseName := []*ua.QueryDataDescription{{RelativePath: &ua.RelativePath{}, AttributeID: ua.AttributeIDBrowseName}}
+	nodeType := func(typeID uint32) *ua.NodeTypeDescription {
+		return &ua.NodeTypeDescription{
+			TypeDefinitionNode: ua.NewNumericExpandedNodeID(0, typeID),
+			IncludeSubTypes:    true,
+			DataToReturn:       browseName,
+		}
+	}
+	req := &ua.QueryFirstRequest{
+		View:      &ua.ViewDescription{ViewID: ua.NewTwoByteNodeID(0)},
+		NodeTypes: []*ua.NodeTypeDescription{nodeType(id.BaseObjectType), nodeType(id.BaseVariableType)},
+		Filter: &ua.ContentFilter{Elements: []*ua.ContentFilterElement{{
+			FilterOperator: ua.FilterOperatorLike,
+			FilterOperands: []*ua.ExtensionObject{
+				ua.NewExtensionObject(&ua.AttributeOperand{
+					NodeID:      ua.NewNumericNodeID(0, id.BaseObjectType),
+					BrowsePath:  &ua.RelativePath{},
+					AttributeID: ua.AttributeIDBrowseName,
+				}),
+				ua.NewExtensionObject(&ua.LiteralOperand{Value: ua.MustVariant(pattern)}),
+			},
+		}}},
+		MaxDataSetsToReturn: max,
+	}
+	var resp *ua.QueryFirstResponse
+	start := time.Now()
+	err = c.Client.Send(ctx, req, func(v ua.Response) error {
+		r, ok := v.(*ua.QueryFirstResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response type %T", v)
+		}
+		resp = r
+		return nil
+	})
+	c.traceCall("QueryFirst", start, responseHeader(resp), 1, err)
+	c.reqStats.bytes("QueryFirst", req, resp)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.ResponseHeader != nil && resp.ResponseHeader.ServiceResult != ua.StatusOK {
+		return nil, false, resp.ResponseHeader.ServiceResult
+	}
+	for _, ds := range resp.QueryDataSets {
+		if ds == nil || ds.NodeID == nil || ds.NodeID.NodeID == nil {
+			continue
+		}
+		n := QueryNode{NodeID: ds.NodeID.NodeID.String()}
+		if len(ds.Values) > 0 && ds.Values[0] != nil {
+			if qn, ok := ds.Values[0].Value().(*ua.QualifiedName); ok && qn != nil {
+				n.BrowseName = qn.Name
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	if len(resp.ContinuationPoint) > 0 {
+		more = true
+		// Only the first page is used; let the server free the rest
+		release := &ua.QueryNextRequest{ReleaseContinuationPoint: true, ContinuationPoint: resp.ContinuationPoint}
+		_ = c.Client.Send(ctx, release, func(ua.Response) error { return nil })
+	}
+	return nodes, more, nil
+}
@@ -0,0 +1,47 @@
+package opc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// RequestServerStateChange asks the server to change its state, e.g. to Shutdown with restart
+// set to reboot it after secondsTillShutdown (Part 5, Server.RequestServerStateChange).
+func (c *Client) RequestServerStateChange(ctx context.Context, state ua.ServerState, estimatedReturn time.Time, secondsTillShutdown uint32, reason *ua.LocalizedText, restart bool) error {
+	_, err := c.CallMethod(ctx, ua.NewNumericNodeID(0, id.Server),
+		ua.NewNumericNodeID(0, id.Server_RequestServerStateChange),
+		int32(state), estimatedReturn, secondsTillShutdown, reason, restart)
+	return err
+}
+
+// MethodInputArguments returns the InputArguments property of methodID; methods without the
+// property take no arguments.
+func (c *Client) MethodInputArguments(ctx context.Context, methodID *ua.NodeID) ([]*ua.Argument, error) {
+	refs, err := c.Browse(ctx, methodID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if ref.BrowseName == nil || ref.BrowseName.Name != "InputArguments" || ref.NodeID == nil {
+			continue
+		}
+		dvs, err := c.ReadValues(ctx, []*ua.NodeID{ref.NodeID.NodeID})
+		if err != nil {
+			return nil, err
+		}
+		var args []*ua.Argument
+		if len(dvs) > 0 && dvs[0] != nil && dvs[0].Value != nil {
+			objs, _ := dvs[0].Value.Value().([]*ua.ExtensionObject)
+			for _, eo := range objs {
+				if a, ok := eo.Value.(*ua.Argument); ok {
+					args = append(args, a)
+				}
+			}
+		}
+		return args, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package opc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+// sessions holds the OPC UA sessions that Clients share (see Client.ShareSession), so several
+// controllers in one process (e.g. gateway instances) targeting the same endpoint with the same
+// security and identity use a single session on the server.
+var sessions = struct {
+	sync.Mutex
+	m map[string]*sharedSession
+}{m: make(map[string]*sharedSession)}
+
+type sharedSession struct {
+	cli  *opcua.Client
+	refs int
+}
+
+// SessionKey identifies a session by endpoint URL, security policy and mode and the user and
+// application identity and locales of cfg. Passwords are only kept as a hash.
+func SessionKey(endpoint, policyURI string, mode ua.MessageSecurityMode, authMode string, cfg *Config) string {
+	parts := []string{strings.TrimRight(endpoint, "/"), policyURI, mode.String(), authMode}
+	if cfg != nil {
+		if authMode == "Username" {
+			sum := sha256.Sum256([]byte(cfg.Password))
+			parts = append(parts, cfg.Username, hex.EncodeToString(sum[:]))
+		}
+		parts = append(parts, cfg.CertFile, cfg.KeyFile, cfg.ApplicationURI, cfg.SessionName, strings.Join(cfg.LocaleIDs, ","))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// ShareSession makes Connect reuse a live session opened by another Client with the same key
+// instead of creating a new one, and Disconnect close the session only when its last user
+// disconnects. Each Client keeps its own subscription. Call it before Connect.
+func (c *Client) ShareSession(key string) {
+	c.shareKey = key
+}
+
+// SharedSession reports whether the client uses a session together with other Clients and how
+// many Clients currently use it.
+func (c *Client) SharedSession() (bool, int) {
+	c.mu.RLock()
+	cli := c.Client
+	c.mu.RUnlock()
+	if c.shareKey == "" || cli == nil {
+		return false, 0
+	}
+	sessions.Lock()
+	defer sessions.Unlock()
+	if s, ok := sessions.m[c.shareKey]; ok && s.cli == cli {
+		return s.refs > 1, s.refs
+	}
+	return false, 0
+}
+
+// acquireSession returns the live session registered for key, counting the new user.
+func acquireSession(key string) *opcua.Client {
+	sessions.Lock()
+	defer sessions.Unlock()
+	s, ok := sessions.m[key]
+	if !ok {
+		return nil
+	}
+	switch s.cli.State() {
+	case opcua.Connected, opcua.Reconnecting:
+		s.refs++
+		return s.cli
+	}
+	return nil
+}
+
+// registerSession records a newly connected session for key unless a live one is already
+// registered (two Clients connected at the same time). It reports whether cli is now shared.
+func registerSession(key string, cli *opcua.Client) bool {
+	sessions.Lock()
+	defer sessions.Unlock()
+	if s, ok := sessions.m[key]; ok && s.cli != cli {
+		switch s.cli.State() {
+		case opcua.Connected, opcua.Reconnecting:
+			return false
+		}
+	}
+	sessions.m[key] = &sharedSession{cli: cli, refs: 1}
+	return true
+}
+
+// releaseSession drops one user of cli and reports whether the session should be closed, which
+// is when it was the last user or cli is no longer the registered session for key.
+func releaseSession(key string, cli *opcua.Client) bool {
+	sessions.Lock()
+	defer sessions.Unlock()
+	s, ok := sessions.m[key]
+	if !ok || s.cli != cli {
+		return true
+	}
+	if s.refs--; s.refs > 0 {
+		return false
+	}
+	delete(sessions.m, key)
+	return true
+}
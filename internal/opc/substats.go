@@ -0,0 +1,86 @@
+package opc
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// subscriptionCounters tally what a watch subscription delivered since it was created.
+type subscriptionCounters struct {
+	notifications atomic.Uint64
+	dataChanges   atomic.Uint64
+	errors        atomic.Uint64
+	last          atomic.Int64 // UnixNano of the last data change notification
+}
+
+func (s *subscriptionCounters) received(items int) {
+	s.notifications.Add(1)
+	s.dataChanges.Add(uint64(items))
+	s.last.Store(time.Now().UnixNano())
+}
+
+// SubscriptionStats describes a watch subscription: its rate group, the parameters as revised by the server,
+// what the client received, and the server's own SubscriptionDiagnostics when it exposes them.
+type SubscriptionStats struct {
+	RateGroup          time.Duration // requested sampling interval, 0 for the default group
+	SubscriptionID     uint32
+	PublishingInterval time.Duration
+	LifetimeCount      uint32
+	MaxKeepAliveCount  uint32
+	Priority           uint8
+	MonitoredItems     int
+
+	Notifications    uint64    // publish responses carrying data changes
+	DataChanges      uint64    // monitored item values received
+	Errors           uint64    // publish errors reported by the stack
+	LastNotification time.Time // zero before the first notification
+
+	// Server is nil when the server does not publish SubscriptionDiagnostics (many disable
+	// them); ServerErr then tells why.
+	Server    *ua.SubscriptionDiagnosticsDataType
+	ServerErr error
+}
+
+// SubscriptionStats returns the statistics of the watch subscriptions, one per rate group in
+// ascending order, or nil when there is none.
+func (c *Client) SubscriptionStats(ctx context.Context) []*SubscriptionStats {
+	c.mu.RLock()
+	groups := make([]*rateGroup, 0, len(c.groups))
+	for _, g := range c.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].interval < groups[j].interval })
+	out := make([]*SubscriptionStats, len(groups))
+	for i, g := range groups {
+		out[i] = &SubscriptionStats{
+			RateGroup:          g.interval,
+			SubscriptionID:     g.sub.SubscriptionID,
+			PublishingInterval: g.sub.RevisedPublishingInterval,
+			LifetimeCount:      g.sub.RevisedLifetimeCount,
+			MaxKeepAliveCount:  g.sub.RevisedMaxKeepAliveCount,
+			Priority:           c.tuning.Priority,
+			MonitoredItems:     g.items,
+			Notifications:      g.stats.notifications.Load(),
+			DataChanges:        g.stats.dataChanges.Load(),
+			Errors:             g.stats.errors.Load(),
+		}
+		if ns := g.stats.last.Load(); ns != 0 {
+			out[i].LastNotification = time.Unix(0, ns)
+		}
+	}
+	c.mu.RUnlock()
+	if len(out) == 0 {
+		return nil
+	}
+
+	for i, st := range out {
+		start := time.Now()
+		st.Server, st.ServerErr = groups[i].sub.Stats(ctx)
+		c.traceCall("Read", start, nil, 1, st.ServerErr)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package opc
+
+import (
+	"time"
+
+	"github.com/gopcua/opcua"
+)
+
+// Default per-operation timeouts used when the corresponding Config field is zero.
+const (
+	DefaultReadTimeout    = 5 * time.Second
+	DefaultBrowseTimeout  = 10 * time.Second
+	DefaultWriteTimeout   = 5 * time.Second
+	DefaultPublishTimeout = 10 * time.Second
+	DefaultExportTimeout  = 30 * time.Second
+)
+
+// Timeouts holds the effective request timeouts per operation type.
+type Timeouts struct {
+	Read    time.Duration
+	Browse  time.Duration
+	Write   time.Duration
+	Publish time.Duration
+	Export  time.Duration
+}
+
+// Timeouts returns the configured timeouts with defaults applied. It is safe to call on a nil Config.
+func (c *Config) Timeouts() Timeouts {
+	if c == nil {
+		return Timeouts{DefaultReadTimeout, DefaultBrowseTimeout, DefaultWriteTimeout, DefaultPublishTimeout, DefaultExportTimeout}
+	}
+	return Timeouts{
+		Read:    secondsOr(c.ReadTimeout, DefaultReadTimeout),
+		Browse:  secondsOr(c.BrowseTimeout, DefaultBrowseTimeout),
+		Write:   secondsOr(c.WriteTimeout, DefaultWriteTimeout),
+		Publish: secondsOr(c.PublishTimeout, DefaultPublishTimeout),
+		Export:  secondsOr(c.ExportTimeout, DefaultExportTimeout),
+	}
+}
+
+// TimeoutOptions returns the client options derived from the timeout settings. The secure
+// channel request timeout caps every request and is the lower bound for Publish, so it is set
+// to the largest per-operation timeout; per-call contexts then apply the individual limits.
+func (c *Config) TimeoutOptions() []opcua.Option {
+	t := c.Timeouts()
+	reqTimeout := t.Publish
+	for _, d := range []time.Duration{t.Read, t.Browse, t.Write} {
+		if d > reqTimeout {
+			reqTimeout = d
+		}
+	}
+	opts := []opcua.Option{opcua.RequestTimeout(reqTimeout)}
+	if c != nil && c.ConnectTimeout > 0 {
+		opts = append(opts, opcua.DialTimeout(time.Duration(c.ConnectTimeout*float64(time.Second))))
+	}
+	return opts
+}
+
+func secondsOr(secs float64, def time.Duration) time.Duration {
+	if secs <= 0 {
+		return def
+	}
+	return time.Duration(secs * float64(time.Second))
+}
@@ -0,0 +1,129 @@
+package opc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// traceTimeLayout uses UTC with microseconds so entries line up with packet capture timestamps.
+const traceTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// Tracer appends one line per OPC UA service call to a trace file:
+//
+//	<UTC time>\t<service>\thandle=<RequestHandle>\tduration=<ms>\tstatus=<StatusCode>\titems=<n>\tendpoint=<url>
+type Tracer struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+}
+
+var activeTracer atomic.Pointer[Tracer]
+
+// OpenTracer opens (appending to) the trace file at path.
+func OpenTracer(path string) (*Tracer, error) {
+	if path == "" {
+		return nil, errors.New("trace file path is empty")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tracer{path: path, f: f, w: bufio.NewWriter(f)}
+	fmt.Fprintf(t.w, "# trace started %s\n", time.Now().UTC().Format(traceTimeLayout))
+	return t, nil
+}
+
+// Path returns the file the tracer writes to.
+func (t *Tracer) Path() string { return t.path }
+
+// Close flushes and closes the trace file.
+func (t *Tracer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.f == nil {
+		return nil
+	}
+	fmt.Fprintf(t.w, "# trace stopped %s\n", time.Now().UTC().Format(traceTimeLayout))
+	_ = t.w.Flush()
+	err := t.f.Close()
+	t.f = nil
+	return err
+}
+
+func (t *Tracer) write(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.f == nil {
+		return
+	}
+	t.w.WriteString(line)
+	// Flush per line so the file can be tailed while a capture is running
+	_ = t.w.Flush()
+}
+
+// SetTracer installs t as the process-wide protocol tracer used by all clients.
+// Passing nil disables tracing. The previous tracer is returned and not closed.
+func SetTracer(t *Tracer) *Tracer {
+	return activeTracer.Swap(t)
+}
+
+// ActiveTracer returns the installed tracer or nil.
+func ActiveTracer() *Tracer {
+	return activeTracer.Load()
+}
+
+// traceCall counts a finished service call in the client's ChannelStats and records it if
+// tracing is enabled. hdr may be nil when the request failed before a response was received.
+func (c *Client) traceCall(service string, start time.Time, hdr *ua.ResponseHeader, items int, err error) {
+	if c != nil {
+		c.reqStats.call(service, time.Since(start), err)
+	}
+	t := activeTracer.Load()
+	if t == nil {
+		return
+	}
+	var handle uint32
+	status := ua.StatusOK
+	if hdr != nil {
+		handle = hdr.RequestHandle
+		status = hdr.ServiceResult
+	}
+	statusText := status.Error()
+	if status == ua.StatusOK {
+		statusText = "Good"
+	}
+	if err != nil {
+		var sc ua.StatusCode
+		if errors.As(err, &sc) {
+			statusText = sc.Error()
+		} else {
+			statusText = "error: " + err.Error()
+		}
+	}
+	endpoint := ""
+	if c != nil {
+		endpoint = c.endpoint
+	}
+	t.write(fmt.Sprintf("%s\t%s\thandle=%d\tduration=%.3fms\tstatus=%s\titems=%d\tendpoint=%s\n",
+		start.UTC().Format(traceTimeLayout), service, handle,
+		float64(time.Since(start))/float64(time.Millisecond), statusText, items, endpoint))
+}
+
+// responseHeader returns the header of a possibly nil service response.
+func responseHeader[R any, P interface {
+	*R
+	Header() *ua.ResponseHeader
+}](resp P) *ua.ResponseHeader {
+	if resp == nil {
+		return nil
+	}
+	return resp.Header()
+}
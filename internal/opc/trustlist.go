@@ -0,0 +1,88 @@
+package opc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// trustListChunk is how many bytes each TrustList Read call asks for.
+const trustListChunk = 64 * 1024
+
+// maxTrustListSize guards against servers that never signal the end of the file.
+const maxTrustListSize = 16 << 20
+
+// trustListID is the TrustList of the server's default application certificate group (Part 12).
+var trustListID = ua.NewNumericNodeID(0, id.ServerConfiguration_CertificateGroups_DefaultApplicationGroup_TrustList)
+
+// ReadTrustList reads the TrustList of the server's default application certificate group by
+// opening it like a file (Part 12). Servers usually require an encrypted session and a user
+// with the SecurityAdmin role.
+func (c *Client) ReadTrustList(ctx context.Context) (*ua.TrustListDataType, error) {
+	out, err := c.CallMethod(ctx, trustListID,
+		ua.NewNumericNodeID(0, id.ServerConfiguration_CertificateGroups_DefaultApplicationGroup_TrustList_Open),
+		byte(ua.OpenFileModeRead))
+	if err != nil {
+		return nil, fmt.Errorf("open trust list: %w", err)
+	}
+	handle, ok := outputArg(out, 0).(uint32)
+	if !ok {
+		return nil, errors.New("open trust list: no file handle returned")
+	}
+	defer func() {
+		// The handle must be closed even when reading failed; the server drops it with the session otherwise
+		_, _ = c.CallMethod(context.Background(), trustListID,
+			ua.NewNumericNodeID(0, id.ServerConfiguration_CertificateGroups_DefaultApplicationGroup_TrustList_Close), handle)
+	}()
+
+	var data []byte
+	for {
+		out, err := c.CallMethod(ctx, trustListID,
+			ua.NewNumericNodeID(0, id.ServerConfiguration_CertificateGroups_DefaultApplicationGroup_TrustList_Read),
+			handle, int32(trustListChunk))
+		if err != nil {
+			return nil, fmt.Errorf("read trust list: %w", err)
+		}
+		chunk, _ := outputArg(out, 0).([]byte)
+		data = append(data, chunk...)
+		if len(chunk) < trustListChunk {
+			break
+		}
+		if len(data) > maxTrustListSize {
+			return nil, fmt.Errorf("trust list exceeds %d bytes", maxTrustListSize)
+		}
+	}
+	tl := new(ua.TrustListDataType)
+	if _, err := ua.Decode(data, tl); err != nil {
+		return nil, fmt.Errorf("decode trust list: %w", err)
+	}
+	return tl, nil
+}
+
+// AddTrustedCertificate adds the DER certificate cert to the server's default application
+// trust list, as trusted or as an issuer (CA) certificate. Servers that require it apply the
+// change only on ApplyServerConfigChanges.
+func (c *Client) AddTrustedCertificate(ctx context.Context, cert []byte, trusted bool) error {
+	_, err := c.CallMethod(ctx, trustListID,
+		ua.NewNumericNodeID(0, id.ServerConfiguration_CertificateGroups_DefaultApplicationGroup_TrustList_AddCertificate),
+		cert, trusted)
+	return err
+}
+
+// ApplyServerConfigChanges asks the server to apply pending configuration changes, such as
+// trust list updates. Servers that apply changes immediately answer with an error or do nothing.
+func (c *Client) ApplyServerConfigChanges(ctx context.Context) error {
+	_, err := c.CallMethod(ctx, ua.NewNumericNodeID(0, id.ServerConfiguration),
+		ua.NewNumericNodeID(0, id.ServerConfiguration_ApplyChanges))
+	return err
+}
+
+func outputArg(out []*ua.Variant, i int) interface{} {
+	if i < len(out) && out[i] != nil {
+		return out[i].Value()
+	}
+	return nil
+}
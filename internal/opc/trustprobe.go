@@ -0,0 +1,129 @@
+package opc
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ProbeTrust checks whether the server accepts the client certificate of cfg: it opens a secure
+// channel with signing to the endpoint matching cfg's security settings (the most secure one
+// when they are Auto or None) and issues GetEndpoints on it. It returns nil once the server
+// trusts the certificate and the server's status, typically BadSecurityChecksFailed, while it
+// does not. Every call presents the certificate again, so servers that file rejected
+// certificates for their operator list it even if it was removed there. No session is created.
+func ProbeTrust(ctx context.Context, cfg *Config) error {
+	if cfg == nil || cfg.CertFile == "" || cfg.KeyFile == "" {
+		return errors.New("no client certificate configured")
+	}
+	if err := CheckEndpointScheme(cfg.EndpointURL); err != nil {
+		return err
+	}
+	eps, err := opcua.GetEndpoints(ctx, cfg.DialURL())
+	if err != nil {
+		return fmt.Errorf("GetEndpoints: %w", err)
+	}
+	supported := eps[:0:0]
+	for _, ep := range eps {
+		if IsSupportedEndpoint(ep) {
+			supported = append(supported, ep)
+		}
+	}
+	ep := pickSecureEndpoint(supported, cfg.SecurityPolicy, cfg.SecurityMode)
+	if ep == nil {
+		return errors.New("the server offers no Sign or SignAndEncrypt endpoint")
+	}
+
+	probe := *cfg
+	probe.SecurityPolicy = ep.SecurityPolicyURI
+	probe.SecurityMode = "Sign"
+	if ep.SecurityMode == ua.MessageSecurityModeSignAndEncrypt {
+		probe.SecurityMode = "SignAndEncrypt"
+	}
+	probe.AuthMode = "Anonymous"
+	opts, err := probe.ToOpcuaOptions()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, opcua.SecurityFromEndpoint(ep, ua.UserTokenTypeAnonymous), opcua.AutoReconnect(false))
+	c, err := opcua.NewClient(cfg.DialURL(), opts...)
+	if err != nil {
+		return err
+	}
+	if err := c.Dial(ctx); err != nil {
+		return err
+	}
+	defer c.Close(context.Background())
+	if _, err := c.GetEndpoints(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pickSecureEndpoint returns the signed endpoint matching policy and mode, or the one with the
+// highest SecurityLevel when they do not select a signed endpoint.
+func pickSecureEndpoint(eps []*ua.EndpointDescription, policy, mode string) *ua.EndpointDescription {
+	policy = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(policy), " ", ""))
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	var best, match *ua.EndpointDescription
+	for _, ep := range eps {
+		if ep == nil || ep.SecurityMode == ua.MessageSecurityModeNone || ep.SecurityMode == ua.MessageSecurityModeInvalid {
+			continue
+		}
+		if best == nil || ep.SecurityLevel > best.SecurityLevel {
+			best = ep
+		}
+		epPolicy := strings.ToLower(ep.SecurityPolicyURI)
+		epMode := "sign"
+		if ep.SecurityMode == ua.MessageSecurityModeSignAndEncrypt {
+			epMode = "signandencrypt"
+		}
+		wantPolicy := policy != "" && policy != "auto" && policy != "none"
+		if wantPolicy && (strings.HasSuffix(epPolicy, "#"+policy) || epPolicy == policy) {
+			if (mode == epMode || mode == "" || mode == "auto" || mode == "none") && (match == nil || ep.SecurityLevel > match.SecurityLevel) {
+				match = ep
+			}
+		}
+	}
+	if match != nil {
+		return match
+	}
+	return best
+}
+
+// CertificateThumbprint returns the SHA-1 thumbprint of the (first) certificate in certFile as
+// uppercase hex, the form server trust lists show.
+func CertificateThumbprint(certFile string) (string, error) {
+	data, err := ReadCertificateDER(certFile)
+	if err != nil {
+		return "", err
+	}
+	return Thumbprint(data), nil
+}
+
+// ReadCertificateDER returns the DER encoding of the (first) certificate in certFile, which may
+// be PEM or DER.
+func ReadCertificateDER(certFile string) ([]byte, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	return data, nil
+}
+
+// Thumbprint returns the SHA-1 thumbprint of a DER certificate or CRL as uppercase hex.
+func Thumbprint(der []byte) string {
+	sum := sha1.Sum(der)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
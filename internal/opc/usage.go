@@ -0,0 +1,20 @@
+package opc
+
+import "time"
+
+// UsageStats are local usage statistics, kept in the config and never sent anywhere. They help
+// users describe the scale of their setup when reporting issues.
+type UsageStats struct {
+	Since                 time.Time `json:"since"`
+	Sessions              int       `json:"sessions"`
+	SessionSeconds        float64   `json:"session_seconds"`
+	LongestSessionSeconds float64   `json:"longest_session_seconds"`
+	// NodesBrowsed counts the references returned by Browse requests; LargestAddressSpace is the
+	// most nodes loaded into the tree in one session.
+	NodesBrowsed        int64 `json:"nodes_browsed"`
+	LargestAddressSpace int   `json:"largest_address_space"`
+	MaxWatched          int   `json:"max_watched"`
+	Writes              int64 `json:"writes"`
+	FailedWrites        int64 `json:"failed_writes"`
+	Exports             int64 `json:"exports"`
+}
@@ -0,0 +1,30 @@
+package opc
+
+import "unicode/utf8"
+
+// DefaultWatchValueMaxLen is the number of characters of a value shown in the watch list when
+// Config.WatchValueMaxLen is zero.
+const DefaultWatchValueMaxLen = 120
+
+// WatchValueLimit returns the number of characters of a value shown in the watch list.
+func (c *Config) WatchValueLimit() int {
+	if c == nil || c.WatchValueMaxLen <= 0 {
+		return DefaultWatchValueMaxLen
+	}
+	return c.WatchValueMaxLen
+}
+
+// TruncateText cuts s after n characters and appends an ellipsis; it reports whether s was cut.
+func TruncateText(s string, n int) (string, bool) {
+	if n <= 0 || len(s) <= n || utf8.RuneCountInString(s) <= n {
+		return s, false
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos] + "…", true
+		}
+		i++
+	}
+	return s, false
+}
@@ -0,0 +1,182 @@
+package profilesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// s3Store keeps the document as an S3 object, addressed path-style so S3-compatible servers
+// (MinIO, Ceph, ...) work too. Requests are signed with AWS Signature Version 4; conditional
+// writes (If-Match / If-None-Match) detect concurrent writers.
+type s3Store struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	key       string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func newS3Store(u *url.URL, cfg *opc.Config) (*s3Store, error) {
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("invalid S3 sync location %q: expected s3://bucket/key", cfg.SyncURL)
+	}
+	if cfg.SyncUsername == "" || cfg.SyncPassword == "" {
+		return nil, errors.New("S3 sync needs an access key ID and secret key")
+	}
+	region := strings.TrimSpace(cfg.SyncS3Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSpace(cfg.SyncS3Endpoint)
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	ep, err := url.Parse(endpoint)
+	if err != nil || ep.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	return &s3Store{
+		endpoint:  ep,
+		region:    region,
+		bucket:    u.Host,
+		key:       key,
+		accessKey: cfg.SyncUsername,
+		secretKey: cfg.SyncPassword,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *s3Store) String() string { return "s3://" + s.bucket + "/" + s.key }
+
+func (s *s3Store) objectURL() *url.URL {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + s.key
+	u.RawPath = ""
+	return &u
+}
+
+func (s *s3Store) do(ctx context.Context, method string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL().String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.sign(req, body, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	return resp, data, err
+}
+
+func (s *s3Store) Get(ctx context.Context) ([]byte, string, error) {
+	resp, data, err := s.do(ctx, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("S3 GET %s: %s %s", s, resp.Status, s3ErrorCode(data))
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+func (s *s3Store) Put(ctx context.Context, data []byte, etag string) (string, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if etag == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", etag)
+	}
+	resp, body, err := s.do(ctx, http.MethodPut, data, header)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	// 409: a concurrent conditional write to the same key won
+	case resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict:
+		return "", ErrChanged
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("S3 PUT %s: %s %s", s, resp.Status, s3ErrorCode(body))
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// s3ErrorCode extracts <Code> from an S3 error response.
+func s3ErrorCode(body []byte) string {
+	b := string(body)
+	start := strings.Index(b, "<Code>")
+	end := strings.Index(b, "</Code>")
+	if start < 0 || end < start {
+		return ""
+	}
+	return "(" + b[start+len("<Code>"):end] + ")"
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (s *s3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"host"}
+	canonHeaders := "host:" + req.URL.Host + "\n"
+	for _, h := range []string{"content-type", "if-match", "if-none-match", "x-amz-content-sha256", "x-amz-date"} {
+		if v := req.Header.Get(h); v != "" {
+			signed = append(signed, h)
+			canonHeaders += h + ":" + strings.TrimSpace(v) + "\n"
+		}
+	}
+	signedHeaders := strings.Join(signed, ";")
+	canonRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
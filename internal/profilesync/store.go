@@ -0,0 +1,198 @@
+// Package profilesync shares connection profiles, the watch list and favorites between devices
+// through a user-provided file share, WebDAV server or S3 bucket.
+package profilesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// ErrNotFound is returned by Store.Get when nothing has been synced yet.
+var ErrNotFound = errors.New("sync document not found")
+
+// ErrChanged is returned by Store.Put when the remote document is no longer the expected version.
+var ErrChanged = errors.New("sync document changed remotely")
+
+// maxDocumentSize bounds downloads; sync documents are a few kilobytes.
+const maxDocumentSize = 16 << 20
+
+// Store holds the sync document. ETags are opaque version tags of the stored document.
+type Store interface {
+	// Get returns the document and its ETag, or ErrNotFound.
+	Get(ctx context.Context) ([]byte, string, error)
+	// Put replaces the document if its ETag is still etag (empty: if none exists yet) and
+	// returns the new ETag, or ErrChanged.
+	Put(ctx context.Context, data []byte, etag string) (string, error)
+	// String names the location for logs.
+	String() string
+}
+
+// OpenStore returns the store configured by cfg.SyncURL.
+func OpenStore(cfg *opc.Config) (Store, error) {
+	raw := strings.TrimSpace(cfg.SyncURL)
+	if raw == "" {
+		return nil, errors.New("no sync location configured")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || len(u.Scheme) < 2 {
+		// Plain and Windows paths ("C:\...", "\\server\share\...")
+		return &fileStore{path: raw}, nil
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "file":
+		return &fileStore{path: filepath.FromSlash(u.Path)}, nil
+	case "http", "https":
+		return &webdavStore{url: raw, username: cfg.SyncUsername, password: cfg.SyncPassword, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case "s3":
+		return newS3Store(u, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported sync location %q: use a file path, http(s):// (WebDAV) or s3://", raw)
+	}
+}
+
+// contentTag is the ETag of stores without native versions.
+func contentTag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fileStore keeps the document in a file, typically on a network share. The version check
+// before writing narrows, but cannot close, the window for concurrent writers.
+type fileStore struct {
+	path string
+}
+
+func (s *fileStore) String() string { return s.path }
+
+func (s *fileStore) Get(ctx context.Context) ([]byte, string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentTag(data), nil
+}
+
+func (s *fileStore) Put(ctx context.Context, data []byte, etag string) (string, error) {
+	_, curTag, err := s.Get(ctx)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		if etag != "" {
+			return "", ErrChanged
+		}
+	case err != nil:
+		return "", err
+	case curTag != etag:
+		return "", ErrChanged
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return "", err
+	}
+	// Replace in one step so other devices never read a half-written file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return contentTag(data), nil
+}
+
+// webdavStore keeps the document as a WebDAV resource, using If-Match / If-None-Match so
+// concurrent writers are detected. The collection must exist.
+type webdavStore struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+func (s *webdavStore) String() string { return s.url }
+
+func (s *webdavStore) do(ctx context.Context, method string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	return resp, data, err
+}
+
+func (s *webdavStore) Get(ctx context.Context) ([]byte, string, error) {
+	resp, data, err := s.do(ctx, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("WebDAV GET %s: %s", s.url, resp.Status)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return data, etag, nil
+	}
+	return data, contentTag(data), nil
+}
+
+func (s *webdavStore) Put(ctx context.Context, data []byte, etag string) (string, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	switch {
+	case etag == "":
+		header.Set("If-None-Match", "*")
+	case strings.HasPrefix(etag, "sha256:"):
+		// The server has no ETags: compare the content instead
+		if _, cur, err := s.Get(ctx); err != nil || cur != etag {
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return "", err
+			}
+			return "", ErrChanged
+		}
+	default:
+		header.Set("If-Match", etag)
+	}
+	resp, _, err := s.do(ctx, http.MethodPut, data, header)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", ErrChanged
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return "", fmt.Errorf("WebDAV PUT %s: %s", s.url, resp.Status)
+	}
+	if newTag := resp.Header.Get("ETag"); newTag != "" && !strings.HasPrefix(newTag, "W/") {
+		return newTag, nil
+	}
+	// Not every server returns the new ETag; ask for it
+	_, newTag, err := s.Get(ctx)
+	return newTag, err
+}
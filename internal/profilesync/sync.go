@@ -0,0 +1,240 @@
+package profilesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+)
+
+// DocumentFormat identifies sync documents; DocumentVersion is bumped on incompatible changes.
+const (
+	DocumentFormat  = "opcuababy-sync"
+	DocumentVersion = 1
+)
+
+// Document is what is synced between devices. Certificates are device specific and passwords
+// secret, so profiles are synced without either.
+type Document struct {
+	Format    string                  `json:"format"`
+	Version   int                     `json:"version"`
+	Device    string                  `json:"device,omitempty"`
+	Updated   time.Time               `json:"updated"`
+	Profiles  []opc.ConnectionProfile `json:"profiles,omitempty"`
+	WatchList []string                `json:"watch_list,omitempty"`
+	Favorites []opc.Favorite          `json:"favorites,omitempty"`
+}
+
+// FromConfig returns the synced part of cfg.
+func FromConfig(cfg *opc.Config, device string) *Document {
+	d := &Document{
+		Format:    DocumentFormat,
+		Version:   DocumentVersion,
+		Device:    device,
+		Updated:   time.Now().UTC(),
+		WatchList: append([]string(nil), cfg.WatchList...),
+		Favorites: append([]opc.Favorite(nil), cfg.Favorites...),
+	}
+	// The controller keeps Config.WatchList sorted; so must synced lists, or every sync would
+	// look like a local change
+	sort.Strings(d.WatchList)
+	for _, p := range cfg.Profiles {
+		p.Certificate, p.PrivateKey = nil, nil
+		p.CertificateExt, p.PrivateKeyExt = "", ""
+		d.Profiles = append(d.Profiles, p)
+	}
+	return d
+}
+
+// ApplyTo replaces the synced part of cfg with d.
+func (d *Document) ApplyTo(cfg *opc.Config) {
+	cfg.Profiles = append([]opc.ConnectionProfile(nil), d.Profiles...)
+	cfg.WatchList = append([]string(nil), d.WatchList...)
+	cfg.Favorites = append([]opc.Favorite(nil), d.Favorites...)
+}
+
+// Hash identifies the content of d, ignoring who wrote it and when.
+func (d *Document) Hash() string {
+	data, _ := json.Marshal(struct {
+		Profiles  []opc.ConnectionProfile `json:"profiles"`
+		WatchList []string                `json:"watch_list"`
+		Favorites []opc.Favorite          `json:"favorites"`
+	}{d.Profiles, d.WatchList, d.Favorites})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// empty reports whether d has nothing to sync, as on a fresh install.
+func (d *Document) empty() bool {
+	return len(d.Profiles) == 0 && len(d.WatchList) == 0 && len(d.Favorites) == 0
+}
+
+// ParseDocument decodes a sync document.
+func ParseDocument(data []byte) (*Document, error) {
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid sync document: %w", err)
+	}
+	if d.Format != DocumentFormat {
+		return nil, errors.New("not an OPC UA Baby sync document")
+	}
+	if d.Version > DocumentVersion {
+		return nil, fmt.Errorf("sync document version %d is newer than supported version %d; update this device", d.Version, DocumentVersion)
+	}
+	return &d, nil
+}
+
+// Merge combines two documents changed independently: profiles by name, watch list entries and
+// favorites by reference. Where both have a profile of the same name, local wins. Removals on
+// either side are undone, as a merge cannot tell a removal from an addition.
+func Merge(local, remote *Document) *Document {
+	m := *local
+	m.Profiles = append([]opc.ConnectionProfile(nil), local.Profiles...)
+	names := make(map[string]bool, len(local.Profiles))
+	for _, p := range local.Profiles {
+		names[strings.ToLower(p.Name)] = true
+	}
+	for _, p := range remote.Profiles {
+		if !names[strings.ToLower(p.Name)] {
+			m.Profiles = append(m.Profiles, p)
+		}
+	}
+
+	m.WatchList = append([]string(nil), local.WatchList...)
+	refs := make(map[string]bool, len(local.WatchList))
+	for _, ref := range local.WatchList {
+		refs[ref] = true
+	}
+	for _, ref := range remote.WatchList {
+		if !refs[ref] {
+			refs[ref] = true
+			m.WatchList = append(m.WatchList, ref)
+		}
+	}
+
+	m.Favorites = append([]opc.Favorite(nil), local.Favorites...)
+	favs := make(map[string]bool, len(local.Favorites))
+	for _, f := range local.Favorites {
+		favs[f.Ref] = true
+	}
+	for _, f := range remote.Favorites {
+		if !favs[f.Ref] {
+			favs[f.Ref] = true
+			m.Favorites = append(m.Favorites, f)
+		}
+	}
+	sort.Strings(m.WatchList)
+	m.Updated = time.Now().UTC()
+	return &m
+}
+
+// Resolution decides a conflict, both sides having changed since the last sync.
+type Resolution int
+
+const (
+	// ResolveAsk reports the conflict (OutcomeConflict) without changing anything.
+	ResolveAsk Resolution = iota
+	// ResolveKeepLocal overwrites the remote document.
+	ResolveKeepLocal
+	// ResolveUseRemote replaces the local data.
+	ResolveUseRemote
+	// ResolveMerge combines both, see Merge.
+	ResolveMerge
+)
+
+// Outcome is what a sync did.
+type Outcome string
+
+const (
+	OutcomeUnchanged Outcome = "unchanged"
+	OutcomePushed    Outcome = "pushed"
+	OutcomePulled    Outcome = "pulled"
+	OutcomeMerged    Outcome = "merged"
+	OutcomeConflict  Outcome = "conflict"
+)
+
+// State is what a device remembers about its last sync (Config.SyncETag and Config.SyncHash).
+type State struct {
+	ETag string
+	Hash string
+}
+
+// Result of Sync. Apply, when set, is the document the local data must be replaced with; State
+// is to be stored once it has been applied.
+type Result struct {
+	Outcome Outcome
+	Apply   *Document
+	// Remote is the remote document, also on conflicts so they can be shown.
+	Remote *Document
+	State  State
+}
+
+// maxSyncRounds bounds retries when other devices keep writing in between.
+const maxSyncRounds = 3
+
+// Sync reconciles local with the document in store. Whichever side changed since state is
+// copied to the other; when both did, resolve decides.
+func Sync(ctx context.Context, store Store, local *Document, state State, resolve Resolution) (Result, error) {
+	localHash := local.Hash()
+	for round := 0; ; round++ {
+		data, etag, err := store.Get(ctx)
+		var remote *Document
+		switch {
+		case errors.Is(err, ErrNotFound):
+			etag = ""
+		case err != nil:
+			return Result{}, err
+		default:
+			if remote, err = ParseDocument(data); err != nil {
+				return Result{}, err
+			}
+		}
+
+		// A fresh install takes what is there instead of asking about a conflict
+		localChanged := localHash != state.Hash && !local.empty()
+		remoteChanged := etag != state.ETag
+		if remote != nil && remote.Hash() == localHash {
+			// Same content written elsewhere, or the first sync of identical setups
+			return Result{Outcome: OutcomeUnchanged, Remote: remote, State: State{ETag: etag, Hash: localHash}}, nil
+		}
+
+		push := local
+		outcome := OutcomePushed
+		switch {
+		case remote == nil:
+		case !remoteChanged && !localChanged:
+			return Result{Outcome: OutcomeUnchanged, Remote: remote, State: state}, nil
+		case !localChanged || (remoteChanged && resolve == ResolveUseRemote):
+			return Result{Outcome: OutcomePulled, Apply: remote, Remote: remote, State: State{ETag: etag, Hash: remote.Hash()}}, nil
+		case !remoteChanged || resolve == ResolveKeepLocal:
+		case resolve == ResolveMerge:
+			push, outcome = Merge(local, remote), OutcomeMerged
+		default:
+			return Result{Outcome: OutcomeConflict, Remote: remote, State: state}, nil
+		}
+
+		out, err := json.MarshalIndent(push, "", "  ")
+		if err != nil {
+			return Result{}, err
+		}
+		newTag, err := store.Put(ctx, out, etag)
+		if errors.Is(err, ErrChanged) && round+1 < maxSyncRounds {
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		res := Result{Outcome: outcome, Remote: remote, State: State{ETag: newTag, Hash: push.Hash()}}
+		if outcome == OutcomeMerged {
+			res.Apply = push
+		}
+		return res, nil
+	}
+}
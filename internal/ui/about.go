@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAboutDialog shows the version and the local usage statistics, which can be copied into an
+// issue report. Nothing is sent anywhere.
+func (ui *UI) showAboutDialog() {
+	grid := container.New(layout.NewFormLayout())
+	var report []string
+	refresh := func() {
+		s := ui.controller.UsageStats()
+		rows := [][2]string{
+			{ui.t("about_version"), ui.currentVersion()},
+			{ui.t("about_platform"), fmt.Sprintf("%s/%s, %s", runtime.GOOS, runtime.GOARCH, runtime.Version())},
+			{ui.t("stats_since"), s.Since.Local().Format("2006-01-02")},
+			{ui.t("stats_sessions"), fmt.Sprintf("%d", s.Sessions)},
+			{ui.t("stats_session_time"), formatStatsDuration(s.SessionSeconds)},
+			{ui.t("stats_longest_session"), formatStatsDuration(s.LongestSessionSeconds)},
+			{ui.t("stats_nodes_browsed"), fmt.Sprintf("%d", s.NodesBrowsed)},
+			{ui.t("stats_largest_space"), fmt.Sprintf("%d", s.LargestAddressSpace)},
+			{ui.t("stats_max_watched"), fmt.Sprintf("%d", s.MaxWatched)},
+			{ui.t("stats_writes"), fmt.Sprintf("%d (%s %d)", s.Writes, ui.t("stats_failed"), s.FailedWrites)},
+			{ui.t("stats_exports"), fmt.Sprintf("%d", s.Exports)},
+		}
+		grid.RemoveAll()
+		report = report[:0]
+		for _, r := range rows {
+			grid.Add(widget.NewLabelWithStyle(r[0], fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+			grid.Add(widget.NewLabel(r[1]))
+			report = append(report, r[0]+": "+r[1])
+		}
+		grid.Refresh()
+	}
+	refresh()
+
+	hint := widget.NewLabel(ui.t("stats_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+		ui.app.Clipboard().SetContent("OPC UA Baby\n" + strings.Join(report, "\n"))
+	})
+	resetBtn := widget.NewButtonWithIcon(ui.t("stats_reset"), theme.DeleteIcon(), func() {
+		dialog.ShowConfirm(ui.t("stats_reset"), ui.t("stats_reset_confirm"), func(ok bool) {
+			if ok {
+				ui.controller.ResetUsageStats()
+				ui.saveConfig()
+				refresh()
+			}
+		}, ui.window)
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("OPC UA Baby", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		grid,
+		hint,
+		container.NewHBox(copyBtn, resetBtn),
+	)
+	dlg := dialog.NewCustom(ui.t("about"), ui.t("close_btn"), content, ui.window)
+	dlg.Resize(fyne.NewSize(480, 460))
+	dlg.Show()
+}
+
+// formatStatsDuration renders seconds as hours and minutes.
+func formatStatsDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%dh %02dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strings"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// writeMaskText summarizes which attributes the node's write masks permit writing.
+func (ui *UI) writeMaskText(m controller.WriteMasks) string {
+	if !m.Known && !m.UserKnown {
+		return ui.t("write_mask_unknown")
+	}
+	if names := m.Writable(); len(names) > 0 {
+		return strings.Join(names, ", ")
+	}
+	return "None"
+}
+
+// showAttributeEditor lets the user read and write non-Value attributes of a node.
+// Attributes the node's (User)WriteMask does not permit writing are marked read-only and
+// cannot be written; the server still has the final say.
+func (ui *UI) showAttributeEditor(nodeID string) {
+	currentLbl := widget.NewLabel("")
+	currentLbl.Wrapping = fyne.TextWrapWord
+	valueEntry := widget.NewEntry()
+	valueEntry.SetPlaceHolder(ui.t("placeholder_attr_value"))
+	maskLbl := widget.NewLabel("...")
+	maskLbl.Wrapping = fyne.TextWrapWord
+
+	names := controller.EditableAttributeNames()
+	var masks controller.WriteMasks // unknown until read, permitting everything
+	labels := func() []string {
+		out := make([]string, len(names))
+		for i, name := range names {
+			out[i] = name
+			if !masks.CanWrite(name) {
+				out[i] += " (" + ui.t("attr_read_only") + ")"
+			}
+		}
+		return out
+	}
+	attrSelect := widget.NewSelect(labels(), nil)
+	selected := func() string {
+		if i := attrSelect.SelectedIndex(); i >= 0 {
+			return names[i]
+		}
+		return ""
+	}
+
+	writeBtn := widget.NewButtonWithIcon(ui.t("write_btn"), theme.DocumentSaveIcon(), nil)
+	updateWritable := func() {
+		if attr := selected(); attr != "" && !masks.CanWrite(attr) {
+			valueEntry.Disable()
+			writeBtn.Disable()
+			return
+		}
+		valueEntry.Enable()
+		writeBtn.Enable()
+	}
+	loadCurrent := func(attr string) {
+		currentLbl.SetText("...")
+		go func() {
+			v, err := ui.controller.ReadAttribute(nodeID, attr)
+			fyne.Do(func() {
+				if selected() != attr {
+					return
+				}
+				if err != nil {
+					currentLbl.SetText(err.Error())
+					return
+				}
+				currentLbl.SetText(v)
+				valueEntry.SetText(v)
+			})
+		}()
+	}
+	attrSelect.OnChanged = func(string) {
+		updateWritable()
+		if attr := selected(); attr != "" {
+			loadCurrent(attr)
+		}
+	}
+
+	writeBtn.OnTapped = func() {
+		attr := selected()
+		if attr == "" || !masks.CanWrite(attr) {
+			return
+		}
+		val := valueEntry.Text
+		writeBtn.Disable()
+		go func() {
+			err := ui.controller.WriteAttribute(nodeID, attr, val)
+			fyne.Do(func() {
+				updateWritable()
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				loadCurrent(attr)
+			})
+		}()
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("attribute"), attrSelect),
+		widget.NewFormItem(ui.t("current_value"), currentLbl),
+		widget.NewFormItem(ui.t("new_value"), valueEntry),
+		widget.NewFormItem(ui.t("writable_attributes"), maskLbl),
+	)
+	content := container.NewVBox(form, container.NewHBox(layout.NewSpacer(), writeBtn))
+
+	d := dialog.NewCustom(ui.t("edit_attributes")+" - "+nodeID, ui.t("close_btn"), content, ui.window)
+	d.Resize(fyne.NewSize(520, 300))
+	d.Show()
+	attrSelect.SetSelectedIndex(0)
+
+	go func() {
+		m, err := ui.controller.ReadWriteMasks(nodeID)
+		fyne.Do(func() {
+			if err != nil {
+				maskLbl.SetText(err.Error())
+				return
+			}
+			masks = m
+			maskLbl.SetText(ui.writeMaskText(m))
+			i := attrSelect.SelectedIndex()
+			attrSelect.Options = labels()
+			attrSelect.Refresh()
+			if i >= 0 {
+				// Relabel the selection without reading the attribute again
+				onChanged := attrSelect.OnChanged
+				attrSelect.OnChanged = nil
+				attrSelect.SetSelectedIndex(i)
+				attrSelect.OnChanged = onChanged
+			}
+			updateWritable()
+		})
+	}()
+}
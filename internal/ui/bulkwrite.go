@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bulkWriteTxChunkSize is the number of values per Write request of transactional bulk writes.
+const bulkWriteTxChunkSize = 10
+
+// showBulkWriteDialog asks for a NodeID,DataType,Value CSV file, validates it against the
+// server and shows the planned writes before anything is written.
+func (ui *UI) showBulkWriteDialog() {
+	dlg := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		rows, perr := controller.ParseBulkWriteCSV(reader)
+		reader.Close()
+		if perr != nil {
+			dialog.ShowError(perr, ui.window)
+			return
+		}
+		progress := dialog.NewCustomWithoutButtons(ui.t("bulk_write"), widget.NewProgressBarInfinite(), ui.window)
+		progress.Show()
+		go func() {
+			verr := ui.controller.ValidateBulkWrite(rows)
+			fyne.Do(func() {
+				progress.Hide()
+				if verr != nil {
+					dialog.ShowError(verr, ui.window)
+					return
+				}
+				ui.showBulkWritePreview(rows)
+			})
+		}()
+	}, ui.window)
+	winSize := ui.window.Canvas().Size()
+	dlg.Resize(fyne.NewSize(winSize.Width*0.9, winSize.Height*0.9))
+	dlg.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".txt"}))
+	dlg.Show()
+}
+
+func (ui *UI) showBulkWritePreview(rows []*controller.BulkWriteRow) {
+	headers := []string{"Line", "NodeID", "DataType", "Value", ui.t("previous_value"), "Status", "Message"}
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.Importance = widget.MediumImportance
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			r := rows[id.Row-1]
+			lbl.TextStyle = fyne.TextStyle{}
+			lbl.Importance = widget.MediumImportance
+			switch id.Col {
+			case 0:
+				lbl.SetText(strconv.Itoa(r.Line))
+			case 1:
+				lbl.SetText(r.NodeID)
+			case 2:
+				lbl.SetText(r.DataType)
+			case 3:
+				lbl.SetText(r.Value)
+			case 4:
+				lbl.SetText(r.Previous)
+			case 5:
+				switch r.Status {
+				case controller.BulkWriteInvalid, controller.BulkWriteFailed:
+					lbl.Importance = widget.DangerImportance
+				case controller.BulkWriteOK:
+					lbl.Importance = widget.SuccessImportance
+				case controller.BulkWriteSkipped, controller.BulkWriteRolledBack:
+					lbl.Importance = widget.WarningImportance
+				}
+				lbl.SetText(r.Status)
+			case 6:
+				lbl.SetText(r.Message)
+			}
+		},
+	)
+	for i, w := range []float32{60, 240, 90, 160, 160, 90, 320} {
+		table.SetColumnWidth(i, w)
+	}
+
+	summaryLbl := widget.NewLabel("")
+	updateSummary := func() {
+		counts := make(map[string]int)
+		for _, r := range rows {
+			counts[r.Status]++
+		}
+		summaryLbl.SetText(fmt.Sprintf(ui.t("bulk_write_summary"), len(rows),
+			counts[controller.BulkWriteValid], counts[controller.BulkWriteInvalid],
+			counts[controller.BulkWriteOK], counts[controller.BulkWriteFailed]))
+		if n, m := counts[controller.BulkWriteSkipped], counts[controller.BulkWriteRolledBack]; n > 0 || m > 0 {
+			summaryLbl.SetText(summaryLbl.Text + fmt.Sprintf(ui.t("bulk_write_summary_tx"), n, m))
+		}
+	}
+	updateSummary()
+
+	progressBar := widget.NewProgressBar()
+	progressBar.Hide()
+
+	// Transactional writes capture the previous values and offer a rollback when the batch
+	// fails or is aborted; chunks are smaller so that an abort stops early
+	txCheck := widget.NewCheck(ui.t("bulk_write_transactional"), nil)
+	txCheck.SetChecked(true)
+	var cancelTx context.CancelFunc
+	abortBtn := widget.NewButtonWithIcon(ui.t("abort_btn"), theme.MediaStopIcon(), func() {
+		if cancelTx != nil {
+			cancelTx()
+		}
+	})
+	abortBtn.Hide()
+	var rollbackBtn *widget.Button
+	rollback := func() {
+		rollbackBtn.Disable()
+		go func() {
+			err := ui.controller.RollbackBulkWrite(rows, controller.DefaultBulkWriteChunkSize)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+				}
+				if controller.CanRollbackBulkWrite(rows) {
+					rollbackBtn.Enable()
+				} else {
+					rollbackBtn.Hide()
+				}
+				updateSummary()
+				table.Refresh()
+			})
+		}()
+	}
+	rollbackBtn = widget.NewButtonWithIcon(ui.t("rollback_btn"), theme.ContentUndoIcon(), rollback)
+	rollbackBtn.Importance = widget.DangerImportance
+	rollbackBtn.Hide()
+
+	var executeBtn *widget.Button
+	executeBtn = widget.NewButtonWithIcon(ui.t("execute_btn"), theme.ConfirmIcon(), func() {
+		valid := 0
+		for _, r := range rows {
+			if r.Status == controller.BulkWriteValid {
+				valid++
+			}
+		}
+		dialog.ShowConfirm(ui.t("bulk_write"), fmt.Sprintf(ui.t("confirm_bulk_write"), valid), func(ok bool) {
+			if !ok {
+				return
+			}
+			executeBtn.Disable()
+			txCheck.Disable()
+			progressBar.SetValue(0)
+			progressBar.Show()
+			onProgress := func(done, total int) {
+				fyne.Do(func() { progressBar.SetValue(float64(done) / float64(total)) })
+			}
+			if !txCheck.Checked {
+				go func() {
+					err := ui.controller.ExecuteBulkWrite(rows, controller.DefaultBulkWriteChunkSize, onProgress)
+					fyne.Do(func() {
+						if err != nil {
+							executeBtn.Enable()
+							txCheck.Enable()
+							dialog.ShowError(err, ui.window)
+							return
+						}
+						updateSummary()
+						table.Refresh()
+					})
+				}()
+				return
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancelTx = cancel
+			abortBtn.Show()
+			go func() {
+				defer cancel()
+				complete, err := ui.controller.ExecuteBulkWriteTransaction(ctx, rows, bulkWriteTxChunkSize, onProgress)
+				fyne.Do(func() {
+					abortBtn.Hide()
+					cancelTx = nil
+					updateSummary()
+					table.Refresh()
+					if err != nil {
+						executeBtn.Enable()
+						txCheck.Enable()
+						dialog.ShowError(err, ui.window)
+						return
+					}
+					if complete || !controller.CanRollbackBulkWrite(rows) {
+						return
+					}
+					rollbackBtn.Show()
+					dialog.ShowConfirm(ui.t("bulk_write"), ui.t("confirm_bulk_rollback"), func(ok bool) {
+						if ok {
+							rollback()
+						}
+					}, ui.window)
+				})
+			}()
+		}, ui.window)
+	})
+	hasValid := false
+	for _, r := range rows {
+		if r.Status == controller.BulkWriteValid {
+			hasValid = true
+			break
+		}
+	}
+	if !hasValid {
+		executeBtn.Disable()
+	}
+
+	reportBtn := widget.NewButtonWithIcon(ui.t("export_report"), theme.DownloadIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			filePath := writer.URI().Path()
+			writer.Close()
+			if err := controller.WriteBulkWriteReport(filePath, rows); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+		saveDialog.SetFileName("bulk_write_report.csv")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+		saveDialog.Show()
+	})
+
+	top := container.NewVBox(
+		container.NewHBox(summaryLbl, layout.NewSpacer(), txCheck, reportBtn, rollbackBtn, abortBtn, executeBtn),
+		progressBar,
+	)
+	content := container.NewBorder(top, nil, nil, nil, table)
+	d := dialog.NewCustom(ui.t("bulk_write"), ui.t("close_btn"), content, ui.window)
+	winSize := ui.window.Canvas().Size()
+	d.Resize(fyne.NewSize(winSize.Width*0.8, winSize.Height*0.8))
+	d.Show()
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// hexGridWidth is the number of bytes per line of the hex pane.
+const hexGridWidth = 16
+
+// byteEditor edits the value of a ByteString write in a hex pane and an ASCII pane kept in
+// sync, so that "4142" is never mistaken for the text "4142" or the other way round.
+type byteEditor struct {
+	ui         *UI
+	hexEntry   *widget.Entry
+	asciiEntry *widget.Entry
+	info       *widget.Label
+	bytes      []byte
+	err        error // the hex pane does not parse
+	syncing    bool  // a pane is being set from the other one
+}
+
+func (ui *UI) newByteEditor(initial []byte) *byteEditor {
+	e := &byteEditor{ui: ui, bytes: initial}
+	e.hexEntry = widget.NewMultiLineEntry()
+	e.hexEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	e.hexEntry.Wrapping = fyne.TextWrapOff
+	e.hexEntry.SetPlaceHolder("41 42 43")
+	e.hexEntry.SetMinRowsVisible(8)
+	e.hexEntry.Validator = func(s string) error {
+		_, err := controller.ParseHexBytes(s)
+		return err
+	}
+	e.asciiEntry = widget.NewMultiLineEntry()
+	e.asciiEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	e.asciiEntry.Wrapping = fyne.TextWrapBreak
+	e.asciiEntry.SetMinRowsVisible(8)
+	e.info = widget.NewLabel("")
+	e.info.Wrapping = fyne.TextWrapWord
+
+	// Typing or pasting into one pane rewrites the other; invalid hex leaves the ASCII pane as it
+	// was until it parses again.
+	e.hexEntry.OnChanged = func(s string) {
+		if e.syncing {
+			return
+		}
+		b, err := controller.ParseHexBytes(s)
+		e.err = err
+		if err == nil {
+			e.bytes = b
+			e.sync(e.asciiEntry)
+		}
+		e.updateInfo()
+	}
+	e.asciiEntry.OnChanged = func(s string) {
+		if e.syncing {
+			return
+		}
+		e.bytes, e.err = []byte(s), nil
+		e.sync(e.hexEntry)
+	}
+	e.sync(nil)
+	return e
+}
+
+// sync shows the bytes in the panes other than from.
+func (e *byteEditor) sync(from *widget.Entry) {
+	e.syncing = true
+	defer func() { e.syncing = false }()
+	if from != e.hexEntry {
+		e.hexEntry.SetText(controller.FormatHexGrid(e.bytes, hexGridWidth))
+	}
+	if from != e.asciiEntry {
+		if textBytes(e.bytes) {
+			e.asciiEntry.SetText(string(e.bytes))
+			e.asciiEntry.Enable()
+		} else {
+			// Dots for the other bytes are not editable text
+			e.asciiEntry.SetText(printableASCII(e.bytes))
+			e.asciiEntry.Disable()
+		}
+	}
+	e.updateInfo()
+}
+
+func (e *byteEditor) updateInfo() {
+	switch {
+	case e.err != nil:
+		e.info.SetText(fmt.Sprintf(e.ui.t("byte_editor_invalid"), e.err))
+		e.info.Importance = widget.DangerImportance
+	case !textBytes(e.bytes):
+		e.info.SetText(fmt.Sprintf(e.ui.t("byte_editor_length"), len(e.bytes)) + " · " + e.ui.t("byte_editor_binary"))
+		e.info.Importance = widget.MediumImportance
+	default:
+		e.info.SetText(fmt.Sprintf(e.ui.t("byte_editor_length"), len(e.bytes)))
+		e.info.Importance = widget.MediumImportance
+	}
+	e.info.Refresh()
+}
+
+// value is the value passed to WriteValue; the hex: prefix keeps it from being read as text.
+func (e *byteEditor) value() (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	return "hex:" + hex.EncodeToString(e.bytes), nil
+}
+
+func (e *byteEditor) content() fyne.CanvasObject {
+	panes := container.NewGridWithColumns(2,
+		container.NewBorder(widget.NewLabel(e.ui.t("byte_editor_hex")), nil, nil, nil, e.hexEntry),
+		container.NewBorder(widget.NewLabel(e.ui.t("byte_editor_ascii")), nil, nil, nil, e.asciiEntry),
+	)
+	return container.NewBorder(nil, e.info, nil, nil, panes)
+}
+
+// watchValueBytes returns the bytes of the watch item nodeID's value, nil when it is not watched
+// or not a ByteString.
+func (ui *UI) watchValueBytes(nodeID string) []byte {
+	ui.watchTableMutex.RLock()
+	defer ui.watchTableMutex.RUnlock()
+	for _, it := range ui.watchRows {
+		if it.NodeID == nodeID {
+			if b, binary := valueBytes(it.UAType, it.DataType, it.Value); binary {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// textBytes reports whether b is printable ASCII, tabs and line breaks, i.e. can be edited in
+// the ASCII pane without losing bytes.
+func textBytes(b []byte) bool {
+	for _, c := range b {
+		if (c < 0x20 || c >= 0x7f) && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+	}
+	return true
+}
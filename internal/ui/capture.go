@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showCaptureDialog arms a trigger capture on a watched node, or shows and disarms the armed one.
+func (ui *UI) showCaptureDialog() {
+	if st := ui.controller.CaptureStatus(); st.Armed {
+		ui.showCaptureStatus(st)
+		return
+	}
+
+	ui.watchTableMutex.RLock()
+	nodeIDs := make([]string, 0, len(ui.watchRows))
+	for _, it := range ui.watchRows {
+		nodeIDs = append(nodeIDs, it.NodeID)
+	}
+	selected := ""
+	if ui.selectedWatchRow >= 0 && ui.selectedWatchRow < len(ui.watchRows) {
+		selected = ui.watchRows[ui.selectedWatchRow].NodeID
+	}
+	ui.watchTableMutex.RUnlock()
+	if len(nodeIDs) == 0 {
+		dialog.ShowInformation(ui.t("capture"), ui.t("capture_no_watches"), ui.window)
+		return
+	}
+
+	nodeSelect := widget.NewSelect(nodeIDs, nil)
+	if selected != "" {
+		nodeSelect.SetSelected(selected)
+	} else {
+		nodeSelect.SetSelectedIndex(0)
+	}
+	conditions := map[string]string{
+		ui.t("trigger_rising"):  controller.TriggerRising,
+		ui.t("trigger_falling"): controller.TriggerFalling,
+		ui.t("trigger_change"):  controller.TriggerChange,
+	}
+	conditionSelect := widget.NewSelect([]string{ui.t("trigger_rising"), ui.t("trigger_falling"), ui.t("trigger_change")}, nil)
+	conditionSelect.SetSelectedIndex(0)
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText("0.5")
+	preEntry := widget.NewEntry()
+	preEntry.SetText("10")
+	postEntry := widget.NewEntry()
+	postEntry.SetText("10")
+	dirEntry := widget.NewEntry()
+	dirEntry.SetPlaceHolder(controller.DefaultCaptureDir())
+	oneShotCheck := widget.NewCheck(ui.t("capture_one_shot"), nil)
+
+	dialog.ShowForm(ui.t("capture"), ui.t("capture_arm"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem("NodeID", nodeSelect),
+			widget.NewFormItem(ui.t("trigger_condition"), conditionSelect),
+			widget.NewFormItem(ui.t("trigger_threshold"), thresholdEntry),
+			widget.NewFormItem(ui.t("capture_pre_s"), preEntry),
+			widget.NewFormItem(ui.t("capture_post_s"), postEntry),
+			widget.NewFormItem(ui.t("capture_dir"), dirEntry),
+			widget.NewFormItem("", oneShotCheck),
+			widget.NewFormItem("", widget.NewButtonWithIcon(ui.t("recording_to_excel"), theme.DownloadIcon(), func() {
+				ui.showRecordingExcelDialog("")
+			})),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			t := controller.CaptureTrigger{
+				NodeID:    nodeSelect.Selected,
+				Condition: conditions[conditionSelect.Selected],
+				Dir:       strings.TrimSpace(dirEntry.Text),
+				OneShot:   oneShotCheck.Checked,
+			}
+			var err error
+			if t.Threshold, err = strconv.ParseFloat(strings.TrimSpace(thresholdEntry.Text), 64); err != nil && t.Condition != controller.TriggerChange {
+				dialog.ShowError(fmt.Errorf("%s: %v", ui.t("trigger_threshold"), err), ui.window)
+				return
+			}
+			for _, w := range []struct {
+				label string
+				entry *widget.Entry
+				dst   *time.Duration
+			}{
+				{"capture_pre_s", preEntry, &t.Pre},
+				{"capture_post_s", postEntry, &t.Post},
+			} {
+				secs, err := strconv.ParseFloat(strings.TrimSpace(w.entry.Text), 64)
+				if err != nil || secs < 0 {
+					dialog.ShowError(fmt.Errorf("%s: invalid value %q", ui.t(w.label), w.entry.Text), ui.window)
+					return
+				}
+				*w.dst = time.Duration(secs * float64(time.Second))
+			}
+			if err := ui.controller.ArmCapture(t); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+}
+
+// showCaptureStatus shows the armed trigger with a button to disarm it.
+func (ui *UI) showCaptureStatus(st controller.CaptureStatus) {
+	t := st.Trigger
+	state := ui.t("capture_armed")
+	if st.Capturing {
+		state = ui.t("capture_recording")
+	}
+	info := widget.NewForm(
+		widget.NewFormItem("NodeID", widget.NewLabel(t.NodeID)),
+		widget.NewFormItem(ui.t("trigger_condition"), widget.NewLabel(fmt.Sprintf("%s %g", t.Condition, t.Threshold))),
+		widget.NewFormItem(ui.t("capture_pre_s"), widget.NewLabel(t.Pre.String())),
+		widget.NewFormItem(ui.t("capture_post_s"), widget.NewLabel(t.Post.String())),
+		widget.NewFormItem(ui.t("capture_dir"), widget.NewLabel(t.Dir)),
+		widget.NewFormItem("Status", widget.NewLabel(state)),
+		widget.NewFormItem(ui.t("capture_written"), widget.NewLabel(strconv.Itoa(st.Count))),
+		widget.NewFormItem(ui.t("capture_last_file"), widget.NewLabel(st.LastFile)),
+	)
+	var dlg dialog.Dialog
+	disarmBtn := widget.NewButton(ui.t("capture_disarm"), func() {
+		dlg.Hide()
+		go ui.controller.DisarmCapture()
+	})
+	excelBtn := widget.NewButtonWithIcon(ui.t("recording_to_excel"), theme.DownloadIcon(), func() {
+		dlg.Hide()
+		ui.showRecordingExcelDialog(st.LastFile)
+	})
+	dlg = dialog.NewCustom(ui.t("capture"), ui.t("close_btn"), container.NewVBox(info, container.NewHBox(disarmBtn, excelBtn)), ui.window)
+	dlg.Show()
+}
+
+// setCaptureStatus reflects the capture state on the watch panel button.
+func (ui *UI) setCaptureStatus(st controller.CaptureStatus) {
+	fyne.Do(func() {
+		switch {
+		case st.Capturing:
+			ui.captureBtn.SetText(ui.t("capture") + " ● " + ui.t("capture_recording"))
+			ui.captureBtn.Importance = widget.DangerImportance
+		case st.Armed:
+			ui.captureBtn.SetText(ui.t("capture") + " (" + ui.t("capture_armed") + ")")
+			ui.captureBtn.Importance = widget.WarningImportance
+		default:
+			ui.captureBtn.SetText(ui.t("capture"))
+			ui.captureBtn.Importance = widget.MediumImportance
+		}
+		ui.captureBtn.Refresh()
+	})
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showCertificateTrustWait guides through getting the client certificate trusted: it presents the
+// certificate to the server, polls until the server accepts it and then connects.
+func (ui *UI) showCertificateTrustWait() {
+	cfg := *ui.config
+	thumbprint, err := opc.CertificateThumbprint(cfg.CertFile)
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+
+	hint := widget.NewLabel(ui.t("trust_wait_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	thumbEntry := widget.NewEntry()
+	thumbEntry.SetText(thumbprint)
+	thumbEntry.Disable()
+	status := widget.NewLabel(ui.t("trust_wait_sending"))
+	status.Wrapping = fyne.TextWrapWord
+	status.Importance = widget.LowImportance
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resend := make(chan struct{}, 1)
+	resendBtn := widget.NewButtonWithIcon(ui.t("trust_resend"), theme.MailSendIcon(), func() {
+		select {
+		case resend <- struct{}{}:
+		default:
+		}
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("endpoint"), widget.NewLabel(cfg.EndpointURL)),
+		widget.NewFormItem(ui.t("dial_client_cert"), widget.NewLabel(cfg.CertFile)),
+		widget.NewFormItem(ui.t("trust_thumbprint"), thumbEntry),
+	)
+	content := container.NewVBox(hint, form, widget.NewProgressBarInfinite(), status, container.NewHBox(resendBtn))
+	dlg := dialog.NewCustom(ui.t("trust_wait_title"), ui.t("cancel_btn"), content, ui.window)
+	dlg.SetOnClosed(cancel)
+	dlg.Resize(fyne.NewSize(560, 360))
+	dlg.Show()
+
+	go func() {
+		err := ui.controller.WaitForCertificateTrust(ctx, &cfg, controller.DefaultTrustPollInterval, resend, func(attempt int, err error) {
+			if err == nil {
+				return
+			}
+			fyne.Do(func() { status.SetText(fmt.Sprintf(ui.t("trust_wait_status"), attempt, err)) })
+		})
+		if err != nil {
+			return
+		}
+		fyne.Do(func() {
+			dlg.Hide()
+			if !ui.isConnected {
+				ui.onConnectClicked()
+			}
+		})
+	}()
+}
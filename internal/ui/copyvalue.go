@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tableCell wraps the content of a table cell so that it can be right-clicked. Fyne delivers a
+// tap to the innermost tappable object, so primary taps are handed back to the table.
+type tableCell struct {
+	widget.BaseWidget
+	content     fyne.CanvasObject
+	table       *widget.Table
+	id          widget.TableCellID
+	onSecondary func(id widget.TableCellID, ev *fyne.PointEvent)
+}
+
+func newTableCell(content fyne.CanvasObject, onSecondary func(id widget.TableCellID, ev *fyne.PointEvent)) *tableCell {
+	c := &tableCell{content: content, onSecondary: onSecondary}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// bind records which cell of table is shown; called from the table's update callback.
+func (c *tableCell) bind(table *widget.Table, id widget.TableCellID) {
+	c.table, c.id = table, id
+}
+
+func (c *tableCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.content)
+}
+
+func (c *tableCell) Tapped(ev *fyne.PointEvent) {
+	if c.table == nil {
+		return
+	}
+	origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(c.table)
+	c.table.Tapped(&fyne.PointEvent{Position: ev.AbsolutePosition.Subtract(origin), AbsolutePosition: ev.AbsolutePosition})
+}
+
+func (c *tableCell) TappedSecondary(ev *fyne.PointEvent) {
+	if c.onSecondary != nil {
+		c.onSecondary(c.id, ev)
+	}
+}
+
+// apiBaseURL is the local REST API address used in copied curl commands.
+func (ui *UI) apiBaseURL() string {
+	port := strings.TrimSpace(ui.config.ApiPort)
+	if port == "" {
+		port = "8080"
+	}
+	return "http://localhost:" + port
+}
+
+// showValueCopyMenu offers copying v as JSON or as curl commands against the local REST API,
+// and the API snippets of its node, followed by extra items.
+func (ui *UI) showValueCopyMenu(v *controller.ValueCopy, pos fyne.Position, extra ...*fyne.MenuItem) {
+	copyText := func(render func() (string, error)) func() {
+		return func() {
+			text, err := render()
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.app.Clipboard().SetContent(text)
+			ui.controller.Log(fmt.Sprintf("[green]Copied value of %s[-]", v.NodeID))
+			if !ui.config.ApiEnabled && strings.HasPrefix(text, "curl") {
+				ui.controller.Log("[yellow]The API server is disabled; enable it in Settings for the copied command to work[-]")
+			}
+			if ui.config.ApiAuthRequired() && strings.HasPrefix(text, "curl") {
+				ui.controller.Log("[yellow]The API requires the API key: add -H \"Authorization: Bearer <key>\" to the copied command[-]")
+			}
+		}
+	}
+	windows := runtime.GOOS == "windows"
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(ui.t("copy_value_json"), copyText(v.JSON)),
+		fyne.NewMenuItem(ui.t("copy_value_curl_read"), copyText(func() (string, error) {
+			return v.CurlRead(ui.apiBaseURL(), windows), nil
+		})),
+		fyne.NewMenuItem(ui.t("copy_value_curl_write"), copyText(func() (string, error) {
+			return v.CurlWrite(ui.apiBaseURL(), windows), nil
+		})),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(ui.t("api_snippets"), func() { ui.showSnippetsDialog(v.NodeID) }),
+	)
+	menu.Items = append(menu.Items, extra...)
+	widget.ShowPopUpMenuAtPosition(menu, ui.window.Canvas(), pos)
+}
+
+// showWatchValueCopyMenu is the context menu of a watch table row.
+func (ui *UI) showWatchValueCopyMenu(id widget.TableCellID, ev *fyne.PointEvent) {
+	ui.watchTableMutex.RLock()
+	index := id.Row - 1
+	if id.Row == 0 || index >= len(ui.watchRows) {
+		ui.watchTableMutex.RUnlock()
+		return
+	}
+	item := ui.watchRows[index]
+	v := controller.ValueCopyFromWatch(item)
+	polled, rate, dedup := item.Polled, item.RateMs, item.Dedup
+	writeItems := ui.booleanWriteItems(item)
+	ui.watchTableMutex.RUnlock()
+
+	pollItem := fyne.NewMenuItem(ui.t("poll_value"), func() { ui.setWatchPolled(v.NodeID, !polled) })
+	pollItem.Checked = polled
+	if ui.config.PollMode == opc.PollAlways {
+		pollItem.Disabled = true
+	}
+	rateItem := fyne.NewMenuItem(ui.t("sampling_rate"), nil)
+	rateItem.ChildMenu = fyne.NewMenu("")
+	for _, ms := range watchRates {
+		label := ui.t("rate_default")
+		if ms > 0 {
+			label = rateInterval(ms).String()
+		}
+		ms := ms
+		choice := fyne.NewMenuItem(label, func() { ui.setWatchRate(v.NodeID, ms) })
+		choice.Checked = ms == rate
+		rateItem.ChildMenu.Items = append(rateItem.ChildMenu.Items, choice)
+	}
+	rateItem.Disabled = polled
+	dedupItem := fyne.NewMenuItem(ui.t("suppress_repeats"), func() { ui.setWatchDedup(v.NodeID, !dedup) })
+	dedupItem.Checked = dedup
+	fullItem := fyne.NewMenuItem(ui.t("value_view_menu"), func() { ui.showFullValueDialog(v.NodeID) })
+	rawItem := fyne.NewMenuItem(ui.t("raw_value_menu"), func() { ui.showRawDataValueDialog(v.NodeID) })
+	extra := []*fyne.MenuItem{fyne.NewMenuItemSeparator(), fullItem, rawItem, rateItem, pollItem, dedupItem}
+	ui.showValueCopyMenu(v, ev.AbsolutePosition, append(extra, writeItems...)...)
+}
+
+// watchRates are the sampling intervals (ms) offered for watch items; 0 is the default
+// subscription.
+var watchRates = []float64{0, 100, 250, 500, 1000, 5000, 10000}
+
+func rateInterval(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// showDetailValueCopyMenu is the context menu of the Value row in the details; the value is read
+// again so that the copy carries its type and timestamp.
+func (ui *UI) showDetailValueCopyMenu(id widget.TableCellID, ev *fyne.PointEvent) {
+	nodeID := string(ui.selectedNodeID)
+	if id.Row >= len(ui.nodeInfoKeys) || ui.nodeInfoKeys[id.Row] != "Value" || nodeID == "" || ui.nodeInfoData["Value"] == "" {
+		return
+	}
+	go func() {
+		attrs, err := ui.controller.ReadNodeAttributes(context.Background(), nodeID)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.showValueCopyMenu(controller.ValueCopyFromAttributes(attrs), ev.AbsolutePosition)
+		})
+	}()
+}
+
+// setWatchPolled switches a watch item between polling and monitoring. The choice is saved by
+// namespace URI, like the watch list.
+func (ui *UI) setWatchPolled(nodeID string, polled bool) {
+	ref := ui.controller.NodeRef(nodeID)
+	ui.config.PolledNodes = slices.DeleteFunc(ui.config.PolledNodes, func(p string) bool {
+		return p == ref || p == nodeID
+	})
+	if polled {
+		ui.config.PolledNodes = append(ui.config.PolledNodes, ref)
+	}
+	ui.saveConfig()
+	go ui.controller.SetWatchPolled(nodeID, polled)
+}
+
+// setWatchDedup switches the suppression of repeated samples of a watch item. The choice is
+// saved by namespace URI, like the watch list.
+func (ui *UI) setWatchDedup(nodeID string, on bool) {
+	ref := ui.controller.NodeRef(nodeID)
+	ui.config.DedupNodes = slices.DeleteFunc(ui.config.DedupNodes, func(p string) bool {
+		return p == ref || p == nodeID
+	})
+	if on {
+		ui.config.DedupNodes = append(ui.config.DedupNodes, ref)
+	}
+	ui.saveConfig()
+	go ui.controller.SetWatchDedup(nodeID, on)
+}
+
+// setWatchRate moves a watch item to the subscription of the sampling interval ms (0 for the
+// default one). The choice is saved by namespace URI, like the watch list.
+func (ui *UI) setWatchRate(nodeID string, ms float64) {
+	ref := ui.controller.NodeRef(nodeID)
+	delete(ui.config.WatchRates, nodeID)
+	delete(ui.config.WatchRates, ref)
+	if ms > 0 {
+		if ui.config.WatchRates == nil {
+			ui.config.WatchRates = make(map[string]float64)
+		}
+		ui.config.WatchRates[ref] = ms
+	}
+	ui.saveConfig()
+	go ui.controller.SetWatchRate(nodeID, ms)
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showDashboardUsersDialog edits the logins of the web dashboard and the API key stored in the
+// config. Without either the dashboard and the API are open to everybody who can reach the API
+// port.
+func (ui *UI) showDashboardUsersDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(ui.t("username"))
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder(ui.t("placeholder_password"))
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder(ui.t("dashboard_confirm_password"))
+	idleEntry := widget.NewEntry()
+	idleEntry.SetPlaceHolder(strconv.Itoa(int(opc.DefaultDashboardIdle.Minutes())))
+	if ui.config.DashboardIdleMinutes > 0 {
+		idleEntry.SetText(strconv.FormatFloat(ui.config.DashboardIdleMinutes, 'f', -1, 64))
+	}
+	hint := widget.NewLabel(ui.t("dashboard_users_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	list := widget.NewList(
+		func() int { return len(ui.config.DashboardUsers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(ui.config.DashboardUsers) {
+				obj.(*widget.Label).SetText(ui.config.DashboardUsers[id].Name)
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id < len(ui.config.DashboardUsers) {
+			nameEntry.SetText(ui.config.DashboardUsers[id].Name)
+			passwordEntry.SetText("")
+			confirmEntry.SetText("")
+		}
+	}
+	newBtn := widget.NewButtonWithIcon(ui.t("dashboard_new_user"), theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		nameEntry.SetText("")
+		passwordEntry.SetText("")
+		confirmEntry.SetText("")
+	})
+
+	saveBtn := widget.NewButtonWithIcon(ui.t("save_btn"), theme.DocumentSaveIcon(), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" {
+			dialog.ShowError(fmt.Errorf("%s is empty", ui.t("username")), ui.window)
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("%s", ui.t("dashboard_password_mismatch")), ui.window)
+			return
+		}
+		hash, err := opc.HashDashboardPassword(passwordEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		u := opc.DashboardUser{Name: name, PasswordHash: hash}
+		replaced := false
+		for i := range ui.config.DashboardUsers {
+			if strings.EqualFold(ui.config.DashboardUsers[i].Name, name) {
+				ui.config.DashboardUsers[i], replaced = u, true
+				break
+			}
+		}
+		if !replaced {
+			ui.config.DashboardUsers = append(ui.config.DashboardUsers, u)
+		}
+		ui.saveConfig()
+		passwordEntry.SetText("")
+		confirmEntry.SetText("")
+		list.Refresh()
+	})
+	saveBtn.Importance = widget.HighImportance
+	deleteBtn := widget.NewButtonWithIcon(ui.t("remove"), theme.DeleteIcon(), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		for i := range ui.config.DashboardUsers {
+			if strings.EqualFold(ui.config.DashboardUsers[i].Name, name) {
+				ui.config.DashboardUsers = append(ui.config.DashboardUsers[:i], ui.config.DashboardUsers[i+1:]...)
+				ui.saveConfig()
+				list.UnselectAll()
+				list.Refresh()
+				nameEntry.SetText("")
+				return
+			}
+		}
+	})
+
+	editor := container.NewVBox(
+		nameEntry,
+		passwordEntry,
+		confirmEntry,
+		container.NewHBox(layout.NewSpacer(), deleteBtn, saveBtn),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabel(ui.t("dashboard_idle_minutes")), nil, idleEntry),
+		ui.apiKeyRow(),
+		hint,
+	)
+	split := container.NewHSplit(container.NewBorder(nil, newBtn, nil, nil, list), editor)
+	split.Offset = 0.3
+	dlg := dialog.NewCustom(ui.t("dashboard_users"), ui.t("close_btn"), split, ui.window)
+	dlg.SetOnClosed(func() {
+		idle := 0.0
+		if s := strings.TrimSpace(idleEntry.Text); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid value '%s'", ui.t("dashboard_idle_minutes"), s), ui.window)
+				return
+			}
+			idle = v
+		}
+		if idle != ui.config.DashboardIdleMinutes {
+			ui.config.DashboardIdleMinutes = idle
+			ui.saveConfig()
+		}
+	})
+	dlg.Resize(fyne.NewSize(600, 440))
+	dlg.Show()
+}
+
+// apiKeyRow shows whether an API key is set, with buttons to generate a new one, which is shown
+// once, and to remove it.
+func (ui *UI) apiKeyRow() fyne.CanvasObject {
+	state := widget.NewLabel("")
+	var removeBtn *widget.Button
+	update := func() {
+		if ui.config.ApiKeyHash != "" {
+			state.SetText(ui.t("api_key_set"))
+			removeBtn.Enable()
+		} else {
+			state.SetText(ui.t("api_key_none"))
+			removeBtn.Disable()
+		}
+	}
+	generateBtn := widget.NewButtonWithIcon(ui.t("api_key_generate"), theme.ViewRefreshIcon(), func() {
+		key, hash, err := opc.GenerateApiKey()
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		ui.config.ApiKeyHash = hash
+		ui.saveConfig()
+		update()
+		keyEntry := widget.NewEntry()
+		keyEntry.SetText(key)
+		msg := widget.NewLabel(ui.t("api_key_shown_once"))
+		msg.Wrapping = fyne.TextWrapWord
+		copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+			ui.window.Clipboard().SetContent(key)
+		})
+		d := dialog.NewCustom(ui.t("api_key"), ui.t("close_btn"), container.NewVBox(msg, container.NewBorder(nil, nil, nil, copyBtn, keyEntry)), ui.window)
+		d.Resize(fyne.NewSize(560, 160))
+		d.Show()
+	})
+	removeBtn = widget.NewButtonWithIcon(ui.t("remove"), theme.DeleteIcon(), func() {
+		ui.config.ApiKeyHash = ""
+		ui.saveConfig()
+		update()
+	})
+	update()
+	return container.NewBorder(nil, nil, widget.NewLabel(ui.t("api_key")), container.NewHBox(generateBtn, removeBtn), state)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showDialDiagnostics explains a failed connect: the error, its likely causes and what to try.
+func (ui *UI) showDialDiagnostics(endpoint string, err error) {
+	kind := opc.ClassifyDialError(err)
+	key := "dial_" + string(kind)
+
+	summary := widget.NewLabelWithStyle(ui.t(key), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	summary.Wrapping = fyne.TextWrapWord
+	errLabel := widget.NewLabel(fmt.Sprintf("%s\n%v", endpoint, err))
+	errLabel.Wrapping = fyne.TextWrapWord
+	errLabel.Importance = widget.LowImportance
+	causes := widget.NewLabel(ui.t(key + "_causes"))
+	causes.Wrapping = fyne.TextWrapWord
+	fixes := widget.NewLabel(ui.t(key + "_fixes"))
+	fixes.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(
+		summary,
+		errLabel,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(ui.t("dial_causes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		causes,
+		widget.NewLabelWithStyle(ui.t("dial_fixes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		fixes,
+	)
+	// The server admin needs to know which certificate to trust
+	if (kind == opc.DialFailureCertificate || kind == opc.DialFailureSecurityChecks) && ui.config.CertFile != "" {
+		content.Add(container.NewBorder(nil, nil, widget.NewLabel(ui.t("dial_client_cert")), nil, widget.NewLabel(ui.config.CertFile)))
+	}
+
+	report := strings.Join([]string{
+		ui.t("connection_diagnostics") + ": " + endpoint,
+		err.Error(),
+		ui.t(key),
+		ui.t("dial_causes") + ":\n" + ui.t(key+"_causes"),
+		ui.t("dial_fixes") + ":\n" + ui.t(key+"_fixes"),
+	}, "\n\n")
+	copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+		ui.app.Clipboard().SetContent(report)
+	})
+	buttons := container.NewHBox(copyBtn)
+	content.Add(buttons)
+
+	dlg := dialog.NewCustom(ui.t("connection_diagnostics"), ui.t("close_btn"), container.NewVScroll(content), ui.window)
+	// Most trust problems are solved on the server; guide through it instead of retrying blindly
+	if (kind == opc.DialFailureCertificate || kind == opc.DialFailureSecurityChecks) && ui.config.CertFile != "" && ui.config.KeyFile != "" {
+		trustBtn := widget.NewButtonWithIcon(ui.t("trust_wait_btn"), theme.MailSendIcon(), func() {
+			dlg.Hide()
+			ui.showCertificateTrustWait()
+		})
+		trustBtn.Importance = widget.HighImportance
+		buttons.Add(trustBtn)
+	}
+	dlg.Resize(fyne.NewSize(560, 460))
+	dlg.Show()
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// onTestEndpointClicked probes the endpoint in the entry (secure channel + GetEndpoints, no session)
+// and reports reachability, latency, security policies and server info.
+func (ui *UI) onTestEndpointClicked() {
+	addr := normalizeEndpoint(ui.endpointEntry.Text)
+	ui.endpointEntry.SetText(addr)
+	ui.testBtn.Disable()
+	go func() {
+		p, err := ui.controller.TestEndpoint(opc.OverrideHost(addr, ui.config.HostOverride))
+		fyne.Do(func() {
+			ui.testBtn.Enable()
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %v", ui.t("endpoint_unreachable"), err), ui.window)
+				return
+			}
+			ms := func(d time.Duration) string { return fmt.Sprintf("%.1f ms", float64(d)/float64(time.Millisecond)) }
+			form := widget.NewForm(
+				widget.NewFormItem(ui.t("endpoint"), widget.NewLabel(p.Endpoint)),
+				widget.NewFormItem(ui.t("channel_open_time"), widget.NewLabel(ms(p.ChannelOpenTime))),
+				widget.NewFormItem(ui.t("round_trip_time"), widget.NewLabel(ms(p.RoundTrip))),
+				widget.NewFormItem(ui.t("server_application"), widget.NewLabel(p.ApplicationName)),
+				widget.NewFormItem("ApplicationUri", widget.NewLabel(p.ApplicationURI)),
+				widget.NewFormItem("ProductUri", widget.NewLabel(p.ProductURI)),
+				widget.NewFormItem(ui.t("security_policies"), widget.NewLabel(strings.Join(p.Policies, "\n"))),
+				widget.NewFormItem(ui.t("user_tokens"), widget.NewLabel(strings.Join(p.UserTokens, ", "))),
+			)
+			dialog.ShowCustom(ui.t("endpoint_reachable"), ui.t("close_btn"), form, ui.window)
+		})
+	}()
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// eventFilterNames lists the built-in filters (all events, alarms and conditions) followed by
+// the saved event filters.
+func (ui *UI) eventFilterNames() []string {
+	names := []string{ui.t("event_type_all"), ui.t("event_type_alarms")}
+	for _, f := range ui.config.EventFilters {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// newEventFilterPicker returns a select of the event filters and a button that opens the filter
+// builder, refreshing the select when it closes.
+func (ui *UI) newEventFilterPicker() (*widget.Select, fyne.CanvasObject) {
+	sel := widget.NewSelect(ui.eventFilterNames(), nil)
+	sel.SetSelected(ui.t("event_type_all"))
+	manage := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
+		ui.showEventFiltersDialog(func() {
+			sel.Options = ui.eventFilterNames()
+			if slices.Contains(sel.Options, sel.Selected) {
+				sel.Refresh()
+				return
+			}
+			sel.SetSelected(ui.t("event_type_all"))
+		})
+	})
+	return sel, container.NewBorder(nil, nil, nil, manage, sel)
+}
+
+// selectedEventFilter maps a picker selection to its filter.
+func (ui *UI) selectedEventFilter(sel *widget.Select) opc.EventFilterPreset {
+	switch sel.Selected {
+	case "", ui.t("event_type_all"):
+		return opc.EventFilterPreset{}
+	case ui.t("event_type_alarms"):
+		return opc.EventFilterPreset{EventType: "ConditionType"}
+	}
+	if p, err := ui.config.EventFilter(sel.Selected); err == nil {
+		return *p
+	}
+	return opc.EventFilterPreset{}
+}
+
+// eventClauseRow is the editor of one where clause in the filter builder.
+type eventClauseRow struct {
+	field *widget.SelectEntry
+	op    *widget.Select
+	value *widget.Entry
+}
+
+// showEventFiltersDialog edits the named event filters stored in the config: the event type,
+// the extra fields to select and the conditions events must meet.
+func (ui *UI) showEventFiltersDialog(onClose func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(ui.t("event_filter_name"))
+	typeEntry := widget.NewSelectEntry(opc.EventTypes)
+	typeEntry.SetPlaceHolder(ui.t("event_type_all"))
+	fieldsCheck := widget.NewCheckGroup(opc.EventFields, nil)
+	fieldsCheck.Horizontal = true
+	otherFields := widget.NewEntry()
+	otherFields.SetPlaceHolder("2:Temperature, LimitState/CurrentState")
+
+	var clauses []*eventClauseRow
+	clauseBox := container.NewVBox()
+	var renderClauses func()
+	addClause := func(c opc.EventClause) {
+		row := &eventClauseRow{
+			field: widget.NewSelectEntry(opc.EventFields),
+			op:    widget.NewSelect(opc.EventFilterOperators, nil),
+			value: widget.NewEntry(),
+		}
+		row.field.SetText(c.Field)
+		row.op.SetSelected(c.Operator)
+		row.value.SetText(c.Value)
+		clauses = append(clauses, row)
+	}
+	renderClauses = func() {
+		clauseBox.RemoveAll()
+		for _, row := range clauses {
+			remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				clauses = slices.DeleteFunc(clauses, func(r *eventClauseRow) bool { return r == row })
+				renderClauses()
+			})
+			clauseBox.Add(container.NewBorder(nil, nil, nil, remove,
+				container.NewGridWithColumns(3, row.field, row.op, row.value)))
+		}
+		if len(clauses) == 0 {
+			lbl := widget.NewLabel(ui.t("event_where_none"))
+			lbl.Importance = widget.LowImportance
+			clauseBox.Add(lbl)
+		}
+	}
+	addClauseBtn := widget.NewButtonWithIcon(ui.t("event_where_add"), theme.ContentAddIcon(), func() {
+		addClause(opc.EventClause{Field: "Severity", Operator: ">=", Value: "500"})
+		renderClauses()
+	})
+
+	load := func(p opc.EventFilterPreset) {
+		nameEntry.SetText(p.Name)
+		typeEntry.SetText(p.EventType)
+		var checked, other []string
+		for _, f := range p.SelectedFields() {
+			if slices.Contains(opc.EventFields, f) {
+				checked = append(checked, f)
+			} else {
+				other = append(other, f)
+			}
+		}
+		fieldsCheck.SetSelected(checked)
+		otherFields.SetText(strings.Join(other, ", "))
+		clauses = nil
+		for _, w := range p.Where {
+			addClause(w)
+		}
+		renderClauses()
+	}
+	current := func() opc.EventFilterPreset {
+		p := opc.EventFilterPreset{
+			Name:      strings.TrimSpace(nameEntry.Text),
+			EventType: strings.TrimSpace(typeEntry.Text),
+		}
+		// Keep the order of the offered fields, then the others as entered
+		for _, f := range opc.EventFields {
+			if slices.Contains(fieldsCheck.Selected, f) {
+				p.Fields = append(p.Fields, f)
+			}
+		}
+		for _, f := range strings.Split(otherFields.Text, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				p.Fields = append(p.Fields, f)
+			}
+		}
+		for _, row := range clauses {
+			p.Where = append(p.Where, opc.EventClause{Field: strings.TrimSpace(row.field.Text), Operator: row.op.Selected, Value: row.value.Text})
+		}
+		return p
+	}
+
+	list := widget.NewList(
+		func() int { return len(ui.config.EventFilters) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(ui.config.EventFilters) {
+				obj.(*widget.Label).SetText(ui.config.EventFilters[id].Name)
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id < len(ui.config.EventFilters) {
+			load(ui.config.EventFilters[id])
+		}
+	}
+	newBtn := widget.NewButtonWithIcon(ui.t("template_new"), theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		load(opc.EventFilterPreset{})
+	})
+
+	saveBtn := widget.NewButtonWithIcon(ui.t("save_btn"), theme.DocumentSaveIcon(), func() {
+		p := current()
+		if err := p.Validate(); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if strings.EqualFold(p.Name, ui.t("event_type_all")) || strings.EqualFold(p.Name, ui.t("event_type_alarms")) {
+			dialog.ShowError(fmt.Errorf("%q is reserved", p.Name), ui.window)
+			return
+		}
+		replaced := false
+		for i := range ui.config.EventFilters {
+			if strings.EqualFold(ui.config.EventFilters[i].Name, p.Name) {
+				ui.config.EventFilters[i], replaced = p, true
+				break
+			}
+		}
+		if !replaced {
+			ui.config.EventFilters = append(ui.config.EventFilters, p)
+		}
+		ui.saveConfig()
+		list.Refresh()
+	})
+	saveBtn.Importance = widget.HighImportance
+	deleteBtn := widget.NewButtonWithIcon(ui.t("remove"), theme.DeleteIcon(), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		for i := range ui.config.EventFilters {
+			if strings.EqualFold(ui.config.EventFilters[i].Name, name) {
+				ui.config.EventFilters = append(ui.config.EventFilters[:i], ui.config.EventFilters[i+1:]...)
+				ui.saveConfig()
+				list.UnselectAll()
+				list.Refresh()
+				load(opc.EventFilterPreset{})
+				return
+			}
+		}
+	})
+	load(opc.EventFilterPreset{})
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("event_filter_name"), nameEntry),
+		widget.NewFormItem(ui.t("event_type"), typeEntry),
+		widget.NewFormItem(ui.t("event_fields"), fieldsCheck),
+		widget.NewFormItem(ui.t("event_fields_other"), otherFields),
+	)
+	where := container.NewBorder(
+		container.NewHBox(widget.NewLabelWithStyle(ui.t("event_where"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), layout.NewSpacer(), addClauseBtn),
+		nil, nil, nil, clauseBox)
+	editor := container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), deleteBtn, saveBtn), nil, nil,
+		container.NewVScroll(container.NewVBox(form, where)))
+	split := container.NewHSplit(container.NewBorder(nil, newBtn, nil, nil, list), editor)
+	split.Offset = 0.25
+	dlg := dialog.NewCustom(ui.t("event_filters"), ui.t("close_btn"), split, ui.window)
+	dlg.SetOnClosed(func() {
+		if onClose != nil {
+			onClose()
+		}
+	})
+	dlg.Resize(fyne.NewSize(860, 560))
+	dlg.Show()
+}
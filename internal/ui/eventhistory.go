@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxLiveEvents is how many live events the event history dialog keeps.
+const maxLiveEvents = 1000
+
+// showEventHistoryDialog reads the events a server historized for a notifier, such as the alarm
+// log kept on the Server object, or shows its events live as they arrive.
+func (ui *UI) showEventHistoryDialog(nodeID string) {
+	nodeEntry := widget.NewEntry()
+	nodeEntry.SetPlaceHolder("i=2253")
+	nodeEntry.SetText(nodeID)
+
+	now := time.Now()
+	startEntry := widget.NewEntry()
+	startEntry.SetText(now.Add(-24 * time.Hour).Format(historyTimeLayout))
+	endEntry := widget.NewEntry()
+	endEntry.SetText(now.Format(historyTimeLayout))
+
+	filterSelect, filterPicker := ui.newEventFilterPicker()
+	filterSelect.SetSelected(ui.t("event_type_alarms"))
+
+	fieldsEntry := widget.NewEntry()
+	fieldsEntry.SetPlaceHolder("ActiveState/Id, AckedState/Id")
+
+	var rows []controller.HistoryEvent
+	var extra []string
+	headers := []string{"Time", "Severity", "Source", "Message", "Event Type"}
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(headers) + len(extra) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				if id.Col < len(headers) {
+					lbl.SetText(headers[id.Col])
+				} else {
+					lbl.SetText(extra[id.Col-len(headers)])
+				}
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+			ev := rows[id.Row-1]
+			switch id.Col {
+			case 0:
+				lbl.SetText(ev.Time.Local().Format("2006-01-02 15:04:05.000"))
+			case 1:
+				lbl.SetText(strconv.Itoa(int(ev.Severity)))
+			case 2:
+				if ev.SourceName != "" {
+					lbl.SetText(ev.SourceName)
+				} else {
+					lbl.SetText(ev.SourceNode)
+				}
+			case 3:
+				lbl.SetText(ev.Message)
+			case 4:
+				lbl.SetText(ev.EventType)
+			default:
+				lbl.SetText(ev.Fields[extra[id.Col-len(headers)]])
+			}
+		},
+	)
+	for i, w := range []float32{190, 70, 160, 320, 180} {
+		table.SetColumnWidth(i, w)
+	}
+	countLbl := widget.NewLabel("")
+	setExtra := func(fields []string) {
+		extra = fields
+		for i := range extra {
+			table.SetColumnWidth(len(headers)+i, 140)
+		}
+	}
+	// filter is the selected event filter with the extra fields entered in the dialog
+	filter := func() opc.EventFilterPreset {
+		preset := ui.selectedEventFilter(filterSelect)
+		for _, f := range strings.Split(fieldsEntry.Text, ",") {
+			if f = strings.TrimSpace(f); f != "" && !slices.Contains(preset.Fields, f) {
+				preset.Fields = append(preset.Fields, f)
+			}
+		}
+		return preset
+	}
+
+	var live *opc.EventSubscription
+	var liveBtn *widget.Button
+	closed := false
+	stopLive := func() {
+		if live != nil {
+			es := live
+			live = nil
+			go es.Close()
+		}
+		liveBtn.SetText(ui.t("event_live"))
+		liveBtn.SetIcon(theme.MediaPlayIcon())
+	}
+
+	readBtn := widget.NewButtonWithIcon(ui.t("read_btn"), theme.SearchIcon(), nil)
+	liveBtn = widget.NewButtonWithIcon(ui.t("event_live"), theme.MediaPlayIcon(), func() {
+		if live != nil {
+			stopLive()
+			return
+		}
+		preset := filter()
+		notifier := nodeEntry.Text
+		liveBtn.Disable()
+		go func() {
+			es, err := ui.controller.SubscribeEvents(notifier, preset, func(ev controller.HistoryEvent) {
+				fyne.Do(func() {
+					if live == nil {
+						return
+					}
+					rows = append([]controller.HistoryEvent{ev}, rows[:min(len(rows), maxLiveEvents-1)]...)
+					countLbl.SetText(fmt.Sprintf("%d", len(rows)))
+					table.Refresh()
+				})
+			})
+			fyne.Do(func() {
+				liveBtn.Enable()
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				if closed {
+					go es.Close()
+					return
+				}
+				live = es
+				rows = nil
+				setExtra(preset.SelectedFields())
+				countLbl.SetText("0")
+				table.Refresh()
+				liveBtn.SetText(ui.t("event_live_stop"))
+				liveBtn.SetIcon(theme.MediaStopIcon())
+			})
+		}()
+	})
+	readBtn.OnTapped = func() {
+		stopLive()
+		start, err := time.ParseInLocation(historyTimeLayout, strings.TrimSpace(startEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %v", ui.t("start_time"), err), ui.window)
+			return
+		}
+		end, err := time.ParseInLocation(historyTimeLayout, strings.TrimSpace(endEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %v", ui.t("end_time"), err), ui.window)
+			return
+		}
+		preset := filter()
+		req := controller.EventHistoryRequest{
+			NodeID:    nodeEntry.Text,
+			Start:     start,
+			End:       end,
+			EventType: preset.EventType,
+			Fields:    preset.SelectedFields(),
+			Where:     preset.Where,
+		}
+		readBtn.Disable()
+		go func() {
+			events, rerr := ui.controller.ReadEventHistory(context.Background(), req)
+			fyne.Do(func() {
+				readBtn.Enable()
+				truncated := errors.Is(rerr, opc.ErrHistoryTruncated)
+				if rerr != nil && !truncated {
+					dialog.ShowError(rerr, ui.window)
+					return
+				}
+				rows = events
+				setExtra(req.Fields)
+				if truncated {
+					countLbl.SetText(fmt.Sprintf(ui.t("history_truncated"), len(events)))
+				} else {
+					countLbl.SetText(fmt.Sprintf("%d", len(events)))
+				}
+				table.Refresh()
+			})
+		}()
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("event_notifier"), nodeEntry),
+		widget.NewFormItem(ui.t("start_time"), startEntry),
+		widget.NewFormItem(ui.t("end_time"), endEntry),
+		widget.NewFormItem(ui.t("event_filter"), filterPicker),
+		widget.NewFormItem(ui.t("event_fields"), fieldsEntry),
+	)
+	top := container.NewVBox(form, container.NewHBox(layout.NewSpacer(), countLbl, liveBtn, readBtn))
+	content := container.NewBorder(top, nil, nil, nil, table)
+
+	d := dialog.NewCustom(ui.t("event_history"), ui.t("close_btn"), content, ui.window)
+	d.SetOnClosed(func() {
+		closed = true
+		stopLive()
+	})
+	winSize := ui.window.Canvas().Size()
+	d.Resize(fyne.NewSize(winSize.Width*0.8, winSize.Height*0.8))
+	d.Show()
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// exportTemplateNames lists the standard layout followed by the configured templates.
+func (ui *UI) exportTemplateNames() []string {
+	names := []string{ui.t("template_standard")}
+	for _, t := range ui.config.ExportTemplates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// newExportTemplatePicker returns a select of the export templates and a button that opens the
+// template editor, refreshing the select when it closes. An empty selection or the standard
+// entry means the export's own layout.
+func (ui *UI) newExportTemplatePicker() (*widget.Select, fyne.CanvasObject) {
+	sel := widget.NewSelect(ui.exportTemplateNames(), nil)
+	sel.SetSelected(ui.t("template_standard"))
+	manage := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
+		ui.showExportTemplatesDialog(func() {
+			sel.Options = ui.exportTemplateNames()
+			for _, name := range sel.Options {
+				if name == sel.Selected {
+					sel.Refresh()
+					return
+				}
+			}
+			sel.SetSelected(ui.t("template_standard"))
+		})
+	})
+	return sel, container.NewBorder(nil, nil, nil, manage, sel)
+}
+
+// selectedExportTemplate maps a picker selection to a template name ("" for the standard layout).
+func (ui *UI) selectedExportTemplate(sel *widget.Select) string {
+	if sel.Selected == ui.t("template_standard") {
+		return ""
+	}
+	return sel.Selected
+}
+
+// showExportTemplatesDialog edits the named export column layouts stored in the config.
+// Columns are written one per line as "field" or "field: Header".
+func (ui *UI) showExportTemplatesDialog(onClose func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(ui.t("template_name"))
+	columnsEntry := widget.NewMultiLineEntry()
+	columnsEntry.SetMinRowsVisible(8)
+	columnsEntry.SetPlaceHolder("node_id: Tag\nname: Description\ndata_type")
+	fields := make([]string, len(opc.ExportFields))
+	for i, f := range opc.ExportFields {
+		fields[i] = f.Field
+	}
+	hint := widget.NewLabel(fmt.Sprintf(ui.t("template_fields_hint"), strings.Join(fields, ", ")))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	list := widget.NewList(
+		func() int { return len(ui.config.ExportTemplates) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(ui.config.ExportTemplates) {
+				obj.(*widget.Label).SetText(ui.config.ExportTemplates[id].Name)
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id < len(ui.config.ExportTemplates) {
+			t := ui.config.ExportTemplates[id]
+			nameEntry.SetText(t.Name)
+			columnsEntry.SetText(t.String())
+		}
+	}
+	newBtn := widget.NewButtonWithIcon(ui.t("template_new"), theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		nameEntry.SetText("")
+		columnsEntry.SetText(opc.DefaultExportTemplate().String())
+	})
+
+	saveBtn := widget.NewButtonWithIcon(ui.t("save_btn"), theme.DocumentSaveIcon(), func() {
+		cols, err := opc.ParseExportColumns(columnsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		t := opc.ExportTemplate{Name: strings.TrimSpace(nameEntry.Text), Columns: cols}
+		if err := t.Validate(); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if strings.EqualFold(t.Name, ui.t("template_standard")) {
+			dialog.ShowError(fmt.Errorf("%q is reserved", t.Name), ui.window)
+			return
+		}
+		replaced := false
+		for i := range ui.config.ExportTemplates {
+			if strings.EqualFold(ui.config.ExportTemplates[i].Name, t.Name) {
+				ui.config.ExportTemplates[i], replaced = t, true
+				break
+			}
+		}
+		if !replaced {
+			ui.config.ExportTemplates = append(ui.config.ExportTemplates, t)
+		}
+		ui.saveConfig()
+		list.Refresh()
+	})
+	saveBtn.Importance = widget.HighImportance
+	deleteBtn := widget.NewButtonWithIcon(ui.t("remove"), theme.DeleteIcon(), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		for i := range ui.config.ExportTemplates {
+			if strings.EqualFold(ui.config.ExportTemplates[i].Name, name) {
+				ui.config.ExportTemplates = append(ui.config.ExportTemplates[:i], ui.config.ExportTemplates[i+1:]...)
+				ui.saveConfig()
+				list.UnselectAll()
+				list.Refresh()
+				nameEntry.SetText("")
+				columnsEntry.SetText("")
+				return
+			}
+		}
+	})
+
+	editor := container.NewBorder(nameEntry, container.NewVBox(hint, container.NewHBox(layout.NewSpacer(), deleteBtn, saveBtn)), nil, nil, columnsEntry)
+	split := container.NewHSplit(container.NewBorder(nil, newBtn, nil, nil, list), editor)
+	split.Offset = 0.3
+	dlg := dialog.NewCustom(ui.t("export_templates"), ui.t("close_btn"), split, ui.window)
+	dlg.SetOnClosed(func() {
+		if onClose != nil {
+			onClose()
+		}
+	})
+	dlg.Resize(fyne.NewSize(640, 440))
+	dlg.Show()
+}
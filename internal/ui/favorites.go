@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// makeFavoritesPanel lists the starred nodes with read, watch, write and unstar actions.
+// Favorites are saved in the config by namespace URI and resolved against the current session.
+func (ui *UI) makeFavoritesPanel() fyne.CanvasObject {
+	ui.favoritesList = ui.newNodeList(
+		func() []opc.Favorite { return ui.config.Favorites },
+		func(fav opc.Favorite) { ui.removeFavorite(fav.Ref) },
+	)
+	empty := widget.NewLabelWithStyle(ui.t("favorites_empty"), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	empty.Wrapping = fyne.TextWrapWord
+	ui.favoritesEmpty = empty
+	ui.refreshFavorites()
+	return container.NewStack(ui.favoritesList, container.NewCenter(empty))
+}
+
+// newNodeList shows saved node references (name and reference) with read, watch, write and
+// remove buttons.
+func (ui *UI) newNodeList(items func() []opc.Favorite, remove func(opc.Favorite)) *widget.List {
+	return widget.NewList(
+		func() int { return len(items()) },
+		func() fyne.CanvasObject {
+			name := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			name.Truncation = fyne.TextTruncateEllipsis
+			ref := widget.NewLabel("")
+			ref.Truncation = fyne.TextTruncateEllipsis
+			actions := container.NewHBox(
+				widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), nil),
+				widget.NewButtonWithIcon("", theme.ContentAddIcon(), nil),
+				widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil),
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+			)
+			return container.NewBorder(nil, nil, nil, actions, container.NewVBox(name, ref))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			list := items()
+			if id >= len(list) {
+				return
+			}
+			fav := list[id]
+			border := obj.(*fyne.Container)
+			labels := border.Objects[0].(*fyne.Container)
+			labels.Objects[0].(*widget.Label).SetText(fav.Name)
+			labels.Objects[1].(*widget.Label).SetText(fav.Ref)
+			buttons := border.Objects[1].(*fyne.Container).Objects
+			buttons[0].(*widget.Button).OnTapped = func() { ui.readFavorite(fav) }
+			buttons[1].(*widget.Button).OnTapped = func() {
+				if nodeID, ok := ui.resolveFavorite(fav); ok {
+					go ui.controller.AddWatch(context.Background(), nodeID)
+				}
+			}
+			buttons[2].(*widget.Button).OnTapped = func() {
+				if nodeID, ok := ui.resolveFavorite(fav); ok {
+					ui.openWriteForNode(nodeID)
+				}
+			}
+			buttons[3].(*widget.Button).OnTapped = func() { remove(fav) }
+		},
+	)
+}
+
+func (ui *UI) refreshFavorites() {
+	if ui.favoritesList == nil {
+		return
+	}
+	if len(ui.config.Favorites) == 0 {
+		ui.favoritesEmpty.Show()
+	} else {
+		ui.favoritesEmpty.Hide()
+	}
+	ui.favoritesList.Refresh()
+}
+
+// favoriteIndex returns the position of nodeID in the favorites, or -1.
+func (ui *UI) favoriteIndex(nodeID string) int {
+	ref := ui.controller.NodeRef(nodeID)
+	for i, f := range ui.config.Favorites {
+		if f.Ref == ref || f.Ref == nodeID {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleFavorite stars or unstars nodeID.
+func (ui *UI) toggleFavorite(nodeID, name string) {
+	if i := ui.favoriteIndex(nodeID); i >= 0 {
+		ui.removeFavorite(ui.config.Favorites[i].Ref)
+		return
+	}
+	if node := ui.controller.GetNode(nodeID); node != nil && node.Name != "" {
+		name = node.Name
+	}
+	if name == "" {
+		name = nodeID
+	}
+	ui.config.Favorites = append(ui.config.Favorites, opc.Favorite{Ref: ui.controller.NodeRef(nodeID), Name: name})
+	ui.saveConfig()
+	ui.refreshFavorites()
+}
+
+func (ui *UI) removeFavorite(ref string) {
+	for i, f := range ui.config.Favorites {
+		if f.Ref == ref {
+			ui.config.Favorites = append(ui.config.Favorites[:i], ui.config.Favorites[i+1:]...)
+			break
+		}
+	}
+	ui.saveConfig()
+	ui.refreshFavorites()
+}
+
+// resolveFavorite maps a favorite to a NodeID of the current session, reporting failures.
+func (ui *UI) resolveFavorite(fav opc.Favorite) (string, bool) {
+	if !ui.controller.ConnectionStatus().Connected && !ui.controller.IsOffline() {
+		dialog.ShowInformation(ui.t("favorites"), ui.t("connect_first"), ui.window)
+		return "", false
+	}
+	nodeID, err := ui.controller.ResolveNodeRef(fav.Ref)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", fav.Name, err), ui.window)
+		return "", false
+	}
+	return nodeID, true
+}
+
+// readFavorite reads the node once and shows its value.
+func (ui *UI) readFavorite(fav opc.Favorite) {
+	nodeID, ok := ui.resolveFavorite(fav)
+	if !ok {
+		return
+	}
+	go func() {
+		attrs, err := ui.controller.ReadNodeAttributes(context.Background(), nodeID)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			form := widget.NewForm(
+				widget.NewFormItem("NodeID", widget.NewLabel(attrs.NodeID)),
+				widget.NewFormItem("DataType", widget.NewLabel(attrs.DataType)),
+				widget.NewFormItem(ui.t("value"), widget.NewLabel(attrs.Value)),
+				widget.NewFormItem(ui.t("timestamp"), widget.NewLabel(attrs.SourceTimestamp)),
+			)
+			dialog.ShowCustom(fav.Name, ui.t("close_btn"), form, ui.window)
+		})
+	}()
+}
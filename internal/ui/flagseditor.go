@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// flagsEditor edits the value of an OptionSet write as one check per bit, kept in sync with the
+// number in its entry.
+type flagsEditor struct {
+	opts    *controller.OptionSet
+	entry   *widget.Entry
+	checks  []*widget.Check
+	syncing bool // the checks are being set from the entry
+}
+
+func (ui *UI) newFlagsEditor(opts *controller.OptionSet, initial uint64) *flagsEditor {
+	e := &flagsEditor{opts: opts, entry: widget.NewEntry()}
+	e.entry.SetPlaceHolder(ui.t("flags_value_hint"))
+	for i := range opts.Bits {
+		bit := uint64(1) << i
+		check := widget.NewCheck(fmt.Sprintf("%d  %s", i, opts.BitName(i)), func(on bool) {
+			if e.syncing {
+				return
+			}
+			mask, _ := opts.Parse(e.entry.Text)
+			if on {
+				mask |= bit
+			} else {
+				mask &^= bit
+			}
+			e.entry.SetText(strconv.FormatUint(mask, 10))
+		})
+		e.checks = append(e.checks, check)
+	}
+	e.entry.OnChanged = func(s string) {
+		mask, err := opts.Parse(s)
+		if err != nil {
+			return
+		}
+		e.syncing = true
+		for i, check := range e.checks {
+			check.SetChecked(mask&(1<<i) != 0)
+		}
+		e.syncing = false
+	}
+	e.entry.SetText(strconv.FormatUint(initial, 10))
+	return e
+}
+
+// value is the value passed to WriteValue, the mask as a number.
+func (e *flagsEditor) value() (string, error) {
+	mask, err := e.opts.Parse(e.entry.Text)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(mask, 10), nil
+}
+
+func (e *flagsEditor) content() fyne.CanvasObject {
+	objs := make([]fyne.CanvasObject, len(e.checks))
+	for i, c := range e.checks {
+		objs[i] = c
+	}
+	return container.NewVBox(e.entry, container.NewGridWithColumns(2, objs...))
+}
+
+// watchValueMask returns the watch item nodeID's value as a mask of opts, 0 when it is not
+// watched.
+func (ui *UI) watchValueMask(nodeID string, opts *controller.OptionSet) uint64 {
+	ui.watchTableMutex.RLock()
+	defer ui.watchTableMutex.RUnlock()
+	for _, it := range ui.watchRows {
+		if it.NodeID == nodeID {
+			mask, _ := opts.Parse(it.Value)
+			return mask
+		}
+	}
+	return 0
+}
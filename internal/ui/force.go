@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"image/color"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showForceDialog forces a local value on a watch row or releases it. Nothing is written to the
+// server; forced rows are flagged in the Value column until released.
+func (ui *UI) showForceDialog(item *controller.WatchItem) {
+	nodeID := item.NodeID
+	serverValue := item.Value
+	if item.Forced {
+		serverValue = item.ServerValue
+	}
+	valueEntry := widget.NewEntry()
+	valueEntry.SetText(item.Value)
+
+	var dlg dialog.Dialog
+	releaseBtn := widget.NewButton(ui.t("release_force"), func() {
+		dlg.Hide()
+		go ui.controller.ReleaseForce(nodeID)
+	})
+	if !item.Forced {
+		releaseBtn.Disable()
+	}
+	releaseAllBtn := widget.NewButton(ui.t("release_all_forces"), func() {
+		dlg.Hide()
+		go ui.controller.ReleaseAllForces()
+	})
+	if len(ui.controller.ForcedNodes()) == 0 {
+		releaseAllBtn.Disable()
+	}
+
+	dlg = dialog.NewForm(ui.t("force_value")+": "+nodeID, ui.t("force_value"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem("", widget.NewLabel(ui.t("force_hint"))),
+			widget.NewFormItem(ui.t("server_value"), widget.NewLabel(serverValue)),
+			widget.NewFormItem(ui.t("force_value"), valueEntry),
+			widget.NewFormItem("", container.NewHBox(releaseBtn, releaseAllBtn)),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := ui.controller.ForceValue(nodeID, valueEntry.Text); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+	dlg.Show()
+}
+
+// warningTint is the background of forced watch values and of overflowing items' Severity.
+func warningTint() color.Color {
+	r, g, b, _ := theme.Color(theme.ColorNameWarning).RGBA()
+	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0x60}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showGoldenValueDialog edits the expected value of a watch row. An empty expected value removes
+// it. Expected values are saved in the config by namespace URI, like the watch list.
+func (ui *UI) showGoldenValueDialog(item *controller.WatchItem) {
+	expectedEntry := widget.NewEntry()
+	toleranceEntry := widget.NewEntry()
+	toleranceEntry.SetPlaceHolder("0")
+	if item.Golden != nil {
+		expectedEntry.SetText(item.Golden.Expected)
+		if item.Golden.Tolerance != 0 {
+			toleranceEntry.SetText(strconv.FormatFloat(item.Golden.Tolerance, 'g', -1, 64))
+		}
+	} else {
+		expectedEntry.SetText(item.Value)
+	}
+	expectedEntry.SetPlaceHolder(ui.t("golden_empty_hint"))
+
+	nodeID := item.NodeID
+	dialog.ShowForm(ui.t("expected_value")+": "+nodeID, ui.t("save_btn"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem(ui.t("current_value"), widget.NewLabel(item.Value)),
+			widget.NewFormItem(ui.t("expected_value"), expectedEntry),
+			widget.NewFormItem(ui.t("tolerance"), toleranceEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			var g *opc.GoldenValue
+			if expected := strings.TrimSpace(expectedEntry.Text); expected != "" {
+				g = &opc.GoldenValue{Expected: expected}
+				if s := strings.TrimSpace(toleranceEntry.Text); s != "" {
+					tol, err := strconv.ParseFloat(s, 64)
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s: %v", ui.t("tolerance"), err), ui.window)
+						return
+					}
+					g.Tolerance = tol
+				}
+			}
+
+			ref := ui.controller.NodeRef(nodeID)
+			if g == nil {
+				delete(ui.config.GoldenValues, ref)
+				delete(ui.config.GoldenValues, nodeID)
+			} else {
+				if ui.config.GoldenValues == nil {
+					ui.config.GoldenValues = make(map[string]opc.GoldenValue)
+				}
+				ui.config.GoldenValues[ref] = *g
+			}
+			ui.saveConfig()
+			go ui.controller.SetGoldenValue(nodeID, g)
+		}, ui.window)
+}
+
+// deviationColor is the background of watch cells whose value differs from the expected value.
+func deviationColor() color.Color {
+	r, g, b, _ := theme.Color(theme.ColorNameError).RGBA()
+	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0x60}
+}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+// showHistoryDialog lets the user read raw or aggregated history for a node.
+func (ui *UI) showHistoryDialog(nodeID string) {
+	rawMode := ui.t("raw")
+	modes := append([]string{rawMode}, controller.AggregateNames()...)
+	modeSelect := widget.NewSelect(modes, nil)
+	modeSelect.SetSelected(rawMode)
+
+	now := time.Now()
+	startEntry := widget.NewEntry()
+	startEntry.SetText(now.Add(-1 * time.Hour).Format(historyTimeLayout))
+	endEntry := widget.NewEntry()
+	endEntry.SetText(now.Format(historyTimeLayout))
+
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetPlaceHolder(ui.t("history_interval_s"))
+	intervalEntry.SetText("60")
+	intervalEntry.Disable()
+	modeSelect.OnChanged = func(s string) {
+		if s == rawMode {
+			intervalEntry.Disable()
+		} else {
+			intervalEntry.Enable()
+		}
+	}
+
+	var rows []*controller.HistoryValue
+	headers := []string{"Timestamp", "Value", "Status"}
+	locale := ui.config.DisplayLocale()
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+			hv := rows[id.Row-1]
+			switch id.Col {
+			case 0:
+				lbl.SetText(locale.Value(hv.Timestamp))
+			case 1:
+				lbl.SetText(locale.Value(hv.Value))
+			case 2:
+				lbl.SetText(hv.Status)
+			}
+		},
+	)
+	table.SetColumnWidth(0, 190)
+	table.SetColumnWidth(1, 200)
+	table.SetColumnWidth(2, 160)
+	countLbl := widget.NewLabel("")
+
+	readBtn := widget.NewButtonWithIcon(ui.t("read_btn"), theme.SearchIcon(), nil)
+	readBtn.OnTapped = func() {
+		start, err := time.ParseInLocation(historyTimeLayout, strings.TrimSpace(startEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %v", ui.t("start_time"), err), ui.window)
+			return
+		}
+		end, err := time.ParseInLocation(historyTimeLayout, strings.TrimSpace(endEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %v", ui.t("end_time"), err), ui.window)
+			return
+		}
+		mode := modeSelect.Selected
+		var interval time.Duration
+		if mode != rawMode {
+			secs, err := strconv.ParseFloat(strings.TrimSpace(intervalEntry.Text), 64)
+			if err != nil || secs <= 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid value", ui.t("processing_interval")), ui.window)
+				return
+			}
+			interval = time.Duration(secs * float64(time.Second))
+		}
+		readBtn.Disable()
+		go func() {
+			var values []*controller.HistoryValue
+			var rerr error
+			if mode == rawMode {
+				values, rerr = ui.controller.ReadHistoryRaw(context.Background(), nodeID, start, end, 0)
+			} else {
+				values, rerr = ui.controller.ReadHistoryAggregate(context.Background(), nodeID, mode, start, end, interval)
+			}
+			fyne.Do(func() {
+				readBtn.Enable()
+				truncated := errors.Is(rerr, opc.ErrHistoryTruncated)
+				if rerr != nil && !truncated {
+					dialog.ShowError(rerr, ui.window)
+					return
+				}
+				rows = values
+				if truncated {
+					countLbl.SetText(fmt.Sprintf(ui.t("history_truncated"), len(values)))
+				} else {
+					countLbl.SetText(fmt.Sprintf("%d", len(values)))
+				}
+				table.Refresh()
+			})
+		}()
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("history_mode"), modeSelect),
+		widget.NewFormItem(ui.t("start_time"), startEntry),
+		widget.NewFormItem(ui.t("end_time"), endEntry),
+		widget.NewFormItem(ui.t("processing_interval"), intervalEntry),
+	)
+	editBtn := widget.NewButtonWithIcon(ui.t("edit_history"), theme.DocumentCreateIcon(), func() {
+		ui.showHistoryEditDialog(nodeID, startEntry.Text, endEntry.Text, readBtn.OnTapped)
+	})
+	exportBtn := widget.NewButtonWithIcon(ui.t("export_ua_json"), theme.DownloadIcon(), func() {
+		values := rows
+		ui.saveUAJSON("history.json", func(path string) error {
+			return ui.controller.ExportHistoryUAJSON(nodeID, values, path)
+		})
+	})
+	excelBtn := widget.NewButtonWithIcon(ui.t("export_excel"), theme.DownloadIcon(), func() {
+		values := rows
+		ui.saveExcel("history.xlsx", func(path string) error {
+			return ui.controller.ExportHistoryExcel(nodeID, nodeID, values, path, true)
+		})
+	})
+	top := container.NewVBox(form, container.NewHBox(layout.NewSpacer(), countLbl, exportBtn, excelBtn, editBtn, readBtn))
+	content := container.NewBorder(top, nil, nil, nil, table)
+
+	d := dialog.NewCustom(ui.t("history_dialog")+" - "+nodeID, ui.t("close_btn"), content, ui.window)
+	winSize := ui.window.Canvas().Size()
+	d.Resize(fyne.NewSize(winSize.Width*0.7, winSize.Height*0.8))
+	d.Show()
+}
+
+// showHistoryEditDialog performs a HistoryUpdate (insert/replace/update a single value,
+// or delete a raw range) after an explicit confirmation. onDone is called on success.
+func (ui *UI) showHistoryEditDialog(nodeID, defaultStart, defaultEnd string, onDone func()) {
+	deleteMode := ui.t("delete_range")
+	modeSelect := widget.NewSelect(append(controller.HistoryUpdateModes(), deleteMode), nil)
+
+	tsEntry := widget.NewEntry()
+	tsEntry.SetText(time.Now().Format(historyTimeLayout))
+	valueEntry := widget.NewEntry()
+	startEntry := widget.NewEntry()
+	startEntry.SetText(defaultStart)
+	endEntry := widget.NewEntry()
+	endEntry.SetText(defaultEnd)
+
+	modeSelect.OnChanged = func(s string) {
+		if s == deleteMode {
+			tsEntry.Disable()
+			valueEntry.Disable()
+			startEntry.Enable()
+			endEntry.Enable()
+		} else {
+			tsEntry.Enable()
+			valueEntry.Enable()
+			startEntry.Disable()
+			endEntry.Disable()
+		}
+	}
+	modeSelect.SetSelected(controller.HistoryUpdateModes()[0])
+
+	parse := func(label, v string) (time.Time, error) {
+		t, err := time.ParseInLocation(historyTimeLayout, strings.TrimSpace(v), time.Local)
+		if err != nil {
+			return t, fmt.Errorf("%s: %v", label, err)
+		}
+		return t, nil
+	}
+
+	dialog.ShowForm(ui.t("edit_history")+" - "+nodeID, ui.t("apply_btn"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem(ui.t("history_update_mode"), modeSelect),
+			widget.NewFormItem(ui.t("timestamp"), tsEntry),
+			widget.NewFormItem(ui.t("value"), valueEntry),
+			widget.NewFormItem(ui.t("start_time"), startEntry),
+			widget.NewFormItem(ui.t("end_time"), endEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			mode := modeSelect.Selected
+			var run func() error
+			if mode == deleteMode {
+				start, err := parse(ui.t("start_time"), startEntry.Text)
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				end, err := parse(ui.t("end_time"), endEntry.Text)
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				run = func() error { return ui.controller.DeleteHistoryRaw(context.Background(), nodeID, start, end) }
+			} else {
+				ts, err := parse(ui.t("timestamp"), tsEntry.Text)
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				samples := []controller.HistorySample{{Timestamp: ts, Value: valueEntry.Text}}
+				run = func() error { return ui.controller.UpdateHistory(context.Background(), nodeID, mode, "", samples) }
+			}
+			dialog.ShowConfirm(ui.t("edit_history"), ui.t("confirm_history_edit"), func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				go func() {
+					err := run()
+					fyne.Do(func() {
+						if err != nil {
+							dialog.ShowError(err, ui.window)
+							return
+						}
+						if onDone != nil {
+							onDone()
+						}
+					})
+				}()
+			}, ui.window)
+		}, ui.window)
+}
+
+// saveUAJSON asks for a target file and runs export on it in the background.
+func (ui *UI) saveUAJSON(defaultName string, export func(path string) error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		filePath := writer.URI().Path()
+		writer.Close()
+		go func() {
+			err := export(filePath)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				dialog.ShowInformation(ui.t("export_ua_json"), fmt.Sprintf(ui.t("ua_json_exported"), filePath), ui.window)
+			})
+		}()
+	}, ui.window)
+	saveDialog.SetFileName(defaultName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	saveDialog.Show()
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// withNodeReport builds the report of the selected node in the background and passes it to fn
+// on the UI thread.
+func (ui *UI) withNodeReport(fn func(r *controller.NodeReport)) {
+	nodeID := string(ui.selectedNodeID)
+	if nodeID == "" || nodeID == ui.virtualRoot {
+		return
+	}
+	go func() {
+		r, err := ui.controller.BuildNodeReport(nodeID)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			fn(r)
+		})
+	}()
+}
+
+// showCopyDetailsMenu offers copying the selected node's details as JSON or Markdown.
+func (ui *UI) showCopyDetailsMenu() {
+	copyAs := func(render func(r *controller.NodeReport) (string, error)) func() {
+		return func() {
+			ui.withNodeReport(func(r *controller.NodeReport) {
+				text, err := render(r)
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				ui.app.Clipboard().SetContent(text)
+				ui.controller.Log(fmt.Sprintf("[green]Copied details of %s[-]", r.Attributes.NodeID))
+			})
+		}
+	}
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem("JSON", copyAs(func(r *controller.NodeReport) (string, error) {
+			data, err := r.JSON()
+			return string(data), err
+		})),
+		fyne.NewMenuItem("Markdown", copyAs(func(r *controller.NodeReport) (string, error) {
+			return r.Markdown(), nil
+		})),
+	)
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(ui.copyDetailsBtn)
+	pos = pos.AddXY(0, ui.copyDetailsBtn.Size().Height)
+	widget.ShowPopUpMenuAtPosition(menu, ui.window.Canvas(), pos)
+}
+
+// exportNodeReport writes the selected node's report to a JSON or HTML file.
+func (ui *UI) exportNodeReport() {
+	ui.withNodeReport(func(r *controller.NodeReport) {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			filePath := writer.URI().Path()
+			writer.Close()
+			if err := controller.WriteNodeReport(filePath, r); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.controller.Log(fmt.Sprintf("[green]Node report written to %s[-]", filePath))
+		}, ui.window)
+		name := strings.NewReplacer(";", "_", "=", "", ":", "_", "/", "_").Replace(r.Attributes.NodeID)
+		saveDialog.SetFileName("node_" + name + ".html")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".html", ".json"}))
+		saveDialog.Show()
+	})
+}
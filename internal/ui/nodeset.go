@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showNodeSetMenu offers the NodeSet2 actions below the NodeSet2 button.
+func (ui *UI) showNodeSetMenu() {
+	compare := fyne.NewMenuItem(ui.t("nodeset_compare"), ui.showNodeSetDiffDialog)
+	compare.Disabled = !ui.isConnected || ui.controller.IsOffline()
+	browse := fyne.NewMenuItem(ui.t("nodeset_browse_offline"), ui.loadNodeSetOffline)
+	browse.Disabled = ui.isConnected
+	menu := fyne.NewMenu("", compare, browse)
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(ui.nodesetBtn)
+	pos = pos.AddXY(0, ui.nodesetBtn.Size().Height)
+	widget.ShowPopUpMenuAtPosition(menu, ui.window.Canvas(), pos)
+}
+
+// pickNodeSetFile asks for a NodeSet2 XML file and passes its path to fn.
+func (ui *UI) pickNodeSetFile(fn func(path string)) {
+	dlg := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+		fn(path)
+	}, ui.window)
+	winSize := ui.window.Canvas().Size()
+	dlg.Resize(fyne.NewSize(winSize.Width*0.9, winSize.Height*0.9))
+	dlg.SetFilter(storage.NewExtensionFileFilter([]string{".xml"}))
+	dlg.Show()
+}
+
+// loadNodeSetOffline starts offline mode on a NodeSet2 model, so its address space can be browsed
+// and watch lists prepared before the server is available.
+func (ui *UI) loadNodeSetOffline() {
+	ui.pickNodeSetFile(func(path string) {
+		ui.offlineBtn.Disable()
+		go func() {
+			err := ui.controller.StartOffline(path, "")
+			fyne.Do(func() {
+				if err != nil {
+					ui.offlineBtn.Enable()
+					dialog.ShowError(err, ui.window)
+				}
+			})
+		}()
+	})
+}
+
+// showNodeSetDiffDialog compares the live server against a NodeSet2 baseline and shows the result.
+func (ui *UI) showNodeSetDiffDialog() {
+	ui.pickNodeSetFile(func(path string) {
+		bar := widget.NewProgressBar()
+		progress := dialog.NewCustomWithoutButtons(ui.t("nodeset_compare"), bar, ui.window)
+		progress.Show()
+		go func() {
+			diff, err := ui.controller.DiffNodeSet(path, func(done, total int) {
+				fyne.Do(func() { bar.SetValue(float64(done) / float64(total)) })
+			})
+			fyne.Do(func() {
+				progress.Hide()
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				ui.showNodeSetDiff(diff)
+			})
+		}()
+	})
+}
+
+func (ui *UI) showNodeSetDiff(diff *controller.NodeSetDiff) {
+	headers := []string{"Kind", "NodeID", "BrowseName", "NodeClass", "Expected", "Actual"}
+	entries := diff.Entries
+	table := widget.NewTable(
+		func() (int, int) { return len(entries) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.Importance = widget.MediumImportance
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			e := entries[id.Row-1]
+			lbl.TextStyle = fyne.TextStyle{}
+			lbl.Importance = widget.MediumImportance
+			switch id.Col {
+			case 0:
+				switch e.Kind {
+				case "missing":
+					lbl.Importance = widget.DangerImportance
+				case "extra":
+					lbl.Importance = widget.LowImportance
+				default:
+					lbl.Importance = widget.WarningImportance
+				}
+				lbl.SetText(e.Kind)
+			case 1:
+				lbl.SetText(e.NodeID)
+			case 2:
+				lbl.SetText(e.BrowseName)
+			case 3:
+				lbl.SetText(e.NodeClass)
+			case 4:
+				lbl.SetText(e.Expected)
+			case 5:
+				lbl.SetText(e.Actual)
+			}
+		},
+	)
+	for i, w := range []float32{90, 240, 200, 110, 160, 220} {
+		table.SetColumnWidth(i, w)
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf(ui.t("nodeset_diff_summary"), diff.Checked, diff.Missing, diff.Extra, diff.Mismatched))
+	exportBtn := widget.NewButtonWithIcon(ui.t("export_btn"), theme.DocumentSaveIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			filePath := writer.URI().Path()
+			writer.Close()
+			if err := controller.WriteNodeSetDiff(filePath, diff); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.controller.Log(fmt.Sprintf("[green]NodeSet diff written to %s[-]", filePath))
+		}, ui.window)
+		saveDialog.SetFileName("nodeset_diff_" + strings.TrimSuffix(diff.Baseline, ".xml") + ".csv")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+		saveDialog.Show()
+	})
+
+	content := container.NewBorder(container.NewHBox(summary), container.NewHBox(exportBtn), nil, nil, table)
+	dlg := dialog.NewCustom(ui.t("nodeset_compare")+": "+diff.Baseline, ui.t("close_btn"), content, ui.window)
+	winSize := ui.window.Canvas().Size()
+	dlg.Resize(fyne.NewSize(winSize.Width*0.9, winSize.Height*0.8))
+	dlg.Show()
+}
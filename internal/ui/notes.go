@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showNodeNoteDialog edits the note and the alias of the selected node. Empty values remove
+// them. Both are saved in the config by namespace URI, like the watch list.
+func (ui *UI) showNodeNoteDialog() {
+	nodeID := string(ui.selectedNodeID)
+	if nodeID == "" || nodeID == ui.virtualRoot {
+		return
+	}
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.Wrapping = fyne.TextWrapWord
+	noteEntry.SetMinRowsVisible(5)
+	noteEntry.SetPlaceHolder(ui.t("node_note_hint"))
+	noteEntry.SetText(ui.controller.NodeNote(nodeID))
+	aliasEntry := widget.NewEntry()
+	aliasEntry.SetPlaceHolder(ui.t("node_alias_hint"))
+	aliasEntry.SetText(ui.controller.NodeAlias(nodeID))
+
+	dlg := dialog.NewForm(ui.t("node_note")+": "+nodeID, ui.t("save_btn"), ui.t("cancel_btn"),
+		[]*widget.FormItem{widget.NewFormItem("", noteEntry), widget.NewFormItem(ui.t("node_alias"), aliasEntry)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			note := strings.TrimSpace(noteEntry.Text)
+			ref := ui.controller.NodeRef(nodeID)
+			delete(ui.config.NodeNotes, ref)
+			delete(ui.config.NodeNotes, nodeID)
+			if note != "" {
+				if ui.config.NodeNotes == nil {
+					ui.config.NodeNotes = make(map[string]string)
+				}
+				ui.config.NodeNotes[ref] = note
+			}
+			alias := strings.TrimSpace(aliasEntry.Text)
+			delete(ui.config.NodeAliases, ref)
+			delete(ui.config.NodeAliases, nodeID)
+			if alias != "" {
+				if ui.config.NodeAliases == nil {
+					ui.config.NodeAliases = make(map[string]string)
+				}
+				ui.config.NodeAliases[ref] = alias
+			}
+			ui.saveConfig()
+			ui.controller.Log(fmt.Sprintf("[green]Note of %s saved[-]", nodeID))
+			if string(ui.selectedNodeID) == nodeID && ui.nodeInfoData["NodeID"] == nodeID {
+				ui.nodeInfoData["Note"] = note
+				ui.nodeInfoTable.Refresh()
+				ui.updateDetailsColumnWidths()
+			}
+		}, ui.window)
+	dlg.Resize(fyne.NewSize(520, 350))
+	dlg.Show()
+}
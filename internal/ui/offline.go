@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showOfflineDialog asks for an exported address space (JSON or NodeSet2 XML) and an optional recorded value
+// stream (JSON Lines from /ws/subscribe) and starts offline mode.
+func (ui *UI) showOfflineDialog() {
+	spaceEntry := widget.NewEntry()
+	spaceEntry.SetPlaceHolder("address-space.json")
+	recordingEntry := widget.NewEntry()
+	recordingEntry.SetPlaceHolder("recording.jsonl")
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("offline_address_space"), container.NewBorder(nil, nil, nil, ui.filePickButton(spaceEntry, []string{".json", ".xml"}), spaceEntry)),
+		widget.NewFormItem(ui.t("offline_recording"), container.NewBorder(nil, nil, nil, ui.filePickButton(recordingEntry, []string{".jsonl", ".json", ".txt"}), recordingEntry)),
+	)
+	dlg := dialog.NewCustomConfirm(ui.t("offline_mode"), ui.t("start_btn"), ui.t("cancel_btn"), form, func(ok bool) {
+		if !ok {
+			return
+		}
+		space := strings.TrimSpace(spaceEntry.Text)
+		recording := strings.TrimSpace(recordingEntry.Text)
+		if space == "" {
+			return
+		}
+		ui.offlineBtn.Disable()
+		go func() {
+			err := ui.controller.StartOffline(space, recording)
+			fyne.Do(func() {
+				if err != nil {
+					ui.offlineBtn.Enable()
+					dialog.ShowError(err, ui.window)
+				}
+			})
+		}()
+	}, ui.window)
+	dlg.Resize(fyne.NewSize(ui.window.Canvas().Size().Width*0.6, 0))
+	dlg.Show()
+}
+
+// filePickButton returns a button that opens a file dialog filtered to exts and puts the chosen
+// path into entry.
+func (ui *UI) filePickButton(entry *widget.Entry, exts []string) *widget.Button {
+	return widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dlg := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			entry.SetText(reader.URI().Path())
+			reader.Close()
+		}, ui.window)
+		winSize := ui.window.Canvas().Size()
+		dlg.Resize(fyne.NewSize(winSize.Width*0.9, winSize.Height*0.9))
+		dlg.SetFilter(storage.NewExtensionFileFilter(exts))
+		dlg.Show()
+	})
+}
+
+// setOfflineIndicator switches the connection status area to the OFFLINE look.
+func (ui *UI) setOfflineIndicator() {
+	ui.statusIcon.SetResource(theme.WarningIcon())
+	ui.serverBanner.SetText(ui.t("offline_banner"))
+	ui.serverBanner.Importance = widget.WarningImportance
+	ui.serverBanner.Show()
+	ui.serverBanner.Refresh()
+	if ui.bulkWriteBtn != nil {
+		ui.bulkWriteBtn.Disable()
+	}
+}
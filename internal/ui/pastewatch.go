@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showPasteNodeIDsDialog adds a pasted list of NodeIDs to the watch list. The entry starts with
+// the clipboard when it holds NodeIDs; invalid entries are listed and skipped.
+func (ui *UI) showPasteNodeIDsDialog() {
+	if !ui.controller.ConnectionStatus().Connected && !ui.controller.IsOffline() {
+		dialog.ShowInformation(ui.t("paste_node_ids"), ui.t("connect_first"), ui.window)
+		return
+	}
+	entry := widget.NewMultiLineEntry()
+	entry.SetPlaceHolder(ui.t("paste_node_ids_hint"))
+	entry.Wrapping = fyne.TextWrapOff
+	clip := ui.app.Clipboard().Content()
+	for _, id := range opc.SplitNodeIDList(clip) {
+		if opc.LooksLikeNodeID(id) {
+			entry.SetText(clip)
+			break
+		}
+	}
+
+	summary := widget.NewLabel("")
+	problems := widget.NewLabel("")
+	problems.Importance = widget.DangerImportance
+	problems.Wrapping = fyne.TextWrapWord
+
+	var ids []string
+	var dlg dialog.Dialog
+	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		dlg.Hide()
+		toAdd := ids
+		go func() {
+			for _, id := range toAdd {
+				ui.controller.AddWatch(context.Background(), id)
+			}
+			ui.controller.Log(fmt.Sprintf("[green]Added %d pasted NodeIDs to the watch list[-]", len(toAdd)))
+		}()
+	})
+	addBtn.Importance = widget.HighImportance
+
+	validate := func(text string) {
+		watched := make(map[string]bool)
+		for _, it := range ui.controller.WatchSnapshot() {
+			watched[it.NodeID] = true
+		}
+		parsed, invalid := ui.controller.ParseNodeIDList(text)
+		ids = ids[:0]
+		already := 0
+		for _, id := range parsed {
+			if watched[id] {
+				already++
+				continue
+			}
+			ids = append(ids, id)
+		}
+		summary.SetText(fmt.Sprintf(ui.t("paste_node_ids_summary"), len(ids), len(invalid), already))
+		lines := make([]string, len(invalid))
+		for i, err := range invalid {
+			lines[i] = err.Error()
+		}
+		problems.SetText(strings.Join(lines, "\n"))
+		addBtn.SetText(fmt.Sprintf(ui.t("add_to_watch_n"), len(ids)))
+		if len(ids) == 0 {
+			addBtn.Disable()
+		} else {
+			addBtn.Enable()
+		}
+	}
+	entry.OnChanged = validate
+	validate(entry.Text)
+
+	cancelBtn := widget.NewButton(ui.t("cancel_btn"), func() { dlg.Hide() })
+	split := container.NewVSplit(entry, container.NewVScroll(problems))
+	split.Offset = 0.7
+	content := container.NewBorder(nil,
+		container.NewVBox(summary, container.NewHBox(layout.NewSpacer(), cancelBtn, addBtn)),
+		nil, nil, split)
+	dlg = dialog.NewCustomWithoutButtons(ui.t("paste_node_ids"), content, ui.window)
+	dlg.Resize(fyne.NewSize(560, 440))
+	dlg.Show()
+}
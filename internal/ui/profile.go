@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"strings"
+
+	"opcuababy/internal/cert"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// showProfileExportDialog exports the saved connection settings as a profile file, or as a QR
+// code and text for devices without a file share. Passwords are never exported.
+func (ui *UI) showProfileExportDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(ui.t("profile_name"))
+	if u, err := url.Parse(ui.config.EndpointURL); err == nil && u.Hostname() != "" {
+		nameEntry.SetText(u.Hostname())
+	}
+	includeCerts := widget.NewCheck(ui.t("profile_include_certs"), nil)
+	if ui.config.CertFile == "" || ui.config.KeyFile == "" {
+		includeCerts.Disable()
+	}
+	includeNotes := widget.NewCheck(fmt.Sprintf(ui.t("profile_include_notes"), len(ui.config.NodeNotes)), nil)
+	if len(ui.config.NodeNotes) == 0 {
+		includeNotes.Disable()
+	}
+	hint := widget.NewLabel(ui.t("profile_export_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	export := func(withCerts bool) (*opc.ConnectionProfile, bool) {
+		p, err := ui.config.ExportProfile(nameEntry.Text, withCerts)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return nil, false
+		}
+		if includeNotes.Checked {
+			p.Notes = maps.Clone(ui.config.NodeNotes)
+		}
+		return p, true
+	}
+
+	saveBtn := widget.NewButtonWithIcon(ui.t("profile_save_file"), theme.DocumentSaveIcon(), func() {
+		p, ok := export(includeCerts.Checked)
+		if !ok {
+			return
+		}
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write(data); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.controller.Log(fmt.Sprintf("[green]Connection profile exported to %s[-]", writer.URI().Path()))
+		}, ui.window)
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			name = "connection"
+		}
+		saveDialog.SetFileName(name + ".opcuababy.json")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		saveDialog.Show()
+	})
+	saveBtn.Importance = widget.HighImportance
+	// A private key does not fit a QR code, and should not be shown on screen anyway
+	qrBtn := widget.NewButtonWithIcon(ui.t("profile_show_qr"), theme.VisibilityIcon(), func() {
+		if p, ok := export(false); ok {
+			ui.showProfileQR(p)
+		}
+	})
+
+	content := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(ui.t("profile_name")), nil, nameEntry),
+		includeCerts,
+		includeNotes,
+		hint,
+		container.NewHBox(qrBtn, saveBtn),
+	)
+	dlg := dialog.NewCustom(ui.t("profile_export"), ui.t("close_btn"), content, ui.window)
+	dlg.Resize(fyne.NewSize(520, 300))
+	dlg.Show()
+}
+
+// showProfileQR shows p as a QR code. Phone camera apps decode it to text, which is pasted into
+// the import dialog of the mobile build.
+func (ui *UI) showProfileQR(p *opc.ConnectionProfile) {
+	text, err := p.MarshalText()
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	png, err := qrcode.Encode(text, qrcode.Medium, 512)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", ui.t("profile_show_qr"), err), ui.window)
+		return
+	}
+	img := canvas.NewImageFromResource(fyne.NewStaticResource("profile.png", png))
+	img.FillMode = canvas.ImageFillContain
+	img.ScaleMode = canvas.ImageScalePixels
+	img.SetMinSize(fyne.NewSize(320, 320))
+	hint := widget.NewLabel(ui.t("profile_qr_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+		ui.app.Clipboard().SetContent(text)
+	})
+	content := container.NewBorder(nil, container.NewVBox(hint, container.NewHBox(copyBtn)), nil, nil, img)
+	dlg := dialog.NewCustom(p.Name, ui.t("close_btn"), content, ui.window)
+	dlg.Resize(fyne.NewSize(420, 520))
+	dlg.Show()
+}
+
+// showProfileImportDialog imports a profile from a file or from pasted text (a scanned QR code)
+// into the config. onImported runs after the config has been saved.
+func (ui *UI) showProfileImportDialog(onImported func()) {
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetPlaceHolder(ui.t("profile_import_placeholder"))
+	textEntry.Wrapping = fyne.TextWrapBreak
+	textEntry.SetMinRowsVisible(5)
+	if clip := strings.TrimSpace(ui.app.Clipboard().Content()); strings.HasPrefix(clip, opc.ProfileTextPrefix) {
+		textEntry.SetText(clip)
+	}
+	hint := widget.NewLabel(ui.t("profile_import_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	var dlg *dialog.CustomDialog
+	apply := func(data []byte) {
+		p, err := opc.ParseProfile(data)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		certDir := ""
+		if p.HasCertificate() {
+			if certDir, err = cert.GetMobileStoragePath(); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+		}
+		if err := ui.config.ApplyProfile(p, certDir); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		ui.saveConfig()
+		ui.endpointEntry.SetText(ui.config.EndpointURL)
+		ui.controller.Log(fmt.Sprintf("[green]Connection profile '%s' imported: %s[-]", p.Name, p.EndpointURL))
+		if len(p.Notes) > 0 {
+			ui.controller.Log(fmt.Sprintf("[green]%d node notes imported[-]", len(p.Notes)))
+		}
+		dlg.Hide()
+		if onImported != nil {
+			onImported()
+		}
+	}
+
+	pasteBtn := widget.NewButtonWithIcon(ui.t("paste"), theme.ContentPasteIcon(), func() {
+		textEntry.SetText(strings.TrimSpace(ui.app.Clipboard().Content()))
+	})
+	fileBtn := widget.NewButtonWithIcon(ui.t("profile_open_file"), theme.FolderOpenIcon(), func() {
+		fileDlg := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			data, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			apply(data)
+		}, ui.window)
+		winSize := ui.window.Canvas().Size()
+		fileDlg.Resize(fyne.NewSize(winSize.Width*0.9, winSize.Height*0.9))
+		fileDlg.SetFilter(storage.NewExtensionFileFilter([]string{".json", ".txt"}))
+		fileDlg.Show()
+	})
+	importBtn := widget.NewButtonWithIcon(ui.t("profile_import"), theme.ConfirmIcon(), func() {
+		if strings.TrimSpace(textEntry.Text) == "" {
+			return
+		}
+		apply([]byte(textEntry.Text))
+	})
+	importBtn.Importance = widget.HighImportance
+
+	content := container.NewVBox(
+		hint,
+		textEntry,
+		container.NewHBox(pasteBtn, fileBtn, importBtn),
+	)
+	dlg = dialog.NewCustom(ui.t("profile_import"), ui.t("cancel_btn"), content, ui.window)
+	dlg.Resize(fyne.NewSize(520, 340))
+	dlg.Show()
+}
+
+// makeSavedProfilesRow offers the saved connection profiles: choosing one loads it into the
+// config, the buttons save the current settings as a profile or remove the chosen one.
+// onLoaded runs after a profile has been loaded.
+func (ui *UI) makeSavedProfilesRow(onLoaded func()) fyne.CanvasObject {
+	names := func() []string {
+		out := make([]string, 0, len(ui.config.Profiles))
+		for _, p := range ui.config.Profiles {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	find := func(name string) int {
+		for i, p := range ui.config.Profiles {
+			if strings.EqualFold(p.Name, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var removeBtn *widget.Button
+	sel := widget.NewSelect(names(), nil)
+	sel.PlaceHolder = ui.t("profile_saved")
+	sel.OnChanged = func(name string) {
+		i := find(name)
+		if i < 0 {
+			return
+		}
+		removeBtn.Enable()
+		p := ui.config.Profiles[i]
+		dialog.ShowConfirm(ui.t("profile_load"), fmt.Sprintf(ui.t("profile_load_confirm"), p.Name, p.EndpointURL), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := ui.config.ApplyProfile(&p, ""); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			ui.saveConfig()
+			ui.endpointEntry.SetText(ui.config.EndpointURL)
+			ui.controller.Log(fmt.Sprintf("[green]Connection profile '%s' loaded: %s[-]", p.Name, p.EndpointURL))
+			if onLoaded != nil {
+				onLoaded()
+			}
+		}, ui.window)
+	}
+
+	saveBtn := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		nameEntry := widget.NewEntry()
+		if u, err := url.Parse(ui.config.EndpointURL); err == nil && u.Hostname() != "" {
+			nameEntry.SetText(u.Hostname())
+		}
+		items := []*widget.FormItem{widget.NewFormItem(ui.t("profile_name"), nameEntry)}
+		dialog.ShowForm(ui.t("profile_save_as"), ui.t("save_btn"), ui.t("cancel_btn"), items, func(ok bool) {
+			name := strings.TrimSpace(nameEntry.Text)
+			if !ok || name == "" {
+				return
+			}
+			// Saved profiles are synced between devices; certificates stay on this one
+			p, err := ui.config.ExportProfile(name, false)
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if i := find(name); i >= 0 {
+				ui.config.Profiles[i] = *p
+			} else {
+				ui.config.Profiles = append(ui.config.Profiles, *p)
+			}
+			ui.saveConfig()
+			sel.SetOptions(names())
+		}, ui.window)
+	})
+	removeBtn = widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		if i := find(sel.Selected); i >= 0 {
+			ui.config.Profiles = append(ui.config.Profiles[:i], ui.config.Profiles[i+1:]...)
+			ui.saveConfig()
+			sel.ClearSelected()
+			sel.SetOptions(names())
+			removeBtn.Disable()
+		}
+	})
+	removeBtn.Disable()
+	return container.NewHBox(sel, saveBtn, removeBtn)
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// booleanWriteItems are the Toggle and Pulse entries of the context menu of a Boolean watch row,
+// nil for other rows. They are disabled when writes to the node are refused.
+func (ui *UI) booleanWriteItems(item *controller.WatchItem) []*fyne.MenuItem {
+	if item.UAType != "Boolean" {
+		return nil
+	}
+	nodeID := item.NodeID
+	width := ui.config.PulseDuration()
+	toggle := fyne.NewMenuItem(ui.t("toggle_value"), func() {
+		go ui.reportWriteError(func(ctx context.Context) error {
+			_, err := ui.controller.ToggleValue(ctx, nodeID)
+			return err
+		})
+	})
+	pulse := fyne.NewMenuItem(fmt.Sprintf(ui.t("pulse_value"), width), func() {
+		go ui.reportWriteError(func(ctx context.Context) error { return ui.controller.PulseValue(ctx, nodeID, width) })
+	})
+	if ui.controller.IsOffline() || ui.controller.CheckWriteAllowed(nodeID) != nil {
+		toggle.Disabled, pulse.Disabled = true, true
+	}
+	return []*fyne.MenuItem{fyne.NewMenuItemSeparator(), toggle, pulse}
+}
+
+// reportWriteError runs write bound to the session, so a disconnect cuts a pulse short, and
+// shows its error, if any.
+func (ui *UI) reportWriteError(write func(ctx context.Context) error) {
+	ctx := ui.controller.GetClientContext()
+	if ctx == nil {
+		err := errors.New("not connected")
+		fyne.Do(func() { dialog.ShowError(err, ui.window) })
+		return
+	}
+	if err := write(ctx); err != nil {
+		fyne.Do(func() { dialog.ShowError(err, ui.window) })
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showRawDataValueDialog shows the DataValues last received for the watch item nodeID: variant
+// type and encoding masks, timestamps with picoseconds and the status code broken into its
+// bits. Selecting one of the recent values shows its breakdown.
+func (ui *UI) showRawDataValueDialog(nodeID string) {
+	var values []controller.RawDataValue
+	form := widget.NewForm()
+	list := widget.NewList(
+		func() int { return len(values) },
+		func() fyne.CanvasObject {
+			lbl := widget.NewLabel("")
+			lbl.TextStyle = fyne.TextStyle{Monospace: true}
+			lbl.Truncation = fyne.TextTruncateEllipsis
+			return lbl
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(values) {
+				return
+			}
+			v := values[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s  %-10s %-14s %s", v.Received.Format("15:04:05.000"), v.Severity, v.VariantType, v.Value))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id < len(values) {
+			ui.fillRawDataValue(form, values[id])
+		}
+	}
+	heading := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	load := func() {
+		values = ui.controller.WatchDataValues(nodeID)
+		heading.SetText(fmt.Sprintf(ui.t("raw_value_recent"), len(values)))
+		list.UnselectAll()
+		list.Refresh()
+		if len(values) == 0 {
+			form.Items = nil
+			form.Append("", widget.NewLabel(ui.t("raw_value_none")))
+			form.Refresh()
+			return
+		}
+		list.Select(0)
+	}
+	refreshBtn := widget.NewButtonWithIcon(ui.t("refresh"), theme.ViewRefreshIcon(), load)
+	load()
+
+	recent := container.NewBorder(
+		container.NewBorder(nil, nil, heading, refreshBtn),
+		nil, nil, nil, list)
+	split := container.NewVSplit(container.NewVScroll(form), recent)
+	split.Offset = 0.6
+	dlg := dialog.NewCustom(fmt.Sprintf(ui.t("raw_value_title"), nodeID), ui.t("close_btn"), split, ui.window)
+	dlg.Resize(fyne.NewSize(640, 640))
+	dlg.Show()
+}
+
+// fillRawDataValue shows the breakdown of one received DataValue.
+func (ui *UI) fillRawDataValue(form *widget.Form, v controller.RawDataValue) {
+	form.Items = nil
+	add := func(label, text string) {
+		l := widget.NewLabel(text)
+		l.Wrapping = fyne.TextWrapWord
+		form.Append(label, l)
+	}
+	add(ui.t("raw_value_received"), v.Received.Format("2006-01-02 15:04:05.000"))
+	add("EncodingMask", fmt.Sprintf("0x%02X  (%s)", v.EncodingMask, strings.Join(v.Fields, ", ")))
+	variant := v.VariantType
+	if variant == "" {
+		variant = "-"
+	} else {
+		variant += fmt.Sprintf("  (mask 0x%02X)", v.VariantMask)
+	}
+	add(ui.t("raw_value_variant"), variant)
+	if len(v.ArrayDimensions) > 0 {
+		add("ArrayDimensions", fmt.Sprint(v.ArrayDimensions))
+	}
+	add("Value", v.Value)
+	add("SourceTimestamp", controller.FormatPicoTime(v.SourceTimestamp, v.SourcePicoseconds))
+	add("ServerTimestamp", controller.FormatPicoTime(v.ServerTimestamp, v.ServerPicoseconds))
+	add("StatusCode", fmt.Sprintf("%s  %s", v.RawCode, v.SymbolicName))
+	add("Severity", v.Severity)
+	add("SubCode", fmt.Sprintf("%d (0x%04X)", v.SubCode, v.SubCode))
+	add("InfoBits", fmt.Sprintf("0x%04X  InfoType=%s", v.InfoBits, v.InfoType))
+	bits := v.StatusBits()
+	if bits == "" {
+		bits = "-"
+	}
+	add(ui.t("raw_value_flags"), bits)
+	form.Refresh()
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// refreshBranch browses nodeID again, discarding the cached children below it, or the whole
+// address space if nodeID is empty. Open branches below it are browsed again as the tree redraws.
+func (ui *UI) refreshBranch(nodeID string) {
+	go func() {
+		if err := ui.controller.RefreshBranch(context.Background(), nodeID); err != nil {
+			fyne.Do(func() { dialog.ShowError(err, ui.window) })
+			return
+		}
+		fyne.Do(func() { ui.nodeTree.Refresh() })
+	}()
+}
+
+// toggleModelWatch marks nodeID to be browsed again periodically, so nodes added or removed on
+// servers without model change events show up, or removes the mark.
+func (ui *UI) toggleModelWatch(nodeID string) {
+	ref := ui.controller.NodeRef(nodeID)
+	if ui.controller.IsModelWatched(nodeID) {
+		ui.config.ModelWatchBranches = slices.DeleteFunc(ui.config.ModelWatchBranches, func(s string) bool {
+			return s == ref || s == nodeID
+		})
+		ui.controller.Log(fmt.Sprintf("[green]%s is no longer watched for changes[-]", nodeID))
+	} else {
+		ui.config.ModelWatchBranches = append(ui.config.ModelWatchBranches, ref)
+		ui.controller.Log(fmt.Sprintf("[green]%s is browsed again every %v to detect added and removed nodes[-]", nodeID, controller.ModelPollInterval))
+	}
+	ui.saveConfig()
+}
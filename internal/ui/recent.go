@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	maxRecentNodes  = 20
+	maxWriteHistory = 100
+)
+
+// showHistoryMenu offers the recently viewed nodes and the write history below the History button.
+func (ui *UI) showHistoryMenu() {
+	recent := fyne.NewMenuItem(ui.t("recent_nodes"), ui.showRecentNodes)
+	writes := fyne.NewMenuItem(ui.t("write_history"), ui.showWriteHistory)
+	clearItem := fyne.NewMenuItem(ui.t("clear_history"), func() {
+		dialog.ShowConfirm(ui.t("clear_history"), ui.t("clear_history_confirm"), func(ok bool) {
+			if !ok {
+				return
+			}
+			ui.config.RecentNodes = nil
+			ui.config.WriteHistory = nil
+			ui.saveConfig()
+		}, ui.window)
+	})
+	clearItem.Disabled = len(ui.config.RecentNodes) == 0 && len(ui.config.WriteHistory) == 0
+	menu := fyne.NewMenu("", recent, writes, fyne.NewMenuItemSeparator(), clearItem)
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(ui.historyBtn)
+	pos = pos.AddXY(0, ui.historyBtn.Size().Height)
+	widget.ShowPopUpMenuAtPosition(menu, ui.window.Canvas(), pos)
+}
+
+// recordRecentNode moves nodeID to the front of the recently viewed nodes.
+func (ui *UI) recordRecentNode(nodeID string) {
+	ref := ui.controller.NodeRef(nodeID)
+	name := nodeID
+	if node := ui.controller.GetNode(nodeID); node != nil && node.Name != "" {
+		name = node.Name
+	}
+	recent := []opc.Favorite{{Ref: ref, Name: name}}
+	for _, f := range ui.config.RecentNodes {
+		if f.Ref != ref && len(recent) < maxRecentNodes {
+			recent = append(recent, f)
+		}
+	}
+	ui.config.RecentNodes = recent
+	ui.saveConfig()
+}
+
+// recordWriteResult adds a finished write to the front of the write history.
+func (ui *UI) recordWriteResult(rec opc.WriteRecord) {
+	history := append([]opc.WriteRecord{rec}, ui.config.WriteHistory...)
+	if len(history) > maxWriteHistory {
+		history = history[:maxWriteHistory]
+	}
+	ui.config.WriteHistory = history
+	ui.saveConfig()
+	if ui.writeHistoryTable != nil {
+		ui.writeHistoryTable.Refresh()
+	}
+}
+
+func (ui *UI) showRecentNodes() {
+	var list *widget.List
+	list = ui.newNodeList(
+		func() []opc.Favorite { return ui.config.RecentNodes },
+		func(fav opc.Favorite) {
+			for i, f := range ui.config.RecentNodes {
+				if f.Ref == fav.Ref {
+					ui.config.RecentNodes = append(ui.config.RecentNodes[:i], ui.config.RecentNodes[i+1:]...)
+					break
+				}
+			}
+			ui.saveConfig()
+			list.Refresh()
+		},
+	)
+	dlg := dialog.NewCustom(ui.t("recent_nodes"), ui.t("close_btn"), list, ui.window)
+	dlg.Resize(fyne.NewSize(560, 420))
+	dlg.Show()
+}
+
+// showWriteHistory lists the recent writes (newest first) with a button to repeat one.
+func (ui *UI) showWriteHistory() {
+	headers := []string{ui.t("timestamp"), "NodeID", "DataType", ui.t("value"), ui.t("result"), ""}
+	table := widget.NewTable(
+		func() (int, int) { return len(ui.config.WriteHistory) + 1, len(headers) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewStack(label, widget.NewButton(ui.t("repeat_write"), nil))
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			cell := obj.(*fyne.Container)
+			label := cell.Objects[0].(*widget.Label)
+			btn := cell.Objects[1].(*widget.Button)
+			label.TextStyle.Bold = id.Row == 0
+			if id.Row == 0 || id.Col < len(headers)-1 {
+				btn.Hide()
+				label.Show()
+				if id.Row == 0 {
+					label.SetText(headers[id.Col])
+					return
+				}
+			} else {
+				label.Hide()
+				btn.Show()
+			}
+			if id.Row-1 >= len(ui.config.WriteHistory) {
+				return
+			}
+			rec := ui.config.WriteHistory[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(rec.Time.Format("2006-01-02 15:04:05"))
+			case 1:
+				label.SetText(rec.Ref)
+			case 2:
+				label.SetText(rec.DataType)
+			case 3:
+				label.SetText(rec.Value)
+			case 4:
+				label.SetText(rec.Result)
+			default:
+				btn.SetText(ui.t("repeat_write"))
+				btn.OnTapped = func() { ui.repeatWrite(rec) }
+			}
+		},
+	)
+	for col, w := range []float32{150, 220, 80, 120, 160, 110} {
+		table.SetColumnWidth(col, w)
+	}
+	ui.writeHistoryTable = table
+	dlg := dialog.NewCustom(ui.t("write_history"), ui.t("close_btn"), table, ui.window)
+	dlg.SetOnClosed(func() { ui.writeHistoryTable = nil })
+	dlg.Resize(fyne.NewSize(900, 480))
+	dlg.Show()
+}
+
+// repeatWrite writes a value from the history again after confirmation.
+func (ui *UI) repeatWrite(rec opc.WriteRecord) {
+	nodeID, ok := ui.resolveFavorite(opc.Favorite{Ref: rec.Ref, Name: rec.Ref})
+	if !ok {
+		return
+	}
+	dialog.ShowConfirm(ui.t("repeat_write"), fmt.Sprintf(ui.t("repeat_write_confirm"), rec.Value, rec.Ref), func(ok bool) {
+		if ok {
+			go ui.controller.WriteValue(context.Background(), nodeID, rec.DataType, rec.Value)
+		}
+	}, ui.window)
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// makeReconnectBanner builds the banner shown above the panels while the connection is down
+// after an unexpected loss. It stays hidden otherwise.
+func (ui *UI) makeReconnectBanner() fyne.CanvasObject {
+	ui.reconnectMsg = widget.NewLabel("")
+	ui.reconnectMsg.Wrapping = fyne.TextWrapWord
+	ui.reconnectMsg.Importance = widget.WarningImportance
+	ui.reconnectNowBtn = widget.NewButtonWithIcon(ui.t("reconnect_now"), theme.ViewRefreshIcon(), ui.reconnectNow)
+	ui.reconnectStopBtn = widget.NewButtonWithIcon(ui.t("reconnect_stop"), theme.CancelIcon(), ui.stopReconnecting)
+	ui.reconnectBanner = container.NewBorder(nil, widget.NewSeparator(),
+		widget.NewIcon(theme.WarningIcon()),
+		container.NewHBox(ui.reconnectNowBtn, ui.reconnectStopBtn),
+		ui.reconnectMsg)
+	ui.reconnectBanner.Hide()
+	return ui.reconnectBanner
+}
+
+// onLinkStateChange shows the banner when the connection drops and hides it once the stack has
+// restored the connection. Runs on the UI thread.
+func (ui *UI) onLinkStateChange(st controller.LinkState) {
+	if ui.reconnectBanner == nil {
+		return
+	}
+	if !st.Down {
+		if !ui.reconnectBusy {
+			ui.hideReconnectBanner()
+			ui.statusIcon.SetResource(theme.ConfirmIcon())
+		}
+		return
+	}
+	if ui.reconnectBusy {
+		return
+	}
+	if st.Reason != "" || ui.reconnectReason == "" {
+		ui.reconnectReason = st.Reason
+	}
+	ui.statusIcon.SetResource(theme.WarningIcon())
+	ui.reconnectBanner.Show()
+	// The stack keeps trying on its own; the countdown is to opening a new session
+	if ui.reconnectCancel == nil {
+		ui.startReconnectCountdown()
+	}
+}
+
+// startReconnectCountdown counts down the configured delay in the banner and reconnects when it
+// runs out.
+func (ui *UI) startReconnectCountdown() {
+	ui.stopReconnectCountdown()
+	stop := make(chan struct{})
+	ui.reconnectCancel = func() { close(stop) }
+	deadline := time.Now().Add(ui.config.ReconnectDelay())
+	ui.reconnectNowBtn.Enable()
+	ui.reconnectStopBtn.Enable()
+	ui.setReconnectMessage(time.Until(deadline))
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			left := time.Until(deadline)
+			fyne.Do(func() {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if left > 0 {
+					ui.setReconnectMessage(left)
+					return
+				}
+				ui.reconnectNow()
+			})
+			if left <= 0 {
+				return
+			}
+		}
+	}()
+}
+
+func (ui *UI) stopReconnectCountdown() {
+	if ui.reconnectCancel != nil {
+		ui.reconnectCancel()
+		ui.reconnectCancel = nil
+	}
+}
+
+func (ui *UI) setReconnectMessage(left time.Duration) {
+	msg := ui.t("reconnect_lost")
+	if ui.reconnectReason != "" {
+		msg += ": " + ui.reconnectReason
+	}
+	secs := int(left.Round(time.Second) / time.Second)
+	ui.reconnectMsg.SetText(msg + " — " + fmt.Sprintf(ui.t("reconnect_countdown"), max(secs, 0)))
+}
+
+// reconnectNow opens a new session right away. A failure restarts the countdown with its error.
+func (ui *UI) reconnectNow() {
+	if ui.reconnectBusy {
+		return
+	}
+	ui.stopReconnectCountdown()
+	ui.reconnectBusy = true
+	ui.reconnectNowBtn.Disable()
+	ui.reconnectStopBtn.Disable()
+	ui.reconnectMsg.SetText(ui.t("reconnecting"))
+	go func() {
+		err := ui.controller.Reconnect(ui.config)
+		fyne.Do(func() {
+			ui.reconnectBusy = false
+			if err == nil {
+				ui.hideReconnectBanner()
+				return
+			}
+			ui.reconnectReason = err.Error()
+			ui.reconnectBanner.Show()
+			ui.startReconnectCountdown()
+		})
+	}()
+}
+
+// stopReconnecting gives up: the session is closed and the banner hidden.
+func (ui *UI) stopReconnecting() {
+	ui.hideReconnectBanner()
+	go ui.controller.Disconnect()
+}
+
+func (ui *UI) hideReconnectBanner() {
+	ui.stopReconnectCountdown()
+	ui.reconnectReason = ""
+	if ui.reconnectBanner != nil {
+		ui.reconnectBanner.Hide()
+	}
+}
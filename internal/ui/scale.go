@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// uiScales are the text size multipliers offered in Settings.
+var uiScales = []float64{0.8, 0.9, 1, 1.15, 1.25, 1.5, 1.75, 2}
+
+// scaledTheme applies the UIScale and UIDensity settings on top of the app theme: text and
+// inline icon sizes grow with the scale, and the compact density halves paddings and line
+// spacing so more rows fit on small screens.
+type scaledTheme struct {
+	fyne.Theme
+	textScale float32
+	compact   bool
+}
+
+func (t *scaledTheme) Size(n fyne.ThemeSizeName) float32 {
+	s := t.Theme.Size(n)
+	switch n {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText,
+		theme.SizeNameCaptionText, theme.SizeNameInlineIcon:
+		return s * t.textScale
+	case theme.SizeNamePadding, theme.SizeNameInnerPadding, theme.SizeNameLineSpacing:
+		if t.compact {
+			return s / 2
+		}
+	}
+	return s
+}
+
+// textScale returns the configured UIScale, limited to the range offered in Settings.
+func (ui *UI) textScale() float32 {
+	if ui.config == nil || ui.config.UIScale <= 0 {
+		return 1
+	}
+	return float32(math.Min(math.Max(ui.config.UIScale, uiScales[0]), uiScales[len(uiScales)-1]))
+}
+
+// applyAppearance installs the theme for the current UIScale and UIDensity. Fyne refreshes all
+// open windows when the theme changes.
+func (ui *UI) applyAppearance() {
+	ui.app.Settings().SetTheme(&scaledTheme{
+		Theme:     &fontOnlyTheme{base: theme.DefaultTheme()},
+		textScale: ui.textScale(),
+		compact:   ui.config != nil && ui.config.UIDensity == "compact",
+	})
+}
+
+// scaleLabel renders a UIScale multiplier for the Settings select, e.g. "125%".
+func scaleLabel(s float64) string {
+	return fmt.Sprintf("%d%%", int(math.Round(s*100)))
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showScheduleWriteDialog plans a delayed or ramped write of nodeID. The start is either a delay in
+// seconds or a clock time (HH:MM[:SS], the next occurrence).
+func (ui *UI) showScheduleWriteDialog(nodeID, dataType, value string) {
+	valueEntry := widget.NewEntry()
+	valueEntry.SetText(value)
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("0 / 15:30:00")
+	rampEntry := widget.NewEntry()
+	rampEntry.SetPlaceHolder("0")
+	stepsEntry := widget.NewEntry()
+	stepsEntry.SetText("10")
+
+	dialog.ShowForm(ui.t("schedule_write")+": "+nodeID, ui.t("schedule_btn"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem("Data Type", widget.NewLabel(dataType)),
+			widget.NewFormItem(ui.t("target_value"), valueEntry),
+			widget.NewFormItem(ui.t("start_at"), startEntry),
+			widget.NewFormItem(ui.t("ramp_duration_s"), rampEntry),
+			widget.NewFormItem(ui.t("ramp_steps"), stepsEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			at, err := parseStartTime(startEntry.Text, time.Now())
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			var ramp time.Duration
+			if s := strings.TrimSpace(rampEntry.Text); s != "" {
+				secs, err := strconv.ParseFloat(s, 64)
+				if err != nil || secs < 0 {
+					dialog.ShowError(fmt.Errorf("invalid ramp duration %q", s), ui.window)
+					return
+				}
+				ramp = time.Duration(secs * float64(time.Second))
+			}
+			steps, _ := strconv.Atoi(strings.TrimSpace(stepsEntry.Text))
+			target := valueEntry.Text
+			go func() {
+				_, err := ui.controller.ScheduleWrite(nodeID, target, at, ramp, steps)
+				fyne.Do(func() {
+					if err != nil {
+						dialog.ShowError(err, ui.window)
+						return
+					}
+					ui.showScheduledWrites()
+				})
+			}()
+		}, ui.window)
+}
+
+// parseStartTime reads a delay in seconds or a clock time; empty means now (zero time).
+func parseStartTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil && secs >= 0 {
+		return now.Add(time.Duration(secs * float64(time.Second))), nil
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+			if at.Before(now) {
+				at = at.AddDate(0, 0, 1)
+			}
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid start %q: use seconds or HH:MM[:SS]", s)
+}
+
+// showScheduledWrites lists scheduled writes with their progress; the selected one can be cancelled.
+func (ui *UI) showScheduledWrites() {
+	if ui.schedTable != nil {
+		ui.refreshScheduledWrites()
+		return
+	}
+	headers := []string{"#", "NodeID", ui.t("target_value"), ui.t("start_at"), ui.t("ramp_steps"), "Status"}
+	selected := -1
+	table := widget.NewTable(
+		func() (int, int) { return len(ui.schedRows) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.Importance = widget.MediumImportance
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			sw := ui.schedRows[id.Row-1]
+			lbl.TextStyle = fyne.TextStyle{}
+			lbl.Importance = widget.MediumImportance
+			switch id.Col {
+			case 0:
+				lbl.SetText(strconv.Itoa(sw.ID))
+			case 1:
+				lbl.SetText(sw.NodeID)
+			case 2:
+				lbl.SetText(sw.Target)
+			case 3:
+				lbl.SetText(sw.At.Format("15:04:05"))
+			case 4:
+				if sw.Ramp > 0 {
+					lbl.SetText(fmt.Sprintf("%d/%d (%s)", sw.Step, sw.Steps, sw.Ramp))
+				} else {
+					lbl.SetText(fmt.Sprintf("%d/%d", sw.Step, sw.Steps))
+				}
+			case 5:
+				switch sw.Status {
+				case controller.ScheduledFailed:
+					lbl.Importance = widget.DangerImportance
+				case controller.ScheduledCancelled:
+					lbl.Importance = widget.WarningImportance
+				case controller.ScheduledDone:
+					lbl.Importance = widget.SuccessImportance
+				}
+				if sw.Message != "" {
+					lbl.SetText(sw.Status + ": " + sw.Message)
+				} else {
+					lbl.SetText(sw.Status)
+				}
+			}
+		},
+	)
+	for i, w := range []float32{40, 240, 110, 90, 140, 220} {
+		table.SetColumnWidth(i, w)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		selected = id.Row - 1
+	}
+
+	cancelBtn := widget.NewButton(ui.t("cancel_write"), func() {
+		if selected < 0 || selected >= len(ui.schedRows) {
+			return
+		}
+		id := ui.schedRows[selected].ID
+		go ui.controller.CancelScheduledWrite(id)
+	})
+	clearBtn := widget.NewButton(ui.t("clear_finished"), func() {
+		go ui.controller.ClearFinishedScheduledWrites()
+	})
+
+	ui.schedTable = table
+	ui.refreshScheduledWrites()
+	content := container.NewBorder(nil, container.NewHBox(cancelBtn, clearBtn), nil, nil, table)
+	dlg := dialog.NewCustom(ui.t("scheduled_writes"), ui.t("close_btn"), content, ui.window)
+	dlg.SetOnClosed(func() {
+		ui.schedTable = nil
+	})
+	winSize := ui.window.Canvas().Size()
+	dlg.Resize(fyne.NewSize(winSize.Width*0.8, winSize.Height*0.6))
+	dlg.Show()
+}
+
+// refreshScheduledWrites reloads the open scheduled writes dialog. Must run on the UI thread.
+func (ui *UI) refreshScheduledWrites() {
+	if ui.schedTable == nil {
+		return
+	}
+	ui.schedRows = ui.controller.ScheduledWrites()
+	ui.schedTable.Refresh()
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSearchDialog searches the address space by name or browse path. Results can be read,
+// watched and written like favorites; a running search can be cancelled.
+func (ui *UI) showSearchDialog() {
+	if !ui.controller.ConnectionStatus().Connected && !ui.controller.IsOffline() {
+		dialog.ShowInformation(ui.t("search"), ui.t("connect_first"), ui.window)
+		return
+	}
+	textEntry := widget.NewEntry()
+	textEntry.SetPlaceHolder(ui.t("search_hint"))
+
+	modes := []string{controller.SearchAuto, controller.SearchPath, controller.SearchQuery, controller.SearchBrowse}
+	modeLabels := make([]string, len(modes))
+	for i, m := range modes {
+		modeLabels[i] = ui.t("search_mode_" + m)
+	}
+	modeSelect := widget.NewSelect(modeLabels, nil)
+	modeSelect.SetSelectedIndex(0)
+
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("i=85")
+	if id := string(ui.selectedNodeID); id != "" && id != ui.virtualRoot {
+		startEntry.SetText(id)
+	}
+
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	var results []opc.Favorite
+	var list *widget.List
+	list = ui.newNodeList(
+		func() []opc.Favorite { return results },
+		func(fav opc.Favorite) {
+			// Removing only drops the row from the results
+			results = slices.DeleteFunc(results, func(f opc.Favorite) bool { return f.Ref == fav.Ref })
+			list.Refresh()
+		},
+	)
+
+	var cancel context.CancelFunc
+	searchBtn := widget.NewButtonWithIcon(ui.t("search"), theme.SearchIcon(), nil)
+	cancelBtn := widget.NewButtonWithIcon(ui.t("cancel_btn"), theme.CancelIcon(), func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	cancelBtn.Disable()
+	run := func() {
+		if cancel != nil {
+			return
+		}
+		req := controller.SearchRequest{
+			Text:    textEntry.Text,
+			Mode:    modes[max(modeSelect.SelectedIndex(), 0)],
+			StartID: startEntry.Text,
+		}
+		ctx, cf := context.WithCancel(context.Background())
+		cancel = cf
+		searchBtn.Disable()
+		cancelBtn.Enable()
+		status.SetText(ui.t("searching"))
+		go func() {
+			res, err := ui.controller.SearchNodes(ctx, req, func(visited, found int) {
+				fyne.Do(func() { status.SetText(fmt.Sprintf(ui.t("search_progress"), visited, found)) })
+			})
+			cf()
+			fyne.Do(func() {
+				cancel = nil
+				searchBtn.Enable()
+				cancelBtn.Disable()
+				if err != nil && !errors.Is(err, context.Canceled) {
+					status.SetText(err.Error())
+					return
+				}
+				results = results[:0]
+				if res != nil {
+					for _, r := range res.Results {
+						name := r.Name
+						if r.Path != "" {
+							name = r.Path
+						}
+						results = append(results, opc.Favorite{Ref: ui.controller.NodeRef(r.NodeID), Name: name})
+					}
+				}
+				list.Refresh()
+				switch {
+				case errors.Is(err, context.Canceled):
+					status.SetText(fmt.Sprintf(ui.t("search_cancelled"), len(results)))
+				default:
+					msg := fmt.Sprintf(ui.t("search_done"), len(results), ui.t("search_mode_"+res.Mode))
+					if res.Truncated {
+						msg = fmt.Sprintf(ui.t("search_truncated"), len(results), ui.t("search_mode_"+res.Mode))
+					}
+					if res.Cached {
+						msg += " " + ui.t("search_cached")
+					}
+					if res.Incomplete {
+						msg += " " + ui.t("search_incomplete")
+					}
+					status.SetText(msg)
+				}
+			})
+		}()
+	}
+	searchBtn.OnTapped = run
+	textEntry.OnSubmitted = func(string) { run() }
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("search_text"), textEntry),
+		widget.NewFormItem(ui.t("search_mode"), modeSelect),
+		widget.NewFormItem(ui.t("search_start"), startEntry),
+	)
+	top := container.NewVBox(form, container.NewHBox(searchBtn, cancelBtn), status)
+	dlg := dialog.NewCustom(ui.t("search"), ui.t("close_btn"), container.NewBorder(top, nil, nil, nil, list), ui.window)
+	dlg.SetOnClosed(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	dlg.Resize(fyne.NewSize(720, 560))
+	dlg.Show()
+	ui.window.Canvas().Focus(textEntry)
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showServerActions lists the methods of the Server object, its VendorServerInfo and
+// ServerConfiguration, such as RequestServerStateChange or vendor reset methods. Every call is
+// confirmed twice.
+func (ui *UI) showServerActions() {
+	status := widget.NewLabel(ui.t("server_actions_loading"))
+	status.Wrapping = fyne.TextWrapWord
+	box := container.NewVBox()
+	hint := widget.NewLabel(ui.t("server_actions_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.WarningImportance
+
+	dlg := dialog.NewCustom(ui.t("server_actions"), ui.t("close_btn"),
+		container.NewBorder(container.NewVBox(hint, status), nil, nil, nil, container.NewVScroll(box)), ui.window)
+	dlg.Resize(fyne.NewSize(640, 480))
+	dlg.Show()
+
+	go func() {
+		actions, err := ui.controller.ServerActions(context.Background())
+		fyne.Do(func() {
+			if err != nil {
+				status.Importance = widget.DangerImportance
+				status.SetText(err.Error())
+				return
+			}
+			if len(actions) == 0 {
+				status.SetText(ui.t("server_actions_none"))
+				return
+			}
+			status.SetText(fmt.Sprintf(ui.t("server_actions_found"), len(actions)))
+			for _, a := range actions {
+				btn := widget.NewButtonWithIcon(a.Name, theme.MediaPlayIcon(), func() {
+					if a.StateChange {
+						ui.showServerStateChange()
+					} else {
+						ui.confirmServerAction(a)
+					}
+				})
+				if !a.Callable() {
+					btn.Disable()
+				}
+				detail := a.Object + " · " + a.MethodID
+				switch {
+				case a.Description != "":
+					detail += " · " + a.Description
+				case len(a.Inputs) > 0 && !a.StateChange:
+					detail += " · " + fmt.Sprintf(ui.t("server_action_needs_args"), strings.Join(a.Inputs, ", "))
+				}
+				lbl := widget.NewLabel(detail)
+				lbl.Importance = widget.LowImportance
+				lbl.Wrapping = fyne.TextWrapWord
+				box.Add(container.NewBorder(nil, nil, btn, nil, lbl))
+			}
+		})
+	}()
+}
+
+// confirmServerActionTwice asks for confirmation of what, then for typing word, and calls run
+// when both are given.
+func (ui *UI) confirmServerActionTwice(title, what, word string, run func()) {
+	dialog.ShowConfirm(title, what, func(ok bool) {
+		if !ok {
+			return
+		}
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(word)
+		prompt := widget.NewLabel(fmt.Sprintf(ui.t("server_action_type_to_confirm"), word))
+		prompt.Wrapping = fyne.TextWrapWord
+		dialog.ShowCustomConfirm(title, ui.t("server_action_execute"), ui.t("cancel_btn"),
+			container.NewVBox(prompt, entry), func(ok bool) {
+				if !ok {
+					return
+				}
+				if strings.TrimSpace(entry.Text) != word {
+					dialog.ShowInformation(title, ui.t("server_action_not_confirmed"), ui.window)
+					return
+				}
+				run()
+			}, ui.window)
+	}, ui.window)
+}
+
+// confirmServerAction calls a method without arguments after a double confirmation.
+func (ui *UI) confirmServerAction(a controller.ServerAction) {
+	what := fmt.Sprintf(ui.t("server_action_confirm"), a.Name, a.Object, ui.endpointEntry.Text)
+	ui.confirmServerActionTwice(ui.t("server_actions"), what, a.Name, func() {
+		go func() {
+			out, err := ui.controller.CallServerAction(context.Background(), a.ObjectID, a.MethodID)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				msg := fmt.Sprintf(ui.t("server_action_done"), a.Name)
+				if len(out) > 0 {
+					msg += "\n" + strings.Join(out, "\n")
+				}
+				dialog.ShowInformation(ui.t("server_actions"), msg, ui.window)
+			})
+		}()
+	})
+}
+
+// showServerStateChange asks for the arguments of RequestServerStateChange, e.g. a shutdown with
+// restart to reboot the server, and calls it after a double confirmation.
+func (ui *UI) showServerStateChange() {
+	stateSelect := widget.NewSelect(controller.ServerStates, nil)
+	stateSelect.SetSelectedIndex(0)
+	restartCheck := widget.NewCheck(ui.t("server_state_restart"), nil)
+	restartCheck.SetChecked(true)
+	delayEntry := widget.NewEntry()
+	delayEntry.SetText("5")
+	returnEntry := widget.NewEntry()
+	returnEntry.SetText("60")
+	reasonEntry := widget.NewEntry()
+	reasonEntry.SetText("Commissioning")
+
+	dialog.ShowForm("RequestServerStateChange", ui.t("server_action_execute"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem(ui.t("server_state"), stateSelect),
+			widget.NewFormItem("", restartCheck),
+			widget.NewFormItem(ui.t("server_state_delay_s"), delayEntry),
+			widget.NewFormItem(ui.t("server_state_return_s"), returnEntry),
+			widget.NewFormItem(ui.t("server_state_reason"), reasonEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			delay, err := strconv.ParseUint(strings.TrimSpace(delayEntry.Text), 10, 32)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %v", ui.t("server_state_delay_s"), err), ui.window)
+				return
+			}
+			ret, err := strconv.ParseFloat(strings.TrimSpace(returnEntry.Text), 64)
+			if err != nil || ret < 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid value", ui.t("server_state_return_s")), ui.window)
+				return
+			}
+			req := controller.ServerStateChangeRequest{
+				State:               stateSelect.Selected,
+				SecondsTillShutdown: uint32(delay),
+				EstimatedReturn:     time.Duration(ret * float64(time.Second)),
+				Reason:              reasonEntry.Text,
+				Restart:             restartCheck.Checked,
+			}
+			what := fmt.Sprintf(ui.t("server_state_confirm"), req.State, ui.endpointEntry.Text)
+			if req.Restart {
+				what += " " + ui.t("server_state_confirm_restart")
+			}
+			ui.confirmServerActionTwice("RequestServerStateChange", what, req.State, func() {
+				go func() {
+					err := ui.controller.RequestServerStateChange(context.Background(), req)
+					fyne.Do(func() {
+						if err != nil {
+							dialog.ShowError(err, ui.window)
+							return
+						}
+						dialog.ShowInformation(ui.t("server_actions"), ui.t("server_state_accepted"), ui.window)
+					})
+				}()
+			})
+		}, ui.window)
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+)
+
+// serverBannerText is the short form shown beside the status icon, e.g. "Prosys Simulation Server 5.4.6".
+func serverBannerText(si *opc.ServerIdentity) string {
+	name := si.ProductName
+	if name == "" {
+		name = si.ApplicationName
+	}
+	text := strings.TrimSpace(name + " " + si.SoftwareVersion)
+	if text == "" {
+		text = si.ApplicationURI
+	}
+	const maxLen = 40
+	if r := []rune(text); len(r) > maxLen {
+		text = string(r[:maxLen-1]) + "…"
+	}
+	return text
+}
+
+// setServerIdentity updates the banner beside the status icon; nil hides it.
+func (ui *UI) setServerIdentity(si *opc.ServerIdentity) {
+	if si == nil {
+		ui.serverBanner.Hide()
+		return
+	}
+	ui.serverBanner.Importance = widget.LowImportance
+	ui.serverBanner.SetText(serverBannerText(si))
+	ui.serverBanner.Show()
+}
+
+// showServerDiagnostics shows the connected server's identity and the connection parameters.
+func (ui *UI) showServerDiagnostics() {
+	si := ui.controller.ServerIdentity()
+	if si == nil {
+		return
+	}
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("2006-01-02 15:04:05")
+	}
+	uptime := ""
+	if !si.StartTime.IsZero() {
+		uptime = time.Since(si.StartTime).Truncate(time.Second).String()
+	}
+	security := ""
+	if ui.config != nil {
+		security = fmt.Sprintf("%s / %s", ui.config.SecurityPolicy, ui.config.SecurityMode)
+	}
+	label := widget.NewLabel
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("endpoint"), label(ui.endpointEntry.Text)),
+		widget.NewFormItem(ui.t("security_policy"), label(security)),
+		widget.NewFormItem(ui.t("trust_list_title"), container.NewHBox(widget.NewButtonWithIcon(ui.t("trust_list_show"), theme.ListIcon(), ui.showServerTrustList))),
+		widget.NewFormItem(ui.t("server_actions"), container.NewHBox(widget.NewButtonWithIcon(ui.t("trust_list_show"), theme.WarningIcon(), ui.showServerActions))),
+		widget.NewFormItem(ui.t("server_application"), label(si.ApplicationName)),
+		widget.NewFormItem("ApplicationUri", label(si.ApplicationURI)),
+		widget.NewFormItem("ProductUri", label(si.ProductURI)),
+		widget.NewFormItem(ui.t("product_name"), label(si.ProductName)),
+		widget.NewFormItem(ui.t("manufacturer"), label(si.ManufacturerName)),
+		widget.NewFormItem(ui.t("software_version"), label(si.SoftwareVersion)),
+		widget.NewFormItem(ui.t("build_number"), label(si.BuildNumber)),
+		widget.NewFormItem(ui.t("build_date"), label(formatTime(si.BuildDate))),
+		widget.NewFormItem(ui.t("server_start_time"), label(formatTime(si.StartTime))),
+		widget.NewFormItem(ui.t("server_uptime"), label(uptime)),
+		widget.NewFormItem(ui.t("clock_offset"), ui.clockOffsetLabel(ui.controller.ClockOffset())),
+	)
+
+	subForm := widget.NewForm()
+	trafficForm := widget.NewForm()
+	refreshBtn := widget.NewButtonWithIcon(ui.t("refresh"), theme.ViewRefreshIcon(), nil)
+	refreshBtn.OnTapped = func() {
+		refreshBtn.Disable()
+		go func() {
+			st := ui.controller.SubscriptionStats()
+			cs := ui.controller.ChannelStats()
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				ui.fillSubscriptionStats(subForm, st)
+				ui.fillChannelStats(trafficForm, cs)
+			})
+		}()
+	}
+	refreshBtn.OnTapped()
+
+	content := container.NewVBox(form, widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabelWithStyle(ui.t("subscription_section"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), refreshBtn),
+		subForm, widget.NewSeparator(),
+		widget.NewLabelWithStyle(ui.t("traffic_section"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		trafficForm)
+	dlg := dialog.NewCustom(ui.t("server_diagnostics"), ui.t("close_btn"), container.NewVScroll(content), ui.window)
+	dlg.Resize(fyne.NewSize(560, 640))
+	dlg.Show()
+}
+
+// clockOffsetLabel shows the offset of the server clock from the local one, highlighted when it
+// exceeds the warning threshold.
+func (ui *UI) clockOffsetLabel(off *opc.ClockOffset) *widget.Label {
+	lbl := widget.NewLabel(ui.t("clock_offset_unknown"))
+	if off == nil {
+		return lbl
+	}
+	lbl.SetText(fmt.Sprintf(ui.t("clock_offset_fmt"), controller.FormatClockOffset(off.Offset()), off.RoundTripMs, off.CheckedAt.Format("15:04:05")))
+	if off.Skewed {
+		lbl.SetText(lbl.Text + "  " + fmt.Sprintf(ui.t("clock_offset_skewed"), ui.config.ClockSkewWarn()))
+		lbl.Importance = widget.WarningImportance
+	}
+	return lbl
+}
+
+// fillSubscriptionStats shows the revised parameters and publish/keep-alive statistics of the
+// watch subscriptions, one block per rate group, including the server's SubscriptionDiagnostics
+// when available.
+func (ui *UI) fillSubscriptionStats(form *widget.Form, stats []*opc.SubscriptionStats) {
+	form.Items = nil
+	if len(stats) == 0 {
+		form.Append("", widget.NewLabel(ui.t("no_subscription")))
+		form.Refresh()
+		return
+	}
+	for _, st := range stats {
+		ui.appendSubscriptionStats(form, st)
+	}
+	form.Refresh()
+}
+
+func (ui *UI) appendSubscriptionStats(form *widget.Form, st *opc.SubscriptionStats) {
+	label := widget.NewLabel
+	add := func(key string, v interface{}) {
+		form.Append(ui.t(key), label(fmt.Sprint(v)))
+	}
+	group := ui.t("rate_default")
+	if st.RateGroup > 0 {
+		group = st.RateGroup.String()
+	}
+	form.Append(ui.t("rate_group"), widget.NewLabelWithStyle(group, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	last := ""
+	if !st.LastNotification.IsZero() {
+		last = fmt.Sprintf("%s (%s ago)", st.LastNotification.Format("15:04:05"), time.Since(st.LastNotification).Truncate(time.Second))
+	}
+	add("subscription_id", st.SubscriptionID)
+	add("publish_interval", st.PublishingInterval)
+	add("lifetime_count", st.LifetimeCount)
+	add("max_keepalive_count", fmt.Sprintf("%d (%s)", st.MaxKeepAliveCount, time.Duration(st.MaxKeepAliveCount)*st.PublishingInterval))
+	add("priority", st.Priority)
+	add("monitored_items", st.MonitoredItems)
+	add("notifications_received", st.Notifications)
+	add("data_changes_received", st.DataChanges)
+	add("publish_errors", st.Errors)
+	add("last_notification", last)
+	if d := st.Server; d != nil {
+		add("server_publish_requests", d.PublishRequestCount)
+		add("server_late_publish", d.LatePublishRequestCount)
+		add("server_keepalive_count", d.CurrentKeepAliveCount)
+		add("server_lifetime_count", d.CurrentLifetimeCount)
+		add("server_unacked", d.UnacknowledgedMessageCount)
+		add("server_discarded", d.DiscardedMessageCount)
+		add("server_queue_overflows", d.MonitoringQueueOverflowCount)
+	} else {
+		msg := widget.NewLabel(ui.t("server_diag_unavailable"))
+		if st.ServerErr != nil {
+			msg.SetText(fmt.Sprintf("%s: %v", ui.t("server_diag_unavailable"), st.ServerErr))
+		}
+		msg.Wrapping = fyne.TextWrapWord
+		form.Append("", msg)
+	}
+}
+
+// fillChannelStats shows the requests, latencies and bytes of the current connection, totals
+// first and then one row per OPC UA service.
+func (ui *UI) fillChannelStats(form *widget.Form, cs *opc.ChannelStats) {
+	form.Items = nil
+	label := widget.NewLabel
+	if cs == nil {
+		form.Append("", label(ui.t("not_connected")))
+		form.Refresh()
+		return
+	}
+	since := ""
+	if !cs.Since.IsZero() {
+		since = fmt.Sprintf("%s (%s)", cs.Since.Format("15:04:05"), time.Since(cs.Since).Truncate(time.Second))
+	}
+	form.Append(ui.t("traffic_since"), label(since))
+	form.Append(ui.t("traffic_requests"), label(fmt.Sprintf(ui.t("traffic_requests_fmt"), cs.Requests, cs.Errors, cs.AvgLatencyMs)))
+	form.Append(ui.t("traffic_bytes"), label(fmt.Sprintf("↑ %s  ↓ %s", formatByteCount(cs.BytesSent), formatByteCount(cs.BytesReceived))))
+	form.Append(ui.t("notifications_received"), label(fmt.Sprint(cs.Notifications)))
+	for _, s := range cs.Services {
+		row := fmt.Sprintf(ui.t("traffic_service_fmt"), s.Requests, s.Errors, s.AvgLatencyMs, s.MaxLatencyMs)
+		if s.BytesSent > 0 || s.BytesReceived > 0 {
+			row += fmt.Sprintf("  ↑ %s  ↓ %s", formatByteCount(s.BytesSent), formatByteCount(s.BytesReceived))
+		}
+		form.Append(s.Service, label(row))
+	}
+	hint := label(ui.t("traffic_bytes_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	form.Append("", hint)
+	form.Refresh()
+}
+
+// formatByteCount renders n with a binary unit, e.g. "12.3 KiB".
+func formatByteCount(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSimulationDialog starts a value simulation on nodeID: a generated waveform written every
+// interval until stopped from the simulations list.
+func (ui *UI) showSimulationDialog(nodeID, dataType string) {
+	waveLabels := make([]string, len(controller.SimWaveforms))
+	for i, w := range controller.SimWaveforms {
+		waveLabels[i] = ui.t("sim_" + w)
+	}
+	waveSelect := widget.NewSelect(waveLabels, nil)
+	waveSelect.SetSelectedIndex(0)
+	minEntry := widget.NewEntry()
+	minEntry.SetText("0")
+	maxEntry := widget.NewEntry()
+	maxEntry.SetText("100")
+	if strings.EqualFold(dataType, "Boolean") {
+		maxEntry.SetText("1")
+	}
+	periodEntry := widget.NewEntry()
+	periodEntry.SetText("60")
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("1")
+
+	dialog.ShowForm(ui.t("simulate")+": "+nodeID, ui.t("sim_start"), ui.t("cancel_btn"),
+		[]*widget.FormItem{
+			widget.NewFormItem("Data Type", widget.NewLabel(dataType)),
+			widget.NewFormItem(ui.t("sim_waveform"), waveSelect),
+			widget.NewFormItem(ui.t("sim_min"), minEntry),
+			widget.NewFormItem(ui.t("sim_max"), maxEntry),
+			widget.NewFormItem(ui.t("sim_period_s"), periodEntry),
+			widget.NewFormItem(ui.t("sim_interval_s"), intervalEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			var nums [4]float64
+			for i, e := range []*widget.Entry{minEntry, maxEntry, periodEntry, intervalEntry} {
+				v, err := strconv.ParseFloat(strings.TrimSpace(e.Text), 64)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("invalid number %q", e.Text), ui.window)
+					return
+				}
+				nums[i] = v
+			}
+			waveform := controller.SimWaveforms[max(waveSelect.SelectedIndex(), 0)]
+			period := time.Duration(nums[2] * float64(time.Second))
+			interval := time.Duration(nums[3] * float64(time.Second))
+			go func() {
+				_, err := ui.controller.StartSimulation(nodeID, waveform, nums[0], nums[1], period, interval)
+				fyne.Do(func() {
+					if err != nil {
+						dialog.ShowError(err, ui.window)
+						return
+					}
+					ui.showSimulations()
+				})
+			}()
+		}, ui.window)
+}
+
+// showSimulations lists the running simulations with their last written value; the selected one
+// or all of them can be stopped.
+func (ui *UI) showSimulations() {
+	if ui.simTable != nil {
+		ui.refreshSimulations()
+		return
+	}
+	headers := []string{"NodeID", ui.t("sim_waveform"), ui.t("sim_range"), ui.t("sim_period_s") + " / " + ui.t("sim_interval_s"), ui.t("sim_writes"), ui.t("sim_last_value")}
+	selected := -1
+	table := widget.NewTable(
+		func() (int, int) { return len(ui.simRows) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.Importance = widget.MediumImportance
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			sim := ui.simRows[id.Row-1]
+			lbl.TextStyle = fyne.TextStyle{}
+			lbl.Importance = widget.MediumImportance
+			switch id.Col {
+			case 0:
+				lbl.SetText(sim.NodeID)
+			case 1:
+				lbl.SetText(ui.t("sim_" + sim.Waveform))
+			case 2:
+				lbl.SetText(fmt.Sprintf("%g .. %g", sim.Min, sim.Max))
+			case 3:
+				lbl.SetText(fmt.Sprintf("%g / %g", sim.Period.Seconds(), sim.Interval.Seconds()))
+			case 4:
+				if sim.Failures > 0 {
+					lbl.Importance = widget.WarningImportance
+					lbl.SetText(fmt.Sprintf("%d (%d failed)", sim.Writes, sim.Failures))
+				} else {
+					lbl.SetText(strconv.Itoa(sim.Writes))
+				}
+			case 5:
+				if sim.LastError != "" {
+					lbl.Importance = widget.DangerImportance
+					lbl.SetText(sim.LastError)
+				} else {
+					lbl.SetText(sim.LastValue)
+				}
+			}
+		},
+	)
+	for i, w := range []float32{240, 90, 120, 200, 110, 200} {
+		table.SetColumnWidth(i, w)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		selected = id.Row - 1
+	}
+
+	stopBtn := widget.NewButton(ui.t("sim_stop"), func() {
+		if selected < 0 || selected >= len(ui.simRows) {
+			return
+		}
+		nodeID := ui.simRows[selected].NodeID
+		go ui.controller.StopSimulation(nodeID)
+	})
+	stopAllBtn := widget.NewButton(ui.t("sim_stop_all"), func() {
+		go ui.controller.StopAllSimulations("stopped by user")
+	})
+
+	ui.simTable = table
+	ui.refreshSimulations()
+	content := container.NewBorder(nil, container.NewHBox(stopBtn, stopAllBtn), nil, nil, table)
+	dlg := dialog.NewCustom(ui.t("simulations"), ui.t("close_btn"), content, ui.window)
+	dlg.SetOnClosed(func() {
+		ui.simTable = nil
+	})
+	winSize := ui.window.Canvas().Size()
+	dlg.Resize(fyne.NewSize(winSize.Width*0.8, winSize.Height*0.5))
+	dlg.Show()
+}
+
+// refreshSimulations reloads the open simulations dialog. Must run on the UI thread.
+func (ui *UI) refreshSimulations() {
+	if ui.simTable == nil {
+		return
+	}
+	ui.simRows = ui.controller.Simulations()
+	ui.simTable.Refresh()
+}
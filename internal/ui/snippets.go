@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"opcuababy/internal/api"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSnippetsDialog shows Python, Node.js and Go code reading, writing and subscribing to nodeID
+// through the local REST and WebSocket API. The node is read for its data type and value.
+func (ui *UI) showSnippetsDialog(nodeID string) {
+	go func() {
+		dataType, value := "", ""
+		if attrs, err := ui.controller.ReadNodeAttributes(context.Background(), nodeID); err == nil && attrs != nil {
+			dataType, value = attrs.DataType, attrs.Value
+		}
+		snippets := api.Snippets(ui.apiBaseURL(), nodeID, dataType, value)
+		fyne.Do(func() { ui.showSnippets(nodeID, snippets) })
+	}()
+}
+
+func (ui *UI) showSnippets(nodeID string, snippets []api.Snippet) {
+	languageNames := map[string]string{"python": "Python", "nodejs": "Node.js", "go": "Go"}
+	operationNames := map[string]string{
+		"read":      ui.t("snippet_read"),
+		"write":     ui.t("snippet_write"),
+		"subscribe": ui.t("snippet_subscribe"),
+	}
+	var languages, operations []string
+	for _, l := range api.SnippetLanguages {
+		languages = append(languages, languageNames[l])
+	}
+	for _, o := range api.SnippetOperations {
+		operations = append(operations, operationNames[o])
+	}
+
+	code := widget.NewMultiLineEntry()
+	code.TextStyle = fyne.TextStyle{Monospace: true}
+	code.Wrapping = fyne.TextWrapOff
+	langSel := widget.NewSelect(languages, nil)
+	opRadio := widget.NewRadioGroup(operations, nil)
+	opRadio.Horizontal = true
+	opRadio.Required = true
+	update := func() {
+		for _, s := range snippets {
+			if languageNames[s.Language] == langSel.Selected && operationNames[s.Operation] == opRadio.Selected {
+				code.SetText(s.Code)
+			}
+		}
+	}
+	langSel.OnChanged = func(string) { update() }
+	opRadio.OnChanged = func(string) { update() }
+	langSel.SetSelectedIndex(0)
+	opRadio.SetSelected(operations[0])
+
+	hint := widget.NewLabel(fmt.Sprintf(ui.t("snippet_hint"), ui.apiBaseURL()))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	if !ui.config.ApiEnabled {
+		hint.SetText(hint.Text + " " + ui.t("snippet_api_disabled"))
+	}
+	copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+		ui.app.Clipboard().SetContent(code.Text)
+		ui.controller.Log(fmt.Sprintf("[green]Copied %s %s snippet for %s[-]", langSel.Selected, opRadio.Selected, nodeID))
+	})
+	buttons := container.NewHBox(copyBtn)
+	if ui.config.ApiEnabled {
+		page, err := url.Parse(ui.apiBaseURL() + "/snippets?" + url.Values{"node_id": {nodeID}}.Encode())
+		if err == nil {
+			buttons.Add(widget.NewButtonWithIcon(ui.t("snippet_open_page"), theme.ComputerIcon(), func() {
+				_ = ui.app.OpenURL(page)
+			}))
+		}
+	}
+
+	top := container.NewVBox(container.NewBorder(nil, nil, langSel, nil, opRadio), hint)
+	content := container.NewBorder(top, buttons, nil, nil, code)
+	dlg := dialog.NewCustom(fmt.Sprintf(ui.t("snippet_title"), nodeID), ui.t("close_btn"), content, ui.window)
+	dlg.Resize(fyne.NewSize(760, 560))
+	dlg.Show()
+}
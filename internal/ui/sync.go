@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"opcuababy/internal/profilesync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// syncDevice names this device in sync documents.
+func syncDevice() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return host + " (" + runtime.GOOS + ")"
+}
+
+// showSyncDialog edits the sync location of profiles, watch list and favorites and syncs on
+// demand.
+func (ui *UI) showSyncDialog() {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder(ui.t("placeholder_sync_url"))
+	urlEntry.SetText(ui.config.SyncURL)
+	userEntry := widget.NewEntry()
+	userEntry.SetPlaceHolder(ui.t("sync_username_hint"))
+	userEntry.SetText(ui.config.SyncUsername)
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetText(ui.config.SyncPassword)
+	s3EndpointEntry := widget.NewEntry()
+	s3EndpointEntry.SetPlaceHolder("https://s3.<region>.amazonaws.com")
+	s3EndpointEntry.SetText(ui.config.SyncS3Endpoint)
+	s3RegionEntry := widget.NewEntry()
+	s3RegionEntry.SetPlaceHolder("us-east-1")
+	s3RegionEntry.SetText(ui.config.SyncS3Region)
+	onStartCheck := widget.NewCheck(ui.t("sync_on_start"), nil)
+	onStartCheck.SetChecked(ui.config.SyncOnStart)
+	hint := widget.NewLabel(ui.t("sync_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	status := widget.NewLabel(ui.syncStatusText())
+	status.Wrapping = fyne.TextWrapWord
+
+	apply := func() {
+		newURL := strings.TrimSpace(urlEntry.Text)
+		if newURL != ui.config.SyncURL {
+			// Another location knows nothing of our last sync
+			ui.config.SyncETag, ui.config.SyncHash = "", ""
+			ui.config.SyncTime = time.Time{}
+		}
+		ui.config.SyncURL = newURL
+		ui.config.SyncUsername = strings.TrimSpace(userEntry.Text)
+		ui.config.SyncPassword = passwordEntry.Text
+		ui.config.SyncS3Endpoint = strings.TrimSpace(s3EndpointEntry.Text)
+		ui.config.SyncS3Region = strings.TrimSpace(s3RegionEntry.Text)
+		ui.config.SyncOnStart = onStartCheck.Checked
+		ui.saveConfig()
+	}
+
+	var syncBtn *widget.Button
+	syncBtn = widget.NewButtonWithIcon(ui.t("sync_now"), theme.ViewRefreshIcon(), func() {
+		apply()
+		syncBtn.Disable()
+		status.SetText(ui.t("sync_running"))
+		ui.runSync(profilesync.ResolveAsk, func() {
+			syncBtn.Enable()
+			status.SetText(ui.syncStatusText())
+		})
+	})
+	syncBtn.Importance = widget.HighImportance
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("sync_url"), urlEntry),
+		widget.NewFormItem(ui.t("username"), userEntry),
+		widget.NewFormItem(ui.t("placeholder_password"), passwordEntry),
+		widget.NewFormItem(ui.t("sync_s3"), container.NewGridWithColumns(2, s3EndpointEntry, s3RegionEntry)),
+		widget.NewFormItem("", onStartCheck),
+	)
+	content := container.NewVBox(form, hint, widget.NewSeparator(), status, container.NewHBox(syncBtn))
+	dlg := dialog.NewCustom(ui.t("sync_title"), ui.t("close_btn"), content, ui.window)
+	dlg.SetOnClosed(apply)
+	dlg.Resize(fyne.NewSize(600, 420))
+	dlg.Show()
+}
+
+// syncStatusText describes the last sync.
+func (ui *UI) syncStatusText() string {
+	if ui.config.SyncTime.IsZero() {
+		return ui.t("sync_never")
+	}
+	return fmt.Sprintf(ui.t("sync_last"), ui.config.SyncTime.Local().Format("2006-01-02 15:04:05"))
+}
+
+// runSync syncs in the background and applies the result to the config. A conflict is put to
+// the user, whose choice runs the sync again. done, if set, runs on the UI thread afterwards.
+func (ui *UI) runSync(resolve profilesync.Resolution, done func()) {
+	finish := func() {
+		if done != nil {
+			done()
+		}
+	}
+	store, err := profilesync.OpenStore(ui.config)
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		finish()
+		return
+	}
+	local := profilesync.FromConfig(ui.config, syncDevice())
+	state := profilesync.State{ETag: ui.config.SyncETag, Hash: ui.config.SyncHash}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		res, err := profilesync.Sync(ctx, store, local, state, resolve)
+		fyne.Do(func() {
+			if err != nil {
+				ui.controller.Log(fmt.Sprintf("[red]Sync with %s failed: %v[-]", store, err))
+				dialog.ShowError(fmt.Errorf("%s: %w", ui.t("sync_title"), err), ui.window)
+				finish()
+				return
+			}
+			if res.Outcome == profilesync.OutcomeConflict {
+				ui.showSyncConflict(res.Remote, done)
+				return
+			}
+			defer finish()
+			if res.Apply != nil {
+				oldWatch := ui.config.WatchList
+				res.Apply.ApplyTo(ui.config)
+				ui.refreshFavorites()
+				if !slices.Equal(oldWatch, ui.config.WatchList) {
+					refs := ui.config.WatchList
+					go ui.controller.ApplyWatchListRefs(context.Background(), refs)
+				}
+			}
+			ui.config.SyncETag, ui.config.SyncHash = res.State.ETag, res.State.Hash
+			ui.config.SyncTime = time.Now()
+			ui.saveConfig()
+			ui.controller.Log(fmt.Sprintf("[green]Sync with %s: %s[-]", store, res.Outcome))
+		})
+	}()
+}
+
+// showSyncConflict asks how to resolve changes made both here and on another device since the
+// last sync.
+func (ui *UI) showSyncConflict(remote *profilesync.Document, done func()) {
+	msg := widget.NewLabel(fmt.Sprintf(ui.t("sync_conflict_msg"), remote.Device, remote.Updated.Local().Format("2006-01-02 15:04:05"),
+		len(ui.config.Profiles), len(ui.config.WatchList), len(ui.config.Favorites),
+		len(remote.Profiles), len(remote.WatchList), len(remote.Favorites)))
+	msg.Wrapping = fyne.TextWrapWord
+
+	var dlg *dialog.CustomDialog
+	choose := func(r profilesync.Resolution) func() {
+		return func() {
+			dlg.Hide()
+			ui.runSync(r, done)
+		}
+	}
+	mergeBtn := widget.NewButton(ui.t("sync_merge"), choose(profilesync.ResolveMerge))
+	mergeBtn.Importance = widget.HighImportance
+	buttons := container.NewHBox(
+		widget.NewButton(ui.t("sync_keep_local"), choose(profilesync.ResolveKeepLocal)),
+		widget.NewButton(ui.t("sync_use_remote"), choose(profilesync.ResolveUseRemote)),
+		mergeBtn,
+	)
+	cancelBtn := widget.NewButton(ui.t("cancel_btn"), func() {
+		dlg.Hide()
+		if done != nil {
+			done()
+		}
+	})
+	dlg = dialog.NewCustomWithoutButtons(ui.t("sync_conflict"), container.NewVBox(msg, buttons, cancelBtn), ui.window)
+	dlg.Resize(fyne.NewSize(520, 240))
+	dlg.Show()
+}
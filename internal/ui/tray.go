@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+)
+
+// setupTray installs the system tray icon and menu when enabled in the config and supported by
+// the platform. With the tray active, closing the window hides it instead of quitting.
+func (ui *UI) setupTray() bool {
+	desk, ok := ui.app.(desktop.App)
+	if !ok || ui.config == nil || !ui.config.TrayEnabled {
+		return false
+	}
+	ui.trayApp = desk
+	icon := ui.app.Icon()
+	if icon == nil {
+		icon = theme.ComputerIcon()
+	}
+	desk.SetSystemTrayIcon(icon)
+	ui.refreshTray()
+	return true
+}
+
+// refreshTray rebuilds the tray menu from the current connection and API state. It must run on
+// the UI thread.
+func (ui *UI) refreshTray() {
+	if ui.trayApp == nil {
+		return
+	}
+	status := ui.t("tray_disconnected")
+	connectLabel := ui.t("connect")
+	if ui.isConnected {
+		status = ui.t("tray_connected") + ": " + ui.config.EndpointURL
+		if ui.controller.IsOffline() {
+			status = ui.t("offline_banner")
+		}
+		connectLabel = ui.t("disconnect")
+	}
+	statusItem := fyne.NewMenuItem(status, nil)
+	statusItem.Disabled = true
+
+	apiItem := fyne.NewMenuItem(ui.t("enable_api"), func() {
+		ui.config.ApiEnabled = !ui.config.ApiEnabled
+		ui.saveConfig()
+		ui.controller.UpdateApiServerState(ui.config)
+		ui.refreshTray()
+	})
+	apiItem.Checked = ui.config.ApiEnabled
+
+	menu := fyne.NewMenu("OPC UA Baby",
+		statusItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(connectLabel, ui.onConnectClicked),
+		apiItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(ui.t("tray_show_window"), func() {
+			ui.window.Show()
+			ui.window.RequestFocus()
+		}),
+	)
+	// Own Quit item so the controller is shut down like on window close; Fyne would add a bare one
+	quit := fyne.NewMenuItem(ui.t("tray_quit"), func() {
+		ui.controller.Shutdown()
+		ui.app.Quit()
+	})
+	quit.IsQuit = true
+	menu.Items = append(menu.Items, fyne.NewMenuItemSeparator(), quit)
+	ui.trayApp.SetSystemTrayMenu(menu)
+}
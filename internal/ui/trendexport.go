@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showRecordingExcelDialog converts a recording (JSON Lines of watch values, e.g. a capture file)
+// to an Excel workbook with one sheet per node. path prefills the recording.
+func (ui *UI) showRecordingExcelDialog(path string) {
+	recordingEntry := widget.NewEntry()
+	recordingEntry.SetPlaceHolder("recording.jsonl")
+	recordingEntry.SetText(path)
+	chartCheck := widget.NewCheck(ui.t("trend_chart"), nil)
+	chartCheck.SetChecked(true)
+
+	form := widget.NewForm(
+		widget.NewFormItem(ui.t("recording_file"), container.NewBorder(nil, nil, nil, ui.filePickButton(recordingEntry, []string{".jsonl", ".json", ".txt"}), recordingEntry)),
+		widget.NewFormItem("", chartCheck),
+	)
+	dlg := dialog.NewCustomConfirm(ui.t("recording_to_excel"), ui.t("export_btn"), ui.t("cancel_btn"), form, func(ok bool) {
+		recording := strings.TrimSpace(recordingEntry.Text)
+		if !ok || recording == "" {
+			return
+		}
+		chart := chartCheck.Checked
+		ui.saveExcel("recording.xlsx", func(filePath string) error {
+			return ui.controller.ExportRecordingExcel(recording, filePath, chart)
+		})
+	}, ui.window)
+	dlg.Resize(fyne.NewSize(ui.window.Canvas().Size().Width*0.6, 0))
+	dlg.Show()
+}
+
+// saveExcel asks for a target workbook and runs export on it in the background.
+func (ui *UI) saveExcel(defaultName string, export func(path string) error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		filePath := writer.URI().Path()
+		writer.Close()
+		go func() {
+			err := export(filePath)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				dialog.ShowInformation(ui.t("export_excel"), fmt.Sprintf(ui.t("ua_json_exported"), filePath), ui.window)
+			})
+		}()
+	}, ui.window)
+	saveDialog.SetFileName(defaultName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx"}))
+	saveDialog.Show()
+}
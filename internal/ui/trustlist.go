@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"opcuababy/internal/controller"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showServerTrustList shows the server's TrustList (ServerConfiguration) and offers to add the
+// client certificate to it, for servers that let a SecurityAdmin manage trust remotely.
+func (ui *UI) showServerTrustList() {
+	certFile := ui.config.CertFile
+	var entries []controller.TrustListEntry
+	headers := []string{ui.t("trust_list_list"), ui.t("trust_list_kind"), ui.t("trust_list_subject"), ui.t("trust_list_expires"), ui.t("trust_thumbprint")}
+	table := widget.NewTable(
+		func() (int, int) { return len(entries) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			lbl := obj.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.SetText(headers[id.Col])
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+			e := entries[id.Row-1]
+			switch id.Col {
+			case 0:
+				lbl.SetText(ui.t("trust_list_" + e.List))
+			case 1:
+				lbl.SetText(ui.t("trust_list_" + e.Kind))
+			case 2:
+				if e.Subject != "" {
+					lbl.SetText(e.Subject)
+				} else {
+					lbl.SetText(e.Issuer)
+				}
+			case 3:
+				if e.NotAfter.IsZero() {
+					lbl.SetText("")
+				} else {
+					lbl.SetText(e.NotAfter.Local().Format("2006-01-02"))
+				}
+			case 4:
+				lbl.SetText(e.Thumbprint)
+			}
+		},
+	)
+	for i, w := range []float32{80, 90, 300, 100, 340} {
+		table.SetColumnWidth(i, w)
+	}
+
+	status := widget.NewLabel(ui.t("trust_list_reading"))
+	status.Wrapping = fyne.TextWrapWord
+	refreshBtn := widget.NewButtonWithIcon(ui.t("refresh"), theme.ViewRefreshIcon(), nil)
+	refreshBtn.OnTapped = func() {
+		refreshBtn.Disable()
+		status.SetText(ui.t("trust_list_reading"))
+		go func() {
+			tl, err := ui.controller.ReadServerTrustList(context.Background(), certFile)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					entries = nil
+					table.Refresh()
+					status.Importance = widget.DangerImportance
+					status.SetText(fmt.Sprintf(ui.t("trust_list_read_failed"), err))
+					return
+				}
+				entries = tl.Entries
+				table.Refresh()
+				status.Importance = widget.MediumImportance
+				switch {
+				case certFile == "":
+					status.SetText(fmt.Sprintf(ui.t("trust_list_count"), len(entries)))
+				case tl.ClientTrusted:
+					status.Importance = widget.SuccessImportance
+					status.SetText(fmt.Sprintf(ui.t("trust_list_count"), len(entries)) + " " + ui.t("trust_list_client_trusted"))
+				default:
+					status.SetText(fmt.Sprintf(ui.t("trust_list_count"), len(entries)) + " " + ui.t("trust_list_client_missing"))
+				}
+			})
+		}()
+	}
+
+	pushBtn := widget.NewButtonWithIcon(ui.t("trust_list_push"), theme.UploadIcon(), func() {
+		dialog.ShowConfirm(ui.t("trust_list_push"), fmt.Sprintf(ui.t("trust_list_push_confirm"), certFile), func(ok bool) {
+			if !ok {
+				return
+			}
+			go func() {
+				err := ui.controller.PushClientCertificate(context.Background(), certFile)
+				fyne.Do(func() {
+					if err != nil {
+						dialog.ShowError(err, ui.window)
+						return
+					}
+					refreshBtn.OnTapped()
+				})
+			}()
+		}, ui.window)
+	})
+	if certFile == "" {
+		pushBtn.Disable()
+	}
+
+	hint := widget.NewLabel(ui.t("trust_list_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	top := container.NewVBox(hint, status, container.NewHBox(layout.NewSpacer(), pushBtn, refreshBtn))
+	dlg := dialog.NewCustom(ui.t("trust_list_title"), ui.t("close_btn"), container.NewBorder(top, nil, nil, nil, table), ui.window)
+	dlg.Resize(fyne.NewSize(960, 520))
+	dlg.Show()
+	refreshBtn.OnTapped()
+}
@@ -5,12 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
-	"net"
+	"net/url"
 	"opcuababy/internal/cert"
 	"opcuababy/internal/controller"
-	"opcuababy/internal/exporter"
 	"opcuababy/internal/opc"
+	"opcuababy/internal/profilesync"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,6 +27,7 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
@@ -242,6 +244,564 @@ var i18n = map[string]map[string]string{
 		"auto_generate_cert":      "Auto-generate certificates",
 		"generate_cert":           "Generate Certificates",
 		"cert_info":               "Certificate Info",
+		// History dialog
+		"history":              "History...",
+		"history_dialog":       "History",
+		"history_mode":         "Mode",
+		"raw":                  "Raw",
+		"start_time":           "Start",
+		"end_time":             "End",
+		"processing_interval":  "Interval (s)",
+		"history_interval_s":   "processing interval in seconds",
+		"history_truncated":    "%d (truncated: the server has more, narrow the time range)",
+		"read_btn":             "Read",
+		"close_btn":            "Close",
+		"edit_history":         "Edit History...",
+		"history_update_mode":  "Operation",
+		"delete_range":         "Delete Range",
+		"timestamp":            "Timestamp",
+		"value":                "Value",
+		"apply_btn":            "Apply",
+		"confirm_history_edit": "This permanently changes archived data on the server. Continue?",
+		// Attributes editor
+		"edit_attributes":        "Edit Attributes...",
+		"attribute":              "Attribute",
+		"current_value":          "Current",
+		"new_value":              "New Value",
+		"write_btn":              "Write",
+		"placeholder_attr_value": "text, locale|text, ns:name, number or true/false",
+		// Timestamps
+		"timestamp_source":     "Timestamp",
+		"timestamp_timezone":   "Time Zone",
+		"ts_source":            "Source",
+		"ts_server":            "Server",
+		"ts_client":            "Client (receive time)",
+		"placeholder_timezone": "local, utc or e.g. Europe/Berlin",
+		// OPC UA JSON export
+		"export_ua_json":   "Export UA JSON",
+		"ua_json_exported": "Exported to %s",
+		// Bulk write from CSV
+		"bulk_write":         "Bulk Write",
+		"bulk_write_summary": "%d rows: %d valid, %d invalid, %d written, %d failed",
+		"execute_btn":        "Execute",
+		"export_report":      "Export Report",
+		"confirm_bulk_write": "Write %d values to the server?",
+		// Endpoint test
+		"test_btn":             "Test",
+		"endpoint_reachable":   "Endpoint Reachable",
+		"endpoint_unreachable": "Endpoint unreachable",
+		"channel_open_time":    "Secure Channel Open",
+		"round_trip_time":      "Round Trip",
+		"server_application":   "Server Application",
+		"security_policies":    "Security Policies",
+		"user_tokens":          "User Tokens",
+		// Protocol trace
+		"protocol_trace":      "Trace OPC UA service calls to file",
+		"protocol_trace_file": "Trace File",
+		// Request timeouts
+		"request_timeouts_s": "Request Timeouts (s)",
+		"timeout_read":       "Read",
+		"timeout_browse":     "Browse",
+		"timeout_write":      "Write",
+		"timeout_publish":    "Publish",
+		"timeout_export":     "Export",
+		// Strict AccessLevel mode
+		"strict_access_level": "Strict mode: refuse writes when AccessLevel is unknown",
+		// Server identity
+		"server_diagnostics": "Server Diagnostics",
+		"product_name":       "Product Name",
+		"manufacturer":       "Manufacturer",
+		"software_version":   "Software Version",
+		"build_number":       "Build Number",
+		"build_date":         "Build Date",
+		"server_start_time":  "Start Time",
+		"server_uptime":      "Uptime",
+		// Offline mode
+		"offline_mode":          "Offline",
+		"offline_address_space": "Address space (JSON export)",
+		"offline_recording":     "Recorded values (JSON Lines, optional)",
+		"offline_banner":        "OFFLINE",
+		"start_btn":             "Start",
+		// Update rates
+		"update_rates_ms":  "Update Rates (ms)",
+		"publish_interval": "Publish",
+		"watch_refresh":    "Watch refresh",
+		// Node report
+		"copy_as": "Copy as…",
+		// System tray
+		"tray_connected":    "Connected",
+		"tray_disconnected": "Disconnected",
+		"tray_show_window":  "Show Window",
+		"tray_quit":         "Quit",
+		"tray_enabled":      "Show tray icon (close hides window)",
+		"start_minimized":   "Start minimized to tray",
+		// API lifetime
+		"keep_api_running": "Keep API running while disconnected",
+		// NodeSet2
+		"nodeset_compare":      "Compare with live server…",
+		"nodeset_diff_summary": "%d baseline nodes checked: %d missing, %d extra, %d mismatched",
+		// NodeSet2 offline
+		"nodeset_browse_offline": "Browse offline…",
+		// Scheduled writes
+		"schedule_write":   "Schedule / Ramp…",
+		"schedule_btn":     "Schedule",
+		"scheduled_writes": "Scheduled Writes",
+		"target_value":     "Target Value",
+		"start_at":         "Start (s or HH:MM)",
+		"ramp_duration_s":  "Ramp Duration (s)",
+		"ramp_steps":       "Steps",
+		"cancel_write":     "Cancel Selected",
+		"clear_finished":   "Clear Finished",
+		// Golden values
+		"expected_value":    "Expected",
+		"tolerance":         "Tolerance (±)",
+		"golden_empty_hint": "Empty removes the expected value",
+		"golden_notify":     "Notify when a watched value deviates from its expected value",
+		"golden_webhook":    "Deviation Webhook URL",
+		"golden_deviation":  "Value deviates",
+		// Trigger capture
+		"capture":            "Capture",
+		"capture_no_watches": "Add the trigger node to the watch list first.",
+		"trigger_rising":     "Rising edge (crosses threshold upwards)",
+		"trigger_falling":    "Falling edge (crosses threshold downwards)",
+		"trigger_change":     "Any change",
+		"capture_one_shot":   "One-shot (disarm after the first capture)",
+		"capture_arm":        "Arm",
+		"trigger_condition":  "Condition",
+		"trigger_threshold":  "Threshold",
+		"capture_pre_s":      "Pre-trigger (s)",
+		"capture_post_s":     "Post-trigger (s)",
+		"capture_dir":        "Output Folder",
+		"capture_armed":      "armed",
+		"capture_recording":  "recording",
+		"capture_disarm":     "Disarm",
+		"capture_written":    "Captures Written",
+		"capture_last_file":  "Last File",
+		// Write protection
+		"write_allow":         "Write allow list",
+		"write_deny":          "Write deny list",
+		"write_patterns_hint": "One per line: NodeID, prefix* or re:regexp (matching the whole NodeID)",
+		// Forced values
+		"force_value":        "Force",
+		"forced_flag":        "FORCED",
+		"force_hint":         "Shown locally only, nothing is written to the server",
+		"release_force":      "Release",
+		"release_all_forces": "Release All",
+		"server_value":       "Server Value",
+		// Favorites
+		"favorites":       "Favorites",
+		"favorites_empty": "Right-click a node in the address space and choose \"Add to Favorites\"",
+		"connect_first":   "Connect to a server first",
+		"add_favorite":    "Add to Favorites",
+		"remove_favorite": "Remove from Favorites",
+		// History menu
+		"history_btn":           "History",
+		"recent_nodes":          "Recently Viewed Nodes",
+		"write_history":         "Write History",
+		"repeat_write":          "Repeat",
+		"repeat_write_confirm":  "Write %s to %s again?",
+		"clear_history":         "Clear History",
+		"clear_history_confirm": "Clear the recently viewed nodes and the write history?",
+		"result":                "Result",
+		// Paste NodeIDs
+		"paste_node_ids":         "Paste NodeIDs",
+		"paste_node_ids_hint":    "One NodeID per line or comma-separated, e.g. ns=2;s=Tag1 or nsu=<uri>;i=1001",
+		"paste_node_ids_summary": "%d valid, %d invalid, %d already watched",
+		"add_to_watch_n":         "Add %d to Watch",
+		// Queue overflow badge in the Severity column
+		"overflow_badge": "Overflow ×%d",
+		// Subscription tuning and statistics
+		"subscription_params":     "Subscription",
+		"lifetime_count":          "Lifetime count",
+		"max_keepalive_count":     "Max keep-alive count",
+		"priority":                "Priority",
+		"subscription_section":    "Watch Subscription",
+		"subscription_id":         "Subscription ID",
+		"monitored_items":         "Monitored items",
+		"notifications_received":  "Notifications received",
+		"data_changes_received":   "Values received",
+		"publish_errors":          "Publish errors",
+		"last_notification":       "Last notification",
+		"server_publish_requests": "Publish requests (server)",
+		"server_late_publish":     "Late publish requests",
+		"server_keepalive_count":  "Current keep-alive count",
+		"server_lifetime_count":   "Current lifetime count",
+		"server_unacked":          "Unacknowledged messages",
+		"server_discarded":        "Discarded messages",
+		"server_queue_overflows":  "Queue overflows",
+		"server_diag_unavailable": "Server does not expose SubscriptionDiagnostics",
+		"no_subscription":         "No active subscription",
+		"refresh":                 "Refresh",
+		// Connection traffic
+		"traffic_section":      "Connection Traffic",
+		"traffic_since":        "Counting since",
+		"traffic_requests":     "Requests",
+		"traffic_requests_fmt": "%d (%d failed), avg %.1f ms",
+		"traffic_bytes":        "Bytes sent / received",
+		"traffic_service_fmt":  "%d req, %d err, avg %.1f ms, max %.1f ms",
+		"traffic_bytes_hint":   "Byte counts are encoded message bodies and exclude secure channel framing, signing and encryption.",
+		"not_connected":        "Not connected",
+		// UI scale and density
+		"ui_appearance":       "Appearance",
+		"ui_scale":            "Text size",
+		"ui_density":          "Density",
+		"density_comfortable": "Comfortable",
+		"density_compact":     "Compact",
+		// Number and date format
+		"format_locale":         "Number/date format",
+		"format_locale_machine": "Machine (1234.5, ISO dates)",
+		// Export templates
+		"export_template":      "Columns",
+		"export_templates":     "Export Templates",
+		"template_standard":    "Standard",
+		"template_name":        "Template name",
+		"template_new":         "New",
+		"template_fields_hint": "One column per line as \"field\" or \"field: Header\". Fields: %s",
+		// Export attribute selection
+		"export_attributes": "Attributes",
+		// Recorded values to Excel
+		"recording_to_excel": "Recording to Excel…",
+		"recording_file":     "Recording (JSON Lines)",
+		"trend_chart":        "Add a chart sheet",
+		"export_excel":       "Export Excel",
+		// Web dashboard login
+		"dashboard_users":             "Dashboard Users",
+		"dashboard_login":             "Dashboard Login",
+		"dashboard_new_user":          "New User",
+		"dashboard_confirm_password":  "Confirm password",
+		"dashboard_password_mismatch": "Passwords do not match",
+		"dashboard_idle_minutes":      "Idle timeout (min)",
+		"dashboard_users_hint":        "With at least one user or an API key the web dashboard (/, /doc) asks for a login and the API, WebSocket and /metrics routes need a dashboard session or the API key (Authorization: Bearer <key>); /api/v1/status stays open. Sessions end after the idle timeout. Passwords are stored as bcrypt hashes, the API key as a SHA-256 hash.",
+		"api_key":                     "API key",
+		"api_key_set":                 "Set",
+		"api_key_none":                "Not set",
+		"api_key_generate":            "Generate",
+		"api_key_shown_once":          "Copy the new API key now; it is not stored and cannot be shown again. Send it as \"Authorization: Bearer <key>\" or in X-API-Key.",
+		// Connect and request retries
+		"retry_policy":        "Retries",
+		"retry_attempts":      "Attempts",
+		"retry_delay_s":       "Delay (s)",
+		"retry_max_elapsed_s": "Give up after (s)",
+		"request_retries":     "Request retries",
+		// Connection diagnostics
+		"connection_diagnostics":      "Connection Diagnostics",
+		"dial_causes":                 "Likely causes",
+		"dial_fixes":                  "Suggested fixes",
+		"dial_client_cert":            "Client certificate",
+		"dial_scheme":                 "The endpoint URL uses a transport that is not supported.",
+		"dial_scheme_causes":          "• The URL starts with https://, opc.https:// or opc.wss://; only opc.tcp:// is implemented\n• Typo in the scheme of the endpoint URL",
+		"dial_scheme_fixes":           "• Use the opc.tcp endpoint of the server (usually port 4840)\n• Use \"Discover\" in the settings to list the opc.tcp endpoints the server offers",
+		"dial_dns":                    "The server host name could not be resolved.",
+		"dial_dns_causes":             "• Typo in the host name of the endpoint URL\n• The host is not in DNS or the hosts file\n• No DNS server reachable from this machine",
+		"dial_dns_fixes":              "• Check the endpoint URL or use the IP address of the server\n• Add the host name to the hosts file\n• Use \"Discover\" in the settings to see the URLs the server announces",
+		"dial_refused":                "The server host refused the TCP connection.",
+		"dial_refused_causes":         "• The OPC UA server is not running\n• Wrong port in the endpoint URL (default 4840)\n• A firewall on the server rejects the port",
+		"dial_refused_fixes":          "• Start the server and check the port it listens on\n• Correct the port in the endpoint URL\n• Open the port in the server firewall",
+		"dial_unreachable":            "The server host cannot be reached or reset the connection.",
+		"dial_unreachable_causes":     "• The host is offline or in another network without a route\n• VPN not connected\n• A firewall or proxy drops the connection",
+		"dial_unreachable_fixes":      "• Check the network connection and VPN\n• Ping the host and check the route\n• Ask the network administrator to allow the port",
+		"dial_timeout":                "The server did not answer in time.",
+		"dial_timeout_causes":         "• A firewall silently drops the packets\n• The server is overloaded or starting up\n• The connect timeout is too short for this network",
+		"dial_timeout_fixes":          "• Check the firewalls between this machine and the server\n• Increase the connect timeout in the settings\n• Try again when the server is idle",
+		"dial_certificate":            "A certificate was rejected.",
+		"dial_certificate_causes":     "• The server has not trusted the client certificate yet\n• The certificate has expired or is not yet valid\n• The ApplicationURI does not match the URI in the certificate",
+		"dial_certificate_fixes":      "• Move the client certificate from the rejected to the trusted folder of the server\n• Generate a new certificate if it has expired\n• Make the ApplicationURI in the settings match the certificate",
+		"dial_security_checks":        "The server rejected the secure channel (BadSecurityChecksFailed).",
+		"dial_security_checks_causes": "• The server has not trusted the client certificate yet\n• The clocks of client and server differ too much\n• The client certificate does not fit the security policy",
+		"dial_security_checks_fixes":  "• Trust the client certificate on the server, then connect again\n• Synchronize the clocks (NTP)\n• Try a different security policy or generate a new certificate",
+		"dial_security_policy":        "The server does not accept the selected security policy or mode.",
+		"dial_security_policy_causes": "• The policy or mode is disabled on the server\n• The server requires signing or encryption",
+		"dial_security_policy_fixes":  "• Use \"Discover\" in the settings and pick an offered endpoint\n• Configure a client certificate for Sign or SignAndEncrypt",
+		"dial_identity":               "The server rejected the user identity.",
+		"dial_identity_causes":        "• Wrong user name or password\n• Anonymous login is disabled on the server\n• The user token policy requires an encrypted channel",
+		"dial_identity_fixes":         "• Check the credentials\n• Switch the authentication mode in the settings\n• Use a Sign or SignAndEncrypt endpoint",
+		"dial_unknown":                "The connection failed.",
+		"dial_unknown_causes":         "• See the error above and the log for details",
+		"dial_unknown_fixes":          "• Use \"Test\" next to the endpoint to check reachability\n• Enable the protocol trace in the settings and connect again",
+		// Client certificate trust flow
+		"trust_wait_btn":     "Send certificate and wait for trust",
+		"trust_wait_title":   "Waiting for Certificate Trust",
+		"trust_wait_hint":    "The client certificate has been presented to the server, which usually files it under rejected certificates. Ask the server operator to trust it (compare the thumbprint). The connection is made as soon as the server accepts it.",
+		"trust_thumbprint":   "Thumbprint (SHA-1)",
+		"trust_resend":       "Send again",
+		"trust_wait_status":  "Check %d: %v",
+		"trust_wait_sending": "Presenting the certificate…",
+		"no_uatcp_endpoints": "The server offers no opc.tcp endpoints; HTTPS and WebSocket transports are not supported.",
+		// Endpoint host override
+		"host_override":             "Host Override",
+		"placeholder_host_override": "IP or host[:port] to dial instead of the endpoint host",
+		"use_advertised_url":        "Keep the advertised endpoint URL and dial the entered host (host override)",
+		// Connection profiles
+		"connection_profile":         "Profile",
+		"profile_export":             "Export",
+		"profile_import":             "Import",
+		"profile_name":               "Name",
+		"profile_include_certs":      "Include client certificate and private key",
+		"profile_export_hint":        "Exports the saved connection settings. Passwords are never exported. The private key travels only in the file, never in the QR code.",
+		"profile_save_file":          "Save File...",
+		"profile_show_qr":            "QR Code",
+		"profile_qr_hint":            "Scan with the phone camera, copy the decoded text and paste it into Settings > Profile > Import on the phone.",
+		"profile_import_placeholder": "opcuababy-profile:... or profile JSON",
+		"profile_import_hint":        "Paste the text of a scanned profile QR code, or open an exported profile file. The connection settings are replaced; a different username needs its password entered again.",
+		"profile_open_file":          "Open File...",
+		"paste":                      "Paste",
+		// Profile sync
+		"profile_saved":        "Saved profiles",
+		"profile_load":         "Load Profile",
+		"profile_load_confirm": "Replace the connection settings with profile \"%s\" (%s)?",
+		"profile_save_as":      "Save as Profile",
+		"sync_btn":             "Sync...",
+		"sync_title":           "Sync Profiles",
+		"sync_url":             "Location",
+		"placeholder_sync_url": "/mnt/share/opcuababy.json, https://dav.example.com/opcuababy.json or s3://bucket/opcuababy.json",
+		"sync_username_hint":   "WebDAV user or S3 access key ID",
+		"sync_s3":              "S3 endpoint / region",
+		"sync_on_start":        "Sync on startup",
+		"sync_hint":            "Saved profiles, the watch list and favorites are shared through a file share, a WebDAV server or an S3 bucket. Passwords and certificates are not synced.",
+		"sync_now":             "Sync Now",
+		"sync_running":         "Syncing...",
+		"sync_never":           "Not synced yet",
+		"sync_last":            "Last synced %s",
+		"sync_conflict":        "Sync Conflict",
+		"sync_conflict_msg":    "Both this device and %s (%s) changed the synced data.\nHere: %d profiles, %d watched nodes, %d favorites\nRemote: %d profiles, %d watched nodes, %d favorites\nMerging keeps entries of both; profiles with the same name keep the local version.",
+		"sync_keep_local":      "Keep Local",
+		"sync_use_remote":      "Use Remote",
+		"sync_merge":           "Merge",
+		// Updates
+		"update_url":             "Update Source",
+		"placeholder_update_url": "Empty: GitHub releases; or URL/path of a release JSON",
+		"update_on_start":        "Check for updates on startup (daily)",
+		"update_check_now":       "Check Now",
+		"update_check":           "Software Update",
+		"update_current":         "You are running %s; the newest release is %s.",
+		"update_available":       "Version %s is available (running %s)",
+		"update_published":       "Released %s",
+		"update_release_page":    "Release Page",
+		"update_skip":            "Skip This Version",
+		"update_download":        "Download %s",
+		"update_staged":          "Downloaded to %s. Close OPC UA Baby and install it from there.",
+		"update_open_folder":     "Open Folder",
+		// About and usage statistics
+		"about":                 "About",
+		"about_version":         "Version",
+		"about_platform":        "Platform",
+		"stats_since":           "Statistics since",
+		"stats_sessions":        "Sessions",
+		"stats_session_time":    "Total session time",
+		"stats_longest_session": "Longest session",
+		"stats_nodes_browsed":   "Nodes browsed",
+		"stats_largest_space":   "Largest address space (nodes)",
+		"stats_max_watched":     "Most watched nodes",
+		"stats_writes":          "Writes",
+		"stats_failed":          "failed",
+		"stats_exports":         "Exports",
+		"stats_hint":            "These statistics are kept on this device only and never sent anywhere. Copy them into an issue report to describe the size of your setup.",
+		"stats_reset":           "Reset Statistics",
+		"stats_reset_confirm":   "Start the usage statistics afresh?",
+		// Value copy
+		"copy_value_json":       "Copy value as JSON",
+		"copy_value_curl_read":  "Copy as curl (read)",
+		"copy_value_curl_write": "Copy as curl (write)",
+		// API snippets
+		"api_snippets":         "API snippets…",
+		"snippet_title":        "API snippets: %s",
+		"snippet_read":         "Read",
+		"snippet_write":        "Write",
+		"snippet_subscribe":    "Subscribe",
+		"snippet_hint":         "Code for the REST and WebSocket API at %s. The write example uses the current value.",
+		"snippet_api_disabled": "The API server is disabled; enable it in Settings.",
+		"snippet_open_page":    "Open in browser",
+		// Watch polling
+		"polled_flag":      "polled",
+		"poll_value":       "Poll instead of monitoring",
+		"poll_interval":    "Poll",
+		"poll_mode":        "Polling",
+		"poll_mode_auto":   "When the server refuses to monitor",
+		"poll_mode_never":  "Never",
+		"poll_mode_always": "Always (all watch items)",
+		// Sampling rate groups
+		"sampling_rate": "Sampling rate",
+		"rate_default":  "Default",
+		"rate_group":    "Rate group",
+		// Raw DataValue popup
+		"raw_value_menu":     "Raw DataValue…",
+		"raw_value_title":    "Raw DataValue: %s",
+		"raw_value_recent":   "Last %d values",
+		"raw_value_none":     "No value received yet.",
+		"raw_value_received": "Received",
+		"raw_value_variant":  "Variant type",
+		"raw_value_flags":    "Status bits",
+		// Offline write queue
+		"write_queue":         "Queue writes while disconnected and replay them on reconnect",
+		"write_queue_title":   "Pending Writes",
+		"write_queue_pending": "Pending writes (%d)",
+		"write_queue_hint":    "These writes were issued while the session was down. They are replayed in order once connected to their server again.",
+		// Transactional bulk writes
+		"bulk_write_transactional": "Transactional (capture previous values)",
+		"bulk_write_summary_tx":    ", %d skipped, %d rolled back",
+		"previous_value":           "Previous",
+		"abort_btn":                "Abort",
+		"rollback_btn":             "Roll back",
+		"confirm_bulk_rollback":    "The batch did not complete. Restore the values already written to their previous values?",
+		// Node notes
+		"node_note":             "Note",
+		"node_note_hint":        "Commissioning observations, e.g. \"sensor scaled wrong, vendor ticket #123\". Leave empty to remove the note.",
+		"profile_include_notes": "Include node notes (%d)",
+		// Server clock check
+		"clock_offset":         "Clock offset",
+		"clock_offset_unknown": "Unknown (CurrentTime not readable)",
+		"clock_offset_fmt":     "%s (round trip %.0f ms, checked %s)",
+		"clock_offset_skewed":  "— more than %v, synchronize the clocks (NTP)",
+		"clock_skew_warn_s":    "Clock skew warning (s)",
+		// Server text locales
+		"server_locales":             "Server text locales",
+		"placeholder_server_locales": "e.g. de-DE, en (empty: server default)",
+		"server_locales_reconnect":   "The locales are sent when the session is activated. Reconnect now to re-read DisplayNames and Descriptions in the chosen locale?",
+		// Reconnect banner
+		"reconnect_now":       "Retry now",
+		"reconnect_stop":      "Stop retrying",
+		"reconnect_lost":      "Connection lost",
+		"reconnect_countdown": "reconnecting in %d s",
+		"reconnecting":        "Reconnecting…",
+		"reconnect_delay_s":   "Reconnect after (s)",
+		// Address space refresh
+		"refresh_branch": "Refresh",
+		"rebrowse_all":   "Rebrowse all",
+		// Model change detection
+		"model_watch_on":  "Watch for changes",
+		"model_watch_off": "Stop watching for changes",
+		// Address space search
+		"search":             "Search",
+		"search_hint":        "Name, pattern with * and ?, or /Objects/2:Plant/Pump path",
+		"search_text":        "Find",
+		"search_mode":        "Mode",
+		"search_start":       "Start node",
+		"search_mode_auto":   "Automatic",
+		"search_mode_path":   "Browse path (server)",
+		"search_mode_query":  "Query service (server)",
+		"search_mode_browse": "Browse (client)",
+		"searching":          "Searching…",
+		"search_progress":    "%d nodes visited, %d found",
+		"search_cancelled":   "Cancelled; %d results so far",
+		"search_truncated":   "%d results (%s); more nodes match, narrow the search",
+		"search_done":        "%d results (%s)",
+		"search_cached":      "(cached)",
+		"search_incomplete":  "Some branches could not be browsed, see the log.",
+		// Write masks
+		"write_mask_unknown":  "Not reported (the server decides on write)",
+		"attr_read_only":      "read-only",
+		"writable_attributes": "Writable",
+		// Value simulation
+		"simulate":       "Simulate…",
+		"simulations":    "Simulations",
+		"sim_start":      "Start",
+		"sim_stop":       "Stop Selected",
+		"sim_stop_all":   "Stop All",
+		"sim_waveform":   "Waveform",
+		"sim_sine":       "Sine",
+		"sim_ramp":       "Ramp",
+		"sim_square":     "Square",
+		"sim_random":     "Random",
+		"sim_min":        "Minimum",
+		"sim_max":        "Maximum",
+		"sim_range":      "Range",
+		"sim_period_s":   "Period (s)",
+		"sim_interval_s": "Write every (s)",
+		"sim_writes":     "Writes",
+		"sim_last_value": "Last Value",
+		// Event history
+		"event_history":     "Event History",
+		"event_notifier":    "Notifier",
+		"event_type":        "Event Type",
+		"event_fields":      "Extra Fields",
+		"event_type_all":    "All events",
+		"event_type_alarms": "Alarms & conditions",
+		// Event filters
+		"event_filter":       "Filter",
+		"event_filters":      "Event Filters",
+		"event_fields_other": "Other Fields",
+		"event_where":        "Conditions (all must match)",
+		"event_where_add":    "Add Condition",
+		"event_where_none":   "No conditions: all events of the type pass.",
+		"event_live":         "Live",
+		"event_live_stop":    "Stop Live",
+		"event_filter_name":  "Filter name",
+		// Server trust list
+		"trust_list_title":          "Server Trust List",
+		"trust_list_show":           "Show…",
+		"trust_list_hint":           "The TrustList of the server default application group (ServerConfiguration). Reading and changing it usually needs an encrypted connection and a user with the SecurityAdmin role.",
+		"trust_list_reading":        "Reading the trust list…",
+		"trust_list_read_failed":    "Cannot read the trust list: %v",
+		"trust_list_count":          "%d entries.",
+		"trust_list_client_trusted": "The client certificate is trusted.",
+		"trust_list_client_missing": "The client certificate is not in the trusted list.",
+		"trust_list_push":           "Add Client Certificate",
+		"trust_list_push_confirm":   "Add the client certificate %s to the trusted certificates of the server (AddCertificate, ApplyChanges)?",
+		"trust_list_list":           "List",
+		"trust_list_kind":           "Kind",
+		"trust_list_subject":        "Subject",
+		"trust_list_expires":        "Expires",
+		"trust_list_trusted":        "Trusted",
+		"trust_list_issuer":         "Issuer",
+		"trust_list_certificate":    "Certificate",
+		"trust_list_crl":            "CRL",
+		// Server actions
+		"server_actions":                "Server Actions",
+		"server_actions_loading":        "Looking for server methods…",
+		"server_actions_hint":           "These methods act on the whole server, e.g. shut it down or reset it. Every call has to be confirmed twice.",
+		"server_actions_none":           "The server exposes no methods on Server, VendorServerInfo or ServerConfiguration.",
+		"server_actions_found":          "%d methods found. Methods with input arguments other than RequestServerStateChange cannot be called here.",
+		"server_action_needs_args":      "needs arguments: %s",
+		"server_action_type_to_confirm": "Type %q to confirm.",
+		"server_action_execute":         "Execute",
+		"server_action_not_confirmed":   "The confirmation did not match; nothing was called.",
+		"server_action_confirm":         "Call %s on %s of the server %s?",
+		"server_action_done":            "%s completed.",
+		"server_state":                  "State",
+		"server_state_restart":          "Restart afterwards",
+		"server_state_delay_s":          "Seconds till shutdown",
+		"server_state_return_s":         "Estimated downtime (s)",
+		"server_state_reason":           "Reason",
+		"server_state_confirm":          "Request state %s from the server %s? Connected clients, including this one, may lose their connection.",
+		"server_state_confirm_restart":  "The server is asked to restart afterwards.",
+		"server_state_accepted":         "The server accepted the state change request.",
+		// Public dashboard
+		"public_dashboard":        "Public Dashboard",
+		"public_dashboard_enable": "Read-only, no login, port:",
+		// Node aliases
+		"node_alias":      "Alias",
+		"node_alias_hint": "Short name for API payloads (?include=meta), e.g. TT-101",
+		// Watch deduplication
+		"suppress_repeats": "Suppress repeated samples",
+		// CSV export options
+		"csv_options":             "CSV",
+		"csv_delimiter_comma":     "Comma (,)",
+		"csv_delimiter_semicolon": "Semicolon (;)",
+		"csv_delimiter_tab":       "Tab",
+		"csv_quote_minimal":       "Quote when needed",
+		"csv_quote_all":           "Quote all fields",
+		"csv_bom":                 "UTF-8 BOM (Excel)",
+		// Full value viewer
+		"value_view_menu":     "Full value…",
+		"value_view_title":    "Value of %s",
+		"value_view_text":     "Text",
+		"value_view_hex":      "Hex",
+		"value_view_size":     "%s, %d characters, %d bytes",
+		"watch_value_max_len": "Watch value length",
+		// ByteString editor
+		"byte_editor_hex":     "Hex",
+		"byte_editor_ascii":   "ASCII",
+		"byte_editor_length":  "%d bytes",
+		"byte_editor_invalid": "Invalid hex: %v",
+		"byte_editor_binary":  "bytes outside printable ASCII are shown as dots; edit them in the hex pane",
+		// Enumeration writes
+		"enum_value_hint": "Pick a value or type its name or number",
+		// OptionSet writes
+		"flags_value_hint": "Value, or bit names joined by |",
+		// Toggle / pulse of Boolean watch rows
+		"toggle_value": "Toggle",
+		"pulse_value":  "Pulse (%s)",
+		"pulse_ms":     "Pulse width (ms)",
 	},
 	"zh": {
 		"endpoint":            "服务端地址",
@@ -312,6 +872,564 @@ var i18n = map[string]map[string]string{
 		"auto_generate_cert":      "自动生成证书",
 		"generate_cert":           "生成证书",
 		"cert_info":               "证书信息",
+		// History dialog
+		"history":              "历史数据...",
+		"history_dialog":       "历史数据",
+		"history_mode":         "模式",
+		"raw":                  "原始值",
+		"start_time":           "开始时间",
+		"end_time":             "结束时间",
+		"processing_interval":  "聚合间隔(秒)",
+		"history_interval_s":   "聚合间隔，单位:秒",
+		"history_truncated":    "%d (已截断：服务器还有更多数据，请缩小时间范围)",
+		"read_btn":             "读取",
+		"close_btn":            "关闭",
+		"edit_history":         "编辑历史...",
+		"history_update_mode":  "操作",
+		"delete_range":         "删除区间",
+		"timestamp":            "时间戳",
+		"value":                "值",
+		"apply_btn":            "应用",
+		"confirm_history_edit": "此操作将永久修改服务器上的历史数据，是否继续？",
+		// Attributes editor
+		"edit_attributes":        "编辑属性...",
+		"attribute":              "属性",
+		"current_value":          "当前值",
+		"new_value":              "新值",
+		"write_btn":              "写入",
+		"placeholder_attr_value": "文本、locale|文本、ns:名称、数字或 true/false",
+		// Timestamps
+		"timestamp_source":     "时间戳来源",
+		"timestamp_timezone":   "时区",
+		"ts_source":            "源时间戳",
+		"ts_server":            "服务器时间戳",
+		"ts_client":            "客户端(接收时间)",
+		"placeholder_timezone": "local、utc 或如 Asia/Shanghai",
+		// OPC UA JSON export
+		"export_ua_json":   "导出 UA JSON",
+		"ua_json_exported": "已导出到 %s",
+		// Bulk write from CSV
+		"bulk_write":         "批量写入",
+		"bulk_write_summary": "共 %d 行：%d 有效，%d 无效，%d 已写入，%d 失败",
+		"execute_btn":        "执行",
+		"export_report":      "导出报告",
+		"confirm_bulk_write": "确定向服务器写入 %d 个值？",
+		// Endpoint test
+		"test_btn":             "测试",
+		"endpoint_reachable":   "服务端可达",
+		"endpoint_unreachable": "服务端不可达",
+		"channel_open_time":    "安全通道建立",
+		"round_trip_time":      "往返时延",
+		"server_application":   "服务端应用",
+		"security_policies":    "安全策略",
+		"user_tokens":          "用户令牌",
+		// Protocol trace
+		"protocol_trace":      "将 OPC UA 服务调用记录到跟踪文件",
+		"protocol_trace_file": "跟踪文件",
+		// Request timeouts
+		"request_timeouts_s": "请求超时（秒）",
+		"timeout_read":       "读取",
+		"timeout_browse":     "浏览",
+		"timeout_write":      "写入",
+		"timeout_publish":    "发布",
+		"timeout_export":     "导出",
+		// Strict AccessLevel mode
+		"strict_access_level": "严格模式：访问级别未知时禁止写入",
+		// Server identity
+		"server_diagnostics": "服务器诊断",
+		"product_name":       "产品名称",
+		"manufacturer":       "制造商",
+		"software_version":   "软件版本",
+		"build_number":       "构建号",
+		"build_date":         "构建日期",
+		"server_start_time":  "启动时间",
+		"server_uptime":      "运行时长",
+		// Offline mode
+		"offline_mode":          "离线模式",
+		"offline_address_space": "地址空间（JSON 导出）",
+		"offline_recording":     "录制数据（JSON Lines，可选）",
+		"offline_banner":        "离线",
+		"start_btn":             "开始",
+		// Update rates
+		"update_rates_ms":  "更新频率（毫秒）",
+		"publish_interval": "发布间隔",
+		"watch_refresh":    "监视刷新",
+		// Node report
+		"copy_as": "复制为…",
+		// System tray
+		"tray_connected":    "已连接",
+		"tray_disconnected": "未连接",
+		"tray_show_window":  "显示窗口",
+		"tray_quit":         "退出",
+		"tray_enabled":      "显示托盘图标（关闭时隐藏窗口）",
+		"start_minimized":   "启动时最小化到托盘",
+		// API lifetime
+		"keep_api_running": "断开连接时保持 API 运行",
+		// NodeSet2
+		"nodeset_compare":      "与在线服务器比较…",
+		"nodeset_diff_summary": "已检查 %d 个基线节点：缺失 %d，多余 %d，不一致 %d",
+		// NodeSet2 offline
+		"nodeset_browse_offline": "离线浏览…",
+		// Scheduled writes
+		"schedule_write":   "定时/斜坡写入…",
+		"schedule_btn":     "计划",
+		"scheduled_writes": "计划写入",
+		"target_value":     "目标值",
+		"start_at":         "开始（秒或 HH:MM）",
+		"ramp_duration_s":  "斜坡时长（秒）",
+		"ramp_steps":       "步数",
+		"cancel_write":     "取消所选",
+		"clear_finished":   "清除已结束",
+		// Golden values
+		"expected_value":    "期望值",
+		"tolerance":         "容差（±）",
+		"golden_empty_hint": "留空则移除期望值",
+		"golden_notify":     "监视值偏离期望值时发送通知",
+		"golden_webhook":    "偏差 Webhook 地址",
+		"golden_deviation":  "数值偏差",
+		// Trigger capture
+		"capture":            "触发捕获",
+		"capture_no_watches": "请先将触发节点加入监视列表。",
+		"trigger_rising":     "上升沿（向上越过阈值）",
+		"trigger_falling":    "下降沿（向下越过阈值）",
+		"trigger_change":     "任意变化",
+		"capture_one_shot":   "单次（首次捕获后解除）",
+		"capture_arm":        "布防",
+		"trigger_condition":  "条件",
+		"trigger_threshold":  "阈值",
+		"capture_pre_s":      "触发前（秒）",
+		"capture_post_s":     "触发后（秒）",
+		"capture_dir":        "输出目录",
+		"capture_armed":      "已布防",
+		"capture_recording":  "记录中",
+		"capture_disarm":     "解除",
+		"capture_written":    "已写入捕获",
+		"capture_last_file":  "最近文件",
+		// Write protection
+		"write_allow":         "写入允许列表",
+		"write_deny":          "写入禁止列表",
+		"write_patterns_hint": "每行一项：NodeID、前缀* 或 re:正则（匹配完整 NodeID）",
+		// Forced values
+		"force_value":        "强制",
+		"forced_flag":        "强制",
+		"force_hint":         "仅在本地显示，不会写入服务器",
+		"release_force":      "释放",
+		"release_all_forces": "全部释放",
+		"server_value":       "服务器值",
+		// Favorites
+		"favorites":       "收藏",
+		"favorites_empty": "在地址空间中右键单击节点并选择“加入收藏”",
+		"connect_first":   "请先连接服务器",
+		"add_favorite":    "加入收藏",
+		"remove_favorite": "取消收藏",
+		// History menu
+		"history_btn":           "历史记录",
+		"recent_nodes":          "最近查看的节点",
+		"write_history":         "写入历史",
+		"repeat_write":          "重复写入",
+		"repeat_write_confirm":  "再次将 %s 写入 %s？",
+		"clear_history":         "清除历史记录",
+		"clear_history_confirm": "清除最近查看的节点和写入历史？",
+		"result":                "结果",
+		// Paste NodeIDs
+		"paste_node_ids":         "粘贴节点ID",
+		"paste_node_ids_hint":    "每行一个节点ID或以逗号分隔，例如 ns=2;s=Tag1 或 nsu=<uri>;i=1001",
+		"paste_node_ids_summary": "有效 %d 个，无效 %d 个，已在监视 %d 个",
+		"add_to_watch_n":         "添加 %d 个到监视",
+		// Queue overflow badge in the Severity column
+		"overflow_badge": "溢出 ×%d",
+		// Subscription tuning and statistics
+		"subscription_params":     "订阅参数",
+		"lifetime_count":          "生命周期计数",
+		"max_keepalive_count":     "最大保活计数",
+		"priority":                "优先级",
+		"subscription_section":    "监视订阅",
+		"subscription_id":         "订阅ID",
+		"monitored_items":         "监视项数量",
+		"notifications_received":  "已接收通知",
+		"data_changes_received":   "已接收数值",
+		"publish_errors":          "发布错误",
+		"last_notification":       "最近通知",
+		"server_publish_requests": "发布请求（服务器）",
+		"server_late_publish":     "延迟发布请求",
+		"server_keepalive_count":  "当前保活计数",
+		"server_lifetime_count":   "当前生命周期计数",
+		"server_unacked":          "未确认消息",
+		"server_discarded":        "丢弃消息",
+		"server_queue_overflows":  "队列溢出",
+		"server_diag_unavailable": "服务器未提供订阅诊断",
+		"no_subscription":         "无活动订阅",
+		"refresh":                 "刷新",
+		// Connection traffic
+		"traffic_section":      "连接流量",
+		"traffic_since":        "统计起始",
+		"traffic_requests":     "请求",
+		"traffic_requests_fmt": "%d（失败 %d），平均 %.1f ms",
+		"traffic_bytes":        "发送 / 接收字节",
+		"traffic_service_fmt":  "%d 次，错误 %d，平均 %.1f ms，最大 %.1f ms",
+		"traffic_bytes_hint":   "字节数为编码后的消息体，不含安全通道的帧头、签名与加密开销。",
+		"not_connected":        "未连接",
+		// UI scale and density
+		"ui_appearance":       "外观",
+		"ui_scale":            "文字大小",
+		"ui_density":          "密度",
+		"density_comfortable": "舒适",
+		"density_compact":     "紧凑",
+		// Number and date format
+		"format_locale":         "数字/日期格式",
+		"format_locale_machine": "机器格式（1234.5，ISO 日期）",
+		// Export templates
+		"export_template":      "列模板",
+		"export_templates":     "导出模板",
+		"template_standard":    "标准",
+		"template_name":        "模板名称",
+		"template_new":         "新建",
+		"template_fields_hint": "每行一列，格式为 \"字段\" 或 \"字段: 表头\"。可用字段：%s",
+		// Export attribute selection
+		"export_attributes": "属性",
+		// Recorded values to Excel
+		"recording_to_excel": "录制数据导出为 Excel…",
+		"recording_file":     "录制文件（JSON Lines）",
+		"trend_chart":        "添加图表工作表",
+		"export_excel":       "导出 Excel",
+		// Web dashboard login
+		"dashboard_users":             "网页仪表板用户",
+		"dashboard_login":             "网页登录",
+		"dashboard_new_user":          "新建用户",
+		"dashboard_confirm_password":  "确认密码",
+		"dashboard_password_mismatch": "两次输入的密码不一致",
+		"dashboard_idle_minutes":      "空闲超时（分钟）",
+		"dashboard_users_hint":        "设置至少一个用户或 API 密钥后，网页仪表板（/、/doc）需要登录，API、WebSocket 和 /metrics 需要仪表板会话或 API 密钥（Authorization: Bearer <密钥>）；/api/v1/status 保持开放。会话在空闲超时后结束。密码以 bcrypt 哈希保存，API 密钥以 SHA-256 哈希保存。",
+		"api_key":                     "API 密钥",
+		"api_key_set":                 "已设置",
+		"api_key_none":                "未设置",
+		"api_key_generate":            "生成",
+		"api_key_shown_once":          "请立即复制新的 API 密钥；它不会被保存，之后无法再次显示。请以 \"Authorization: Bearer <密钥>\" 或 X-API-Key 发送。",
+		// Connect and request retries
+		"retry_policy":        "重试",
+		"retry_attempts":      "次数",
+		"retry_delay_s":       "间隔（秒）",
+		"retry_max_elapsed_s": "放弃时间（秒）",
+		"request_retries":     "请求重试",
+		// Connection diagnostics
+		"connection_diagnostics":      "连接诊断",
+		"dial_causes":                 "可能原因",
+		"dial_fixes":                  "建议措施",
+		"dial_client_cert":            "客户端证书",
+		"dial_scheme":                 "端点 URL 使用了不支持的传输方式。",
+		"dial_scheme_causes":          "• URL 以 https://、opc.https:// 或 opc.wss:// 开头；目前仅支持 opc.tcp://\n• 端点 URL 的协议部分拼写错误",
+		"dial_scheme_fixes":           "• 使用服务器的 opc.tcp 端点（通常为 4840 端口）\n• 在设置中使用“发现端点”列出服务器提供的 opc.tcp 端点",
+		"dial_dns":                    "无法解析服务器主机名。",
+		"dial_dns_causes":             "• 端点 URL 中的主机名拼写错误\n• 主机未在 DNS 或 hosts 文件中登记\n• 本机无法访问 DNS 服务器",
+		"dial_dns_fixes":              "• 检查端点 URL，或改用服务器的 IP 地址\n• 将主机名添加到 hosts 文件\n• 在设置中使用“发现端点”查看服务器公布的 URL",
+		"dial_refused":                "服务器主机拒绝了 TCP 连接。",
+		"dial_refused_causes":         "• OPC UA 服务器未运行\n• 端点 URL 中的端口错误（默认 4840）\n• 服务器上的防火墙拒绝该端口",
+		"dial_refused_fixes":          "• 启动服务器并确认其监听端口\n• 修正端点 URL 中的端口\n• 在服务器防火墙中开放该端口",
+		"dial_unreachable":            "无法访问服务器主机，或连接被重置。",
+		"dial_unreachable_causes":     "• 主机离线，或位于无路由的其他网络\n• 未连接 VPN\n• 防火墙或代理中断了连接",
+		"dial_unreachable_fixes":      "• 检查网络连接和 VPN\n• Ping 主机并检查路由\n• 请网络管理员放行该端口",
+		"dial_timeout":                "服务器未在规定时间内响应。",
+		"dial_timeout_causes":         "• 防火墙静默丢弃了数据包\n• 服务器过载或正在启动\n• 对该网络而言连接超时过短",
+		"dial_timeout_fixes":          "• 检查本机与服务器之间的防火墙\n• 在设置中增大连接超时\n• 待服务器空闲后重试",
+		"dial_certificate":            "证书被拒绝。",
+		"dial_certificate_causes":     "• 服务器尚未信任客户端证书\n• 证书已过期或尚未生效\n• ApplicationURI 与证书中的 URI 不一致",
+		"dial_certificate_fixes":      "• 在服务器上将客户端证书从 rejected 目录移到 trusted 目录\n• 证书过期时重新生成\n• 使设置中的 ApplicationURI 与证书一致",
+		"dial_security_checks":        "服务器拒绝了安全通道（BadSecurityChecksFailed）。",
+		"dial_security_checks_causes": "• 服务器尚未信任客户端证书\n• 客户端与服务器时钟相差过大\n• 客户端证书不满足该安全策略",
+		"dial_security_checks_fixes":  "• 在服务器上信任客户端证书后重新连接\n• 同步时钟（NTP）\n• 尝试其他安全策略或重新生成证书",
+		"dial_security_policy":        "服务器不接受所选的安全策略或模式。",
+		"dial_security_policy_causes": "• 服务器已禁用该策略或模式\n• 服务器要求签名或加密",
+		"dial_security_policy_fixes":  "• 在设置中使用“发现端点”并选择服务器提供的端点\n• 为 Sign 或 SignAndEncrypt 配置客户端证书",
+		"dial_identity":               "服务器拒绝了用户身份。",
+		"dial_identity_causes":        "• 用户名或密码错误\n• 服务器禁用了匿名登录\n• 用户令牌策略要求加密通道",
+		"dial_identity_fixes":         "• 检查凭据\n• 在设置中切换认证方式\n• 使用 Sign 或 SignAndEncrypt 端点",
+		"dial_unknown":                "连接失败。",
+		"dial_unknown_causes":         "• 详见上方错误和日志",
+		"dial_unknown_fixes":          "• 使用端点旁的“测试”检查可达性\n• 在设置中启用协议跟踪后重新连接",
+		// Client certificate trust flow
+		"trust_wait_btn":     "发送证书并等待信任",
+		"trust_wait_title":   "等待证书信任",
+		"trust_wait_hint":    "客户端证书已提交给服务器，服务器通常会将其放入“已拒绝证书”。请服务器管理员信任该证书（核对指纹）。服务器接受后将立即自动连接。",
+		"trust_thumbprint":   "指纹（SHA-1）",
+		"trust_resend":       "重新发送",
+		"trust_wait_status":  "第 %d 次检查：%v",
+		"trust_wait_sending": "正在提交证书…",
+		"no_uatcp_endpoints": "服务器未提供 opc.tcp 端点；不支持 HTTPS 和 WebSocket 传输。",
+		// Endpoint host override
+		"host_override":             "主机覆盖",
+		"placeholder_host_override": "用于替代端点主机的 IP 或主机[:端口]",
+		"use_advertised_url":        "保留服务器公布的端点 URL，并连接所输入的主机（主机覆盖）",
+		// Connection profiles
+		"connection_profile":         "连接配置文件",
+		"profile_export":             "导出",
+		"profile_import":             "导入",
+		"profile_name":               "名称",
+		"profile_include_certs":      "包含客户端证书和私钥",
+		"profile_export_hint":        "导出已保存的连接设置。密码不会被导出。私钥只包含在文件中，不会出现在二维码中。",
+		"profile_save_file":          "保存文件...",
+		"profile_show_qr":            "二维码",
+		"profile_qr_hint":            "用手机相机扫描，复制识别出的文本，然后在手机上的 设置 > 配置文件 > 导入 中粘贴。",
+		"profile_import_placeholder": "opcuababy-profile:... 或配置文件 JSON",
+		"profile_import_hint":        "粘贴扫描得到的配置文件二维码文本，或打开导出的配置文件。连接设置将被替换；用户名变化时需重新输入密码。",
+		"profile_open_file":          "打开文件...",
+		"paste":                      "粘贴",
+		// Profile sync
+		"profile_saved":        "已保存的配置",
+		"profile_load":         "加载配置",
+		"profile_load_confirm": "用配置“%s”（%s）替换当前连接设置？",
+		"profile_save_as":      "另存为配置",
+		"sync_btn":             "同步...",
+		"sync_title":           "同步配置",
+		"sync_url":             "位置",
+		"placeholder_sync_url": "/mnt/share/opcuababy.json、https://dav.example.com/opcuababy.json 或 s3://bucket/opcuababy.json",
+		"sync_username_hint":   "WebDAV 用户或 S3 访问密钥 ID",
+		"sync_s3":              "S3 端点 / 区域",
+		"sync_on_start":        "启动时同步",
+		"sync_hint":            "已保存的配置、监视列表和收藏通过文件共享、WebDAV 服务器或 S3 存储桶共享。密码和证书不会同步。",
+		"sync_now":             "立即同步",
+		"sync_running":         "正在同步...",
+		"sync_never":           "尚未同步",
+		"sync_last":            "上次同步 %s",
+		"sync_conflict":        "同步冲突",
+		"sync_conflict_msg":    "本设备和 %s（%s）都修改了同步数据。\n本地：%d 个配置，%d 个监视节点，%d 个收藏\n远程：%d 个配置，%d 个监视节点，%d 个收藏\n合并会保留双方的条目；同名配置保留本地版本。",
+		"sync_keep_local":      "保留本地",
+		"sync_use_remote":      "使用远程",
+		"sync_merge":           "合并",
+		// Updates
+		"update_url":             "更新源",
+		"placeholder_update_url": "留空：GitHub 发布；或发布 JSON 的 URL/路径",
+		"update_on_start":        "启动时检查更新（每天一次）",
+		"update_check_now":       "立即检查",
+		"update_check":           "软件更新",
+		"update_current":         "当前运行 %s；最新版本为 %s。",
+		"update_available":       "新版本 %s 可用（当前 %s）",
+		"update_published":       "发布于 %s",
+		"update_release_page":    "发布页面",
+		"update_skip":            "跳过此版本",
+		"update_download":        "下载 %s",
+		"update_staged":          "已下载到 %s。请关闭 OPC UA Baby 后从该位置安装。",
+		"update_open_folder":     "打开文件夹",
+		// About and usage statistics
+		"about":                 "关于",
+		"about_version":         "版本",
+		"about_platform":        "平台",
+		"stats_since":           "统计起始",
+		"stats_sessions":        "会话次数",
+		"stats_session_time":    "会话总时长",
+		"stats_longest_session": "最长会话",
+		"stats_nodes_browsed":   "已浏览节点",
+		"stats_largest_space":   "最大地址空间（节点）",
+		"stats_max_watched":     "最多监视节点",
+		"stats_writes":          "写入次数",
+		"stats_failed":          "失败",
+		"stats_exports":         "导出次数",
+		"stats_hint":            "这些统计数据仅保存在本设备上，不会发送到任何地方。提交问题时可复制它们以说明使用规模。",
+		"stats_reset":           "重置统计",
+		"stats_reset_confirm":   "重新开始统计使用数据？",
+		// Value copy
+		"copy_value_json":       "复制值为 JSON",
+		"copy_value_curl_read":  "复制为 curl（读取）",
+		"copy_value_curl_write": "复制为 curl（写入）",
+		// API snippets
+		"api_snippets":         "API 代码片段…",
+		"snippet_title":        "API 代码片段：%s",
+		"snippet_read":         "读取",
+		"snippet_write":        "写入",
+		"snippet_subscribe":    "订阅",
+		"snippet_hint":         "适用于 %s 上 REST 与 WebSocket API 的代码。写入示例使用当前值。",
+		"snippet_api_disabled": "API 服务器未启用，请在设置中启用。",
+		"snippet_open_page":    "在浏览器中打开",
+		// Watch polling
+		"polled_flag":      "轮询",
+		"poll_value":       "轮询代替订阅",
+		"poll_interval":    "轮询",
+		"poll_mode":        "轮询模式",
+		"poll_mode_auto":   "服务器拒绝订阅时轮询",
+		"poll_mode_never":  "从不",
+		"poll_mode_always": "始终轮询（全部监视项）",
+		// Sampling rate groups
+		"sampling_rate": "采样速率",
+		"rate_default":  "默认",
+		"rate_group":    "速率组",
+		// Raw DataValue popup
+		"raw_value_menu":     "原始 DataValue…",
+		"raw_value_title":    "原始 DataValue：%s",
+		"raw_value_recent":   "最近 %d 个值",
+		"raw_value_none":     "尚未收到任何值。",
+		"raw_value_received": "接收时间",
+		"raw_value_variant":  "Variant 类型",
+		"raw_value_flags":    "状态位",
+		// Offline write queue
+		"write_queue":         "断开连接时缓存写入，重连后按顺序重放",
+		"write_queue_title":   "待处理写入",
+		"write_queue_pending": "待处理写入 (%d)",
+		"write_queue_hint":    "这些写入是在会话断开期间发出的，重新连接到对应服务器后将按顺序重放。",
+		// Transactional bulk writes
+		"bulk_write_transactional": "事务模式（先记录原值）",
+		"bulk_write_summary_tx":    "，%d 跳过，%d 已回滚",
+		"previous_value":           "原值",
+		"abort_btn":                "中止",
+		"rollback_btn":             "回滚",
+		"confirm_bulk_rollback":    "批量写入未完成。是否将已写入的值恢复为原值？",
+		// Node notes
+		"node_note":             "备注",
+		"node_note_hint":        "调试记录，例如“传感器量程错误，厂商工单 #123”。留空则删除备注。",
+		"profile_include_notes": "包含节点备注（%d 条）",
+		// Server clock check
+		"clock_offset":         "时钟偏差",
+		"clock_offset_unknown": "未知（无法读取 CurrentTime）",
+		"clock_offset_fmt":     "%s（往返 %.0f ms，检查于 %s）",
+		"clock_offset_skewed":  "— 超过 %v，请同步时钟（NTP）",
+		"clock_skew_warn_s":    "时钟偏差警告 (秒)",
+		// Server text locales
+		"server_locales":             "服务器文本语言",
+		"placeholder_server_locales": "例如 de-DE, en（留空：服务器默认）",
+		"server_locales_reconnect":   "语言设置在会话激活时发送。是否立即重新连接，以所选语言重新读取 DisplayName 和 Description？",
+		// Reconnect banner
+		"reconnect_now":       "立即重试",
+		"reconnect_stop":      "停止重试",
+		"reconnect_lost":      "连接已断开",
+		"reconnect_countdown": "%d 秒后重新连接",
+		"reconnecting":        "正在重新连接…",
+		"reconnect_delay_s":   "重连等待（秒）",
+		// Address space refresh
+		"refresh_branch": "刷新",
+		"rebrowse_all":   "全部重新浏览",
+		// Model change detection
+		"model_watch_on":  "监视结构变化",
+		"model_watch_off": "停止监视结构变化",
+		// Address space search
+		"search":             "搜索",
+		"search_hint":        "名称、含 * 和 ? 的模式，或 /Objects/2:Plant/Pump 路径",
+		"search_text":        "查找",
+		"search_mode":        "模式",
+		"search_start":       "起始节点",
+		"search_mode_auto":   "自动",
+		"search_mode_path":   "浏览路径（服务器）",
+		"search_mode_query":  "查询服务（服务器）",
+		"search_mode_browse": "浏览（客户端）",
+		"searching":          "正在搜索…",
+		"search_progress":    "已访问 %d 个节点，找到 %d 个",
+		"search_cancelled":   "已取消；目前 %d 个结果",
+		"search_truncated":   "%d 个结果（%s）；还有更多匹配，请缩小搜索范围",
+		"search_done":        "%d 个结果（%s）",
+		"search_cached":      "（缓存）",
+		"search_incomplete":  "部分分支无法浏览，请查看日志。",
+		// Write masks
+		"write_mask_unknown":  "未报告（由服务器在写入时决定）",
+		"attr_read_only":      "只读",
+		"writable_attributes": "可写属性",
+		// Value simulation
+		"simulate":       "模拟…",
+		"simulations":    "模拟",
+		"sim_start":      "开始",
+		"sim_stop":       "停止所选",
+		"sim_stop_all":   "全部停止",
+		"sim_waveform":   "波形",
+		"sim_sine":       "正弦",
+		"sim_ramp":       "斜坡",
+		"sim_square":     "方波",
+		"sim_random":     "随机",
+		"sim_min":        "最小值",
+		"sim_max":        "最大值",
+		"sim_range":      "范围",
+		"sim_period_s":   "周期（秒）",
+		"sim_interval_s": "写入间隔（秒）",
+		"sim_writes":     "写入次数",
+		"sim_last_value": "最后值",
+		// Event history
+		"event_history":     "事件历史",
+		"event_notifier":    "事件通知器",
+		"event_type":        "事件类型",
+		"event_fields":      "附加字段",
+		"event_type_all":    "全部事件",
+		"event_type_alarms": "报警与条件",
+		// Event filters
+		"event_filter":       "过滤器",
+		"event_filters":      "事件过滤器",
+		"event_fields_other": "其他字段",
+		"event_where":        "条件（全部满足）",
+		"event_where_add":    "添加条件",
+		"event_where_none":   "无条件：该类型的全部事件都通过。",
+		"event_live":         "实时",
+		"event_live_stop":    "停止实时",
+		"event_filter_name":  "过滤器名称",
+		// Server trust list
+		"trust_list_title":          "服务器信任列表",
+		"trust_list_show":           "查看…",
+		"trust_list_hint":           "服务器默认应用证书组的信任列表（ServerConfiguration）。读取和修改通常需要加密连接以及具有 SecurityAdmin 角色的用户。",
+		"trust_list_reading":        "正在读取信任列表…",
+		"trust_list_read_failed":    "无法读取信任列表：%v",
+		"trust_list_count":          "共 %d 项。",
+		"trust_list_client_trusted": "客户端证书已受信任。",
+		"trust_list_client_missing": "客户端证书不在受信任列表中。",
+		"trust_list_push":           "添加客户端证书",
+		"trust_list_push_confirm":   "将客户端证书 %s 添加到服务器的受信任证书中（AddCertificate、ApplyChanges）？",
+		"trust_list_list":           "列表",
+		"trust_list_kind":           "类型",
+		"trust_list_subject":        "主题",
+		"trust_list_expires":        "到期",
+		"trust_list_trusted":        "受信任",
+		"trust_list_issuer":         "颁发者",
+		"trust_list_certificate":    "证书",
+		"trust_list_crl":            "CRL",
+		// Server actions
+		"server_actions":                "服务器操作",
+		"server_actions_loading":        "正在查找服务器方法…",
+		"server_actions_hint":           "这些方法作用于整个服务器，例如关闭或重置。每次调用都需要确认两次。",
+		"server_actions_none":           "服务器在 Server、VendorServerInfo 或 ServerConfiguration 上未提供方法。",
+		"server_actions_found":          "找到 %d 个方法。除 RequestServerStateChange 外，带输入参数的方法无法在此调用。",
+		"server_action_needs_args":      "需要参数：%s",
+		"server_action_type_to_confirm": "输入 %q 以确认。",
+		"server_action_execute":         "执行",
+		"server_action_not_confirmed":   "确认内容不匹配，未执行任何调用。",
+		"server_action_confirm":         "在服务器 %[3]s 的 %[2]s 上调用 %[1]s？",
+		"server_action_done":            "%s 已完成。",
+		"server_state":                  "状态",
+		"server_state_restart":          "之后重启",
+		"server_state_delay_s":          "距关闭秒数",
+		"server_state_return_s":         "预计停机时间（秒）",
+		"server_state_reason":           "原因",
+		"server_state_confirm":          "请求服务器 %[2]s 进入 %[1]s 状态？已连接的客户端（包括本客户端）可能会断开。",
+		"server_state_confirm_restart":  "之后将请求服务器重启。",
+		"server_state_accepted":         "服务器已接受状态变更请求。",
+		// Public dashboard
+		"public_dashboard":        "公开看板",
+		"public_dashboard_enable": "只读、免登录，端口：",
+		// Node aliases
+		"node_alias":      "别名",
+		"node_alias_hint": "API 数据中使用的简称（?include=meta），例如 TT-101",
+		// Watch deduplication
+		"suppress_repeats": "抑制重复采样",
+		// CSV export options
+		"csv_options":             "CSV",
+		"csv_delimiter_comma":     "逗号 (,)",
+		"csv_delimiter_semicolon": "分号 (;)",
+		"csv_delimiter_tab":       "制表符",
+		"csv_quote_minimal":       "按需加引号",
+		"csv_quote_all":           "所有字段加引号",
+		"csv_bom":                 "UTF-8 BOM（Excel）",
+		// Full value viewer
+		"value_view_menu":     "完整值…",
+		"value_view_title":    "%s 的值",
+		"value_view_text":     "文本",
+		"value_view_hex":      "十六进制",
+		"value_view_size":     "%s，%d 个字符，%d 字节",
+		"watch_value_max_len": "监视值显示长度",
+		// ByteString editor
+		"byte_editor_hex":     "十六进制",
+		"byte_editor_ascii":   "ASCII",
+		"byte_editor_length":  "%d 字节",
+		"byte_editor_invalid": "无效的十六进制：%v",
+		"byte_editor_binary":  "不可打印的字节显示为点，请在十六进制窗格中编辑",
+		// Enumeration writes
+		"enum_value_hint": "选择一个值，或输入其名称或数字",
+		// OptionSet writes
+		"flags_value_hint": "数值，或用 | 连接的位名称",
+		// Toggle / pulse of Boolean watch rows
+		"toggle_value": "取反",
+		"pulse_value":  "脉冲 (%s)",
+		"pulse_ms":     "脉冲宽度 (ms)",
 	},
 }
 
@@ -333,6 +1451,10 @@ func (ui *UI) applyLanguage() {
 		}
 		ui.connectBtn.Refresh()
 	}
+	if ui.testBtn != nil {
+		ui.testBtn.SetText(ui.t("test_btn"))
+		ui.testBtn.Refresh()
+	}
 	if ui.configBtn != nil {
 		ui.configBtn.SetText(ui.t("settings"))
 		ui.configBtn.Refresh()
@@ -341,6 +1463,38 @@ func (ui *UI) applyLanguage() {
 		ui.exportBtn.SetText(ui.t("export"))
 		ui.exportBtn.Refresh()
 	}
+	if ui.offlineBtn != nil {
+		ui.offlineBtn.SetText(ui.t("offline_mode"))
+		ui.offlineBtn.Refresh()
+	}
+	if ui.rebrowseBtn != nil {
+		ui.rebrowseBtn.SetText(ui.t("rebrowse_all"))
+		ui.rebrowseBtn.Refresh()
+	}
+	if ui.searchBtn != nil {
+		ui.searchBtn.SetText(ui.t("search"))
+		ui.searchBtn.Refresh()
+	}
+	if ui.historyBtn != nil {
+		ui.historyBtn.SetText(ui.t("history_btn"))
+		ui.historyBtn.Refresh()
+	}
+	if ui.aboutBtn != nil {
+		ui.aboutBtn.SetText(ui.t("about"))
+		ui.aboutBtn.Refresh()
+	}
+	if ui.copyDetailsBtn != nil {
+		ui.copyDetailsBtn.SetText(ui.t("copy_as"))
+		ui.copyDetailsBtn.Refresh()
+	}
+	if ui.exportReportBtn != nil {
+		ui.exportReportBtn.SetText(ui.t("export_report"))
+		ui.exportReportBtn.Refresh()
+	}
+	if ui.noteBtn != nil {
+		ui.noteBtn.SetText(ui.t("node_note"))
+		ui.noteBtn.Refresh()
+	}
 	if ui.clearAllBtn != nil {
 		ui.clearAllBtn.SetText(ui.t("clear_all"))
 		ui.clearAllBtn.Refresh()
@@ -357,6 +1511,38 @@ func (ui *UI) applyLanguage() {
 		ui.writeWatchBtn.SetText(ui.t("write"))
 		ui.writeWatchBtn.Refresh()
 	}
+	if ui.goldenBtn != nil {
+		ui.goldenBtn.SetText(ui.t("expected_value"))
+		ui.goldenBtn.Refresh()
+	}
+	if ui.forceBtn != nil {
+		ui.forceBtn.SetText(ui.t("force_value"))
+		ui.forceBtn.Refresh()
+	}
+	if ui.leftTabs != nil {
+		ui.leftTabs.Items[0].Text = ui.t("address_space")
+		ui.leftTabs.Items[1].Text = ui.t("favorites")
+		ui.leftTabs.Refresh()
+		ui.favoritesEmpty.SetText(ui.t("favorites_empty"))
+	}
+	if ui.captureBtn != nil {
+		ui.setCaptureStatus(ui.controller.CaptureStatus())
+	}
+	if ui.writeQueueBtn != nil {
+		ui.refreshWriteQueue()
+	}
+	if ui.bulkWriteBtn != nil {
+		ui.bulkWriteBtn.SetText(ui.t("bulk_write"))
+		ui.bulkWriteBtn.Refresh()
+	}
+	if ui.pasteWatchBtn != nil {
+		ui.pasteWatchBtn.SetText(ui.t("paste_node_ids"))
+		ui.pasteWatchBtn.Refresh()
+	}
+	if ui.exportWatchBtn != nil {
+		ui.exportWatchBtn.SetText(ui.t("export_ua_json"))
+		ui.exportWatchBtn.Refresh()
+	}
 	if ui.clearLogBtn != nil {
 		ui.clearLogBtn.SetText(ui.t("clear_logs"))
 		ui.clearLogBtn.Refresh()
@@ -540,12 +1726,41 @@ type UI struct {
 	window     fyne.Window
 	controller *controller.Controller
 
-	endpointEntry  *widget.Entry
-	connectBtn     *widget.Button
-	configBtn      *widget.Button
-	exportBtn      *widget.Button
-	statusIcon     *widget.Icon
+	endpointEntry *widget.Entry
+	connectBtn    *widget.Button
+	configBtn     *widget.Button
+	exportBtn     *widget.Button
+	statusIcon    *widget.Icon
+	testBtn       *widget.Button
+	serverBanner  *widget.Button
+	offlineBtn    *widget.Button
+	rebrowseBtn   *widget.Button
+	searchBtn     *widget.Button
+	nodesetBtn    *widget.Button
+	historyBtn    *widget.Button
+	aboutBtn      *widget.Button
+
+	writeHistoryTable *widget.Table // open Write History table, refreshed on new writes
+
+	// Banner shown while the connection is down after an unexpected loss (see reconnect.go)
+	reconnectBanner  *fyne.Container
+	reconnectMsg     *widget.Label
+	reconnectNowBtn  *widget.Button
+	reconnectStopBtn *widget.Button
+	reconnectReason  string
+	reconnectCancel  func() // stops the countdown
+	reconnectBusy    bool   // a reconnect started from the banner is running
+
+	copyDetailsBtn  *widget.Button
+	exportReportBtn *widget.Button
+	noteBtn         *widget.Button
+
+	trayApp        desktop.App // non-nil when the system tray icon is active
 	apiStatusLabel *widget.Label
+	schedTable     *widget.Table // scheduled writes dialog, nil while closed
+	schedRows      []controller.ScheduledWrite
+	simTable       *widget.Table // simulations dialog, nil while closed
+	simRows        []controller.Simulation
 
 	// Cards to allow retitling on language change
 	connectionCard   *widget.Card
@@ -572,6 +1787,7 @@ type UI struct {
 
 	watchTable             *widget.Table
 	watchRows              []*controller.WatchItem
+	watchRowIndex          map[string]int // NodeID -> index in watchRows
 	watchTableMutex        sync.RWMutex
 	watchTableColumnWidths map[int]float32 // 缓存订阅表列宽状态
 
@@ -581,6 +1797,18 @@ type UI struct {
 	selectedWatchRow int
 	removeWatchBtn   *widget.Button
 	writeWatchBtn    *widget.Button
+	goldenBtn        *widget.Button
+	forceBtn         *widget.Button
+	favoritesList    *widget.List
+	favoritesEmpty   *widget.Label
+	leftTabs         *container.AppTabs
+	captureBtn       *widget.Button
+	writeQueueBtn    *widget.Button // pending writes indicator, hidden while the queue is empty
+	writeQueueList   *widget.List   // write queue dialog, nil while closed
+	writeQueueRows   []controller.QueuedWrite
+	exportWatchBtn   *widget.Button
+	pasteWatchBtn    *widget.Button
+	bulkWriteBtn     *widget.Button
 	watchBtn         *widget.Button
 	writeBtn         *widget.Button
 	clearAllBtn      *widget.Button
@@ -599,8 +1827,6 @@ type UI struct {
 
 func NewUI(c *controller.Controller, apiStatus *string) *UI {
 	a := app.NewWithID("com.giantbaby.opcuababy") // Use App ID for storage
-	// Only change font on iOS; keep all other visuals from the default theme.
-	a.Settings().SetTheme(&fontOnlyTheme{base: theme.DefaultTheme()})
 	w := a.NewWindow("OpcUa Client - Big GiantBaby")
 	w.Resize(fyne.NewSize(1200, 860))
 
@@ -617,7 +1843,7 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 		watchTableColumnWidths: make(map[int]float32),
 		nodeInfoKeys: []string{
 			"NodeID", "NodeClass", "DisplayName",
-			"Description", "DataType", "AccessLevel", "Value",
+			"Description", "DataType", "AccessLevel", "Writable", "Value", "Note",
 		},
 		logBuilder: new(strings.Builder),
 		config: &opc.Config{
@@ -630,6 +1856,7 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 			SessionTimeout:   30,
 			ApiPort:          "8080",
 			ApiEnabled:       true,
+			KeepApiRunning:   true,
 			ConnectTimeout:   5, // Default 5-second timeout
 			Language:         "en",
 			AutoGenerateCert: runtime.GOOS == "ios" || runtime.GOOS == "android", // Enable by default on mobile
@@ -638,6 +1865,9 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 	}
 
 	ui.loadConfig()
+	c.SetUsageStats(ui.config.Usage)
+	// Only change font on iOS, text scale and density; keep all other visuals from the default theme.
+	ui.applyAppearance()
 
 	// Set initial localized API status text
 	ui.initWidgets()
@@ -645,6 +1875,8 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 	ui.initCallbacks()
 	ui.window.SetOnClosed(func() {
 		fmt.Println("Window is closing, initiating graceful shutdown...")
+		// Keep the statistics of the running session
+		ui.saveConfig()
 		// 1. 发起断开连接的请求。这会触发 controller 去关闭 opcua 客户端。
 		//    我们使用 goroutine 是因为它可能是个耗时操作，避免阻塞UI线程。
 		go ui.controller.Disconnect()
@@ -688,6 +1920,10 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 	}()
 	ui.window.SetContent(ui.makeLayout())
 
+	if ui.config.SyncOnStart && ui.config.SyncURL != "" {
+		ui.runSync(profilesync.ResolveAsk, nil)
+	}
+	ui.autoCheckForUpdates()
 	if ui.config.AutoConnect {
 		go func() {
 			time.Sleep(500 * time.Millisecond)
@@ -695,8 +1931,18 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 		}()
 	}
 
+	// Redraw the watch list less often while the window is minimized or in the background
+	ui.app.Lifecycle().SetOnExitedForeground(func() { ui.controller.SetWatchPumpBackoff(true) })
+	ui.app.Lifecycle().SetOnEnteredForeground(func() { ui.controller.SetWatchPumpBackoff(false) })
+
 	// Ensure full cleanup on app close: stop API server, disconnect OPC client, clear state
+	trayActive := ui.setupTray()
 	w.SetCloseIntercept(func() {
+		// With a tray icon the app keeps running in the background; quit from the tray menu
+		if trayActive {
+			w.Hide()
+			return
+		}
 		// Best-effort shutdown before window closes
 		ui.controller.Shutdown()
 		// proceed to close the window/app
@@ -706,6 +1952,10 @@ func NewUI(c *controller.Controller, apiStatus *string) *UI {
 }
 
 func (ui *UI) Run() {
+	if ui.trayApp != nil && ui.config.StartMinimized {
+		ui.app.Run()
+		return
+	}
 	ui.window.ShowAndRun()
 }
 
@@ -732,8 +1982,20 @@ func (ui *UI) initWidgets() {
 	ui.connectBtn = widget.NewButtonWithIcon(ui.t("connect"), theme.LoginIcon(), ui.onConnectClicked)
 	ui.configBtn = widget.NewButtonWithIcon(ui.t("settings"), theme.SettingsIcon(), ui.showConfigDialog)
 	ui.exportBtn = widget.NewButtonWithIcon(ui.t("export"), theme.DownloadIcon(), ui.showExportDialog)
+	ui.offlineBtn = widget.NewButtonWithIcon(ui.t("offline_mode"), theme.MediaReplayIcon(), ui.showOfflineDialog)
+	ui.rebrowseBtn = widget.NewButtonWithIcon(ui.t("rebrowse_all"), theme.ViewRefreshIcon(), func() { ui.refreshBranch("") })
+	ui.rebrowseBtn.Importance = widget.LowImportance
+	ui.searchBtn = widget.NewButtonWithIcon(ui.t("search"), theme.SearchIcon(), ui.showSearchDialog)
+	ui.searchBtn.Importance = widget.LowImportance
+	ui.nodesetBtn = widget.NewButtonWithIcon("NodeSet2", theme.DocumentIcon(), ui.showNodeSetMenu)
+	ui.historyBtn = widget.NewButtonWithIcon(ui.t("history_btn"), theme.HistoryIcon(), ui.showHistoryMenu)
+	ui.aboutBtn = widget.NewButtonWithIcon(ui.t("about"), theme.InfoIcon(), ui.showAboutDialog)
 
 	ui.statusIcon = widget.NewIcon(theme.CancelIcon())
+	ui.testBtn = widget.NewButtonWithIcon(ui.t("test_btn"), theme.MediaPlayIcon(), ui.onTestEndpointClicked)
+	ui.serverBanner = widget.NewButtonWithIcon("", theme.InfoIcon(), ui.showServerDiagnostics)
+	ui.serverBanner.Importance = widget.LowImportance
+	ui.serverBanner.Hide()
 
 	ui.nodeTree = widget.NewTree(
 		ui.treeChildrenCallback,
@@ -753,13 +2015,26 @@ func (ui *UI) initWidgets() {
 		if ui.nodeTree.IsBranch(uid) {
 			ui.nodeTree.ToggleBranch(uid)
 		}
-		go ui.controller.ReadNodeAttributes(string(uid))
+		ui.recordRecentNode(string(uid))
+		go func(nodeID string) {
+			// Keep the Value of a selected variable live while it stays selected
+			attrs, err := ui.controller.ReadNodeAttributes(context.Background(), nodeID)
+			if string(ui.selectedNodeID) != nodeID {
+				return // selection moved on meanwhile
+			}
+			if err == nil && attrs != nil && attrs.NodeClass == "NodeClassVariable" {
+				ui.controller.SetDetailNode(nodeID, attrs.DataType)
+			} else {
+				ui.controller.SetDetailNode("", "")
+			}
+		}(string(uid))
 	}
 	ui.nodeTree.OnUnselected = func(uid widget.TreeNodeID) {
 		//ui.controller.Log(fmt.Sprintf("[blue]Tree OnUnselected: %s[-]", string(uid)))
 		if ui.selectedNodeID == uid {
 			ui.selectedNodeID = ""
 			ui.resetNodeDetails()
+			go ui.controller.SetDetailNode("", "")
 		}
 	}
 
@@ -771,10 +2046,12 @@ func (ui *UI) initWidgets() {
 		func() fyne.CanvasObject {
 			lbl := widget.NewLabel("")
 			lbl.Wrapping = fyne.TextWrapWord
-			return lbl
+			return newTableCell(lbl, ui.showDetailValueCopyMenu)
 		},
 		func(id widget.TableCellID, obj fyne.CanvasObject) {
-			lbl := obj.(*widget.Label)
+			cell := obj.(*tableCell)
+			cell.bind(ui.nodeInfoTable, id)
+			lbl := cell.content.(*widget.Label)
 			key := ui.nodeInfoKeys[id.Row]
 			if id.Col == 0 {
 				lbl.SetText(key)
@@ -801,19 +2078,20 @@ func (ui *UI) initWidgets() {
 		func() (int, int) {
 			ui.watchTableMutex.RLock()
 			defer ui.watchTableMutex.RUnlock()
-			return len(ui.watchRows) + 1, 12
+			return len(ui.watchRows) + 1, watchColumnCount
 		},
 		func() fyne.CanvasObject {
 			lbl := widget.NewLabel("")
 			rect := canvas.NewRectangle(color.Transparent)
-			return container.NewStack(rect, lbl)
+			return newTableCell(container.NewStack(rect, lbl), ui.showWatchValueCopyMenu)
 		},
 		ui.updateWatchTableCell,
 	)
 
 	// 设置默认列宽并缓存
-	defWidths := []float32{150, 150, 100, 150, 110, 110, 150, 80, 120, 130, 80, 120}
+	defWidths := []float32{150, 150, 100, 150, 110, 110, 150, 80, 120, 130, 80, 120, 130}
 	for i, w := range defWidths {
+		w *= ui.textScale()
 		ui.watchTable.SetColumnWidth(i, w)
 		ui.watchTableColumnWidths[i] = w
 	}
@@ -826,28 +2104,47 @@ func (ui *UI) initWidgets() {
 			ui.watchTableMutex.RLock()
 			if row >= 0 && row < len(ui.watchRows) {
 				item := ui.watchRows[row]
+				_, cut := ui.watchValueText(item)
 				ui.watchTableMutex.RUnlock()
-				go ui.openWriteForNode(item.NodeID)
+				if cut {
+					// Values cut off in the table open in full first; the viewer has a write button
+					ui.showFullValueDialog(item.NodeID)
+				} else {
+					go ui.openWriteForNode(item.NodeID)
+				}
 			} else {
 				ui.watchTableMutex.RUnlock()
 			}
 		}
+		// Timestamp and status columns open the received DataValues
+		if id.Row > 0 && id.Col >= 4 && id.Col <= 11 {
+			ui.watchTableMutex.RLock()
+			if row := id.Row - 1; row < len(ui.watchRows) {
+				nodeID := ui.watchRows[row].NodeID
+				defer ui.showRawDataValueDialog(nodeID)
+			}
+			ui.watchTableMutex.RUnlock()
+		}
 
 		if id.Row == 0 {
 			ui.selectedWatchRow = -1
 			ui.removeWatchBtn.Disable()
 			ui.writeWatchBtn.Disable()
+			ui.goldenBtn.Disable()
+			ui.forceBtn.Disable()
 			return
 		}
 		ui.selectedWatchRow = id.Row - 1
 		ui.removeWatchBtn.Enable()
 		ui.writeWatchBtn.Enable()
+		ui.goldenBtn.Enable()
+		ui.forceBtn.Enable()
 		ui.watchTable.Refresh()
 	}
 
 	ui.watchBtn = widget.NewButtonWithIcon(ui.t("add_to_watch"), theme.ContentAddIcon(), func() {
 		if ui.selectedNodeID != "" {
-			ui.controller.AddWatch(string(ui.selectedNodeID))
+			ui.controller.AddWatch(context.Background(), string(ui.selectedNodeID))
 		}
 	})
 
@@ -882,6 +2179,27 @@ func (ui *UI) initWidgets() {
 	})
 	ui.writeWatchBtn.Disable()
 
+	ui.goldenBtn = widget.NewButtonWithIcon(ui.t("expected_value"), theme.ConfirmIcon(), func() {
+		if ui.selectedWatchRow < 0 || ui.selectedWatchRow >= len(ui.watchRows) {
+			return
+		}
+		ui.showGoldenValueDialog(ui.watchRows[ui.selectedWatchRow])
+	})
+	ui.goldenBtn.Disable()
+
+	ui.forceBtn = widget.NewButtonWithIcon(ui.t("force_value"), theme.WarningIcon(), func() {
+		if ui.selectedWatchRow < 0 || ui.selectedWatchRow >= len(ui.watchRows) {
+			return
+		}
+		ui.showForceDialog(ui.watchRows[ui.selectedWatchRow])
+	})
+	ui.forceBtn.Disable()
+
+	ui.captureBtn = widget.NewButtonWithIcon(ui.t("capture"), theme.MediaRecordIcon(), ui.showCaptureDialog)
+	ui.writeQueueBtn = widget.NewButtonWithIcon("", theme.UploadIcon(), ui.showWriteQueue)
+	ui.writeQueueBtn.Importance = widget.WarningImportance
+	ui.writeQueueBtn.Hide()
+
 	ui.logText = widget.NewRichText()
 	ui.logText.Wrapping = fyne.TextWrapOff
 	ui.logText.Segments = []widget.RichTextSegment{&widget.TextSegment{Text: "", Style: widget.RichTextStyleInline}}
@@ -918,26 +2236,84 @@ func (ui *UI) initCallbacks() {
 		}
 	}()
 
+	c.OnWriteDone = func(rec opc.WriteRecord) {
+		fyne.Do(func() { ui.recordWriteResult(rec) })
+	}
+
+	c.OnLinkStateChange = func(st controller.LinkState) {
+		fyne.Do(func() { ui.onLinkStateChange(st) })
+	}
+
 	c.OnConnectionStateChange = func(connected bool, endpoint string, err error) {
 		fyne.Do(func() {
 			// keep internal state in sync so applyLanguage() renders correct button text
 			ui.isConnected = connected
+			// A connect or disconnect not started by the reconnect banner ends it
+			if !ui.reconnectBusy {
+				ui.hideReconnectBanner()
+			}
 			ui.connectBtn.Enable()
 			if connected {
 				ui.connectBtn.SetText(ui.t("disconnect"))
 				ui.connectBtn.SetIcon(theme.LogoutIcon())
 				ui.statusIcon.SetResource(theme.ConfirmIcon())
+				ui.offlineBtn.Disable()
+				if ui.controller.IsOffline() {
+					ui.setOfflineIndicator()
+				}
 				ui.nodeTree.Root = ui.virtualRoot
 				ui.nodeTree.OpenBranch(ui.virtualRoot)
 			} else {
 				ui.connectBtn.SetText(ui.t("connect"))
 				ui.connectBtn.SetIcon(theme.LoginIcon())
 				ui.statusIcon.SetResource(theme.CancelIcon())
+				ui.setServerIdentity(nil)
+				ui.offlineBtn.Enable()
+				ui.bulkWriteBtn.Enable()
 			}
 			ui.statusIcon.Refresh()
+			ui.refreshTray()
 		})
 	}
 
+	c.OnDetailValueUpdate = func(nodeID, value string) {
+		fyne.Do(func() {
+			if string(ui.selectedNodeID) != nodeID || ui.nodeInfoData["NodeID"] != nodeID {
+				return
+			}
+			ui.nodeInfoData["Value"] = value
+			ui.nodeInfoTable.Refresh()
+		})
+	}
+
+	c.OnGoldenDeviation = func(item controller.WatchItem) {
+		if !ui.config.GoldenNotify {
+			return
+		}
+		fyne.CurrentApp().SendNotification(&fyne.Notification{
+			Title:   ui.t("golden_deviation"),
+			Content: fmt.Sprintf("%s = %s (%s %s)", item.NodeID, item.Value, ui.t("expected_value"), controller.FormatGolden(item.Golden)),
+		})
+	}
+
+	c.OnCaptureUpdate = ui.setCaptureStatus
+
+	c.OnScheduledWritesUpdate = func() {
+		fyne.Do(ui.refreshScheduledWrites)
+	}
+
+	c.OnSimulationsUpdate = func() {
+		fyne.Do(ui.refreshSimulations)
+	}
+
+	c.OnWriteQueueUpdate = func() {
+		fyne.Do(ui.refreshWriteQueue)
+	}
+
+	c.OnServerIdentityUpdate = func(si *opc.ServerIdentity) {
+		fyne.Do(func() { ui.setServerIdentity(si) })
+	}
+
 	c.OnAddressSpaceReset = func() {
 		fyne.Do(func() {
 			ui.nodeCacheMutex.Lock()
@@ -951,12 +2327,40 @@ func (ui *UI) initCallbacks() {
 		})
 	}
 
-	c.OnWatchListUpdate = func(items []*controller.WatchItem) {
+	c.OnWatchListUpdate = func(u controller.WatchListUpdate) {
 		fyne.Do(func() {
-			ui.watchTableMutex.Lock()
-			ui.watchRows = items
-			ui.watchTableMutex.Unlock()
-			ui.watchTable.Refresh()
+			if u.Items != nil {
+				index := make(map[string]int, len(u.Items))
+				for i, it := range u.Items {
+					index[it.NodeID] = i
+				}
+				ui.watchTableMutex.Lock()
+				ui.watchRows = u.Items
+				ui.watchRowIndex = index
+				ui.watchTableMutex.Unlock()
+				ui.watchTable.Refresh()
+				// Keep the saved watch list in step, stored by namespace URI
+				if refs := ui.controller.WatchListRefs(); !slices.Equal(refs, ui.config.WatchList) {
+					ui.config.WatchList = refs
+					ui.saveConfig()
+				}
+				return
+			}
+			// Value changes only: redraw the affected value/status cells. Cells that are
+			// scrolled out of view are skipped by the table.
+			ui.watchTableMutex.RLock()
+			rows := make([]int, 0, len(u.Changed))
+			for _, id := range u.Changed {
+				if i, ok := ui.watchRowIndex[id]; ok {
+					rows = append(rows, i)
+				}
+			}
+			ui.watchTableMutex.RUnlock()
+			for _, i := range rows {
+				for col := watchFirstValueColumn; col < watchColumnCount; col++ {
+					ui.watchTable.RefreshItem(widget.TableCellID{Row: i + 1, Col: col})
+				}
+			}
 		})
 	}
 
@@ -974,20 +2378,32 @@ func (ui *UI) initCallbacks() {
 				"Description": attrs.Description,
 				"DataType":    attrs.DataType,
 				"AccessLevel": attrs.AccessLevel,
-				"Value":       attrs.Value,
+				"Writable":    ui.writeMaskText(attrs.WriteMasks),
+				"Value":       controller.FormatEnumValue(attrs.Value, attrs.EnumName+attrs.Flags),
+				"Note":        ui.controller.NodeNote(attrs.NodeID),
+			}
+			// Attributes the server refused to return are shown with their status instead of blank
+			for name, status := range attrs.AttributeStatus {
+				if _, shown := ui.nodeInfoData[name]; shown {
+					ui.nodeInfoData[name] = controller.UnavailableText(status)
+				}
 			}
 			ui.nodeInfoTable.Refresh()
 			// 属性内容可能变化，更新列宽（左列适配名称，右列适配值或占满剩余宽度）
 			ui.updateDetailsColumnWidths()
 
 			if strings.Contains(attrs.NodeClass, "Variable") {
-				// AccessLevel may be empty on some servers; treat empty as permissive
-				if attrs.AccessLevel == "" || strings.Contains(attrs.AccessLevel, "Read") {
+				// AccessLevel may be unavailable on some servers; treat unknown as permissive
+				// unless strict mode asks to refuse writes in that case
+				if !attrs.AccessLevelKnown || strings.Contains(attrs.AccessLevel, "Read") {
 					ui.watchBtn.Enable()
 				} else {
 					ui.watchBtn.Disable()
 				}
-				if attrs.AccessLevel == "" || strings.Contains(attrs.AccessLevel, "Write") {
+				strict := ui.config != nil && ui.config.StrictAccessLevel
+				if ui.controller.IsOffline() {
+					ui.writeBtn.Disable()
+				} else if (!attrs.AccessLevelKnown && !strict) || strings.Contains(attrs.AccessLevel, "Write") {
 					ui.writeBtn.Enable()
 				} else {
 					ui.writeBtn.Disable()
@@ -1025,6 +2441,10 @@ func (ui *UI) onConnectClicked() {
 
 	// Normalize endpoint before connecting to ensure scheme/port are in place
 	if ui.config != nil {
+		if user, password, ok := opc.EndpointCredentials(ui.config.EndpointURL); ok {
+			ui.config.Username, ui.config.Password, ui.config.AuthMode = user, password, "Username"
+			ui.controller.Log(fmt.Sprintf("[yellow]Using the credentials of the endpoint URL for the username login of '%s'[-]", user))
+		}
 		norm := normalizeEndpoint(strings.TrimSpace(ui.config.EndpointURL))
 		ui.config.EndpointURL = norm
 		if ui.endpointEntry != nil {
@@ -1041,11 +2461,16 @@ func (ui *UI) onConnectClicked() {
 		// Certificate handling is now done in config.ToOpcuaOptions()
 		// No need to call EnsureCertificates here as it's handled automatically
 
+		endpoint := ui.config.EndpointURL
 		err := ui.controller.Connect(ui.config)
 		fyne.Do(func() {
 			ui.connectBtn.Enable()
 			if err != nil {
 				ui.connectBtn.SetText(ui.t("connect"))
+				// A Disconnect while connecting is not a failure worth explaining
+				if !errors.Is(err, context.Canceled) {
+					ui.showDialDiagnostics(endpoint, err)
+				}
 			}
 			ui.connectBtn.Refresh()
 		})
@@ -1056,7 +2481,7 @@ func (ui *UI) openWriteForNode(nodeID string) {
 	// 在后台线程执行网络/读取操作，然后在 UI 线程弹窗，避免跨线程操作 UI 导致崩溃
 	go func() {
 		// 优先刷新服务器端 DataType
-		if a, err := ui.controller.ReadNodeAttributes(nodeID); err == nil && a != nil && a.DataType != "" {
+		if a, err := ui.controller.ReadNodeAttributes(context.Background(), nodeID); err == nil && a != nil && a.DataType != "" {
 			dt := a.DataType
 			fyne.Do(func() {
 				ui.showWriteDialog(nodeID, dt)
@@ -1083,22 +2508,85 @@ func (ui *UI) openWriteForNode(nodeID string) {
 }
 
 func (ui *UI) showWriteDialog(nodeID, dataType string) {
+	if err := ui.controller.CheckWriteAllowed(nodeID); err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
 	valueEntry := widget.NewEntry()
-	dialog.ShowForm("Write Value to "+nodeID, "Write", "Cancel",
+	var valueInput fyne.CanvasObject = valueEntry
+	value := func() (string, error) { return valueEntry.Text, nil }
+	if enum := ui.controller.EnumType(context.Background(), dataType); enum != nil {
+		// Enumerations offer their values; names and numbers may also be typed
+		options := make([]string, 0, len(enum.Values))
+		for _, ev := range enum.Values {
+			options = append(options, controller.FormatEnumValue(strconv.FormatInt(ev.Value, 10), ev.Name))
+		}
+		enumEntry := widget.NewSelectEntry(options)
+		enumEntry.SetPlaceHolder(ui.t("enum_value_hint"))
+		valueEntry, valueInput = &enumEntry.Entry, enumEntry
+	} else if opts := ui.controller.OptionSetType(context.Background(), dataType); opts != nil {
+		// OptionSets are edited bit by bit
+		flags := ui.newFlagsEditor(opts, ui.watchValueMask(nodeID, opts))
+		valueInput, value = flags.content(), flags.value
+	}
+	// ByteStrings are edited as bytes, so "4142" cannot be taken for text or the other way round
+	var bytesEditor *byteEditor
+	if strings.EqualFold(dataType, "ByteString") {
+		bytesEditor = ui.newByteEditor(ui.watchValueBytes(nodeID))
+		valueInput, value = bytesEditor.content(), bytesEditor.value
+	}
+	var dlg dialog.Dialog
+	scheduleBtn := widget.NewButtonWithIcon(ui.t("schedule_write"), theme.HistoryIcon(), func() {
+		v, err := value()
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		dlg.Hide()
+		ui.showScheduleWriteDialog(nodeID, dataType, v)
+	})
+	scheduledBtn := widget.NewButton(ui.t("scheduled_writes"), func() {
+		dlg.Hide()
+		ui.showScheduledWrites()
+	})
+	simulateBtn := widget.NewButtonWithIcon(ui.t("simulate"), theme.MediaPlayIcon(), func() {
+		dlg.Hide()
+		ui.showSimulationDialog(nodeID, dataType)
+	})
+	simulationsBtn := widget.NewButton(ui.t("simulations"), func() {
+		dlg.Hide()
+		ui.showSimulations()
+	})
+	dlg = dialog.NewForm("Write Value to "+nodeID, "Write", "Cancel",
 		[]*widget.FormItem{
 			widget.NewFormItem("Data Type", widget.NewLabel(dataType)),
-			widget.NewFormItem("New Value", valueEntry),
+			widget.NewFormItem("New Value", valueInput),
+			widget.NewFormItem("", container.NewHBox(scheduleBtn, scheduledBtn)),
+			widget.NewFormItem("", container.NewHBox(simulateBtn, simulationsBtn)),
 		},
 		func(ok bool) {
-			if ok {
-				go ui.controller.WriteValue(nodeID, dataType, valueEntry.Text)
+			if !ok {
+				return
+			}
+			v, err := value()
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
 			}
+			go ui.controller.WriteValue(context.Background(), nodeID, dataType, v)
 		}, ui.window)
+	if bytesEditor != nil {
+		dlg.Resize(fyne.NewSize(820, 480))
+	}
+	dlg.Show()
 }
 
 func (ui *UI) showConfigDialog() {
 	endpointEntry := widget.NewEntry()
 	endpointEntry.SetText(ui.config.EndpointURL)
+	hostOverrideEntry := widget.NewEntry()
+	hostOverrideEntry.SetPlaceHolder(ui.t("placeholder_host_override"))
+	hostOverrideEntry.SetText(ui.config.HostOverride)
 
 	appURIEntry := widget.NewEntry()
 	appURIEntry.SetPlaceHolder(ui.t("placeholder_app_uri"))
@@ -1364,9 +2852,20 @@ func (ui *UI) showConfigDialog() {
 
 	apiEnabledCheck := widget.NewCheck(ui.t("enable_api"), nil)
 	apiEnabledCheck.SetChecked(ui.config.ApiEnabled)
+	keepApiCheck := widget.NewCheck(ui.t("keep_api_running"), nil)
+	keepApiCheck.SetChecked(ui.config.KeepApiRunning)
+	publicDashboardCheck := widget.NewCheck(ui.t("public_dashboard_enable"), nil)
+	publicDashboardCheck.SetChecked(ui.config.PublicDashboard)
+	publicDashboardPortEntry := widget.NewEntry()
+	publicDashboardPortEntry.SetPlaceHolder(opc.DefaultPublicDashboardPort)
+	publicDashboardPortEntry.SetText(ui.config.PublicDashboardPort)
 
 	autoConnectCheck := widget.NewCheck(ui.t("auto_connect"), nil)
 	autoConnectCheck.SetChecked(ui.config.AutoConnect)
+	trayCheck := widget.NewCheck(ui.t("tray_enabled"), nil)
+	trayCheck.SetChecked(ui.config.TrayEnabled)
+	startMinimizedCheck := widget.NewCheck(ui.t("start_minimized"), nil)
+	startMinimizedCheck.SetChecked(ui.config.StartMinimized)
 
 	disableLogCheck := widget.NewCheck(ui.t("disable_logs"), nil)
 	disableLogCheck.SetChecked(ui.config.DisableLog)
@@ -1383,10 +2882,156 @@ func (ui *UI) showConfigDialog() {
 	languageSelect := widget.NewSelect(langNames, nil)
 	languageSelect.SetSelected(selectedLangName)
 
+	scaleNames := make([]string, len(uiScales))
+	for i, sc := range uiScales {
+		scaleNames[i] = scaleLabel(sc)
+	}
+	scaleSelect := widget.NewSelect(scaleNames, nil)
+	scaleSelect.SetSelected(scaleLabel(float64(ui.textScale())))
+	densityDisplayToValue := map[string]string{
+		ui.t("density_comfortable"): "comfortable",
+		ui.t("density_compact"):     "compact",
+	}
+	densitySelect := widget.NewSelect([]string{ui.t("density_comfortable"), ui.t("density_compact")}, nil)
+	densitySelect.SetSelected(ui.t("density_comfortable"))
+	if ui.config.UIDensity == "compact" {
+		densitySelect.SetSelected(ui.t("density_compact"))
+	}
+
+	traceCheck := widget.NewCheck(ui.t("protocol_trace"), nil)
+	traceCheck.SetChecked(ui.config.ProtocolTrace)
+	traceFileEntry := widget.NewEntry()
+	traceFileEntry.SetPlaceHolder(controller.ProtocolTracePath(nil))
+	traceFileEntry.SetText(ui.config.ProtocolTraceFile)
+
+	strictAccessCheck := widget.NewCheck(ui.t("strict_access_level"), nil)
+	strictAccessCheck.SetChecked(ui.config.StrictAccessLevel)
+	writeQueueCheck := widget.NewCheck(ui.t("write_queue"), nil)
+	writeQueueCheck.SetChecked(ui.config.WriteQueue)
+
+	goldenNotifyCheck := widget.NewCheck(ui.t("golden_notify"), nil)
+	goldenNotifyCheck.SetChecked(ui.config.GoldenNotify)
+	updateURLEntry := widget.NewEntry()
+	updateURLEntry.SetPlaceHolder(ui.t("placeholder_update_url"))
+	updateURLEntry.SetText(ui.config.UpdateURL)
+	updateOnStartCheck := widget.NewCheck(ui.t("update_on_start"), nil)
+	updateOnStartCheck.SetChecked(ui.config.UpdateCheckOnStart)
+	updateCheckBtn := widget.NewButtonWithIcon(ui.t("update_check_now"), theme.ViewRefreshIcon(), func() {
+		ui.checkForUpdates(updateURLEntry.Text, true)
+	})
+	goldenWebhookEntry := widget.NewEntry()
+	goldenWebhookEntry.SetPlaceHolder("https://example.com/hook")
+	goldenWebhookEntry.SetText(ui.config.GoldenWebhookURL)
+	writeAllowEntry := widget.NewMultiLineEntry()
+	writeAllowEntry.SetPlaceHolder(ui.t("write_patterns_hint"))
+	writeAllowEntry.SetText(strings.Join(ui.config.WriteAllow, "\n"))
+	writeAllowEntry.SetMinRowsVisible(2)
+	writeDenyEntry := widget.NewMultiLineEntry()
+	writeDenyEntry.SetPlaceHolder(ui.t("write_patterns_hint"))
+	writeDenyEntry.SetText(strings.Join(ui.config.WriteDeny, "\n"))
+	writeDenyEntry.SetMinRowsVisible(2)
+
+	tsSourceDisplayToValue := map[string]string{
+		ui.t("ts_source"): "source",
+		ui.t("ts_server"): "server",
+		ui.t("ts_client"): "client",
+	}
+	tsSourceSelect := widget.NewSelect([]string{ui.t("ts_source"), ui.t("ts_server"), ui.t("ts_client")}, nil)
+	tsSourceSelect.SetSelected(ui.t("ts_source"))
+	for disp, v := range tsSourceDisplayToValue {
+		if strings.EqualFold(ui.config.TimestampSource, v) {
+			tsSourceSelect.SetSelected(disp)
+		}
+	}
+	formatLocaleNames := []string{ui.t("format_locale_machine")}
+	for _, l := range opc.DisplayLocales {
+		formatLocaleNames = append(formatLocaleNames, l.Tag)
+	}
+	formatLocaleSelect := widget.NewSelect(formatLocaleNames, nil)
+	formatLocaleSelect.SetSelected(formatLocaleNames[0])
+	if l := ui.config.DisplayLocale(); l.Tag != "" {
+		formatLocaleSelect.SetSelected(l.Tag)
+	}
+	serverLocaleEntry := widget.NewEntry()
+	serverLocaleEntry.SetPlaceHolder(ui.t("placeholder_server_locales"))
+	serverLocaleEntry.SetText(strings.Join(ui.config.LocaleIDs, ", "))
+	tsZoneEntry := widget.NewEntry()
+	tsZoneEntry.SetPlaceHolder(ui.t("placeholder_timezone"))
+	tsZoneEntry.SetText(ui.config.TimestampTimezone)
+	clockSkewEntry := widget.NewEntry()
+	clockSkewEntry.SetPlaceHolder(strconv.FormatFloat(opc.DefaultClockSkewWarn.Seconds(), 'f', -1, 64))
+	if ui.config.ClockSkewWarnSeconds > 0 {
+		clockSkewEntry.SetText(strconv.FormatFloat(ui.config.ClockSkewWarnSeconds, 'f', -1, 64))
+	}
+
 	timeoutEntry := widget.NewEntry()
 	timeoutEntry.SetPlaceHolder(ui.t("placeholder_timeout_s"))
 	timeoutEntry.SetText(fmt.Sprintf("%.1f", ui.config.ConnectTimeout))
 
+	// Per-operation request timeouts (seconds); empty restores the default
+	effective := ui.config.Timeouts()
+	newTimeoutEntry := func(d time.Duration) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(ui.t("placeholder_timeout_s"))
+		e.SetText(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		return e
+	}
+	readTimeoutEntry := newTimeoutEntry(effective.Read)
+	browseTimeoutEntry := newTimeoutEntry(effective.Browse)
+	writeTimeoutEntry := newTimeoutEntry(effective.Write)
+	publishTimeoutEntry := newTimeoutEntry(effective.Publish)
+	exportTimeoutEntry := newTimeoutEntry(effective.Export)
+
+	// Connect retry policy and retries of transient request failures; empty uses the defaults
+	retryPolicy := ui.config.ConnectRetryPolicy()
+	retryAttemptsEntry := widget.NewEntry()
+	retryAttemptsEntry.SetText(strconv.Itoa(retryPolicy.Attempts))
+	retryDelayEntry := widget.NewEntry()
+	retryDelayEntry.SetText(strconv.FormatFloat(retryPolicy.Delay.Seconds(), 'f', -1, 64))
+	retryMaxElapsedEntry := widget.NewEntry()
+	retryMaxElapsedEntry.SetPlaceHolder("∞")
+	if retryPolicy.MaxElapsed > 0 {
+		retryMaxElapsedEntry.SetText(strconv.FormatFloat(retryPolicy.MaxElapsed.Seconds(), 'f', -1, 64))
+	}
+	requestRetriesEntry := widget.NewEntry()
+	requestRetriesEntry.SetPlaceHolder("1")
+	if ui.config.RequestRetryAttempts > 1 {
+		requestRetriesEntry.SetText(strconv.Itoa(ui.config.RequestRetryAttempts))
+	}
+	reconnectDelayEntry := widget.NewEntry()
+	reconnectDelayEntry.SetText(strconv.FormatFloat(ui.config.ReconnectDelay().Seconds(), 'f', -1, 64))
+
+	// Subscription publishing interval and watch list redraw rate (milliseconds)
+	intervals := ui.config.Intervals()
+	newIntervalEntry := func(d time.Duration) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64))
+		return e
+	}
+	publishIntervalEntry := newIntervalEntry(intervals.Publish)
+	pumpIntervalEntry := newIntervalEntry(intervals.WatchPump)
+	pollIntervalEntry := newIntervalEntry(intervals.Poll)
+	pulseEntry := newIntervalEntry(ui.config.PulseDuration())
+	valueMaxLenEntry := widget.NewEntry()
+	valueMaxLenEntry.SetText(strconv.Itoa(ui.config.WatchValueLimit()))
+	pollModes := []string{opc.PollAuto, opc.PollNever, opc.PollAlways}
+	pollModeNames := []string{ui.t("poll_mode_auto"), ui.t("poll_mode_never"), ui.t("poll_mode_always")}
+	pollModeSelect := widget.NewSelect(pollModeNames, nil)
+	pollModeSelect.SetSelectedIndex(max(slices.Index(pollModes, ui.config.PollMode), 0))
+
+	// Subscription lifetime/keep-alive counts and priority; empty uses the defaults
+	newCountEntry := func(v uint32, placeholder string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(placeholder)
+		if v > 0 {
+			e.SetText(strconv.FormatUint(uint64(v), 10))
+		}
+		return e
+	}
+	lifetimeCountEntry := newCountEntry(ui.config.SubscriptionLifetimeCount, "10000")
+	keepAliveCountEntry := newCountEntry(ui.config.SubscriptionMaxKeepAliveCount, "3000")
+	priorityEntry := newCountEntry(uint32(ui.config.SubscriptionPriority), "0")
+
 	// Discover Endpoints button and logic
 	discoverBtn := widget.NewButton(ui.t("discover_endpoints"), func() {
 		// Determine timeout from field or fallback
@@ -1430,7 +3075,7 @@ func (ui *UI) showConfigDialog() {
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(to*float64(time.Second)))
 			defer cancel()
-			eps, err := opcua.GetEndpoints(ctx, addr)
+			eps, err := opcua.GetEndpoints(ctx, opc.OverrideHost(addr, hostOverrideEntry.Text))
 			fyne.Do(func() { prog.Hide() })
 			if err != nil {
 				fyne.Do(func() { dialog.ShowError(err, ui.window) })
@@ -1452,6 +3097,10 @@ func (ui *UI) showConfigDialog() {
 			}
 			rows := make([]row, 0, len(eps))
 			for _, ep := range eps {
+				// HTTPS/WebSocket endpoints of the same server cannot be used by the stack
+				if !opc.IsSupportedEndpoint(ep) {
+					continue
+				}
 				pol := toPolicy(ep.SecurityPolicyURI)
 				md := toMode(ep.SecurityMode)
 				// Determine supported user token types (limit to Anonymous/UserName for UI)
@@ -1482,6 +3131,10 @@ func (ui *UI) showConfigDialog() {
 				disp := fmt.Sprintf("%s\n%s | %s%s", ep.EndpointURL, pol, md, extra)
 				rows = append(rows, row{display: disp, url: ep.EndpointURL, policy: pol, mode: md, supportsAnon: supAnon, supportsUsername: supUser})
 			}
+			if len(rows) == 0 {
+				fyne.Do(func() { dialog.ShowInformation(ui.t("discover_endpoints"), ui.t("no_uatcp_endpoints"), ui.window) })
+				return
+			}
 
 			fyne.Do(func() {
 				list := widget.NewList(
@@ -1490,13 +3143,31 @@ func (ui *UI) showConfigDialog() {
 					func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(rows[i].display) },
 				)
 				var picker *dialog.CustomDialog
+				// Servers often advertise internal host names; optionally keep their URL and dial the entered host
+				enteredHost := ""
+				if u, err := url.Parse(opc.OverrideHost(addr, hostOverrideEntry.Text)); err == nil {
+					enteredHost = u.Host
+				}
+				advertisedCheck := widget.NewCheck(ui.t("use_advertised_url"), nil)
+				advertisedCheck.Hide()
+				for _, r := range rows {
+					if u, err := url.Parse(r.url); err == nil && !strings.EqualFold(u.Host, enteredHost) {
+						advertisedCheck.Show()
+						break
+					}
+				}
 				list.OnSelected = func(id widget.ListItemID) {
 					if id < 0 || id >= len(rows) {
 						return
 					}
 					sel := rows[id]
-					// Do not overwrite the endpoint with the server-advertised URL; keep the user-entered address
-					endpointEntry.SetText(addr)
+					if u, err := url.Parse(sel.url); advertisedCheck.Checked && err == nil && !strings.EqualFold(u.Host, enteredHost) {
+						endpointEntry.SetText(sel.url)
+						hostOverrideEntry.SetText(enteredHost)
+					} else {
+						// Do not overwrite the endpoint with the server-advertised URL; keep the user-entered address
+						endpointEntry.SetText(addr)
+					}
 					// Apply policy/mode if they are among our options
 					policySelect.SetSelected(sel.policy)
 					modeSelect.SetSelected(sel.mode)
@@ -1534,8 +3205,9 @@ func (ui *UI) showConfigDialog() {
 						picker.Hide()
 					}
 				}
-				content := container.NewVScroll(list)
-				content.SetMinSize(fyne.NewSize(480, 300))
+				scroll := container.NewVScroll(list)
+				scroll.SetMinSize(fyne.NewSize(480, 300))
+				content := container.NewBorder(nil, advertisedCheck, nil, nil, scroll)
 				// Wrap content with a bordered themed panel to give the popup a border
 				borderBg := NewThemedArea(ui.app, func() color.Color { return theme.Color(theme.ColorNameBackground) }, 1, appleCornerRadius)
 				bordered := container.NewMax(borderBg, container.NewPadded(content))
@@ -1547,12 +3219,58 @@ func (ui *UI) showConfigDialog() {
 
 	endpointRow := container.NewBorder(nil, nil, nil, discoverBtn, endpointEntry)
 
+	var settingsDlg *dialog.CustomDialog
+	// Reopen the settings so they show imported or loaded values
+	reopenSettings := func() {
+		if settingsDlg != nil {
+			settingsDlg.Hide()
+		}
+		ui.showConfigDialog()
+	}
+	profileRow := container.NewHBox(
+		ui.makeSavedProfilesRow(reopenSettings),
+		widget.NewButtonWithIcon(ui.t("profile_export"), theme.UploadIcon(), ui.showProfileExportDialog),
+		widget.NewButtonWithIcon(ui.t("profile_import"), theme.DownloadIcon(), func() {
+			ui.showProfileImportDialog(reopenSettings)
+		}),
+		widget.NewButtonWithIcon(ui.t("sync_btn"), theme.ViewRefreshIcon(), ui.showSyncDialog),
+	)
+
 	formItems := []*widget.FormItem{
 		widget.NewFormItem(ui.t("endpoint_url"), endpointRow),
+		widget.NewFormItem(ui.t("connection_profile"), profileRow),
+		widget.NewFormItem(ui.t("host_override"), hostOverrideEntry),
 		widget.NewFormItem(ui.t("application_uri"), appURIEntry),
 		widget.NewFormItem(ui.t("product_uri"), productURIEntry),
 		widget.NewFormItem(ui.t("session_timeout_s"), sessionTimeoutEntry),
 		widget.NewFormItem(ui.t("connect_timeout_s"), timeoutEntry),
+		widget.NewFormItem(ui.t("request_timeouts_s"), container.NewGridWithColumns(5,
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("timeout_read")), nil, readTimeoutEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("timeout_browse")), nil, browseTimeoutEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("timeout_write")), nil, writeTimeoutEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("timeout_publish")), nil, publishTimeoutEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("timeout_export")), nil, exportTimeoutEntry),
+		)),
+		widget.NewFormItem(ui.t("retry_policy"), container.NewGridWithColumns(5,
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("retry_attempts")), nil, retryAttemptsEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("retry_delay_s")), nil, retryDelayEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("retry_max_elapsed_s")), nil, retryMaxElapsedEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("request_retries")), nil, requestRetriesEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("reconnect_delay_s")), nil, reconnectDelayEntry),
+		)),
+		widget.NewFormItem(ui.t("update_rates_ms"), container.NewGridWithColumns(3,
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("publish_interval")), nil, publishIntervalEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("watch_refresh")), nil, pumpIntervalEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("poll_interval")), nil, pollIntervalEntry),
+		)),
+		widget.NewFormItem(ui.t("poll_mode"), pollModeSelect),
+		widget.NewFormItem(ui.t("watch_value_max_len"), valueMaxLenEntry),
+		widget.NewFormItem(ui.t("pulse_ms"), pulseEntry),
+		widget.NewFormItem(ui.t("subscription_params"), container.NewGridWithColumns(3,
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("lifetime_count")), nil, lifetimeCountEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("max_keepalive_count")), nil, keepAliveCountEntry),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("priority")), nil, priorityEntry),
+		)),
 		widget.NewFormItem(ui.t("security_policy"), policySelect),
 		widget.NewFormItem(ui.t("security_mode"), modeSelect),
 		// Place certificate/key next to security settings
@@ -1562,10 +3280,32 @@ func (ui *UI) showConfigDialog() {
 		widget.NewFormItem(ui.t("authentication"), authModeRadio),
 		widget.NewFormItem("", credHolder),
 		widget.NewFormItem(ui.t("api_port"), apiPortEntry),
-		widget.NewFormItem("", apiEnabledCheck),
+		widget.NewFormItem("", container.NewHBox(apiEnabledCheck, keepApiCheck)),
+		widget.NewFormItem(ui.t("dashboard_login"), container.NewHBox(widget.NewButtonWithIcon(ui.t("dashboard_users"), theme.AccountIcon(), ui.showDashboardUsersDialog))),
+		widget.NewFormItem(ui.t("public_dashboard"), container.NewBorder(nil, nil, publicDashboardCheck, nil, publicDashboardPortEntry)),
 		widget.NewFormItem("", disableLogCheck),
 		widget.NewFormItem("", autoConnectCheck),
+		widget.NewFormItem("", container.NewHBox(trayCheck, startMinimizedCheck)),
 		widget.NewFormItem(ui.t("language"), languageSelect),
+		widget.NewFormItem(ui.t("ui_appearance"), container.NewGridWithColumns(2,
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("ui_scale")), nil, scaleSelect),
+			container.NewBorder(nil, nil, widget.NewLabel(ui.t("ui_density")), nil, densitySelect),
+		)),
+		widget.NewFormItem(ui.t("timestamp_source"), tsSourceSelect),
+		widget.NewFormItem(ui.t("timestamp_timezone"), tsZoneEntry),
+		widget.NewFormItem(ui.t("clock_skew_warn_s"), clockSkewEntry),
+		widget.NewFormItem(ui.t("format_locale"), formatLocaleSelect),
+		widget.NewFormItem(ui.t("server_locales"), serverLocaleEntry),
+		widget.NewFormItem("", traceCheck),
+		widget.NewFormItem(ui.t("protocol_trace_file"), traceFileEntry),
+		widget.NewFormItem("", strictAccessCheck),
+		widget.NewFormItem("", writeQueueCheck),
+		widget.NewFormItem("", goldenNotifyCheck),
+		widget.NewFormItem(ui.t("golden_webhook"), goldenWebhookEntry),
+		widget.NewFormItem(ui.t("update_url"), updateURLEntry),
+		widget.NewFormItem("", container.NewHBox(updateOnStartCheck, updateCheckBtn)),
+		widget.NewFormItem(ui.t("write_allow"), writeAllowEntry),
+		widget.NewFormItem(ui.t("write_deny"), writeDenyEntry),
 	}
 
 	// Build custom form content so we can style buttons
@@ -1576,7 +3316,6 @@ func (ui *UI) showConfigDialog() {
 	formWidget.SubmitText = ""
 	formWidget.CancelText = ""
 	// Footer with buttons
-	var settingsDlg *dialog.CustomDialog
 	cancelBtn := widget.NewButtonWithIcon(ui.t("cancel_btn"), theme.CancelIcon(), func() {
 		if settingsDlg != nil {
 			settingsDlg.Hide()
@@ -1585,6 +3324,19 @@ func (ui *UI) showConfigDialog() {
 	cancelBtn.Importance = widget.MediumImportance // default style
 	saveBtn := widget.NewButtonWithIcon(ui.t("save_btn"), theme.ConfirmIcon(), func() {
 		// Save logic
+		hostOverride := strings.TrimSpace(hostOverrideEntry.Text)
+		if strings.Contains(hostOverride, "/") {
+			dialog.ShowError(fmt.Errorf("%s: expected IP or host[:port], got '%s'", ui.t("host_override"), hostOverride), ui.window)
+			return
+		}
+		ui.config.HostOverride = hostOverride
+		// Credentials typed into the URL belong in the username login
+		if user, password, ok := opc.EndpointCredentials(endpointEntry.Text); ok {
+			userEntry.SetText(user)
+			passwordEntry.SetText(password)
+			authModeRadio.SetSelected(valueToDisplay["Username"])
+			endpointEntry.SetText(normalizeEndpoint(endpointEntry.Text))
+		}
 		ui.config.EndpointURL = endpointEntry.Text
 		ui.endpointEntry.SetText(endpointEntry.Text)
 		ui.config.ApplicationURI = appURIEntry.Text
@@ -1596,23 +3348,213 @@ func (ui *UI) showConfigDialog() {
 		ui.config.Password = passwordEntry.Text
 		ui.config.CertFile = certFileEntry.Text
 		ui.config.KeyFile = keyFileEntry.Text
+		ports := opc.Config{ApiPort: apiPortEntry.Text, PublicDashboard: publicDashboardCheck.Checked, PublicDashboardPort: publicDashboardPortEntry.Text}
+		if err := ports.ValidatePublicDashboardPort(); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
 		ui.config.ApiPort = apiPortEntry.Text
 		ui.config.ApiEnabled = apiEnabledCheck.Checked
+		ui.config.KeepApiRunning = keepApiCheck.Checked
+		ui.config.PublicDashboard = publicDashboardCheck.Checked
+		ui.config.PublicDashboardPort = strings.TrimSpace(publicDashboardPortEntry.Text)
 		ui.config.AutoConnect = autoConnectCheck.Checked
+		ui.config.TrayEnabled = trayCheck.Checked
+		ui.config.StartMinimized = startMinimizedCheck.Checked
 		ui.config.DisableLog = disableLogCheck.Checked
 
 		if code, ok := langDisplayToCode[languageSelect.Selected]; ok {
 			ui.config.Language = code
 		}
+		for i, name := range scaleNames {
+			if name == scaleSelect.Selected {
+				ui.config.UIScale = uiScales[i]
+			}
+		}
+		if ui.config.UIScale == 1 {
+			ui.config.UIScale = 0
+		}
+		ui.config.UIDensity = densityDisplayToValue[densitySelect.Selected]
+		if ui.config.UIDensity == "comfortable" {
+			ui.config.UIDensity = ""
+		}
+		ui.config.TimestampSource = tsSourceDisplayToValue[tsSourceSelect.Selected]
+		ui.config.FormatLocale = opc.LookupDisplayLocale(formatLocaleSelect.Selected).Tag
+		localeIDs := opc.ParseLocaleIDs(serverLocaleEntry.Text)
+		localesChanged := !slices.Equal(localeIDs, ui.config.LocaleIDs)
+		ui.config.LocaleIDs = localeIDs
+		if tz := strings.TrimSpace(tsZoneEntry.Text); tz == "" || strings.EqualFold(tz, "local") || strings.EqualFold(tz, "utc") {
+			ui.config.TimestampTimezone = tz
+		} else if _, err := time.LoadLocation(tz); err == nil {
+			ui.config.TimestampTimezone = tz
+		} else {
+			dialog.ShowError(fmt.Errorf("%s: %v", ui.t("timestamp_timezone"), err), ui.window)
+			return
+		}
+		if s := strings.TrimSpace(clockSkewEntry.Text); s == "" {
+			ui.config.ClockSkewWarnSeconds = 0
+		} else if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 {
+			ui.config.ClockSkewWarnSeconds = v
+		} else {
+			dialog.ShowError(fmt.Errorf("%s: invalid value '%s'", ui.t("clock_skew_warn_s"), s), ui.window)
+			return
+		}
 		if timeout, err := strconv.ParseFloat(timeoutEntry.Text, 64); err == nil {
 			ui.config.ConnectTimeout = timeout
 		}
+		for _, t := range []struct {
+			label string
+			entry *widget.Entry
+			dst   *float64
+		}{
+			{"timeout_read", readTimeoutEntry, &ui.config.ReadTimeout},
+			{"timeout_browse", browseTimeoutEntry, &ui.config.BrowseTimeout},
+			{"timeout_write", writeTimeoutEntry, &ui.config.WriteTimeout},
+			{"timeout_publish", publishTimeoutEntry, &ui.config.PublishTimeout},
+			{"timeout_export", exportTimeoutEntry, &ui.config.ExportTimeout},
+		} {
+			s := strings.TrimSpace(t.entry.Text)
+			if s == "" {
+				*t.dst = 0
+				continue
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid timeout '%s'", ui.t(t.label), s), ui.window)
+				return
+			}
+			*t.dst = v
+		}
+		for _, t := range []struct {
+			label string
+			entry *widget.Entry
+			dst   *int
+		}{
+			{"retry_attempts", retryAttemptsEntry, &ui.config.RetryAttempts},
+			{"request_retries", requestRetriesEntry, &ui.config.RequestRetryAttempts},
+			{"watch_value_max_len", valueMaxLenEntry, &ui.config.WatchValueMaxLen},
+		} {
+			s := strings.TrimSpace(t.entry.Text)
+			if s == "" {
+				*t.dst = 0
+				continue
+			}
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 1 {
+				dialog.ShowError(fmt.Errorf("%s: invalid value '%s'", ui.t(t.label), s), ui.window)
+				return
+			}
+			*t.dst = v
+		}
+		for _, t := range []struct {
+			label string
+			entry *widget.Entry
+			dst   *float64
+		}{
+			{"retry_delay_s", retryDelayEntry, &ui.config.RetryDelaySeconds},
+			{"retry_max_elapsed_s", retryMaxElapsedEntry, &ui.config.RetryMaxElapsedSeconds},
+			{"reconnect_delay_s", reconnectDelayEntry, &ui.config.ReconnectDelaySeconds},
+		} {
+			s := strings.TrimSpace(t.entry.Text)
+			if s == "" || s == "∞" {
+				*t.dst = 0
+				continue
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid value '%s'", ui.t(t.label), s), ui.window)
+				return
+			}
+			*t.dst = v
+		}
+		for _, t := range []struct {
+			label string
+			entry *widget.Entry
+			dst   *float64
+		}{
+			{"publish_interval", publishIntervalEntry, &ui.config.PublishIntervalMs},
+			{"watch_refresh", pumpIntervalEntry, &ui.config.WatchPumpIntervalMs},
+			{"poll_interval", pollIntervalEntry, &ui.config.PollIntervalMs},
+			{"pulse_ms", pulseEntry, &ui.config.PulseMs},
+		} {
+			s := strings.TrimSpace(t.entry.Text)
+			if s == "" {
+				*t.dst = 0
+				continue
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				dialog.ShowError(fmt.Errorf("%s: invalid interval '%s'", ui.t(t.label), s), ui.window)
+				return
+			}
+			*t.dst = v
+		}
+		var counts [3]uint64
+		for i, t := range []struct {
+			label string
+			entry *widget.Entry
+			bits  int
+		}{
+			{"lifetime_count", lifetimeCountEntry, 32},
+			{"max_keepalive_count", keepAliveCountEntry, 32},
+			{"priority", priorityEntry, 8},
+		} {
+			s := strings.TrimSpace(t.entry.Text)
+			if s == "" {
+				continue
+			}
+			v, err := strconv.ParseUint(s, 10, t.bits)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: invalid value '%s'", ui.t(t.label), s), ui.window)
+				return
+			}
+			counts[i] = v
+		}
+		// The lifetime must cover at least three keep-alive periods (OPC UA Part 4)
+		if counts[0] > 0 && counts[1] > 0 && counts[0] < 3*counts[1] {
+			dialog.ShowError(fmt.Errorf("%s must be at least 3 × %s", ui.t("lifetime_count"), ui.t("max_keepalive_count")), ui.window)
+			return
+		}
+		ui.config.SubscriptionLifetimeCount = uint32(counts[0])
+		ui.config.SubscriptionMaxKeepAliveCount = uint32(counts[1])
+		ui.config.SubscriptionPriority = uint8(counts[2])
+		ui.config.PollMode = pollModes[max(pollModeSelect.SelectedIndex(), 0)]
+		writeAllow, writeDeny := splitLines(writeAllowEntry.Text), splitLines(writeDenyEntry.Text)
+		for _, l := range [][]string{writeAllow, writeDeny} {
+			if err := controller.ValidateWritePatterns(l); err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+		}
+		ui.config.WriteAllow, ui.config.WriteDeny = writeAllow, writeDeny
+		ui.config.ProtocolTrace = traceCheck.Checked
+		ui.config.ProtocolTraceFile = strings.TrimSpace(traceFileEntry.Text)
+		ui.config.StrictAccessLevel = strictAccessCheck.Checked
+		ui.config.WriteQueue = writeQueueCheck.Checked
+		ui.config.GoldenNotify = goldenNotifyCheck.Checked
+		ui.config.GoldenWebhookURL = strings.TrimSpace(goldenWebhookEntry.Text)
+		ui.config.UpdateURL = strings.TrimSpace(updateURLEntry.Text)
+		ui.config.UpdateCheckOnStart = updateOnStartCheck.Checked
 		// Persist and apply changes
 		ui.saveConfig()
 		ui.applyLanguage()
+		ui.applyAppearance()
+		if err := ui.controller.ApplyProtocolTrace(ui.config); err != nil {
+			dialog.ShowError(err, ui.window)
+		}
+		go ui.controller.ApplyIntervals()
+		go ui.controller.UpdateApiServerState(ui.config)
 		if settingsDlg != nil {
 			settingsDlg.Hide()
 		}
+		// The locales are sent when the session is activated; texts already read stay as they are
+		if localesChanged && ui.isConnected {
+			dialog.ShowConfirm(ui.t("server_locales"), ui.t("server_locales_reconnect"), func(ok bool) {
+				if ok {
+					ui.reconnectNow()
+				}
+			}, ui.window)
+		}
 	})
 	saveBtn.Importance = widget.HighImportance
 
@@ -1627,11 +3569,20 @@ func (ui *UI) showConfigDialog() {
 
 }
 
+// Watch table layout: columns before watchFirstValueColumn (NodeID, Name, DataType) do not
+// change with value updates.
+const (
+	watchColumnCount      = 13
+	watchFirstValueColumn = 3
+)
+
 func (ui *UI) updateWatchTableCell(id widget.TableCellID, obj fyne.CanvasObject) {
 	ui.watchTableMutex.RLock()
 	defer ui.watchTableMutex.RUnlock()
 
-	cont := obj.(*fyne.Container)
+	cell := obj.(*tableCell)
+	cell.bind(ui.watchTable, id)
+	cont := cell.content.(*fyne.Container)
 	rect := cont.Objects[0].(*canvas.Rectangle)
 	lbl := cont.Objects[1].(*widget.Label)
 
@@ -1639,7 +3590,7 @@ func (ui *UI) updateWatchTableCell(id widget.TableCellID, obj fyne.CanvasObject)
 		headers := []string{
 			"NodeID", "Name", "DataType", "Value", "Timestamp",
 			"Severity", "SymbolicName", "SubCode", "StructChanged", "SemanticsChanged",
-			"InfoBits", "RawCode",
+			"InfoBits", "RawCode", "Expected",
 		}
 		lbl.TextStyle = fyne.TextStyle{Bold: true}
 		lbl.SetText(headers[id.Col])
@@ -1656,6 +3607,10 @@ func (ui *UI) updateWatchTableCell(id widget.TableCellID, obj fyne.CanvasObject)
 
 	if index == ui.selectedWatchRow {
 		rect.FillColor = theme.Color(theme.ColorNameFocus)
+	} else if (item.Forced && id.Col == 3) || (item.OverflowCount > 0 && id.Col == 5) {
+		rect.FillColor = warningTint()
+	} else if item.Deviation && (id.Col == 3 || id.Col == 12) {
+		rect.FillColor = deviationColor()
 	} else {
 		rect.FillColor = color.Transparent
 	}
@@ -1669,11 +3624,19 @@ func (ui *UI) updateWatchTableCell(id widget.TableCellID, obj fyne.CanvasObject)
 	case 2:
 		text = item.DataType
 	case 3:
-		text = item.Value
+		text, _ = ui.watchValueText(item)
 	case 4:
 		text = item.Timestamp
+		if item.Polled {
+			text += "  [" + ui.t("polled_flag") + "]"
+		} else if item.RateMs > 0 {
+			text += "  [" + rateInterval(item.RateMs).String() + "]"
+		}
 	case 5:
 		text = item.Severity
+		if item.OverflowCount > 0 {
+			text += "  [" + fmt.Sprintf(ui.t("overflow_badge"), item.OverflowCount) + "]"
+		}
 	case 6:
 		text = item.SymbolicName
 	case 7:
@@ -1686,15 +3649,15 @@ func (ui *UI) updateWatchTableCell(id widget.TableCellID, obj fyne.CanvasObject)
 		text = strconv.FormatUint(uint64(item.InfoBits), 10)
 	case 11:
 		text = item.RawCode
+	case 12:
+		text = controller.FormatGolden(item.Golden)
 	}
 
 	lbl.TextStyle = fyne.TextStyle{}
 	lbl.SetText(text)
 	obj.Refresh()
 
-	txt := canvas.NewText(text, color.Transparent)
-	txt.TextSize = theme.TextSize()
-	neededWidth := txt.MinSize().Width + 20
+	neededWidth := fyne.MeasureText(text, theme.TextSize(), lbl.TextStyle).Width + 20
 
 	curWidth := ui.watchTableColumnWidths[id.Col]
 	if neededWidth > curWidth {
@@ -1709,7 +3672,7 @@ func (ui *UI) treeChildrenCallback(uid widget.TreeNodeID) []widget.TreeNodeID {
 		// but only if we are connected.
 		if ui.controller.GetClientForExport() != nil && ui.controller.GetClientContext() != nil {
 			if !ui.controller.HasBrowseBeenPerformed("i=84") && !ui.controller.IsBrowsing("i=84") {
-				go ui.controller.Browse("i=84")
+				go ui.controller.Browse(context.Background(), "i=84")
 			}
 		}
 		return ui.controller.GetAddressSpaceChildren("i=84")
@@ -1734,7 +3697,7 @@ func (ui *UI) treeIsBranchCallback(uid widget.TreeNodeID) bool {
 	// Only trigger a browse when we have a connected client/context to avoid log spam pre-connect.
 	if ui.controller.GetClientForExport() != nil && ui.controller.GetClientContext() != nil {
 		if !ui.controller.HasBrowseBeenPerformed(string(uid)) && !ui.controller.IsBrowsing(string(uid)) {
-			go ui.controller.Browse(string(uid))
+			go ui.controller.Browse(context.Background(), string(uid))
 		}
 	}
 
@@ -1909,14 +3872,62 @@ func (r *treeRow) TappedSecondary(ev *fyne.PointEvent) {
 	// Build menu item for Add to Watch
 	addItem := fyne.NewMenuItem(r.ui.t("add_to_watch"), func() {
 		nid := string(r.nodeID)
-		go r.ui.controller.AddWatch(nid)
+		go r.ui.controller.AddWatch(context.Background(), nid)
 	})
 	// Only enable for Variable nodes
 	if r.nodeClass != ua.NodeClassVariable {
 		addItem.Disabled = true
 	}
 
-	m := fyne.NewMenu("", addItem)
+	historyItem := fyne.NewMenuItem(r.ui.t("history"), func() {
+		r.ui.showHistoryDialog(string(r.nodeID))
+	})
+	if r.nodeClass != ua.NodeClassVariable {
+		historyItem.Disabled = true
+	}
+	// Objects such as the Server object are the notifiers whose events may be historized
+	eventHistoryItem := fyne.NewMenuItem(r.ui.t("event_history"), func() {
+		r.ui.showEventHistoryDialog(string(r.nodeID))
+	})
+	if r.nodeClass != ua.NodeClassObject {
+		eventHistoryItem.Disabled = true
+	}
+
+	attrItem := fyne.NewMenuItem(r.ui.t("edit_attributes"), func() {
+		r.ui.showAttributeEditor(string(r.nodeID))
+	})
+
+	favLabel := r.ui.t("add_favorite")
+	if r.ui.favoriteIndex(string(r.nodeID)) >= 0 {
+		favLabel = r.ui.t("remove_favorite")
+	}
+	favItem := fyne.NewMenuItem(favLabel, func() {
+		r.ui.toggleFavorite(string(r.nodeID), r.name.Text)
+	})
+
+	snippetsItem := fyne.NewMenuItem(r.ui.t("api_snippets"), func() {
+		r.ui.showSnippetsDialog(string(r.nodeID))
+	})
+	if r.nodeClass != ua.NodeClassVariable {
+		snippetsItem.Disabled = true
+	}
+
+	refreshItem := fyne.NewMenuItem(r.ui.t("refresh_branch"), func() {
+		r.ui.refreshBranch(string(r.nodeID))
+	})
+	watchLabel := r.ui.t("model_watch_on")
+	if r.ui.controller.IsModelWatched(string(r.nodeID)) {
+		watchLabel = r.ui.t("model_watch_off")
+	}
+	modelWatchItem := fyne.NewMenuItem(watchLabel, func() {
+		r.ui.toggleModelWatch(string(r.nodeID))
+	})
+	if !r.isBranch {
+		refreshItem.Disabled = true
+		modelWatchItem.Disabled = true
+	}
+
+	m := fyne.NewMenu("", addItem, historyItem, eventHistoryItem, attrItem, favItem, snippetsItem, fyne.NewMenuItemSeparator(), refreshItem, modelWatchItem)
 	// Show popup menu (default placement handled by Fyne)
 	widget.NewPopUpMenu(m, r.ui.window.Canvas())
 }
@@ -1937,11 +3948,23 @@ func (r *treeRow) Tapped(ev *fyne.PointEvent) {
 	// r.ui.controller.Log(fmt.Sprintf("[blue]Row Tapped: %s[-]", string(r.nodeID)))
 }
 
+// jsonStreamFormat is the export format writing JSON node records while browsing, for address
+// spaces too large to hold as a tree.
+const jsonStreamFormat = "JSON (streaming)"
+
+// addressSpaceFormats maps the export dialog's formats to controller.AddressSpaceFormats.
+var addressSpaceFormats = map[string]string{"JSON": "json", jsonStreamFormat: "json-stream", "CSV": "csv", "Excel": "xlsx"}
+
 func (ui *UI) showExportDialog() {
-	// Format selection: JSON, CSV, Excel
-	fileTypeRadio := widget.NewRadioGroup([]string{"JSON", "CSV", "Excel"}, nil)
+	// Format selection: JSON, streamed JSON, CSV, Excel or a SCADA tag import preset
+	formats := []string{"JSON", jsonStreamFormat, "CSV", "Excel"}
+	presetByLabel := make(map[string]controller.TagPreset)
+	for _, p := range controller.TagPresets {
+		formats = append(formats, p.Label)
+		presetByLabel[p.Label] = p
+	}
+	fileTypeRadio := widget.NewSelect(formats, nil)
 	fileTypeRadio.SetSelected("JSON")
-	fileTypeRadio.Horizontal = true
 
 	// Scope selection: All or Folder
 	scopeRadio := widget.NewRadioGroup([]string{ui.t("all"), ui.t("folder")}, nil)
@@ -1961,6 +3984,59 @@ func (ui *UI) showExportDialog() {
 	recursiveCheck.Checked = true
 	recursiveCheck.Disable()
 
+	// Column template (CSV and Excel only; JSON keeps the full tree)
+	templateSelect, templatePicker := ui.newExportTemplatePicker()
+	templateSelect.Disable()
+	// Optional attributes read per node; skipping some speeds up large exports
+	attrLabels := make(map[string]string, len(opc.ExportAttributeFields))
+	var attrOptions []string
+	for _, f := range opc.ExportFields {
+		if slices.Contains(opc.ExportAttributeFields, f.Field) {
+			attrLabels[f.Header] = f.Field
+			attrOptions = append(attrOptions, f.Header)
+		}
+	}
+	attrCheck := widget.NewCheckGroup(attrOptions, nil)
+	attrCheck.Horizontal = true
+	for _, label := range attrOptions {
+		if !slices.Contains(ui.config.ExportSkipAttributes, attrLabels[label]) {
+			attrCheck.Selected = append(attrCheck.Selected, label)
+		}
+	}
+	// CSV delimiter, BOM and quoting, e.g. semicolons and a BOM for Excel in European locales
+	csvFormat := ui.config.CSVFormat()
+	delimiterNames := []string{ui.t("csv_delimiter_comma"), ui.t("csv_delimiter_semicolon"), ui.t("csv_delimiter_tab")}
+	delimiterSelect := widget.NewSelect(delimiterNames, nil)
+	delimiterSelect.SetSelectedIndex(max(slices.Index(opc.CSVDelimiters, csvFormat.Delimiter), 0))
+	quoteNames := []string{ui.t("csv_quote_minimal"), ui.t("csv_quote_all")}
+	quoteSelect := widget.NewSelect(quoteNames, nil)
+	quoteSelect.SetSelectedIndex(max(slices.Index(opc.CSVQuotes, csvFormat.Quote), 0))
+	bomCheck := widget.NewCheck(ui.t("csv_bom"), nil)
+	bomCheck.SetChecked(csvFormat.BOM)
+	setCSVEnabled := func(on bool) {
+		for _, w := range []fyne.Disableable{delimiterSelect, quoteSelect, bomCheck} {
+			if on {
+				w.Enable()
+			} else {
+				w.Disable()
+			}
+		}
+	}
+	setCSVEnabled(false)
+	fileTypeRadio.OnChanged = func(s string) {
+		setCSVEnabled(s == "CSV")
+		if _, preset := presetByLabel[s]; preset || s == "JSON" || s == jsonStreamFormat {
+			templateSelect.Disable()
+		} else {
+			templateSelect.Enable()
+		}
+		if _, preset := presetByLabel[s]; preset {
+			attrCheck.Disable()
+		} else {
+			attrCheck.Enable()
+		}
+	}
+
 	// Enable/disable controls based on scope
 	scopeRadio.OnChanged = func(s string) {
 		isFolder := s == ui.t("folder")
@@ -1976,6 +4052,9 @@ func (ui *UI) showExportDialog() {
 	d := dialog.NewForm(ui.t("export_dialog"), ui.t("export_btn"), ui.t("cancel_btn"),
 		[]*widget.FormItem{
 			widget.NewFormItem(ui.t("format"), fileTypeRadio),
+			widget.NewFormItem(ui.t("export_template"), templatePicker),
+			widget.NewFormItem(ui.t("export_attributes"), attrCheck),
+			widget.NewFormItem(ui.t("csv_options"), container.NewHBox(delimiterSelect, quoteSelect, bomCheck)),
 			widget.NewFormItem(ui.t("scope"), scopeRadio),
 			widget.NewFormItem(ui.t("folder_nodeid"), nodeIDEntry),
 			widget.NewFormItem(ui.t("options"), recursiveCheck),
@@ -1988,6 +4067,19 @@ func (ui *UI) showExportDialog() {
 			scope := scopeRadio.Selected
 			nodeID := strings.TrimSpace(nodeIDEntry.Text)
 			recursive := recursiveCheck.Checked
+			template := ui.selectedExportTemplate(templateSelect)
+			ui.config.ExportSkipAttributes = nil
+			for _, label := range attrOptions {
+				if !slices.Contains(attrCheck.Selected, label) {
+					ui.config.ExportSkipAttributes = append(ui.config.ExportSkipAttributes, attrLabels[label])
+				}
+			}
+			if format == "CSV" {
+				ui.config.CSVDelimiter = opc.CSVDelimiters[max(delimiterSelect.SelectedIndex(), 0)]
+				ui.config.CSVQuote = opc.CSVQuotes[max(quoteSelect.SelectedIndex(), 0)]
+				ui.config.CSVBOM = bomCheck.Checked
+			}
+			ui.saveConfig()
 
 			if scope == ui.t("folder") && nodeID == "" {
 				dialog.ShowError(errors.New(ui.t("folder_nodeid_error")), ui.window)
@@ -1999,16 +4091,23 @@ func (ui *UI) showExportDialog() {
 
 			var filter storage.FileFilter
 			var extension string
+			if p, ok := presetByLabel[format]; ok {
+				format = p.ID
+			}
 			switch format {
-			case "JSON":
+			case "JSON", jsonStreamFormat:
 				filter = storage.NewExtensionFileFilter([]string{".json"})
 				extension = ".json"
 			case "CSV":
 				filter = storage.NewExtensionFileFilter([]string{".csv"})
 				extension = ".csv"
-			default: // Excel
+			case "Excel":
 				filter = storage.NewExtensionFileFilter([]string{".xlsx"})
 				extension = ".xlsx"
+			default: // SCADA tag preset
+				p, _ := controller.LookupTagPreset(format)
+				filter = storage.NewExtensionFileFilter([]string{p.Extension})
+				extension = p.Extension
 			}
 
 			saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
@@ -2027,7 +4126,7 @@ func (ui *UI) showExportDialog() {
 				if scope == ui.t("folder") {
 					scopeInternal = "Folder"
 				}
-				go ui.runExport(filePath, format, scopeInternal, nodeID, recursive)
+				go ui.runExport(filePath, format, scopeInternal, nodeID, template, recursive)
 
 			}, ui.window)
 			saveDialog.SetFileName("export" + extension)
@@ -2037,7 +4136,7 @@ func (ui *UI) showExportDialog() {
 	d.Show()
 }
 
-func (ui *UI) runExport(filePath, format, scope, nodeID string, recursive bool) {
+func (ui *UI) runExport(filePath, format, scope, nodeID, template string, recursive bool) {
 	client := ui.controller.GetClientForExport()
 	if client == nil {
 		fyne.CurrentApp().SendNotification(&fyne.Notification{
@@ -2062,21 +4161,26 @@ func (ui *UI) runExport(filePath, format, scope, nodeID string, recursive bool)
 	})
 
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-		var exportErr error
-		exporter := exporter.New(client)
-		if scope == "Folder" && !recursive {
+		_, isPreset := controller.LookupTagPreset(format)
+		if scope == "Folder" && !recursive && !isPreset {
 			// For now, non-recursive export is not implemented in exporter APIs; fall back to recursive
 			ui.controller.Log("[yellow]Non-recursive export not yet supported; exporting recursively.[-]")
 		}
-		switch format {
-		case "JSON":
-			exportErr = exporter.ExportToJSON(ctx, rootID, filePath)
-		case "CSV":
-			exportErr = exporter.ExportToCSV(ctx, rootID, filePath)
-		default: // Excel
-			exportErr = exporter.ExportToExcel(ctx, rootID, filePath)
+		var exportErr error
+		if isPreset {
+			exportErr = ui.exportTagPreset(filePath, format, scope, nodeID, recursive)
+			if exportErr != nil {
+				ui.controller.Log(fmt.Sprintf("[red]Export failed: %v[-]", exportErr))
+			} else {
+				ui.controller.Log(fmt.Sprintf("[green]Successfully exported from %s to %s[-]", rootID, filePath))
+			}
+		} else {
+			exportErr = ui.controller.ExportAddressSpace(context.Background(), controller.AddressSpaceExport{
+				RootID:   rootID,
+				Format:   addressSpaceFormats[format],
+				Template: template,
+				FilePath: filePath,
+			})
 		}
 
 		if exportErr != nil {
@@ -2084,17 +4188,44 @@ func (ui *UI) runExport(filePath, format, scope, nodeID string, recursive bool)
 				Title:   "Export Failed",
 				Content: exportErr.Error(),
 			})
-			ui.controller.Log(fmt.Sprintf("[red]Export failed: %v[-]", exportErr))
 		} else {
 			fyne.CurrentApp().SendNotification(&fyne.Notification{
 				Title:   "Export Successful",
 				Content: "Exported to " + filePath,
 			})
-			ui.controller.Log(fmt.Sprintf("[green]Successfully exported from %s to %s[-]", rootID, filePath))
 		}
 	}()
 }
 
+// exportTagPreset writes the Variable nodes of the export scope as a SCADA tag import file.
+// The tags read through an OPC UA connection named after the endpoint host at the watch
+// publishing interval.
+func (ui *UI) exportTagPreset(filePath, presetID, scope, nodeID string, recursive bool) error {
+	parentID := ""
+	if scope == "Folder" && nodeID != "" {
+		parentID = nodeID
+	} else {
+		recursive = true
+	}
+	tags, err := ui.controller.CollectVariableNodes(context.Background(), parentID, recursive)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	opts := controller.TagPresetOptions{ScanRateMs: int(ui.config.PublishIntervalMs)}
+	if u, err := url.Parse(ui.config.EndpointURL); err == nil {
+		opts.OPCServer = u.Hostname()
+	}
+	if err := controller.WriteTagPreset(f, presetID, tags, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func (ui *UI) makeLayout() fyne.CanvasObject {
 	// Follow system theme automatically using a themed panel that refreshes on theme change
 	newBg := func() fyne.CanvasObject {
@@ -2102,16 +4233,20 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 	}
 
 	// Connection section with subtle gray tint and padding
-	endpointWithStatus := container.NewBorder(nil, nil, nil, ui.statusIcon,
+	endpointWithStatus := container.NewBorder(nil, nil, nil, container.NewHBox(ui.testBtn, ui.statusIcon, ui.serverBanner),
 		container.NewPadded(ui.endpointEntry)) // Add padding around the entry
 	connBg := newBg()
 
 	// Create a padded grid for buttons with even spacing
 	buttonGrid := container.NewPadded(
-		container.NewGridWithColumns(3,
+		container.NewGridWithColumns(7,
 			container.NewHBox(layout.NewSpacer(), ui.connectBtn, layout.NewSpacer()),
 			container.NewHBox(layout.NewSpacer(), ui.configBtn, layout.NewSpacer()),
 			container.NewHBox(layout.NewSpacer(), ui.exportBtn, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), ui.offlineBtn, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), ui.nodesetBtn, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), ui.historyBtn, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), ui.aboutBtn, layout.NewSpacer()),
 		),
 	)
 
@@ -2129,9 +4264,14 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 
 	// Address space section with the same subtle gray tint
 	addrBg := newBg()
-	addrContent := container.NewStack(addrBg, ui.nodeTree)
+	addrContent := container.NewStack(addrBg,
+		container.NewBorder(container.NewHBox(layout.NewSpacer(), ui.searchBtn, ui.rebrowseBtn), nil, nil, nil, ui.nodeTree))
 	ui.addressSpaceCard = nil
-	leftBottom := addrContent
+	ui.leftTabs = container.NewAppTabs(
+		container.NewTabItem(ui.t("address_space"), addrContent),
+		container.NewTabItem(ui.t("favorites"), container.NewStack(newBg(), ui.makeFavoritesPanel())),
+	)
+	leftBottom := ui.leftTabs
 	leftPanel := container.NewVSplit(leftTop, leftBottom)
 	// 延迟设置分割比例以确保渲染器已准备就绪
 	defer func() {
@@ -2139,6 +4279,11 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 	}()
 
 	ui.clearAllBtn = widget.NewButtonWithIcon(ui.t("clear_all"), theme.ContentClearIcon(), ui.controller.RemoveAllWatches)
+	ui.bulkWriteBtn = widget.NewButtonWithIcon(ui.t("bulk_write"), theme.UploadIcon(), ui.showBulkWriteDialog)
+	ui.pasteWatchBtn = widget.NewButtonWithIcon(ui.t("paste_node_ids"), theme.ContentPasteIcon(), ui.showPasteNodeIDsDialog)
+	ui.exportWatchBtn = widget.NewButtonWithIcon(ui.t("export_ua_json"), theme.DownloadIcon(), func() {
+		ui.saveUAJSON("watch.json", ui.controller.ExportWatchUAJSON)
+	})
 
 	// Create a padded container for watch buttons with even spacing
 	watchButtons := container.NewPadded(
@@ -2152,6 +4297,20 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 			layout.NewSpacer(),
 			ui.writeWatchBtn,
 			layout.NewSpacer(),
+			ui.goldenBtn,
+			layout.NewSpacer(),
+			ui.forceBtn,
+			layout.NewSpacer(),
+			ui.captureBtn,
+			layout.NewSpacer(),
+			ui.writeQueueBtn,
+			layout.NewSpacer(),
+			ui.bulkWriteBtn,
+			layout.NewSpacer(),
+			ui.pasteWatchBtn,
+			layout.NewSpacer(),
+			ui.exportWatchBtn,
+			layout.NewSpacer(),
 		),
 	)
 
@@ -2165,12 +4324,11 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 	)
 
 	// Watch list with the same subtle gray tint
-	watchScroll := container.NewVScroll(ui.watchTable)
 	watchBg := newBg()
 	watchContent := container.NewStack(
 		watchBg,
 		container.NewBorder(toolbar, nil, nil, nil,
-			container.NewPadded(watchScroll), // Add padding around the watch list
+			container.NewPadded(ui.watchTable), // The table scrolls itself and only renders visible cells
 		),
 	)
 	// No Card for watch list; keep pure container for white background
@@ -2183,10 +4341,13 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 	// Details 区域与日志区域结构对齐：背景 + 顶部标题 + 内边距 + 内容
 	detailsBg := newBg()
 	ui.detailsTitleLbl = widget.NewLabelWithStyle(ui.t("selected_details"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	ui.copyDetailsBtn = widget.NewButtonWithIcon(ui.t("copy_as"), theme.ContentCopyIcon(), ui.showCopyDetailsMenu)
+	ui.exportReportBtn = widget.NewButtonWithIcon(ui.t("export_report"), theme.DocumentSaveIcon(), ui.exportNodeReport)
+	ui.noteBtn = widget.NewButtonWithIcon(ui.t("node_note"), theme.DocumentCreateIcon(), ui.showNodeNoteDialog)
 	detailsHeader := container.NewBorder(
 		nil, nil,
 		ui.detailsTitleLbl,
-		nil,
+		container.NewHBox(ui.noteBtn, ui.copyDetailsBtn, ui.exportReportBtn),
 		layout.NewSpacer(),
 	)
 	detailsContainer := container.NewStack(
@@ -2263,7 +4424,7 @@ func (ui *UI) makeLayout() fyne.CanvasObject {
 	// 通过 Padded 容器提供的上下内边距保证足够高度
 
 	// 用 Border 将品牌栏置于顶部
-	wrapped := container.NewBorder(brand, nil, nil, nil, mainLayout)
+	wrapped := container.NewBorder(container.NewVBox(brand, ui.makeReconnectBanner()), nil, nil, nil, mainLayout)
 	// Outermost background: themed, borderless, follows system theme
 	rootBg := NewThemedBackground(ui.app)
 	return container.NewStack(rootBg, wrapped)
@@ -2292,23 +4453,7 @@ func (ui *UI) clearLogs() {
 }
 
 func normalizeEndpoint(input string) string {
-	s := strings.TrimSpace(input)
-	if s == "" {
-		return "opc.tcp://127.0.0.1:4840"
-	}
-	if strings.Contains(s, "://") {
-		return s
-	}
-	if strings.HasPrefix(s, "[") {
-		if _, _, err := net.SplitHostPort(s); err == nil {
-			return "opc.tcp://" + s
-		}
-		return "opc.tcp://" + s + ":4840"
-	}
-	if host, port, err := net.SplitHostPort(s); err == nil && host != "" && port != "" {
-		return "opc.tcp://" + s
-	}
-	return "opc.tcp://" + s + ":4840"
+	return opc.NormalizeEndpoint(input)
 }
 
 // updateDetailsColumnWidths 根据属性名称文本宽度自适应设置左列宽度
@@ -2360,6 +4505,7 @@ func (ui *UI) updateDetailsColumnWidths() {
 const configName = "opcuababy_config.json"
 
 func (ui *UI) saveConfig() {
+	ui.config.Usage = ui.controller.UsageStats()
 	// 1) Save to Preferences (works on iOS/iPadOS)
 	if ui.app != nil {
 		if data, err := json.Marshal(ui.config); err == nil {
@@ -2466,3 +4612,14 @@ func parseColorTags(logText string) []widget.RichTextSegment {
 	}
 	return segments
 }
+
+// splitLines returns the trimmed, non-empty lines of s.
+func splitLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
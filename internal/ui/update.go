@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"opcuababy/internal/update"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// updateCheckInterval spaces automatic update checks.
+const updateCheckInterval = 24 * time.Hour
+
+// currentVersion is the version of this build: the linked-in version, else the packaged app's.
+func (ui *UI) currentVersion() string {
+	if update.Version != "dev" {
+		return update.Version
+	}
+	if v := ui.app.Metadata().Version; v != "" {
+		return v
+	}
+	return update.Version
+}
+
+// autoCheckForUpdates runs the startup check when enabled and due. Failures are only logged:
+// plant laptops are often offline.
+func (ui *UI) autoCheckForUpdates() {
+	if !ui.config.UpdateCheckOnStart || time.Since(ui.config.UpdateLastCheck) < updateCheckInterval {
+		return
+	}
+	ui.config.UpdateLastCheck = time.Now()
+	ui.saveConfig()
+	ui.checkForUpdates(ui.config.UpdateURL, false)
+}
+
+// checkForUpdates looks up the newest release at source. A manual check reports every result;
+// an automatic one only a newer release the user has not skipped.
+func (ui *UI) checkForUpdates(source string, manual bool) {
+	current := ui.currentVersion()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		rel, err := update.Check(ctx, source)
+		fyne.Do(func() {
+			if err != nil {
+				ui.controller.Log(fmt.Sprintf("[yellow]Update check failed: %v[-]", err))
+				if manual {
+					dialog.ShowError(fmt.Errorf("%s: %w", ui.t("update_check"), err), ui.window)
+				}
+				return
+			}
+			if !rel.IsNewer(current) {
+				if manual {
+					dialog.ShowInformation(ui.t("update_check"), fmt.Sprintf(ui.t("update_current"), current, rel.Version), ui.window)
+				}
+				return
+			}
+			ui.controller.Log(fmt.Sprintf("[cyan]Version %s is available (running %s)[-]", rel.Version, current))
+			if !manual && strings.EqualFold(rel.Version, ui.config.UpdateSkipVersion) {
+				return
+			}
+			ui.showUpdateDialog(rel, current)
+		})
+	}()
+}
+
+// showUpdateDialog presents a newer release with its notes. Desktop builds can download the
+// matching file into a staging folder; mobile builds link to the release page.
+func (ui *UI) showUpdateDialog(rel *update.Release, current string) {
+	header := widget.NewLabelWithStyle(fmt.Sprintf(ui.t("update_available"), rel.Version, current), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	header.Wrapping = fyne.TextWrapWord
+	info := container.NewVBox(header)
+	if rel.Name != "" && rel.Name != rel.Version {
+		info.Add(widget.NewLabel(rel.Name))
+	}
+	if !rel.Published.IsZero() {
+		info.Add(widget.NewLabel(fmt.Sprintf(ui.t("update_published"), rel.Published.Local().Format("2006-01-02"))))
+	}
+	notes := widget.NewRichTextFromMarkdown(rel.Notes)
+	notes.Wrapping = fyne.TextWrapWord
+	notesScroll := container.NewVScroll(notes)
+	notesScroll.SetMinSize(fyne.NewSize(520, 260))
+
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	status.Hide()
+	progress := widget.NewProgressBar()
+	progress.Hide()
+	buttons := container.NewHBox()
+
+	if rel.PageURL != "" {
+		if u, err := url.Parse(rel.PageURL); err == nil {
+			buttons.Add(widget.NewButtonWithIcon(ui.t("update_release_page"), theme.ComputerIcon(), func() {
+				_ = ui.app.OpenURL(u)
+			}))
+		}
+	}
+	var dlg *dialog.CustomDialog
+	// A download has no overall timeout; closing the dialog cancels it
+	ctx, cancel := context.WithCancel(context.Background())
+	skipBtn := widget.NewButton(ui.t("update_skip"), func() {
+		ui.config.UpdateSkipVersion = rel.Version
+		ui.saveConfig()
+		dlg.Hide()
+	})
+	buttons.Add(skipBtn)
+
+	mobile := runtime.GOOS == "ios" || runtime.GOOS == "android"
+	if asset := rel.AssetFor(runtime.GOOS, runtime.GOARCH); asset != nil && !mobile {
+		var downloadBtn *widget.Button
+		downloadBtn = widget.NewButtonWithIcon(fmt.Sprintf(ui.t("update_download"), asset.Name), theme.DownloadIcon(), func() {
+			downloadBtn.Disable()
+			progress.SetValue(0)
+			progress.Show()
+			dir := update.StagingDir(rel.Version)
+			go func() {
+				shown := -1
+				path, err := update.Download(ctx, asset, dir, func(done, total int64) {
+					// Redraw per percent, not per chunk
+					if pct := int(done * 100 / max(total, 1)); total > 0 && pct != shown {
+						shown = pct
+						fyne.Do(func() { progress.SetValue(float64(pct) / 100) })
+					}
+				})
+				fyne.Do(func() {
+					progress.Hide()
+					status.Show()
+					if err != nil {
+						downloadBtn.Enable()
+						status.SetText(err.Error())
+						ui.controller.Log(fmt.Sprintf("[red]Update download failed: %v[-]", err))
+						return
+					}
+					status.SetText(fmt.Sprintf(ui.t("update_staged"), path))
+					ui.controller.Log(fmt.Sprintf("[green]Update %s downloaded to %s[-]", rel.Version, path))
+					buttons.Add(widget.NewButtonWithIcon(ui.t("update_open_folder"), theme.FolderOpenIcon(), func() {
+						_ = ui.app.OpenURL(fileURL(filepath.Dir(path)))
+					}))
+				})
+			}()
+		})
+		downloadBtn.Importance = widget.HighImportance
+		buttons.Add(downloadBtn)
+	}
+
+	content := container.NewBorder(info, container.NewVBox(progress, status, buttons), nil, nil, notesScroll)
+	dlg = dialog.NewCustom(ui.t("update_check"), ui.t("close_btn"), content, ui.window)
+	dlg.SetOnClosed(cancel)
+	dlg.Resize(fyne.NewSize(620, 480))
+	dlg.Show()
+}
+
+// fileURL returns a file:// URL for a local path, for OpenURL.
+func fileURL(path string) *url.URL {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		// Windows drive paths
+		p = "/" + p
+	}
+	return &url.URL{Scheme: "file", Path: p}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"opcuababy/internal/controller"
+	"opcuababy/internal/opc"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// watchValueText is the Value cell of a watch item, cut off at the configured length; it
+// reports whether the value was cut.
+func (ui *UI) watchValueText(item *controller.WatchItem) (string, bool) {
+	text := controller.FormatEnumValue(ui.config.DisplayLocale().ValueOfType(item.UAType, item.Value), item.EnumName+item.Flags)
+	text, cut := opc.TruncateText(text, ui.config.WatchValueLimit())
+	if item.Forced {
+		text = "[" + ui.t("forced_flag") + "] " + text
+	}
+	return text, cut
+}
+
+// valueBytes returns the bytes of a watch value: ByteStrings are shown as hex and decoded,
+// anything else is taken as its UTF-8 text.
+func valueBytes(uaType, dataType, value string) ([]byte, bool) {
+	if strings.EqualFold(uaType, "ByteString") || strings.EqualFold(dataType, "ByteString") {
+		if b, err := hex.DecodeString(value); err == nil {
+			return b, true
+		}
+	}
+	return []byte(value), false
+}
+
+// printableASCII replaces the bytes outside printable ASCII with dots, like the right column of
+// a hex dump.
+func printableASCII(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c >= 0x20 && c < 0x7f {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
+
+// showFullValueDialog shows the whole value of the watch item nodeID, which the watch list cuts
+// off at WatchValueMaxLen characters, as text or as a hex dump with an ASCII column.
+func (ui *UI) showFullValueDialog(nodeID string) {
+	var uaType, dataType, value string
+	var found bool
+	load := func() {
+		ui.watchTableMutex.RLock()
+		defer ui.watchTableMutex.RUnlock()
+		for _, it := range ui.watchRows {
+			if it.NodeID == nodeID {
+				uaType, dataType, value, found = it.UAType, it.DataType, it.Value, true
+				return
+			}
+		}
+	}
+	load()
+	if !found {
+		return
+	}
+
+	content := widget.NewMultiLineEntry()
+	content.TextStyle = fyne.TextStyle{Monospace: true}
+	info := widget.NewLabel("")
+	info.Importance = widget.LowImportance
+	views := []string{ui.t("value_view_text"), ui.t("value_view_hex")}
+	viewRadio := widget.NewRadioGroup(views, nil)
+	viewRadio.Horizontal = true
+	viewRadio.Required = true
+	update := func() {
+		b, binary := valueBytes(uaType, dataType, value)
+		if viewRadio.Selected == views[1] {
+			content.Wrapping = fyne.TextWrapOff
+			content.SetText(strings.TrimSuffix(hex.Dump(b), "\n"))
+		} else {
+			content.Wrapping = fyne.TextWrapBreak
+			if binary {
+				content.SetText(printableASCII(b))
+			} else {
+				content.SetText(value)
+			}
+		}
+		info.SetText(fmt.Sprintf(ui.t("value_view_size"), uaType, utf8.RuneCountInString(value), len(b)))
+	}
+	viewRadio.OnChanged = func(string) { update() }
+	if _, binary := valueBytes(uaType, dataType, value); binary {
+		viewRadio.SetSelected(views[1])
+	} else {
+		viewRadio.SetSelected(views[0])
+	}
+
+	copyBtn := widget.NewButtonWithIcon(ui.t("copy"), theme.ContentCopyIcon(), func() {
+		ui.app.Clipboard().SetContent(content.Text)
+		ui.controller.Log(fmt.Sprintf("[green]Copied value of %s[-]", nodeID))
+	})
+	refreshBtn := widget.NewButtonWithIcon(ui.t("refresh"), theme.ViewRefreshIcon(), func() {
+		load()
+		update()
+	})
+	writeBtn := widget.NewButtonWithIcon(ui.t("write"), theme.DocumentCreateIcon(), func() {
+		ui.openWriteForNode(nodeID)
+	})
+
+	top := container.NewBorder(nil, nil, viewRadio, container.NewHBox(refreshBtn, copyBtn, writeBtn))
+	body := container.NewBorder(top, info, nil, nil, content)
+	dlg := dialog.NewCustom(fmt.Sprintf(ui.t("value_view_title"), nodeID), ui.t("close_btn"), body, ui.window)
+	dlg.Resize(fyne.NewSize(720, 520))
+	dlg.Show()
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showWriteQueue lists the writes waiting for the session; each can be cancelled.
+func (ui *UI) showWriteQueue() {
+	if ui.writeQueueList != nil {
+		ui.refreshWriteQueue()
+		return
+	}
+	list := widget.NewList(
+		func() int { return len(ui.writeQueueRows) },
+		func() fyne.CanvasObject {
+			lbl := widget.NewLabel("")
+			lbl.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon(ui.t("cancel_write"), theme.CancelIcon(), nil), lbl)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(ui.writeQueueRows) {
+				return
+			}
+			qw := ui.writeQueueRows[id]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("#%d  %s  %s = %s  (%s)", qw.ID, qw.Queued.Format("15:04:05"), qw.NodeID, qw.Value, qw.Endpoint))
+			row.Objects[1].(*widget.Button).OnTapped = func() { go ui.controller.CancelQueuedWrite(qw.ID) }
+		},
+	)
+	hint := widget.NewLabel(ui.t("write_queue_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	ui.writeQueueList = list
+	ui.refreshWriteQueue()
+	dlg := dialog.NewCustom(ui.t("write_queue_title"), ui.t("close_btn"), container.NewBorder(hint, nil, nil, nil, list), ui.window)
+	dlg.SetOnClosed(func() {
+		ui.writeQueueList = nil
+	})
+	dlg.Resize(fyne.NewSize(720, 420))
+	dlg.Show()
+}
+
+// refreshWriteQueue updates the pending writes button and the open write queue dialog. Must run
+// on the UI thread.
+func (ui *UI) refreshWriteQueue() {
+	ui.writeQueueRows = ui.controller.QueuedWrites()
+	if n := len(ui.writeQueueRows); n > 0 {
+		ui.writeQueueBtn.SetText(fmt.Sprintf(ui.t("write_queue_pending"), n))
+		ui.writeQueueBtn.Show()
+	} else {
+		ui.writeQueueBtn.Hide()
+	}
+	if ui.writeQueueList != nil {
+		ui.writeQueueList.Refresh()
+	}
+}
@@ -0,0 +1,377 @@
+// Package update checks for new releases and downloads them for installation.
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the version of this build, set with
+// -ldflags "-X opcuababy/internal/update.Version=v1.2.3".
+var Version = "dev"
+
+// DefaultSource is checked when no update URL is configured.
+const DefaultSource = "https://api.github.com/repos/channono/opcuababy/releases/latest"
+
+// Asset is a downloadable file of a release.
+type Asset struct {
+	Name   string
+	URL    string
+	Size   int64
+	SHA256 string // hex, optional
+}
+
+// Release describes the newest available version.
+type Release struct {
+	Version   string
+	Name      string
+	Notes     string // Markdown
+	PageURL   string
+	Published time.Time
+	Assets    []Asset
+}
+
+// manifest accepts a GitHub release (GET /repos/{owner}/{repo}/releases/latest) as well as a
+// hand-written file with the short field names, for update servers on plant networks.
+type manifest struct {
+	TagName     string    `json:"tag_name"`
+	Version     string    `json:"version"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Notes       string    `json:"notes"`
+	HTMLURL     string    `json:"html_url"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+		URL                string `json:"url"`
+		Size               int64  `json:"size"`
+		SHA256             string `json:"sha256"`
+	} `json:"assets"`
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Check fetches the release description at source (DefaultSource when empty): an http(s) URL or
+// a file path, e.g. on a share for machines without internet access.
+func Check(ctx context.Context, source string) (*Release, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		source = DefaultSource
+	}
+	data, err := fetch(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid release description at %s: %w", source, err)
+	}
+	rel := &Release{
+		Version:   firstNonEmpty(m.Version, m.TagName),
+		Name:      m.Name,
+		Notes:     firstNonEmpty(m.Notes, m.Body),
+		PageURL:   m.HTMLURL,
+		Published: m.PublishedAt,
+	}
+	if rel.Version == "" {
+		return nil, fmt.Errorf("release description at %s names no version", source)
+	}
+	if rel.PageURL == "" && !strings.Contains(source, "api.github.com") {
+		rel.PageURL = m.URL
+	}
+	for _, a := range m.Assets {
+		u := firstNonEmpty(a.BrowserDownloadURL, a.URL)
+		if u == "" {
+			continue
+		}
+		rel.Assets = append(rel.Assets, Asset{Name: a.Name, URL: resolve(source, u), Size: a.Size, SHA256: strings.ToLower(a.SHA256)})
+	}
+	return rel, nil
+}
+
+// resolve makes asset URLs of hand-written manifests relative to the manifest.
+func resolve(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil || b.Scheme == "" || len(b.Scheme) < 2 {
+		if filepath.IsAbs(ref) || strings.Contains(ref, "://") {
+			return ref
+		}
+		return filepath.Join(filepath.Dir(base), ref)
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// downloadClient has no overall timeout, which would also cut off reading a large asset over a
+// slow link; Download is bounded by its ctx and the connection setup by the Transport timeouts.
+var downloadClient = &http.Client{Transport: &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	TLSHandshakeTimeout:   30 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+	ForceAttemptHTTP2:     true,
+}}
+
+func fetch(ctx context.Context, source string) ([]byte, error) {
+	rc, _, err := open(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, 4<<20))
+}
+
+// open returns the content at an http(s) URL, fetched with cl, file URL or path, and its size if
+// known.
+func open(ctx context.Context, cl *http.Client, source string) (io.ReadCloser, int64, error) {
+	u, err := url.Parse(source)
+	if err != nil || len(u.Scheme) < 2 || u.Scheme == "file" {
+		path := source
+		if err == nil && u.Scheme == "file" {
+			path = filepath.FromSlash(u.Path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		st, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, st.Size(), nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json, application/json, */*")
+	req.Header.Set("User-Agent", "opcuababy/"+Version)
+	resp, err := cl.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// CompareVersions compares dotted versions such as "v1.2.3" and "1.10.0-rc1", returning -1, 0
+// or 1. A pre-release sorts before its release; anything unparsable (a "dev" build) before all.
+func CompareVersions(a, b string) int {
+	pa, oka := parseVersion(a)
+	pb, okb := parseVersion(b)
+	switch {
+	case !oka && !okb:
+		return 0
+	case !oka:
+		return -1
+	case !okb:
+		return 1
+	}
+	for i := 0; i < 3; i++ {
+		if pa.nums[i] != pb.nums[i] {
+			if pa.nums[i] < pb.nums[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case pa.pre == pb.pre:
+		return 0
+	case pa.pre == "":
+		return 1
+	case pb.pre == "":
+		return -1
+	case pa.pre < pb.pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+type version struct {
+	nums [3]int
+	pre  string
+}
+
+func parseVersion(s string) (version, bool) {
+	var v version
+	s = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(s)), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.pre, _ = strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.nums[i] = n
+	}
+	return v, true
+}
+
+// IsNewer reports whether rel is newer than the running version current.
+func (rel *Release) IsNewer(current string) bool {
+	return CompareVersions(rel.Version, current) > 0
+}
+
+var (
+	osNames = map[string][]string{
+		"windows": {"windows", "win64", "win32", ".exe"}, // not "win": darwin
+		"darwin":  {"darwin", "macos", "mac", "osx"},
+		"linux":   {"linux"},
+	}
+	archNames = map[string][]string{
+		"amd64": {"amd64", "x86_64", "x64"},
+		"arm64": {"arm64", "aarch64"},
+	}
+)
+
+// AssetFor returns the asset built for goos/goarch, judged by its name, or nil. Assets naming
+// the OS but no architecture (universal macOS builds, ...) are used when no better one exists.
+func (rel *Release) AssetFor(goos, goarch string) *Asset {
+	var fallback *Asset
+	for i := range rel.Assets {
+		a := &rel.Assets[i]
+		name := strings.ToLower(a.Name)
+		if strings.HasSuffix(name, ".sha256") || strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		if !containsAny(name, osNames[goos]) {
+			continue
+		}
+		if containsAny(name, archNames[goarch]) {
+			return a
+		}
+		otherArch := false
+		for arch, names := range archNames {
+			if arch != goarch && containsAny(name, names) {
+				otherArch = true
+			}
+		}
+		if !otherArch && fallback == nil {
+			fallback = a
+		}
+	}
+	return fallback
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// StagingDir is where downloaded updates wait for installation.
+func StagingDir(version string) string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "opcuababy", "updates", strings.TrimPrefix(version, "v"))
+}
+
+// Download fetches a into dir and verifies its SHA-256 when the release lists one. progress, if
+// set, is called with the bytes received and the total size (0 if unknown). The file appears
+// under its final name only when complete.
+func Download(ctx context.Context, a *Asset, dir string, progress func(done, total int64)) (string, error) {
+	name := filepath.Base(a.Name)
+	if name == "." || name == string(filepath.Separator) || name == "" {
+		return "", errors.New("asset has no file name")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	rc, total, err := open(ctx, downloadClient, a.URL)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	if total <= 0 {
+		total = a.Size
+	}
+	path := filepath.Join(dir, name)
+	part := path + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	var done int64
+	buf := make([]byte, 64<<10)
+	for {
+		n, rerr := rc.Read(buf)
+		if n > 0 {
+			if _, err := f.Write(buf[:n]); err != nil {
+				f.Close()
+				os.Remove(part)
+				return "", err
+			}
+			h.Write(buf[:n])
+			done += int64(n)
+			if progress != nil {
+				progress(done, total)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			f.Close()
+			os.Remove(part)
+			return "", rerr
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(part)
+		return "", err
+	}
+	if a.SHA256 != "" {
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != a.SHA256 {
+			os.Remove(part)
+			return "", fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, sum, a.SHA256)
+		}
+	}
+	if err := os.Rename(part, path); err != nil {
+		os.Remove(part)
+		return "", err
+	}
+	return path, nil
+}
@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"opcuababy/internal/api"
 	"opcuababy/internal/controller"
+	"opcuababy/internal/gateway"
 	"opcuababy/internal/ui"
 )
 
 
 func main() {
+	manifest := flag.String("manifest", "", "run headless as a gateway described by this YAML/JSON manifest")
+	flag.Parse()
+	if *manifest != "" {
+		runGateway(*manifest)
+		return
+	}
+
 	c := controller.New()
 	var apiStatus string
 
@@ -24,4 +39,17 @@ func main() {
 	c.UpdateApiServerState(ui.GetConfig())
 
 	ui.Run()
-}
\ No newline at end of file
+}
+
+// runGateway collects from every instance of the manifest until interrupted.
+func runGateway(path string) {
+	m, err := gateway.LoadManifest(path)
+	if err != nil {
+		log.Fatalf("gateway manifest: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("gateway: starting %d instances from %s", len(m.Instances), path)
+	gateway.Run(ctx, m, func(msg string) { log.Print(msg) })
+	log.Print("gateway: stopped")
+}